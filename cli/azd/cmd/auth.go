@@ -38,5 +38,7 @@ func authActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newLogoutAction,
 	})
 
+	authCacheActions(group)
+
 	return group
 }
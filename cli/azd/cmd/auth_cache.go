@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func authCacheActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("cache", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "cache",
+			Short: "Inspect and clear the accounts of the token cache.",
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command:        newAuthCacheListCmd(),
+		ActionResolver: newAuthCacheListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.Add("clear", &actions.ActionDescriptorOptions{
+		Command:        newAuthCacheClearCmd(),
+		ActionResolver: newAuthCacheClearAction,
+		OutputFormats:  []output.Format{output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	return group
+}
+
+func newAuthCacheListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the accounts with tokens in the token cache.",
+		Aliases: []string{"ls"},
+	}
+}
+
+type authCacheListAction struct {
+	authManager *auth.Manager
+	formatter   output.Formatter
+	writer      io.Writer
+}
+
+func newAuthCacheListAction(
+	authManager *auth.Manager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &authCacheListAction{
+		authManager: authManager,
+		formatter:   formatter,
+		writer:      writer,
+	}
+}
+
+func (a *authCacheListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	accounts, err := a.authManager.CachedAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing cached accounts: %w", err)
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		if len(accounts) == 0 {
+			fmt.Fprintln(a.writer, "The token cache has no accounts.")
+			return nil, nil
+		}
+
+		columns := []output.Column{
+			{
+				Heading:       "USERNAME",
+				ValueTemplate: "{{.Username}}",
+			},
+			{
+				Heading:       "TENANT ID",
+				ValueTemplate: "{{.TenantID}}",
+			},
+			{
+				Heading:       "HOME ACCOUNT ID",
+				ValueTemplate: "{{.HomeAccountID}}",
+			},
+			{
+				Heading:       "EXPIRES ON",
+				ValueTemplate: `{{with .ExpiresOn}}{{.Local.Format "2006-01-02 15:04:05"}}{{end}}`,
+			},
+			{
+				Heading:       "CURRENT",
+				ValueTemplate: "{{.Current}}",
+			},
+		}
+
+		err = a.formatter.Format(accounts, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = a.formatter.Format(accounts, a.writer, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func newAuthCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear <home-account-id>",
+		Short: "Remove the tokens of an account from the token cache.",
+		Long: "Remove the tokens of an account from the token cache, in all of its tenants. The tokens of other " +
+			"accounts are kept. Run 'azd auth cache list' to see the home account ids of the cached accounts.",
+		Args: cobra.ExactArgs(1),
+	}
+}
+
+type authCacheClearAction struct {
+	authManager *auth.Manager
+	args        []string
+}
+
+func newAuthCacheClearAction(authManager *auth.Manager, args []string) actions.Action {
+	return &authCacheClearAction{
+		authManager: authManager,
+		args:        args,
+	}
+}
+
+func (a *authCacheClearAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	homeAccountID := a.args[0]
+	if err := a.authManager.ClearCachedAccount(ctx, homeAccountID); err != nil {
+		return nil, fmt.Errorf("clearing cached account: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Removed the tokens of account %s from the token cache.", homeAccountID),
+		},
+	}, nil
+}
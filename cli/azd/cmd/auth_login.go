@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"strconv"
 	"strings"
 
@@ -44,16 +43,17 @@ func newAuthLoginFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions
 }
 
 type loginFlags struct {
-	onlyCheckStatus        bool
-	useDeviceCode          boolPtr
-	tenantID               string
-	clientID               string
-	clientSecret           stringPtr
-	clientCertificate      string
-	federatedTokenProvider string
-	scopes                 []string
-	redirectPort           int
-	global                 *internal.GlobalCommandOptions
+	onlyCheckStatus           bool
+	useDeviceCode             boolPtr
+	tenantID                  string
+	clientID                  string
+	clientSecret              stringPtr
+	clientCertificate         string
+	clientCertificatePassword stringPtr
+	federatedTokenProvider    string
+	scopes                    []string
+	redirectPort              int
+	global                    *internal.GlobalCommandOptions
 }
 
 // stringPtr implements a pflag.Value and allows us to distinguish between a flag value being explicitly set to the empty
@@ -105,6 +105,7 @@ func (p *boolPtr) Type() string {
 const (
 	cClientSecretFlagName                = "client-secret"
 	cClientCertificateFlagName           = "client-certificate"
+	cClientCertificatePasswordFlagName   = "client-certificate-password"
 	cFederatedCredentialProviderFlagName = "federated-credential-provider"
 )
 
@@ -128,12 +129,18 @@ func (lf *loginFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandO
 		&lf.clientCertificate,
 		cClientCertificateFlagName,
 		"",
-		"The path to the client certificate for the service principal to authenticate with.")
+		"The path to the client certificate for the service principal to authenticate with, a PEM or PFX file "+
+			"with the private key.")
+	local.Var(
+		&lf.clientCertificatePassword,
+		cClientCertificatePasswordFlagName,
+		"The password of the client certificate, when it is encrypted. "+
+			"Set to the empty string to read the value from the console.")
 	local.StringVar(
 		&lf.federatedTokenProvider,
 		cFederatedCredentialProviderFlagName,
 		"",
-		"The provider to use to acquire a federated token to authenticate with.")
+		"The provider to use to acquire a federated token to authenticate with. Supported values: github, azure-pipelines.")
 	local.StringVar(
 		&lf.tenantID,
 		"tenant-id",
@@ -412,6 +419,10 @@ func (la *loginAction) login(ctx context.Context) error {
 				}, ", "))
 		}
 
+		if la.flags.clientCertificatePassword.ptr != nil && la.flags.clientCertificate == "" {
+			return fmt.Errorf("%s can only be set with %s", cClientCertificatePasswordFlagName, cClientCertificateFlagName)
+		}
+
 		switch {
 		case la.flags.clientSecret.ptr != nil:
 			if *la.flags.clientSecret.ptr == "" {
@@ -430,19 +441,23 @@ func (la *loginAction) login(ctx context.Context) error {
 				return fmt.Errorf("logging in: %w", err)
 			}
 		case la.flags.clientCertificate != "":
-			certFile, err := os.Open(la.flags.clientCertificate)
-			if err != nil {
-				return fmt.Errorf("reading certificate: %w", err)
-			}
-			defer certFile.Close()
-
-			cert, err := io.ReadAll(certFile)
-			if err != nil {
-				return fmt.Errorf("reading certificate: %w", err)
+			var password string
+			if la.flags.clientCertificatePassword.ptr != nil {
+				password = *la.flags.clientCertificatePassword.ptr
+				if password == "" {
+					v, err := la.console.Prompt(ctx, input.ConsoleOptions{
+						Message:    "Enter the password of your client certificate",
+						IsPassword: true,
+					})
+					if err != nil {
+						return fmt.Errorf("prompting for client certificate password: %w", err)
+					}
+					password = v
+				}
 			}
 
 			if _, err := la.authManager.LoginWithServicePrincipalCertificate(
-				ctx, la.flags.tenantID, la.flags.clientID, cert,
+				ctx, la.flags.tenantID, la.flags.clientID, la.flags.clientCertificate, password,
 			); err != nil {
 				return fmt.Errorf("logging in: %w", err)
 			}
@@ -480,14 +495,25 @@ func (la *loginAction) login(ctx context.Context) error {
 				return fmt.Errorf("logging in: %w", err)
 			}
 		} else {
+			// unless --use-device-code is set explicitly, log in with a device code when a browser can't be launched
+			deviceCodeFallback := la.flags.useDeviceCode.ptr == nil
 			_, err := la.authManager.LoginInteractive(ctx, la.flags.scopes,
 				&auth.LoginInteractiveOptions{
 					TenantID:     la.flags.tenantID,
 					RedirectPort: la.flags.redirectPort,
 					WithOpenUrl: func(url string) error {
+						if deviceCodeFallback && overrideBrowser == nil {
+							if err := launchBrowser(ctx, url); err != nil {
+								return fmt.Errorf("%w: %w", auth.ErrBrowserLaunch, err)
+							}
+
+							return nil
+						}
+
 						openWithDefaultBrowser(ctx, la.console, url)
 						return nil
 					},
+					DeviceCodeFallback: deviceCodeFallback,
 				})
 			if err != nil {
 				return fmt.Errorf("logging in: %w", err)
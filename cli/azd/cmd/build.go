@@ -164,7 +164,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	for _, svc := range stableServices {
 		stepMessage := fmt.Sprintf("Building service %s", svc.Name)
-		ba.console.ShowSpinner(ctx, stepMessage, input.Step)
+		ba.console.ShowSpinner(ctx, stepMessage, input.StepElapsed)
 
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
@@ -178,7 +178,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		go func() {
 			for buildProgress := range buildTask.Progress() {
 				progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
-				ba.console.ShowSpinner(ctx, progressMessage, input.Step)
+				ba.console.ShowSpinner(ctx, progressMessage, input.StepElapsed)
 			}
 		}()
 
@@ -1,16 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -27,14 +31,12 @@ const cDocsFlagName = "docs"
 // CobraBuilder manages the construction of the cobra command tree from nested ActionDescriptors
 type CobraBuilder struct {
 	container *ioc.NestedContainer
-	runner    *middleware.MiddlewareRunner
 }
 
 // Creates a new instance of the Cobra builder
 func NewCobraBuilder(container *ioc.NestedContainer) *CobraBuilder {
 	return &CobraBuilder{
 		container: container,
-		runner:    middleware.NewMiddlewareRunner(container),
 	}
 }
 
@@ -104,19 +106,37 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 		ctx := cmd.Context()
 		ctx = tools.WithInstalledCheckCache(ctx)
 
+		// Dependencies with a scoped lifetime, like the environment, are created once for the command and its child
+		// actions, and disposed after it
+		scope := cb.container.NewScope()
+		defer func() {
+			if err := scope.Close(); err != nil {
+				log.Printf("failed disposing command dependencies: %v", err)
+			}
+
+			// The console is a singleton, so it isn't disposed with the scope. Closing it stops watching for resizes.
+			var console input.Console
+			if err := scope.Resolve(&console); err == nil {
+				if err := console.Close(); err != nil {
+					log.Printf("failed closing console: %v", err)
+				}
+			}
+		}()
+		runner := middleware.NewMiddlewareRunner(scope)
+
 		// Registers the following to enable injection into actions that require them
-		ioc.RegisterInstance(cb.container, cb.runner)
-		ioc.RegisterInstance(cb.container, middleware.MiddlewareContext(cb.runner))
-		ioc.RegisterInstance(cb.container, cmd)
-		ioc.RegisterInstance(cb.container, args)
+		ioc.RegisterInstance(scope, runner)
+		ioc.RegisterInstance(scope, middleware.MiddlewareContext(runner))
+		ioc.RegisterInstance(scope, cmd)
+		ioc.RegisterInstance(scope, args)
 
-		if err := cb.registerMiddleware(descriptor); err != nil {
+		if err := cb.registerMiddleware(runner, descriptor); err != nil {
 			return err
 		}
 
 		actionName := createActionName(cmd)
 		var action actions.Action
-		if err := cb.container.ResolveNamed(actionName, &action); err != nil {
+		if err := scope.ResolveNamed(actionName, &action); err != nil {
 			if errors.Is(err, ioc.ErrResolveInstance) {
 				return fmt.Errorf(
 					//nolint:lll
@@ -126,6 +146,9 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 				)
 			}
 
+			// Errors resolving the dependencies of the action, like a missing project, are written as error events with
+			// structured output. Otherwise, cobra shows the error.
+			cb.writeStructuredError(cmd, err)
 			return err
 		}
 
@@ -139,13 +162,18 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 
 		// Run the middleware chain with action
 		log.Printf("Resolved action '%s'\n", actionName)
-		actionResult, err := cb.runner.RunAction(ctx, runOptions, action)
+		actionResult, err := runner.RunAction(ctx, runOptions, action)
 
 		// At this point, we know that there might be an error, so we can silence cobra from showing it after us.
 		cmd.SilenceErrors = true
 
 		// TODO: Consider refactoring to move the UX writing to a middleware
-		invokeErr := cb.container.Invoke(func(console input.Console) {
+		invokeErr := scope.Invoke(func(console input.Console) {
+			var traceID string
+			if actionResult != nil {
+				traceID = actionResult.TraceID
+			}
+
 			var displayResult *ux.ActionResult
 			if actionResult != nil && actionResult.Message != nil {
 				displayResult = &ux.ActionResult{
@@ -153,8 +181,11 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 					FollowUp:       actionResult.Message.FollowUp,
 				}
 			} else if err != nil {
+				code, details := errorCodeAndDetails(err, traceID)
 				displayResult = &ux.ActionResult{
-					Err: err,
+					Err:        err,
+					ErrCode:    code,
+					ErrDetails: details,
 				}
 			}
 
@@ -162,24 +193,23 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 				console.MessageUxItem(ctx, displayResult)
 			}
 
-			if err != nil {
-				var respErr *azcore.ResponseError
-				var azureErr *azapi.AzureDeploymentError
-				var toolExitErr *exec.ExitError
+			// With structured output, the trace ID and suggestion are details of the error event instead.
+			if err != nil && console.IsUnformatted() {
 				var suggestionErr *azcli.ErrorWithSuggestion
+				var toolTimeoutErr *exec.TimeoutError
+
+				// the output of the tool isn't in the error when the tool timed out, so the end of it is shown instead
+				if errors.As(err, &toolTimeoutErr) && toolTimeoutErr.OutputTail != "" {
+					console.MessageUxItem(ctx, &ux.CommandOutput{
+						Cmd:    filepath.Base(toolTimeoutErr.Cmd),
+						Output: toolTimeoutErr.OutputTail,
+					})
+				}
 
-				// We only want to show trace ID for server-related errors,
-				// where we have full server logs to troubleshoot from.
-				//
-				// For client errors, we don't want to show the trace ID, as it is not useful to the user currently.
-				if errors.As(err, &respErr) ||
-					errors.As(err, &azureErr) ||
-					(errors.As(err, &toolExitErr) && toolExitErr.Cmd == "terraform") {
-					if actionResult != nil && actionResult.TraceID != "" {
-						console.Message(
-							ctx,
-							output.WithErrorFormat(fmt.Sprintf("TraceID: %s", actionResult.TraceID)))
-					}
+				if showTraceID(err) && traceID != "" {
+					console.Message(
+						ctx,
+						output.WithErrorFormat(fmt.Sprintf("TraceID: %s", traceID)))
 				}
 
 				if errors.As(err, &suggestionErr) {
@@ -363,7 +393,10 @@ func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.Acti
 // Registers all middleware components for the current command and any parent descriptors
 // Middleware components are insure to run in the order that they were registered from the
 // root registration, down through action groups and ultimately individual actions
-func (cb *CobraBuilder) registerMiddleware(descriptor *actions.ActionDescriptor) error {
+func (cb *CobraBuilder) registerMiddleware(
+	runner *middleware.MiddlewareRunner,
+	descriptor *actions.ActionDescriptor,
+) error {
 	chain := []*actions.MiddlewareRegistration{}
 	current := descriptor
 
@@ -394,7 +427,7 @@ func (cb *CobraBuilder) registerMiddleware(descriptor *actions.ActionDescriptor)
 	// higher up the command structure are resolved before lower registrations
 	for i := len(chain) - 1; i > -1; i-- {
 		registration := chain[i]
-		if err := cb.runner.Use(registration.Name, registration.Resolver); err != nil {
+		if err := runner.Use(registration.Name, registration.Resolver); err != nil {
 			return err
 		}
 	}
@@ -412,3 +445,96 @@ func createActionName(cmd *cobra.Command) string {
 
 	return strings.ToLower(actionName)
 }
+
+// writeStructuredError writes err as an error event when the console uses structured output, and silences cobra from
+// showing it again.
+func (cb *CobraBuilder) writeStructuredError(cmd *cobra.Command, err error) {
+	invokeErr := cb.container.Invoke(func(console input.Console, formatter output.Formatter) {
+		if formatter == nil || (formatter.Kind() != output.JsonFormat && formatter.Kind() != output.YamlFormat) {
+			return
+		}
+
+		code, details := errorCodeAndDetails(err, "")
+		console.MessageUxItem(cmd.Context(), &ux.ActionResult{
+			Err:        err,
+			ErrCode:    code,
+			ErrDetails: details,
+		})
+		cmd.SilenceErrors = true
+	})
+	if invokeErr != nil {
+		log.Printf("failed writing error event: %v", invokeErr)
+	}
+}
+
+// showTraceID returns true when the trace ID should be shown for err. We only want to show trace ID for server-related
+// errors, where we have full server logs to troubleshoot from.
+//
+// For client errors, we don't want to show the trace ID, as it is not useful to the user currently.
+func showTraceID(err error) bool {
+	var respErr *azcore.ResponseError
+	var azureErr *azapi.AzureDeploymentError
+	var toolExitErr *exec.ExitError
+
+	return errors.As(err, &respErr) ||
+		errors.As(err, &azureErr) ||
+		(errors.As(err, &toolExitErr) && toolExitErr.Cmd == "terraform")
+}
+
+// errorCodeAndDetails returns the code that classifies err, and the details written with it in the error event of
+// structured output.
+func errorCodeAndDetails(err error, traceID string) (string, map[string]any) {
+	code := "error"
+	details := map[string]any{}
+
+	var respErr *azcore.ResponseError
+	var azureErr *azapi.AzureDeploymentError
+	var toolExitErr *exec.ExitError
+	var toolTimeoutErr *exec.TimeoutError
+	var authFailedErr *auth.AuthFailedError
+	var suggestionErr *azcli.ErrorWithSuggestion
+
+	if errors.As(err, &azureErr) {
+		code = "deploymentFailed"
+	} else if errors.As(err, &respErr) {
+		code = "serviceError"
+		if respErr.ErrorCode != "" {
+			details["serviceErrorCode"] = respErr.ErrorCode
+		}
+		if respErr.StatusCode != 0 {
+			details["statusCode"] = respErr.StatusCode
+		}
+	} else if errors.As(err, &toolExitErr) {
+		code = "toolFailed"
+		details["tool"] = filepath.Base(toolExitErr.Cmd)
+		details["exitCode"] = toolExitErr.ExitCode
+		if toolExitErr.OutputTruncated() {
+			details["outputTail"] = toolExitErr.OutputTail
+		}
+	} else if errors.As(err, &toolTimeoutErr) {
+		code = "toolTimedOut"
+		details["tool"] = filepath.Base(toolTimeoutErr.Cmd)
+		details["timeout"] = toolTimeoutErr.Timeout.String()
+		if toolTimeoutErr.OutputTail != "" {
+			details["outputTail"] = toolTimeoutErr.OutputTail
+		}
+	} else if errors.As(err, &authFailedErr) {
+		code = "authFailed"
+	} else if errors.Is(err, terminal.InterruptErr) || errors.Is(err, context.Canceled) {
+		code = "canceled"
+	}
+
+	if errors.As(err, &suggestionErr) {
+		details["suggestion"] = suggestionErr.Suggestion
+	}
+
+	if showTraceID(err) && traceID != "" {
+		details["traceId"] = traceID
+	}
+
+	if len(details) == 0 {
+		return code, nil
+	}
+
+	return code, details
+}
@@ -3,14 +3,19 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
@@ -178,6 +183,44 @@ func Test_BuildAndRunActionWithNestedAndConditionalMiddleware(t *testing.T) {
 	require.False(t, middlewareBRan)
 }
 
+func Test_BuildAndRunActionWithScopedDependency(t *testing.T) {
+	container := ioc.NewNestedContainer(nil)
+	setup(container)
+	container.RegisterScoped(func() *testScopedResource {
+		return &testScopedResource{}
+	})
+
+	var actionResource, middlewareResource *testScopedResource
+	root := actions.NewActionDescriptor("root", &actions.ActionDescriptorOptions{
+		ActionResolver: func(resource *testScopedResource) actions.Action {
+			actionResource = resource
+			return &testScopedAction{resource: resource}
+		},
+	}).UseMiddleware("scoped", func(resource *testScopedResource) middleware.Middleware {
+		middlewareResource = resource
+		return newTestMiddlewareA()
+	})
+
+	builder := NewCobraBuilder(container)
+	cmd, err := builder.BuildCommand(root)
+
+	require.NotNil(t, cmd)
+	require.NoError(t, err)
+
+	cmd.SetArgs([]string{})
+	err = cmd.ExecuteContext(context.Background())
+
+	require.NoError(t, err)
+	// The action and its middleware share the instance of the command, disposed after the command ran
+	require.Same(t, actionResource, middlewareResource)
+	require.True(t, actionResource.closed)
+
+	var rootResource *testScopedResource
+	require.NoError(t, container.Resolve(&rootResource))
+	require.NotSame(t, actionResource, rootResource)
+	require.False(t, rootResource.closed)
+}
+
 func Test_BuildCommandsWithAutomaticHelpAndOutputFlags(t *testing.T) {
 	container := ioc.NewNestedContainer(nil)
 
@@ -337,6 +380,27 @@ func (a *testAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	return nil, nil
 }
 
+type testScopedResource struct {
+	closed bool
+}
+
+func (r *testScopedResource) Close() error {
+	r.closed = true
+	return nil
+}
+
+type testScopedAction struct {
+	resource *testScopedResource
+}
+
+func (a *testScopedAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.resource.closed {
+		return nil, errors.New("resource was disposed before the action ran")
+	}
+
+	return nil, nil
+}
+
 // Middleware
 
 type testMiddlewareA struct {
@@ -370,3 +434,61 @@ func (m *testMiddlewareB) Run(ctx context.Context, nextFn middleware.NextFn) (*a
 
 	return nextFn(ctx)
 }
+
+func Test_ErrorCodeAndDetails(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantDetails map[string]any
+	}{
+		{
+			name:     "Unclassified",
+			err:      errors.New("something failed"),
+			wantCode: "error",
+		},
+		{
+			name:     "ServiceError",
+			err:      fmt.Errorf("getting resource: %w", &azcore.ResponseError{ErrorCode: "NotFound", StatusCode: 404}),
+			wantCode: "serviceError",
+			wantDetails: map[string]any{
+				"serviceErrorCode": "NotFound",
+				"statusCode":       404,
+				"traceId":          "trace",
+			},
+		},
+		{
+			name:     "ToolFailed",
+			err:      &exec.ExitError{Cmd: "/usr/bin/docker", ExitCode: 2},
+			wantCode: "toolFailed",
+			wantDetails: map[string]any{
+				"tool":     "docker",
+				"exitCode": 2,
+			},
+		},
+		{
+			name: "Suggestion",
+			err: &azcli.ErrorWithSuggestion{
+				Err:        errors.New("not logged in"),
+				Suggestion: "Run azd auth login",
+			},
+			wantCode: "error",
+			wantDetails: map[string]any{
+				"suggestion": "Run azd auth login",
+			},
+		},
+		{
+			name:     "Canceled",
+			err:      fmt.Errorf("prompting: %w", terminal.InterruptErr),
+			wantCode: "canceled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, details := errorCodeAndDetails(tt.err, "trace")
+			require.Equal(t, tt.wantCode, code)
+			require.Equal(t, tt.wantDetails, details)
+		})
+	}
+}
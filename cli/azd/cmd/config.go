@@ -6,7 +6,6 @@ import (
 	"io"
 	"path/filepath"
 	"runtime"
-	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -17,6 +16,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 var userConfigPath string
@@ -96,7 +97,7 @@ func configActions(root *actions.ActionDescriptor, rootOptions *internal.GlobalC
 			Long:  `Show all configuration values in ` + userConfigPath + `.`,
 		},
 		ActionResolver: newConfigShowAction,
-		OutputFormats:  []output.Format{output.JsonFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat},
 		DefaultFormat:  output.JsonFormat,
 	})
 
@@ -118,7 +119,7 @@ func configActions(root *actions.ActionDescriptor, rootOptions *internal.GlobalC
 			Args:  cobra.ExactArgs(1),
 		},
 		ActionResolver: newConfigGetAction,
-		OutputFormats:  []output.Format{output.JsonFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat},
 		DefaultFormat:  output.JsonFormat,
 	})
 
@@ -162,8 +163,12 @@ $ azd config set defaults.location eastus`,
 			Footer: getCmdListAlphaHelpFooter,
 		},
 		ActionResolver: newConfigListAlphaAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
 	})
 
+	markOffline(group)
+
 	return group
 }
 
@@ -194,7 +199,7 @@ func (a *configShowAction) Run(ctx context.Context) (*actions.ActionResult, erro
 
 	values := azdConfig.Raw()
 
-	if a.formatter.Kind() == output.JsonFormat {
+	if a.formatter.Kind() == output.JsonFormat || a.formatter.Kind() == output.YamlFormat {
 		err := a.formatter.Format(values, a.writer, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failing formatting config values: %w", err)
@@ -268,7 +273,7 @@ func (a *configGetAction) Run(ctx context.Context) (*actions.ActionResult, error
 		return nil, fmt.Errorf("no value stored at path '%s'", key)
 	}
 
-	if a.formatter.Kind() == output.JsonFormat {
+	if a.formatter.Kind() == output.JsonFormat || a.formatter.Kind() == output.YamlFormat {
 		err := a.formatter.Format(value, a.writer, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failing formatting config values: %w", err)
@@ -445,28 +450,47 @@ func getCmdConfigHelpFooter(c *cobra.Command) string {
 
 type configListAlphaAction struct {
 	alphaFeaturesManager *alpha.FeatureManager
-	console              input.Console
+	formatter            output.Formatter
+	writer               io.Writer
 	args                 []string
 }
 
 func (a *configListAlphaAction) Run(ctx context.Context) (*actions.ActionResult, error) {
-	features, err := a.alphaFeaturesManager.ListFeatures()
+	featuresById, err := a.alphaFeaturesManager.ListFeatures()
 	if err != nil {
 		return nil, err
 	}
-	var alphaOutput []string
-	for _, alphaFeature := range features {
-		alphaOutput = append(alphaOutput,
-			strings.Join(
-				[]string{
-					fmt.Sprintf("Name: %s", alphaFeature.Id),
-					fmt.Sprintf("Description: %s", alphaFeature.Description),
-					fmt.Sprintf("Status: %s", alphaFeature.Status),
-				},
-				"\n",
-			))
+
+	features := maps.Values(featuresById)
+	slices.SortFunc(features, func(x, y alpha.Feature) bool {
+		return x.Id < y.Id
+	})
+
+	if a.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "NAME",
+				ValueTemplate: "{{.Id}}",
+			},
+			{
+				Heading:       "STATUS",
+				ValueTemplate: "{{.Status}}",
+			},
+			{
+				Heading:       "DESCRIPTION",
+				ValueTemplate: "{{.Description}}",
+			},
+		}
+
+		err = a.formatter.Format(features, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = a.formatter.Format(features, a.writer, nil)
+	}
+	if err != nil {
+		return nil, err
 	}
-	a.console.Message(ctx, strings.Join(alphaOutput, "\n\n"))
 
 	// No UX output
 	return nil, nil
@@ -474,11 +498,13 @@ func (a *configListAlphaAction) Run(ctx context.Context) (*actions.ActionResult,
 
 func newConfigListAlphaAction(
 	alphaFeaturesManager *alpha.FeatureManager,
-	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
 	args []string) actions.Action {
 	return &configListAlphaAction{
 		alphaFeaturesManager: alphaFeaturesManager,
-		console:              console,
+		formatter:            formatter,
+		writer:               writer,
 		args:                 args,
 	}
 }
@@ -488,6 +514,9 @@ func getCmdListAlphaHelpFooter(*cobra.Command) string {
 		"Displays a list of all available features in the alpha stage": output.WithHighLightFormat(
 			"azd config list-alpha",
 		),
+		"Displays the alpha features and whether they are enabled as JSON": output.WithHighLightFormat(
+			"azd config list-alpha --output json",
+		),
 		"Turn on a specific alpha feature": output.WithHighLightFormat(
 			"azd config set alpha.<feature-name> on",
 		),
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
@@ -21,6 +24,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerinstances"
 	"github.com/azure/azure-dev/cli/azd/pkg/devcenter"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -39,10 +43,14 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bundler"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cargo"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/composer"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/helm"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
@@ -60,17 +68,17 @@ import (
 // This is to ensure pre-conditions are met for composite actions like 'up'
 // This finds the action for a named instance and casts it to the correct type for injection
 func registerAction[T actions.Action](container *ioc.NestedContainer, actionName string) {
-	container.RegisterSingleton(func() (T, error) {
-		return resolveAction[T](container, actionName)
+	container.RegisterSingleton(func(current *ioc.NestedContainer) (T, error) {
+		return resolveAction[T](current, actionName)
 	})
 }
 
 // Registers a singleton action for the specified action name
 // This finds the action for a named instance and casts it to the correct type for injection
 func registerActionInitializer[T actions.Action](container *ioc.NestedContainer, actionName string) {
-	container.RegisterSingleton(func() actions.ActionInitializer[T] {
+	container.RegisterSingleton(func(current *ioc.NestedContainer) actions.ActionInitializer[T] {
 		return func() (T, error) {
-			return resolveAction[T](container, actionName)
+			return resolveAction[T](current, actionName)
 		}
 	})
 }
@@ -93,6 +101,60 @@ func resolveAction[T actions.Action](container *ioc.NestedContainer, actionName
 	return instance, nil
 }
 
+// outputTheme returns the color theme of the output, selected by AZD_THEME or the output.theme user config, in that
+// order. Invalid values are ignored, falling back to the default theme.
+func outputTheme(userConfigManager config.UserConfigManager) output.Theme {
+	value, source := os.Getenv("AZD_THEME"), "AZD_THEME"
+	if value == "" {
+		userConfig, err := userConfigManager.Load()
+		if err != nil {
+			log.Printf("loading user config to select the output theme: %v", err)
+			return output.DefaultTheme
+		}
+
+		configValue, has := userConfig.Get("output.theme")
+		if !has {
+			return output.DefaultTheme
+		}
+
+		value, source = fmt.Sprint(configValue), "output.theme"
+	}
+
+	theme, err := output.ParseTheme(value)
+	if err != nil {
+		log.Printf("ignoring invalid value for %s: %v", source, err)
+		return output.DefaultTheme
+	}
+
+	return theme
+}
+
+// outputTailSize returns the number of bytes at the end of the output of a failed tool that are reported, which is set in
+// KB with AZD_TOOL_OUTPUT_TAIL_KB. Invalid values are ignored, falling back to exec.DefaultOutputTailSize.
+func outputTailSize() int {
+	value := os.Getenv("AZD_TOOL_OUTPUT_TAIL_KB")
+	if value == "" {
+		return exec.DefaultOutputTailSize
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		log.Printf("ignoring invalid value for AZD_TOOL_OUTPUT_TAIL_KB: %s", value)
+		return exec.DefaultOutputTailSize
+	}
+
+	return size * 1024
+}
+
+// toolTimeout returns the default duration after which tools that don't complete are killed, which is set with
+// AZD_TOOL_TIMEOUT as a duration like '30m'. Tools don't time out by default, and invalid values are ignored. Docker
+// builds use AZD_BUILD_TIMEOUT instead when it is set, and terraform provisioning only times out with
+// AZD_PROVISION_TIMEOUT, as it is interactive.
+func toolTimeout() time.Duration {
+	timeout, _ := exec.TimeoutFromEnv("AZD_TOOL_TIMEOUT")
+	return timeout
+}
+
 // Registers common Azd dependencies
 func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(output.GetCommandFormatter)
@@ -100,10 +162,13 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(func(
 		rootOptions *internal.GlobalCommandOptions,
 		formatter output.Formatter,
+		userConfigManager config.UserConfigManager,
 		cmd *cobra.Command) input.Console {
+		output.SetTheme(outputTheme(userConfigManager))
+
 		writer := cmd.OutOrStdout()
-		// When using JSON formatting, we want to ensure we always write messages from the console to stderr.
-		if formatter != nil && formatter.Kind() == output.JsonFormat {
+		// When using JSON or YAML formatting, we want to ensure we always write messages from the console to stderr.
+		if formatter != nil && (formatter.Kind() == output.JsonFormat || formatter.Kind() == output.YamlFormat) {
 			writer = cmd.ErrOrStderr()
 		}
 
@@ -115,7 +180,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			cmd.InOrStdin() == os.Stdin && isatty.IsTerminal(os.Stdin.Fd()) &&
 			isatty.IsTerminal(os.Stdout.Fd())
 
-		return input.NewConsole(rootOptions.NoPrompt, isTerminal, writer, input.ConsoleHandles{
+		return input.NewConsole(rootOptions.NoPrompt, rootOptions.AssumeYes, isTerminal, writer, input.ConsoleHandles{
 			Stdin:  cmd.InOrStdin(),
 			Stdout: cmd.OutOrStdout(),
 			Stderr: cmd.ErrOrStderr(),
@@ -125,10 +190,12 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(func(console input.Console, rootOptions *internal.GlobalCommandOptions) exec.CommandRunner {
 		return exec.NewCommandRunner(
 			&exec.RunnerOptions{
-				Stdin:        console.Handles().Stdin,
-				Stdout:       console.Handles().Stdout,
-				Stderr:       console.Handles().Stderr,
-				DebugLogging: rootOptions.EnableDebugLogging,
+				Stdin:          console.Handles().Stdin,
+				Stdout:         console.Handles().Stdout,
+				Stderr:         console.Handles().Stderr,
+				DebugLogging:   rootOptions.EnableDebugLogging,
+				OutputTailSize: outputTailSize(),
+				Timeout:        toolTimeout(),
 			})
 	})
 
@@ -182,7 +249,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	// Register an initialized environment based on the specified environment flag, or the default environment.
 	// Note that referencing an *environment.Environment in a command automatically triggers a UI prompt if the
 	// environment is uninitialized or a default environment doesn't yet exist.
-	container.RegisterSingleton(
+	// The environment is scoped to the command, it's loaded once for the command and its child actions.
+	container.RegisterScoped(
 		func(ctx context.Context,
 			azdContext *azdcontext.AzdContext,
 			envManager environment.Manager,
@@ -231,6 +299,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	container.RegisterSingleton(environment.NewLocalFileDataStore)
 	container.RegisterSingleton(environment.NewManager)
+	container.RegisterSingleton(environment.NewKeyVaultSecretResolver)
 
 	container.RegisterSingleton(func() *lazy.Lazy[environment.LocalDataStore] {
 		return lazy.NewLazy(func() (environment.LocalDataStore, error) {
@@ -374,14 +443,24 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 		return armresourcegraph.NewClient(credential, options)
 	})
+	container.RegisterSingleton(func(current *ioc.NestedContainer) *lazy.Lazy[*armresourcegraph.Client] {
+		return lazy.NewLazy(func() (*armresourcegraph.Client, error) {
+			var client *armresourcegraph.Client
+			err := current.Resolve(&client)
+
+			return client, err
+		})
+	})
+	container.RegisterSingleton(infra.NewResourceGraphQuery)
 
 	container.RegisterSingleton(templates.NewTemplateManager)
 	container.RegisterSingleton(templates.NewSourceManager)
 	container.RegisterSingleton(project.NewResourceManager)
-	container.RegisterSingleton(func() *lazy.Lazy[project.ResourceManager] {
+	// The resource and service managers depend on the environment, resolved from the scope of the command
+	container.RegisterSingleton(func(current *ioc.NestedContainer) *lazy.Lazy[project.ResourceManager] {
 		return lazy.NewLazy(func() (project.ResourceManager, error) {
 			var resourceManager project.ResourceManager
-			err := container.Resolve(&resourceManager)
+			err := current.Resolve(&resourceManager)
 
 			return resourceManager, err
 		})
@@ -390,10 +469,10 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(project.NewDotNetImporter)
 	container.RegisterSingleton(project.NewImportManager)
 	container.RegisterSingleton(project.NewServiceManager)
-	container.RegisterSingleton(func() *lazy.Lazy[project.ServiceManager] {
+	container.RegisterSingleton(func(current *ioc.NestedContainer) *lazy.Lazy[project.ServiceManager] {
 		return lazy.NewLazy(func() (project.ServiceManager, error) {
 			var serviceManager project.ServiceManager
-			err := container.Resolve(&serviceManager)
+			err := current.Resolve(&serviceManager)
 
 			return serviceManager, err
 		})
@@ -409,21 +488,42 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(account.NewManager)
 	container.RegisterSingleton(account.NewSubscriptionsManager)
 	container.RegisterSingleton(account.NewSubscriptionCredentialProvider)
+	container.RegisterSingleton(func(current *ioc.NestedContainer) *lazy.Lazy[account.Manager] {
+		return lazy.NewLazy(func() (account.Manager, error) {
+			var accountManager account.Manager
+			err := current.Resolve(&accountManager)
+
+			return accountManager, err
+		})
+	})
 	container.RegisterSingleton(azcli.NewManagedClustersService)
 	container.RegisterSingleton(azcli.NewAdService)
 	container.RegisterSingleton(azcli.NewContainerRegistryService)
 	container.RegisterSingleton(containerapps.NewContainerAppService)
+	container.RegisterSingleton(containerinstances.NewContainerInstanceService)
 	container.RegisterSingleton(project.NewContainerHelper)
+	container.RegisterSingleton(project.NewImageScanner)
+	container.RegisterSingleton(project.NewPackageManifest)
 	container.RegisterSingleton(azcli.NewSpringService)
-	container.RegisterSingleton(func() ioc.ServiceLocator {
-		return ioc.NewServiceLocator(container)
+	container.RegisterSingleton(func(current *ioc.NestedContainer) ioc.ServiceLocator {
+		return ioc.NewServiceLocator(current)
 	})
 
 	container.RegisterSingleton(func(subManager *account.SubscriptionsManager) account.SubscriptionTenantResolver {
 		return subManager
 	})
 
-	container.RegisterSingleton(func(ctx context.Context, authManager *auth.Manager) (azcore.TokenCredential, error) {
+	container.RegisterSingleton(func(
+		ctx context.Context,
+		authManager *auth.Manager,
+		annotations CmdAnnotations,
+	) (azcore.TokenCredential, error) {
+		if annotations.IsOffline() {
+			return auth.NewLazyCredential(func() (azcore.TokenCredential, error) {
+				return authManager.CredentialForCurrentUser(ctx, nil)
+			}), nil
+		}
+
 		return authManager.CredentialForCurrentUser(ctx, nil)
 	})
 
@@ -438,6 +538,14 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			EnableTelemetry: rootOptions.EnableTelemetry,
 		})
 	})
+	container.RegisterSingleton(func() *lazy.Lazy[azcli.AzCli] {
+		return lazy.NewLazy(func() (azcli.AzCli, error) {
+			var azCli azcli.AzCli
+			err := container.Resolve(&azCli)
+
+			return azCli, err
+		})
+	})
 	container.RegisterSingleton(azapi.NewDeployments)
 	container.RegisterSingleton(azapi.NewDeploymentOperations)
 	container.RegisterSingleton(docker.NewDocker)
@@ -446,7 +554,11 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(github.NewGitHubCli)
 	container.RegisterSingleton(javac.NewCli)
 	container.RegisterSingleton(kubectl.NewKubectl)
+	container.RegisterSingleton(helm.NewHelmCli)
 	container.RegisterSingleton(maven.NewMavenCli)
+	container.RegisterSingleton(cargo.NewCargoCli)
+	container.RegisterSingleton(composer.NewComposerCli)
+	container.RegisterSingleton(bundler.NewBundlerCli)
 	container.RegisterSingleton(npm.NewNpmCli)
 	container.RegisterSingleton(python.NewPythonCli)
 	container.RegisterSingleton(swa.NewSwaCli)
@@ -469,6 +581,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.StaticWebAppTarget:       project.NewStaticWebAppTarget,
 		project.AksTarget:                project.NewAksTarget,
 		project.SpringAppTarget:          project.NewSpringAppTarget,
+		project.AciTarget:                project.NewAciTarget,
 		project.DotNetContainerAppTarget: project.NewDotNetContainerAppTarget,
 	}
 
@@ -488,6 +601,10 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.ServiceLanguageJavaScript: project.NewNpmProject,
 		project.ServiceLanguageTypeScript: project.NewNpmProject,
 		project.ServiceLanguageJava:       project.NewMavenProject,
+		project.ServiceLanguageGo:         project.NewGoProject,
+		project.ServiceLanguageRust:       project.NewCargoProject,
+		project.ServiceLanguagePhp:        project.NewComposerProject,
+		project.ServiceLanguageRuby:       project.NewBundlerProject,
 		project.ServiceLanguageDocker:     project.NewDockerProject,
 	}
 
@@ -497,6 +614,21 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		}
 	}
 
+	// Service targets and languages provided by plugins
+	for _, plugin := range project.ServicePlugins() {
+		for target, constructor := range plugin.ServiceTargets() {
+			if err := container.RegisterNamedSingleton(string(target), constructor); err != nil {
+				panic(fmt.Errorf("registering service target %s of plugin %s: %w", target, plugin.Name(), err))
+			}
+		}
+
+		for language, constructor := range plugin.FrameworkServices() {
+			if err := container.RegisterNamedSingleton(string(language), constructor); err != nil {
+				panic(fmt.Errorf("registering framework service %s of plugin %s: %w", language, plugin.Name(), err))
+			}
+		}
+	}
+
 	// Pipelines
 	container.RegisterSingleton(pipeline.NewPipelineManager)
 	container.RegisterSingleton(func(flags *pipelineConfigFlags) *pipeline.PipelineManagerArgs {
@@ -504,10 +636,12 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	})
 
 	pipelineProviderMap := map[string]any{
-		"github-ci":  pipeline.NewGitHubCiProvider,
-		"github-scm": pipeline.NewGitHubScmProvider,
-		"azdo-ci":    pipeline.NewAzdoCiProvider,
-		"azdo-scm":   pipeline.NewAzdoScmProvider,
+		"github-ci":     pipeline.NewGitHubCiProvider,
+		"github-scm":    pipeline.NewGitHubScmProvider,
+		"azdo-ci":       pipeline.NewAzdoCiProvider,
+		"azdo-scm":      pipeline.NewAzdoScmProvider,
+		"bitbucket-ci":  pipeline.NewBitbucketCiProvider,
+		"bitbucket-scm": pipeline.NewBitbucketScmProvider,
 	}
 
 	for provider, constructor := range pipelineProviderMap {
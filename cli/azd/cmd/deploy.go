@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -30,7 +33,11 @@ import (
 type deployFlags struct {
 	serviceName string
 	all         bool
+	filters     []string
 	fromPackage string
+	noBuild     bool
+	parallel    int
+	dryRun      bool
 	global      *internal.GlobalCommandOptions
 	*envFlag
 }
@@ -65,12 +72,38 @@ func (d *deployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		false,
 		"Deploys all services that are listed in "+azdcontext.ProjectFileName,
 	)
+	local.StringArrayVar(
+		&d.filters,
+		"filter",
+		nil,
+		"Deploys the services whose names match the glob `pattern`. Can be specified multiple times.",
+	)
 	local.StringVar(
 		&d.fromPackage,
 		"from-package",
 		"",
 		"Deploys the application from an existing package.",
 	)
+	local.BoolVar(
+		&d.noBuild,
+		"no-build",
+		false,
+		"Deploys the packages recorded by the last azd package, without packaging the services again.",
+	)
+	local.IntVar(
+		&d.parallel,
+		"parallel",
+		1,
+		//nolint:lll
+		"Deploys up to `N` services concurrently, after the services they depend on. When N is omitted or 0, up to GOMAXPROCS services are deployed concurrently.",
+	)
+	local.Lookup("parallel").NoOptDefVal = "0"
+	local.BoolVar(
+		&d.dryRun,
+		"dry-run",
+		false,
+		"Prints what deploying the services would do, without building, pushing or deploying anything.",
+	)
 }
 
 func (d *deployFlags) setCommon(envFlag *envFlag) {
@@ -113,6 +146,9 @@ type deployAction struct {
 	packageActionInitializer actions.ActionInitializer[*packageAction]
 	alphaFeatureManager      *alpha.FeatureManager
 	importManager            *project.ImportManager
+	packageManifest          *project.PackageManifest
+	// the packages recorded by the last azd package of the services deployed with --no-build, by service name
+	recordedPackages map[string]*project.ServicePackageResult
 }
 
 func newDeployAction(
@@ -134,6 +170,7 @@ func newDeployAction(
 	packageActionInitializer actions.ActionInitializer[*packageAction],
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	packageManifest *project.PackageManifest,
 ) actions.Action {
 	return &deployAction{
 		flags:                    flags,
@@ -154,6 +191,7 @@ func newDeployAction(
 		packageActionInitializer: packageActionInitializer,
 		alphaFeatureManager:      alphaFeatureManager,
 		importManager:            importManager,
+		packageManifest:          packageManifest,
 	}
 }
 
@@ -162,6 +200,12 @@ type DeploymentResult struct {
 	Services  map[string]*project.ServiceDeployResult `json:"services"`
 }
 
+// DeploymentPlan is the result of azd deploy --dry-run
+type DeploymentPlan struct {
+	Timestamp time.Time                             `json:"timestamp"`
+	Services  map[string]*project.ServiceDeployPlan `json:"services"`
+}
+
 func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	targetServiceName := da.flags.serviceName
 	if len(da.args) == 1 {
@@ -176,24 +220,44 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
-	targetServiceName, err := getTargetServiceName(
-		ctx,
-		da.projectManager,
-		da.importManager,
-		da.projectConfig,
-		string(project.ServiceEventDeploy),
-		targetServiceName,
-		da.flags.all,
-	)
-	if err != nil {
+	if err := validateServiceFilters(da.flags.filters, targetServiceName, da.flags.all); err != nil {
 		return nil, err
 	}
 
+	if len(da.flags.filters) == 0 {
+		var err error
+		targetServiceName, err = getTargetServiceName(
+			ctx,
+			da.projectManager,
+			da.importManager,
+			da.projectConfig,
+			string(project.ServiceEventDeploy),
+			targetServiceName,
+			da.flags.all,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(da.flags.filters) > 0 && da.flags.fromPackage != "" {
+		return nil, errors.New(
+			"'--from-package' cannot be specified when '--filter' is set. Specify a specific service by passing a <service>")
+	}
+
 	if da.flags.all && da.flags.fromPackage != "" {
 		return nil, errors.New(
 			"'--from-package' cannot be specified when '--all' is set. Specify a specific service by passing a <service>")
 	}
 
+	if da.flags.noBuild && da.flags.fromPackage != "" {
+		return nil, errors.New("'--no-build' cannot be specified when '--from-package' is set")
+	}
+
+	if da.flags.parallel < 0 {
+		return nil, errors.New("'--parallel' must be greater than or equal to 0")
+	}
+
 	if targetServiceName == "" && da.flags.fromPackage != "" {
 		return nil, errors.New(
 			//nolint:lll
@@ -201,33 +265,198 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
-	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
-		return nil, err
+	// initializing the services registers their deployment side effects, such as the hooks of service targets, which
+	// a dry run doesn't run
+	if !da.flags.dryRun {
+		if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := da.projectManager.EnsureServiceTargetTools(ctx, da.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) &&
+			matchesServiceFilters(da.flags.filters, svc.Name)
 	}); err != nil {
 		return nil, err
 	}
 
 	// Command title
-	da.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: "Deploying services (azd deploy)",
-	})
+	if da.flags.dryRun {
+		da.console.MessageUxItem(ctx, &ux.MessageTitle{
+			Title:     "Planning the deployment of services (azd deploy --dry-run)",
+			TitleNote: "This is a dry run. Nothing will be built, pushed or deployed.",
+		})
+	} else {
+		da.console.MessageUxItem(ctx, &ux.MessageTitle{
+			Title: "Deploying services (azd deploy)",
+		})
+	}
 
 	startTime := time.Now()
 
-	deployResults := map[string]*project.ServiceDeployResult{}
 	stableServices, err := da.importManager.ServiceStable(ctx, da.projectConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, svc := range stableServices {
-		stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
+	stableServices, err = da.serviceManager.DeploymentOrder(stableServices)
+	if err != nil {
+		return nil, err
+	}
+
+	// the services that aren't selected with --filter aren't deployed, nor shown as skipped
+	stableServices, err = filterServices(stableServices, da.flags.filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if da.flags.noBuild {
+		// fail before anything is deployed when a service wasn't packaged
+		da.recordedPackages, err = da.loadRecordedPackages(ctx, stableServices, targetServiceName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if da.flags.dryRun {
+		return da.dryRun(ctx, stableServices, targetServiceName, startTime)
+	}
+
+	parallelism := da.flags.parallel
+	if parallelism == 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	var deployResults map[string]*project.ServiceDeployResult
+	if parallelism > 1 && targetServiceName == "" && len(stableServices) > 1 {
+		deployResults, err = da.deployParallel(ctx, stableServices, parallelism)
+	} else {
+		deployResults, err = da.deploySequential(ctx, stableServices, targetServiceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		deployResult := DeploymentResult{
+			Timestamp: time.Now(),
+			Services:  deployResults,
+		}
+
+		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("deploy result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Your application was deployed to Azure in %s.", ux.DurationAsText(since(startTime))),
+			FollowUp: getResourceGroupFollowUp(ctx, da.formatter, da.projectConfig, da.resourceManager, da.env, false),
+		},
+	}, nil
+}
+
+// dryRun prints the deployment plan of each service, what deploying it would do, without changing anything.
+func (da *deployAction) dryRun(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	targetServiceName string,
+	startTime time.Time,
+) (*actions.ActionResult, error) {
+	deployPlans := map[string]*project.ServiceDeployPlan{}
+
+	for _, svc := range services {
+		stepMessage := fmt.Sprintf("Planning deployment of service %s", svc.Name)
 		da.console.ShowSpinner(ctx, stepMessage, input.Step)
 
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		// nil when the service would be packaged before it's deployed
+		var packageResult *project.ServicePackageResult
+		if da.flags.fromPackage != "" {
+			packageResult = &project.ServicePackageResult{
+				PackagePath: da.flags.fromPackage,
+			}
+		} else if da.flags.noBuild {
+			packageResult = da.recordedPackages[svc.Name]
+		}
+
+		deployPlan, err := da.serviceManager.DeployPreview(ctx, svc, packageResult)
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+
+		deployPlans[svc.Name] = deployPlan
+		da.console.MessageUxItem(ctx, deployPlan)
+	}
+
+	if da.formatter.Kind() == output.JsonFormat {
+		deployPlan := DeploymentPlan{
+			Timestamp: time.Now(),
+			Services:  deployPlans,
+		}
+
+		if fmtErr := da.formatter.Format(deployPlan, da.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("deployment plan could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Planned the deployment in %s. Nothing was deployed.", ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
+// loadRecordedPackages returns the packages recorded by the last azd package of the services that are deployed, by service
+// name. The error lists every service without a recorded package.
+func (da *deployAction) loadRecordedPackages(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	targetServiceName string,
+) (map[string]*project.ServicePackageResult, error) {
+	recordedPackages := map[string]*project.ServicePackageResult{}
+	var errs []error
+
+	for _, svc := range services {
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			continue
+		}
+
+		packageResult, err := da.packageManifest.Load(ctx, svc)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		recordedPackages[svc.Name] = packageResult
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("'--no-build' deploys the packages recorded by a previous azd package: %w", errors.Join(errs...))
+	}
+
+	return recordedPackages, nil
+}
+
+// deploySequential deploys the services one at a time, in order, showing the progress of each service in a spinner.
+// Deployment stops at the first service that fails to deploy.
+func (da *deployAction) deploySequential(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	targetServiceName string,
+) (map[string]*project.ServiceDeployResult, error) {
+	deployResults := map[string]*project.ServiceDeployResult{}
+
+	for _, svc := range services {
+		stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
+		da.console.ShowSpinner(ctx, stepMessage, input.StepElapsed)
+
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
@@ -242,75 +471,172 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			da.console.WarnForFeature(ctx, alphaFeatureId)
 		}
 
-		var packageResult *project.ServicePackageResult
-		if da.flags.fromPackage != "" {
-			// --from-package set, skip packaging
-			packageResult = &project.ServicePackageResult{
-				PackagePath: da.flags.fromPackage,
+		deployResult, err := da.deployService(ctx, svc, func(progressMessage string) {
+			da.console.ShowSpinner(ctx, fmt.Sprintf("%s (%s)", stepMessage, progressMessage), input.StepElapsed)
+		})
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+
+		deployResults[svc.Name] = deployResult
+
+		// report deploy outputs
+		da.console.MessageUxItem(ctx, deployResult)
+	}
+
+	return deployResults, nil
+}
+
+// deployParallel deploys up to parallelism services concurrently. A service is deployed once the services it depends
+// on are deployed, and isn't deployed when one of them fails. The services that don't depend on a failed service are
+// still deployed, and the errors of all failed services are returned together.
+//
+// A spinner can't show the progress of several services at once, so a line is written as each service completes.
+func (da *deployAction) deployParallel(
+	ctx context.Context,
+	services []*project.ServiceConfig,
+	parallelism int,
+) (map[string]*project.ServiceDeployResult, error) {
+	deployResults := map[string]*project.ServiceDeployResult{}
+	deployErrors := map[string]error{}
+	var resultsMu sync.Mutex
+
+	// closed when the deployment of the service completes, successfully or not
+	completed := map[string]chan struct{}{}
+	for _, svc := range services {
+		completed[svc.Name] = make(chan struct{})
+	}
+
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for _, svc := range services {
+		if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(svc.Host)); isAlphaFeature {
+			da.console.WarnForFeature(ctx, alphaFeatureId)
+		}
+
+		wg.Add(1)
+		go func(svc *project.ServiceConfig) {
+			defer wg.Done()
+			defer close(completed[svc.Name])
+
+			stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
+
+			// the dependencies that aren't being deployed, such as the services not selected with --filter, are
+			// assumed to be deployed already
+			for _, dependency := range svc.DependsOn {
+				if dependencyCompleted, has := completed[dependency]; has {
+					<-dependencyCompleted
+				}
 			}
-		} else {
-			//  --from-package not set, package the application
-			packageTask := da.serviceManager.Package(ctx, svc, nil, nil)
-			done := make(chan struct{})
-			go func() {
-				for packageProgress := range packageTask.Progress() {
-					progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, packageProgress.Message)
-					da.console.ShowSpinner(ctx, progressMessage, input.Step)
+
+			resultsMu.Lock()
+			var err error
+			for _, dependency := range svc.DependsOn {
+				if _, has := completed[dependency]; !has {
+					continue
+				}
+
+				if _, has := deployResults[dependency]; !has {
+					err = fmt.Errorf(
+						"service '%s' was not deployed because its dependency '%s' failed to deploy", svc.Name, dependency)
+					break
 				}
-				close(done)
-			}()
+			}
+			resultsMu.Unlock()
+
+			var deployResult *project.ServiceDeployResult
+			if err == nil {
+				semaphore <- struct{}{}
+				deployResult, err = da.deployService(ctx, svc, func(progressMessage string) {
+					log.Printf("%s (%s)", stepMessage, progressMessage)
+				})
+				<-semaphore
+			}
+
+			resultsMu.Lock()
+			defer resultsMu.Unlock()
 
-			packageResult, err = packageTask.Await()
-			// wait for console updates to complete
-			<-done
-			// do not stop progress here as next step is to deploy
 			if err != nil {
-				da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
-				return nil, err
+				deployErrors[svc.Name] = err
+				da.console.MessageUxItem(ctx, &ux.FailedMessage{Message: stepMessage})
+				return
 			}
+
+			deployResults[svc.Name] = deployResult
+			da.console.MessageUxItem(ctx, &ux.DoneMessage{Message: stepMessage})
+			da.console.MessageUxItem(ctx, deployResult)
+		}(svc)
+	}
+
+	wg.Wait()
+
+	errs := []error{}
+	for _, svc := range services {
+		if err, has := deployErrors[svc.Name]; has {
+			errs = append(errs, err)
 		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return deployResults, nil
+}
 
-		deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
+// deployService packages the service, unless a package is specified with --from-package or the package recorded by the
+// last azd package is deployed with --no-build, and deploys it. The messages of the packaging and deployment progress are
+// passed to reportProgress.
+func (da *deployAction) deployService(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	reportProgress func(progressMessage string),
+) (*project.ServiceDeployResult, error) {
+	var packageResult *project.ServicePackageResult
+	if da.flags.fromPackage != "" {
+		// --from-package set, skip packaging
+		packageResult = &project.ServicePackageResult{
+			PackagePath: da.flags.fromPackage,
+		}
+	} else if da.flags.noBuild {
+		// --no-build set, deploy the package recorded by the last azd package
+		packageResult = da.recordedPackages[svc.Name]
+	} else {
+		//  --from-package not set, package the application
+		packageTask := da.serviceManager.Package(ctx, svc, nil, nil)
 		done := make(chan struct{})
 		go func() {
-			for deployProgress := range deployTask.Progress() {
-				progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, deployProgress.Message)
-				da.console.ShowSpinner(ctx, progressMessage, input.Step)
+			for packageProgress := range packageTask.Progress() {
+				reportProgress(packageProgress.Message)
 			}
 			close(done)
 		}()
 
-		deployResult, err := deployTask.Await()
-		// wait for console updates to complete
+		var err error
+		packageResult, err = packageTask.Await()
+		// wait for progress updates to complete
 		<-done
-		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
 		if err != nil {
 			return nil, err
 		}
-
-		deployResults[svc.Name] = deployResult
-
-		// report deploy outputs
-		da.console.MessageUxItem(ctx, deployResult)
 	}
 
-	if da.formatter.Kind() == output.JsonFormat {
-		deployResult := DeploymentResult{
-			Timestamp: time.Now(),
-			Services:  deployResults,
+	deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
+	done := make(chan struct{})
+	go func() {
+		for deployProgress := range deployTask.Progress() {
+			reportProgress(deployProgress.Message)
 		}
+		close(done)
+	}()
 
-		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
-			return nil, fmt.Errorf("deploy result could not be displayed: %w", fmtErr)
-		}
-	}
+	deployResult, err := deployTask.Await()
+	// wait for progress updates to complete
+	<-done
 
-	return &actions.ActionResult{
-		Message: &actions.ResultMessage{
-			Header:   fmt.Sprintf("Your application was deployed to Azure in %s.", ux.DurationAsText(since(startTime))),
-			FollowUp: getResourceGroupFollowUp(ctx, da.formatter, da.projectConfig, da.resourceManager, da.env, false),
-		},
-	}, nil
+	return deployResult, err
 }
 
 func getCmdDeployHelpDescription(*cobra.Command) string {
@@ -320,8 +646,14 @@ func getCmdDeployHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is deployed.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(fmt.Sprintf(
+			"When %s is set, only the services whose names match one of the patterns are deployed, in dependency order.",
+			output.WithHighLightFormat("--filter"))),
 		formatHelpNote("After the deployment is complete, the endpoint is printed. To start the service, select" +
 			" the endpoint or paste it in a browser."),
+		formatHelpNote(fmt.Sprintf(
+			"When %s is set, the steps of the deployment of each service are printed and nothing is deployed.",
+			output.WithHighLightFormat("--dry-run"))),
 	})
 }
 
@@ -336,8 +668,17 @@ func getCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'web' to Azure.": output.WithHighLightFormat(
 			"azd deploy web",
 		),
+		"Deploy the services whose names start with 'api-' to Azure.": output.WithHighLightFormat(
+			"azd deploy --filter 'api-*'",
+		),
+		"Print what deploying all services in the current project would do.": output.WithHighLightFormat(
+			"azd deploy --all --dry-run",
+		),
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Deploy all services from the packages recorded by a previous azd package.": output.WithHighLightFormat(
+			"azd deploy --all --no-build",
+		),
 	})
 }
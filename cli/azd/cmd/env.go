@@ -8,16 +8,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -42,11 +47,28 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvSetAction,
 	})
 
+	group.Add("set-secret", &actions.ActionDescriptorOptions{
+		Command:        newEnvSetSecretCmd(),
+		FlagsResolver:  newEnvSetSecretFlags,
+		ActionResolver: newEnvSetSecretAction,
+	})
+
 	group.Add("select", &actions.ActionDescriptorOptions{
 		Command:        newEnvSelectCmd(),
 		ActionResolver: newEnvSelectAction,
 	})
 
+	group.Add("rename", &actions.ActionDescriptorOptions{
+		Command:        newEnvRenameCmd(),
+		ActionResolver: newEnvRenameAction,
+	})
+
+	group.Add("clone", &actions.ActionDescriptorOptions{
+		Command:        newEnvCloneCmd(),
+		FlagsResolver:  newEnvCloneFlags,
+		ActionResolver: newEnvCloneAction,
+	})
+
 	group.Add("new", &actions.ActionDescriptorOptions{
 		Command:        newEnvNewCmd(),
 		FlagsResolver:  newEnvNewFlags,
@@ -56,7 +78,7 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 	group.Add("list", &actions.ActionDescriptorOptions{
 		Command:        newEnvListCmd(),
 		ActionResolver: newEnvListAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat, output.TableFormat},
 		DefaultFormat:  output.TableFormat,
 	})
 
@@ -72,7 +94,17 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		Command:        newEnvGetValuesCmd(),
 		FlagsResolver:  newEnvGetValuesFlags,
 		ActionResolver: newEnvGetValuesAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.EnvVarsFormat},
+		OutputFormats: []output.Format{
+			output.JsonFormat, output.YamlFormat, output.EnvVarsFormat, output.ShellFormat,
+		},
+		DefaultFormat: output.EnvVarsFormat,
+	})
+
+	group.Add("export", &actions.ActionDescriptorOptions{
+		Command:        newEnvExportCmd(),
+		FlagsResolver:  newEnvExportFlags,
+		ActionResolver: newEnvExportAction,
+		OutputFormats:  []output.Format{output.EnvVarsFormat, output.JsonFormat},
 		DefaultFormat:  output.EnvVarsFormat,
 	})
 
@@ -96,11 +128,21 @@ func newEnvSetCmd() *cobra.Command {
 
 type envSetFlags struct {
 	envFlag
+	secret boolPtr
 	global *internal.GlobalCommandOptions
 }
 
 func (f *envSetFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	f.envFlag.Bind(local, global)
+	secretFlag := local.VarPF(
+		&f.secret,
+		"secret",
+		"",
+		//nolint:lll
+		"Marks the value as a secret. Secrets must be Key Vault references, ex) keyvault://<vault>/<secret>, which are resolved when the value is used and excluded when the environment is exported. A key that is already a secret stays one unless --secret=false is set.",
+	)
+	// ensure the flag behaves as a common boolean flag which is set to true when used without any other arg
+	secretFlag.NoOptDefVal = "true"
 	f.global = global
 }
 
@@ -132,7 +174,31 @@ func newEnvSetAction(
 }
 
 func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// a key that is already a secret stays one, unless the mark is explicitly cleared
+	secret := e.env.IsSecret(e.args[0])
+	if e.flags.secret.ptr != nil {
+		value, err := strconv.ParseBool(*e.flags.secret.ptr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --secret: %s", *e.flags.secret.ptr)
+		}
+
+		secret = value
+	}
+
+	if _, isReference := environment.ParseKeyVaultReference(e.args[1]); secret && !isReference {
+		return nil, fmt.Errorf(
+			"the value of secret '%s' must be a key vault reference, ex) keyvault://<vault>/<secret>, "+
+				"so the secret isn't saved in plaintext. Run 'azd env set-secret %s' to store the value in key vault, "+
+				"or set --secret=false to store it as a plain value",
+			e.args[0],
+			e.args[0],
+		)
+	}
+
 	e.env.DotenvSet(e.args[0], e.args[1])
+	if err := e.env.SetSecret(e.args[0], secret); err != nil {
+		return nil, fmt.Errorf("marking secret: %w", err)
+	}
 
 	if err := e.envManager.Save(ctx, e.env); err != nil {
 		return nil, fmt.Errorf("saving environment: %w", err)
@@ -141,6 +207,147 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	return nil, nil
 }
 
+func newEnvSetSecretFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSetSecretFlags {
+	flags := &envSetSecretFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvSetSecretCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-secret <key>",
+		Short: "Store a secret value in Key Vault and reference it from your environment.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type envSetSecretFlags struct {
+	envFlag
+	vault  string
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envSetSecretFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	local.StringVar(
+		&f.vault,
+		"vault",
+		"",
+		//nolint:lll
+		"The name of the Key Vault that stores the secret. Defaults to the vault the key already references, or else to the AZURE_KEY_VAULT_NAME environment value. Otherwise, the vault is prompted for.",
+	)
+	f.global = global
+}
+
+type envSetSecretAction struct {
+	console             input.Console
+	env                 *environment.Environment
+	envManager          environment.Manager
+	resourceGraphClient *lazy.Lazy[*armresourcegraph.Client]
+	flags               *envSetSecretFlags
+	args                []string
+}
+
+func newEnvSetSecretAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	resourceGraphClient *lazy.Lazy[*armresourcegraph.Client],
+	console input.Console,
+	flags *envSetSecretFlags,
+	args []string,
+) actions.Action {
+	return &envSetSecretAction{
+		console:             console,
+		env:                 env,
+		envManager:          envManager,
+		resourceGraphClient: resourceGraphClient,
+		flags:               flags,
+		args:                args,
+	}
+}
+
+func (e *envSetSecretAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	key := e.args[0]
+
+	// a key that already references a secret keeps storing its value in that secret
+	reference, isReference := environment.ParseKeyVaultReference(e.env.Getenv(key))
+	if !isReference || (e.flags.vault != "" && e.flags.vault != reference.Vault) {
+		reference = environment.KeyVaultReference{
+			Vault:      e.flags.vault,
+			SecretName: environment.KeyVaultSecretName(key),
+		}
+	}
+
+	if reference.Vault == "" {
+		reference.Vault = e.env.Getenv(environment.KeyVaultNameEnvVarName)
+	}
+
+	if reference.Vault == "" && !e.console.IsNoPromptMode() {
+		vault, err := e.promptVault(ctx)
+		if err != nil {
+			return nil, err
+		}
+		reference.Vault = vault
+	}
+
+	if reference.Vault == "" {
+		return nil, fmt.Errorf(
+			"no key vault to store secret '%s' in, set the vault with --vault or the %s environment value",
+			key,
+			environment.KeyVaultNameEnvVarName,
+		)
+	}
+
+	value, err := e.console.Prompt(ctx, input.ConsoleOptions{
+		Message:    fmt.Sprintf("Enter the value of secret '%s'", key),
+		IsPassword: true,
+		Required:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prompting for secret value: %w", err)
+	}
+
+	if err := e.envManager.SaveSecret(ctx, e.env, key, reference, value); err != nil {
+		return nil, fmt.Errorf("saving secret: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Stored secret '%s' in key vault '%s', referenced as %s.", key, reference.Vault, reference.String()),
+		},
+	}, nil
+}
+
+// promptVault prompts for the name of the Key Vault to store the secret in, suggesting the vaults of the subscription of
+// the environment.
+func (e *envSetSecretAction) promptVault(ctx context.Context) (string, error) {
+	options := input.ConsoleOptions{
+		Message:  "Enter the name of the Key Vault to store the secret in",
+		Help:     fmt.Sprintf("Set --vault or the %s environment value to skip this prompt.", environment.KeyVaultNameEnvVarName),
+		Required: true,
+	}
+
+	if subscriptionId := e.env.GetSubscriptionId(); subscriptionId != "" {
+		options.Suggest = prompt.NewSuggester(ctx, func(ctx context.Context) ([]string, error) {
+			client, err := e.resourceGraphClient.GetValue()
+			if err != nil {
+				return nil, err
+			}
+
+			return prompt.ResourceSuggestions(client, subscriptionId, "Microsoft.KeyVault/vaults")(ctx)
+		})
+	}
+
+	vault, err := e.console.Prompt(ctx, options)
+	if err != nil {
+		return "", fmt.Errorf("prompting for key vault: %w", err)
+	}
+
+	return strings.TrimSpace(vault), nil
+}
+
 func newEnvSelectCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "select <environment>",
@@ -182,6 +389,43 @@ func (e *envSelectAction) Run(ctx context.Context) (*actions.ActionResult, error
 	return nil, nil
 }
 
+func newEnvRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <environment> <new-name>",
+		Short: "Rename an environment.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type envRenameAction struct {
+	envManager environment.Manager
+	args       []string
+}
+
+func newEnvRenameAction(envManager environment.Manager, args []string) actions.Action {
+	return &envRenameAction{
+		envManager: envManager,
+		args:       args,
+	}
+}
+
+func (e *envRenameAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	oldName, newName := e.args[0], e.args[1]
+
+	err := e.envManager.Rename(ctx, oldName, newName)
+	if errors.Is(err, environment.ErrNotFound) {
+		return nil, fmt.Errorf(`environment '%s' does not exist. Run "azd env list" to see the existing environments`, oldName)
+	} else if err != nil {
+		return nil, fmt.Errorf("renaming environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Renamed environment '%s' to '%s'.", oldName, newName),
+		},
+	}, nil
+}
+
 func newEnvListCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:     "list",
@@ -251,6 +495,80 @@ func (e *envListAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	return nil, nil
 }
 
+type envCloneFlags struct {
+	excludeSecrets bool
+	selectEnv      bool
+	global         *internal.GlobalCommandOptions
+}
+
+func (f *envCloneFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.excludeSecrets, "exclude-secrets", false, "Excludes the values marked as secrets from the clone.")
+	local.BoolVar(&f.selectEnv, "select", false, "Sets the new environment as the default environment.")
+
+	f.global = global
+}
+
+func newEnvCloneFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envCloneFlags {
+	flags := &envCloneFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvCloneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clone <source> <destination>",
+		Short: "Copy the values and configuration of an environment into a new environment.",
+		Long: "Copy the values and configuration of an environment into a new environment. " +
+			"Values set from infrastructure outputs are not copied and are set when the new environment is provisioned.",
+		Args: cobra.ExactArgs(2),
+	}
+}
+
+type envCloneAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	flags      *envCloneFlags
+	args       []string
+}
+
+func newEnvCloneAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	flags *envCloneFlags,
+	args []string,
+) actions.Action {
+	return &envCloneAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (e *envCloneAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	source, dest := e.args[0], e.args[1]
+
+	_, err := e.envManager.Clone(ctx, source, dest, environment.CloneOptions{
+		ExcludeSecrets: e.flags.excludeSecrets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloning environment: %w", err)
+	}
+
+	if e.flags.selectEnv {
+		if err := e.azdCtx.SetDefaultEnvironmentName(dest); err != nil {
+			return nil, fmt.Errorf("setting default environment: %w", err)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Cloned environment '%s' to '%s'.", source, dest),
+		},
+	}, nil
+}
+
 type envNewFlags struct {
 	subscription string
 	location     string
@@ -514,16 +832,26 @@ func newEnvGetValuesCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get-values",
 		Short: "Get all environment values.",
+		Long: "Get all environment values.\n\n" +
+			"Use '--output shell' to print POSIX shell export statements, which can be loaded with " +
+			"'eval \"$(azd env get-values --output shell)\"'.",
 	}
 }
 
 type envGetValuesFlags struct {
 	envFlag
-	global *internal.GlobalCommandOptions
+	maskSecrets bool
+	global      *internal.GlobalCommandOptions
 }
 
 func (eg *envGetValuesFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	eg.envFlag.Bind(local, global)
+	local.BoolVar(
+		&eg.maskSecrets,
+		"mask-secrets",
+		false,
+		"Replaces the values marked as secrets with 'azd env set --secret' with a mask.",
+	)
 	eg.global = global
 }
 
@@ -575,7 +903,100 @@ func (eg *envGetValuesAction) Run(ctx context.Context) (*actions.ActionResult, e
 		return nil, fmt.Errorf("ensuring environment exists: %w", err)
 	}
 
-	return nil, eg.formatter.Format(env.Dotenv(), eg.writer, nil)
+	options := environment.ExportOptions{
+		IncludeSecrets: true,
+		MaskSecrets:    eg.flags.maskSecrets,
+	}
+
+	return nil, eg.formatter.Format(env.Export(options), eg.writer, nil)
+}
+
+func newEnvExportFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envExportFlags {
+	flags := &envExportFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export",
+		Short: "Export environment values as a .env file.",
+	}
+}
+
+type envExportFlags struct {
+	envFlag
+	prefix         string
+	includeSecrets bool
+	global         *internal.GlobalCommandOptions
+}
+
+func (ef *envExportFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	ef.envFlag.Bind(local, global)
+	local.StringVar(&ef.prefix, "prefix", "", "Exports only the values with keys that start with the prefix.")
+	local.BoolVar(
+		&ef.includeSecrets,
+		"include-secrets",
+		false,
+		"Exports the values marked as secrets with 'azd env set --secret', which are excluded by default.",
+	)
+	ef.global = global
+}
+
+type envExportAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	formatter  output.Formatter
+	writer     io.Writer
+	flags      *envExportFlags
+}
+
+func newEnvExportAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envExportFlags,
+) actions.Action {
+	return &envExportAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		formatter:  formatter,
+		writer:     writer,
+		flags:      flags,
+	}
+}
+
+func (ee *envExportAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := ee.flags.environmentName
+	if name == "" {
+		defaultName, err := ee.azdCtx.GetDefaultEnvironmentName()
+		if err != nil {
+			return nil, err
+		}
+		name = defaultName
+	}
+
+	env, err := ee.envManager.Get(ctx, name)
+	if errors.Is(err, environment.ErrNotFound) {
+		return nil, fmt.Errorf(
+			`environment does not exist. You can create it with "azd env new"`,
+		)
+	} else if err != nil {
+		return nil, fmt.Errorf("ensuring environment exists: %w", err)
+	}
+
+	options := environment.ExportOptions{
+		Prefix:         ee.flags.prefix,
+		IncludeSecrets: ee.flags.includeSecrets,
+	}
+
+	if ee.formatter.Kind() == output.JsonFormat {
+		return nil, ee.formatter.Format(env.Export(options), ee.writer, nil)
+	}
+
+	return nil, env.ExportDotenv(ee.writer, options)
 }
 
 func getCmdEnvHelpDescription(*cobra.Command) string {
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_envSetAction_Secret(t *testing.T) {
+	const reference = "keyvault://vault/secret"
+
+	tests := []struct {
+		name   string
+		secret *string
+		value  string
+		want   bool
+		err    string
+	}{
+		{name: "KeepsMark", value: "keyvault://vault/other", want: true},
+		{name: "ClearsMark", secret: to.Ptr("false"), value: "plain", want: false},
+		{name: "Marks", secret: to.Ptr("true"), value: reference, want: true},
+		{name: "RejectsPlainValueOfSecret", value: "plain", err: "must be a key vault reference"},
+		{name: "RejectsInvalidFlag", secret: to.Ptr("maybe"), value: reference, err: "invalid value for --secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := environment.NewWithValues("test", map[string]string{"DB_PASSWORD": reference})
+			require.NoError(t, env.SetSecret("DB_PASSWORD", true))
+
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", mock.Anything, env).Return(nil)
+
+			action := &envSetAction{
+				env:        env,
+				envManager: envManager,
+				flags:      &envSetFlags{secret: boolPtr{ptr: tt.secret}},
+				args:       []string{"DB_PASSWORD", tt.value},
+			}
+
+			_, err := action.Run(context.Background())
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.value, env.Getenv("DB_PASSWORD"))
+			require.Equal(t, tt.want, env.IsSecret("DB_PASSWORD"))
+		})
+	}
+}
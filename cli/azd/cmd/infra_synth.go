@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
@@ -211,7 +213,7 @@ func (a *infraSynthAction) promptForDuplicates(
 	return nil, nil
 }
 
-// Returns files that are both present in source and target.
+// Returns files that are both present in source and target, with different contents in target.
 // The files returned are expressed in their relative paths to source/target.
 func determineDuplicates(source string, target string) ([]string, error) {
 	var duplicateFiles []string
@@ -229,7 +231,20 @@ func determineDuplicates(source string, target string) ([]string, error) {
 			return fmt.Errorf("computing relative path: %w", err)
 		}
 
-		if _, err := os.Stat(filepath.Join(target, partial)); err == nil {
+		targetContents, err := os.ReadFile(filepath.Join(target, partial))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("reading %s: %w", partial, err)
+		}
+
+		// files that are synthesized unchanged, like the files of the infra folder of the project, aren't overwritten
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(contents, targetContents) {
 			duplicateFiles = append(duplicateFiles, partial)
 		}
 
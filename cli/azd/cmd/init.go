@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -16,6 +17,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/repository"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -48,11 +50,14 @@ func newInitCmd() *cobra.Command {
 }
 
 type initFlags struct {
-	templatePath   string
-	templateBranch string
-	subscription   string
-	location       string
-	global         *internal.GlobalCommandOptions
+	templatePath        string
+	templateBranch      string
+	templateRef         string
+	subscription        string
+	location            string
+	generateDockerfiles bool
+	fromCode            bool
+	global              *internal.GlobalCommandOptions
 	envFlag
 }
 
@@ -71,6 +76,13 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		"b",
 		"",
 		"The template branch to initialize from. Must be used with a template argument (--template or -t).")
+	local.StringVar(
+		&i.templateRef,
+		"ref",
+		"",
+		//nolint:lll
+		"The template branch, tag or full commit SHA to initialize from. Must be used with a template argument (--template or -t). Defaults to the default branch of the template.",
+	)
 	local.StringVarP(
 		&i.subscription,
 		"subscription",
@@ -79,6 +91,20 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		"Name or ID of an Azure subscription to use for the new environment",
 	)
 	local.StringVarP(&i.location, "location", "l", "", "Azure location for the new environment")
+	local.BoolVar(
+		&i.generateDockerfiles,
+		"generate-dockerfiles",
+		false,
+		//nolint:lll
+		"When initializing from app code, generate a Dockerfile for each detected Python, JavaScript, TypeScript and Java service that doesn't have one, instead of building with buildpacks.",
+	)
+	local.BoolVar(
+		&i.fromCode,
+		"from-code",
+		false,
+		//nolint:lll
+		"Initialize the app from the code in the current directory. With --no-prompt, the detected services are used as they are and the environment name must be set with --environment.",
+	)
 	i.envFlag.Bind(local, global)
 
 	i.global = global
@@ -94,6 +120,8 @@ type initAction struct {
 	repoInitializer *repository.Initializer
 	templateManager *templates.TemplateManager
 	featuresManager *alpha.FeatureManager
+	// the account is only resolved when it's used, so that init runs without a login otherwise
+	lazyAccountManager *lazy.Lazy[account.Manager]
 }
 
 func newInitAction(
@@ -105,17 +133,19 @@ func newInitAction(
 	flags *initFlags,
 	repoInitializer *repository.Initializer,
 	templateManager *templates.TemplateManager,
-	featuresManager *alpha.FeatureManager) actions.Action {
+	featuresManager *alpha.FeatureManager,
+	lazyAccountManager *lazy.Lazy[account.Manager]) actions.Action {
 	return &initAction{
-		lazyAzdCtx:      lazyAzdCtx,
-		lazyEnvManager:  lazyEnvManager,
-		console:         console,
-		cmdRun:          cmdRun,
-		gitCli:          gitCli,
-		flags:           flags,
-		repoInitializer: repoInitializer,
-		templateManager: templateManager,
-		featuresManager: featuresManager,
+		lazyAzdCtx:         lazyAzdCtx,
+		lazyEnvManager:     lazyEnvManager,
+		console:            console,
+		cmdRun:             cmdRun,
+		gitCli:             gitCli,
+		flags:              flags,
+		repoInitializer:    repoInitializer,
+		templateManager:    templateManager,
+		featuresManager:    featuresManager,
+		lazyAccountManager: lazyAccountManager,
 	}
 }
 
@@ -137,6 +167,25 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 				"Using branch argument (-b or --branch) requires a template argument (--template or -t) to be specified.")
 	}
 
+	if i.flags.templateRef != "" && i.flags.templatePath == "" {
+		return nil,
+			errors.New(
+				"Using ref argument (--ref) requires a template argument (--template or -t) to be specified.")
+	}
+
+	if i.flags.templateRef != "" && i.flags.templateBranch != "" {
+		return nil, errors.New("--branch (-b) and --ref can't be used together.")
+	}
+
+	if i.flags.fromCode && i.flags.templatePath != "" {
+		return nil, errors.New("--from-code and --template (-t) can't be used together.")
+	}
+
+	if i.flags.fromCode && i.console.IsNoPromptMode() && i.flags.environmentName == "" {
+		return nil, errors.New(
+			"Using --from-code with --no-prompt requires an environment name (--environment or -e) to be specified.")
+	}
+
 	// ensure that git is available
 	if err := tools.EnsureInstalled(ctx, []tools.ExternalTool{i.gitCli}...); err != nil {
 		return nil, err
@@ -156,13 +205,33 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("checking if project exists: %w", err)
 	}
 
+	envName, err := azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving default environment name: %w", err)
+	}
+
+	// the subscription and location are validated before the app is initialized, so invalid values don't leave a
+	// partially initialized project behind. They're only used when a new environment is created.
+	var subscriptionId, location string
+	if envName == "" {
+		subscriptionId, location, err = i.resolveSubscriptionAndLocation(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var initTypeSelect initType
 	if i.flags.templatePath != "" {
 		// an explicit --template passed, always initialize from app template
 		initTypeSelect = initAppTemplate
 	}
 
-	if i.flags.templatePath == "" && existingProject {
+	if i.flags.fromCode {
+		// an explicit --from-code passed, always initialize from the code in the current directory
+		initTypeSelect = initFromApp
+	}
+
+	if initTypeSelect == initUnknown && existingProject {
 		// no explicit --template, and azure.yaml exists, only initialize environment
 		initTypeSelect = initEnvironment
 	}
@@ -194,7 +263,7 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			templateMetadata = &template.Metadata
 		}
 
-		if _, err := i.initializeEnv(ctx, azdCtx, templateMetadata); err != nil {
+		if _, err := i.initializeEnv(ctx, azdCtx, subscriptionId, location, templateMetadata); err != nil {
 			return nil, err
 		}
 	case initFromApp:
@@ -220,13 +289,26 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 
 		err = i.repoInitializer.InitFromApp(ctx, azdCtx, func() (*environment.Environment, error) {
-			return i.initializeEnv(ctx, azdCtx, nil)
+			return i.initializeEnv(ctx, azdCtx, subscriptionId, location, nil)
+		}, repository.InitFromAppOptions{
+			GenerateDockerfiles: i.flags.generateDockerfiles,
+			FromCode:            i.flags.fromCode,
 		})
 		if err != nil {
 			return nil, err
 		}
+
+		// the next steps are shown right away, the rest of the guidance is left in the file
+		nextSteps, err := nextStepsSection(filepath.Join(azdCtx.ProjectDirectory(), "next-steps.md"))
+		if err != nil {
+			return nil, err
+		}
+		if nextSteps != "" {
+			i.console.Message(ctx, "")
+			i.console.MessageUxItem(ctx, &ux.Markdown{Content: nextSteps})
+		}
 	case initEnvironment:
-		_, err = i.initializeEnv(ctx, azdCtx, nil)
+		_, err = i.initializeEnv(ctx, azdCtx, subscriptionId, location, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -242,6 +324,35 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}, nil
 }
 
+// nextStepsSection returns the "Next Steps" section of the next-steps.md file at path, with its heading. Returns an
+// empty string when the file or the section doesn't exist.
+func nextStepsSection(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("reading next steps: %w", err)
+	}
+
+	var section []string
+	inSection := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "## ") {
+			if inSection {
+				break
+			}
+
+			inSection = strings.TrimSpace(strings.TrimPrefix(line, "## ")) == "Next Steps"
+		}
+
+		if inSection {
+			section = append(section, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(section, "\n")), nil
+}
+
 type initType int
 
 const (
@@ -301,7 +412,12 @@ func (i *initAction) initializeTemplate(
 			}
 		}
 
-		err = i.repoInitializer.Initialize(ctx, azdCtx, template, i.flags.templateBranch)
+		templateRef := i.flags.templateRef
+		if templateRef == "" {
+			templateRef = i.flags.templateBranch
+		}
+
+		err = i.repoInitializer.Initialize(ctx, azdCtx, template, templateRef)
 		if err != nil {
 			return nil, fmt.Errorf("init from template repository: %w", err)
 		}
@@ -318,6 +434,8 @@ func (i *initAction) initializeTemplate(
 func (i *initAction) initializeEnv(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
+	subscriptionId string,
+	location string,
 	templateMetadata *templates.Metadata) (*environment.Environment, error) {
 	envName, err := azdCtx.GetDefaultEnvironmentName()
 	if err != nil {
@@ -349,8 +467,8 @@ func (i *initAction) initializeEnv(
 
 	envSpec := environment.Spec{
 		Name:         i.flags.environmentName,
-		Subscription: i.flags.subscription,
-		Location:     i.flags.location,
+		Subscription: subscriptionId,
+		Location:     location,
 		Examples:     examples,
 	}
 
@@ -383,6 +501,113 @@ func (i *initAction) initializeEnv(
 	return env, nil
 }
 
+// resolveSubscriptionAndLocation returns the subscription ID and location name the new environment is created with, so that
+// provisioning doesn't prompt for them. The --subscription and --location flags take precedence over the
+// AZURE_SUBSCRIPTION_ID and AZURE_LOCATION environment variables. Either way, the subscription must be accessible by the
+// current account, and the location must be available in the subscription, or in the default subscription when none is
+// set. Empty values are returned, without signing in, when neither is set.
+func (i *initAction) resolveSubscriptionAndLocation(
+	ctx context.Context) (subscriptionId string, location string, err error) {
+	subscription, subscriptionSource := i.flags.subscription, "--subscription"
+	if subscription == "" {
+		subscription, subscriptionSource = os.Getenv(environment.SubscriptionIdEnvVarName), environment.SubscriptionIdEnvVarName
+	}
+
+	location, locationSource := i.flags.location, "--location"
+	if location == "" {
+		location, locationSource = os.Getenv(environment.LocationEnvVarName), environment.LocationEnvVarName
+	}
+
+	if subscription == "" && location == "" {
+		return "", "", nil
+	}
+
+	accountManager, err := i.lazyAccountManager.GetValue()
+	if err != nil {
+		return "", "", err
+	}
+
+	if subscription != "" {
+		subscriptions, err := accountManager.GetSubscriptions(ctx)
+		if err != nil {
+			return "", "", fmt.Errorf("listing subscriptions: %w", err)
+		}
+
+		sub, err := findSubscription(subscriptions, subscription)
+		if err != nil {
+			return "", "", &azcli.ErrorWithSuggestion{
+				Err: fmt.Errorf("invalid %s: %w", subscriptionSource, err),
+				Suggestion: "Run 'azd auth login' to sign in with an account that has access to the " +
+					"subscription, or run 'az account list' to list the subscriptions of the current account.",
+			}
+		}
+
+		subscriptionId = sub.Id
+	}
+
+	if location != "" {
+		// the location is validated against the default subscription when no subscription is set, and is kept as it is
+		// when there's no default either, in which case provisioning validates it once a subscription is selected.
+		locationsSubscriptionId := subscriptionId
+		if locationsSubscriptionId == "" {
+			locationsSubscriptionId = accountManager.GetDefaultSubscriptionID(ctx)
+		}
+
+		if locationsSubscriptionId != "" {
+			locations, err := accountManager.GetLocations(ctx, locationsSubscriptionId)
+			if err != nil {
+				return "", "", fmt.Errorf("listing locations: %w", err)
+			}
+
+			loc, err := findLocation(locations, location)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid %s: %w in subscription %s", locationSource, err, locationsSubscriptionId)
+			}
+
+			location = loc.Name
+		}
+	}
+
+	return subscriptionId, location, nil
+}
+
+// findSubscription returns the subscription with the given ID or name. Names are matched case-insensitively, and must
+// identify a single subscription.
+func findSubscription(subscriptions []account.Subscription, nameOrId string) (*account.Subscription, error) {
+	var matches []account.Subscription
+	for _, sub := range subscriptions {
+		if strings.EqualFold(sub.Id, nameOrId) {
+			return &sub, nil
+		}
+
+		if strings.EqualFold(sub.Name, nameOrId) {
+			matches = append(matches, sub)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("subscription '%s' not found", nameOrId)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf(
+			"subscription name '%s' matches %d subscriptions, use the subscription ID instead", nameOrId, len(matches))
+	}
+}
+
+// findLocation returns the location with the given name (e.g. "westus2") or display name (e.g. "West US 2"), matched
+// case-insensitively.
+func findLocation(locations []account.Location, name string) (*account.Location, error) {
+	for _, loc := range locations {
+		if strings.EqualFold(loc.Name, name) || strings.EqualFold(loc.DisplayName, name) {
+			return &loc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("location '%s' not found", name)
+}
+
 func getCmdInitHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription("Initialize a new application in your current directory.",
 		[]string{
@@ -409,5 +634,11 @@ func getCmdInitHelpFooter(*cobra.Command) string {
 			output.WithHighLightFormat("--branch"),
 			output.WithWarningFormat("[Branch name]"),
 		),
+		"Initialize a template to your current local directory from a tag or commit.": fmt.Sprintf("%s %s %s %s",
+			output.WithHighLightFormat("azd init --template"),
+			output.WithWarningFormat("[GitHub repo URL]"),
+			output.WithHighLightFormat("--ref"),
+			output.WithWarningFormat("[Tag or commit SHA]"),
+		),
 	})
 }
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findSubscription(t *testing.T) {
+	subscriptions := []account.Subscription{
+		{Id: "00000000-0000-0000-0000-000000000001", Name: "Dev"},
+		{Id: "00000000-0000-0000-0000-000000000002", Name: "Prod"},
+		{Id: "00000000-0000-0000-0000-000000000003", Name: "Prod"},
+	}
+
+	sub, err := findSubscription(subscriptions, "00000000-0000-0000-0000-000000000002")
+	require.NoError(t, err)
+	require.Equal(t, "00000000-0000-0000-0000-000000000002", sub.Id)
+
+	sub, err = findSubscription(subscriptions, "dev")
+	require.NoError(t, err)
+	require.Equal(t, "00000000-0000-0000-0000-000000000001", sub.Id)
+
+	_, err = findSubscription(subscriptions, "Prod")
+	require.ErrorContains(t, err, "matches 2 subscriptions")
+
+	_, err = findSubscription(subscriptions, "Test")
+	require.ErrorContains(t, err, "subscription 'Test' not found")
+}
+
+func Test_findLocation(t *testing.T) {
+	locations := []account.Location{
+		{Name: "eastus", DisplayName: "East US", RegionalDisplayName: "(US) East US"},
+		{Name: "westus2", DisplayName: "West US 2", RegionalDisplayName: "(US) West US 2"},
+	}
+
+	loc, err := findLocation(locations, "westus2")
+	require.NoError(t, err)
+	require.Equal(t, "westus2", loc.Name)
+
+	loc, err = findLocation(locations, "east us")
+	require.NoError(t, err)
+	require.Equal(t, "eastus", loc.Name)
+
+	_, err = findLocation(locations, "northeurope")
+	require.ErrorContains(t, err, "location 'northeurope' not found")
+}
+
+func Test_initAction_resolveSubscriptionAndLocation(t *testing.T) {
+	accountManager := &mockaccount.MockAccountManager{
+		Subscriptions: []account.Subscription{
+			{Id: "00000000-0000-0000-0000-000000000001", Name: "Dev"},
+			{Id: "00000000-0000-0000-0000-000000000002", Name: "Prod"},
+		},
+		Locations: []account.Location{
+			{Name: "eastus", DisplayName: "East US"},
+			{Name: "westus2", DisplayName: "West US 2"},
+		},
+	}
+
+	newAction := func(flags *initFlags) *initAction {
+		return &initAction{
+			flags: flags,
+			lazyAccountManager: lazy.NewLazy(func() (account.Manager, error) {
+				return accountManager, nil
+			}),
+		}
+	}
+
+	t.Run("NotSet", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "")
+		t.Setenv(environment.LocationEnvVarName, "")
+
+		action := &initAction{
+			flags: &initFlags{},
+			lazyAccountManager: lazy.NewLazy(func() (account.Manager, error) {
+				return nil, errors.New("not signed in")
+			}),
+		}
+
+		subscriptionId, location, err := action.resolveSubscriptionAndLocation(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, subscriptionId)
+		require.Empty(t, location)
+	})
+
+	t.Run("FromFlags", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "00000000-0000-0000-0000-000000000001")
+		t.Setenv(environment.LocationEnvVarName, "eastus")
+
+		action := newAction(&initFlags{subscription: "prod", location: "West US 2"})
+		subscriptionId, location, err := action.resolveSubscriptionAndLocation(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "00000000-0000-0000-0000-000000000002", subscriptionId)
+		require.Equal(t, "westus2", location)
+	})
+
+	t.Run("FromEnvironment", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "Dev")
+		t.Setenv(environment.LocationEnvVarName, "east us")
+
+		action := newAction(&initFlags{})
+		subscriptionId, location, err := action.resolveSubscriptionAndLocation(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", subscriptionId)
+		require.Equal(t, "eastus", location)
+	})
+
+	t.Run("InvalidSubscriptionFromEnvironment", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "Test")
+		t.Setenv(environment.LocationEnvVarName, "")
+
+		action := newAction(&initFlags{})
+		_, _, err := action.resolveSubscriptionAndLocation(context.Background())
+		require.ErrorContains(t, err, "invalid AZURE_SUBSCRIPTION_ID: subscription 'Test' not found")
+	})
+
+	t.Run("InvalidLocationFromEnvironment", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "")
+		t.Setenv(environment.LocationEnvVarName, "northeurope")
+
+		accountManager.DefaultSubscription = "00000000-0000-0000-0000-000000000001"
+		t.Cleanup(func() { accountManager.DefaultSubscription = "" })
+
+		action := newAction(&initFlags{})
+		_, _, err := action.resolveSubscriptionAndLocation(context.Background())
+		require.ErrorContains(t, err, "invalid AZURE_LOCATION: location 'northeurope' not found")
+	})
+
+	t.Run("LocationWithoutSubscription", func(t *testing.T) {
+		t.Setenv(environment.SubscriptionIdEnvVarName, "")
+		t.Setenv(environment.LocationEnvVarName, "")
+
+		// without a subscription to validate against, the location is kept as it is
+		action := newAction(&initFlags{location: "northeurope"})
+		subscriptionId, location, err := action.resolveSubscriptionAndLocation(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, subscriptionId)
+		require.Equal(t, "northeurope", location)
+	})
+}
+
+func Test_nextStepsSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "next-steps.md")
+
+	section, err := nextStepsSection(path)
+	require.NoError(t, err)
+	require.Empty(t, section)
+
+	content := "# Next Steps after `azd init`\r\n\r\n## Table of Contents\r\n\r\n1. [Next Steps](#next-steps)\r\n\r\n" +
+		"## Next Steps\r\n\r\n### Provision\r\n\r\nRun `azd up`.\r\n\r\n## Billing\r\n\r\nCosts apply.\r\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	section, err = nextStepsSection(path)
+	require.NoError(t, err)
+	require.Equal(t, "## Next Steps\n\n### Provision\n\nRun `azd up`.", section)
+}
@@ -109,7 +109,18 @@ func (m *HooksMiddleware) registerCommandHooks(
 	commandNames := []string{m.options.CommandPath}
 	commandNames = append(commandNames, m.options.Aliases...)
 
+	preHooks, err := hooksManager.GetByParams(projectConfig.Hooks, ext.HookTypePre, commandNames...)
+	if err != nil {
+		return nil, err
+	}
+
 	err = hooksRunner.Invoke(ctx, commandNames, func() error {
+		// pre hooks, such as a prebuild or prepackage hook that generates code, may change the inputs of an app host,
+		// so the app host manifests cached while the hooks were registered are generated again
+		if len(preHooks) > 0 {
+			m.importManager.ResetCache()
+		}
+
 		result, err := next(ctx)
 		if err != nil {
 			return err
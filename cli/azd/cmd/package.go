@@ -13,15 +13,19 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/imagescan"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type packageFlags struct {
-	all    bool
-	global *internal.GlobalCommandOptions
+	all     bool
+	filters []string
+	global  *internal.GlobalCommandOptions
 	*envFlag
 	outputPath string
+	scan       bool
+	push       bool
 }
 
 func newPackageFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *packageFlags {
@@ -44,12 +48,31 @@ func (pf *packageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 		false,
 		"Packages all services that are listed in "+azdcontext.ProjectFileName,
 	)
+	local.StringArrayVar(
+		&pf.filters,
+		"filter",
+		nil,
+		"Packages the services whose names match the glob `pattern`. Can be specified multiple times.",
+	)
 	local.StringVar(
 		&pf.outputPath,
 		"output-path",
 		"",
 		"File or folder path where the generated packages will be saved.",
 	)
+	local.BoolVar(
+		&pf.scan,
+		"scan",
+		false,
+		"Scans the container images of the services for vulnerabilities with Trivy or docker scout.",
+	)
+	local.BoolVar(
+		&pf.push,
+		"push",
+		false,
+		"Pushes the container images of the services to their registries, so the packages can be deployed from "+
+			"another machine with azd deploy --no-build.",
+	)
 }
 
 func newPackageCmd() *cobra.Command {
@@ -65,15 +88,18 @@ func newPackageCmd() *cobra.Command {
 }
 
 type packageAction struct {
-	flags          *packageFlags
-	args           []string
-	projectConfig  *project.ProjectConfig
-	projectManager project.ProjectManager
-	importManager  *project.ImportManager
-	serviceManager project.ServiceManager
-	console        input.Console
-	formatter      output.Formatter
-	writer         io.Writer
+	flags           *packageFlags
+	args            []string
+	projectConfig   *project.ProjectConfig
+	projectManager  project.ProjectManager
+	importManager   *project.ImportManager
+	serviceManager  project.ServiceManager
+	imageScanner    *project.ImageScanner
+	packageManifest *project.PackageManifest
+	containerHelper *project.ContainerHelper
+	console         input.Console
+	formatter       output.Formatter
+	writer          io.Writer
 }
 
 func newPackageAction(
@@ -86,23 +112,30 @@ func newPackageAction(
 	formatter output.Formatter,
 	writer io.Writer,
 	importManager *project.ImportManager,
+	imageScanner *project.ImageScanner,
+	packageManifest *project.PackageManifest,
+	containerHelper *project.ContainerHelper,
 ) actions.Action {
 	return &packageAction{
-		flags:          flags,
-		args:           args,
-		projectConfig:  projectConfig,
-		projectManager: projectManager,
-		serviceManager: serviceManager,
-		console:        console,
-		formatter:      formatter,
-		writer:         writer,
-		importManager:  importManager,
+		flags:           flags,
+		args:            args,
+		projectConfig:   projectConfig,
+		projectManager:  projectManager,
+		serviceManager:  serviceManager,
+		console:         console,
+		formatter:       formatter,
+		writer:          writer,
+		importManager:   importManager,
+		imageScanner:    imageScanner,
+		packageManifest: packageManifest,
+		containerHelper: containerHelper,
 	}
 }
 
 type PackageResult struct {
 	Timestamp time.Time                                `json:"timestamp"`
 	Services  map[string]*project.ServicePackageResult `json:"services"`
+	Scans     map[string]*imagescan.Result             `json:"scans,omitempty"`
 }
 
 func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error) {
@@ -118,35 +151,50 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		targetServiceName = pa.args[0]
 	}
 
-	targetServiceName, err := getTargetServiceName(
-		ctx,
-		pa.projectManager,
-		pa.importManager,
-		pa.projectConfig,
-		string(project.ServiceEventPackage),
-		targetServiceName,
-		pa.flags.all,
-	)
-	if err != nil {
+	if err := validateServiceFilters(pa.flags.filters, targetServiceName, pa.flags.all); err != nil {
 		return nil, err
 	}
 
+	if len(pa.flags.filters) == 0 {
+		var err error
+		targetServiceName, err = getTargetServiceName(
+			ctx,
+			pa.projectManager,
+			pa.importManager,
+			pa.projectConfig,
+			string(project.ServiceEventPackage),
+			targetServiceName,
+			pa.flags.all,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if err := pa.projectManager.Initialize(ctx, pa.projectConfig); err != nil {
 		return nil, err
 	}
 
 	if err := pa.projectManager.EnsureAllTools(ctx, pa.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) &&
+			matchesServiceFilters(pa.flags.filters, svc.Name)
 	}); err != nil {
 		return nil, err
 	}
 
 	packageResults := map[string]*project.ServicePackageResult{}
+	scanResults := map[string]*imagescan.Result{}
 
 	serviceTable, err := pa.importManager.ServiceStable(ctx, pa.projectConfig)
 	if err != nil {
 		return nil, err
 	}
+
+	// the services that aren't selected with --filter aren't packaged, nor shown as skipped
+	serviceTable, err = filterServices(serviceTable, pa.flags.filters)
+	if err != nil {
+		return nil, err
+	}
 	serviceCount := len(serviceTable)
 	for index, svc := range serviceTable {
 		// TODO(ellismg): We need to figure out what packaging an containerized dotnet app means. For now, just skip it.
@@ -184,7 +232,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		}
 
 		stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
-		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+		pa.console.ShowSpinner(ctx, stepMessage, input.StepElapsed)
 
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
@@ -200,7 +248,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		go func() {
 			for packageProgress := range packageTask.Progress() {
 				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
-				pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+				pa.console.ShowSpinner(ctx, progressMessage, input.StepElapsed)
 			}
 			close(done)
 		}()
@@ -217,6 +265,29 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 		// report package output
 		pa.console.MessageUxItem(ctx, packageResult)
+
+		if pa.flags.scan || svc.Docker.Scan != nil {
+			scanResult, err := pa.scanImage(ctx, svc, packageResult)
+			if scanResult != nil {
+				scanResults[svc.Name] = scanResult
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// images that fail the scan aren't pushed
+		if pa.flags.push {
+			if err := pa.pushImage(ctx, svc, packageResult); err != nil {
+				return nil, err
+			}
+		}
+
+		// record the package, so it can be deployed with azd deploy --no-build. Images that fail the scan aren't recorded
+		if err := pa.packageManifest.Save(ctx, svc, packageResult); err != nil {
+			return nil, err
+		}
+
 		if index < serviceCount-1 {
 			pa.console.Message(ctx, "")
 		}
@@ -226,6 +297,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		packageResult := PackageResult{
 			Timestamp: time.Now(),
 			Services:  packageResults,
+			Scans:     scanResults,
 		}
 
 		if fmtErr := pa.formatter.Format(packageResult, pa.writer, nil); fmtErr != nil {
@@ -240,6 +312,60 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}, nil
 }
 
+// scanImage scans the image of the packaged service for vulnerabilities and shows the number found by severity. Fails
+// when the image has more high or critical vulnerabilities than the threshold of the service.
+func (pa *packageAction) scanImage(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	packageResult *project.ServicePackageResult,
+) (*imagescan.Result, error) {
+	stepMessage := fmt.Sprintf("Scanning image of service %s", svc.Name)
+	pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	scanResult, err := pa.imageScanner.Scan(ctx, svc, packageResult, pa.flags.scan)
+	if err == nil && scanResult == nil {
+		pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+		return nil, nil
+	}
+
+	if err == nil {
+		err = project.CheckImageScanThreshold(svc, scanResult)
+	}
+	pa.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+
+	if scanResult != nil {
+		pa.console.MessageUxItem(ctx, project.ImageScanSummary(scanResult))
+	}
+
+	return scanResult, err
+}
+
+// pushImage pushes the image of the packaged service to the registries of the service, so the recorded package refers to
+// the image in the registry.
+func (pa *packageAction) pushImage(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	packageResult *project.ServicePackageResult,
+) error {
+	stepMessage := fmt.Sprintf("Pushing image of service %s", svc.Name)
+	pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+	remoteImage, err := pa.containerHelper.PushPackage(ctx, svc, packageResult, func(progress project.ServiceProgress) {
+		pa.console.ShowSpinner(ctx, fmt.Sprintf("%s (%s)", stepMessage, progress.Message), input.Step)
+	})
+	if err == nil && remoteImage == "" {
+		pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+		return nil
+	}
+	pa.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+
+	if remoteImage != "" {
+		pa.console.Message(ctx, fmt.Sprintf("- Remote Image: %s", output.WithLinkFormat(remoteImage)))
+	}
+
+	return err
+}
+
 func getCmdPackageHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Packages application's code to be deployed to Azure. %s",
@@ -250,7 +376,13 @@ func getCmdPackageHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is packaged.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(fmt.Sprintf(
+			"When %s is set, only the services whose names match one of the patterns are packaged.",
+			output.WithHighLightFormat("--filter"))),
 		formatHelpNote("After the packaging is complete, the package locations are printed."),
+		formatHelpNote(fmt.Sprintf(
+			"When %s is set, or a service sets 'docker.scan', the container images are scanned for vulnerabilities.",
+			output.WithHighLightFormat("--scan"))),
 	})
 }
 
@@ -259,9 +391,15 @@ func getCmdPackageHelpFooter(*cobra.Command) string {
 		"Packages all services in the current project to Azure.": output.WithHighLightFormat("azd package --all"),
 		"Packages the service named 'api' to Azure.":             output.WithHighLightFormat("azd package api"),
 		"Packages the service named 'web' to Azure.":             output.WithHighLightFormat("azd package web"),
+		"Packages the services whose names start with 'api-' or 'web-'.": output.WithHighLightFormat(
+			"azd package --filter 'api-*' --filter 'web-*'",
+		),
 		"Packages all services to the specified output path.": output.WithHighLightFormat(
 			"azd package --output-path ./dist",
 		),
+		"Packages the service named 'api' and scans its container image for vulnerabilities.": output.WithHighLightFormat(
+			"azd package api --scan",
+		),
 		"Packages the service named 'api' to the specified output path.": output.WithHighLightFormat(
 			"azd package api --output-path ./dist/api.zip",
 		),
@@ -56,6 +56,20 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 		"The authentication type used between the pipeline provider and Azure for deployment (Only valid for GitHub provider). Valid values: federated, client-credentials.",
 	)
 	//nolint:lll
+	local.StringVar(
+		&pc.PipelineGitHubEnvironment,
+		"github-environment",
+		"",
+		"The GitHub Actions environment the workflow deploys from. Creates the environment, sets the pipeline variables and secrets in it and adds it to the workflow jobs that use azd (Only valid for GitHub provider).",
+	)
+	//nolint:lll
+	local.BoolVar(
+		&pc.PipelineGitHubEnvironmentFromEnv,
+		"github-environment-from-env",
+		false,
+		"Uses the name of the azd environment as the GitHub Actions environment the workflow deploys from (Only valid for GitHub provider).",
+	)
+	//nolint:lll
 	local.StringArrayVar(
 		&pc.PipelineRoleNames,
 		"principal-role",
@@ -65,7 +79,8 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 	// default provider is empty because it can be set from azure.yaml. By letting default here be empty, we know that
 	// there no customer input using --provider
 	local.StringVar(&pc.PipelineProvider, "provider", "",
-		"The pipeline provider to use (github for Github Actions and azdo for Azure Pipelines).")
+		"The pipeline provider to use (github for Github Actions, azdo for Azure Pipelines and bitbucket for Bitbucket "+
+			"Pipelines).")
 	pc.envFlag.Bind(local, global)
 	pc.global = global
 }
@@ -196,8 +211,8 @@ func getCmdPipelineHelpDescription(*cobra.Command) string {
 				"azd commands (e.g. " +
 					output.WithHighLightFormat("provision") + ", " +
 					output.WithHighLightFormat("deploy") + ") " +
-					"can be used within GitHub Actions and Azure Pipelines to test your code against real Azure resources " +
-					"and facilitate deployments."),
+					"can be used within GitHub Actions, Azure Pipelines and Bitbucket Pipelines to test your code against " +
+					"real Azure resources and facilitate deployments."),
 			formatHelpNote(
 				"After creating a pipeline definition file, running " +
 					output.WithHighLightFormat("pipeline config") +
@@ -219,8 +234,8 @@ func getCmdPipelineConfigHelpDescription(*cobra.Command) string {
 		"Configure your deployment pipeline to connect securely to Azure",
 		[]string{
 			formatHelpNote(
-				"Supports GitHub Actions and Azure Pipelines. To configure using a specific pipeline provider, " +
-					"provide a value for the '--provider' flag."),
+				"Supports GitHub Actions, Azure Pipelines and Bitbucket Pipelines. To configure using a specific " +
+					"pipeline provider, provide a value for the '--provider' flag."),
 			formatHelpNote(
 				output.WithHighLightFormat("pipeline config") +
 					" creates or uses a service principal on the Azure subscription to create a secure connection between" +
@@ -247,5 +262,10 @@ func getCmdPipelineConfigHelpFooter(c *cobra.Command) string {
 			output.WithWarningFormat("app-test"),
 			output.WithHighLightFormat("--provider azdo"),
 		),
+		"Configure a deployment pipeline for 'app-test' environment on Bitbucket Pipelines.": fmt.Sprintf("%s %s %s",
+			output.WithHighLightFormat("azd pipeline config -e"),
+			output.WithWarningFormat("app-test"),
+			output.WithHighLightFormat("--provider bitbucket"),
+		),
 	})
 }
@@ -253,7 +253,14 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	}
 
 	if previewMode {
-		p.console.MessageUxItem(ctx, deployResultToUx(deployPreviewResult))
+		if p.formatter.Kind() == output.JsonFormat {
+			if err := p.formatter.Format(
+				provisioning.NewProvisionPreviewResult(deployPreviewResult), p.writer, nil); err != nil {
+				return nil, fmt.Errorf("preview succeeded but the preview result could not be displayed: %w", err)
+			}
+		} else {
+			p.console.MessageUxItem(ctx, deployResultToUx(deployPreviewResult))
+		}
 
 		return &actions.ActionResult{
 			Message: &actions.ResultMessage{
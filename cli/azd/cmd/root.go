@@ -93,6 +93,13 @@ func NewRootCmd(ctx context.Context, staticHelp bool, middlewareChain []*actions
 					"no-prompt",
 					false,
 					"Accepts the default value instead of prompting, or it fails if there is no default.")
+			rootCmd.PersistentFlags().
+				BoolVarP(
+					&opts.AssumeYes,
+					"yes",
+					"y",
+					false,
+					"Answers yes to confirmations instead of prompting, even with --no-prompt. Other prompts are unaffected.")
 
 			// The telemetry system is responsible for reading these flags value and using it to configure the telemetry
 			// system, but we still need to add it to our flag set so that when we parse the command line with Cobra we
@@ -127,7 +134,7 @@ func NewRootCmd(ctx context.Context, staticHelp bool, middlewareChain []*actions
 		ActionResolver:   newVersionAction,
 		FlagsResolver:    newVersionFlags,
 		DisableTelemetry: true,
-		OutputFormats:    []output.Format{output.JsonFormat, output.NoneFormat},
+		OutputFormats:    []output.Format{output.JsonFormat, output.YamlFormat, output.NoneFormat},
 		DefaultFormat:    output.NoneFormat,
 		GroupingOptions: actions.CommandGroupOptions{
 			RootLevelHelp: actions.CmdGroupAbout,
@@ -138,7 +145,7 @@ func NewRootCmd(ctx context.Context, staticHelp bool, middlewareChain []*actions
 		Command:        newShowCmd(),
 		FlagsResolver:  newShowFlags,
 		ActionResolver: newShowAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat, output.NoneFormat},
 		DefaultFormat:  output.NoneFormat,
 		GroupingOptions: actions.CommandGroupOptions{
 			RootLevelHelp: actions.CmdGroupMonitor,
@@ -164,7 +171,7 @@ func NewRootCmd(ctx context.Context, staticHelp bool, middlewareChain []*actions
 		ActionResolver: newLogoutAction,
 	})
 
-	root.Add("init", &actions.ActionDescriptorOptions{
+	initDescriptor := root.Add("init", &actions.ActionDescriptorOptions{
 		Command:        newInitCmd(),
 		FlagsResolver:  newInitFlags,
 		ActionResolver: newInitAction,
@@ -176,6 +183,7 @@ func NewRootCmd(ctx context.Context, staticHelp bool, middlewareChain []*actions
 			RootLevelHelp: actions.CmdGroupConfig,
 		},
 	})
+	markOffline(initDescriptor)
 
 	root.
 		Add("restore", &actions.ActionDescriptorOptions{
@@ -259,7 +267,13 @@ func NewRootCmd(ctx context.Context, staticHelp bool, middlewareChain []*actions
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
-		UseMiddleware("hooks", middleware.NewHooksMiddleware)
+		UseMiddlewareWhen("hooks", middleware.NewHooksMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			if dryRun, _ := descriptor.Options.Command.Flags().GetBool("dry-run"); dryRun {
+				log.Println("Skipping deploy hooks due to dry-run flag.")
+				return false
+			}
+			return true
+		})
 
 	root.
 		Add("up", &actions.ActionDescriptorOptions{
@@ -30,10 +30,17 @@ import (
 type showFlags struct {
 	global *internal.GlobalCommandOptions
 	envFlag
+	query string
 }
 
 func (s *showFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
 	s.envFlag.Bind(local, global)
+	local.StringVar(
+		&s.query,
+		"query",
+		"",
+		"Lists the resources of the environment that match a KQL filter, for example \"type =~ 'Microsoft.Web/sites'\".",
+	)
 	s.global = global
 }
 
@@ -65,6 +72,7 @@ type showAction struct {
 	flags                *showFlags
 	lazyServiceManager   *lazy.Lazy[project.ServiceManager]
 	lazyResourceManager  *lazy.Lazy[project.ResourceManager]
+	resourceGraphQuery   *infra.ResourceGraphQuery
 }
 
 func newShowAction(
@@ -80,6 +88,7 @@ func newShowAction(
 	flags *showFlags,
 	lazyServiceManager *lazy.Lazy[project.ServiceManager],
 	lazyResourceManager *lazy.Lazy[project.ResourceManager],
+	resourceGraphQuery *infra.ResourceGraphQuery,
 ) actions.Action {
 	return &showAction{
 		projectConfig:        projectConfig,
@@ -94,6 +103,7 @@ func newShowAction(
 		flags:                flags,
 		lazyServiceManager:   lazyServiceManager,
 		lazyResourceManager:  lazyResourceManager,
+		resourceGraphQuery:   resourceGraphQuery,
 	}
 }
 
@@ -187,7 +197,16 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 	}
 
-	if s.formatter.Kind() == output.JsonFormat {
+	if s.flags.query != "" {
+		resources, err := s.queryResources(ctx, environmentName, subId, rgName)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Resources = resources
+	}
+
+	if s.formatter.Kind() == output.JsonFormat || s.formatter.Kind() == output.YamlFormat {
 		return nil, s.formatter.Format(res, s.writer, nil)
 	}
 
@@ -222,9 +241,76 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		AzurePortalLink: azurePortalLink(subId, rgName),
 	})
 
+	if s.flags.query != "" {
+		s.displayResources(ctx, res.Resources)
+	}
+
 	return nil, nil
 }
 
+// queryResources returns the resources of the resource group of the environment that match the query of the flags.
+func (s *showAction) queryResources(
+	ctx context.Context, environmentName string, subId string, rgName string) ([]contracts.ShowResource, error) {
+	if subId == "" || rgName == "" {
+		return nil, &azcli.ErrorWithSuggestion{
+			Err: fmt.Errorf("the resources of environment '%s' can't be queried because they aren't provisioned",
+				environmentName),
+			Suggestion: fmt.Sprintf("Suggestion: provision the resources of the environment with %s.",
+				output.WithHighLightFormat("azd provision")),
+		}
+	}
+
+	resources, err := s.resourceGraphQuery.EnvironmentResources(ctx, subId, rgName, s.flags.query)
+	if errors.Is(err, infra.ErrInvalidResourceFilter) {
+		return nil, &azcli.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: "Suggestion: --query accepts a single KQL predicate, for example " +
+				"\"type =~ 'Microsoft.Web/sites' and tags['azd-service-name'] == 'api'\".",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	showResources := make([]contracts.ShowResource, 0, len(resources))
+	for _, resource := range resources {
+		showResources = append(showResources, contracts.ShowResource{
+			Id:        resource.Id,
+			Name:      resource.Name,
+			Type:      resource.Type,
+			Location:  resource.Location,
+			Tags:      resource.Tags,
+			PortalUrl: resource.PortalUrl(),
+		})
+	}
+
+	return showResources, nil
+}
+
+// displayResources lists the resources returned by the query in a table, followed by their links to the Azure Portal,
+// which are listed on their own lines so they aren't truncated to fit the table into the console.
+func (s *showAction) displayResources(ctx context.Context, resources []contracts.ShowResource) {
+	if len(resources) == 0 {
+		s.console.Message(ctx, "\nNo resources of the environment match the query.")
+		return
+	}
+
+	table := &ux.Table{
+		Headers: []string{"Name", "Type", "Location"},
+		Rows:    make([][]string, 0, len(resources)),
+	}
+	for _, resource := range resources {
+		table.Rows = append(table.Rows, []string{resource.Name, resource.Type, resource.Location})
+	}
+
+	s.console.Message(ctx, "")
+	s.console.MessageUxItem(ctx, table)
+
+	s.console.Message(ctx, "\nView the resources in the Azure Portal:")
+	for _, resource := range resources {
+		s.console.Message(ctx, fmt.Sprintf("  %s: %s", resource.Name, output.WithLinkFormat(resource.PortalUrl)))
+	}
+}
+
 func (s *showAction) serviceEndpoint(
 	ctx context.Context, subId string, serviceConfig *project.ServiceConfig, env *environment.Environment) string {
 	resourceManager, err := s.lazyResourceManager.GetValue()
@@ -275,6 +361,14 @@ func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowTy
 		return contracts.ShowTypeNode
 	case project.ServiceLanguageJava:
 		return contracts.ShowTypeJava
+	case project.ServiceLanguageGo:
+		return contracts.ShowTypeGo
+	case project.ServiceLanguageRust:
+		return contracts.ShowTypeRust
+	case project.ServiceLanguagePhp:
+		return contracts.ShowTypePhp
+	case project.ServiceLanguageRuby:
+		return contracts.ShowTypeRuby
 	default:
 		panic(fmt.Sprintf("unknown language %s", language))
 	}
@@ -35,19 +35,21 @@ func templatesActions(root *actions.ActionDescriptor) *actions.ActionDescriptor
 		Command:        newTemplateListCmd(),
 		ActionResolver: newTemplateListAction,
 		FlagsResolver:  newTemplateListFlags,
-		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat, output.TableFormat},
 		DefaultFormat:  output.TableFormat,
 	})
 
 	group.Add("show", &actions.ActionDescriptorOptions{
 		Command:        newTemplateShowCmd(),
 		ActionResolver: newTemplateShowAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat, output.NoneFormat},
 		DefaultFormat:  output.NoneFormat,
 	})
 
 	_ = templateSourceActions(group)
 
+	markOffline(group)
+
 	return group
 }
 
@@ -218,7 +220,7 @@ func templateSourceActions(root *actions.ActionDescriptor) *actions.ActionDescri
 	group.Add("list", &actions.ActionDescriptorOptions{
 		Command:        newTemplateSourceListCmd(),
 		ActionResolver: newTemplateSourceListAction,
-		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		OutputFormats:  []output.Format{output.JsonFormat, output.YamlFormat, output.TableFormat},
 		DefaultFormat:  output.TableFormat,
 	})
 
@@ -9,10 +9,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -20,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/cli/browser"
 	"github.com/spf13/pflag"
 )
@@ -27,6 +31,29 @@ import (
 // CmdAnnotations on a command
 type CmdAnnotations map[string]string
 
+// offlineAnnotation is the annotation of the commands that don't need Azure, so they run without a login. The credential
+// of the current user is created when these commands first request a token, rather than when their dependencies are
+// resolved, which fails when the user isn't logged in.
+const offlineAnnotation = "azd.offline"
+
+// IsOffline returns true when the command is annotated with offlineAnnotation.
+func (a CmdAnnotations) IsOffline() bool {
+	return a[offlineAnnotation] == "true"
+}
+
+// markOffline annotates the command of descriptor, and the commands of its children, with offlineAnnotation.
+func markOffline(descriptor *actions.ActionDescriptor) {
+	cmd := descriptor.Options.Command
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+
+	cmd.Annotations[offlineAnnotation] = "true"
+	for _, child := range descriptor.Children() {
+		markOffline(child)
+	}
+}
+
 type Asker func(p survey.Prompt, response interface{}) error
 
 const environmentNameFlag string = "environment"
@@ -137,6 +164,78 @@ func getTargetServiceName(
 	return targetServiceName, nil
 }
 
+// validateServiceFilters checks that services selected with --filter aren't also selected with <service> or --all.
+func validateServiceFilters(filters []string, targetServiceName string, allFlagValue bool) error {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	if targetServiceName != "" {
+		return fmt.Errorf("cannot specify both --filter and <service>")
+	}
+
+	if allFlagValue {
+		return fmt.Errorf("cannot specify both --filter and --all")
+	}
+
+	for _, filter := range filters {
+		if _, err := path.Match(filter, ""); err != nil {
+			return fmt.Errorf("invalid --filter '%s': %w", filter, err)
+		}
+	}
+
+	return nil
+}
+
+// matchesServiceFilters returns whether the service name matches any of the glob patterns given with --filter, as
+// specified by [path.Match]. Every service matches when there are no patterns.
+func matchesServiceFilters(filters []string, serviceName string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	for _, filter := range filters {
+		if matched, err := path.Match(filter, serviceName); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterServices returns the services that match the glob patterns given with --filter, in the same order. Fails when a
+// pattern doesn't match any of the services, listing the names of the services.
+func filterServices(services []*project.ServiceConfig, filters []string) ([]*project.ServiceConfig, error) {
+	if len(filters) == 0 {
+		return services, nil
+	}
+
+	serviceNames := make([]string, 0, len(services))
+	for _, svc := range services {
+		serviceNames = append(serviceNames, svc.Name)
+	}
+
+	for _, filter := range filters {
+		if !slices.ContainsFunc(serviceNames, func(name string) bool { return matchesServiceFilters([]string{filter}, name) }) {
+			return nil, &azcli.ErrorWithSuggestion{
+				Err: fmt.Errorf("no services matched --filter '%s'", filter),
+				Suggestion: fmt.Sprintf(
+					"Check the pattern given with --filter. The services of the project are: %s",
+					strings.Join(serviceNames, ", ")),
+			}
+		}
+	}
+
+	filtered := []*project.ServiceConfig{}
+	for _, svc := range services {
+		if matchesServiceFilters(filters, svc.Name) {
+			filtered = append(filtered, svc)
+		}
+	}
+
+	return filtered, nil
+}
+
 // Calculate the total time since t, excluding user interaction time.
 func since(t time.Time) time.Duration {
 	userInteractTime := tracing.InteractTimeMs.Load()
@@ -155,6 +254,15 @@ func openWithDefaultBrowser(ctx context.Context, console input.Console, url stri
 		return
 	}
 
+	if err := launchBrowser(ctx, url); err != nil {
+		log.Printf("warning: failed to use manual launch: %s\n", err.Error())
+		console.Message(ctx, fmt.Sprintf("Azd was unable to open the next url. Please try it manually: %s", url))
+	}
+}
+
+// launchBrowser opens the url with the browser configured by $BROWSER, the default browser or, on WSL, the browser of
+// Windows. It returns an error when none of them can be launched.
+func launchBrowser(ctx context.Context, url string) error {
 	cmdRunner := azdExec.NewCommandRunner(nil)
 
 	// In Codespaces and devcontainers a $BROWSER environment variable is
@@ -169,7 +277,7 @@ func openWithDefaultBrowser(ctx context.Context, console input.Console, url stri
 			Args: []string{url},
 		})
 		if err == nil {
-			return
+			return nil
 		}
 		log.Printf(
 			"warning: failed to open browser configured by $BROWSER: %s\nTrying with default browser.\n",
@@ -179,7 +287,7 @@ func openWithDefaultBrowser(ctx context.Context, console input.Console, url stri
 
 	err := browser.OpenURL(url)
 	if err == nil {
-		return
+		return nil
 	}
 
 	log.Printf(
@@ -198,7 +306,7 @@ func openWithDefaultBrowser(ctx context.Context, console input.Console, url stri
 		},
 	})
 	if err == nil {
-		return
+		return nil
 	}
 	log.Printf(
 		"warning: failed to open browser with cmd: %s\nTrying powershell.", err.Error(),
@@ -210,12 +318,7 @@ func openWithDefaultBrowser(ctx context.Context, console input.Console, url stri
 			"-NoProfile", "-Command", "Start-Process", fmt.Sprintf("\"%s\"", url),
 		},
 	})
-	if err == nil {
-		return
-	}
-
-	log.Printf("warning: failed to use manual launch: %s\n", err.Error())
-	console.Message(ctx, fmt.Sprintf("Azd was unable to open the next url. Please try it manually: %s", url))
+	return err
 }
 
 const cReferenceDocumentationUrl = "https://learn.microsoft.com/azure/developer/azure-developer-cli/reference#"
@@ -11,6 +11,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
 	"github.com/stretchr/testify/require"
@@ -85,3 +86,43 @@ func Test_getResourceGroupFollowUpPreview(t *testing.T) {
 
 	require.Contains(t, followUp, "You can view the current resources under the resource group Name in Azure Portal:")
 }
+
+func Test_filterServices(t *testing.T) {
+	services := []*project.ServiceConfig{{Name: "web"}, {Name: "api-orders"}, {Name: "api-users"}, {Name: "worker"}}
+	names := func(services []*project.ServiceConfig) []string {
+		result := []string{}
+		for _, svc := range services {
+			result = append(result, svc.Name)
+		}
+		return result
+	}
+
+	t.Run("NoFilters", func(t *testing.T) {
+		filtered, err := filterServices(services, nil)
+		require.NoError(t, err)
+		require.Equal(t, services, filtered)
+	})
+
+	t.Run("KeepsOrder", func(t *testing.T) {
+		filtered, err := filterServices(services, []string{"worker", "api-*"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api-orders", "api-users", "worker"}, names(filtered))
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, err := filterServices(services, []string{"api-*", "frontend"})
+		require.ErrorContains(t, err, "no services matched --filter 'frontend'")
+
+		var suggestionErr *azcli.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.Contains(t, suggestionErr.Suggestion, "web, api-orders, api-users, worker")
+	})
+}
+
+func Test_validateServiceFilters(t *testing.T) {
+	require.NoError(t, validateServiceFilters(nil, "web", true))
+	require.NoError(t, validateServiceFilters([]string{"api-*"}, "", false))
+	require.ErrorContains(t, validateServiceFilters([]string{"api-*"}, "web", false), "--filter and <service>")
+	require.ErrorContains(t, validateServiceFilters([]string{"api-*"}, "", true), "--filter and --all")
+	require.ErrorContains(t, validateServiceFilters([]string{"api-["}, "", false), "invalid --filter 'api-['")
+}
@@ -14,6 +14,8 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/bmatcuk/doublestar/v4"
 )
 
@@ -26,6 +28,10 @@ const (
 	JavaScript    Language = "js"
 	TypeScript    Language = "ts"
 	Python        Language = "python"
+	Go            Language = "go"
+	Rust          Language = "rust"
+	Php           Language = "php"
+	Ruby          Language = "ruby"
 )
 
 func (pt Language) Display() string {
@@ -42,6 +48,14 @@ func (pt Language) Display() string {
 		return "TypeScript"
 	case Python:
 		return "Python"
+	case Go:
+		return "Go"
+	case Rust:
+		return "Rust"
+	case Php:
+		return "PHP"
+	case Ruby:
+		return "Ruby"
 	}
 
 	return ""
@@ -58,6 +72,16 @@ const (
 	PyFlask   Dependency = "flask"
 	PyDjango  Dependency = "django"
 	PyFastApi Dependency = "fastapi"
+
+	GoGin  Dependency = "gin"
+	GoEcho Dependency = "echo"
+
+	RustActix Dependency = "actix-web"
+	RustAxum  Dependency = "axum"
+
+	PhpLaravel Dependency = "laravel"
+
+	RubyRails Dependency = "rails"
 )
 
 var WebUIFrameworks = map[Dependency]struct{}{
@@ -71,6 +95,14 @@ func (f Dependency) Language() Language {
 	switch f {
 	case JsReact, JsAngular, JsVue, JsJQuery:
 		return JavaScript
+	case GoGin, GoEcho:
+		return Go
+	case RustActix, RustAxum:
+		return Rust
+	case PhpLaravel:
+		return Php
+	case RubyRails:
+		return Ruby
 	}
 
 	return ""
@@ -86,6 +118,18 @@ func (f Dependency) Display() string {
 		return "Vue.js"
 	case JsJQuery:
 		return "JQuery"
+	case GoGin:
+		return "Gin"
+	case GoEcho:
+		return "Echo"
+	case RustActix:
+		return "Actix Web"
+	case RustAxum:
+		return "Axum"
+	case PhpLaravel:
+		return "Laravel"
+	case RubyRails:
+		return "Ruby on Rails"
 	}
 
 	return ""
@@ -128,6 +172,23 @@ func (db DatabaseDep) Display() string {
 	return ""
 }
 
+// A type of messaging service that is inferred through heuristics while scanning project information.
+type MessagingDep string
+
+const (
+	// Messaging dependencies. Service Bus is also inferred from AMQP 1.0 client libraries, the protocol it supports.
+	MessagingServiceBus MessagingDep = "servicebus"
+)
+
+func (m MessagingDep) Display() string {
+	switch m {
+	case MessagingServiceBus:
+		return "Azure Service Bus"
+	}
+
+	return ""
+}
+
 type Project struct {
 	// The language associated with the project.
 	Language Language
@@ -138,6 +199,9 @@ type Project struct {
 	// Experimental: Database dependencies inferred through heuristics while scanning dependencies in the project.
 	DatabaseDeps []DatabaseDep
 
+	// Experimental: Messaging dependencies inferred through heuristics while scanning dependencies in the project.
+	MessagingDeps []MessagingDep
+
 	// The path to the project directory.
 	Path string
 
@@ -146,6 +210,14 @@ type Project struct {
 
 	// If true, the project uses Docker for packaging. This is inferred through the presence of a Dockerfile.
 	Docker *Docker
+
+	// The package manager of a JavaScript or TypeScript project, inferred through the presence of a lockfile.
+	// Nil if no lockfile is present.
+	PackageManager *PackageManager
+
+	// The package manager of a Python project, inferred through the presence of a lockfile, a Pipfile or the tool
+	// tables of pyproject.toml. Nil if the dependencies are installed with pip from requirements.txt.
+	PythonPackageManager *PythonPackageManager
 }
 
 func (p *Project) HasWebUIFramework() bool {
@@ -160,6 +232,28 @@ func (p *Project) HasWebUIFramework() bool {
 
 type Docker struct {
 	Path string
+
+	// The port exposed by the final stage of the Dockerfile. Zero when the port can't be determined, for example when
+	// multiple ports are exposed or the port is set by a variable.
+	Port int
+}
+
+type PackageManager struct {
+	Kind npm.PackageManagerKind
+
+	// The path to the lockfile that the package manager is inferred from. When multiple lockfiles are present,
+	// this is the most recently modified.
+	LockFilePath string
+}
+
+type PythonPackageManager struct {
+	Kind python.PackageManagerKind
+
+	// The path to the file that declares the dependencies, for example pyproject.toml.
+	ManifestPath string
+
+	// The path to the lockfile that the package manager is inferred from. Empty when no lockfile is present.
+	LockFilePath string
 }
 
 type projectDetector interface {
@@ -177,6 +271,12 @@ var allDetectors = []projectDetector{
 	},
 	&dotNetDetector{},
 	&pythonDetector{},
+	&goDetector{},
+	&rustDetector{},
+	// PHP apps commonly have a package.json for their frontend assets, which shouldn't be detected as a JavaScript app
+	&phpDetector{},
+	// the same goes for Rails apps that bundle their assets with jsbundling-rails or cssbundling-rails
+	&rubyDetector{},
 	&javaScriptDetector{},
 }
 
@@ -209,17 +309,35 @@ func detectUnder(ctx context.Context, root string, config detectConfig) ([]Proje
 		// Normalize all paths to use forward slash '/' for glob matching
 		relativePathForMatch := filepath.ToSlash(relativePath)
 
+		excluded := false
 		for _, p := range config.ExcludePatterns {
 			match, err := doublestar.Match(p, relativePathForMatch)
 			if err != nil {
 				return err
 			}
 			if match {
-				return filepath.SkipDir
+				excluded = true
+				break
+			}
+		}
+
+		if config.ignore != nil {
+			excluded, err = config.ignore.apply(path, excluded)
+			if err != nil {
+				return err
 			}
 		}
 
-		project, err := detectAny(ctx, config.detectors, path, entries)
+		if excluded {
+			return filepath.SkipDir
+		}
+
+		var project *Project
+		if config.cache != nil {
+			project, err = config.cache.detect(ctx, config.detectors, path, entries)
+		} else {
+			project, err = detectAny(ctx, config.detectors, path, entries)
+		}
 		if err != nil {
 			return err
 		}
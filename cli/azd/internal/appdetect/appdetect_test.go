@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/stretchr/testify/require"
 )
@@ -16,12 +17,26 @@ import (
 //go:embed testdata/*
 var testDataFs embed.FS
 
+// A go.mod file placed in testdata would make the directory a separate module, which can't be embedded.
+const testGoMod = `module example.com/goapp
+
+go 1.21
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/lib/pq v1.10.9
+)
+`
+
 // Verify standard detection for all languages and dependencies.
 func TestDetect(t *testing.T) {
 	dir := t.TempDir()
 	err := copyTestDataDir(t, "**", dir)
 	require.NoError(t, err)
 
+	err = os.WriteFile(filepath.Join(dir, "go", "go.mod"), []byte(testGoMod), osutil.PermissionFile)
+	require.NoError(t, err)
+
 	tests := []struct {
 		name    string
 		options []DetectOption
@@ -36,6 +51,17 @@ func TestDetect(t *testing.T) {
 					Path:          "dotnet",
 					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, program.cs",
 				},
+				{
+					Language:      Go,
+					Path:          "go",
+					DetectionRule: "Inferred by presence of: go.mod",
+					Dependencies: []Dependency{
+						GoGin,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbPostgres,
+					},
+				},
 				{
 					Language:      Java,
 					Path:          "java",
@@ -64,6 +90,18 @@ func TestDetect(t *testing.T) {
 						DbSqlServer,
 					},
 				},
+				{
+					Language:      Php,
+					Path:          "php",
+					DetectionRule: "Inferred by presence of: composer.json, artisan",
+					Dependencies: []Dependency{
+						PhpLaravel,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbPostgres,
+						DbRedis,
+					},
+				},
 				{
 					Language:      Python,
 					Path:          "python",
@@ -84,6 +122,33 @@ func TestDetect(t *testing.T) {
 						DbPostgres,
 						DbRedis,
 					},
+					MessagingDeps: []MessagingDep{
+						MessagingServiceBus,
+					},
+				},
+				{
+					Language:      Ruby,
+					Path:          "ruby",
+					DetectionRule: "Inferred by presence of: Gemfile, config.ru, bin/rails",
+					Dependencies: []Dependency{
+						RubyRails,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbPostgres,
+						DbRedis,
+					},
+				},
+				{
+					Language:      Rust,
+					Path:          "rust",
+					DetectionRule: "Inferred by presence of: Cargo.toml",
+					Dependencies: []Dependency{
+						RustAxum,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbPostgres,
+						DbRedis,
+					},
 				},
 				{
 					Language:      TypeScript,
@@ -118,6 +183,10 @@ func TestDetect(t *testing.T) {
 			[]DetectOption{
 				WithoutJavaScript(),
 				WithoutPython(),
+				WithoutGo(),
+				WithoutRust(),
+				WithoutPhp(),
+				WithoutRuby(),
 			},
 			[]Project{
 				{
@@ -139,6 +208,10 @@ func TestDetect(t *testing.T) {
 					"**/*-full",
 					"**/javascript",
 					"typescript",
+					"go",
+					"rust",
+					"php",
+					"ruby",
 				}, false),
 			},
 			[]Project{
@@ -159,6 +232,41 @@ func TestDetect(t *testing.T) {
 				},
 			},
 		},
+		{
+			"IgnorePatterns",
+			[]DetectOption{
+				WithIgnorePatterns(dir, []string{
+					"# comment",
+					"",
+					"*-full/",
+					"/javascript",
+					"typescript",
+					"go",
+					"rust",
+					"php",
+					"ruby",
+					"java",
+					"!java",
+				}),
+			},
+			[]Project{
+				{
+					Language:      DotNet,
+					Path:          "dotnet",
+					DetectionRule: "Inferred by presence of: dotnettestapp.csproj, program.cs",
+				},
+				{
+					Language:      Java,
+					Path:          "java",
+					DetectionRule: "Inferred by presence of: pom.xml",
+				},
+				{
+					Language:      Python,
+					Path:          "python",
+					DetectionRule: "Inferred by presence of: requirements.txt",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -254,3 +362,345 @@ func copyTestDataDir(t *testing.T, glob string, dst string) error {
 		return os.WriteFile(targetPath, contents, osutil.PermissionFile)
 	})
 }
+
+func TestGoMainPackage(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/go/**", dir)
+	require.NoError(t, err)
+
+	moduleDir := filepath.Join(dir, "go")
+	err = os.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte(testGoMod), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	mainPackage, err := GoMainPackage(moduleDir)
+	require.NoError(t, err)
+	require.Equal(t, "cmd/server", mainPackage)
+
+	err = os.WriteFile(filepath.Join(moduleDir, "main.go"), []byte("// root\npackage main\n"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	mainPackage, err = GoMainPackage(moduleDir)
+	require.NoError(t, err)
+	require.Equal(t, ".", mainPackage)
+
+	version, err := GoVersion(moduleDir)
+	require.NoError(t, err)
+	require.Equal(t, "1.21", version)
+}
+
+func TestDetectJsPackageManager(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	projects, err := Detect(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Nil(t, projects[0].PackageManager)
+
+	err = os.WriteFile(filepath.Join(dir, "yarn.lock"), nil, osutil.PermissionFile)
+	require.NoError(t, err)
+
+	projects, err = Detect(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, &PackageManager{
+		Kind:         npm.PackageManagerYarn,
+		LockFilePath: filepath.Join(dir, "yarn.lock"),
+	}, projects[0].PackageManager)
+}
+
+func TestDatabaseFromConnectionString(t *testing.T) {
+	tests := []struct {
+		connectionString string
+		want             DatabaseDep
+		wantOk           bool
+	}{
+		{"Server=tcp:myserver.database.windows.net,1433;Initial Catalog=appdb;", DbSqlServer, true},
+		{"Server=(localdb)\\mssqllocaldb;Database=appdb;Trusted_Connection=True;", DbSqlServer, true},
+		{"Server=myserver.mysql.database.azure.com;Database=appdb;Uid=admin;", DbMySql, true},
+		{"Server=localhost;Port=3306;Database=appdb;", DbMySql, true},
+		{"mysql://admin@localhost/appdb", DbMySql, true},
+		{"Host=localhost;Database=appdb;", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.connectionString, func(t *testing.T) {
+			db, ok := databaseFromConnectionString(tt.connectionString)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, db)
+		})
+	}
+}
+
+func TestRustBinaryName(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/rust/**", dir)
+	require.NoError(t, err)
+
+	projectDir := filepath.Join(dir, "rust")
+	binaryName, err := RustBinaryName(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "server", binaryName)
+
+	manifest := "[package]\nname = \"rust-api\" # the package\n\n[dependencies]\nactix-web = \"4\"\n"
+	err = os.WriteFile(filepath.Join(projectDir, "Cargo.toml"), []byte(manifest), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	binaryName, err = RustBinaryName(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "rust-api", binaryName)
+}
+
+func TestDockerfileExposedPort(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		want       int
+	}{
+		{
+			"Single",
+			"FROM nginx\nEXPOSE 80\n",
+			80,
+		},
+		{
+			"Protocol",
+			"FROM node:20\nexpose 3000/tcp\nCMD [\"node\", \"index.js\"]\n",
+			3000,
+		},
+		{
+			"MultiStage",
+			"FROM golang:1.21 AS build\nEXPOSE 9000\nRUN go build -o /app\n\nFROM gcr.io/distroless/static\n" +
+				"# EXPOSE 1234\nEXPOSE 8080\nENTRYPOINT [\"/app\"]\n",
+			8080,
+		},
+		{
+			"SamePortTwice",
+			"FROM nginx\nEXPOSE 80\nEXPOSE 80/tcp\n",
+			80,
+		},
+		{
+			"LineContinuation",
+			"FROM nginx\nEXPOSE \\\n  8080\n",
+			8080,
+		},
+		{
+			"MultiplePorts",
+			"FROM nginx\nEXPOSE 80 443\n",
+			0,
+		},
+		{
+			"MultipleExposeLines",
+			"FROM nginx\nEXPOSE 80\nEXPOSE 443\n",
+			0,
+		},
+		{
+			"Variable",
+			"FROM node:20\nARG PORT=3000\nEXPOSE $PORT\n",
+			0,
+		},
+		{
+			"NoExpose",
+			"FROM nginx\n",
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			err := os.WriteFile(path, []byte(tt.dockerfile), osutil.PermissionFile)
+			require.NoError(t, err)
+
+			port, err := dockerfileExposedPort(path)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, port)
+		})
+	}
+}
+
+func TestPhpDocumentRoot(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/php/**", dir)
+	require.NoError(t, err)
+
+	projectDir := filepath.Join(dir, "php")
+	documentRoot, err := PhpDocumentRoot(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "public", documentRoot)
+
+	err = os.RemoveAll(filepath.Join(projectDir, "public"))
+	require.NoError(t, err)
+
+	documentRoot, err = PhpDocumentRoot(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "", documentRoot)
+}
+
+func TestJavaVersion(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/java/**", dir)
+	require.NoError(t, err)
+
+	projectDir := filepath.Join(dir, "java")
+	javaVersion, err := JavaVersion(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "", javaVersion)
+
+	tests := []struct {
+		properties string
+		want       string
+	}{
+		{"<java.version>21</java.version>", "21"},
+		{"<maven.compiler.release>17</maven.compiler.release>", "17"},
+		{"<maven.compiler.target>1.8</maven.compiler.target>", "8"},
+		{"<java.version>${jdk.version}</java.version>", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.properties, func(t *testing.T) {
+			pom := "<project><properties>" + tt.properties + "</properties></project>"
+			err = os.WriteFile(filepath.Join(projectDir, "pom.xml"), []byte(pom), osutil.PermissionFile)
+			require.NoError(t, err)
+
+			javaVersion, err := JavaVersion(projectDir)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, javaVersion)
+		})
+	}
+}
+
+func TestRubyVersion(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/ruby/**", dir)
+	require.NoError(t, err)
+
+	projectDir := filepath.Join(dir, "ruby")
+	version, err := RubyVersion(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "3.3.0", version)
+
+	err = os.WriteFile(filepath.Join(projectDir, ".ruby-version"), []byte("ruby-3.2.2\n"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	version, err = RubyVersion(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, "3.2.2", version)
+}
+
+func TestRailsAssets(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/ruby/**", dir)
+	require.NoError(t, err)
+
+	projectDir := filepath.Join(dir, "ruby")
+	precompile, node, err := RailsAssets(projectDir)
+	require.NoError(t, err)
+	require.True(t, precompile)
+	require.True(t, node)
+
+	err = os.WriteFile(filepath.Join(projectDir, "Gemfile"), []byte("gem \"rails\"\n"), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	precompile, node, err = RailsAssets(projectDir)
+	require.NoError(t, err)
+	require.False(t, precompile)
+	require.False(t, node)
+}
+
+func TestDetectRubyRequiresWebApp(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    bool
+		wantDep []Dependency
+	}{
+		{"GemfileOnly", map[string]string{"Gemfile": "gem \"rubocop\"\n"}, false, nil},
+		{"GemfileWithSinatra", map[string]string{"Gemfile": "gem \"sinatra\"\n"}, true, nil},
+		{"ConfigRu", map[string]string{"Gemfile": "gem \"rubocop\"\n", "config.ru": "run App\n"}, true, nil},
+		{"BinRails", map[string]string{"bin/rails": ""}, true, []Dependency{RubyRails}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, contents := range tt.files {
+				path := filepath.Join(dir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+				require.NoError(t, os.WriteFile(path, []byte(contents), osutil.PermissionFile))
+			}
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+
+			project, err := (&rubyDetector{}).DetectProject(context.Background(), dir, entries)
+			require.NoError(t, err)
+			if !tt.want {
+				require.Nil(t, project)
+				return
+			}
+
+			require.NotNil(t, project)
+			require.Equal(t, tt.wantDep, project.Dependencies)
+		})
+	}
+}
+
+func TestDetectProjectFileEncoding(t *testing.T) {
+	// "Café" encoded as ISO-8859-1, which isn't valid UTF-8
+	latin1 := "Caf\xe9"
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		language Language
+		wantDeps []DatabaseDep
+	}{
+		{
+			"PomLatin1",
+			map[string]string{
+				"pom.xml": `<?xml version="1.0" encoding="ISO-8859-1"?>` +
+					"<project><description>" + latin1 + "</description><dependencies>" +
+					"<dependency><artifactId>mysql-connector-j</artifactId></dependency>" +
+					"</dependencies></project>",
+			},
+			Java,
+			[]DatabaseDep{DbMySql},
+		},
+		{
+			"PomMalformed",
+			map[string]string{"pom.xml": "<project><dependencies></project>"},
+			Java,
+			nil,
+		},
+		{
+			"CsprojLatin1",
+			map[string]string{
+				"Program.cs": "",
+				"app.csproj": `<?xml version="1.0" encoding="ISO-8859-1"?>` +
+					"<Project><PropertyGroup><Company>" + latin1 + "</Company></PropertyGroup><ItemGroup>" +
+					`<PackageReference Include="Microsoft.Data.SqlClient" />` +
+					"</ItemGroup></Project>",
+			},
+			DotNet,
+			[]DatabaseDep{DbSqlServer},
+		},
+		{
+			"CsprojMalformed",
+			map[string]string{"Program.cs": "", "app.csproj": "<Project><ItemGroup></Project>"},
+			DotNet,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, contents := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), osutil.PermissionFile))
+			}
+
+			// unparsable project files don't fail detection
+			projects, err := Detect(context.Background(), dir)
+			require.NoError(t, err)
+			require.Len(t, projects, 1)
+			require.Equal(t, tt.language, projects[0].Language)
+			require.Equal(t, tt.wantDeps, projects[0].DatabaseDeps)
+		})
+	}
+}
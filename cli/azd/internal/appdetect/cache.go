@@ -0,0 +1,165 @@
+package appdetect
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// Cache caches the projects detected in each directory, so that detecting a directory again reuses the previous result
+// while the directory is unchanged. A directory is unchanged when the names, sizes and modification times of its entries
+// are the same as when it was last detected.
+//
+// Detectors may read files nested in subdirectories, for example to find the main package of a Go module. Changes to
+// these files aren't observed when the entries of the directory are unchanged, call Reset to detect all directories again.
+// A Cache is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	saved   time.Duration
+}
+
+type cacheEntry struct {
+	// The fingerprint of the entries of the directory when it was detected
+	fingerprint string
+	// The detected project, nil if no project was detected
+	project *Project
+	// The time spent detecting the directory
+	elapsed time.Duration
+}
+
+// NewCache creates an empty Cache.
+func NewCache() *Cache {
+	return &Cache{
+		entries: map[string]cacheEntry{},
+	}
+}
+
+// Reset removes all cached results, so that every directory is detected again.
+func (c *Cache) Reset() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]cacheEntry{}
+}
+
+// Saved returns the total time spent detecting the directories whose cached results were reused.
+func (c *Cache) Saved() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.saved
+}
+
+// detect detects the project in the directory at path, reusing the cached result when the directory is unchanged.
+func (c *Cache) detect(
+	ctx context.Context,
+	detectors []projectDetector,
+	path string,
+	entries []fs.DirEntry) (*Project, error) {
+	fingerprint, err := directoryFingerprint(entries)
+	if err != nil {
+		log.Printf("computing fingerprint of %s, skipping cache: %v", path, err)
+		return detectAny(ctx, detectors, path, entries)
+	}
+
+	key := cacheKey(detectors, path)
+
+	c.mu.Lock()
+	if cached, has := c.entries[key]; has && cached.fingerprint == fingerprint {
+		c.saved += cached.elapsed
+		c.mu.Unlock()
+
+		log.Printf("Reusing detection result for directory: %s", path)
+		return cached.project.clone(), nil
+	}
+	c.mu.Unlock()
+
+	start := time.Now()
+	project, err := detectAny(ctx, detectors, path, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{
+		fingerprint: fingerprint,
+		project:     project.clone(),
+		elapsed:     time.Since(start),
+	}
+
+	return project, nil
+}
+
+// cacheKey returns the key of the cached result of detecting the directory at path with detectors. Results are cached
+// separately for each set of detectors, since a directory may be detected as a different language otherwise.
+func cacheKey(detectors []projectDetector, path string) string {
+	languages := make([]string, 0, len(detectors))
+	for _, detector := range detectors {
+		languages = append(languages, string(detector.Language()))
+	}
+
+	return path + "|" + strings.Join(languages, ",")
+}
+
+// directoryFingerprint returns a hash of the names, sizes and modification times of the entries of a directory.
+func directoryFingerprint(entries []fs.DirEntry) (string, error) {
+	hash := sha256.New()
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "%s\x00%t\x00%d\x00%d\n", entry.Name(), entry.IsDir(), info.Size(), info.ModTime().UnixNano())
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// clone returns a deep copy of the project, so the cached project isn't modified by callers.
+func (p *Project) clone() *Project {
+	if p == nil {
+		return nil
+	}
+
+	cloned := *p
+	cloned.Dependencies = slices.Clone(p.Dependencies)
+	cloned.DatabaseDeps = slices.Clone(p.DatabaseDeps)
+	cloned.MessagingDeps = slices.Clone(p.MessagingDeps)
+
+	if p.Docker != nil {
+		docker := *p.Docker
+		cloned.Docker = &docker
+	}
+
+	if p.PackageManager != nil {
+		packageManager := *p.PackageManager
+		cloned.PackageManager = &packageManager
+	}
+
+	if p.PythonPackageManager != nil {
+		packageManager := *p.PythonPackageManager
+		cloned.PythonPackageManager = &packageManager
+	}
+
+	return &cloned
+}
@@ -0,0 +1,56 @@
+package appdetect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectWithCache(t *testing.T) {
+	dir := t.TempDir()
+	err := copyTestDataDir(t, "**/javascript/**", dir)
+	require.NoError(t, err)
+
+	cache := NewCache()
+	projects, err := Detect(context.Background(), dir, WithCache(cache))
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Nil(t, projects[0].Dependencies)
+	require.Zero(t, cache.Saved())
+
+	// modifying the results doesn't modify the cached results
+	projects[0].Dependencies = append(projects[0].Dependencies, JsReact)
+
+	projects, err = Detect(context.Background(), dir, WithCache(cache))
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Nil(t, projects[0].Dependencies)
+	require.Positive(t, cache.Saved())
+
+	// changing a file of the project detects the project again
+	packageJson := filepath.Join(dir, "javascript", "package.json")
+	err = os.WriteFile(packageJson, []byte(`{"dependencies": {"react": "^18.2.0"}}`), osutil.PermissionFile)
+	require.NoError(t, err)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(packageJson, later, later))
+
+	saved := cache.Saved()
+	projects, err = Detect(context.Background(), dir, WithCache(cache))
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, []Dependency{JsReact}, projects[0].Dependencies)
+
+	// the root directory, which has no project, is unchanged
+	require.Greater(t, cache.Saved(), saved)
+
+	cache.Reset()
+	saved = cache.Saved()
+	_, err = Detect(context.Background(), dir, WithCache(cache))
+	require.NoError(t, err)
+	require.Equal(t, saved, cache.Saved())
+}
@@ -86,6 +86,12 @@ type detectConfig struct {
 
 	// Internal usage fields
 	defaultExcludePatterns []string
+
+	// Rules parsed from an ignore file, evaluated after ExcludePatterns. See WithIgnorePatterns.
+	ignore *ignoreRules
+
+	// The cache of detection results, nil if results aren't cached. See WithCache.
+	cache *Cache
 }
 
 // Config that relates to project languages
@@ -117,6 +123,44 @@ func WithExcludePatterns(patterns []string, overrideDefaults bool) DetectOption
 	return &excludePatternsOptions{patterns, overrideDefaults}
 }
 
+type ignorePatternsOptions struct {
+	baseDir string
+	lines   []string
+}
+
+func (o *ignorePatternsOptions) apply(c detectConfig) detectConfig {
+	c.ignore = &ignoreRules{
+		baseDir: o.baseDir,
+		rules:   parseIgnoreRules(o.lines),
+	}
+	return c
+}
+
+// WithIgnorePatterns excludes directories using the lines of an ignore file in gitignore syntax, such as .azdignore.
+// Patterns are resolved against baseDir, which may differ from the directory being scanned.
+//
+// The patterns are evaluated after the patterns of WithExcludePatterns and the default exclude patterns, and the last
+// matching pattern wins. A negated pattern ('!pattern') therefore includes a directory that is otherwise excluded,
+// unless a parent directory of it is excluded.
+func WithIgnorePatterns(baseDir string, lines []string) DetectOption {
+	return &ignorePatternsOptions{baseDir, lines}
+}
+
+type cacheOptions struct {
+	cache *Cache
+}
+
+func (o *cacheOptions) apply(c detectConfig) detectConfig {
+	c.cache = o.cache
+	return c
+}
+
+// WithCache reuses the results cached in cache for the directories that are unchanged since they were last detected, and
+// caches the results of the directories detected. See Cache for when a directory is considered unchanged.
+func WithCache(cache *Cache) DetectOption {
+	return &cacheOptions{cache}
+}
+
 type includePython struct {
 }
 
@@ -252,3 +296,139 @@ func (o *excludeJavaScript) applyLang(c languageConfig) languageConfig {
 func WithoutJavaScript() LanguageOption {
 	return &excludeJavaScript{}
 }
+
+type includeGo struct {
+}
+
+func (o *includeGo) apply(c detectConfig) detectConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Go)
+	return c
+}
+
+func (o *includeGo) applyLang(c languageConfig) languageConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Go)
+	return c
+}
+
+func WithGo() LanguageOption {
+	return &includeGo{}
+}
+
+type excludeGo struct {
+}
+
+func (o *excludeGo) apply(c detectConfig) detectConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Go)
+	return c
+}
+
+func (o *excludeGo) applyLang(c languageConfig) languageConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Go)
+	return c
+}
+
+func WithoutGo() LanguageOption {
+	return &excludeGo{}
+}
+
+type includeRust struct {
+}
+
+func (o *includeRust) apply(c detectConfig) detectConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Rust)
+	return c
+}
+
+func (o *includeRust) applyLang(c languageConfig) languageConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Rust)
+	return c
+}
+
+func WithRust() LanguageOption {
+	return &includeRust{}
+}
+
+type excludeRust struct {
+}
+
+func (o *excludeRust) apply(c detectConfig) detectConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Rust)
+	return c
+}
+
+func (o *excludeRust) applyLang(c languageConfig) languageConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Rust)
+	return c
+}
+
+func WithoutRust() LanguageOption {
+	return &excludeRust{}
+}
+
+type includePhp struct {
+}
+
+func (o *includePhp) apply(c detectConfig) detectConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Php)
+	return c
+}
+
+func (o *includePhp) applyLang(c languageConfig) languageConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Php)
+	return c
+}
+
+func WithPhp() LanguageOption {
+	return &includePhp{}
+}
+
+type excludePhp struct {
+}
+
+func (o *excludePhp) apply(c detectConfig) detectConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Php)
+	return c
+}
+
+func (o *excludePhp) applyLang(c languageConfig) languageConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Php)
+	return c
+}
+
+func WithoutPhp() LanguageOption {
+	return &excludePhp{}
+}
+
+type includeRuby struct {
+}
+
+func (o *includeRuby) apply(c detectConfig) detectConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Ruby)
+	return c
+}
+
+func (o *includeRuby) applyLang(c languageConfig) languageConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Ruby)
+	return c
+}
+
+func WithRuby() LanguageOption {
+	return &includeRuby{}
+}
+
+type excludeRuby struct {
+}
+
+func (o *excludeRuby) apply(c detectConfig) detectConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Ruby)
+	return c
+}
+
+func (o *excludeRuby) applyLang(c languageConfig) languageConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Ruby)
+	return c
+}
+
+func WithoutRuby() LanguageOption {
+	return &excludeRuby{}
+}
@@ -1,19 +1,92 @@
 package appdetect
 
 import (
+	"bufio"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 func detectDocker(path string, entries []fs.DirEntry) (*Docker, error) {
 	for _, entry := range entries {
 		if strings.ToLower(entry.Name()) == "dockerfile" {
+			dockerPath := filepath.Join(path, entry.Name())
+			port, err := dockerfileExposedPort(dockerPath)
+			if err != nil {
+				return nil, err
+			}
+
 			return &Docker{
-				Path: filepath.Join(path, entry.Name()),
+				Path: dockerPath,
+				Port: port,
 			}, nil
 		}
 	}
 
 	return nil, nil
 }
+
+// dockerfileExposedPort returns the port exposed by the EXPOSE instructions of the final stage of a Dockerfile.
+//
+// Zero is returned when the port can't be determined: the final stage has no EXPOSE instruction, exposes more than
+// one port, or exposes a port set by a variable, for example 'EXPOSE $PORT'.
+func dockerfileExposedPort(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	ports := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	instruction := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if instruction == "" && strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// join lines continued by a trailing backslash into a single instruction
+		if continued, has := strings.CutSuffix(line, "\\"); has {
+			instruction += continued + " "
+			continue
+		}
+		instruction += line
+
+		fields := strings.Fields(instruction)
+		instruction = ""
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "FROM":
+			// only the ports of the final stage are exposed by the image
+			ports = map[string]struct{}{}
+		case "EXPOSE":
+			for _, port := range fields[1:] {
+				// ports may specify a protocol, for example 8080/tcp
+				port, _, _ = strings.Cut(port, "/")
+				ports[port] = struct{}{}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(ports) != 1 {
+		return 0, nil
+	}
+
+	for port := range ports {
+		if number, err := strconv.Atoi(port); err == nil && number > 0 && number <= 65535 {
+			return number, nil
+		}
+	}
+
+	return 0, nil
+}
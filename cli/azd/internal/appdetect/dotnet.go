@@ -2,10 +2,16 @@ package appdetect
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/fs"
+	"log"
+	"os"
 	"path/filepath"
 	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type dotNetDetector struct {
@@ -19,6 +25,7 @@ func (dd *dotNetDetector) DetectProject(ctx context.Context, path string, entrie
 	var hasProjectFile bool
 	var hasStartupFile bool
 	var projFileName string
+	var projFilePath string
 	var startUpFileName string
 
 	for _, entry := range entries {
@@ -39,16 +46,144 @@ func (dd *dotNetDetector) DetectProject(ctx context.Context, path string, entrie
 		case ".csproj", ".fsproj", ".vbproj":
 			hasProjectFile = true
 			projFileName = name
+			projFilePath = filepath.Join(path, entry.Name())
 		}
 	}
 
 	if hasProjectFile && hasStartupFile {
-		return &Project{
+		project := &Project{
 			Language:      DotNet,
 			Path:          path,
 			DetectionRule: "Inferred by presence of: " + fmt.Sprintf("%s, %s", projFileName, startUpFileName),
-		}, nil
+		}
+
+		databaseDepMap := map[DatabaseDep]struct{}{}
+		messagingDepMap := map[MessagingDep]struct{}{}
+		if err := detectDotNetPackages(projFilePath, databaseDepMap, messagingDepMap); err != nil {
+			return nil, err
+		}
+
+		if err := detectDotNetConnectionStrings(path, entries, databaseDepMap); err != nil {
+			return nil, err
+		}
+
+		if len(databaseDepMap) > 0 {
+			project.DatabaseDeps = maps.Keys(databaseDepMap)
+			slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+				return string(a) < string(b)
+			})
+		}
+
+		if len(messagingDepMap) > 0 {
+			project.MessagingDeps = maps.Keys(messagingDepMap)
+			slices.SortFunc(project.MessagingDeps, func(a, b MessagingDep) bool {
+				return string(a) < string(b)
+			})
+		}
+
+		return project, nil
 	}
 
 	return nil, nil
 }
+
+// dotNetProjectFile is the subset of a .NET project file used for detection.
+type dotNetProjectFile struct {
+	PackageReferences []struct {
+		Include string `xml:"Include,attr"`
+	} `xml:"ItemGroup>PackageReference"`
+}
+
+// detectDotNetPackages adds the databases and messaging services inferred from the package references of the project
+// file.
+func detectDotNetPackages(
+	projectFile string,
+	databaseDepMap map[DatabaseDep]struct{},
+	messagingDepMap map[MessagingDep]struct{}) error {
+	contents, err := os.ReadFile(projectFile)
+	if err != nil {
+		return err
+	}
+
+	var project dotNetProjectFile
+	if err := unmarshalXml(contents, &project); err != nil {
+		// the project is still detected, its dependencies just aren't inferred
+		log.Printf("skipping package references of %s, parsing failed: %v", projectFile, err)
+		return nil
+	}
+
+	for _, ref := range project.PackageReferences {
+		// NuGet package ids are case insensitive
+		switch strings.ToLower(ref.Include) {
+		case "mysqlconnector",
+			"mysql.data",
+			"pomelo.entityframeworkcore.mysql",
+			"mysql.entityframeworkcore":
+			databaseDepMap[DbMySql] = struct{}{}
+		case "microsoft.data.sqlclient",
+			"system.data.sqlclient",
+			"microsoft.entityframeworkcore.sqlserver":
+			databaseDepMap[DbSqlServer] = struct{}{}
+		}
+
+		switch strings.ToLower(ref.Include) {
+		case "azure.messaging.servicebus",
+			"microsoft.azure.servicebus",
+			"amqpnetlite":
+			messagingDepMap[MessagingServiceBus] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// detectDotNetConnectionStrings adds the databases inferred from the connection strings in the 'ConnectionStrings'
+// section of the appsettings.json files of the project.
+func detectDotNetConnectionStrings(path string, entries []fs.DirEntry, databaseDepMap map[DatabaseDep]struct{}) error {
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if entry.IsDir() || !strings.HasPrefix(name, "appsettings") || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		contents, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		var appSettings struct {
+			ConnectionStrings map[string]string `json:"ConnectionStrings"`
+		}
+		if err := json.Unmarshal(contents, &appSettings); err != nil {
+			// appsettings.json allows comments and trailing commas which are not valid JSON, skip these files.
+			log.Printf("skipping connection string detection in %s: %v", entry.Name(), err)
+			continue
+		}
+
+		for _, connectionString := range appSettings.ConnectionStrings {
+			if db, ok := databaseFromConnectionString(connectionString); ok {
+				databaseDepMap[db] = struct{}{}
+			}
+		}
+	}
+
+	return nil
+}
+
+// databaseFromConnectionString infers the database from well-known patterns in a connection string.
+func databaseFromConnectionString(connectionString string) (DatabaseDep, bool) {
+	lower := strings.ToLower(connectionString)
+	switch {
+	case strings.HasPrefix(lower, "mysql://"),
+		strings.Contains(lower, ".mysql.database.azure.com"),
+		strings.Contains(lower, "port=3306"):
+		return DbMySql, true
+	case strings.Contains(lower, ".database.windows.net"),
+		strings.Contains(lower, "initial catalog="),
+		strings.Contains(lower, "(localdb)"),
+		strings.Contains(lower, "trusted_connection="):
+		return DbSqlServer, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,227 @@
+package appdetect
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type goDetector struct {
+}
+
+func (gd *goDetector) Language() Language {
+	return Go
+}
+
+func (gd *goDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	for _, entry := range entries {
+		if strings.ToLower(entry.Name()) == "go.mod" {
+			project := &Project{
+				Language:      Go,
+				Path:          path,
+				DetectionRule: "Inferred by presence of: " + entry.Name(),
+			}
+
+			file, err := os.Open(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			scanner := bufio.NewScanner(file)
+			dependencyMap := map[Dependency]struct{}{}
+			databaseDepMap := map[DatabaseDep]struct{}{}
+			messagingDepMap := map[MessagingDep]struct{}{}
+
+			for scanner.Scan() {
+				// requirements look like either of:
+				//   require github.com/gin-gonic/gin v1.9.1
+				//   	github.com/gin-gonic/gin v1.9.1 // indirect
+				fields := strings.Fields(scanner.Text())
+				if len(fields) > 0 && fields[0] == "require" {
+					fields = fields[1:]
+				}
+				if len(fields) < 2 {
+					continue
+				}
+
+				module := fields[0]
+				switch {
+				case module == "github.com/gin-gonic/gin":
+					dependencyMap[GoGin] = struct{}{}
+				case module == "github.com/labstack/echo" || strings.HasPrefix(module, "github.com/labstack/echo/"):
+					dependencyMap[GoEcho] = struct{}{}
+				}
+
+				switch module {
+				case "github.com/go-sql-driver/mysql":
+					databaseDepMap[DbMySql] = struct{}{}
+				case "github.com/lib/pq",
+					"github.com/jackc/pgx/v4",
+					"github.com/jackc/pgx/v5":
+					databaseDepMap[DbPostgres] = struct{}{}
+				case "go.mongodb.org/mongo-driver":
+					databaseDepMap[DbMongo] = struct{}{}
+				case "github.com/microsoft/go-mssqldb",
+					"github.com/denisenkom/go-mssqldb":
+					databaseDepMap[DbSqlServer] = struct{}{}
+				case "github.com/redis/go-redis/v9",
+					"github.com/go-redis/redis/v8":
+					databaseDepMap[DbRedis] = struct{}{}
+				}
+
+				switch module {
+				case "github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus",
+					"github.com/Azure/go-amqp":
+					messagingDepMap[MessagingServiceBus] = struct{}{}
+				}
+			}
+
+			if err := scanner.Err(); err != nil {
+				_ = file.Close()
+				return nil, err
+			}
+
+			if err := file.Close(); err != nil {
+				return nil, err
+			}
+
+			if len(dependencyMap) > 0 {
+				project.Dependencies = maps.Keys(dependencyMap)
+				slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			if len(databaseDepMap) > 0 {
+				project.DatabaseDeps = maps.Keys(databaseDepMap)
+				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			if len(messagingDepMap) > 0 {
+				project.MessagingDeps = maps.Keys(messagingDepMap)
+				slices.SortFunc(project.MessagingDeps, func(a, b MessagingDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			return project, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GoMainPackage returns the directory of the main package of a Go module, relative to the module directory and using
+// forward slashes, for example '.' or 'cmd/server'.
+//
+// The module directory is checked first, followed by the directories under 'cmd', the common layout for Go modules.
+// An empty string is returned if no main package is found.
+// An error is returned only if the directories cannot be read.
+func GoMainPackage(projectPath string) (string, error) {
+	isMain, err := isGoMainPackage(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	if isMain {
+		return ".", nil
+	}
+
+	cmdEntries, err := os.ReadDir(filepath.Join(projectPath, "cmd"))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	// os.ReadDir returns entries sorted by name, so the first main package found is picked deterministically.
+	for _, entry := range cmdEntries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		isMain, err := isGoMainPackage(filepath.Join(projectPath, "cmd", entry.Name()))
+		if err != nil {
+			return "", err
+		}
+
+		if isMain {
+			return "cmd/" + entry.Name(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// isGoMainPackage returns true if the directory contains a non-test Go source file that declares 'package main'.
+func isGoMainPackage(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		isMain, err := declaresPackageMain(filepath.Join(dir, name))
+		if err != nil {
+			return false, err
+		}
+
+		if isMain {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func declaresPackageMain(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// the package clause is the first statement in the file, after comments and build constraints.
+		if len(fields) >= 2 && fields[0] == "package" {
+			return fields[1] == "main", nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// GoVersion returns the Go version declared by the 'go' directive in the go.mod file of a Go module, for example '1.21'.
+// An empty string is returned if the directive is not present.
+func GoVersion(projectPath string) (string, error) {
+	file, err := os.Open(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "go" {
+			return fields[1], nil
+		}
+	}
+
+	return "", scanner.Err()
+}
@@ -0,0 +1,94 @@
+package appdetect
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ignoreRule is a single pattern of an ignore file, such as .azdignore.
+type ignoreRule struct {
+	// The doublestar pattern, matched against the forward-slash path relative to the base directory of the rules.
+	pattern string
+
+	// If true, a directory matching the pattern is scanned even if it is excluded by a previous rule or pattern.
+	negate bool
+}
+
+// ignoreRules are the rules parsed from an ignore file, resolved against a base directory.
+type ignoreRules struct {
+	baseDir string
+	rules   []ignoreRule
+}
+
+// parseIgnoreRules parses lines in gitignore syntax into ignore rules:
+//
+//   - Blank lines and lines starting with '#' are ignored. Use '\#' for patterns starting with '#'.
+//   - A pattern starting with '!' negates the pattern. Use '\!' for patterns starting with '!'.
+//   - A pattern containing a '/' at the beginning or in the middle is relative to the base directory. Otherwise, the
+//     pattern matches at any level below the base directory.
+//   - A trailing '/' is accepted and ignored, since only directories are matched.
+//   - '*', '?', '[...]' and '**' are supported as wildcards.
+func parseIgnoreRules(lines []string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+
+		line = strings.TrimSuffix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		if strings.Contains(line, "/") {
+			rule.pattern = strings.TrimPrefix(line, "/")
+		} else {
+			rule.pattern = "**/" + line
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// apply returns whether the directory is excluded after evaluating the rules, given whether it is excluded before
+// evaluating them. Rules are evaluated in order, and the last rule that matches the directory wins.
+//
+// Since excluded directories are not scanned, a negated rule has no effect on a directory under an excluded directory.
+func (r ignoreRules) apply(dir string, excluded bool) (bool, error) {
+	rel, err := filepath.Rel(r.baseDir, dir)
+	if err != nil {
+		return false, err
+	}
+
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		// the rules only apply to directories under the base directory
+		return excluded, nil
+	}
+
+	for _, rule := range r.rules {
+		match, err := doublestar.Match(rule.pattern, rel)
+		if err != nil {
+			return false, err
+		}
+
+		if match {
+			excluded = !rule.negate
+		}
+	}
+
+	return excluded, nil
+}
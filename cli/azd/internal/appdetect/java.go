@@ -2,8 +2,15 @@ package appdetect
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
 	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type javaDetector struct {
@@ -16,13 +23,106 @@ func (jd *javaDetector) Language() Language {
 func (jd *javaDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
 	for _, entry := range entries {
 		if strings.ToLower(entry.Name()) == "pom.xml" {
-			return &Project{
+			project := &Project{
 				Language:      Java,
 				Path:          path,
 				DetectionRule: "Inferred by presence of: " + entry.Name(),
-			}, nil
+			}
+
+			contents, err := os.ReadFile(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			var pom pomProject
+			if err := unmarshalXml(contents, &pom); err != nil {
+				// the project is still detected, its dependencies just aren't inferred
+				log.Printf("skipping dependencies of %s, parsing failed: %v", filepath.Join(path, entry.Name()), err)
+				return project, nil
+			}
+
+			databaseDepMap := map[DatabaseDep]struct{}{}
+			messagingDepMap := map[MessagingDep]struct{}{}
+			for _, dep := range pom.Dependencies {
+				switch dep.ArtifactId {
+				case "mysql-connector-j", "mysql-connector-java":
+					databaseDepMap[DbMySql] = struct{}{}
+				case "mssql-jdbc", "r2dbc-mssql":
+					databaseDepMap[DbSqlServer] = struct{}{}
+				}
+
+				switch dep.ArtifactId {
+				case "azure-messaging-servicebus",
+					"spring-cloud-azure-starter-servicebus",
+					"spring-cloud-azure-starter-servicebus-jms",
+					"qpid-jms-client":
+					messagingDepMap[MessagingServiceBus] = struct{}{}
+				}
+			}
+
+			if len(databaseDepMap) > 0 {
+				project.DatabaseDeps = maps.Keys(databaseDepMap)
+				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			if len(messagingDepMap) > 0 {
+				project.MessagingDeps = maps.Keys(messagingDepMap)
+				slices.SortFunc(project.MessagingDeps, func(a, b MessagingDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			return project, nil
 		}
 	}
 
 	return nil, nil
 }
+
+// pomProject is the subset of a Maven project file used for detection.
+type pomProject struct {
+	Dependencies []pomDependency `xml:"dependencies>dependency"`
+	Properties   pomProperties   `xml:"properties"`
+}
+
+type pomProperties struct {
+	JavaVersion          string `xml:"java.version"`
+	MavenCompilerRelease string `xml:"maven.compiler.release"`
+	MavenCompilerTarget  string `xml:"maven.compiler.target"`
+}
+
+type pomDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+}
+
+// JavaVersion returns the Java version that the Maven project in projectPath is compiled for, for example '17'. The version
+// is read from the 'java.version', 'maven.compiler.release' or 'maven.compiler.target' properties of pom.xml, and
+// legacy versions such as '1.8' are returned as '8'. An empty string is returned if none of the properties are set, or
+// are set by a variable.
+func JavaVersion(projectPath string) (string, error) {
+	contents, err := os.ReadFile(filepath.Join(projectPath, "pom.xml"))
+	if err != nil {
+		return "", err
+	}
+
+	var pom pomProject
+	if err := unmarshalXml(contents, &pom); err != nil {
+		return "", fmt.Errorf("parsing pom.xml: %w", err)
+	}
+
+	for _, version := range []string{
+		pom.Properties.JavaVersion,
+		pom.Properties.MavenCompilerRelease,
+		pom.Properties.MavenCompilerTarget,
+	} {
+		version = strings.TrimPrefix(strings.TrimSpace(version), "1.")
+		if version != "" && !strings.Contains(version, "$") {
+			return version, nil
+		}
+	}
+
+	return "", nil
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
@@ -45,6 +46,7 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 
 			angularAdded := false
 			databaseDepMap := map[DatabaseDep]struct{}{}
+			messagingDepMap := map[MessagingDep]struct{}{}
 
 			for dep := range packagesJson.Dependencies {
 				switch dep {
@@ -73,6 +75,11 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 				case "redis", "redis-om":
 					databaseDepMap[DbRedis] = struct{}{}
 				}
+
+				switch dep {
+				case "@azure/service-bus", "rhea", "rhea-promise":
+					messagingDepMap[MessagingServiceBus] = struct{}{}
+				}
 			}
 
 			if len(databaseDepMap) > 0 {
@@ -82,6 +89,13 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 				})
 			}
 
+			if len(messagingDepMap) > 0 {
+				project.MessagingDeps = maps.Keys(messagingDepMap)
+				slices.SortFunc(project.MessagingDeps, func(a, b MessagingDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
 			slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
 				return string(a) < string(b)
 			})
@@ -113,6 +127,18 @@ func (nd *javaScriptDetector) DetectProject(ctx context.Context, path string, en
 				project.Language = TypeScript
 			}
 
+			packageManager, err := npm.DetectPackageManager(path)
+			if err != nil {
+				return nil, err
+			}
+
+			if packageManager.LockFile != "" {
+				project.PackageManager = &PackageManager{
+					Kind:         packageManager.Kind,
+					LockFilePath: filepath.Join(path, packageManager.LockFile),
+				}
+			}
+
 			return project, nil
 		}
 	}
@@ -0,0 +1,129 @@
+package appdetect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ComposerJson is the subset of a composer.json manifest read by azd.
+type ComposerJson struct {
+	Require map[string]string `json:"require"`
+}
+
+type phpDetector struct {
+}
+
+func (pd *phpDetector) Language() Language {
+	return Php
+}
+
+func (pd *phpDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	var composerJson string
+	var artisan string
+	for _, entry := range entries {
+		switch strings.ToLower(entry.Name()) {
+		case "composer.json":
+			composerJson = entry.Name()
+		case "artisan":
+			// the command line of Laravel apps
+			artisan = entry.Name()
+		}
+	}
+
+	if composerJson == "" && artisan == "" {
+		return nil, nil
+	}
+
+	project := &Project{
+		Language: Php,
+		Path:     path,
+	}
+
+	detectedFiles := []string{}
+	dependencyMap := map[Dependency]struct{}{}
+	databaseDepMap := map[DatabaseDep]struct{}{}
+
+	if composerJson != "" {
+		detectedFiles = append(detectedFiles, composerJson)
+
+		contents, err := os.ReadFile(filepath.Join(path, composerJson))
+		if err != nil {
+			return nil, err
+		}
+
+		var manifest ComposerJson
+		if err := json.Unmarshal(contents, &manifest); err != nil {
+			return nil, err
+		}
+
+		for pkg := range manifest.Require {
+			// composer package names are case insensitive
+			switch strings.ToLower(pkg) {
+			case "laravel/framework":
+				dependencyMap[PhpLaravel] = struct{}{}
+			}
+
+			switch strings.ToLower(pkg) {
+			case "ext-mysqli", "ext-pdo_mysql":
+				databaseDepMap[DbMySql] = struct{}{}
+			case "ext-pgsql", "ext-pdo_pgsql":
+				databaseDepMap[DbPostgres] = struct{}{}
+			case "ext-mongodb", "mongodb/mongodb", "mongodb/laravel-mongodb":
+				databaseDepMap[DbMongo] = struct{}{}
+			case "ext-sqlsrv", "ext-pdo_sqlsrv":
+				databaseDepMap[DbSqlServer] = struct{}{}
+			case "ext-redis", "predis/predis":
+				databaseDepMap[DbRedis] = struct{}{}
+			}
+		}
+	}
+
+	if artisan != "" {
+		detectedFiles = append(detectedFiles, artisan)
+		dependencyMap[PhpLaravel] = struct{}{}
+	}
+
+	project.DetectionRule = "Inferred by presence of: " + strings.Join(detectedFiles, ", ")
+
+	if len(dependencyMap) > 0 {
+		project.Dependencies = maps.Keys(dependencyMap)
+		slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	if len(databaseDepMap) > 0 {
+		project.DatabaseDeps = maps.Keys(databaseDepMap)
+		slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	return project, nil
+}
+
+// PhpDocumentRoot returns the directory of the project that is served by the web server, relative to the project
+// directory. This is the 'public' directory used by Laravel and most PHP frameworks, when the project has one.
+// An empty string is returned when the project directory itself is the document root.
+func PhpDocumentRoot(projectPath string) (string, error) {
+	info, err := os.Stat(filepath.Join(projectPath, "public"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if !info.IsDir() {
+		return "", nil
+	}
+
+	return "public", nil
+}
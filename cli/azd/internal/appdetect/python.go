@@ -3,11 +3,14 @@ package appdetect
 import (
 	"bufio"
 	"context"
+	"errors"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
@@ -20,83 +23,243 @@ func (pd *pythonDetector) Language() Language {
 }
 
 func (pd *pythonDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	var requirementsFile, pyprojectFile, pipfile string
 	for _, entry := range entries {
-		// entry.Name() == "pyproject.toml" when azd supports pyproject files
-		if strings.ToLower(entry.Name()) == "requirements.txt" {
-			project := &Project{
-				Language:      Python,
-				Path:          path,
-				DetectionRule: "Inferred by presence of: " + entry.Name(),
-			}
+		switch {
+		case strings.ToLower(entry.Name()) == python.RequirementsFile:
+			requirementsFile = entry.Name()
+		case entry.Name() == python.PyprojectFile:
+			pyprojectFile = entry.Name()
+		case entry.Name() == python.PipfileFile:
+			pipfile = entry.Name()
+		}
+	}
 
-			file, err := os.Open(filepath.Join(path, entry.Name()))
-			if err != nil {
-				return nil, err
-			}
+	var modules []string
+	var detectedBy string
 
-			scanner := bufio.NewScanner(file)
-			databaseDepMap := map[DatabaseDep]struct{}{}
+	if requirementsFile != "" {
+		requirements, err := requirementsModules(filepath.Join(path, requirementsFile))
+		if err != nil {
+			return nil, err
+		}
 
-			for scanner.Scan() {
-				split := strings.Split(scanner.Text(), "==")
-				if len(split) < 1 {
-					continue
-				}
+		modules = append(modules, requirements...)
+		detectedBy = requirementsFile
+	}
 
-				// pip is case insensitive: PEP 426
-				// https://peps.python.org/pep-0426/#name
-				module := strings.ToLower(strings.TrimSpace(split[0]))
-				switch module {
-				case "fastapi":
-					project.Dependencies = append(project.Dependencies, PyFastApi)
-				case "flask":
-					project.Dependencies = append(project.Dependencies, PyFlask)
-				case "django":
-					project.Dependencies = append(project.Dependencies, PyDjango)
-				}
+	if pyprojectFile != "" {
+		dependencies, isProject, err := pyprojectModules(filepath.Join(path, pyprojectFile))
+		if err != nil {
+			return nil, err
+		}
 
-				switch module {
-				case "flask_mysqldb",
-					"mysqlclient",
-					"aiomysql",
-					"asyncmy":
-					databaseDepMap[DbMySql] = struct{}{}
-				case "psycopg2",
-					"psycopg2-binary",
-					"psycopg",
-					"psycopgbinary",
-					"asyncpg",
-					"aiopg":
-					databaseDepMap[DbPostgres] = struct{}{}
-				case "pymongo",
-					"beanie",
-					"motor":
-					databaseDepMap[DbMongo] = struct{}{}
-				case "redis", "redis-om":
-					databaseDepMap[DbRedis] = struct{}{}
-				}
+		// pyproject.toml also configures tools such as linters, so it only marks a project when it declares one
+		if isProject {
+			modules = append(modules, dependencies...)
+			if detectedBy == "" {
+				detectedBy = pyprojectFile
 			}
+		}
+	}
+
+	if pipfile != "" {
+		packages, err := pipfileModules(filepath.Join(path, pipfile))
+		if err != nil {
+			return nil, err
+		}
+
+		modules = append(modules, packages...)
+		if detectedBy == "" {
+			detectedBy = pipfile
+		}
+	}
+
+	if detectedBy == "" {
+		return nil, nil
+	}
+
+	project := &Project{
+		Language:      Python,
+		Path:          path,
+		DetectionRule: "Inferred by presence of: " + detectedBy,
+	}
+
+	databaseDepMap := map[DatabaseDep]struct{}{}
+	messagingDepMap := map[MessagingDep]struct{}{}
+	dependencyMap := map[Dependency]struct{}{}
 
-			if err := file.Close(); err != nil {
-				return nil, err
+	for _, module := range modules {
+		switch module {
+		case "fastapi":
+			dependencyMap[PyFastApi] = struct{}{}
+		case "flask":
+			dependencyMap[PyFlask] = struct{}{}
+		case "django":
+			dependencyMap[PyDjango] = struct{}{}
+		}
+
+		switch module {
+		case "flask_mysqldb",
+			"mysqlclient",
+			"aiomysql",
+			"asyncmy",
+			"pymysql":
+			databaseDepMap[DbMySql] = struct{}{}
+		case "pyodbc",
+			"pymssql",
+			"mssql-django":
+			databaseDepMap[DbSqlServer] = struct{}{}
+		case "psycopg2",
+			"psycopg2-binary",
+			"psycopg",
+			"psycopgbinary",
+			"asyncpg",
+			"aiopg":
+			databaseDepMap[DbPostgres] = struct{}{}
+		case "pymongo",
+			"beanie",
+			"motor":
+			databaseDepMap[DbMongo] = struct{}{}
+		case "redis", "redis-om":
+			databaseDepMap[DbRedis] = struct{}{}
+		}
+
+		switch module {
+		case "azure-servicebus",
+			"uamqp",
+			"python-qpid-proton":
+			messagingDepMap[MessagingServiceBus] = struct{}{}
+		}
+	}
+
+	if len(dependencyMap) > 0 {
+		project.Dependencies = maps.Keys(dependencyMap)
+		slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	if len(databaseDepMap) > 0 {
+		project.DatabaseDeps = maps.Keys(databaseDepMap)
+		slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	if len(messagingDepMap) > 0 {
+		project.MessagingDeps = maps.Keys(messagingDepMap)
+		slices.SortFunc(project.MessagingDeps, func(a, b MessagingDep) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	packageManager, err := python.DetectPackageManager(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if packageManager.Kind != python.PackageManagerPip || packageManager.ManifestFile != python.RequirementsFile {
+		project.PythonPackageManager = &PythonPackageManager{
+			Kind:         packageManager.Kind,
+			ManifestPath: filepath.Join(path, packageManager.ManifestFile),
+		}
+
+		if packageManager.LockFile != "" {
+			project.PythonPackageManager.LockFilePath = filepath.Join(path, packageManager.LockFile)
+		}
+	}
+
+	return project, nil
+}
+
+// requirementsModules returns the lowercased names of the modules listed in the requirements.txt file at path.
+func requirementsModules(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	modules := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		split := strings.Split(scanner.Text(), "==")
+		if len(split) < 1 {
+			continue
+		}
+
+		// pip is case insensitive: PEP 426
+		// https://peps.python.org/pep-0426/#name
+		modules = append(modules, strings.ToLower(strings.TrimSpace(split[0])))
+	}
+
+	return modules, scanner.Err()
+}
+
+// pythonNameRegex matches the name at the start of a dependency specifier: PEP 508
+// https://peps.python.org/pep-0508/#names
+var pythonNameRegex = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// pyprojectModules returns the lowercased names of the dependencies declared in the pyproject.toml file at path, and
+// whether the file declares a project with a [project] or [tool.poetry] table. The dependencies are read from the
+// PEP 621 dependency arrays, the uv dev-dependencies array and the poetry dependency tables.
+func pyprojectModules(path string) ([]string, bool, error) {
+	modules := []string{}
+	isProject := false
+
+	err := scanToml(path, func(entry tomlEntry) {
+		table, key := entry.Table, strings.ToLower(entry.Key)
+		if key == "" {
+			if table == "project" || table == "tool.poetry" {
+				isProject = true
 			}
+			return
+		}
 
-			if len(databaseDepMap) > 0 {
-				project.DatabaseDeps = maps.Keys(databaseDepMap)
-				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
-					return string(a) < string(b)
-				})
+		switch {
+		case table == "project" && key == "dependencies",
+			table == "project.optional-dependencies",
+			table == "dependency-groups",
+			table == "tool.uv" && key == "dev-dependencies":
+			modules = append(modules, specifierNames(entry.Value)...)
+		case table == "tool.poetry.dependencies",
+			table == "tool.poetry.dev-dependencies",
+			strings.HasPrefix(table, "tool.poetry.group.") && strings.HasSuffix(table, ".dependencies"):
+			if key != "python" {
+				modules = append(modules, key)
 			}
+		}
+	})
+
+	return modules, isProject, err
+}
+
+// pipfileModules returns the lowercased names of the packages declared in the Pipfile at path.
+func pipfileModules(path string) ([]string, error) {
+	modules := []string{}
+
+	err := scanToml(path, func(entry tomlEntry) {
+		if entry.Key != "" && (entry.Table == "packages" || entry.Table == "dev-packages") {
+			modules = append(modules, strings.ToLower(entry.Key))
+		}
+	})
 
-			slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
-				return string(a) < string(b)
-			})
+	return modules, err
+}
 
-			return project, nil
+// specifierNames returns the lowercased names of the dependency specifiers quoted in s, for example 'flask' for
+// '"Flask>=3.0",'.
+func specifierNames(s string) []string {
+	names := []string{}
+	for _, match := range quotedStringRegex.FindAllStringSubmatch(s, -1) {
+		specifier := match[1] + match[2]
+		if name := pythonNameRegex.FindStringSubmatch(specifier); name != nil {
+			names = append(names, strings.ToLower(name[1]))
 		}
 	}
 
-	return nil, nil
+	return names
 }
 
 // PyFastApiLaunch returns the launch argument for a python FastAPI project to be served by a python web server.
@@ -165,3 +328,27 @@ func PyFastApiLaunch(projectPath string) (string, error) {
 
 	return launchPath, nil
 }
+
+// PyDjangoWsgiModule returns the WSGI module of a python Django project to be served by a python web server, for example
+// 'mysite.wsgi'. The module is the wsgi.py file of a package directly under the project directory, as created by
+// 'django-admin startproject'. An empty string is returned if no such module is found.
+func PyDjangoWsgiModule(projectPath string) (string, error) {
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(projectPath, entry.Name(), "wsgi.py")); err == nil {
+			return entry.Name() + ".wsgi", nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
@@ -1,10 +1,12 @@
 package appdetect
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,3 +48,117 @@ func TestFindFastApiMain(t *testing.T) {
 	require.NoError(t, err)
 	require.Empty(t, s)
 }
+
+func TestPyDjangoWsgiModule(t *testing.T) {
+	temp := t.TempDir()
+
+	module, err := PyDjangoWsgiModule(temp)
+	require.NoError(t, err)
+	require.Empty(t, module)
+
+	err = os.MkdirAll(filepath.Join(temp, "mysite"), 0700)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(temp, "mysite", "wsgi.py"), []byte{}, 0600)
+	require.NoError(t, err)
+
+	module, err = PyDjangoWsgiModule(temp)
+	require.NoError(t, err)
+	require.Equal(t, "mysite.wsgi", module)
+}
+
+func TestDetectPythonPackageManager(t *testing.T) {
+	t.Run("Poetry", func(t *testing.T) {
+		dir := t.TempDir()
+		pyproject := `[tool.poetry]
+name = "app"
+
+[tool.poetry.dependencies]
+python = "^3.12"
+Flask = "^3.0"
+psycopg2-binary = { version = "^2.9" }
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^8.0"
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte(pyproject), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "poetry.lock"), nil, 0600))
+
+		projects, err := Detect(context.Background(), dir)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		require.Equal(t, "Inferred by presence of: pyproject.toml", projects[0].DetectionRule)
+		require.Equal(t, []Dependency{PyFlask}, projects[0].Dependencies)
+		require.Equal(t, []DatabaseDep{DbPostgres}, projects[0].DatabaseDeps)
+		require.Equal(t, &PythonPackageManager{
+			Kind:         python.PackageManagerPoetry,
+			ManifestPath: filepath.Join(dir, "pyproject.toml"),
+			LockFilePath: filepath.Join(dir, "poetry.lock"),
+		}, projects[0].PythonPackageManager)
+	})
+
+	t.Run("Uv", func(t *testing.T) {
+		dir := t.TempDir()
+		pyproject := `[project]
+name = "app"
+dependencies = [
+    "fastapi[standard]>=0.110",  # the web framework
+    'redis',
+]
+
+[tool.uv]
+dev-dependencies = ["pytest"]
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte(pyproject), 0600))
+
+		projects, err := Detect(context.Background(), dir)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		require.Equal(t, []Dependency{PyFastApi}, projects[0].Dependencies)
+		require.Equal(t, []DatabaseDep{DbRedis}, projects[0].DatabaseDeps)
+		require.Equal(t, &PythonPackageManager{
+			Kind:         python.PackageManagerUv,
+			ManifestPath: filepath.Join(dir, "pyproject.toml"),
+		}, projects[0].PythonPackageManager)
+	})
+
+	t.Run("Pipenv", func(t *testing.T) {
+		dir := t.TempDir()
+		pipfile := `[packages]
+django = "*"
+
+[dev-packages]
+pytest = "*"
+`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Pipfile"), []byte(pipfile), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Pipfile.lock"), []byte("{}"), 0600))
+
+		projects, err := Detect(context.Background(), dir)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		require.Equal(t, "Inferred by presence of: Pipfile", projects[0].DetectionRule)
+		require.Equal(t, []Dependency{PyDjango}, projects[0].Dependencies)
+		require.Equal(t, &PythonPackageManager{
+			Kind:         python.PackageManagerPipenv,
+			ManifestPath: filepath.Join(dir, "Pipfile"),
+			LockFilePath: filepath.Join(dir, "Pipfile.lock"),
+		}, projects[0].PythonPackageManager)
+	})
+
+	t.Run("Pip", func(t *testing.T) {
+		dir := t.TempDir()
+		// a pyproject.toml that only configures tools doesn't change the package manager
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte("[tool.ruff]\n"), 0600))
+
+		projects, err := Detect(context.Background(), dir)
+		require.NoError(t, err)
+		require.Empty(t, projects)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("flask==3.0\n"), 0600))
+
+		projects, err = Detect(context.Background(), dir)
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+		require.Equal(t, []Dependency{PyFlask}, projects[0].Dependencies)
+		require.Nil(t, projects[0].PythonPackageManager)
+	})
+}
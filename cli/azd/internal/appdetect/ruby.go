@@ -0,0 +1,210 @@
+package appdetect
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type rubyDetector struct {
+}
+
+func (rd *rubyDetector) Language() Language {
+	return Ruby
+}
+
+func (rd *rubyDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	var gemfile string
+	var configRu string
+	var binRails string
+	for _, entry := range entries {
+		switch entry.Name() {
+		case "Gemfile":
+			gemfile = entry.Name()
+		case "config.ru":
+			// the rackup file of Rack apps, which Rails generates as well
+			configRu = entry.Name()
+		case "bin":
+			if entry.IsDir() {
+				// the command line of Rails apps
+				if _, err := os.Stat(filepath.Join(path, "bin", "rails")); err == nil {
+					binRails = "bin/rails"
+				}
+			}
+		}
+	}
+
+	var gems []string
+	if gemfile != "" {
+		var err error
+		if gems, err = readGemfile(filepath.Join(path, gemfile)); err != nil {
+			return nil, err
+		}
+	}
+
+	// a Gemfile alone is also used by tools and gems, so it only identifies a web app when it declares a web framework
+	// or server
+	if configRu == "" && binRails == "" && !slices.ContainsFunc(gems, isRubyWebGem) {
+		return nil, nil
+	}
+
+	project := &Project{
+		Language: Ruby,
+		Path:     path,
+	}
+
+	detectedFiles := []string{}
+	dependencyMap := map[Dependency]struct{}{}
+	databaseDepMap := map[DatabaseDep]struct{}{}
+
+	if gemfile != "" {
+		detectedFiles = append(detectedFiles, gemfile)
+
+		for _, gem := range gems {
+			switch gem {
+			case "rails":
+				dependencyMap[RubyRails] = struct{}{}
+			}
+
+			switch gem {
+			case "mysql2", "trilogy":
+				databaseDepMap[DbMySql] = struct{}{}
+			case "pg":
+				databaseDepMap[DbPostgres] = struct{}{}
+			case "mongoid", "mongo":
+				databaseDepMap[DbMongo] = struct{}{}
+			case "activerecord-sqlserver-adapter", "tiny_tds":
+				databaseDepMap[DbSqlServer] = struct{}{}
+			case "redis":
+				databaseDepMap[DbRedis] = struct{}{}
+			}
+		}
+	}
+
+	if configRu != "" {
+		detectedFiles = append(detectedFiles, configRu)
+	}
+
+	if binRails != "" {
+		detectedFiles = append(detectedFiles, binRails)
+		dependencyMap[RubyRails] = struct{}{}
+	}
+
+	project.DetectionRule = "Inferred by presence of: " + strings.Join(detectedFiles, ", ")
+
+	if len(dependencyMap) > 0 {
+		project.Dependencies = maps.Keys(dependencyMap)
+		slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	if len(databaseDepMap) > 0 {
+		project.DatabaseDeps = maps.Keys(databaseDepMap)
+		slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	return project, nil
+}
+
+// isRubyWebGem returns whether gem is a web framework or server, whose declaration in a Gemfile identifies a web app.
+func isRubyWebGem(gem string) bool {
+	switch gem {
+	case "rack", "rails", "sinatra", "puma":
+		return true
+	default:
+		return false
+	}
+}
+
+var gemRegex = regexp.MustCompile(`^\s*gem\s+["']([^"']+)["']`)
+var gemfileRubyRegex = regexp.MustCompile(`^\s*ruby\s+["']([0-9][0-9.]*)["']`)
+
+// readGemfile returns the names of the gems declared in a Gemfile, in order of declaration.
+func readGemfile(gemfilePath string) ([]string, error) {
+	file, err := os.Open(gemfilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gems := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if match := gemRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			gems = append(gems, match[1])
+		}
+	}
+
+	return gems, scanner.Err()
+}
+
+// RubyVersion returns the version of Ruby required by the project, read from its .ruby-version file or from the ruby
+// directive of its Gemfile, for example '3.3.0'. An empty string is returned when the project doesn't specify a version.
+func RubyVersion(projectPath string) (string, error) {
+	contents, err := os.ReadFile(filepath.Join(projectPath, ".ruby-version"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	// version managers accept an optional 'ruby-' prefix
+	if version := strings.TrimPrefix(strings.TrimSpace(string(contents)), "ruby-"); version != "" {
+		return version, nil
+	}
+
+	file, err := os.Open(filepath.Join(projectPath, "Gemfile"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if match := gemfileRubyRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			return match[1], nil
+		}
+	}
+
+	return "", scanner.Err()
+}
+
+// RailsAssets reports how the assets of a Rails app are built for production. precompile is true when the app has an
+// asset pipeline, which must run 'rails assets:precompile' when the app is packaged, and node is true when the assets are
+// bundled with jsbundling-rails or cssbundling-rails, which run Node.js to build them.
+func RailsAssets(projectPath string) (precompile bool, node bool, err error) {
+	gems, err := readGemfile(filepath.Join(projectPath, "Gemfile"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, false, err
+	}
+
+	for _, gem := range gems {
+		switch gem {
+		case "sprockets-rails", "propshaft", "importmap-rails":
+			precompile = true
+		case "jsbundling-rails", "cssbundling-rails":
+			precompile = true
+			node = true
+		}
+	}
+
+	if !precompile {
+		// apps created before the asset pipeline gems were split out of rails
+		if info, err := os.Stat(filepath.Join(projectPath, "app", "assets")); err == nil && info.IsDir() {
+			precompile = true
+		}
+	}
+
+	return precompile, node, nil
+}
@@ -0,0 +1,154 @@
+package appdetect
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type rustDetector struct {
+}
+
+func (rd *rustDetector) Language() Language {
+	return Rust
+}
+
+func (rd *rustDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	for _, entry := range entries {
+		if entry.Name() == "Cargo.toml" {
+			project := &Project{
+				Language:      Rust,
+				Path:          path,
+				DetectionRule: "Inferred by presence of: " + entry.Name(),
+			}
+
+			manifest, err := readCargoManifest(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			if manifest.packageName == "" {
+				// a workspace manifest without a package, the members of the workspace are detected separately
+				return nil, nil
+			}
+
+			dependencyMap := map[Dependency]struct{}{}
+			databaseDepMap := map[DatabaseDep]struct{}{}
+			messagingDepMap := map[MessagingDep]struct{}{}
+
+			for _, crate := range manifest.dependencies {
+				switch crate {
+				case "actix-web":
+					dependencyMap[RustActix] = struct{}{}
+				case "axum":
+					dependencyMap[RustAxum] = struct{}{}
+				}
+
+				switch crate {
+				case "mysql", "mysql_async":
+					databaseDepMap[DbMySql] = struct{}{}
+				case "postgres", "tokio-postgres":
+					databaseDepMap[DbPostgres] = struct{}{}
+				case "mongodb":
+					databaseDepMap[DbMongo] = struct{}{}
+				case "tiberius":
+					databaseDepMap[DbSqlServer] = struct{}{}
+				case "redis":
+					databaseDepMap[DbRedis] = struct{}{}
+				}
+
+				switch crate {
+				case "azservicebus", "fe2o3-amqp":
+					messagingDepMap[MessagingServiceBus] = struct{}{}
+				}
+			}
+
+			if len(dependencyMap) > 0 {
+				project.Dependencies = maps.Keys(dependencyMap)
+				slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			if len(databaseDepMap) > 0 {
+				project.DatabaseDeps = maps.Keys(databaseDepMap)
+				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			if len(messagingDepMap) > 0 {
+				project.MessagingDeps = maps.Keys(messagingDepMap)
+				slices.SortFunc(project.MessagingDeps, func(a, b MessagingDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			return project, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// RustBinaryName returns the name of the binary built from the Cargo.toml manifest in the project directory. This is
+// the name of the first [[bin]] target, or the package name when the manifest declares no binary targets.
+// An empty string is returned if the manifest declares neither.
+func RustBinaryName(projectPath string) (string, error) {
+	manifest, err := readCargoManifest(filepath.Join(projectPath, "Cargo.toml"))
+	if err != nil {
+		return "", err
+	}
+
+	if manifest.binName != "" {
+		return manifest.binName, nil
+	}
+
+	return manifest.packageName, nil
+}
+
+// cargoManifest is the subset of a Cargo.toml manifest read by azd.
+type cargoManifest struct {
+	// The name in the [package] table.
+	packageName string
+	// The name of the first [[bin]] target.
+	binName string
+	// The names of the crates in the [dependencies] table.
+	dependencies []string
+}
+
+// readCargoManifest reads the package name, binary name and dependencies of a Cargo.toml manifest.
+func readCargoManifest(path string) (cargoManifest, error) {
+	manifest := cargoManifest{}
+	firstBin := false
+
+	err := scanToml(path, func(entry tomlEntry) {
+		if entry.Key == "" {
+			// only the first [[bin]] target is considered
+			firstBin = entry.ArrayTable && entry.Table == "bin" && manifest.binName == ""
+
+			// dependencies can also be declared as tables, for example [dependencies.axum]
+			if crate, has := strings.CutPrefix(entry.Table, "dependencies."); has {
+				manifest.dependencies = append(manifest.dependencies, tomlString(crate))
+			}
+			return
+		}
+
+		switch {
+		case entry.Table == "package" && entry.Key == "name":
+			manifest.packageName = tomlString(entry.Value)
+		case entry.Table == "bin" && firstBin && entry.Key == "name":
+			manifest.binName = tomlString(entry.Value)
+		case entry.Table == "dependencies":
+			// dotted keys, for example 'axum.workspace = true', name the crate before the first '.'
+			crate, _, _ := strings.Cut(entry.Key, ".")
+			manifest.dependencies = append(manifest.dependencies, tomlString(crate))
+		}
+	})
+
+	return manifest, err
+}
@@ -0,0 +1,131 @@
+package appdetect
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// tomlEntry is a table header or a key/value pair of a TOML file.
+type tomlEntry struct {
+	// The name of the table the entry is in, for example 'tool.poetry' for '[tool.poetry]', without quotes and whitespace.
+	// Empty before the first table header.
+	Table string
+	// True when the table is an array of tables, for example '[[bin]]'.
+	ArrayTable bool
+	// The unquoted key of a key/value pair. Empty for a table header.
+	Key string
+	// The value of a key/value pair. The lines of a multiline array are joined, without their comments.
+	Value string
+}
+
+// scanToml calls fn with each table header and key/value pair of the TOML file at path, in order. Only the TOML
+// constructs used by project manifests in practice are understood: tables, arrays of tables, key/value pairs on a single
+// line and multiline arrays.
+func scanToml(path string, fn func(entry tomlEntry)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry := tomlEntry{}
+	// true while the lines of a multiline array are joined to the value of entry
+	inArray := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if inArray {
+			entry.Value += " " + stripTomlComment(line)
+			if closesTomlArray(line) {
+				inArray = false
+				fn(entry)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			entry = tomlEntry{
+				Table:      tomlTableName(line),
+				ArrayTable: strings.HasPrefix(line, "[["),
+			}
+			fn(entry)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		entry.Key = strings.Trim(strings.TrimSpace(key), `"'`)
+		entry.Value = strings.TrimSpace(value)
+		if strings.HasPrefix(entry.Value, "[") && !closesTomlArray(entry.Value) {
+			entry.Value = stripTomlComment(entry.Value)
+			inArray = true
+			continue
+		}
+
+		fn(entry)
+	}
+
+	return scanner.Err()
+}
+
+// tomlTableName returns the name of the table of a TOML table header line, for example 'tool.poetry' for
+// '[tool.poetry]'.
+func tomlTableName(line string) string {
+	name := strings.Trim(line, "[] ")
+	return strings.ReplaceAll(strings.ReplaceAll(name, `"`, ""), " ", "")
+}
+
+// tomlString returns the value of a TOML string, with surrounding whitespace, quotes and trailing comments removed.
+func tomlString(value string) string {
+	value = strings.TrimSpace(value)
+	for _, quote := range []string{`"`, `'`} {
+		if strings.HasPrefix(value, quote) {
+			if end := strings.Index(value[1:], quote); end >= 0 {
+				return value[1 : end+1]
+			}
+		}
+	}
+
+	return value
+}
+
+// stripTomlComment returns line without its trailing comment, ignoring the '#' of quoted strings.
+func stripTomlComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+
+	return line
+}
+
+// quotedStringRegex matches the strings of a TOML line, for example the dependency specifiers of an array.
+var quotedStringRegex = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// closesTomlArray returns true when a TOML line ends the array it continues, ignoring the brackets of quoted strings
+// such as 'uvicorn[standard]'.
+func closesTomlArray(line string) bool {
+	unquoted := quotedStringRegex.ReplaceAllString(line, "")
+	if comment := strings.Index(unquoted, "#"); comment >= 0 {
+		unquoted = unquoted[:comment]
+	}
+
+	return strings.Contains(unquoted, "]")
+}
@@ -0,0 +1,51 @@
+package appdetect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanToml(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.toml")
+	err := os.WriteFile(path, []byte(`# comment
+title = "top"
+
+[project]
+"name" = 'app'
+dependencies = [
+    "uvicorn[standard]>=0.30", # the server isn't "fast"
+    "fastapi#egg",
+]
+
+[[bin]]
+name = "first"
+
+[tool . "poetry"]
+`), 0600)
+	require.NoError(t, err)
+
+	entries := []tomlEntry{}
+	err = scanToml(path, func(entry tomlEntry) {
+		entries = append(entries, entry)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []tomlEntry{
+		{Key: "title", Value: `"top"`},
+		{Table: "project"},
+		{Table: "project", Key: "name", Value: "'app'"},
+		{
+			Table: "project",
+			Key:   "dependencies",
+			Value: `[ "uvicorn[standard]>=0.30", "fastapi#egg", ]`,
+		},
+		{Table: "bin", ArrayTable: true},
+		{Table: "bin", ArrayTable: true, Key: "name", Value: `"first"`},
+		{Table: "tool.poetry"},
+	}, entries)
+
+	require.Equal(t, "app", tomlString(entries[2].Value))
+	require.Equal(t, []string{"uvicorn", "fastapi"}, specifierNames(entries[3].Value))
+}
@@ -0,0 +1,16 @@
+package appdetect
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"golang.org/x/net/html/charset"
+)
+
+// unmarshalXml parses the XML document in contents into v. Unlike xml.Unmarshal, documents that declare an encoding
+// other than UTF-8, such as a pom.xml with encoding="ISO-8859-1", are decoded.
+func unmarshalXml(contents []byte, v any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(contents))
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder.Decode(v)
+}
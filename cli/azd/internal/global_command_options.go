@@ -15,6 +15,10 @@ type GlobalCommandOptions struct {
 	// if there is no default value the prompt returns an error.
 	NoPrompt bool
 
+	// AssumeYes indicates confirmation prompts should be answered yes without prompting. Other prompts, such as selections,
+	// are still shown. It's enabled with `--yes`, for any command, and takes precedence over NoPrompt for confirmations.
+	AssumeYes bool
+
 	// EnableTelemetry indicates if telemetry should be sent.
 	// The rootCmd will disable this based if the environment variable
 	// AZURE_DEV_COLLECT_TELEMETRY is set to 'no'.
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
@@ -16,12 +20,16 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/otiai10/copy"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -31,21 +39,97 @@ var languageMap = map[appdetect.Language]project.ServiceLanguageKind{
 	appdetect.JavaScript: project.ServiceLanguageJavaScript,
 	appdetect.TypeScript: project.ServiceLanguageTypeScript,
 	appdetect.Python:     project.ServiceLanguagePython,
+	appdetect.Go:         project.ServiceLanguageGo,
+	appdetect.Rust:       project.ServiceLanguageRust,
+	appdetect.Php:        project.ServiceLanguagePhp,
+	appdetect.Ruby:       project.ServiceLanguageRuby,
 }
 
 var dbMap = map[appdetect.DatabaseDep]struct{}{
-	appdetect.DbMongo:    {},
-	appdetect.DbPostgres: {},
-	appdetect.DbRedis:    {},
+	appdetect.DbMongo:     {},
+	appdetect.DbPostgres:  {},
+	appdetect.DbMySql:     {},
+	appdetect.DbSqlServer: {},
+	appdetect.DbRedis:     {},
+}
+
+var messagingMap = map[appdetect.MessagingDep]struct{}{
+	appdetect.MessagingServiceBus: {},
 }
 
 var ErrNoServicesDetected = errors.New("no services detected in the current directory")
 
+// azdIgnoreFileName is the name of the optional file, at the root of the project, that lists directories to exclude
+// when scanning app code, in gitignore syntax.
+const azdIgnoreFileName = ".azdignore"
+
+// readAzdIgnore returns the lines of the .azdignore file in the project directory, or nil if the file does not exist.
+func readAzdIgnore(projectDir string) ([]string, error) {
+	contents, err := os.ReadFile(filepath.Join(projectDir, azdIgnoreFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", azdIgnoreFileName, err)
+	}
+
+	return strings.Split(string(contents), "\n"), nil
+}
+
+// excludeInAzdIgnore adds the directories of the excluded services to the .azdignore file in the project directory, so
+// they aren't detected when init is run again. Directories that are already listed, and the project directory itself,
+// are not added. It returns whether the file was updated.
+func excludeInAzdIgnore(projectDir string, excluded []appdetect.Project) (bool, error) {
+	lines, err := readAzdIgnore(projectDir)
+	if err != nil {
+		return false, err
+	}
+
+	var added []string
+	for _, svc := range excluded {
+		rel, err := filepath.Rel(projectDir, svc.Path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			log.Printf("not excluding %s in %s, it is not under the project directory", svc.Path, azdIgnoreFileName)
+			continue
+		}
+
+		line := "/" + filepath.ToSlash(rel) + "/"
+		if !slices.Contains(lines, line) && !slices.Contains(added, line) {
+			added = append(added, line)
+		}
+	}
+
+	if len(added) == 0 {
+		return false, nil
+	}
+
+	var contents strings.Builder
+	if len(lines) > 0 {
+		contents.WriteString(strings.Join(lines, "\n"))
+		if lines[len(lines)-1] != "" {
+			contents.WriteString("\n")
+		}
+		contents.WriteString("\n")
+	}
+
+	contents.WriteString("# Services excluded when initializing the app with azd init\n")
+	for _, line := range added {
+		contents.WriteString(line + "\n")
+	}
+
+	err = os.WriteFile(filepath.Join(projectDir, azdIgnoreFileName), []byte(contents.String()), osutil.PermissionFile)
+	if err != nil {
+		return false, fmt.Errorf("writing %s: %w", azdIgnoreFileName, err)
+	}
+
+	return true, nil
+}
+
 // InitFromApp initializes the infra directory and project file from the current existing app.
 func (i *Initializer) InitFromApp(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
-	initializeEnv func() (*environment.Environment, error)) error {
+	initializeEnv func() (*environment.Environment, error),
+	initOptions InitFromAppOptions) error {
 	i.console.Message(ctx, "")
 	title := "Scanning app code in current directory"
 	i.console.ShowSpinner(ctx, title, input.Step)
@@ -53,12 +137,22 @@ func (i *Initializer) InitFromApp(
 
 	projects := []appdetect.Project{}
 	start := time.Now()
+	savedBefore := i.cacheSaved()
 	sourceDir := filepath.Join(wd, "src")
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("detect"))
 
+	// Read on each scan so that changes to the ignore file are picked up when init is run again
+	ignoreLines, err := readAzdIgnore(wd)
+	if err != nil {
+		i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+		return err
+	}
+
 	// Prioritize src directory if it exists
 	if ent, err := os.Stat(sourceDir); err == nil && ent.IsDir() {
-		prj, err := appdetect.Detect(ctx, sourceDir)
+		prj, err := appdetect.Detect(ctx, sourceDir,
+			appdetect.WithIgnorePatterns(wd, ignoreLines),
+			appdetect.WithCache(i.detectCache))
 		if err == nil && len(prj) > 0 {
 			projects = prj
 		}
@@ -69,7 +163,9 @@ func (i *Initializer) InitFromApp(
 			"**/eng",
 			"**/tool",
 			"**/tools"},
-			false))
+			false),
+			appdetect.WithIgnorePatterns(wd, ignoreLines),
+			appdetect.WithCache(i.detectCache))
 		if err != nil {
 			i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
 			return err
@@ -79,7 +175,6 @@ func (i *Initializer) InitFromApp(
 	}
 
 	appHostManifests := make(map[string]*apphost.Manifest)
-	appHostForProject := make(map[string]string)
 
 	// Load the manifests for all the App Host projects we detected, we use the manifest as part of infrastructure
 	// generation.
@@ -88,16 +183,13 @@ func (i *Initializer) InitFromApp(
 			continue
 		}
 
-		manifest, err := apphost.ManifestFromAppHost(ctx, prj.Path, i.dotnetCli)
+		manifest, err := i.appHostManifest(ctx, prj.Path)
 		if err != nil {
 			return fmt.Errorf("failed to generate manifest from app host project: %w", err)
 		}
 		appHostManifests[prj.Path] = manifest
-
-		for _, path := range apphost.ProjectPaths(manifest) {
-			appHostForProject[filepath.Dir(path)] = prj.Path
-		}
 	}
+	appHostForProject := projectAppHosts(appHostManifests)
 
 	// Filter out all the projects owned by an App Host.
 	{
@@ -110,6 +202,11 @@ func (i *Initializer) InitFromApp(
 		projects = filteredProject
 	}
 
+	if saved := i.cacheSaved() - savedBefore; saved > 0 {
+		log.Printf("reused cached detection results, saving %s", saved)
+		tracing.SetUsageAttributes(fields.AppInitDetectCacheSavedTime.Int64(saved.Milliseconds()))
+	}
+
 	end := time.Since(start)
 	if i.console.IsSpinnerInteractive() {
 		// If the spinner is interactive, we want to show it for at least 1 second
@@ -119,11 +216,30 @@ func (i *Initializer) InitFromApp(
 
 	isDotNetAppHost := func(p appdetect.Project) bool { return p.Language == appdetect.DotNetAppHost }
 	if idx := slices.IndexFunc(projects, isDotNetAppHost); idx >= 0 {
+		var appHosts []appdetect.Project
+		for _, prj := range projects {
+			if isDotNetAppHost(prj) {
+				appHosts = append(appHosts, prj)
+			}
+		}
+
 		// TODO(ellismg): We will have to figure out how to relax this over time.
-		if len(projects) != 1 {
+		if len(appHosts) != len(projects) {
 			return errors.New("only a single Aspire project is supported at this time")
 		}
 
+		if len(appHosts) > 1 {
+			// A project may only contain a single Aspire service, so the user picks which app host to initialize.
+			selected, err := i.selectAppHost(ctx, wd, appHosts)
+			if err != nil {
+				return err
+			}
+
+			idx = slices.IndexFunc(projects, func(p appdetect.Project) bool { return p.Path == selected.Path })
+			log.Printf("initializing app host %s with projects %s",
+				selected.Path, strings.Join(appHostProjectDirs(appHostForProject, selected.Path), ", "))
+		}
+
 		detect := detectConfirmAppHost{console: i.console}
 		detect.Init(projects[idx], wd)
 
@@ -135,9 +251,18 @@ func (i *Initializer) InitFromApp(
 		ingressSelector := apphost.NewIngressSelector(appHostManifests[projects[idx].Path], i.console)
 		tracing.SetUsageAttributes(fields.AppInitLastStep.String("modify"))
 
-		exposed, err := ingressSelector.SelectPublicServices(ctx)
-		if err != nil {
-			return err
+		var exposed []string
+		if initOptions.FromCode && i.console.IsNoPromptMode() {
+			// the detected defaults are accepted, so all the services that can be exposed are exposed
+			exposed = apphost.ExposableServices(appHostManifests[projects[idx].Path])
+			if len(exposed) > 0 {
+				i.console.Message(ctx, fmt.Sprintf("Exposing services to the Internet: %s", strings.Join(exposed, ", ")))
+			}
+		} else {
+			exposed, err = ingressSelector.SelectPublicServices(ctx)
+			if err != nil {
+				return err
+			}
 		}
 
 		tracing.SetUsageAttributes(fields.AppInitLastStep.String("config"))
@@ -207,6 +332,7 @@ func (i *Initializer) InitFromApp(
 		if err := copy.Copy(staging, azdCtx.ProjectDirectory(), options); err != nil {
 			return fmt.Errorf("copying contents from temp staging directory: %w", err)
 		}
+		i.ResetDetectionCache()
 
 		i.console.MessageUxItem(ctx, &ux.DoneMessage{
 			Message: "Generating " + output.WithHighLightFormat("./azure.yaml"),
@@ -228,11 +354,26 @@ func (i *Initializer) InitFromApp(
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("modify"))
 
 	// Confirm selection of services and databases
-	err := detect.Confirm(ctx)
+	err = detect.Confirm(ctx)
+	if err != nil {
+		return err
+	}
+
+	infra := filepath.Join(azdCtx.ProjectDirectory(), "infra")
+	infraChoice, err := i.confirmExistingInfra(ctx, azdCtx, infra)
 	if err != nil {
 		return err
 	}
 
+	// The provider of the existing infra that is kept instead of generating IaC
+	var keptInfraProvider provisioning.ProviderKind
+	if infraChoice == existingInfraSkip {
+		keptInfraProvider, err = existingInfraProvider(infra)
+		if err != nil {
+			return err
+		}
+	}
+
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("config"))
 
 	// Create the infra spec
@@ -250,12 +391,40 @@ func (i *Initializer) InitFromApp(
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("generate"))
 
 	i.console.Message(ctx, "\n"+output.WithBold("Generating files to run your app on Azure:")+"\n")
-	err = i.genProjectFile(ctx, azdCtx, detect)
+
+	updated, err := excludeInAzdIgnore(wd, detect.Excluded)
 	if err != nil {
 		return err
 	}
 
-	infra := filepath.Join(azdCtx.ProjectDirectory(), "infra")
+	if updated {
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: "Updating " + output.WithHighLightFormat("./"+azdIgnoreFileName),
+		})
+	}
+
+	t, err := scaffold.Load()
+	if err != nil {
+		return fmt.Errorf("loading scaffold templates: %w", err)
+	}
+
+	err = i.genDockerfiles(ctx, t, azdCtx, &detect, spec, initOptions.GenerateDockerfiles)
+	if err != nil {
+		return err
+	}
+
+	err = i.genProjectFile(ctx, azdCtx, detect, keptInfraProvider)
+	if err != nil {
+		return err
+	}
+
+	if infraChoice == existingInfraSkip {
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: "Keeping the existing infrastructure in " + output.WithHighLightFormat("./infra"),
+		})
+		return nil
+	}
+
 	title = "Generating Infrastructure as Code files in " + output.WithHighLightFormat("./infra")
 	i.console.ShowSpinner(ctx, title, input.Step)
 	defer i.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
@@ -266,11 +435,6 @@ func (i *Initializer) InitFromApp(
 	}
 
 	defer func() { _ = os.RemoveAll(staging) }()
-	t, err := scaffold.Load()
-	if err != nil {
-		return fmt.Errorf("loading scaffold templates: %w", err)
-	}
-
 	err = scaffold.ExecInfra(t, spec, staging)
 	if err != nil {
 		return err
@@ -280,7 +444,12 @@ func (i *Initializer) InitFromApp(
 		return err
 	}
 
-	skipStagingFiles, err := i.promptForDuplicates(ctx, staging, infra)
+	var skipStagingFiles map[string]struct{}
+	if infraChoice == existingInfraMerge {
+		skipStagingFiles, err = duplicateSourceFiles(staging, infra)
+	} else {
+		skipStagingFiles, err = i.promptForDuplicates(ctx, staging, infra)
+	}
 	if err != nil {
 		return err
 	}
@@ -309,10 +478,113 @@ func (i *Initializer) InitFromApp(
 	return nil
 }
 
+// appHostManifest returns the manifest of the app host project at path. The manifest is generated once per app host
+// project and reused until ResetDetectionCache is called, since generating it requires building the project.
+func (i *Initializer) appHostManifest(ctx context.Context, path string) (*apphost.Manifest, error) {
+	i.cacheMu.Lock()
+	defer i.cacheMu.Unlock()
+
+	if cached, has := i.manifestCache[path]; has {
+		i.manifestSaved += cached.elapsed
+		return cached.manifest, nil
+	}
+
+	start := time.Now()
+	manifest, err := apphost.ManifestFromAppHost(ctx, path, i.dotnetCli)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.manifestCache == nil {
+		i.manifestCache = map[string]manifestCacheEntry{}
+	}
+
+	i.manifestCache[path] = manifestCacheEntry{
+		manifest: manifest,
+		elapsed:  time.Since(start),
+	}
+
+	return manifest, nil
+}
+
+// cacheSaved returns the total time saved by reusing cached detection results and app host manifests.
+func (i *Initializer) cacheSaved() time.Duration {
+	i.cacheMu.Lock()
+	defer i.cacheMu.Unlock()
+
+	return i.detectCache.Saved() + i.manifestSaved
+}
+
+// selectAppHost prompts the user to select one of multiple detected app host projects.
+func (i *Initializer) selectAppHost(
+	ctx context.Context,
+	root string,
+	appHosts []appdetect.Project) (appdetect.Project, error) {
+	options := make([]string, 0, len(appHosts))
+	for _, appHost := range appHosts {
+		options = append(options, relSafe(root, appHost.Path))
+	}
+
+	if i.console.IsNoPromptMode() {
+		return appdetect.Project{}, fmt.Errorf(
+			"detected %d Aspire app host projects (%s), only a single app host project can be initialized. "+
+				"Exclude the other app host projects in %s, or run init without --no-prompt to select one",
+			len(appHosts), strings.Join(options, ", "), azdIgnoreFileName)
+	}
+
+	i.console.Message(ctx, fmt.Sprintf("\nDetected %d Aspire app host projects.", len(appHosts)))
+	selection, err := i.console.Select(ctx, input.ConsoleOptions{
+		Message: "Select the app host project to initialize",
+		Help: "Hint: Aspire app host\n\n" +
+			"Only a single Aspire app host project can be hosted in an azd project at this time. " +
+			"The projects referenced by the selected app host are deployed with it.",
+		Options: options,
+	})
+	if err != nil {
+		return appdetect.Project{}, err
+	}
+
+	return appHosts[selection], nil
+}
+
+// projectAppHosts returns the path of the app host that references each project, keyed by the directory of the project.
+// A project referenced by multiple app hosts is attributed to the first of them, by path.
+func projectAppHosts(appHostManifests map[string]*apphost.Manifest) map[string]string {
+	appHostForProject := make(map[string]string)
+	appHostPaths := maps.Keys(appHostManifests)
+	slices.Sort(appHostPaths)
+
+	for _, appHostPath := range appHostPaths {
+		for _, path := range apphost.ProjectPaths(appHostManifests[appHostPath]) {
+			if _, has := appHostForProject[filepath.Dir(path)]; !has {
+				appHostForProject[filepath.Dir(path)] = appHostPath
+			}
+		}
+	}
+
+	return appHostForProject
+}
+
+// appHostProjectDirs returns the sorted directories of the projects attributed to the app host at appHostPath.
+func appHostProjectDirs(appHostForProject map[string]string, appHostPath string) []string {
+	var dirs []string
+	for dir, appHost := range appHostForProject {
+		if appHost == appHostPath {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	slices.Sort(dirs)
+	return dirs
+}
+
+// genProjectFile generates the project file of the detected services. When infraProvider is set, the project uses the
+// existing infra of the provider instead of the generated IaC.
 func (i *Initializer) genProjectFile(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
-	detect detectConfirm) error {
+	detect detectConfirm,
+	infraProvider provisioning.ProviderKind) error {
 	title := "Generating " + output.WithHighLightFormat("./"+azdcontext.ProjectFileName)
 
 	i.console.ShowSpinner(ctx, title, input.Step)
@@ -323,6 +595,12 @@ func (i *Initializer) genProjectFile(
 	if err != nil {
 		return fmt.Errorf("converting config: %w", err)
 	}
+
+	// bicep is the default provider, so it's only set for terraform
+	if infraProvider == provisioning.Terraform {
+		config.Infra.Provider = infraProvider
+	}
+
 	err = project.Save(
 		ctx,
 		&config,
@@ -334,6 +612,412 @@ func (i *Initializer) genProjectFile(
 	return i.writeCoreAssets(ctx, azdCtx)
 }
 
+// InitFromAppOptions are the options of initializing a project from the app code in the current directory.
+type InitFromAppOptions struct {
+	// If true, a Dockerfile is generated for each Python, JavaScript, TypeScript and Java service that does not already
+	// have one. Otherwise these services are built with buildpacks.
+	GenerateDockerfiles bool
+	// If true, the app is initialized with --from-code, which accepts the detected defaults in no-prompt mode, such as
+	// exposing all the services of an Aspire app host to the Internet.
+	FromCode bool
+}
+
+// genDockerfiles generates a Dockerfile for each Go, Rust, PHP and Ruby service that does not already have one, and
+// updates the detected service to use it. When generateAll is true, a Dockerfile and a .dockerignore file are also
+// generated for each Python, JavaScript, TypeScript and Java service without a Dockerfile. The user is prompted before
+// existing files are overwritten.
+//
+// Go, Rust, PHP and Ruby services are always packaged as a container. The generated Dockerfile builds the service and
+// exposes the port in spec, which must be indexed in the same order as detect.Services.
+func (i *Initializer) genDockerfiles(
+	ctx context.Context,
+	t *template.Template,
+	azdCtx *azdcontext.AzdContext,
+	detect *detectConfirm,
+	spec scaffold.InfraSpec,
+	generateAll bool) error {
+	for idx, prj := range detect.Services {
+		if prj.Docker != nil {
+			continue
+		}
+
+		port := spec.Services[idx].Port
+		var templateName string
+		var dockerfile any
+		var dockerignore []string
+		var err error
+		switch prj.Language {
+		case appdetect.Go:
+			templateName = "go.Dockerfile"
+			dockerfile, err = goDockerfileFromDetect(prj, port)
+		case appdetect.Rust:
+			templateName = "rust.Dockerfile"
+			dockerfile, err = rustDockerfileFromDetect(prj, port)
+		case appdetect.Php:
+			templateName = "php.Dockerfile"
+			dockerfile, err = phpDockerfileFromDetect(prj, port)
+		case appdetect.Ruby:
+			templateName = "ruby.Dockerfile"
+			dockerfile, err = rubyDockerfileFromDetect(prj, port)
+			dockerignore = []string{".bundle", "log/*", "tmp/*", "node_modules", ".env"}
+		case appdetect.Python:
+			if !generateAll {
+				continue
+			}
+
+			templateName = "python.Dockerfile"
+			dockerfile, err = pythonDockerfileFromDetect(prj, port)
+			dockerignore = []string{"__pycache__", "*.pyc", ".venv", "venv", ".env"}
+		case appdetect.JavaScript, appdetect.TypeScript:
+			if !generateAll {
+				continue
+			}
+
+			templateName = "node.Dockerfile"
+			dockerfile = nodeDockerfileFromDetect(prj, port)
+			dockerignore = []string{"node_modules", "npm-debug.log*", ".env"}
+		case appdetect.Java:
+			if !generateAll {
+				continue
+			}
+
+			templateName = "java.Dockerfile"
+			dockerfile, err = javaDockerfileFromDetect(prj, port)
+			dockerignore = []string{"target"}
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		files := []string{"Dockerfile"}
+		if dockerignore != nil {
+			files = append(files, ".dockerignore")
+		}
+
+		err = i.genServiceFiles(ctx, prj.Path, func(staging string) error {
+			err := scaffold.Execute(t, templateName, dockerfile, filepath.Join(staging, "Dockerfile"))
+			if err != nil {
+				return err
+			}
+
+			if dockerignore != nil {
+				return scaffold.Execute(t, "dockerignore", dockerignore, filepath.Join(staging, ".dockerignore"))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("generating Dockerfile for %s: %w", prj.Path, err)
+		}
+
+		dockerPath := filepath.Join(prj.Path, "Dockerfile")
+		detect.Services[idx].Docker = &appdetect.Docker{Path: dockerPath}
+
+		for _, file := range files {
+			rel, err := filepath.Rel(azdCtx.ProjectDirectory(), filepath.Join(prj.Path, file))
+			if err != nil {
+				return err
+			}
+
+			i.console.MessageUxItem(ctx, &ux.DoneMessage{
+				Message: "Generating " + output.WithHighLightFormat("./"+filepath.ToSlash(rel)),
+			})
+		}
+	}
+
+	return nil
+}
+
+// genServiceFiles writes the files generated by gen into the directory of a service. The files are generated in a
+// staging directory first, and the user is prompted before files already present in the service directory are
+// overwritten.
+func (i *Initializer) genServiceFiles(ctx context.Context, target string, gen func(staging string) error) error {
+	staging, err := os.MkdirTemp("", "azd-service")
+	if err != nil {
+		return fmt.Errorf("mkdir temp: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	if err := gen(staging); err != nil {
+		return err
+	}
+
+	skipStagingFiles, err := i.promptForDuplicates(ctx, staging, target)
+	if err != nil {
+		return err
+	}
+
+	options := copy.Options{}
+	if skipStagingFiles != nil {
+		options.Skip = func(fileInfo os.FileInfo, src, dest string) (bool, error) {
+			_, skip := skipStagingFiles[src]
+			return skip, nil
+		}
+	}
+
+	if err := copy.Copy(staging, target, options); err != nil {
+		return fmt.Errorf("copying contents from temp staging directory: %w", err)
+	}
+
+	// the written files may be nested in directories whose entries are unchanged, which the detection cache doesn't
+	// observe, so the app is detected from scratch the next time it is initialized
+	i.ResetDetectionCache()
+	return nil
+}
+
+func goDockerfileFromDetect(prj appdetect.Project, port int) (scaffold.GoDockerfile, error) {
+	mainPackage, err := appdetect.GoMainPackage(prj.Path)
+	if err != nil {
+		return scaffold.GoDockerfile{}, fmt.Errorf("finding main package in %s: %w", prj.Path, err)
+	}
+
+	if mainPackage == "" {
+		return scaffold.GoDockerfile{}, fmt.Errorf(
+			"no main package found in %s. Add a Dockerfile to the directory to specify how the app is built",
+			prj.Path)
+	}
+
+	if mainPackage != "." {
+		mainPackage = "./" + mainPackage
+	}
+
+	goVersion, err := appdetect.GoVersion(prj.Path)
+	if err != nil {
+		return scaffold.GoDockerfile{}, fmt.Errorf("reading go version in %s: %w", prj.Path, err)
+	}
+
+	if goVersion == "" {
+		// the latest release of Go 1
+		goVersion = "1"
+	}
+
+	return scaffold.GoDockerfile{
+		GoVersion:   goVersion,
+		MainPackage: mainPackage,
+		Port:        port,
+	}, nil
+}
+
+func rustDockerfileFromDetect(prj appdetect.Project, port int) (scaffold.RustDockerfile, error) {
+	binaryName, err := appdetect.RustBinaryName(prj.Path)
+	if err != nil {
+		return scaffold.RustDockerfile{}, fmt.Errorf("reading binary name in %s: %w", prj.Path, err)
+	}
+
+	if binaryName == "" {
+		return scaffold.RustDockerfile{}, fmt.Errorf(
+			"no binary found in %s. Add a Dockerfile to the directory to specify how the app is built",
+			prj.Path)
+	}
+
+	return scaffold.RustDockerfile{
+		BinaryName: binaryName,
+		Port:       port,
+	}, nil
+}
+
+func phpDockerfileFromDetect(prj appdetect.Project, port int) (scaffold.PhpDockerfile, error) {
+	documentRoot, err := appdetect.PhpDocumentRoot(prj.Path)
+	if err != nil {
+		return scaffold.PhpDockerfile{}, fmt.Errorf("detecting document root in %s: %w", prj.Path, err)
+	}
+
+	if port <= 0 {
+		// the port of the default builder
+		port = 80
+	}
+
+	dockerfile := scaffold.PhpDockerfile{
+		DocumentRoot: documentRoot,
+		Port:         port,
+	}
+
+	for _, db := range prj.DatabaseDeps {
+		switch db {
+		case appdetect.DbPostgres:
+			dockerfile.Extensions = append(dockerfile.Extensions, "pdo_pgsql")
+			dockerfile.Packages = append(dockerfile.Packages, "libpq-dev")
+		case appdetect.DbMySql:
+			dockerfile.Extensions = append(dockerfile.Extensions, "pdo_mysql")
+		}
+	}
+
+	return dockerfile, nil
+}
+
+// rubyDockerfileFromDetect returns the data used to generate a Dockerfile for a Ruby service, which is served with Puma.
+// The assets of Rails apps with an asset pipeline are precompiled when the image is built.
+func rubyDockerfileFromDetect(prj appdetect.Project, port int) (scaffold.RubyDockerfile, error) {
+	rubyVersion, err := appdetect.RubyVersion(prj.Path)
+	if err != nil {
+		return scaffold.RubyDockerfile{}, fmt.Errorf("reading ruby version in %s: %w", prj.Path, err)
+	}
+
+	if rubyVersion == "" {
+		// the latest stable Ruby release
+		rubyVersion = "3.3"
+	}
+
+	if port <= 0 {
+		// the default port of Rails apps
+		port = 3000
+	}
+
+	dockerfile := scaffold.RubyDockerfile{
+		RubyVersion: rubyVersion,
+		Port:        port,
+	}
+
+	if slices.Contains(prj.Dependencies, appdetect.RubyRails) {
+		dockerfile.PrecompileAssets, dockerfile.Node, err = appdetect.RailsAssets(prj.Path)
+		if err != nil {
+			return scaffold.RubyDockerfile{}, fmt.Errorf("detecting assets in %s: %w", prj.Path, err)
+		}
+	}
+
+	for _, db := range prj.DatabaseDeps {
+		switch db {
+		case appdetect.DbPostgres:
+			dockerfile.BuildPackages = append(dockerfile.BuildPackages, "libpq-dev")
+			dockerfile.Packages = append(dockerfile.Packages, "libpq5")
+		case appdetect.DbMySql:
+			dockerfile.BuildPackages = append(dockerfile.BuildPackages, "default-libmysqlclient-dev")
+			dockerfile.Packages = append(dockerfile.Packages, "libmariadb3")
+		}
+	}
+
+	return dockerfile, nil
+}
+
+// pythonDockerfileFromDetect returns the data used to generate a Dockerfile for a Python service. The app is served with
+// uvicorn for FastAPI, and with gunicorn for Django and Flask. Otherwise, the main.py or app.py script of the project is
+// run. The dependencies are installed with the detected package manager, or with pip from requirements.txt.
+func pythonDockerfileFromDetect(prj appdetect.Project, port int) (scaffold.PythonDockerfile, error) {
+	if port <= 0 {
+		// the port of the default builder
+		port = 80
+	}
+
+	dockerfile := scaffold.PythonDockerfile{
+		Port: port,
+	}
+
+	if prj.PythonPackageManager != nil {
+		if prj.PythonPackageManager.Kind != python.PackageManagerPip {
+			dockerfile.PackageManager = string(prj.PythonPackageManager.Kind)
+		}
+		dockerfile.ManifestFile = filepath.Base(prj.PythonPackageManager.ManifestPath)
+		if prj.PythonPackageManager.LockFilePath != "" {
+			dockerfile.LockFile = filepath.Base(prj.PythonPackageManager.LockFilePath)
+		}
+	}
+
+	bind := fmt.Sprintf("0.0.0.0:%d", port)
+
+	for _, dep := range prj.Dependencies {
+		switch dep {
+		case appdetect.PyFastApi:
+			launch, err := appdetect.PyFastApiLaunch(prj.Path)
+			if err != nil {
+				return scaffold.PythonDockerfile{}, fmt.Errorf("finding FastAPI app in %s: %w", prj.Path, err)
+			}
+
+			if launch != "" {
+				dockerfile.Packages = []string{"uvicorn"}
+				dockerfile.Command = []string{"uvicorn", launch, "--host", "0.0.0.0", "--port", strconv.Itoa(port)}
+				return dockerfile, nil
+			}
+		case appdetect.PyDjango:
+			module, err := appdetect.PyDjangoWsgiModule(prj.Path)
+			if err != nil {
+				return scaffold.PythonDockerfile{}, fmt.Errorf("finding Django WSGI module in %s: %w", prj.Path, err)
+			}
+
+			if module != "" {
+				dockerfile.Packages = []string{"gunicorn"}
+				dockerfile.Command = []string{"gunicorn", "--bind", bind, module}
+				return dockerfile, nil
+			}
+		case appdetect.PyFlask:
+			if _, err := os.Stat(filepath.Join(prj.Path, "app.py")); err == nil {
+				dockerfile.Packages = []string{"gunicorn"}
+				dockerfile.Command = []string{"gunicorn", "--bind", bind, "app:app"}
+				return dockerfile, nil
+			}
+		}
+	}
+
+	for _, script := range []string{"main.py", "app.py"} {
+		if _, err := os.Stat(filepath.Join(prj.Path, script)); err == nil {
+			dockerfile.Command = []string{"python", script}
+			return dockerfile, nil
+		}
+	}
+
+	return scaffold.PythonDockerfile{}, fmt.Errorf(
+		"no entrypoint found in %s. Add a Dockerfile to the directory to specify how the app is run",
+		prj.Path)
+}
+
+// nodeDockerfileFromDetect returns the data used to generate a Dockerfile for a JavaScript or TypeScript service, using
+// the detected package manager. The static files of web UI frameworks are served by nginx.
+func nodeDockerfileFromDetect(prj appdetect.Project, port int) scaffold.NodeDockerfile {
+	if port <= 0 {
+		// the port of the default builder
+		port = 80
+	}
+
+	dockerfile := scaffold.NodeDockerfile{
+		PackageManager: string(npm.PackageManagerNpm),
+		Port:           port,
+	}
+
+	if prj.PackageManager != nil {
+		dockerfile.PackageManager = string(prj.PackageManager.Kind)
+		dockerfile.LockFile = filepath.Base(prj.PackageManager.LockFilePath)
+	}
+
+	if prj.HasWebUIFramework() {
+		dockerfile.OutputPath = webOutputPath(prj, filepath.Base(prj.Path))
+	}
+
+	return dockerfile
+}
+
+// javaDockerfileFromDetect returns the data used to generate a Dockerfile for a Java service built with Maven.
+func javaDockerfileFromDetect(prj appdetect.Project, port int) (scaffold.JavaDockerfile, error) {
+	if port <= 0 {
+		// the port of the default builder
+		port = 80
+	}
+
+	javaVersion, err := appdetect.JavaVersion(prj.Path)
+	if err != nil {
+		return scaffold.JavaDockerfile{}, fmt.Errorf("reading java version in %s: %w", prj.Path, err)
+	}
+
+	if javaVersion == "" {
+		// the oldest Java LTS release supported by current frameworks such as Spring Boot 3
+		javaVersion = "17"
+	}
+
+	_, err = os.Stat(filepath.Join(prj.Path, "mvnw"))
+	mavenWrapper := err == nil
+
+	stat, err := os.Stat(filepath.Join(prj.Path, ".mvn"))
+	mavenWrapperConfig := err == nil && stat.IsDir()
+
+	return scaffold.JavaDockerfile{
+		JavaVersion:        javaVersion,
+		MavenWrapper:       mavenWrapper,
+		MavenWrapperConfig: mavenWrapperConfig,
+		Port:               port,
+	}, nil
+}
+
 const InitGenTemplateId = "azd-init"
 
 func prjConfigFromDetect(
@@ -374,24 +1058,7 @@ func prjConfigFromDetect(
 		}
 
 		if prj.HasWebUIFramework() {
-			// By default, use 'dist'. This is common for frameworks such as:
-			// - TypeScript
-			// - Vue.js
-			svc.OutputPath = "dist"
-
-		loop:
-			for _, dep := range prj.Dependencies {
-				switch dep {
-				case appdetect.JsReact:
-					// react uses 'build'
-					svc.OutputPath = "build"
-					break loop
-				case appdetect.JsAngular:
-					// angular uses dist/<project name>
-					svc.OutputPath = "dist/" + filepath.Base(rel)
-					break loop
-				}
-			}
+			svc.OutputPath = webOutputPath(prj, filepath.Base(rel))
 		}
 
 		name := filepath.Base(rel)
@@ -403,3 +1070,23 @@ func prjConfigFromDetect(
 
 	return config, nil
 }
+
+// webOutputPath returns the directory of the static files built for a project with a web UI framework, relative to the
+// project directory. projectName is the name of the project, which angular uses in the output path.
+func webOutputPath(prj appdetect.Project, projectName string) string {
+	for _, dep := range prj.Dependencies {
+		switch dep {
+		case appdetect.JsReact:
+			// react uses 'build'
+			return "build"
+		case appdetect.JsAngular:
+			// angular uses dist/<project name>
+			return "dist/" + projectName
+		}
+	}
+
+	// By default, use 'dist'. This is common for frameworks such as:
+	// - TypeScript
+	// - Vue.js
+	return "dist"
+}
@@ -0,0 +1,507 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_phpDockerfileFromDetect(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "public"), 0755))
+
+	prj := appdetect.Project{
+		Language:     appdetect.Php,
+		Path:         dir,
+		Dependencies: []appdetect.Dependency{appdetect.PhpLaravel},
+		DatabaseDeps: []appdetect.DatabaseDep{appdetect.DbPostgres, appdetect.DbRedis},
+	}
+
+	dockerfile, err := phpDockerfileFromDetect(prj, -1)
+	require.NoError(t, err)
+	require.Equal(t, scaffold.PhpDockerfile{
+		DocumentRoot: "public",
+		Port:         80,
+		Extensions:   []string{"pdo_pgsql"},
+		Packages:     []string{"libpq-dev"},
+	}, dockerfile)
+
+	templates, err := scaffold.Load()
+	require.NoError(t, err)
+
+	dockerPath := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, scaffold.Execute(templates, "php.Dockerfile", dockerfile, dockerPath))
+
+	contents, err := os.ReadFile(dockerPath)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "RUN docker-php-ext-install pdo_pgsql\n")
+	require.Contains(t, string(contents), "'    listen 80;'")
+	require.Contains(t, string(contents), "'    root /var/www/html/public;'")
+	require.Contains(t, string(contents), "EXPOSE 80\n")
+}
+
+func Test_rubyDockerfileFromDetect(t *testing.T) {
+	dir := t.TempDir()
+	gemfile := "source \"https://rubygems.org\"\n\nruby \"3.2.2\"\n\ngem \"rails\"\ngem \"propshaft\"\ngem \"pg\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Gemfile"), []byte(gemfile), 0600))
+
+	prj := appdetect.Project{
+		Language:     appdetect.Ruby,
+		Path:         dir,
+		Dependencies: []appdetect.Dependency{appdetect.RubyRails},
+		DatabaseDeps: []appdetect.DatabaseDep{appdetect.DbPostgres},
+	}
+
+	dockerfile, err := rubyDockerfileFromDetect(prj, 3000)
+	require.NoError(t, err)
+	require.Equal(t, scaffold.RubyDockerfile{
+		RubyVersion:      "3.2.2",
+		Port:             3000,
+		PrecompileAssets: true,
+		BuildPackages:    []string{"libpq-dev"},
+		Packages:         []string{"libpq5"},
+	}, dockerfile)
+
+	templates, err := scaffold.Load()
+	require.NoError(t, err)
+
+	dockerPath := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, scaffold.Execute(templates, "ruby.Dockerfile", dockerfile, dockerPath))
+
+	contents, err := os.ReadFile(dockerPath)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "FROM ruby:3.2.2-slim AS build\n")
+	require.Contains(t, string(contents), "build-essential git libpq-dev \\\n")
+	require.Contains(t, string(contents), "RUN SECRET_KEY_BASE_DUMMY=1 bundle exec rails assets:precompile\n")
+	require.Contains(t, string(contents), "--no-install-recommends libpq5 \\\n")
+	require.Contains(t, string(contents), `CMD ["bundle", "exec", "puma", "-b", "tcp://0.0.0.0:3000"]`)
+	require.NotContains(t, string(contents), "nodejs")
+}
+
+func Test_pythonDockerfileFromDetect(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.py"), []byte("app = Flask(__name__)\n"), 0600))
+
+	prj := appdetect.Project{
+		Language:     appdetect.Python,
+		Path:         dir,
+		Dependencies: []appdetect.Dependency{appdetect.PyFlask},
+	}
+
+	dockerfile, err := pythonDockerfileFromDetect(prj, 80)
+	require.NoError(t, err)
+	require.Equal(t, scaffold.PythonDockerfile{
+		Packages: []string{"gunicorn"},
+		Command:  []string{"gunicorn", "--bind", "0.0.0.0:80", "app:app"},
+		Port:     80,
+	}, dockerfile)
+
+	templates, err := scaffold.Load()
+	require.NoError(t, err)
+
+	dockerPath := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, scaffold.Execute(templates, "python.Dockerfile", dockerfile, dockerPath))
+
+	contents, err := os.ReadFile(dockerPath)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "RUN pip install --no-cache-dir -r requirements.txt gunicorn\n")
+	require.Contains(t, string(contents), "CMD [\"gunicorn\", \"--bind\", \"0.0.0.0:80\", \"app:app\"]\n")
+
+	prj.Dependencies = nil
+	_, err = pythonDockerfileFromDetect(prj, 80)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "app.py")))
+	_, err = pythonDockerfileFromDetect(prj, 80)
+	require.Error(t, err)
+}
+
+func Test_pythonDockerfileFromDetect_PackageManager(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.py"), []byte("app = Flask(__name__)\n"), 0600))
+
+	tests := []struct {
+		name           string
+		packageManager *appdetect.PythonPackageManager
+		want           []string
+	}{
+		{
+			name: "Poetry",
+			packageManager: &appdetect.PythonPackageManager{
+				Kind:         python.PackageManagerPoetry,
+				ManifestPath: filepath.Join(dir, "pyproject.toml"),
+				LockFilePath: filepath.Join(dir, "poetry.lock"),
+			},
+			want: []string{
+				"COPY pyproject.toml poetry.lock ./\n",
+				"&& poetry install --only main --no-root --no-interaction --no-ansi\n",
+				"RUN pip install --no-cache-dir gunicorn\n",
+			},
+		},
+		{
+			name: "Pipenv",
+			packageManager: &appdetect.PythonPackageManager{
+				Kind:         python.PackageManagerPipenv,
+				ManifestPath: filepath.Join(dir, "Pipfile"),
+			},
+			want: []string{
+				"COPY Pipfile ./\n",
+				"RUN pipenv install --system --skip-lock\n",
+			},
+		},
+		{
+			name: "Uv",
+			packageManager: &appdetect.PythonPackageManager{
+				Kind:         python.PackageManagerUv,
+				ManifestPath: filepath.Join(dir, "pyproject.toml"),
+				LockFilePath: filepath.Join(dir, "uv.lock"),
+			},
+			want: []string{
+				"COPY pyproject.toml uv.lock ./\n",
+				"RUN uv export --frozen --no-dev --no-emit-project --no-hashes -o requirements.txt",
+			},
+		},
+		{
+			name: "PipPyproject",
+			packageManager: &appdetect.PythonPackageManager{
+				Kind:         python.PackageManagerPip,
+				ManifestPath: filepath.Join(dir, "pyproject.toml"),
+			},
+			want: []string{
+				"COPY . .\nRUN pip install --no-cache-dir . gunicorn\nEXPOSE 80\n",
+			},
+		},
+	}
+
+	templates, err := scaffold.Load()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prj := appdetect.Project{
+				Language:             appdetect.Python,
+				Path:                 dir,
+				Dependencies:         []appdetect.Dependency{appdetect.PyFlask},
+				PythonPackageManager: tt.packageManager,
+			}
+
+			dockerfile, err := pythonDockerfileFromDetect(prj, 80)
+			require.NoError(t, err)
+
+			dockerPath := filepath.Join(t.TempDir(), "Dockerfile")
+			require.NoError(t, scaffold.Execute(templates, "python.Dockerfile", dockerfile, dockerPath))
+
+			contents, err := os.ReadFile(dockerPath)
+			require.NoError(t, err)
+			for _, want := range tt.want {
+				require.Contains(t, string(contents), want)
+			}
+			require.Contains(t, string(contents), "CMD [\"gunicorn\", \"--bind\", \"0.0.0.0:80\", \"app:app\"]\n")
+		})
+	}
+}
+
+func Test_nodeDockerfileFromDetect(t *testing.T) {
+	dir := t.TempDir()
+	prj := appdetect.Project{
+		Language:     appdetect.JavaScript,
+		Path:         filepath.Join(dir, "web"),
+		Dependencies: []appdetect.Dependency{appdetect.JsReact},
+		PackageManager: &appdetect.PackageManager{
+			Kind:         npm.PackageManagerYarn,
+			LockFilePath: filepath.Join(dir, "web", "yarn.lock"),
+		},
+	}
+
+	dockerfile := nodeDockerfileFromDetect(prj, 80)
+	require.Equal(t, scaffold.NodeDockerfile{
+		PackageManager: "yarn",
+		LockFile:       "yarn.lock",
+		OutputPath:     "build",
+		Port:           80,
+	}, dockerfile)
+
+	templates, err := scaffold.Load()
+	require.NoError(t, err)
+
+	dockerPath := filepath.Join(dir, "Dockerfile")
+	require.NoError(t, scaffold.Execute(templates, "node.Dockerfile", dockerfile, dockerPath))
+
+	contents, err := os.ReadFile(dockerPath)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "RUN yarn install --frozen-lockfile\n")
+	require.Contains(t, string(contents), "COPY --from=build /app/build /usr/share/nginx/html\n")
+
+	prj.Dependencies = nil
+	prj.PackageManager = nil
+	require.Equal(t, scaffold.NodeDockerfile{
+		PackageManager: "npm",
+		Port:           80,
+	}, nodeDockerfileFromDetect(prj, 80))
+}
+
+func Test_javaDockerfileFromDetect(t *testing.T) {
+	tests := map[string]struct {
+		mvnDir bool
+	}{
+		"MavenWrapper":           {},
+		"MavenWrapperWithMvnDir": {mvnDir: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			pom := "<project><properties><java.version>21</java.version></properties></project>"
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(pom), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "mvnw"), []byte{}, 0700))
+			if tt.mvnDir {
+				require.NoError(t, os.Mkdir(filepath.Join(dir, ".mvn"), 0700))
+			}
+
+			dockerfile, err := javaDockerfileFromDetect(appdetect.Project{Language: appdetect.Java, Path: dir}, 80)
+			require.NoError(t, err)
+			require.Equal(t, scaffold.JavaDockerfile{
+				JavaVersion:        "21",
+				MavenWrapper:       true,
+				MavenWrapperConfig: tt.mvnDir,
+				Port:               80,
+			}, dockerfile)
+
+			templates, err := scaffold.Load()
+			require.NoError(t, err)
+
+			dockerPath := filepath.Join(dir, "Dockerfile")
+			require.NoError(t, scaffold.Execute(templates, "java.Dockerfile", dockerfile, dockerPath))
+
+			contents, err := os.ReadFile(dockerPath)
+			require.NoError(t, err)
+			require.Contains(t, string(contents), "FROM maven:3-eclipse-temurin-21 AS build\n")
+			require.Contains(t, string(contents), "RUN ./mvnw -B package -DskipTests\n")
+			require.Contains(t, string(contents), "ENV SERVER_PORT=80\n")
+
+			// copying a missing .mvn directory fails the build
+			if tt.mvnDir {
+				require.Contains(t, string(contents), "COPY .mvn .mvn\n")
+			} else {
+				require.NotContains(t, string(contents), ".mvn")
+			}
+		})
+	}
+}
+
+func TestInitializer_genDockerfiles(t *testing.T) {
+	dir := t.TempDir()
+	apiDir := filepath.Join(dir, "api")
+	require.NoError(t, os.Mkdir(apiDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(apiDir, "main.py"), []byte{}, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(apiDir, ".dockerignore"), []byte("custom\n"), 0600))
+
+	i := &Initializer{
+		console: input.NewConsole(
+			false,
+			false,
+			false,
+			os.Stdout,
+			input.ConsoleHandles{
+				Stderr: os.Stderr,
+				// keep the existing .dockerignore file
+				Stdin:  strings.NewReader("Keep my existing files unchanged\n"),
+				Stdout: os.Stdout,
+			},
+			nil),
+	}
+
+	templates, err := scaffold.Load()
+	require.NoError(t, err)
+
+	azdCtx := azdcontext.NewAzdContextWithDirectory(dir)
+	spec := scaffold.InfraSpec{Services: []scaffold.ServiceSpec{{Name: "api", Port: 80}}}
+	detect := detectConfirm{Services: []appdetect.Project{{Language: appdetect.Python, Path: apiDir}}}
+
+	// Python services are built with buildpacks unless Dockerfile generation is opted into
+	require.NoError(t, i.genDockerfiles(context.Background(), templates, azdCtx, &detect, spec, false))
+	require.Nil(t, detect.Services[0].Docker)
+	require.NoFileExists(t, filepath.Join(apiDir, "Dockerfile"))
+
+	err = i.genDockerfiles(context.Background(), templates, azdCtx, &detect, spec, true)
+
+	// Print extra newline to avoid mangling `go test -v` final test result output while waiting for final stdin,
+	// which may result in incorrect `gotestsum` reporting
+	fmt.Println()
+
+	require.NoError(t, err)
+	require.Equal(t, &appdetect.Docker{Path: filepath.Join(apiDir, "Dockerfile")}, detect.Services[0].Docker)
+	require.FileExists(t, filepath.Join(apiDir, "Dockerfile"))
+
+	dockerignore, err := os.ReadFile(filepath.Join(apiDir, ".dockerignore"))
+	require.NoError(t, err)
+	require.Equal(t, "custom\n", string(dockerignore))
+}
+
+func Test_excludeInAzdIgnore(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, azdIgnoreFileName), []byte("/node_modules/\n/api/"), 0600)
+	require.NoError(t, err)
+
+	excluded := []appdetect.Project{
+		{Language: appdetect.Python, Path: filepath.Join(dir, "api")},
+		{Language: appdetect.JavaScript, Path: filepath.Join(dir, "src", "web")},
+		{Language: appdetect.DotNet, Path: dir},
+	}
+
+	updated, err := excludeInAzdIgnore(dir, excluded)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	contents, err := os.ReadFile(filepath.Join(dir, azdIgnoreFileName))
+	require.NoError(t, err)
+	require.Equal(t,
+		"/node_modules/\n/api/\n\n# Services excluded when initializing the app with azd init\n/src/web/\n",
+		string(contents))
+
+	// excluding the same services again leaves the file unchanged
+	updated, err = excludeInAzdIgnore(dir, excluded)
+	require.NoError(t, err)
+	require.False(t, updated)
+}
+
+func TestInitializer_genServiceFiles_ResetsDetectionCache(t *testing.T) {
+	dir := t.TempDir()
+	apiDir := filepath.Join(dir, "api")
+	require.NoError(t, os.Mkdir(apiDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(apiDir, "main.py"), []byte{}, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(apiDir, "requirements.txt"), []byte{}, 0600))
+
+	i := &Initializer{
+		console: input.NewConsole(
+			false,
+			false,
+			false,
+			os.Stdout,
+			input.ConsoleHandles{
+				Stderr: os.Stderr,
+				Stdin:  strings.NewReader(""),
+				Stdout: os.Stdout,
+			},
+			nil),
+		detectCache: appdetect.NewCache(),
+	}
+
+	detect := func() {
+		projects, err := appdetect.Detect(context.Background(), dir, appdetect.WithCache(i.detectCache))
+		require.NoError(t, err)
+		require.Len(t, projects, 1)
+	}
+
+	detect()
+	detect()
+	saved := i.detectCache.Saved()
+	require.Positive(t, saved)
+
+	err := i.genServiceFiles(context.Background(), filepath.Join(apiDir, "app"), func(staging string) error {
+		return os.WriteFile(filepath.Join(staging, "settings.py"), []byte{}, 0600)
+	})
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(apiDir, "app", "settings.py"))
+
+	// the cached results were removed, so no detection results are reused
+	detect()
+	require.Equal(t, saved, i.detectCache.Saved())
+}
+
+func TestInitializer_selectAppHost(t *testing.T) {
+	root := t.TempDir()
+	storeHost := appdetect.Project{Language: appdetect.DotNetAppHost, Path: filepath.Join(root, "store", "AppHost")}
+	blogHost := appdetect.Project{Language: appdetect.DotNetAppHost, Path: filepath.Join(root, "blog", "AppHost")}
+
+	projectManifest := func(paths map[string]string) *apphost.Manifest {
+		manifest := &apphost.Manifest{Resources: map[string]*apphost.Resource{}}
+		for name, path := range paths {
+			path := path
+			manifest.Resources[name] = &apphost.Resource{Type: "project.v0", Path: &path}
+		}
+		return manifest
+	}
+	appHostForProject := projectAppHosts(map[string]*apphost.Manifest{
+		storeHost.Path: projectManifest(map[string]string{
+			"api": filepath.Join(root, "store", "Api", "Api.csproj"),
+			"web": filepath.Join(root, "store", "Web", "Web.csproj"),
+		}),
+		blogHost.Path: projectManifest(map[string]string{
+			"blog": filepath.Join(root, "blog", "Blog", "Blog.csproj"),
+		}),
+	})
+
+	i := &Initializer{
+		console: input.NewConsole(
+			false,
+			false,
+			false,
+			os.Stdout,
+			input.ConsoleHandles{
+				Stderr: os.Stderr,
+				Stdin:  strings.NewReader(relSafe(root, blogHost.Path) + "\n"),
+				Stdout: os.Stdout,
+			},
+			nil),
+	}
+
+	selected, err := i.selectAppHost(context.Background(), root, []appdetect.Project{storeHost, blogHost})
+
+	// Print extra newline to avoid mangling `go test -v` final test result output while waiting for final stdin,
+	// which may result in incorrect `gotestsum` reporting
+	fmt.Println()
+
+	require.NoError(t, err)
+	require.Equal(t, blogHost, selected)
+
+	// only the projects referenced by the selected app host are attributed to it
+	require.Equal(t, []string{filepath.Join(root, "blog", "Blog")}, appHostProjectDirs(appHostForProject, selected.Path))
+	require.Equal(t, []string{
+		filepath.Join(root, "store", "Api"),
+		filepath.Join(root, "store", "Web"),
+	}, appHostProjectDirs(appHostForProject, storeHost.Path))
+}
+
+func TestInitializer_selectAppHost_NoPrompt(t *testing.T) {
+	root := t.TempDir()
+	appHosts := []appdetect.Project{
+		{Language: appdetect.DotNetAppHost, Path: filepath.Join(root, "store")},
+		{Language: appdetect.DotNetAppHost, Path: filepath.Join(root, "blog")},
+	}
+
+	i := &Initializer{
+		console: input.NewConsole(true, false, false, os.Stdout, input.ConsoleHandles{
+			Stderr: os.Stderr,
+			Stdin:  strings.NewReader(""),
+			Stdout: os.Stdout,
+		}, nil),
+	}
+
+	_, err := i.selectAppHost(context.Background(), root, appHosts)
+	require.ErrorContains(t, err, "detected 2 Aspire app host projects (store, blog)")
+}
+
+func Test_projectAppHosts_SharedProject(t *testing.T) {
+	shared := "/src/Shared/Shared.csproj"
+	manifest := &apphost.Manifest{Resources: map[string]*apphost.Resource{
+		"shared": {Type: "project.v0", Path: &shared},
+	}}
+
+	// a project referenced by both app hosts is attributed to one of them only
+	appHostForProject := projectAppHosts(map[string]*apphost.Manifest{"/src/B": manifest, "/src/A": manifest})
+	require.Equal(t, map[string]string{"/src/Shared": "/src/A"}, appHostForProject)
+	require.Empty(t, appHostProjectDirs(appHostForProject, "/src/B"))
+}
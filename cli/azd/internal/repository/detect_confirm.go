@@ -40,11 +40,15 @@ const (
 	EntryKindModified EntryKind = "modified"
 )
 
-// detectConfirm handles prompting for confirming the detected services and databases
+// detectConfirm handles prompting for confirming the detected services, databases and messaging services
 type detectConfirm struct {
-	// detected services and databases
+	// detected services, databases and messaging services
 	Services  []appdetect.Project
 	Databases map[appdetect.DatabaseDep]EntryKind
+	Messaging map[appdetect.MessagingDep]EntryKind
+
+	// detected services that the user excluded from the app
+	Excluded []appdetect.Project
 
 	// the root directory of the project
 	root string
@@ -57,7 +61,9 @@ type detectConfirm struct {
 // Init initializes state from initial detection output
 func (d *detectConfirm) Init(projects []appdetect.Project, root string) {
 	d.Databases = make(map[appdetect.DatabaseDep]EntryKind)
+	d.Messaging = make(map[appdetect.MessagingDep]EntryKind)
 	d.Services = make([]appdetect.Project, 0, len(projects))
+	d.Excluded = nil
 	d.modified = false
 	d.root = root
 
@@ -71,6 +77,12 @@ func (d *detectConfirm) Init(projects []appdetect.Project, root string) {
 				d.Databases[dbType] = EntryKindDetected
 			}
 		}
+
+		for _, messagingType := range project.MessagingDeps {
+			if _, supported := messagingMap[messagingType]; supported {
+				d.Messaging[messagingType] = EntryKindDetected
+			}
+		}
 	}
 
 	d.captureUsage(
@@ -99,6 +111,7 @@ func (d *detectConfirm) captureUsage(
 
 // Confirm prompts the user to confirm the detected services and databases,
 // providing modifications to the detected services and databases.
+// In no-prompt mode, the detected services and databases are accepted as they are.
 func (d *detectConfirm) Confirm(ctx context.Context) error {
 	for {
 		if err := d.render(ctx); err != nil {
@@ -106,12 +119,20 @@ func (d *detectConfirm) Confirm(ctx context.Context) error {
 		}
 		d.modified = false
 
+		if d.console.IsNoPromptMode() {
+			d.captureUsage(
+				fields.AppInitConfirmedDatabases,
+				fields.AppInitConfirmedServices)
+			return nil
+		}
+
 		continueOption, err := d.console.Select(ctx, input.ConsoleOptions{
 			Message: "Select an option",
 			Options: []string{
 				"Confirm and continue initializing my app",
 				"Remove a detected service",
 				"Add an undetected service",
+				"Include or exclude detected services",
 			},
 		})
 		if err != nil {
@@ -142,6 +163,13 @@ func (d *detectConfirm) Confirm(ctx context.Context) error {
 			}
 
 			tracing.IncrementUsageAttribute(fields.AppInitModifyAddCount.Int(1))
+		case 3:
+			if err := d.toggle(ctx); err != nil {
+				if errors.Is(err, terminal.InterruptErr) {
+					continue
+				}
+				return err
+			}
 		}
 	}
 }
@@ -183,6 +211,10 @@ func (d *detectConfirm) render(ctx context.Context) error {
 			recommendedServices = append(recommendedServices, "Azure Database for PostgreSQL flexible server")
 		case appdetect.DbMongo:
 			recommendedServices = append(recommendedServices, "Azure CosmosDB API for MongoDB")
+		case appdetect.DbMySql:
+			recommendedServices = append(recommendedServices, "Azure Database for MySQL flexible server")
+		case appdetect.DbSqlServer:
+			recommendedServices = append(recommendedServices, "Azure SQL Database")
 		case appdetect.DbRedis:
 			recommendedServices = append(recommendedServices, "Azure Container Apps Redis add-on")
 		}
@@ -198,6 +230,23 @@ func (d *detectConfirm) render(ctx context.Context) error {
 		d.console.Message(ctx, "")
 	}
 
+	for messaging, entry := range d.Messaging {
+		switch messaging {
+		case appdetect.MessagingServiceBus:
+			recommendedServices = append(recommendedServices, "Azure Service Bus")
+		}
+
+		status := ""
+		if entry == EntryKindModified {
+			status = " " + output.WithSuccessFormat("[Updated]")
+		} else if entry == EntryKindManual {
+			status = " " + output.WithSuccessFormat("[Added]")
+		}
+
+		d.console.Message(ctx, "  "+color.BlueString(messaging.Display())+status)
+		d.console.Message(ctx, "")
+	}
+
 	displayedServices := make([]string, 0, len(recommendedServices))
 	for _, svc := range recommendedServices {
 		displayedServices = append(displayedServices, color.MagentaString(svc))
@@ -212,8 +261,89 @@ func (d *detectConfirm) render(ctx context.Context) error {
 	return nil
 }
 
+// toggle prompts the user to select the detected services to include in the app. The services that aren't selected are
+// moved to Excluded, along with the databases and messaging services that only they depend on.
+func (d *detectConfirm) toggle(ctx context.Context) error {
+	all := make([]appdetect.Project, 0, len(d.Services)+len(d.Excluded))
+	all = append(all, d.Services...)
+	all = append(all, d.Excluded...)
+	slices.SortFunc(all, func(a, b appdetect.Project) bool {
+		return a.Path < b.Path
+	})
+
+	options := make([]string, 0, len(all))
+	included := make([]string, 0, len(d.Services))
+	for _, svc := range all {
+		option := fmt.Sprintf("%s in %s", projectDisplayName(svc), relSafe(d.root, svc.Path))
+		options = append(options, option)
+
+		if slices.ContainsFunc(d.Services, func(p appdetect.Project) bool { return p.Path == svc.Path }) {
+			included = append(included, option)
+		}
+	}
+
+	selected, err := d.console.MultiSelect(ctx, input.ConsoleOptions{
+		Message:       "Select the services to include in your app",
+		Options:       options,
+		DefaultValue:  included,
+		MinSelections: 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	services := make([]appdetect.Project, 0, len(selected))
+	excluded := []appdetect.Project{}
+	changed := false
+	for i, svc := range all {
+		include := slices.Contains(selected, options[i])
+		if include {
+			services = append(services, svc)
+		} else {
+			excluded = append(excluded, svc)
+		}
+
+		if include != slices.Contains(included, options[i]) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	d.Services = services
+	d.Excluded = excluded
+
+	// keep only the databases and messaging services that the included services depend on
+	databases := make(map[appdetect.DatabaseDep]EntryKind)
+	messaging := make(map[appdetect.MessagingDep]EntryKind)
+	for _, svc := range d.Services {
+		for _, db := range svc.DatabaseDeps {
+			if entry, has := d.Databases[db]; has {
+				databases[db] = entry
+			} else if _, supported := dbMap[db]; supported {
+				databases[db] = EntryKindDetected
+			}
+		}
+
+		for _, m := range svc.MessagingDeps {
+			if entry, has := d.Messaging[m]; has {
+				messaging[m] = entry
+			} else if _, supported := messagingMap[m]; supported {
+				messaging[m] = EntryKindDetected
+			}
+		}
+	}
+
+	d.Databases = databases
+	d.Messaging = messaging
+	d.modified = true
+	return nil
+}
+
 func (d *detectConfirm) remove(ctx context.Context) error {
-	modifyOptions := make([]string, 0, len(d.Services)+len(d.Databases))
+	modifyOptions := make([]string, 0, len(d.Services)+len(d.Databases)+len(d.Messaging))
 	for _, svc := range d.Services {
 		modifyOptions = append(
 			modifyOptions, fmt.Sprintf("%s in %s", projectDisplayName(svc), relSafe(d.root, svc.Path)))
@@ -224,6 +354,11 @@ func (d *detectConfirm) remove(ctx context.Context) error {
 		modifyOptions = append(modifyOptions, db.Display())
 	}
 
+	displayMessaging := maps.Keys(d.Messaging)
+	for _, messaging := range displayMessaging {
+		modifyOptions = append(modifyOptions, messaging.Display())
+	}
+
 	i, err := d.console.Select(ctx, input.ConsoleOptions{
 		Message: "Select the service you want to remove",
 		Options: modifyOptions,
@@ -276,6 +411,34 @@ func (d *detectConfirm) remove(ctx context.Context) error {
 			}
 		}
 		d.modified = true
+	} else if i < len(d.Services)+len(d.Databases)+len(d.Messaging) {
+		messaging := displayMessaging[i-len(d.Services)-len(d.Databases)]
+
+		confirm, err := d.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Remove %s?", messaging.Display()),
+		})
+		if err != nil {
+			return err
+		}
+
+		if !confirm {
+			return nil
+		}
+
+		delete(d.Messaging, messaging)
+
+		for i := range d.Services {
+			for j, dependency := range d.Services[i].MessagingDeps {
+				if dependency == messaging {
+					d.Services[i].MessagingDeps = append(
+						d.Services[i].MessagingDeps[:j],
+						d.Services[i].MessagingDeps[j+1:]...)
+					d.Services[i].DetectionRule = string(EntryKindModified)
+				}
+			}
+		}
+		d.modified = true
 	}
 
 	return nil
@@ -304,8 +467,20 @@ func (d *detectConfirm) add(ctx context.Context) error {
 		return a.Display() < b.Display()
 	})
 
-	selections := make([]string, 0, len(languages)+len(frameworks)+len(databases))
-	entries := make([]any, 0, len(languages)+len(frameworks)+len(databases))
+	// only include messaging services not already added
+	allMessaging := maps.Keys(messagingMap)
+	messaging := make([]appdetect.MessagingDep, 0, len(allMessaging))
+	for _, m := range allMessaging {
+		if _, ok := d.Messaging[m]; !ok {
+			messaging = append(messaging, m)
+		}
+	}
+	slices.SortFunc(messaging, func(a, b appdetect.MessagingDep) bool {
+		return a.Display() < b.Display()
+	})
+
+	selections := make([]string, 0, len(languages)+len(frameworks)+len(databases)+len(messaging))
+	entries := make([]any, 0, len(languages)+len(frameworks)+len(databases)+len(messaging))
 
 	for _, lang := range languages {
 		selections = append(selections, fmt.Sprintf("%s\t%s", lang.Display(), "[Language]"))
@@ -322,6 +497,11 @@ func (d *detectConfirm) add(ctx context.Context) error {
 		entries = append(entries, db)
 	}
 
+	for _, m := range messaging {
+		selections = append(selections, fmt.Sprintf("%s\t%s", m.Display(), "[Messaging]"))
+		entries = append(entries, m)
+	}
+
 	// only apply tab-align if interactive
 	if d.console.IsSpinnerInteractive() {
 		formatted, err := tabWrite(selections, 3)
@@ -333,7 +513,7 @@ func (d *detectConfirm) add(ctx context.Context) error {
 	}
 
 	i, err := d.console.Select(ctx, input.ConsoleOptions{
-		Message: "Select a language or database to add",
+		Message: "Select a language, database or messaging service to add",
 		Options: selections,
 	})
 	if err != nil {
@@ -372,6 +552,28 @@ func (d *detectConfirm) add(ctx context.Context) error {
 		d.Services[idx].DetectionRule = string(EntryKindModified)
 		d.modified = true
 		return nil
+	case appdetect.MessagingDep:
+		messagingDep := entries[i].(appdetect.MessagingDep)
+		d.Messaging[messagingDep] = EntryKindManual
+
+		svcSelect := make([]string, 0, len(d.Services))
+		for _, svc := range d.Services {
+			svcSelect = append(svcSelect,
+				fmt.Sprintf("%s in %s", projectDisplayName(svc), filepath.Base(svc.Path)))
+		}
+
+		idx, err := d.console.Select(ctx, input.ConsoleOptions{
+			Message: "Select the service that uses this messaging service",
+			Options: svcSelect,
+		})
+		if err != nil {
+			return err
+		}
+
+		d.Services[idx].MessagingDeps = append(d.Services[idx].MessagingDeps, messagingDep)
+		d.Services[idx].DetectionRule = string(EntryKindModified)
+		d.modified = true
+		return nil
 	default:
 		log.Panic("unhandled entry type")
 	}
@@ -43,12 +43,19 @@ func (d *detectConfirmAppHost) captureUsage(
 
 // Confirm prompts the user to confirm the detected services and databases,
 // providing modifications to the detected services and databases.
+// In no-prompt mode, the detected app host is accepted as it is.
 func (d *detectConfirmAppHost) Confirm(ctx context.Context) error {
 	for {
 		if err := d.render(ctx); err != nil {
 			return err
 		}
 
+		if d.console.IsNoPromptMode() {
+			d.captureUsage(
+				fields.AppInitConfirmedServices)
+			return nil
+		}
+
 		continueOption, err := d.console.Select(ctx, input.ConsoleOptions{
 			Message: "Select an option",
 			Options: []string{
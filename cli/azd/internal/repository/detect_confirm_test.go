@@ -38,7 +38,9 @@ func Test_detectConfirm_confirm(t *testing.T) {
 		name         string
 		detection    []appdetect.Project
 		interactions []string
+		noPrompt     bool
 		want         []appdetect.Project
+		wantExcluded []appdetect.Project
 	}{
 		{
 			name: "confirm single",
@@ -187,11 +189,124 @@ func Test_detectConfirm_confirm(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add a messaging service",
+			detection: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+				},
+			},
+			interactions: []string{
+				"Add an undetected service",
+				fmt.Sprintf("%s\t%s", appdetect.MessagingServiceBus.Display(), "[Messaging]"),
+				fmt.Sprintf("%s in %s", appdetect.DotNet.Display(), "dotnet-dir"),
+				"Confirm and continue initializing my app",
+			},
+			want: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+					MessagingDeps: []appdetect.MessagingDep{
+						appdetect.MessagingServiceBus,
+					},
+					DetectionRule: string(EntryKindModified),
+				},
+			},
+		},
+		{
+			name: "exclude a service",
+			detection: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+					DatabaseDeps: []appdetect.DatabaseDep{
+						appdetect.DbPostgres,
+					},
+				},
+				{
+					Language: appdetect.Java,
+					Path:     javaDir,
+				},
+			},
+			interactions: []string{
+				"Include or exclude detected services",
+				"n",
+				"y",
+				"Confirm and continue initializing my app",
+			},
+			want: []appdetect.Project{
+				{
+					Language: appdetect.Java,
+					Path:     javaDir,
+				},
+			},
+			wantExcluded: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+					DatabaseDeps: []appdetect.DatabaseDep{
+						appdetect.DbPostgres,
+					},
+				},
+			},
+		},
+		{
+			name: "remove a messaging service",
+			detection: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+					MessagingDeps: []appdetect.MessagingDep{
+						appdetect.MessagingServiceBus,
+					},
+				},
+			},
+			interactions: []string{
+				"Remove a detected service",
+				appdetect.MessagingServiceBus.Display(),
+				"y",
+				"Confirm and continue initializing my app",
+			},
+			want: []appdetect.Project{
+				{
+					Language:      appdetect.DotNet,
+					Path:          dotNetDir,
+					MessagingDeps: []appdetect.MessagingDep{},
+					DetectionRule: string(EntryKindModified),
+				},
+			},
+		},
+		{
+			name: "no prompt accepts detected",
+			detection: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+				},
+				{
+					Language: appdetect.Java,
+					Path:     javaDir,
+				},
+			},
+			noPrompt: true,
+			want: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     dotNetDir,
+				},
+				{
+					Language: appdetect.Java,
+					Path:     javaDir,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := &detectConfirm{
 				console: input.NewConsole(
+					tt.noPrompt,
 					false,
 					false,
 					os.Stdout,
@@ -212,6 +327,7 @@ func Test_detectConfirm_confirm(t *testing.T) {
 
 			require.NoError(t, err)
 			require.Equal(t, tt.want, d.Services)
+			require.Equal(t, tt.wantExcluded, d.Excluded)
 		})
 	}
 }
@@ -17,6 +17,9 @@ import (
 // A regex that matches against "likely" well-formed database names
 var wellFormedDbNameRegex = regexp.MustCompile(`^[a-zA-Z\-_0-9]*$`)
 
+// A regex that matches the characters that aren't allowed by wellFormedDbNameRegex
+var invalidNameCharRegex = regexp.MustCompile(`[^a-zA-Z\-_0-9]+`)
+
 // infraSpecFromDetect creates an InfraSpec from the results of app detection confirmation,
 // prompting for additional inputs if necessary.
 func (i *Initializer) infraSpecFromDetect(
@@ -28,6 +31,12 @@ func (i *Initializer) infraSpecFromDetect(
 			continue
 		}
 
+		if i.console.IsNoPromptMode() {
+			// the database is named after the app, there is no one to confirm another name
+			setDatabaseName(&spec, database, defaultResourceName(detect.root))
+			continue
+		}
+
 	dbPrompt:
 		for {
 			dbName, err := i.console.Prompt(ctx, input.ConsoleOptions{
@@ -72,24 +81,25 @@ func (i *Initializer) infraSpecFromDetect(
 				}
 			}
 
-			switch database {
-			case appdetect.DbMongo:
-				spec.DbCosmosMongo = &scaffold.DatabaseCosmosMongo{
-					DatabaseName: dbName,
-				}
+			if database != appdetect.DbMongo && dbName == "" {
+				i.console.Message(ctx, "Database name is required.")
+				continue
+			}
 
-				break dbPrompt
-			case appdetect.DbPostgres:
-				if dbName == "" {
-					i.console.Message(ctx, "Database name is required.")
-					continue
-				}
+			setDatabaseName(&spec, database, dbName)
+			break dbPrompt
+		}
+	}
 
-				spec.DbPostgres = &scaffold.DatabasePostgres{
-					DatabaseName: dbName,
-				}
+	for messaging := range detect.Messaging {
+		switch messaging {
+		case appdetect.MessagingServiceBus:
+			serviceBus, err := i.promptServiceBus(ctx, detect.root)
+			if err != nil {
+				return scaffold.InfraSpec{}, err
 			}
-			break dbPrompt
+
+			spec.ServiceBus = serviceBus
 		}
 	}
 
@@ -100,7 +110,15 @@ func (i *Initializer) infraSpecFromDetect(
 			Port: -1,
 		}
 
-		if svc.Docker == nil || svc.Docker.Path == "" {
+		if svc.Docker != nil && svc.Docker.Path != "" {
+			if svc.Docker.Port > 0 {
+				// the port exposed by the Dockerfile, otherwise the user is prompted for the port
+				serviceSpec.Port = svc.Docker.Port
+			}
+		} else if svc.Language == appdetect.Go || svc.Language == appdetect.Rust || svc.Language == appdetect.Ruby {
+			// the generated Dockerfile exposes the default port of the web framework, if one is known
+			serviceSpec.Port = webFrameworkDefaultPort(svc.Dependencies)
+		} else {
 			// default builder always specifies port 80
 			serviceSpec.Port = 80
 		}
@@ -126,18 +144,49 @@ func (i *Initializer) infraSpecFromDetect(
 				serviceSpec.DbPostgres = &scaffold.DatabaseReference{
 					DatabaseName: spec.DbPostgres.DatabaseName,
 				}
+				// ActiveRecord and Sequel read the connection from DATABASE_URL
+				serviceSpec.DatabaseUrl = svc.Language == appdetect.Ruby
+			case appdetect.DbMySql:
+				serviceSpec.DbMySql = &scaffold.DatabaseReference{
+					DatabaseName: spec.DbMySql.DatabaseName,
+				}
+			case appdetect.DbSqlServer:
+				serviceSpec.DbSqlServer = &scaffold.DatabaseReference{
+					DatabaseName: spec.DbSqlServer.DatabaseName,
+				}
 			case appdetect.DbRedis:
 				serviceSpec.DbRedis = &scaffold.DatabaseReference{
 					DatabaseName: "redis",
 				}
 			}
 		}
+
+		for _, messaging := range svc.MessagingDeps {
+			// filter out messaging services that were removed
+			if _, ok := detect.Messaging[messaging]; !ok {
+				continue
+			}
+
+			switch messaging {
+			case appdetect.MessagingServiceBus:
+				serviceSpec.ServiceBus = &scaffold.ServiceBusReference{
+					QueueName: spec.ServiceBus.QueueName,
+					TopicName: spec.ServiceBus.TopicName,
+				}
+			}
+		}
 		spec.Services = append(spec.Services, serviceSpec)
 	}
 
 	backends := []scaffold.ServiceReference{}
 	frontends := []scaffold.ServiceReference{}
 	for idx := range spec.Services {
+		if spec.Services[idx].Port == -1 && i.console.IsNoPromptMode() {
+			return scaffold.InfraSpec{}, fmt.Errorf(
+				"the port that '%s' listens on could not be detected, run init without --no-prompt to enter it",
+				spec.Services[idx].Name)
+		}
+
 		if spec.Services[idx].Port == -1 {
 			var port int
 			for {
@@ -190,3 +239,102 @@ func (i *Initializer) infraSpecFromDetect(
 
 	return spec, nil
 }
+
+// setDatabaseName adds the database to the spec, with the name of the app database.
+func setDatabaseName(spec *scaffold.InfraSpec, database appdetect.DatabaseDep, dbName string) {
+	switch database {
+	case appdetect.DbMongo:
+		spec.DbCosmosMongo = &scaffold.DatabaseCosmosMongo{
+			DatabaseName: dbName,
+		}
+	case appdetect.DbPostgres:
+		spec.DbPostgres = &scaffold.DatabasePostgres{
+			DatabaseName: dbName,
+		}
+	case appdetect.DbMySql:
+		spec.DbMySql = &scaffold.DatabaseMySql{
+			DatabaseName: dbName,
+		}
+	case appdetect.DbSqlServer:
+		spec.DbSqlServer = &scaffold.DatabaseSqlServer{
+			DatabaseName: dbName,
+		}
+	}
+}
+
+// defaultResourceName returns the name used for the databases and messaging entities of the app in no-prompt mode,
+// which is the name of the project directory with the characters that aren't well-formed in a name replaced.
+func defaultResourceName(root string) string {
+	name := strings.Trim(invalidNameCharRegex.ReplaceAllString(filepath.Base(root), "-"), "-")
+	if name == "" {
+		return "app"
+	}
+
+	return strings.ToLower(name)
+}
+
+// promptServiceBus prompts for the queue or topic to create in the Service Bus namespace.
+func (i *Initializer) promptServiceBus(ctx context.Context, root string) (*scaffold.ServiceBus, error) {
+	if i.console.IsNoPromptMode() {
+		// the app is assumed to use a queue named after the app
+		return &scaffold.ServiceBus{QueueName: defaultResourceName(root)}, nil
+	}
+
+	entityKinds := []string{"Queue", "Topic"}
+	kind, err := i.console.Select(ctx, input.ConsoleOptions{
+		Message: "Does the app send messages to a queue or a topic (Azure Service Bus)?",
+		Options: entityKinds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		name, err := i.console.Prompt(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf("Input the name of the %s", strings.ToLower(entityKinds[kind])),
+			Help: "Hint: Service Bus entity name\n\n" +
+				"Name of the queue or topic that the app sends messages to and receives messages from. " +
+				"This entity will be created after running azd provision or azd up.",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "" {
+			i.console.Message(ctx, fmt.Sprintf("%s name is required.", entityKinds[kind]))
+			continue
+		}
+
+		if !wellFormedDbNameRegex.MatchString(name) {
+			i.console.Message(ctx,
+				fmt.Sprintf("%s name can only contain letters, numbers, hyphens and underscores.", entityKinds[kind]))
+			continue
+		}
+
+		if kind == 0 {
+			return &scaffold.ServiceBus{QueueName: name}, nil
+		}
+
+		return &scaffold.ServiceBus{TopicName: name}, nil
+	}
+}
+
+// webFrameworkDefaultPort returns the port that the given Go, Rust or Ruby web framework dependencies listen on by default,
+// or -1 if the port is not known.
+func webFrameworkDefaultPort(deps []appdetect.Dependency) int {
+	for _, dep := range deps {
+		switch dep {
+		case appdetect.GoGin:
+			return 8080
+		case appdetect.GoEcho:
+			return 1323
+		case appdetect.RustActix:
+			// the port used by the Actix Web examples, the framework has no default
+			return 8080
+		case appdetect.RubyRails:
+			return 3000
+		}
+	}
+
+	return -1
+}
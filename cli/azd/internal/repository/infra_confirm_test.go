@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -93,6 +94,124 @@ func TestInitializer_infraSpecFromDetect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "api with docker exposing a port",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.DotNet,
+						Path:     "dotnet",
+						Docker:   &appdetect.Docker{Path: "Dockerfile", Port: 8080},
+					},
+				},
+			},
+			interactions: []string{},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "dotnet",
+						Port:    8080,
+						Backend: &scaffold.Backend{},
+					},
+				},
+			},
+		},
+		{
+			name: "go api",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Go,
+						Path:     "go",
+						Dependencies: []appdetect.Dependency{
+							appdetect.GoGin,
+						},
+					},
+				},
+			},
+			interactions: []string{},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "go",
+						Port:    8080,
+						Backend: &scaffold.Backend{},
+					},
+				},
+			},
+		},
+		{
+			name: "go api without framework",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Go,
+						Path:     "go",
+					},
+				},
+			},
+			interactions: []string{
+				// prompt for port
+				"3000",
+			},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "go",
+						Port:    3000,
+						Backend: &scaffold.Backend{},
+					},
+				},
+			},
+		},
+		{
+			name: "rust api",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Rust,
+						Path:     "rust",
+						Dependencies: []appdetect.Dependency{
+							appdetect.RustActix,
+						},
+					},
+				},
+			},
+			interactions: []string{},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "rust",
+						Port:    8080,
+						Backend: &scaffold.Backend{},
+					},
+				},
+			},
+		},
+		{
+			name: "php api",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Php,
+						Path:     "php",
+						Dependencies: []appdetect.Dependency{
+							appdetect.PhpLaravel,
+						},
+					},
+				},
+			},
+			interactions: []string{},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "php",
+						Port:    80,
+						Backend: &scaffold.Backend{},
+					},
+				},
+			},
+		},
 		{
 			name: "api and web",
 			detect: detectConfirm{
@@ -138,6 +257,45 @@ func TestInitializer_infraSpecFromDetect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "rails api with postgres",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.Ruby,
+						Path:     "rails",
+						Dependencies: []appdetect.Dependency{
+							appdetect.RubyRails,
+						},
+						DatabaseDeps: []appdetect.DatabaseDep{
+							appdetect.DbPostgres,
+						},
+					},
+				},
+				Databases: map[appdetect.DatabaseDep]EntryKind{
+					appdetect.DbPostgres: EntryKindDetected,
+				},
+			},
+			interactions: []string{
+				"myappdb", // fill in db name
+			},
+			want: scaffold.InfraSpec{
+				DbPostgres: &scaffold.DatabasePostgres{
+					DatabaseName: "myappdb",
+				},
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "rails",
+						Port:    3000,
+						Backend: &scaffold.Backend{},
+						DbPostgres: &scaffold.DatabaseReference{
+							DatabaseName: "myappdb",
+						},
+						DatabaseUrl: true,
+					},
+				},
+			},
+		},
 		{
 			name: "api and web with db",
 			detect: detectConfirm{
@@ -197,11 +355,122 @@ func TestInitializer_infraSpecFromDetect(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "api with mysql and sql server",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.DotNet,
+						Path:     "dotnet",
+						DatabaseDeps: []appdetect.DatabaseDep{
+							appdetect.DbMySql,
+							appdetect.DbSqlServer,
+						},
+					},
+				},
+				Databases: map[appdetect.DatabaseDep]EntryKind{
+					appdetect.DbMySql:     EntryKindDetected,
+					appdetect.DbSqlServer: EntryKindDetected,
+				},
+			},
+			interactions: []string{
+				// an empty name is rejected, then fill in db names. The order of prompts is not deterministic, so the same
+				// name is used for both
+				"",
+				"appdb",
+				"appdb",
+			},
+			want: scaffold.InfraSpec{
+				DbMySql: &scaffold.DatabaseMySql{
+					DatabaseName: "appdb",
+				},
+				DbSqlServer: &scaffold.DatabaseSqlServer{
+					DatabaseName: "appdb",
+				},
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "dotnet",
+						Port:    80,
+						Backend: &scaffold.Backend{},
+						DbMySql: &scaffold.DatabaseReference{
+							DatabaseName: "appdb",
+						},
+						DbSqlServer: &scaffold.DatabaseReference{
+							DatabaseName: "appdb",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "api with service bus",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.DotNet,
+						Path:     "dotnet",
+						MessagingDeps: []appdetect.MessagingDep{
+							appdetect.MessagingServiceBus,
+						},
+					},
+				},
+				Messaging: map[appdetect.MessagingDep]EntryKind{
+					appdetect.MessagingServiceBus: EntryKindDetected,
+				},
+			},
+			interactions: []string{
+				// select a queue, an empty name is rejected, then fill in the queue name
+				"Queue",
+				"",
+				"orders",
+			},
+			want: scaffold.InfraSpec{
+				ServiceBus: &scaffold.ServiceBus{
+					QueueName: "orders",
+				},
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "dotnet",
+						Port:    80,
+						Backend: &scaffold.Backend{},
+						ServiceBus: &scaffold.ServiceBusReference{
+							QueueName: "orders",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "api with declined service bus",
+			detect: detectConfirm{
+				Services: []appdetect.Project{
+					{
+						Language: appdetect.DotNet,
+						Path:     "dotnet",
+						MessagingDeps: []appdetect.MessagingDep{
+							appdetect.MessagingServiceBus,
+						},
+					},
+				},
+				Messaging: map[appdetect.MessagingDep]EntryKind{},
+			},
+			interactions: []string{},
+			want: scaffold.InfraSpec{
+				Services: []scaffold.ServiceSpec{
+					{
+						Name:    "dotnet",
+						Port:    80,
+						Backend: &scaffold.Backend{},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			i := &Initializer{
 				console: input.NewConsole(
+					false,
 					false,
 					false,
 					os.Stdout,
@@ -224,3 +493,90 @@ func TestInitializer_infraSpecFromDetect(t *testing.T) {
 		})
 	}
 }
+
+func TestInitializer_infraSpecFromDetect_NoPrompt(t *testing.T) {
+	newInitializer := func() *Initializer {
+		return &Initializer{
+			console: input.NewConsole(
+				true,
+				false,
+				false,
+				os.Stdout,
+				input.ConsoleHandles{
+					Stderr: os.Stderr,
+					Stdin:  strings.NewReader(""),
+					Stdout: os.Stdout,
+				},
+				nil),
+		}
+	}
+
+	t.Run("DetectedDefaults", func(t *testing.T) {
+		detect := detectConfirm{
+			Services: []appdetect.Project{
+				{
+					Language: appdetect.Python,
+					Path:     "py",
+					DatabaseDeps: []appdetect.DatabaseDep{
+						appdetect.DbPostgres,
+					},
+					MessagingDeps: []appdetect.MessagingDep{
+						appdetect.MessagingServiceBus,
+					},
+				},
+			},
+			Databases: map[appdetect.DatabaseDep]EntryKind{
+				appdetect.DbPostgres: EntryKindDetected,
+			},
+			Messaging: map[appdetect.MessagingDep]EntryKind{
+				appdetect.MessagingServiceBus: EntryKindDetected,
+			},
+			root: filepath.Join(t.TempDir(), "My App"),
+		}
+
+		spec, err := newInitializer().infraSpecFromDetect(context.Background(), detect)
+		require.NoError(t, err)
+		require.Equal(t, scaffold.InfraSpec{
+			DbPostgres: &scaffold.DatabasePostgres{
+				DatabaseName: "my-app",
+			},
+			ServiceBus: &scaffold.ServiceBus{
+				QueueName: "my-app",
+			},
+			Services: []scaffold.ServiceSpec{
+				{
+					Name:    "py",
+					Port:    80,
+					Backend: &scaffold.Backend{},
+					DbPostgres: &scaffold.DatabaseReference{
+						DatabaseName: "my-app",
+					},
+					ServiceBus: &scaffold.ServiceBusReference{
+						QueueName: "my-app",
+					},
+				},
+			},
+		}, spec)
+	})
+
+	t.Run("UndetectedPort", func(t *testing.T) {
+		detect := detectConfirm{
+			Services: []appdetect.Project{
+				{
+					Language: appdetect.DotNet,
+					Path:     "dotnet",
+					Docker:   &appdetect.Docker{Path: "Dockerfile"},
+				},
+			},
+		}
+
+		_, err := newInitializer().infraSpecFromDetect(context.Background(), detect)
+		require.ErrorContains(t, err, "the port that 'dotnet' listens on could not be detected")
+	})
+}
+
+func Test_defaultResourceName(t *testing.T) {
+	require.Equal(t, "my-app", defaultResourceName(filepath.Join("projects", "My App")))
+	require.Equal(t, "todo_api", defaultResourceName(filepath.Join("projects", "todo_api")))
+	require.Equal(t, "app", defaultResourceName(filepath.Join("projects", "...")))
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// existingInfraChoice is what init does with an infra directory that already has a main.bicep or main.tf file.
+type existingInfraChoice string
+
+const (
+	// The Infrastructure as Code files aren't generated, only the project and its services are initialized.
+	existingInfraSkip existingInfraChoice = "skip"
+	// The generated files that aren't present in the infra directory are added, the existing files are kept unchanged.
+	existingInfraMerge existingInfraChoice = "merge"
+	// The user chooses what to do with each generated file that is present in the infra directory.
+	existingInfraReview existingInfraChoice = "review"
+)
+
+// existingInfraProvider returns the provider of the main.bicep or main.tf file in the infra directory, or an empty
+// provider when the directory has neither.
+func existingInfraProvider(infraDir string) (provisioning.ProviderKind, error) {
+	for _, candidate := range []struct {
+		file     string
+		provider provisioning.ProviderKind
+	}{
+		{"main.bicep", provisioning.Bicep},
+		{"main.tf", provisioning.Terraform},
+	} {
+		if _, err := os.Stat(filepath.Join(infraDir, candidate.file)); err == nil {
+			return candidate.provider, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("checking for existing infra: %w", err)
+		}
+	}
+
+	return "", nil
+}
+
+// confirmExistingInfra returns what to do with the infra directory of the project when it already has a main.bicep or
+// main.tf file, or an empty choice when there is no existing infra. The user is prompted once, the choice is saved in
+// the azd context and reused when the app is initialized again. In no-prompt mode, the existing infra is kept unchanged
+// and no IaC is generated.
+func (i *Initializer) confirmExistingInfra(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	infraDir string) (existingInfraChoice, error) {
+	provider, err := existingInfraProvider(infraDir)
+	if err != nil || provider == "" {
+		return "", err
+	}
+
+	saved, err := azdCtx.GetExistingInfraChoice()
+	if err != nil {
+		return "", fmt.Errorf("reading saved choice for existing infra: %w", err)
+	}
+
+	switch choice := existingInfraChoice(saved); choice {
+	case existingInfraSkip, existingInfraMerge, existingInfraReview:
+		log.Printf("using saved choice for existing infra in %s: %s", infraDir, choice)
+		return choice, nil
+	case "":
+	default:
+		log.Printf("ignoring unknown saved choice for existing infra: %s", saved)
+	}
+
+	if i.console.IsNoPromptMode() {
+		i.console.Message(ctx, fmt.Sprintf(
+			"Found existing infrastructure in %s, keeping it unchanged.", output.WithHighLightFormat("./infra")))
+		return existingInfraSkip, nil
+	}
+
+	i.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf("Found existing %s infrastructure in %s.",
+			provider, output.WithHighLightFormat("./infra")),
+	})
+
+	choices := []existingInfraChoice{existingInfraSkip, existingInfraMerge, existingInfraReview}
+	selection, err := i.console.Select(ctx, input.ConsoleOptions{
+		Message: "What would you like to do with your existing infrastructure?",
+		Help: "Hint: Existing infrastructure\n\n" +
+			"azd generates Infrastructure as Code files in ./infra for the detected services. You can keep your " +
+			"existing files and only initialize the project and its services, or add the generated files to them. " +
+			"Your choice is saved in ./.azure and used when you initialize the app again.",
+		Options: []string{
+			"Keep my existing infrastructure, don't generate Infrastructure as Code files",
+			"Add the generated files that don't conflict with my existing files",
+			"Choose for each conflicting file",
+		},
+		DefaultValue: "Keep my existing infrastructure, don't generate Infrastructure as Code files",
+	})
+	if err != nil {
+		return "", fmt.Errorf("prompting for existing infra: %w", err)
+	}
+
+	choice := choices[selection]
+	if err := azdCtx.SetExistingInfraChoice(string(choice)); err != nil {
+		return "", fmt.Errorf("saving choice for existing infra: %w", err)
+	}
+
+	return choice, nil
+}
+
+// duplicateSourceFiles returns the absolute paths of the files in staging that are also present in target, which are
+// skipped to merge the files of staging into target without overwriting any existing file.
+func duplicateSourceFiles(staging string, target string) (map[string]struct{}, error) {
+	duplicateFiles, err := determineDuplicates(staging, target)
+	if err != nil {
+		return nil, fmt.Errorf("checking for overwrites: %w", err)
+	}
+
+	skip := make(map[string]struct{}, len(duplicateFiles))
+	for _, file := range duplicateFiles {
+		skip[filepath.Join(staging, file)] = struct{}{}
+		log.Printf("merging infra, keeping existing file unchanged: %s", filepath.Join(target, file))
+	}
+
+	return skip, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_existingInfraProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected provisioning.ProviderKind
+	}{
+		{"None", []string{"modules/app.bicep"}, ""},
+		{"Bicep", []string{"main.bicep", "main.parameters.json"}, provisioning.Bicep},
+		{"Terraform", []string{"main.tf", "variables.tf"}, provisioning.Terraform},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			createFiles(t, dir, tt.files)
+
+			provider, err := existingInfraProvider(dir)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, provider)
+		})
+	}
+}
+
+func TestInitializer_confirmExistingInfra(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		saved    string
+		noPrompt bool
+		// the selection of the prompt, or -1 when no prompt is expected
+		selection int
+		expected  existingInfraChoice
+		// the choice saved in the azd context after confirming
+		expectedSaved string
+	}{
+		{"NoExistingInfra", nil, "", false, -1, "", ""},
+		{"Skip", []string{"main.bicep"}, "", false, 0, existingInfraSkip, "skip"},
+		{"Merge", []string{"main.tf"}, "", false, 1, existingInfraMerge, "merge"},
+		{"Review", []string{"main.bicep"}, "", false, 2, existingInfraReview, "review"},
+		{"Saved", []string{"main.bicep"}, "merge", false, -1, existingInfraMerge, "merge"},
+		{"SavedUnknown", []string{"main.bicep"}, "replace", false, 0, existingInfraSkip, "skip"},
+		{"NoPrompt", []string{"main.bicep"}, "", true, -1, existingInfraSkip, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			infra := filepath.Join(dir, "infra")
+			createFiles(t, infra, tt.files)
+
+			azdCtx := azdcontext.NewAzdContextWithDirectory(dir)
+			require.NoError(t, azdCtx.SetDefaultEnvironmentName("dev"))
+			if tt.saved != "" {
+				require.NoError(t, azdCtx.SetExistingInfraChoice(tt.saved))
+			}
+
+			console := mockinput.NewMockConsole()
+			console.SetNoPromptMode(tt.noPrompt)
+			console.WhenSelect(func(options input.ConsoleOptions) bool {
+				return true
+			}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+				require.GreaterOrEqual(t, tt.selection, 0, "unexpected prompt: %s", options.Message)
+				return tt.selection, nil
+			})
+
+			i := &Initializer{console: console}
+			choice, err := i.confirmExistingInfra(context.Background(), azdCtx, infra)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, choice)
+
+			saved, err := azdCtx.GetExistingInfraChoice()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSaved, saved)
+
+			// saving the choice keeps the default environment
+			envName, err := azdCtx.GetDefaultEnvironmentName()
+			require.NoError(t, err)
+			require.Equal(t, "dev", envName)
+		})
+	}
+}
+
+func Test_duplicateSourceFiles(t *testing.T) {
+	staging := t.TempDir()
+	target := t.TempDir()
+
+	createFiles(t, staging, []string{"main.bicep", "main.parameters.json", "modules/app.bicep"})
+	createFiles(t, target, []string{"main.bicep", "modules/app.bicep", "modules/db.bicep"})
+
+	skip, err := duplicateSourceFiles(staging, target)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{
+		filepath.Join(staging, "main.bicep"):        {},
+		filepath.Join(staging, "modules/app.bicep"): {},
+	}, skip)
+}
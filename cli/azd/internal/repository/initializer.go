@@ -8,12 +8,19 @@ import (
 	"fmt"
 	"io/fs"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
@@ -22,6 +29,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/resources"
@@ -34,6 +42,21 @@ type Initializer struct {
 	gitCli         git.GitCli
 	dotnetCli      dotnet.DotNetCli
 	lazyEnvManager *lazy.Lazy[environment.Manager]
+	configManager  config.UserConfigManager
+	commandRunner  exec.CommandRunner
+
+	// The results of app detection and the app host manifests generated during the session, which are reused when the
+	// app is detected again. See ResetDetectionCache.
+	detectCache   *appdetect.Cache
+	manifestCache map[string]manifestCacheEntry
+	manifestSaved time.Duration
+	cacheMu       sync.Mutex
+}
+
+type manifestCacheEntry struct {
+	manifest *apphost.Manifest
+	// The time spent generating the manifest
+	elapsed time.Duration
 }
 
 func NewInitializer(
@@ -41,23 +64,42 @@ func NewInitializer(
 	gitCli git.GitCli,
 	dotnetCli dotnet.DotNetCli,
 	lazyEnvManager *lazy.Lazy[environment.Manager],
+	configManager config.UserConfigManager,
+	commandRunner exec.CommandRunner,
 ) *Initializer {
 	return &Initializer{
 		console:        console,
 		gitCli:         gitCli,
 		lazyEnvManager: lazyEnvManager,
+		configManager:  configManager,
+		commandRunner:  commandRunner,
 		dotnetCli:      dotnetCli,
+		detectCache:    appdetect.NewCache(),
+		manifestCache:  map[string]manifestCacheEntry{},
 	}
 }
 
-// Initializes a local repository in the project directory from a remote repository.
+// ResetDetectionCache removes the cached results of app detection and the cached app host manifests, so the app is
+// detected again the next time it is initialized. The cache should be reset when files of the app change in ways that
+// the cache doesn't observe, see appdetect.Cache.
+func (i *Initializer) ResetDetectionCache() {
+	i.cacheMu.Lock()
+	defer i.cacheMu.Unlock()
+
+	i.detectCache.Reset()
+	i.manifestCache = map[string]manifestCacheEntry{}
+}
+
+// Initializes a local repository in the project directory from a remote repository. templateRef is the branch, tag or
+// commit SHA of the repository to initialize from, the default branch when empty.
 //
-// A confirmation prompt is displayed for any existing files to be overwritten.
+// A confirmation prompt is displayed for any existing files to be overwritten. Once the files are written, the postinit
+// hook of the template runs with the consent of the user.
 func (i *Initializer) Initialize(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
 	template *templates.Template,
-	templateBranch string) error {
+	templateRef string) error {
 	var err error
 	stepMessage := fmt.Sprintf("Downloading template code to: %s", output.WithLinkFormat("%s", azdCtx.ProjectDirectory()))
 	i.console.ShowSpinner(ctx, stepMessage, input.Step)
@@ -82,7 +124,7 @@ func (i *Initializer) Initialize(
 		return err
 	}
 
-	filesWithExecPerms, err := i.fetchCode(ctx, templateUrl, templateBranch, staging)
+	filesWithExecPerms, err := i.fetchCode(ctx, templateUrl, templateRef, staging)
 	if err != nil {
 		return err
 	}
@@ -128,16 +170,49 @@ func (i *Initializer) Initialize(
 
 	i.console.StopSpinner(ctx, stepMessage+"\n", input.GetStepResultFormat(err))
 
-	return nil
+	return i.runPostInitHook(ctx, azdCtx)
 }
 
 func (i *Initializer) fetchCode(
 	ctx context.Context,
 	templateUrl string,
-	templateBranch string,
+	templateRef string,
 	destination string) (executableFilePaths []string, err error) {
-	err = i.gitCli.ShallowClone(ctx, templateUrl, templateBranch, destination)
-	if err != nil {
+	// private templates authenticate with a token over HTTPS, or else with the credential helpers of git or the SSH agent
+	var userConfig config.Config
+	if i.configManager != nil {
+		userConfig, err = i.configManager.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading user configuration: %w", err)
+		}
+	}
+	token := templates.Token(userConfig, templateUrl)
+
+	err = i.gitCli.ShallowCloneRef(ctx, templateUrl, destination, git.CloneOptions{Ref: templateRef, Token: token})
+	switch {
+	case errors.Is(err, git.ErrRefNotFound):
+		return nil, &azcli.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: "Check the branch, tag or commit given with --branch or --ref, " +
+				"or omit it to use the default branch of the template.",
+		}
+	case errors.Is(err, git.ErrAccessDenied):
+		return nil, &azcli.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: fmt.Sprintf("The credentials used for the template don't grant read access to it. "+
+				"Set %s to a personal access token that can read the repository and %s to the host of the "+
+				"repository, or use the SSH URL of the repository with a key loaded in your SSH agent.",
+				templates.TokenEnvVarName, templates.TokenHostsEnvVarName),
+		}
+	case errors.Is(err, git.ErrRepositoryNotFound):
+		return nil, &azcli.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: fmt.Sprintf("Check the name of the template. If the template is private, set %s to a "+
+				"personal access token that can read the repository and %s to the host of the repository, or use the "+
+				"SSH URL of the repository with a key loaded in your SSH agent.",
+				templates.TokenEnvVarName, templates.TokenHostsEnvVarName),
+		}
+	case err != nil:
 		return nil, fmt.Errorf("fetching template: %w", err)
 	}
 
@@ -158,7 +233,12 @@ func (i *Initializer) fetchCode(
 	return executableFilePaths, nil
 }
 
-// promptForDuplicates prompts the user for any duplicate files detected.
+// ErrOverwriteAborted is returned when the user aborts instead of choosing what to do with files that are present both
+// locally and in the template.
+var ErrOverwriteAborted = errors.New("aborted to avoid overwriting existing files")
+
+// promptForDuplicates prompts the user for any duplicate files detected. The user can overwrite or keep all the files at
+// once, abort, or choose for each file. In no-prompt mode, all the existing files are kept.
 // The list of absolute source file paths to skip are returned.
 func (i *Initializer) promptForDuplicates(
 	ctx context.Context, staging string, target string) (skipSourceFiles map[string]struct{}, err error) {
@@ -173,43 +253,95 @@ func (i *Initializer) promptForDuplicates(
 		return nil, fmt.Errorf("checking for overwrites: %w", err)
 	}
 
-	if len(duplicateFiles) > 0 {
-		i.console.StopSpinner(ctx, "", input.StepDone)
-		i.console.MessageUxItem(ctx, &ux.WarningMessage{
-			Description: "The following files are present both locally and in the template:",
-		})
+	if len(duplicateFiles) == 0 {
+		return nil, nil
+	}
 
+	i.console.StopSpinner(ctx, "", input.StepDone)
+	i.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: "The following files are present both locally and in the template:",
+	})
+
+	for _, file := range duplicateFiles {
+		i.console.Message(ctx, fmt.Sprintf(" * %s", file))
+	}
+
+	// skipFiles returns the source paths of files, which are kept unchanged
+	skipFiles := func(files []string) map[string]struct{} {
+		skip := make(map[string]struct{}, len(files))
+		for _, file := range files {
+			// this also cleans the result, which is important for matching
+			sourceFile := filepath.Join(staging, file)
+			skip[sourceFile] = struct{}{}
+		}
+		return skip
+	}
+
+	if i.console.IsNoPromptMode() {
 		for _, file := range duplicateFiles {
-			i.console.Message(ctx, fmt.Sprintf(" * %s", file))
+			log.Printf("no-prompt mode, keeping existing file unchanged: %s", filepath.Join(target, file))
 		}
 
+		i.console.Message(ctx, "Keeping the existing files unchanged.")
+		return skipFiles(duplicateFiles), nil
+	}
+
+	const reviewEach = "Choose for each file"
+	selection, err := i.console.Select(ctx, input.ConsoleOptions{
+		Message: "What would you like to do with these files?",
+		Options: []string{
+			"Overwrite with versions from template",
+			"Keep my existing files unchanged",
+			reviewEach,
+			"Abort",
+		},
+		DefaultValue: reviewEach,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("prompting to overwrite: %w", err)
+	}
+
+	switch selection {
+	case 0: // overwrite all
+		return nil, nil
+	case 1: // keep all
+		return skipFiles(duplicateFiles), nil
+	case 3: // abort
+		return nil, ErrOverwriteAborted
+	}
+
+	skipSourceFiles = map[string]struct{}{}
+	for idx, file := range duplicateFiles {
 		selection, err := i.console.Select(ctx, input.ConsoleOptions{
-			Message: "What would you like to do with these files?",
+			Message: fmt.Sprintf("What would you like to do with %s?", file),
 			Options: []string{
-				"Overwrite with versions from template",
-				"Keep my existing files unchanged",
+				"Overwrite with version from template",
+				"Keep my existing file unchanged",
+				"Overwrite this file and all remaining files",
+				"Keep this file and all remaining files unchanged",
+				"Abort",
 			},
 		})
-
 		if err != nil {
 			return nil, fmt.Errorf("prompting to overwrite: %w", err)
 		}
 
 		switch selection {
 		case 0: // overwrite
-			return nil, nil
 		case 1: // keep
-			skipSourceFiles = make(map[string]struct{}, len(duplicateFiles))
-			for _, file := range duplicateFiles {
-				// this also cleans the result, which is important for matching
-				sourceFile := filepath.Join(staging, file)
-				skipSourceFiles[sourceFile] = struct{}{}
-			}
+			maps.Copy(skipSourceFiles, skipFiles([]string{file}))
+		case 2: // overwrite remaining
+			return skipSourceFiles, nil
+		case 3: // keep remaining
+			maps.Copy(skipSourceFiles, skipFiles(duplicateFiles[idx:]))
 			return skipSourceFiles, nil
+		case 4: // abort
+			return nil, ErrOverwriteAborted
 		}
 	}
 
-	return nil, nil
+	return skipSourceFiles, nil
 }
 
 func (i *Initializer) gitInitialize(ctx context.Context,
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -24,6 +25,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockconfig"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
@@ -60,6 +62,8 @@ func Test_Initializer_Initialize(t *testing.T) {
 				git.NewGitCli(mockContext.CommandRunner),
 				dotnet.NewDotNetCli(mockContext.CommandRunner),
 				lazy.From[environment.Manager](mockEnv),
+				nil,
+				mockContext.CommandRunner,
 			)
 			err := i.Initialize(*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local"}, "")
 			require.NoError(t, err)
@@ -104,6 +108,8 @@ func Test_Initializer_DevCenter(t *testing.T) {
 		git.NewGitCli(mockContext.CommandRunner),
 		dotnet.NewDotNetCli(mockContext.CommandRunner),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
+		mockContext.CommandRunner,
 	)
 	err := i.Initialize(*mockContext.Context, azdCtx, template, "")
 	require.NoError(t, err)
@@ -116,6 +122,75 @@ func Test_Initializer_DevCenter(t *testing.T) {
 	require.Equal(t, prj.Platform.Config["environmentDefinition"], "DEVCENTER_ENV_DEFINITION")
 }
 
+func Test_Initializer_PostInitHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks of the test templates are sh scripts")
+	}
+
+	tests := []struct {
+		name        string
+		templateDir string
+		noPrompt    bool
+		allow       string
+		confirm     bool
+		ran         bool
+		err         string
+	}{
+		{name: "Confirmed", templateDir: "template-postinit", confirm: true, ran: true},
+		{name: "Declined", templateDir: "template-postinit", confirm: false, ran: false},
+		{name: "Allowed", templateDir: "template-postinit", noPrompt: true, allow: "true", ran: true},
+		{name: "Disallowed", templateDir: "template-postinit", allow: "false", confirm: true, ran: false},
+		{name: "NoPromptDisallowed", templateDir: "template-postinit", noPrompt: true, allow: "false", ran: false},
+		{
+			name:        "NoPromptWithoutConsent",
+			templateDir: "template-postinit",
+			noPrompt:    true,
+			err:         "postinit hook of the template requires consent to run with --no-prompt",
+		},
+		{name: "Failure", templateDir: "template-postinit-failure", allow: "true", err: "missing secrets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+			mockContext := mocks.NewMockContext(context.Background())
+			mockGitClone(t, mockContext, "https://github.com/Azure-Samples/local", testCase{templateDir: tt.templateDir})
+			mockContext.Console.SetNoPromptMode(tt.noPrompt)
+			mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+				return strings.Contains(options.Message, "postinit")
+			}).Respond(tt.confirm)
+
+			userConfig := config.NewEmptyConfig()
+			if tt.allow != "" {
+				require.NoError(t, userConfig.Set(allowPostInitHooksKey, tt.allow))
+			}
+			configManager := config.NewUserConfigManager(mockconfig.NewMockConfigManager().WithConfig(userConfig))
+
+			i := NewInitializer(
+				mockContext.Console,
+				git.NewGitCli(mockContext.CommandRunner),
+				dotnet.NewDotNetCli(mockContext.CommandRunner),
+				lazy.From[environment.Manager](&mockenv.MockEnvManager{}),
+				configManager,
+				mockContext.CommandRunner,
+			)
+			err := i.Initialize(*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local"}, "")
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.ran {
+				require.FileExists(t, filepath.Join(projectDir, "initialized.txt"))
+			} else {
+				require.NoFileExists(t, filepath.Join(projectDir, "initialized.txt"))
+			}
+		})
+	}
+}
+
 func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 	templateDir := "template"
 	tests := []struct {
@@ -174,6 +249,8 @@ func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 				git.NewGitCli(mockRunner),
 				dotnet.NewDotNetCli(mockRunner),
 				lazy.From[environment.Manager](mockEnv),
+				nil,
+				mockRunner,
 			)
 			err = i.Initialize(context.Background(), azdCtx, &templates.Template{RepositoryPath: "local"}, "")
 			require.NoError(t, err)
@@ -375,7 +452,7 @@ func Test_Initializer_WriteCoreAssets(t *testing.T) {
 			envManager := &mockenv.MockEnvManager{}
 			envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-			i := NewInitializer(console, git.NewGitCli(realRunner), nil, lazy.From[environment.Manager](envManager))
+			i := NewInitializer(console, git.NewGitCli(realRunner), nil, lazy.From[environment.Manager](envManager), nil, realRunner)
 			err := i.writeCoreAssets(context.Background(), azdCtx)
 			require.NoError(t, err)
 
@@ -492,6 +569,69 @@ func Test_determineDuplicates(t *testing.T) {
 	}
 }
 
+func Test_Initializer_promptForDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		noPrompt bool
+		// the selection of what to do with all the files, followed by the selections for each file
+		selections []int
+		expected   []string
+		err        error
+	}{
+		{"OverwriteAll", false, []int{0}, nil, nil},
+		{"KeepAll", false, []int{1}, []string{"a.txt", "b.txt", "c.txt"}, nil},
+		{"Abort", false, []int{3}, nil, ErrOverwriteAborted},
+		{"EachFile", false, []int{2, 1, 0, 1}, []string{"a.txt", "c.txt"}, nil},
+		{"EachFileOverwriteRemaining", false, []int{2, 1, 2}, []string{"a.txt"}, nil},
+		{"EachFileKeepRemaining", false, []int{2, 0, 3}, []string{"b.txt", "c.txt"}, nil},
+		{"EachFileAbort", false, []int{2, 1, 4}, nil, ErrOverwriteAborted},
+		{"NoPrompt", true, nil, []string{"a.txt", "b.txt", "c.txt"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staging := t.TempDir()
+			target := t.TempDir()
+
+			createFiles(t, staging, []string{"a.txt", "b.txt", "c.txt", "d.txt"})
+			createFiles(t, target, []string{"a.txt", "b.txt", "c.txt"})
+
+			console := mockinput.NewMockConsole()
+			console.SetNoPromptMode(tt.noPrompt)
+			selections := slices.Clone(tt.selections)
+			console.WhenSelect(func(options input.ConsoleOptions) bool {
+				return true
+			}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+				require.NotEmpty(t, selections, "unexpected prompt: %s", options.Message)
+				selection := selections[0]
+				selections = selections[1:]
+				return selection, nil
+			})
+
+			i := &Initializer{console: console}
+			skip, err := i.promptForDuplicates(context.Background(), staging, target)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Empty(t, selections)
+
+			expected := []string{}
+			for _, file := range tt.expected {
+				expected = append(expected, filepath.Join(staging, file))
+			}
+
+			actual := []string{}
+			for file := range skip {
+				actual = append(actual, file)
+			}
+
+			require.ElementsMatch(t, expected, actual)
+		})
+	}
+}
+
 func createFiles(t *testing.T, dir string, files []string) {
 	for _, file := range files {
 		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0755))
@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// postInitHookName is the name of the project hook that templates declare to run one-time setup, such as generating
+// secrets or initializing submodules, after the template is initialized.
+const postInitHookName = "postinit"
+
+// allowPostInitHooksKey is the key we use in user config to denote that the postinit hooks of templates run without
+// asking for consent, which is required to run them with --no-prompt, or that they never run when it is false. The value
+// should be a string as specified by [strconv.ParseBool].
+const allowPostInitHooksKey = "template.allowPostInitHooks"
+
+// runPostInitHook runs the postinit hook of the project initialized from a template, once the files of the template are
+// written. The hook is displayed before it runs, and only runs with the consent of the user, which is given at the
+// prompt, or ahead of time with allowPostInitHooksKey. The hook is skipped when the user declines, and init fails with
+// --no-prompt when consent wasn't configured ahead of time.
+func (i *Initializer) runPostInitHook(ctx context.Context, azdCtx *azdcontext.AzdContext) error {
+	if _, err := os.Stat(azdCtx.ProjectPath()); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	prj, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return fmt.Errorf("loading project: %w", err)
+	}
+
+	if prj.Hooks[postInitHookName] == nil {
+		return nil
+	}
+
+	hooks := map[string]*ext.HookConfig{postInitHookName: prj.Hooks[postInitHookName]}
+	hooksManager := ext.NewHooksManager(azdCtx.ProjectDirectory())
+
+	// resolves the configuration for the current platform, so the command that is displayed is the one that runs
+	hookConfigs, err := hooksManager.GetAll(hooks)
+	if err != nil {
+		return err
+	}
+
+	i.console.Message(ctx, fmt.Sprintf(
+		"\nThe template runs a %s hook to finish setting up the project:\n\n%s\n",
+		output.WithHighLightFormat(postInitHookName),
+		hookConfigs[0].Run))
+
+	allowed, configured, err := i.allowPostInitHooks()
+	if err != nil {
+		return err
+	}
+
+	if !configured && i.console.IsNoPromptMode() {
+		return &azcli.ErrorWithSuggestion{
+			Err: fmt.Errorf("the %s hook of the template requires consent to run with --no-prompt", postInitHookName),
+			Suggestion: fmt.Sprintf("Run 'azd config set %s true' to allow the %s hooks of templates to run, or "+
+				"'azd config set %s false' to skip them, and run 'azd init' again.",
+				allowPostInitHooksKey, postInitHookName, allowPostInitHooksKey),
+		}
+	}
+
+	if !configured {
+		allowed, err = i.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      fmt.Sprintf("Do you want to run the %s hook?", postInitHookName),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !allowed {
+		i.console.Message(ctx, fmt.Sprintf(
+			"Skipped the %s hook. You can run it later with %s.\n",
+			postInitHookName,
+			output.WithHighLightFormat("azd hooks run %s", postInitHookName)))
+		return nil
+	}
+
+	// the environment of the project is created after the template is initialized, so the hook runs without one
+	hooksRunner := ext.NewHooksRunner(
+		hooksManager,
+		i.commandRunner,
+		nil,
+		i.console,
+		azdCtx.ProjectDirectory(),
+		hooks,
+		environment.New(""),
+	)
+
+	if err := hooksRunner.RunHooks(ctx, ext.HookTypePost, nil, "init"); err != nil {
+		return fmt.Errorf("running %s hook: %w", postInitHookName, err)
+	}
+
+	return nil
+}
+
+// allowPostInitHooks returns whether the user allowed the postinit hooks of templates to run without prompting, and
+// whether the user configured it at all.
+func (i *Initializer) allowPostInitHooks() (allow bool, configured bool, err error) {
+	if i.configManager == nil {
+		return false, false, nil
+	}
+
+	userConfig, err := i.configManager.Load()
+	if err != nil {
+		return false, false, fmt.Errorf("loading user configuration: %w", err)
+	}
+
+	if value, has := userConfig.Get(allowPostInitHooksKey); has {
+		if value, ok := value.(string); ok {
+			if allow, err := strconv.ParseBool(value); err == nil {
+				return allow, true, nil
+			}
+		}
+	}
+
+	return false, false, nil
+}
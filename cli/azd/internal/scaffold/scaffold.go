@@ -129,6 +129,27 @@ func ExecInfra(
 		}
 	}
 
+	if spec.DbMySql != nil {
+		err = Execute(t, "db-mysql.bicep", spec.DbMySql, filepath.Join(infraApp, "db-mysql.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding mysql: %w", err)
+		}
+	}
+
+	if spec.DbSqlServer != nil {
+		err = Execute(t, "db-sqlserver.bicep", spec.DbSqlServer, filepath.Join(infraApp, "db-sqlserver.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding sql server: %w", err)
+		}
+	}
+
+	if spec.ServiceBus != nil {
+		err = Execute(t, "messaging-servicebus.bicep", spec.ServiceBus, filepath.Join(infraApp, "messaging-servicebus.bicep"))
+		if err != nil {
+			return fmt.Errorf("scaffolding service bus: %w", err)
+		}
+	}
+
 	for _, svc := range spec.Services {
 		err = Execute(t, "host-containerapp.bicep", svc, filepath.Join(infraApp, svc.Name+".bicep"))
 		if err != nil {
@@ -161,6 +182,27 @@ func preExecExpand(spec *InfraSpec) {
 			})
 	}
 
+	// mysql and sql server use separate passwords, so that they don't conflict with postgres
+	if spec.DbMySql != nil {
+		spec.Parameters = append(spec.Parameters,
+			Parameter{
+				Name:   "mysqlDatabasePassword",
+				Value:  "$(secretOrRandomPassword ${AZURE_KEY_VAULT_NAME} mysqlDatabasePassword)",
+				Type:   "string",
+				Secret: true,
+			})
+	}
+
+	if spec.DbSqlServer != nil {
+		spec.Parameters = append(spec.Parameters,
+			Parameter{
+				Name:   "sqlDatabasePassword",
+				Value:  "$(secretOrRandomPassword ${AZURE_KEY_VAULT_NAME} sqlDatabasePassword)",
+				Type:   "string",
+				Secret: true,
+			})
+	}
+
 	for _, svc := range spec.Services {
 		// containerapp requires a global '_exist' parameter for each service
 		spec.Parameters = append(spec.Parameters,
@@ -94,6 +94,25 @@ func TestExecInfra(t *testing.T) {
 				},
 			},
 		},
+		{
+			"API with Postgres database URL",
+			InfraSpec{
+				DbPostgres: &DatabasePostgres{
+					DatabaseName: "appdb",
+					DatabaseUser: "appuser",
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3000,
+						DbPostgres: &DatabaseReference{
+							DatabaseName: "appdb",
+						},
+						DatabaseUrl: true,
+					},
+				},
+			},
+		},
 		{
 			"API with MongoDB",
 			InfraSpec{
@@ -111,6 +130,42 @@ func TestExecInfra(t *testing.T) {
 				},
 			},
 		},
+		{
+			"API with MySQL",
+			InfraSpec{
+				DbMySql: &DatabaseMySql{
+					DatabaseName: "appdb",
+					DatabaseUser: "appuser",
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						DbMySql: &DatabaseReference{
+							DatabaseName: "appdb",
+						},
+					},
+				},
+			},
+		},
+		{
+			"API with SQL Server",
+			InfraSpec{
+				DbSqlServer: &DatabaseSqlServer{
+					DatabaseName: "appdb",
+					DatabaseUser: "appuser",
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						DbSqlServer: &DatabaseReference{
+							DatabaseName: "appdb",
+						},
+					},
+				},
+			},
+		},
 		{
 			"API with Redis",
 			InfraSpec{
@@ -125,6 +180,40 @@ func TestExecInfra(t *testing.T) {
 				},
 			},
 		},
+		{
+			"API with Service Bus queue",
+			InfraSpec{
+				ServiceBus: &ServiceBus{
+					QueueName: "orders",
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						ServiceBus: &ServiceBusReference{
+							QueueName: "orders",
+						},
+					},
+				},
+			},
+		},
+		{
+			"API with Service Bus topic",
+			InfraSpec{
+				ServiceBus: &ServiceBus{
+					TopicName: "events",
+				},
+				Services: []ServiceSpec{
+					{
+						Name: "api",
+						Port: 3100,
+						ServiceBus: &ServiceBusReference{
+							TopicName: "events",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -12,6 +12,11 @@ type InfraSpec struct {
 	// Databases to create
 	DbPostgres    *DatabasePostgres
 	DbCosmosMongo *DatabaseCosmosMongo
+	DbMySql       *DatabaseMySql
+	DbSqlServer   *DatabaseSqlServer
+
+	// Messaging services to create
+	ServiceBus *ServiceBus
 }
 
 type Parameter struct {
@@ -30,6 +35,22 @@ type DatabaseCosmosMongo struct {
 	DatabaseName string
 }
 
+type DatabaseMySql struct {
+	DatabaseUser string
+	DatabaseName string
+}
+
+type DatabaseSqlServer struct {
+	DatabaseUser string
+	DatabaseName string
+}
+
+// ServiceBus is an Azure Service Bus namespace with a single queue or topic. Only one of QueueName and TopicName is set.
+type ServiceBus struct {
+	QueueName string
+	TopicName string
+}
+
 type ServiceSpec struct {
 	Name string
 	Port int
@@ -43,7 +64,130 @@ type ServiceSpec struct {
 	// Connection to a database
 	DbPostgres    *DatabaseReference
 	DbCosmosMongo *DatabaseReference
+	DbMySql       *DatabaseReference
+	DbSqlServer   *DatabaseReference
 	DbRedis       *DatabaseReference
+
+	// If true, the connection to the Postgres database is also provided as a URL in DATABASE_URL, which is how Ruby on
+	// Rails apps are configured.
+	DatabaseUrl bool
+
+	// Connection to a messaging service
+	ServiceBus *ServiceBusReference
+}
+
+// GoDockerfile is the data used to generate a Dockerfile for a Go service.
+type GoDockerfile struct {
+	// The Go version of the build image, for example '1.21'.
+	GoVersion string
+
+	// The path of the main package to build, relative to the module directory, for example './cmd/server'.
+	MainPackage string
+
+	// The port the service listens on. No port is exposed if the value is not positive.
+	Port int
+}
+
+// RustDockerfile is the data used to generate a Dockerfile for a Rust service.
+type RustDockerfile struct {
+	// The name of the binary target to build, as declared in Cargo.toml.
+	BinaryName string
+
+	// The port the service listens on. No port is exposed if the value is not positive.
+	Port int
+}
+
+// PhpDockerfile is the data used to generate a Dockerfile for a PHP service, which serves the app with PHP-FPM and nginx.
+type PhpDockerfile struct {
+	// The directory served by nginx, relative to the project directory, for example 'public'. The project directory is
+	// served if the value is empty.
+	DocumentRoot string
+
+	// The port nginx listens on.
+	Port int
+
+	// The PHP extensions to install with docker-php-ext-install, for example 'pdo_pgsql'.
+	Extensions []string
+
+	// The system packages required to build the extensions, for example 'libpq-dev'.
+	Packages []string
+}
+
+// PythonDockerfile is the data used to generate a Dockerfile for a Python service.
+type PythonDockerfile struct {
+	// The additional packages installed with pip to serve the app, for example 'gunicorn'.
+	Packages []string
+
+	// The package manager that installs the dependencies of the app, 'poetry', 'pipenv' or 'uv'. The dependencies are
+	// installed with pip if the value is empty.
+	PackageManager string
+
+	// The file that declares the dependencies installed with pip, 'requirements.txt' if the value is empty. When it is
+	// 'pyproject.toml', the app is installed with its dependencies.
+	ManifestFile string
+
+	// The name of the lockfile of the package manager, for example 'poetry.lock'. Dependencies are installed without a
+	// lockfile if the value is empty.
+	LockFile string
+
+	// The command that starts the app, for example ['gunicorn', '--bind', '0.0.0.0:80', 'app:app'].
+	Command []string
+
+	// The port the service listens on.
+	Port int
+}
+
+// NodeDockerfile is the data used to generate a Dockerfile for a JavaScript or TypeScript service.
+type NodeDockerfile struct {
+	// The package manager that installs the dependencies and runs the scripts of the app, for example 'npm'.
+	PackageManager string
+
+	// The name of the lockfile of the package manager, for example 'package-lock.json'. Dependencies are installed
+	// without a lockfile if the value is empty.
+	LockFile string
+
+	// The directory of the static files produced by the build script, relative to the project directory, for example
+	// 'dist'. When set, the static files are served by nginx, otherwise the app is started with the start script.
+	OutputPath string
+
+	// The port the service listens on.
+	Port int
+}
+
+// JavaDockerfile is the data used to generate a Dockerfile for a Java service built with Maven.
+type JavaDockerfile struct {
+	// The Java version of the build and runtime images, for example '17'.
+	JavaVersion string
+
+	// If true, the project is built with the Maven wrapper of the project instead of the Maven of the build image.
+	MavenWrapper bool
+
+	// If true, the project has the .mvn directory of the Maven wrapper, which configures the wrapper.
+	MavenWrapperConfig bool
+
+	// The port the service listens on.
+	Port int
+}
+
+// RubyDockerfile is the data used to generate a Dockerfile for a Ruby service, which serves the app with Puma.
+type RubyDockerfile struct {
+	// The version of the ruby image, for example '3.3'.
+	RubyVersion string
+
+	// The port Puma listens on.
+	Port int
+
+	// If true, the assets of the Rails app are precompiled when the image is built.
+	PrecompileAssets bool
+
+	// If true, Node.js is installed in the build stage to bundle the assets of the Rails app.
+	Node bool
+
+	// The system packages required to build the native extensions of the gems, for example 'libpq-dev'.
+	BuildPackages []string
+
+	// The system packages required by the native extensions of the gems at runtime, for example 'libpq5'.
+	Packages []string
 }
 
 type Frontend struct {
@@ -62,6 +206,11 @@ type DatabaseReference struct {
 	DatabaseName string
 }
 
+type ServiceBusReference struct {
+	QueueName string
+	TopicName string
+}
+
 func containerAppExistsParameter(serviceName string) Parameter {
 	return Parameter{
 		Name: BicepName(serviceName) + "Exists",
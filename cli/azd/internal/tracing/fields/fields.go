@@ -250,4 +250,7 @@ const (
 
 	// The last step recorded during the app init process.
 	AppInitLastStep = attribute.Key("appinit.lastStep")
+
+	// The time saved by reusing cached detection results and app host manifests, in milliseconds.
+	AppInitDetectCacheSavedTime = attribute.Key("appinit.detect.cache_saved_time")
 )
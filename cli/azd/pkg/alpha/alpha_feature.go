@@ -11,9 +11,10 @@ import (
 
 // Feature defines the structure for a feature in alpha mode.
 type Feature struct {
-	Id          string `yaml:"id"`
-	Description string `yaml:"description"`
-	Status      string
+	Id          string `yaml:"id" json:"id"`
+	Description string `yaml:"description" json:"description"`
+	// Status is On when the feature is enabled, by the user config or by an AZD_ALPHA_ENABLE_<ID> environment variable
+	Status string `json:"status"`
 }
 
 // constant keys are used within source code to pull the AlphaFeature
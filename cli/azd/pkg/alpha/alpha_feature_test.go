@@ -160,3 +160,31 @@ func Test_AlphaToggle(t *testing.T) {
 	})
 
 }
+
+func Test_ListFeaturesEnvOverride(t *testing.T) {
+	mockAlphaFeatures := func() []Feature {
+		return []Feature{
+			{Id: "envOn", Description: "enabled by the environment"},
+			{Id: "envOff", Description: "disabled by the environment"},
+		}
+	}
+
+	mockConfig := config.NewConfig(map[string]any{
+		parentKey: map[string]any{
+			"envOff": enabledValue,
+		},
+	})
+
+	t.Setenv("AZD_ALPHA_ENABLE_ENVON", "true")
+	t.Setenv("AZD_ALPHA_ENABLE_ENVOFF", "false")
+
+	alphaManager := &FeatureManager{
+		alphaFeaturesResolver: mockAlphaFeatures,
+		userConfigCache:       mockConfig,
+	}
+
+	alphaF, err := alphaManager.ListFeatures()
+	require.NoError(t, err)
+	require.Equal(t, enabledText, alphaF["envOn"].Status)
+	require.Equal(t, disabledText, alphaF["envOff"].Status)
+}
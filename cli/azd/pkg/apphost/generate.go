@@ -9,10 +9,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/scaffold"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/resources"
@@ -183,6 +185,118 @@ func GenerateProjectArtifacts(
 	return files, nil
 }
 
+// resourceLoaders load the manifest resources of each type that azd translates to infrastructure into the generator.
+// Resources of other types aren't supported.
+var resourceLoaders = map[string]func(b *infraGenerator, name string, comp *Resource) error{
+	"azure.servicebus.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addServiceBus(name, comp.Queues, comp.Topics)
+		return nil
+	},
+	"azure.appinsights.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addAppInsights(name)
+		return nil
+	},
+	"project.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addProject(name, *comp.Path, comp.Env, comp.Bindings)
+		return nil
+	},
+	"container.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addContainer(name, *comp.Image, comp.Env, comp.Bindings)
+		return nil
+	},
+	"redis.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addContainerAppService(name, "redis")
+		return nil
+	},
+	"azure.keyvault.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addKeyVault(name)
+		return nil
+	},
+	"azure.storage.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addStorageAccount(name)
+		return nil
+	},
+	"azure.storage.blob.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addStorageBlobContainer(*comp.Parent, name)
+		return nil
+	},
+	"azure.openai.account.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		return b.addOpenAIAccount(name, comp.Deployments)
+	},
+	// We currently use a ACA Postgres Service per database. Because of this, we don't need to retain any information
+	// from the server resource.
+	//
+	// The server is listed here to ensure we don't error out on the resource type by treating it as an unknown resource
+	// type.
+	"postgres.server.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		return nil
+	},
+	"postgres.database.v0": func(b *infraGenerator, name string, comp *Resource) error {
+		b.addContainerAppService(name, "postgres")
+		return nil
+	},
+	"postgres.connection.v0":       addConnectionString,
+	"rabbitmq.connection.v0":       addConnectionString,
+	"azure.cosmosdb.connection.v0": addConnectionString,
+}
+
+func addConnectionString(b *infraGenerator, name string, comp *Resource) error {
+	b.connectionStrings[name] = *comp.ConnectionString
+	return nil
+}
+
+// UnsupportedResource is a resource of the manifest whose type azd doesn't support.
+type UnsupportedResource struct {
+	Name string
+	Type string
+}
+
+// UnsupportedResourcesError is returned by [ValidateManifest] when the manifest has resources of types that azd doesn't
+// support.
+type UnsupportedResourcesError struct {
+	// Resources are the unsupported resources, sorted by name.
+	Resources []UnsupportedResource
+}
+
+func (e *UnsupportedResourcesError) Error() string {
+	lines := make([]string, 0, len(e.Resources))
+	for _, resource := range e.Resources {
+		lines = append(lines, fmt.Sprintf("  %s (%s)", resource.Name, resource.Type))
+	}
+
+	return fmt.Sprintf(
+		"the app host manifest has resources of types that azd %s doesn't support:\n%s",
+		internal.VersionInfo().Version,
+		strings.Join(lines, "\n"))
+}
+
+// ValidateManifest returns an [*UnsupportedResourcesError] listing every resource of the manifest whose type azd doesn't
+// support, so the manifest can be rejected before any infrastructure is generated from it. Unsupported resources are
+// accepted when AZD_DEBUG_DOTNET_APPHOST_IGNORE_UNSUPPORTED_RESOURCES is set.
+func ValidateManifest(manifest *Manifest) error {
+	var unsupported []UnsupportedResource
+	for name, resource := range manifest.Resources {
+		if _, has := resourceLoaders[resource.Type]; !has {
+			unsupported = append(unsupported, UnsupportedResource{Name: name, Type: resource.Type})
+		}
+	}
+
+	if len(unsupported) == 0 {
+		return nil
+	}
+
+	ignore, err := strconv.ParseBool(os.Getenv("AZD_DEBUG_DOTNET_APPHOST_IGNORE_UNSUPPORTED_RESOURCES"))
+	if err == nil && ignore {
+		return nil
+	}
+
+	slices.SortFunc(unsupported, func(a, b UnsupportedResource) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return &UnsupportedResourcesError{Resources: unsupported}
+}
+
 type infraGenerator struct {
 	containers        map[string]genContainer
 	projects          map[string]genProject
@@ -201,6 +315,7 @@ func newInfraGenerator() *infraGenerator {
 			ServiceBuses:                    make(map[string]genServiceBus),
 			StorageAccounts:                 make(map[string]genStorageAccount),
 			KeyVaults:                       make(map[string]genKeyVault),
+			OpenAIAccounts:                  make(map[string]genOpenAIAccount),
 			ContainerApps:                   make(map[string]genContainerApp),
 		},
 		containers:                   make(map[string]genContainer),
@@ -216,38 +331,8 @@ func (b *infraGenerator) LoadManifest(m *Manifest) error {
 	for name, comp := range m.Resources {
 		b.resourceTypes[name] = comp.Type
 
-		switch comp.Type {
-		case "azure.servicebus.v0":
-			b.addServiceBus(name, comp.Queues, comp.Topics)
-		case "azure.appinsights.v0":
-			b.addAppInsights(name)
-		case "project.v0":
-			b.addProject(name, *comp.Path, comp.Env, comp.Bindings)
-		case "container.v0":
-			b.addContainer(name, *comp.Image, comp.Env, comp.Bindings)
-		case "redis.v0":
-			b.addContainerAppService(name, "redis")
-		case "azure.keyvault.v0":
-			b.addKeyVault(name)
-		case "azure.storage.v0":
-			b.addStorageAccount(name)
-		case "azure.storage.blob.v0":
-			b.addStorageBlobContainer(*comp.Parent, name)
-		case "postgres.server.v0":
-			// We currently use a ACA Postgres Service per database. Because of this, we don't need to retain any
-			// information from the server resource.
-			//
-			// We have the case statement here to ensure we don't error out on the resource type by treating it as an unknown
-			// resource type.
-		case "postgres.database.v0":
-			b.addContainerAppService(name, "postgres")
-		case "postgres.connection.v0":
-			b.connectionStrings[name] = *comp.ConnectionString
-		case "rabbitmq.connection.v0":
-			b.connectionStrings[name] = *comp.ConnectionString
-		case "azure.cosmosdb.connection.v0":
-			b.connectionStrings[name] = *comp.ConnectionString
-		default:
+		load, has := resourceLoaders[comp.Type]
+		if !has {
 			ignore, err := strconv.ParseBool(os.Getenv("AZD_DEBUG_DOTNET_APPHOST_IGNORE_UNSUPPORTED_RESOURCES"))
 			if err == nil && ignore {
 				log.Printf(
@@ -255,7 +340,11 @@ func (b *infraGenerator) LoadManifest(m *Manifest) error {
 					comp.Type)
 				continue
 			}
-			return fmt.Errorf("unsupported resource type: %s", comp.Type)
+			return fmt.Errorf("unsupported resource type %s of resource %s", comp.Type, name)
+		}
+
+		if err := load(b, name, comp); err != nil {
+			return err
 		}
 	}
 
@@ -320,6 +409,42 @@ func (b *infraGenerator) addKeyVault(name string) {
 	b.bicepContext.KeyVaults[name] = genKeyVault{}
 }
 
+// addOpenAIAccount adds an Azure OpenAI account, which is a Cognitive Services account of kind OpenAI, with the given
+// model deployments.
+func (b *infraGenerator) addOpenAIAccount(name string, deployments []OpenAIDeployment) error {
+	account := genOpenAIAccount{}
+
+	for _, deployment := range deployments {
+		if deployment.Name == "" || deployment.ModelName == "" || deployment.ModelVersion == "" {
+			return fmt.Errorf(
+				"the deployments of azure.openai.account.v0 resource %s require a name, modelName and modelVersion", name)
+		}
+
+		genDeployment := genOpenAIDeployment{
+			Name:         deployment.Name,
+			ModelName:    deployment.ModelName,
+			ModelVersion: deployment.ModelVersion,
+			SkuName:      "Standard",
+			SkuCapacity:  1,
+		}
+
+		if deployment.Sku != nil {
+			if deployment.Sku.Name != "" {
+				genDeployment.SkuName = deployment.Sku.Name
+			}
+
+			if deployment.Sku.Capacity > 0 {
+				genDeployment.SkuCapacity = deployment.Sku.Capacity
+			}
+		}
+
+		account.Deployments = append(account.Deployments, genDeployment)
+	}
+
+	b.bicepContext.OpenAIAccounts[name] = account
+	return nil
+}
+
 func (b *infraGenerator) addStorageBlobContainer(storageAccount, containerName string) {
 	// TODO(ellismg): We have to handle the case where we may visit the blob resource before the storage account resource.
 	// But this implementation means that if the parent storage account is not in the manifest, we will not detect that
@@ -546,7 +671,10 @@ func (b *infraGenerator) Compile() error {
 				default:
 					return errUnsupportedProperty(targetType, prop)
 				}
-			case targetType == "azure.keyvault.v0" || targetType == "azure.storage.blob.v0":
+			case targetType == "azure.keyvault.v0" ||
+				targetType == "azure.storage.blob.v0" ||
+				targetType == "azure.openai.account.v0":
+
 				switch prop {
 				case "connectionString":
 					projectTemplateCtx.Env[k] = fmt.Sprintf(
@@ -0,0 +1,125 @@
+package apphost
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestManifest(t *testing.T, name string) *Manifest {
+	contents, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(contents, &manifest))
+	return &manifest
+}
+
+func TestAspireOpenAI(t *testing.T) {
+	manifest := loadTestManifest(t, "aspire-openai.json")
+
+	files, err := BicepTemplate(manifest)
+	require.NoError(t, err)
+
+	for _, name := range []string{"main", "resources"} {
+		t.Run(name, func(t *testing.T) {
+			contents, err := fs.ReadFile(files, name+".bicep")
+			require.NoError(t, err)
+
+			snapshot.NewConfig(".bicep").SnapshotT(t, string(contents))
+		})
+	}
+
+	t.Run("containerApp", func(t *testing.T) {
+		containerApp, err := ContainerAppManifestTemplateForProject(manifest, "api")
+		require.NoError(t, err)
+
+		snapshot.NewConfig(".yaml").SnapshotT(t, containerApp)
+	})
+}
+
+func TestUnsupportedResourceType(t *testing.T) {
+	manifest := &Manifest{
+		Resources: map[string]*Resource{
+			"search": {
+				Type: "azure.search.v0",
+			},
+		},
+	}
+
+	_, err := BicepTemplate(manifest)
+	require.EqualError(t, err, "unsupported resource type azure.search.v0 of resource search")
+}
+
+func TestOpenAIDeploymentWithoutModel(t *testing.T) {
+	manifest := &Manifest{
+		Resources: map[string]*Resource{
+			"openai": {
+				Type: "azure.openai.account.v0",
+				Deployments: []OpenAIDeployment{
+					{
+						Name: "chat",
+					},
+				},
+			},
+		},
+	}
+
+	_, err := BicepTemplate(manifest)
+	require.ErrorContains(t, err, "require a name, modelName and modelVersion")
+}
+
+func TestValidateManifest(t *testing.T) {
+	manifest := &Manifest{
+		Resources: map[string]*Resource{
+			"search": {
+				Type: "azure.search.v0",
+			},
+			"api": {
+				Type: "project.v0",
+			},
+			"cosmos": {
+				Type: "azure.cosmosdb.account.v0",
+			},
+		},
+	}
+
+	err := ValidateManifest(manifest)
+
+	var unsupportedErr *UnsupportedResourcesError
+	require.ErrorAs(t, err, &unsupportedErr)
+	require.Equal(t, []UnsupportedResource{
+		{Name: "cosmos", Type: "azure.cosmosdb.account.v0"},
+		{Name: "search", Type: "azure.search.v0"},
+	}, unsupportedErr.Resources)
+	require.ErrorContains(t, err, "\n  cosmos (azure.cosmosdb.account.v0)\n  search (azure.search.v0)")
+
+	t.Setenv("AZD_DEBUG_DOTNET_APPHOST_IGNORE_UNSUPPORTED_RESOURCES", "true")
+	require.NoError(t, ValidateManifest(manifest))
+}
+
+// Verify that every resource type accepted by ValidateManifest is translated by the generator.
+func TestSupportedResourceTypes(t *testing.T) {
+	value := "value"
+	for resourceType := range resourceLoaders {
+		manifest := &Manifest{
+			Resources: map[string]*Resource{
+				"resource": {
+					Type:             resourceType,
+					Path:             &value,
+					Image:            &value,
+					Parent:           &value,
+					ConnectionString: &value,
+				},
+			},
+		}
+
+		require.NoError(t, ValidateManifest(manifest), resourceType)
+		require.NoError(t, newInfraGenerator().LoadManifest(manifest), resourceType)
+	}
+}
@@ -17,6 +17,18 @@ type genContainerAppEnvironmentServices struct {
 
 type genKeyVault struct{}
 
+type genOpenAIAccount struct {
+	Deployments []genOpenAIDeployment
+}
+
+type genOpenAIDeployment struct {
+	Name         string
+	ModelName    string
+	ModelVersion string
+	SkuName      string
+	SkuCapacity  int
+}
+
 type genContainerApp struct {
 	Image   string
 	Ingress *genContainerServiceIngress
@@ -49,6 +61,7 @@ type genBicepTemplateContext struct {
 	ServiceBuses                    map[string]genServiceBus
 	StorageAccounts                 map[string]genStorageAccount
 	KeyVaults                       map[string]genKeyVault
+	OpenAIAccounts                  map[string]genOpenAIAccount
 	ContainerAppEnvironmentServices map[string]genContainerAppEnvironmentServices
 	ContainerApps                   map[string]genContainerApp
 }
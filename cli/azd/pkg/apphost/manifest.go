@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/sethvargo/go-retry"
 )
 
 type Manifest struct {
@@ -49,6 +53,30 @@ type Resource struct {
 	// Some resources just represent connections to existing resources that need not be provisioned.  These resources have
 	// a "connectionString" property which is the connection string that should be used during binding.
 	ConnectionString *string `json:"connectionString,omitempty"`
+
+	// Deployments is optionally present on a azure.openai.account.v0 resource, and is a list of models to deploy to the
+	// account.
+	Deployments []OpenAIDeployment `json:"deployments,omitempty"`
+}
+
+// OpenAIDeployment is the deployment of a model to an Azure OpenAI account.
+type OpenAIDeployment struct {
+	// Name is the name of the deployment, which apps use to call the model.
+	Name string `json:"name"`
+
+	// ModelName is the name of the model to deploy, for example "gpt-35-turbo".
+	ModelName string `json:"modelName"`
+
+	// ModelVersion is the version of the model to deploy, for example "0613".
+	ModelVersion string `json:"modelVersion"`
+
+	// Sku is the SKU of the deployment. When it is nil, the deployment uses the Standard SKU with a capacity of 1.
+	Sku *OpenAIDeploymentSku `json:"sku,omitempty"`
+}
+
+type OpenAIDeploymentSku struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
 }
 
 type Reference struct {
@@ -63,8 +91,89 @@ type Binding struct {
 	External      bool   `json:"external"`
 }
 
-// ManifestFromAppHost returns the Manifest from the given app host.
+// The default number of times generating the manifest is retried after a transient error. It can be changed with the
+// AZD_DOTNET_APPHOST_MANIFEST_RETRIES environment variable.
+const defaultManifestRetries = 3
+
+// The delay before retrying to generate the manifest the first time, which is doubled for each retry after it.
+const defaultManifestRetryDelay = 2 * time.Second
+
+var manifestRetryDelay = defaultManifestRetryDelay
+
+// The output of the dotnet CLI when generating the manifest failed for reasons that may not occur again, such as a NuGet
+// feed that couldn't be reached during restore or a build output locked by another build.
+var transientManifestErrors = []string{
+	// Unable to load the service index for source
+	"NU1301",
+	// Child node exited prematurely
+	"MSB4166",
+	// Could not copy a file, it is locked by another process
+	"MSB3026",
+	"MSB3027",
+	"being used by another process",
+	"An existing connection was forcibly closed",
+	"The SSL connection could not be established",
+}
+
+// ManifestFromAppHost returns the Manifest from the given app host. Generating the manifest is retried with an exponential
+// backoff when it fails with a transient error, other errors are returned immediately.
 func ManifestFromAppHost(ctx context.Context, appHostProject string, dotnetCli dotnet.DotNetCli) (*Manifest, error) {
+	retries := manifestRetries()
+	attempt := 0
+
+	var manifest *Manifest
+	err := retry.Do(
+		ctx,
+		retry.WithMaxRetries(retries, retry.NewExponential(manifestRetryDelay)),
+		func(ctx context.Context) error {
+			attempt++
+			log.Printf("generating manifest of app host %s, attempt %d of %d", appHostProject, attempt, retries+1)
+
+			m, err := manifestFromAppHost(ctx, appHostProject, dotnetCli)
+			if err != nil && isTransientManifestError(err) {
+				log.Printf("generating manifest of app host %s failed with a transient error: %v", appHostProject, err)
+				return retry.RetryableError(err)
+			} else if err != nil {
+				return err
+			}
+
+			manifest = m
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// manifestRetries returns the number of times generating the manifest is retried after a transient error.
+func manifestRetries() uint64 {
+	if value := os.Getenv("AZD_DOTNET_APPHOST_MANIFEST_RETRIES"); value != "" {
+		if retries, err := strconv.ParseUint(value, 10, 0); err == nil {
+			return retries
+		}
+
+		log.Printf("ignoring invalid AZD_DOTNET_APPHOST_MANIFEST_RETRIES value: %s", value)
+	}
+
+	return defaultManifestRetries
+}
+
+// isTransientManifestError returns true when generating the manifest failed with an error that may not occur again, so
+// generating the manifest is worth retrying. Errors such as an app host that doesn't build are not transient.
+func isTransientManifestError(err error) bool {
+	message := strings.ToLower(err.Error())
+	for _, transientErr := range transientManifestErrors {
+		if strings.Contains(message, strings.ToLower(transientErr)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func manifestFromAppHost(ctx context.Context, appHostProject string, dotnetCli dotnet.DotNetCli) (*Manifest, error) {
 	tempDir, err := os.MkdirTemp("", "azd-provision")
 	if err != nil {
 		return nil, fmt.Errorf("creating temp directory for apphost-manifest.json: %w", err)
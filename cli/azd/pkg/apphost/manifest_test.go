@@ -0,0 +1,97 @@
+package apphost
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestFromAppHost(t *testing.T) {
+	manifestRetryDelay = time.Millisecond
+	t.Cleanup(func() {
+		manifestRetryDelay = defaultManifestRetryDelay
+	})
+
+	transientErr := errors.New("exit code: 1, stdout: error NU1301: Unable to load the service index for source " +
+		"https://api.nuget.org/v3/index.json., stderr: ")
+	fatalErr := errors.New("exit code: 1, stdout: Program.cs(3,1): error CS0103: The name 'foo' does not exist, stderr: ")
+
+	tests := []struct {
+		name         string
+		retries      string
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "Success",
+			wantAttempts: 1,
+		},
+		{
+			name:         "TransientError",
+			errs:         []error{transientErr, transientErr},
+			wantAttempts: 3,
+		},
+		{
+			name:         "TransientErrorRetriesExhausted",
+			errs:         []error{transientErr, transientErr, transientErr, transientErr},
+			wantAttempts: 4,
+			wantErr:      transientErr,
+		},
+		{
+			name:         "TransientErrorRetriesConfigured",
+			retries:      "1",
+			errs:         []error{transientErr, transientErr},
+			wantAttempts: 2,
+			wantErr:      transientErr,
+		},
+		{
+			name:         "FatalError",
+			errs:         []error{fatalErr},
+			wantAttempts: 1,
+			wantErr:      fatalErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AZD_DOTNET_APPHOST_MANIFEST_RETRIES", tt.retries)
+
+			attempts := 0
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "dotnet run") && strings.Contains(command, "--publisher manifest")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return exec.NewRunResult(1, "", ""), tt.errs[attempts-1]
+				}
+
+				outputPath := args.Args[len(args.Args)-1]
+				err := os.WriteFile(outputPath, []byte(`{"resources":{"api":{"type":"project.v0"}}}`), 0600)
+				return exec.NewRunResult(0, "", ""), err
+			})
+
+			manifest, err := ManifestFromAppHost(
+				*mockContext.Context, "AppHost.csproj", dotnet.NewDotNetCli(mockContext.CommandRunner))
+			require.Equal(t, tt.wantAttempts, attempts)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Nil(t, manifest)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "project.v0", manifest.Resources["api"].Type)
+		})
+	}
+}
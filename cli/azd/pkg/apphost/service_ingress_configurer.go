@@ -20,25 +20,19 @@ func NewIngressSelector(manifest *Manifest, console input.Console) *IngressSelec
 }
 
 func (adc *IngressSelector) SelectPublicServices(ctx context.Context) ([]string, error) {
-	var services []string
-	for name, res := range adc.manifest.Resources {
-		if (res.Type == "container.v0" || res.Type == "project.v0") && len(res.Bindings) > 0 {
-			services = append(services, name)
-		}
-	}
-
+	services := ExposableServices(adc.manifest)
 	if len(services) == 0 {
 		return nil, nil
 	}
 
-	slices.Sort(services)
-
 	adc.console.Message(ctx, "By default, a service can only be reached from inside the Azure Container Apps environment "+
 		"it is running in. Selecting a service here will also allow it to be reached from the Internet.")
 
 	exposed, err := adc.console.MultiSelect(ctx, input.ConsoleOptions{
 		Message: "Select which services to expose to the Internet",
 		Options: services,
+		// no service is exposed unless the user selects it.
+		DefaultValue: []string{},
 	})
 	if err != nil {
 		return nil, err
@@ -46,3 +40,17 @@ func (adc *IngressSelector) SelectPublicServices(ctx context.Context) ([]string,
 
 	return exposed, nil
 }
+
+// ExposableServices returns the names of the services in the manifest that can be exposed to the Internet, sorted by
+// name.
+func ExposableServices(manifest *Manifest) []string {
+	var services []string
+	for name, res := range manifest.Resources {
+		if (res.Type == "container.v0" || res.Type == "project.v0") && len(res.Bindings) > 0 {
+			services = append(services, name)
+		}
+	}
+
+	slices.Sort(services)
+	return services
+}
@@ -0,0 +1,35 @@
+package apphost
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPublicServicesNoPrompt(t *testing.T) {
+	manifest := &Manifest{
+		Resources: map[string]*Resource{
+			"api": {Type: "project.v0", Bindings: map[string]*Binding{"http": {}}},
+			"web": {Type: "container.v0", Bindings: map[string]*Binding{"http": {}}},
+			"db":  {Type: "postgres.server.v0"},
+		},
+	}
+	require.Equal(t, []string{"api", "web"}, ExposableServices(manifest))
+
+	stdout := &bytes.Buffer{}
+	console := input.NewConsole(true, false, false, stdout, input.ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{})
+
+	// services are private unless they're selected
+	exposed, err := NewIngressSelector(manifest, console).SelectPublicServices(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, exposed)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// azurePipelinesFederatedTokenClient fetches federated tokens of Azure Resource Manager service connections when running in
+// Azure Pipelines, with the OIDC token endpoint of the job.
+type azurePipelinesFederatedTokenClient struct {
+	pipeline runtime.Pipeline
+}
+
+func newAzurePipelinesFederatedTokenClient(options *policy.ClientOptions) *azurePipelinesFederatedTokenClient {
+	pipeline := runtime.NewPipeline("azure-pipelines", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{
+			&systemAccessTokenAuthPolicy{},
+		},
+	}, options)
+
+	return &azurePipelinesFederatedTokenClient{
+		pipeline: pipeline,
+	}
+}
+
+// TokenForServiceConnection gets the federated token of the service connection from the job, see
+// https://learn.microsoft.com/rest/api/azure/devops/distributedtask/oidctoken/create
+func (c *azurePipelinesFederatedTokenClient) TokenForServiceConnection(
+	ctx context.Context, serviceConnectionID string,
+) (string, error) {
+	oidcRequestUrl, has := os.LookupEnv("SYSTEM_OIDCREQUESTURI")
+	if !has {
+		return "", errors.New("no SYSTEM_OIDCREQUESTURI set in the environment")
+	}
+
+	requestUrl, err := url.Parse(oidcRequestUrl)
+	if err != nil {
+		return "", fmt.Errorf("parsing SYSTEM_OIDCREQUESTURI: %w", err)
+	}
+
+	query := requestUrl.Query()
+	query.Set("api-version", "7.1")
+	query.Set("serviceConnectionId", serviceConnectionID)
+	requestUrl.RawQuery = query.Encode()
+
+	req, err := runtime.NewRequest(ctx, http.MethodPost, requestUrl.String())
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	req.Raw().Header.Set("Content-Type", "application/json")
+
+	res, err := c.pipeline.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sending request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if !runtime.HasStatusCode(res, http.StatusOK) {
+		return "", fmt.Errorf("expected 200 response, got: %d", res.StatusCode)
+	}
+
+	tokenResponse, err := httputil.ReadRawResponse[oidcTokenResponse](res)
+	if err != nil {
+		return "", fmt.Errorf("reading body: %w", err)
+	}
+
+	if tokenResponse.OidcToken == "" {
+		return "", errors.New("no token in response")
+	}
+
+	return tokenResponse.OidcToken, nil
+}
+
+type oidcTokenResponse struct {
+	OidcToken string `json:"oidcToken"`
+}
+
+type systemAccessTokenAuthPolicy struct{}
+
+// Do authorizes a request with the access token of the job
+func (b *systemAccessTokenAuthPolicy) Do(req *policy.Request) (*http.Response, error) {
+	token, has := os.LookupEnv("SYSTEM_ACCESSTOKEN")
+	if !has {
+		return nil, errors.New("no SYSTEM_ACCESSTOKEN set in environment")
+	}
+
+	req.Raw().Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return req.Next()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenForServiceConnection(t *testing.T) {
+	t.Setenv("SYSTEM_OIDCREQUESTURI", "https://dev.azure.com/org/project/_apis/oidctoken")
+	t.Setenv("SYSTEM_ACCESSTOKEN", "fake-token")
+
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var req http.Request
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		req = *request
+		return true
+	}).Respond(&http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(`{ "oidcToken": "abc" }`)),
+	})
+
+	client := newAzurePipelinesFederatedTokenClient(&policy.ClientOptions{
+		Transport: mockContext.HttpClient,
+	})
+
+	token, err := client.TokenForServiceConnection(context.Background(), "SERVICE_CONNECTION_ID")
+	require.NoError(t, err)
+
+	require.Equal(t, "abc", token)
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "Bearer fake-token", req.Header.Get("Authorization"))
+	require.Equal(t,
+		"https://dev.azure.com/org/project/_apis/oidctoken?api-version=7.1&serviceConnectionId=SERVICE_CONNECTION_ID",
+		req.URL.String())
+}
+
+func TestTokenForServiceConnectionFailure(t *testing.T) {
+	t.Setenv("SYSTEM_OIDCREQUESTURI", "https://dev.azure.com/org/project/_apis/oidctoken")
+	t.Setenv("SYSTEM_ACCESSTOKEN", "fake-token")
+
+	mockContext := mocks.NewMockContext(context.Background())
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return true
+	}).Respond(&http.Response{
+		StatusCode: 401,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+	})
+
+	client := newAzurePipelinesFederatedTokenClient(&policy.ClientOptions{
+		Transport: mockContext.HttpClient,
+	})
+
+	_, err := client.TokenForServiceConnection(context.Background(), "SERVICE_CONNECTION_ID")
+	require.Error(t, err)
+}
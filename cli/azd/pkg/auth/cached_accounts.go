@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// CachedAccount is an account with tokens in the MSAL token cache of azd, in one tenant.
+type CachedAccount struct {
+	HomeAccountID string `json:"homeAccountId"`
+	Username      string `json:"username,omitempty"`
+	TenantID      string `json:"tenantId,omitempty"`
+	// ExpiresOn is when the latest cached access token of the account in the tenant expires, nil when there is no
+	// access token. Expired access tokens are refreshed with the refresh token of the account.
+	ExpiresOn *time.Time `json:"expiresOn,omitempty"`
+	// Current is true for the account azd is logged in with.
+	Current bool `json:"current"`
+}
+
+// ErrAccountNotCached is returned by ClearCachedAccount when the token cache has no account with the home account id.
+var ErrAccountNotCached = errors.New("account not found in the token cache")
+
+// CachedAccounts lists the accounts with tokens in the token cache.
+func (m *Manager) CachedAccounts(ctx context.Context) ([]CachedAccount, error) {
+	accounts, err := m.publicClient.Accounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+
+	var currentHomeAccountID string
+	if cfg, err := m.readAuthConfig(); err == nil {
+		if currentUser, err := readUserProperties(cfg); err == nil && currentUser.HomeAccountID != nil {
+			currentHomeAccountID = *currentUser.HomeAccountID
+		}
+	}
+
+	expiry := m.accessTokenExpiry()
+
+	cached := make([]CachedAccount, 0, len(accounts))
+	for _, account := range accounts {
+		cachedAccount := CachedAccount{
+			HomeAccountID: account.HomeAccountID,
+			Username:      account.PreferredUsername,
+			TenantID:      account.Realm,
+			Current:       account.HomeAccountID == currentHomeAccountID,
+		}
+
+		if expiresOn, has := expiry[accountTenant{account.HomeAccountID, account.Realm}]; has {
+			cachedAccount.ExpiresOn = &expiresOn
+		}
+
+		cached = append(cached, cachedAccount)
+	}
+
+	return cached, nil
+}
+
+// ClearCachedAccount removes the tokens of the account with the home account id from the token cache, in all of its
+// tenants. The tokens of other accounts are kept. When the account is the one azd is logged in with, the user is logged
+// out.
+func (m *Manager) ClearCachedAccount(ctx context.Context, homeAccountID string) error {
+	accounts, err := m.publicClient.Accounts(ctx)
+	if err != nil {
+		return fmt.Errorf("listing accounts: %w", err)
+	}
+
+	removed := false
+	for _, account := range accounts {
+		if account.HomeAccountID != homeAccountID {
+			continue
+		}
+
+		if err := m.publicClient.RemoveAccount(ctx, account); err != nil {
+			return fmt.Errorf("removing account from msal cache: %w", err)
+		}
+		removed = true
+	}
+
+	if !removed {
+		return fmt.Errorf("%w: '%s'", ErrAccountNotCached, homeAccountID)
+	}
+
+	cfg, err := m.readAuthConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	// the current user can no longer get tokens, so it is logged out.
+	if currentUser, err := readUserProperties(cfg); err == nil &&
+		currentUser.HomeAccountID != nil && *currentUser.HomeAccountID == homeAccountID {
+		if err := cfg.Unset(cCurrentUserKey); err != nil {
+			return fmt.Errorf("un-setting current user: %w", err)
+		}
+
+		if err := m.saveAuthConfig(cfg); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// accountTenant identifies the tokens of an account in a tenant.
+type accountTenant struct {
+	homeAccountID string
+	tenantID      string
+}
+
+// accessTokenExpiry returns when the latest access token of each account and tenant of the MSAL cache expires. The
+// access tokens aren't exposed by MSAL, so they are read from the contents of the cache.
+func (m *Manager) accessTokenExpiry() map[accountTenant]time.Time {
+	expiry := map[accountTenant]time.Time{}
+
+	adapter, ok := m.msalCache.(*msalCacheAdapter)
+	if !ok {
+		return expiry
+	}
+
+	data, err := adapter.cache.Read(cCurrentUserCacheKey)
+	if err != nil {
+		if !errors.Is(err, errCacheKeyNotFound) {
+			log.Printf("reading msal cache: %v", err)
+		}
+		return expiry
+	}
+
+	var contents struct {
+		AccessToken map[string]struct {
+			HomeAccountID string `json:"home_account_id"`
+			Realm         string `json:"realm"`
+			ExpiresOn     string `json:"expires_on"`
+		} `json:"AccessToken"`
+	}
+	if err := json.Unmarshal(data, &contents); err != nil {
+		log.Printf("parsing msal cache: %v", err)
+		return expiry
+	}
+
+	for _, token := range contents.AccessToken {
+		seconds, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		key := accountTenant{token.HomeAccountID, token.Realm}
+		if expiresOn := time.Unix(seconds, 0); expiresOn.After(expiry[key]) {
+			expiry[key] = expiresOn
+		}
+	}
+
+	return expiry
+}
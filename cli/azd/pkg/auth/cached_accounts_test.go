@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/stretchr/testify/require"
+)
+
+const testMsalCache = `{
+	"AccessToken": {
+		"first-token": {
+			"home_account_id": "user.home",
+			"realm": "home",
+			"expires_on": "1700000000"
+		},
+		"second-token": {
+			"home_account_id": "user.home",
+			"realm": "home",
+			"expires_on": "1700003600"
+		},
+		"guest-token": {
+			"home_account_id": "user.home",
+			"realm": "guest",
+			"expires_on": "invalid"
+		}
+	}
+}`
+
+func TestCachedAccounts(t *testing.T) {
+	m := newCachedAccountsManager(t)
+
+	accounts, err := m.CachedAccounts(context.Background())
+	require.NoError(t, err)
+
+	expiresOn := time.Unix(1700003600, 0)
+	require.Equal(t, []CachedAccount{
+		{
+			HomeAccountID: "user.home",
+			Username:      "user@contoso.com",
+			TenantID:      "home",
+			ExpiresOn:     &expiresOn,
+			Current:       true,
+		},
+		{
+			HomeAccountID: "user.home",
+			Username:      "user@contoso.com",
+			TenantID:      "guest",
+			Current:       true,
+		},
+		{
+			HomeAccountID: "other.home",
+			Username:      "other@contoso.com",
+			TenantID:      "other",
+		},
+	}, accounts)
+}
+
+func TestClearCachedAccount(t *testing.T) {
+	t.Run("NotCached", func(t *testing.T) {
+		m := newCachedAccountsManager(t)
+
+		err := m.ClearCachedAccount(context.Background(), "missing.home")
+		require.True(t, errors.Is(err, ErrAccountNotCached))
+	})
+
+	t.Run("OtherAccount", func(t *testing.T) {
+		m := newCachedAccountsManager(t)
+
+		require.NoError(t, m.ClearCachedAccount(context.Background(), "other.home"))
+
+		client := m.publicClient.(*cachedAccountsPublicClient)
+		require.Len(t, client.accounts, 2)
+
+		cfg, err := m.readAuthConfig()
+		require.NoError(t, err)
+		_, err = readUserProperties(cfg)
+		require.NoError(t, err)
+	})
+
+	t.Run("CurrentAccount", func(t *testing.T) {
+		m := newCachedAccountsManager(t)
+
+		require.NoError(t, m.ClearCachedAccount(context.Background(), "user.home"))
+
+		client := m.publicClient.(*cachedAccountsPublicClient)
+		require.Len(t, client.accounts, 1)
+		require.Equal(t, "other.home", client.accounts[0].HomeAccountID)
+
+		_, err := m.CredentialForCurrentUser(context.Background(), nil)
+		require.True(t, errors.Is(err, ErrNoCurrentUser))
+	})
+}
+
+func newCachedAccountsManager(t *testing.T) *Manager {
+	m := &Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		publicClient: &cachedAccountsPublicClient{
+			accounts: []public.Account{
+				{HomeAccountID: "user.home", PreferredUsername: "user@contoso.com", Realm: "home"},
+				{HomeAccountID: "user.home", PreferredUsername: "user@contoso.com", Realm: "guest"},
+				{HomeAccountID: "other.home", PreferredUsername: "other@contoso.com", Realm: "other"},
+			},
+		},
+		msalCache: &msalCacheAdapter{
+			cache: &memoryCache{
+				cache: map[string][]byte{
+					cCurrentUserCacheKey: []byte(testMsalCache),
+				},
+			},
+		},
+	}
+
+	homeAccountID := "user.home"
+	require.NoError(t, m.saveUserProperties(&userProperties{HomeAccountID: &homeAccountID}))
+
+	return m
+}
+
+// cachedAccountsPublicClient is a mockPublicClient with a list of accounts that can be removed.
+type cachedAccountsPublicClient struct {
+	mockPublicClient
+	accounts []public.Account
+}
+
+func (c *cachedAccountsPublicClient) Accounts(ctx context.Context) ([]public.Account, error) {
+	return c.accounts, nil
+}
+
+func (c *cachedAccountsPublicClient) RemoveAccount(ctx context.Context, account public.Account) error {
+	kept := []public.Account{}
+	for _, a := range c.accounts {
+		if a.HomeAccountID != account.HomeAccountID || a.Realm != account.Realm {
+			kept = append(kept, a)
+		}
+	}
+
+	c.accounts = kept
+	return nil
+}
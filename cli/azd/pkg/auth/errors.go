@@ -21,6 +21,10 @@ const cDefaultReloginScenario = "reauthentication required"
 // If the auth information or credentials are not found or invalid, the user is considered not to be logged in.
 var ErrNoCurrentUser = errors.New("not logged in, run `azd auth login` to login")
 
+// ErrBrowserLaunch is wrapped by the errors [WithOpenUrl] functions return when they can't launch a browser, such as on a
+// headless machine. LoginInteractive only falls back to a device code login on these errors.
+var ErrBrowserLaunch = errors.New("unable to launch a browser")
+
 // ReLoginRequiredError indicates that the logged in user needs to perform a log in to reauthenticate.
 // This typically means that while the credentials stored on the machine are valid, the server has rejected
 // the credentials due to expired credentials, or additional challenges being required.
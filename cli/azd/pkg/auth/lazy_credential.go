@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+)
+
+// lazyCredential is an azcore.TokenCredential that creates the credential it gets tokens from when the first token is
+// requested, rather than when it is created.
+type lazyCredential struct {
+	credential *lazy.Lazy[azcore.TokenCredential]
+}
+
+// NewLazyCredential returns a credential that calls create when a token is first requested, and gets tokens from the
+// credential it returns. When create fails, the error is returned by GetToken, and create is called again for the next
+// token, for example when the user logs in between requests.
+//
+// It's used by commands that don't need Azure, so that clients can be created for them without a login.
+func NewLazyCredential(create func() (azcore.TokenCredential, error)) azcore.TokenCredential {
+	return &lazyCredential{
+		credential: lazy.NewLazy(create),
+	}
+}
+
+func (c *lazyCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	credential, err := c.credential.GetValue()
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+
+	return credential.GetToken(ctx, options)
+}
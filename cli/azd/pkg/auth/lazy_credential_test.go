@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyCredential(t *testing.T) {
+	calls := 0
+	loggedIn := false
+	credential := NewLazyCredential(func() (azcore.TokenCredential, error) {
+		calls++
+		if !loggedIn {
+			return nil, ErrNoCurrentUser
+		}
+
+		return &mocks.MockCredentials{}, nil
+	})
+
+	// the credential isn't created until a token is requested
+	require.Equal(t, 0, calls)
+
+	_, err := credential.GetToken(context.Background(), policy.TokenRequestOptions{})
+	require.ErrorIs(t, err, ErrNoCurrentUser)
+	require.Equal(t, 1, calls)
+
+	loggedIn = true
+	_, err = credential.GetToken(context.Background(), policy.TokenRequestOptions{})
+	require.NoError(t, err)
+	_, err = credential.GetToken(context.Background(), policy.TokenRequestOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
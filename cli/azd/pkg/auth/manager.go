@@ -5,7 +5,10 @@ package auth
 
 import (
 	"context"
+	//nolint:gosec
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,10 +16,13 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
@@ -29,6 +35,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/cli/browser"
+	"github.com/mattn/go-isatty"
 )
 
 // TODO(azure/azure-dev#710): Right now, we re-use the App Id of the `az` CLI, until we have our own.
@@ -91,10 +98,12 @@ type HttpClient interface {
 type Manager struct {
 	publicClient        publicClient
 	publicClientOptions []public.Option
+	msalCache           cache.ExportReplace
 	configManager       config.FileConfigManager
 	userConfigManager   config.UserConfigManager
 	credentialCache     Cache
 	ghClient            *github.FederatedTokenClient
+	azdoClient          *azurePipelinesFederatedTokenClient
 	httpClient          HttpClient
 	console             input.Console
 }
@@ -120,8 +129,9 @@ func NewManager(
 		return nil, fmt.Errorf("creating msal cache root: %w", err)
 	}
 
+	msalCache := newCache(cacheRoot)
 	options := []public.Option{
-		public.WithCache(newCache(cacheRoot)),
+		public.WithCache(msalCache),
 		public.WithAuthority(cDefaultAuthority),
 		public.WithHTTPClient(httpClient),
 	}
@@ -131,15 +141,19 @@ func NewManager(
 		return nil, fmt.Errorf("creating msal client: %w", err)
 	}
 
-	ghClient := github.NewFederatedTokenClient(nil)
+	federatedTokenClientOptions := &policy.ClientOptions{
+		Transport: httpClient,
+	}
 
 	return &Manager{
 		publicClient:        &msalPublicClientAdapter{client: &publicClientApp},
 		publicClientOptions: options,
+		msalCache:           msalCache,
 		configManager:       configManager,
 		userConfigManager:   userConfigManager,
 		credentialCache:     newCredentialCache(authRoot),
-		ghClient:            ghClient,
+		ghClient:            github.NewFederatedTokenClient(federatedTokenClientOptions),
+		azdoClient:          newAzurePipelinesFederatedTokenClient(federatedTokenClientOptions),
 		httpClient:          httpClient,
 		console:             console,
 	}, nil
@@ -257,6 +271,8 @@ func (m *Manager) CredentialForCurrentUser(
 
 		if ps.ClientSecret != nil {
 			return m.newCredentialFromClientSecret(tenantID, *currentUser.ClientID, *ps.ClientSecret)
+		} else if ps.ClientCertificatePath != nil {
+			return m.newCredentialFromClientCertificateFile(tenantID, *currentUser.ClientID, ps)
 		} else if ps.ClientCertificate != nil {
 			return m.newCredentialFromClientCertificate(tenantID, *currentUser.ClientID, *ps.ClientCertificate)
 		} else if ps.FederatedAuth != nil && ps.FederatedAuth.TokenProvider != nil {
@@ -385,9 +401,72 @@ func (m *Manager) newCredentialFromClientCertificate(
 		return nil, fmt.Errorf("decoding certificate: %w: %w", err, ErrNoCurrentUser)
 	}
 
-	certs, key, err := azidentity.ParseCertificates(certData, nil)
+	cred, _, err := m.newCredentialFromClientCertificateData(tenantID, clientID, certData, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", err, ErrNoCurrentUser)
+	}
+
+	return cred, nil
+}
+
+// newCredentialFromClientCertificateFile creates a credential from the client certificate file saved when logging in.
+// The file is read each time, so that the private key is only stored in the file.
+func (m *Manager) newCredentialFromClientCertificateFile(
+	tenantID string,
+	clientID string,
+	ps *persistedSecret,
+) (azcore.TokenCredential, error) {
+	certData, err := os.ReadFile(*ps.ClientCertificatePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading certificate: %w: %w", err, ErrNoCurrentUser)
+	}
+
+	var password string
+	if ps.ClientCertificatePassword != nil {
+		password = *ps.ClientCertificatePassword
+	}
+
+	cred, thumbprint, err := m.newCredentialFromClientCertificateData(tenantID, clientID, certData, password)
 	if err != nil {
-		return nil, fmt.Errorf("parsing certificate: %w: %w", err, ErrNoCurrentUser)
+		return nil, fmt.Errorf("%w: %w", err, ErrNoCurrentUser)
+	}
+
+	if ps.ClientCertificateThumbprint != nil && *ps.ClientCertificateThumbprint != thumbprint {
+		log.Printf(
+			"the client certificate %s was replaced since logging in, its thumbprint changed from %s to %s",
+			*ps.ClientCertificatePath, *ps.ClientCertificateThumbprint, thumbprint)
+	}
+
+	return cred, nil
+}
+
+// newCredentialFromClientCertificateData creates a credential from a PEM or PKCS#12 certificate with its private key,
+// returning the thumbprint of the certificate. It fails when the certificate isn't valid at the current time.
+func (m *Manager) newCredentialFromClientCertificateData(
+	tenantID string,
+	clientID string,
+	certData []byte,
+	password string,
+) (azcore.TokenCredential, string, error) {
+	var passwordBytes []byte
+	if password != "" {
+		passwordBytes = []byte(password)
+	}
+
+	certs, key, err := azidentity.ParseCertificates(certData, passwordBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing certificate: %w", err)
+	}
+
+	// the first certificate is the one of the private key
+	now := time.Now()
+	if now.After(certs[0].NotAfter) {
+		return nil, "", fmt.Errorf(
+			"the client certificate expired on %s", certs[0].NotAfter.Format(time.RFC3339))
+	}
+	if now.Before(certs[0].NotBefore) {
+		return nil, "", fmt.Errorf(
+			"the client certificate is not valid before %s", certs[0].NotBefore.Format(time.RFC3339))
 	}
 
 	options := &azidentity.ClientCertificateCredentialOptions{
@@ -399,10 +478,13 @@ func (m *Manager) newCredentialFromClientCertificate(
 		tenantID, clientID, certs, key, options)
 
 	if err != nil {
-		return nil, fmt.Errorf("creating credential: %w: %w", err, ErrNoCurrentUser)
+		return nil, "", fmt.Errorf("creating credential: %w", err)
 	}
 
-	return cred, nil
+	// certificate thumbprints are SHA-1 hashes
+	//nolint:gosec
+	thumbprint := sha1.Sum(certs[0].Raw)
+	return cred, strings.ToUpper(hex.EncodeToString(thumbprint[:])), nil
 }
 
 func (m *Manager) newCredentialFromFederatedTokenProvider(
@@ -410,9 +492,12 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 	clientID string,
 	provider federatedTokenProvider,
 ) (azcore.TokenCredential, error) {
-	if provider != gitHubFederatedAuth {
-		return nil, fmt.Errorf("unsupported federated token provider: '%s'", string(provider))
+	if _, has := federatedTokenProviderEnvVars[provider]; !has {
+		return nil, fmt.Errorf(
+			"unsupported federated token provider: '%s', supported providers are: '%s' and '%s'",
+			string(provider), gitHubFederatedAuth, azurePipelinesFederatedAuth)
 	}
+
 	options := &azidentity.ClientAssertionCredentialOptions{
 		ClientOptions: policy.ClientOptions{
 			Transport: m.httpClient,
@@ -422,7 +507,21 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 		tenantID,
 		clientID,
 		func(ctx context.Context) (string, error) {
-			federatedToken, err := m.ghClient.TokenForAudience(ctx, "api://AzureADTokenExchange")
+			// The variables are checked when a token is requested rather than when the credential is created, so
+			// commands that never need a token keep working when a pipeline step doesn't map them.
+			if err := checkFederatedTokenProviderEnv(provider); err != nil {
+				return "", err
+			}
+
+			var federatedToken string
+			var err error
+			switch provider {
+			case azurePipelinesFederatedAuth:
+				federatedToken, err = m.azdoClient.TokenForServiceConnection(
+					ctx, os.Getenv("AZURESUBSCRIPTION_SERVICE_CONNECTION_ID"))
+			default:
+				federatedToken, err = m.ghClient.TokenForAudience(ctx, "api://AzureADTokenExchange")
+			}
 			if err != nil {
 				return "", fmt.Errorf("fetching federated token: %w", err)
 			}
@@ -437,6 +536,25 @@ func (m *Manager) newCredentialFromFederatedTokenProvider(
 	return cred, nil
 }
 
+// checkFederatedTokenProviderEnv returns an error naming any environment variables the provider needs to fetch a
+// federated token that aren't set.
+func checkFederatedTokenProviderEnv(provider federatedTokenProvider) error {
+	envVars := federatedTokenProviderEnvVars[provider]
+	missing := []string{}
+	for _, envVar := range envVars {
+		if _, has := os.LookupEnv(envVar); !has {
+			missing = append(missing, envVar)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf(
+			"the '%s' federated token provider requires the %s environment variables, missing: %s",
+			string(provider), strings.Join(envVars, ", "), strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
 func (m *Manager) newCredentialFromCloudShell() (azcore.TokenCredential, error) {
 	return NewCloudShellCredential(m.httpClient), nil
 }
@@ -449,9 +567,13 @@ type LoginInteractiveOptions struct {
 	TenantID     string
 	RedirectPort int
 	WithOpenUrl  WithOpenUrl
+	// When true, the user logs in with a device code when WithOpenUrl fails to launch a browser, for example on a
+	// headless machine, which it reports by returning an error wrapping [ErrBrowserLaunch].
+	DeviceCodeFallback bool
 }
 
-// LoginInteractive opens a browser for authenticate the user.
+// LoginInteractive opens a browser for authenticate the user. When options.DeviceCodeFallback is set and the browser
+// can't be launched, the user logs in with a device code instead.
 func (m *Manager) LoginInteractive(
 	ctx context.Context,
 	scopes []string,
@@ -479,7 +601,20 @@ func (m *Manager) LoginInteractive(
 
 	res, err := m.publicClient.AcquireTokenInteractive(ctx, scopes, acquireTokenOptions...)
 	if err != nil {
-		return nil, err
+		if !options.DeviceCodeFallback || !errors.Is(err, ErrBrowserLaunch) {
+			return nil, err
+		}
+
+		log.Printf("interactive login failed, falling back to device code login: %v", err)
+		m.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: "Unable to log in with a browser on this machine, logging in with a device code instead.",
+		})
+
+		// the browser couldn't be launched, so the device code login only prints the url to browse to
+		return m.LoginWithDeviceCode(ctx, options.TenantID, scopes, func(url string) error {
+			m.console.Message(ctx, fmt.Sprintf("To sign in, go to %s from a browser on any device.", url))
+			return nil
+		})
 	}
 
 	if err := m.saveLoginForPublicClient(res); err != nil {
@@ -529,7 +664,14 @@ func (m *Manager) LoginWithDeviceCode(
 				"Then press enter and continue to log in from your browser...",
 			},
 		})
-		m.console.WaitForEnter()
+
+		// there's no enter to wait for without a terminal, and the wait ends when the code expires, since the login
+		// can't complete after that anyway
+		if stdin, ok := m.console.Handles().Stdin.(*os.File); ok && isatty.IsTerminal(stdin.Fd()) {
+			waitCtx, cancel := context.WithDeadline(ctx, code.ExpiresOn())
+			m.console.WaitForEnterCtx(waitCtx)
+			cancel()
+		}
 
 		if err := withOpenUrl(url); err != nil {
 			log.Println("error launching browser: ", err.Error())
@@ -573,38 +715,51 @@ func (m *Manager) LoginWithServicePrincipalSecret(
 	return cred, nil
 }
 
+// LoginWithServicePrincipalCertificate logs in as the service principal with the client certificate file, a PEM or
+// PKCS#12 (PFX) file which holds the private key, encrypted with the password when it is not empty. The path and
+// thumbprint of the certificate are saved, and the file is read again by later commands, so the private key is only
+// stored in the file.
 func (m *Manager) LoginWithServicePrincipalCertificate(
-	ctx context.Context, tenantId, clientId string, certData []byte,
+	ctx context.Context, tenantId, clientId, certificatePath, password string,
 ) (azcore.TokenCredential, error) {
-	certs, key, err := azidentity.ParseCertificates(certData, nil)
+	certificatePath, err := filepath.Abs(certificatePath)
 	if err != nil {
-		return nil, fmt.Errorf("parsing certificate: %w", err)
+		return nil, fmt.Errorf("resolving certificate path: %w", err)
 	}
 
-	cred, err := azidentity.NewClientCertificateCredential(tenantId, clientId, certs, key, nil)
+	certData, err := os.ReadFile(certificatePath)
 	if err != nil {
-		return nil, fmt.Errorf("creating credential: %w", err)
+		return nil, fmt.Errorf("reading certificate: %w", err)
 	}
 
-	encodedCert := base64.StdEncoding.EncodeToString(certData)
+	cred, thumbprint, err := m.newCredentialFromClientCertificateData(tenantId, clientId, certData, password)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := m.saveLoginForServicePrincipal(
-		tenantId,
-		clientId,
-		&persistedSecret{
-			ClientCertificate: &encodedCert,
-		},
-	); err != nil {
+	ps := &persistedSecret{
+		ClientCertificatePath:       &certificatePath,
+		ClientCertificateThumbprint: &thumbprint,
+	}
+	if password != "" {
+		ps.ClientCertificatePassword = &password
+	}
+
+	if err := m.saveLoginForServicePrincipal(tenantId, clientId, ps); err != nil {
 		return nil, err
 	}
 
 	return cred, nil
 }
 
+// LoginWithServicePrincipalFederatedTokenProvider logs in with the federated token the CI system provides for the
+// service principal, such as the OIDC token of a GitHub Actions job. Only the provider is stored, the federated token is
+// fetched each time a token is requested.
 func (m *Manager) LoginWithServicePrincipalFederatedTokenProvider(
 	ctx context.Context, tenantId, clientId, provider string,
 ) (azcore.TokenCredential, error) {
-	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, federatedTokenProvider(provider))
+	tokenProvider := federatedTokenProvider(provider)
+	cred, err := m.newCredentialFromFederatedTokenProvider(tenantId, clientId, tokenProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -614,7 +769,7 @@ func (m *Manager) LoginWithServicePrincipalFederatedTokenProvider(
 		clientId,
 		&persistedSecret{
 			FederatedAuth: &federatedAuth{
-				TokenProvider: &gitHubFederatedAuth,
+				TokenProvider: &tokenProvider,
 			},
 		},
 	); err != nil {
@@ -838,21 +993,39 @@ type persistedSecret struct {
 	ClientSecret *string `json:"clientSecret,omitempty"`
 
 	// The bytes of the client certificate, which can be presented to azidentity.ParseCertificates, encoded as a
-	// base64 string.
+	// base64 string. Logins before ClientCertificatePath was added store the certificate this way.
 	ClientCertificate *string `json:"clientCertificate,omitempty"`
 
+	// The absolute path of the client certificate file, which holds the private key of the certificate.
+	ClientCertificatePath *string `json:"clientCertificatePath,omitempty"`
+
+	// The SHA-1 thumbprint of the client certificate, as a hex string.
+	ClientCertificateThumbprint *string `json:"clientCertificateThumbprint,omitempty"`
+
+	// The password of the client certificate file, when it is encrypted.
+	ClientCertificatePassword *string `json:"clientCertificatePassword,omitempty"`
+
 	// The federated auth credential.
 	FederatedAuth *federatedAuth `json:"federatedAuth,omitempty"`
 }
 
 // federated auth token providers
 var (
-	gitHubFederatedAuth federatedTokenProvider = "github"
+	gitHubFederatedAuth         federatedTokenProvider = "github"
+	azurePipelinesFederatedAuth federatedTokenProvider = "azure-pipelines"
 )
 
 // token provider for federated auth
 type federatedTokenProvider string
 
+// the environment variables the federated token providers read the federated token with
+var federatedTokenProviderEnvVars = map[federatedTokenProvider][]string{
+	gitHubFederatedAuth: {"ACTIONS_ID_TOKEN_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_TOKEN"},
+	azurePipelinesFederatedAuth: {
+		"SYSTEM_OIDCREQUESTURI", "SYSTEM_ACCESSTOKEN", "AZURESUBSCRIPTION_SERVICE_CONNECTION_ID",
+	},
+}
+
 // federatedAuth stores federated authentication information.
 type federatedAuth struct {
 	// The auth token provider. Tokens are obtained by calling the provider as needed.
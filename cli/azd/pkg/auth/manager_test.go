@@ -6,13 +6,15 @@ package auth
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
-
-	_ "embed"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -20,6 +22,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 	"github.com/stretchr/testify/require"
@@ -84,9 +87,6 @@ func TestServicePrincipalLoginClientSecret(t *testing.T) {
 	require.True(t, errors.Is(err, ErrNoCurrentUser))
 }
 
-//go:embed testdata/certificate.pem
-var cTestClientCertificate []byte
-
 func TestServicePrincipalLoginClientCertificate(t *testing.T) {
 	credentialCache := &memoryCache{
 		cache: make(map[string][]byte),
@@ -99,7 +99,7 @@ func TestServicePrincipalLoginClientCertificate(t *testing.T) {
 	}
 
 	cred, err := m.LoginWithServicePrincipalCertificate(
-		context.Background(), "testClientId", "testTenantId", cTestClientCertificate,
+		context.Background(), "testClientId", "testTenantId", filepath.Join("testdata", "certificate.pem"), "",
 	)
 
 	require.NoError(t, err)
@@ -119,6 +119,88 @@ func TestServicePrincipalLoginClientCertificate(t *testing.T) {
 	require.True(t, errors.Is(err, ErrNoCurrentUser))
 }
 
+func TestServicePrincipalLoginClientCertificateFile(t *testing.T) {
+	certPath := filepath.Join(t.TempDir(), "certificate.pfx")
+	pfx, err := os.ReadFile(filepath.Join("testdata", "certificate.pfx"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certPath, pfx, osutil.PermissionFile))
+
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache: &memoryCache{
+			cache: make(map[string][]byte),
+		},
+	}
+
+	_, err = m.LoginWithServicePrincipalCertificate(
+		context.Background(), "testClientId", "testTenantId", certPath, "wrong-password",
+	)
+	require.ErrorContains(t, err, "parsing certificate")
+
+	cred, err := m.LoginWithServicePrincipalCertificate(
+		context.Background(), "testClientId", "testTenantId", certPath, "password",
+	)
+	require.NoError(t, err)
+	require.IsType(t, new(azidentity.ClientCertificateCredential), cred)
+
+	// the path and the thumbprint of the certificate are saved, the private key stays in the file
+	ps, err := m.loadSecret("testClientId", "testTenantId")
+	require.NoError(t, err)
+	require.Nil(t, ps.ClientCertificate)
+	require.Equal(t, certPath, *ps.ClientCertificatePath)
+	require.Equal(t, "B32C892CC2BDC0B622BD145120993F5B2AB7D479", *ps.ClientCertificateThumbprint)
+	require.Equal(t, "password", *ps.ClientCertificatePassword)
+
+	cred, err = m.CredentialForCurrentUser(context.Background(), nil)
+	require.NoError(t, err)
+	require.IsType(t, new(azidentity.ClientCertificateCredential), cred)
+
+	// the file is read again by later commands
+	require.NoError(t, os.Remove(certPath))
+	_, err = m.CredentialForCurrentUser(context.Background(), nil)
+	require.ErrorIs(t, err, ErrNoCurrentUser)
+	require.ErrorContains(t, err, "reading certificate")
+}
+
+func TestServicePrincipalLoginClientCertificateExpired(t *testing.T) {
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache: &memoryCache{
+			cache: make(map[string][]byte),
+		},
+	}
+
+	_, err := m.LoginWithServicePrincipalCertificate(
+		context.Background(), "testClientId", "testTenantId", filepath.Join("testdata", "expired_certificate.pem"), "",
+	)
+	require.EqualError(t, err, "the client certificate expired on 2022-08-19T22:46:20Z")
+}
+
+func TestServicePrincipalClientCertificateStoredCertificate(t *testing.T) {
+	certData, err := os.ReadFile(filepath.Join("testdata", "certificate.pem"))
+	require.NoError(t, err)
+
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache: &memoryCache{
+			cache: make(map[string][]byte),
+		},
+	}
+
+	// logins before the certificate path was saved store the whole certificate
+	encodedCert := base64.StdEncoding.EncodeToString(certData)
+	require.NoError(t, m.saveLoginForServicePrincipal("testTenantId", "testClientId", &persistedSecret{
+		ClientCertificate: &encodedCert,
+	}))
+
+	cred, err := m.CredentialForCurrentUser(context.Background(), nil)
+	require.NoError(t, err)
+	require.IsType(t, new(azidentity.ClientCertificateCredential), cred)
+}
+
 func TestServicePrincipalLoginFederatedTokenProvider(t *testing.T) {
 	credentialCache := &memoryCache{
 		cache: make(map[string][]byte),
@@ -165,6 +247,71 @@ func TestServicePrincipalLoginFederatedTokenProvider(t *testing.T) {
 	require.True(t, errors.Is(err, ErrNoCurrentUser))
 }
 
+func TestServicePrincipalLoginFederatedTokenProviderAzurePipelines(t *testing.T) {
+	t.Setenv("SYSTEM_OIDCREQUESTURI", "https://dev.azure.com/org/project/_apis/oidctoken")
+	t.Setenv("SYSTEM_ACCESSTOKEN", "fake-token")
+	t.Setenv("AZURESUBSCRIPTION_SERVICE_CONNECTION_ID", "SERVICE_CONNECTION_ID")
+
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache: &memoryCache{
+			cache: make(map[string][]byte),
+		},
+		azdoClient: newAzurePipelinesFederatedTokenClient(nil),
+	}
+
+	cred, err := m.LoginWithServicePrincipalFederatedTokenProvider(
+		context.Background(), "testClientId", "testTenantId", "azure-pipelines",
+	)
+	require.NoError(t, err)
+	require.IsType(t, new(azidentity.ClientAssertionCredential), cred)
+
+	// only the provider is saved, no secret
+	ps, err := m.loadSecret("testClientId", "testTenantId")
+	require.NoError(t, err)
+	require.Nil(t, ps.ClientSecret)
+	require.Equal(t, azurePipelinesFederatedAuth, *ps.FederatedAuth.TokenProvider)
+
+	cred, err = m.CredentialForCurrentUser(context.Background(), nil)
+	require.NoError(t, err)
+	require.IsType(t, new(azidentity.ClientAssertionCredential), cred)
+}
+
+func TestServicePrincipalLoginFederatedTokenProviderMissingEnv(t *testing.T) {
+	m := Manager{
+		configManager:     newMemoryConfigManager(),
+		userConfigManager: newMemoryUserConfigManager(),
+		credentialCache: &memoryCache{
+			cache: make(map[string][]byte),
+		},
+	}
+
+	t.Setenv("SYSTEM_OIDCREQUESTURI", "https://dev.azure.com/org/project/_apis/oidctoken")
+	// t.Setenv restores the variables once the test completes
+	for _, envVar := range []string{"SYSTEM_ACCESSTOKEN", "AZURESUBSCRIPTION_SERVICE_CONNECTION_ID"} {
+		t.Setenv(envVar, "")
+		os.Unsetenv(envVar)
+	}
+
+	// the variables are only required once a token is fetched
+	_, err := m.LoginWithServicePrincipalFederatedTokenProvider(
+		context.Background(), "testClientId", "testTenantId", "azure-pipelines",
+	)
+	require.NoError(t, err)
+
+	err = checkFederatedTokenProviderEnv(azurePipelinesFederatedAuth)
+	require.EqualError(t, err,
+		"the 'azure-pipelines' federated token provider requires the SYSTEM_OIDCREQUESTURI, SYSTEM_ACCESSTOKEN, "+
+			"AZURESUBSCRIPTION_SERVICE_CONNECTION_ID environment variables, missing: SYSTEM_ACCESSTOKEN, "+
+			"AZURESUBSCRIPTION_SERVICE_CONNECTION_ID")
+
+	_, err = m.LoginWithServicePrincipalFederatedTokenProvider(
+		context.Background(), "testClientId", "testTenantId", "gitlab",
+	)
+	require.ErrorContains(t, err, "unsupported federated token provider: 'gitlab'")
+}
+
 func TestLegacyAzCliCredentialSupport(t *testing.T) {
 	mgr := newMemoryUserConfigManager()
 
@@ -225,6 +372,80 @@ func TestLoginInteractive(t *testing.T) {
 	require.True(t, errors.Is(err, ErrNoCurrentUser))
 }
 
+func TestLoginInteractiveDeviceCodeFallback(t *testing.T) {
+	browserErr := fmt.Errorf("%w: no browser available", ErrBrowserLaunch)
+
+	t.Run("Fallback", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		m := &Manager{
+			configManager:     newMemoryConfigManager(),
+			userConfigManager: newMemoryUserConfigManager(),
+			publicClient:      &mockPublicClient{interactiveErr: browserErr},
+			console:           console,
+		}
+
+		cred, err := m.LoginInteractive(context.Background(), nil, &LoginInteractiveOptions{
+			WithOpenUrl:        func(url string) error { return browserErr },
+			DeviceCodeFallback: true,
+		})
+		require.NoError(t, err)
+		require.IsType(t, new(azdCredential), cred)
+		require.Regexp(t, "logging in with a device code instead", console.Output())
+		require.Regexp(t, "Start by copying the next code: 123-456", console.Output())
+		require.Regexp(t, "To sign in, go to https://microsoft.com/devicelogin", console.Output())
+
+		// the device code login is saved like the interactive login
+		cred, err = m.CredentialForCurrentUser(context.Background(), nil)
+		require.NoError(t, err)
+		require.IsType(t, new(azdCredential), cred)
+	})
+
+	t.Run("NoFallback", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		m := &Manager{
+			configManager:     newMemoryConfigManager(),
+			userConfigManager: newMemoryUserConfigManager(),
+			publicClient:      &mockPublicClient{interactiveErr: browserErr},
+			console:           console,
+		}
+
+		_, err := m.LoginInteractive(context.Background(), nil, nil)
+		require.ErrorIs(t, err, browserErr)
+		require.Empty(t, console.Output())
+	})
+
+	t.Run("OtherError", func(t *testing.T) {
+		loginErr := errors.New("login failed")
+		console := mockinput.NewMockConsole()
+		m := &Manager{
+			configManager:     newMemoryConfigManager(),
+			userConfigManager: newMemoryUserConfigManager(),
+			publicClient:      &mockPublicClient{interactiveErr: loginErr},
+			console:           console,
+		}
+
+		// only failing to launch a browser falls back to a device code login
+		_, err := m.LoginInteractive(context.Background(), nil, &LoginInteractiveOptions{DeviceCodeFallback: true})
+		require.ErrorIs(t, err, loginErr)
+		require.Empty(t, console.Output())
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		m := &Manager{
+			configManager:     newMemoryConfigManager(),
+			userConfigManager: newMemoryUserConfigManager(),
+			publicClient:      &mockPublicClient{interactiveErr: context.Canceled},
+			console:           mockinput.NewMockConsole(),
+		}
+
+		_, err := m.LoginInteractive(ctx, nil, &LoginInteractiveOptions{DeviceCodeFallback: true})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
 func TestLoginDeviceCode(t *testing.T) {
 	console := mockinput.NewMockConsole()
 	m := &Manager{
@@ -331,6 +552,8 @@ func (m *memoryConfigManager) Save(cfg config.Config, path string) error {
 }
 
 type mockPublicClient struct {
+	// when set, AcquireTokenInteractive fails with this error
+	interactiveErr error
 }
 
 func (m *mockPublicClient) Accounts(ctx context.Context) ([]public.Account, error) {
@@ -348,6 +571,10 @@ func (m *mockPublicClient) RemoveAccount(ctx context.Context, account public.Acc
 func (m *mockPublicClient) AcquireTokenInteractive(
 	ctx context.Context, scopes []string, options ...public.AcquireInteractiveOption,
 ) (public.AuthResult, error) {
+	if m.interactiveErr != nil {
+		return public.AuthResult{}, m.interactiveErr
+	}
+
 	return public.AuthResult{
 		Account: public.Account{
 			HomeAccountID: "test.id",
@@ -381,6 +608,10 @@ func (m *mockDeviceCode) UserCode() string {
 	return "123-456"
 }
 
+func (m *mockDeviceCode) ExpiresOn() time.Time {
+	return time.Now().Add(15 * time.Minute)
+}
+
 func (m *mockDeviceCode) AuthenticationResult(ctx context.Context) (public.AuthResult, error) {
 	return public.AuthResult{
 		Account: public.Account{
@@ -5,6 +5,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
 )
@@ -21,6 +22,7 @@ type publicClient interface {
 type deviceCodeResult interface {
 	Message() string
 	UserCode() string
+	ExpiresOn() time.Time
 	AuthenticationResult(context.Context) (public.AuthResult, error)
 }
 
@@ -80,6 +82,10 @@ func (m *msalDeviceCodeAdapter) UserCode() string {
 	return m.code.Result.UserCode
 }
 
+func (m *msalDeviceCodeAdapter) ExpiresOn() time.Time {
+	return m.code.Result.ExpiresOn
+}
+
 func (m *msalDeviceCodeAdapter) AuthenticationResult(ctx context.Context) (public.AuthResult, error) {
 	res, err := m.code.AuthenticationResult(ctx)
 	if err != nil {
@@ -2,9 +2,11 @@ package azd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/cosmos"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -68,6 +70,7 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	// Remote Environment State Providers
 	remoteStateProviderMap := map[environment.RemoteKind]any{
 		environment.RemoteKindAzureBlobStorage: environment.NewStorageBlobDataStore,
+		environment.RemoteKindAzureCosmosDB:    environment.NewCosmosDataStore,
 	}
 
 	for remoteKind, constructor := range remoteStateProviderMap {
@@ -85,13 +88,8 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		}
 
 		var storageAccountConfig *storage.AccountConfig
-		jsonBytes, err := json.Marshal(remoteStateConfig.Config)
-		if err != nil {
-			return nil, fmt.Errorf("marshalling remote state config: %w", err)
-		}
-
-		if err := json.Unmarshal(jsonBytes, &storageAccountConfig); err != nil {
-			return nil, fmt.Errorf("unmarshalling remote state config: %w", err)
+		if err := unmarshalRemoteStateConfig(remoteStateConfig, &storageAccountConfig); err != nil {
+			return nil, err
 		}
 
 		// If a container name has not been explicitly configured
@@ -105,10 +103,40 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		return storageAccountConfig, nil
 	})
 
+	container.RegisterSingleton(func(
+		remoteStateConfig *state.RemoteConfig,
+		projectConfig *project.ProjectConfig,
+	) (*cosmos.AccountConfig, error) {
+		if remoteStateConfig == nil {
+			return nil, nil
+		}
+
+		var cosmosAccountConfig *cosmos.AccountConfig
+		if err := unmarshalRemoteStateConfig(remoteStateConfig, &cosmosAccountConfig); err != nil {
+			return nil, err
+		}
+
+		if cosmosAccountConfig.AccountName == "" || cosmosAccountConfig.DatabaseName == "" {
+			return nil, errors.New(
+				"remote state configuration is invalid. The 'cosmos' backend requires 'accountName' and 'databaseName'.")
+		}
+
+		// If a container name has not been explicitly configured
+		// Default to use the project name as the container name
+		if cosmosAccountConfig.ContainerName == "" {
+			cosmosAccountConfig.ContainerName = projectConfig.Name
+		}
+
+		return cosmosAccountConfig, nil
+	})
+
 	// Storage components
 	container.RegisterSingleton(storage.NewBlobClient)
 	container.RegisterSingleton(storage.NewBlobSdkClient)
 
+	// Cosmos DB components
+	container.RegisterSingleton(cosmos.NewContainerClient)
+
 	// Templates
 
 	// Gets a list of default template sources used in azd.
@@ -121,3 +149,17 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 
 	return nil
 }
+
+// unmarshalRemoteStateConfig unmarshals the backend specific config of the remote state configuration into target
+func unmarshalRemoteStateConfig(remoteStateConfig *state.RemoteConfig, target any) error {
+	jsonBytes, err := json.Marshal(remoteStateConfig.Config)
+	if err != nil {
+		return fmt.Errorf("marshalling remote state config: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonBytes, target); err != nil {
+		return fmt.Errorf("unmarshalling remote state config: %w", err)
+	}
+
+	return nil
+}
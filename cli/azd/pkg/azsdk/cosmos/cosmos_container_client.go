@@ -0,0 +1,381 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// AccountConfig contains the configuration for connecting to a container of a Cosmos DB account.
+// The container must be partitioned by the '/id' path.
+type AccountConfig struct {
+	AccountName   string
+	DatabaseName  string
+	ContainerName string
+	Endpoint      string
+}
+
+const (
+	DefaultEndpoint = "documents.azure.com"
+
+	// The version of the Cosmos DB REST API
+	apiVersion = "2018-12-31"
+)
+
+var (
+	ErrItemNotFound       = errors.New("item not found")
+	ErrItemConflict       = errors.New("item already exists")
+	ErrPreconditionFailed = errors.New("item was modified since it was read")
+)
+
+// Item is a JSON document stored in a Cosmos DB container
+type Item struct {
+	// The id of the document, which is also its partition key
+	Id string
+	// The entity tag of the current version of the document, set by Cosmos DB
+	ETag string
+	// The JSON document
+	Body []byte
+}
+
+type ContainerClient interface {
+	// ReadItem reads the item with the specified id from the configured container.
+	ReadItem(ctx context.Context, id string) (*Item, error)
+
+	// ListItems returns all the items of the configured container.
+	ListItems(ctx context.Context) ([]*Item, error)
+
+	// CreateItem creates an item in the configured container, failing with ErrItemConflict when the item already exists.
+	CreateItem(ctx context.Context, item *Item) (*Item, error)
+
+	// ReplaceItem replaces an item of the configured container. When the ETag of item is set, the replace fails with
+	// ErrPreconditionFailed unless the ETag matches the current version of the item.
+	ReplaceItem(ctx context.Context, item *Item) (*Item, error)
+
+	// DeleteItem deletes the item with the specified id from the configured container. When etag is set, the delete fails
+	// with ErrPreconditionFailed unless etag matches the current version of the item.
+	DeleteItem(ctx context.Context, id string, etag string) error
+}
+
+type containerClient struct {
+	config   *AccountConfig
+	pipeline runtime.Pipeline
+}
+
+// NewContainerClient creates a new ContainerClient instance to manage the items of a Cosmos DB container
+func NewContainerClient(
+	ctx context.Context,
+	credential azcore.TokenCredential,
+	accountConfig *AccountConfig,
+	httpClient httputil.HttpClient,
+	userAgent httputil.UserAgent,
+) ContainerClient {
+	if accountConfig.Endpoint == "" {
+		accountConfig.Endpoint = DefaultEndpoint
+	}
+
+	coreOptions := azsdk.
+		DefaultClientOptionsBuilder(ctx, httpClient, string(userAgent)).
+		BuildCoreClientOptions()
+
+	scope := fmt.Sprintf("https://%s.%s/.default", accountConfig.AccountName, accountConfig.Endpoint)
+	pipeline := runtime.NewPipeline("cosmos", "1.0.0", runtime.PipelineOptions{
+		PerRetry: []policy.Policy{newAuthorizationPolicy(credential, scope)},
+	}, coreOptions)
+
+	return &containerClient{
+		config:   accountConfig,
+		pipeline: pipeline,
+	}
+}
+
+func (c *containerClient) ReadItem(ctx context.Context, id string) (*Item, error) {
+	request, err := c.newRequest(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("reading item '%s': %w", id, err)
+	}
+
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, fmt.Errorf("reading item '%s': %w", id, describeResponseError(response))
+	}
+
+	return readItem(response)
+}
+
+func (c *containerClient) ListItems(ctx context.Context) ([]*Item, error) {
+	items := []*Item{}
+	continuation := ""
+
+	for {
+		request, err := c.newRequest(ctx, http.MethodGet, "", nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if continuation != "" {
+			request.Raw().Header.Set("x-ms-continuation", continuation)
+		}
+
+		page, err := c.listItemsPage(request)
+		if err != nil {
+			return nil, fmt.Errorf("listing items: %w", err)
+		}
+
+		items = append(items, page.items...)
+		continuation = page.continuation
+		if continuation == "" {
+			return items, nil
+		}
+	}
+}
+
+func (c *containerClient) CreateItem(ctx context.Context, item *Item) (*Item, error) {
+	request, err := c.newRequest(ctx, http.MethodPost, "", item)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("creating item '%s': %w", item.Id, err)
+	}
+
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusCreated) {
+		return nil, fmt.Errorf("creating item '%s': %w", item.Id, describeResponseError(response))
+	}
+
+	return readItem(response)
+}
+
+func (c *containerClient) ReplaceItem(ctx context.Context, item *Item) (*Item, error) {
+	request, err := c.newRequest(ctx, http.MethodPut, item.Id, item)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.ETag != "" {
+		request.Raw().Header.Set("If-Match", item.ETag)
+	}
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("replacing item '%s': %w", item.Id, err)
+	}
+
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, fmt.Errorf("replacing item '%s': %w", item.Id, describeResponseError(response))
+	}
+
+	return readItem(response)
+}
+
+func (c *containerClient) DeleteItem(ctx context.Context, id string, etag string) error {
+	request, err := c.newRequest(ctx, http.MethodDelete, id, nil)
+	if err != nil {
+		return err
+	}
+
+	if etag != "" {
+		request.Raw().Header.Set("If-Match", etag)
+	}
+
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return fmt.Errorf("deleting item '%s': %w", id, err)
+	}
+
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusNoContent) {
+		return fmt.Errorf("deleting item '%s': %w", id, describeResponseError(response))
+	}
+
+	return nil
+}
+
+type itemsPage struct {
+	items        []*Item
+	continuation string
+}
+
+func (c *containerClient) listItemsPage(request *policy.Request) (*itemsPage, error) {
+	response, err := c.pipeline.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, describeResponseError(response)
+	}
+
+	var body struct {
+		Documents []json.RawMessage `json:"Documents"`
+	}
+
+	if err := runtime.UnmarshalAsJSON(response, &body); err != nil {
+		return nil, fmt.Errorf("unmarshalling documents: %w", err)
+	}
+
+	page := &itemsPage{
+		items:        make([]*Item, 0, len(body.Documents)),
+		continuation: response.Header.Get("x-ms-continuation"),
+	}
+
+	for _, document := range body.Documents {
+		item, err := newItem(document)
+		if err != nil {
+			return nil, err
+		}
+
+		page.items = append(page.items, item)
+	}
+
+	return page, nil
+}
+
+// newRequest creates a request for the documents of the configured container, or for the document with the specified id
+// when id is not empty. The body of item is sent as the content of the request when item is not nil.
+func (c *containerClient) newRequest(
+	ctx context.Context,
+	method string,
+	id string,
+	item *Item,
+) (*policy.Request, error) {
+	endpoint := fmt.Sprintf(
+		"https://%s.%s/dbs/%s/colls/%s/docs",
+		c.config.AccountName,
+		c.config.Endpoint,
+		url.PathEscape(c.config.DatabaseName),
+		url.PathEscape(c.config.ContainerName),
+	)
+	if id != "" {
+		endpoint = fmt.Sprintf("%s/%s", endpoint, url.PathEscape(id))
+	}
+
+	request, err := runtime.NewRequest(ctx, method, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	request.Raw().Header.Set("x-ms-version", apiVersion)
+
+	partitionKey := id
+	if item != nil {
+		partitionKey = item.Id
+	}
+
+	if partitionKey != "" {
+		partitionKeyJson, err := json.Marshal([]string{partitionKey})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling partition key: %w", err)
+		}
+
+		request.Raw().Header.Set("x-ms-documentdb-partitionkey", string(partitionKeyJson))
+	}
+
+	if item != nil {
+		if err := request.SetBody(streaming.NopCloser(bytes.NewReader(item.Body)), "application/json"); err != nil {
+			return nil, fmt.Errorf("setting request body: %w", err)
+		}
+	}
+
+	return request, nil
+}
+
+// readItem reads the item returned in the body of response
+func readItem(response *http.Response) (*Item, error) {
+	body, err := runtime.Payload(response)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return newItem(body)
+}
+
+// newItem creates an item from a JSON document returned by Cosmos DB, which includes the system properties of the item
+func newItem(document []byte) (*Item, error) {
+	var properties struct {
+		Id   string `json:"id"`
+		ETag string `json:"_etag"`
+	}
+
+	if err := json.Unmarshal(document, &properties); err != nil {
+		return nil, fmt.Errorf("unmarshalling document: %w", err)
+	}
+
+	return &Item{
+		Id:   properties.Id,
+		ETag: properties.ETag,
+		Body: document,
+	}, nil
+}
+
+// describeResponseError returns the error of an unsuccessful response, wrapping the errors of this package for the status
+// codes callers are expected to handle
+func describeResponseError(response *http.Response) error {
+	err := runtime.NewResponseError(response)
+
+	switch response.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %w", ErrItemNotFound, err)
+	case http.StatusConflict:
+		return fmt.Errorf("%w: %w", ErrItemConflict, err)
+	case http.StatusPreconditionFailed:
+		return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+	}
+
+	return err
+}
+
+// authorizationPolicy authorizes requests to Cosmos DB with Microsoft Entra ID tokens. Cosmos DB doesn't accept bearer
+// tokens, the token is sent in the Cosmos DB specific format of the authorization header.
+type authorizationPolicy struct {
+	credential azcore.TokenCredential
+	scope      string
+}
+
+func newAuthorizationPolicy(credential azcore.TokenCredential, scope string) policy.Policy {
+	return &authorizationPolicy{
+		credential: credential,
+		scope:      scope,
+	}
+}
+
+func (p *authorizationPolicy) Do(request *policy.Request) (*http.Response, error) {
+	token, err := p.credential.GetToken(request.Raw().Context(), policy.TokenRequestOptions{
+		Scopes: []string{p.scope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting token for scope '%s': %w", p.scope, err)
+	}
+
+	request.Raw().Header.Set("Authorization", url.QueryEscape(fmt.Sprintf("type=aad&ver=1.0&sig=%s", token.Token)))
+	request.Raw().Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+
+	return request.Next()
+}
@@ -0,0 +1,177 @@
+package azsdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// Values of DeployStatus.Status for finished deployments
+const (
+	DeployStatusFailed  = 3
+	DeployStatusSuccess = 4
+)
+
+// publishStatusInterval is the interval between requests for the status of a publish operation.
+var publishStatusInterval = 10 * time.Second
+
+// FuncAppHostClient publishes function apps hosted on the Flex Consumption plan, with the publish (One Deploy) api of the
+// deployment endpoint of the app. The deployment endpoint stores the package in the deployment storage of the app.
+type FuncAppHostClient struct {
+	hostName string
+	pipeline runtime.Pipeline
+}
+
+// Creates a new FuncAppHostClient instance for the deployment endpoint with the given host name, for example
+// 'app.scm.azurewebsites.net'
+func NewFuncAppHostClient(
+	hostName string,
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*FuncAppHostClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	// We do not have a Resource provider to register
+	options.DisableRPRegistration = true
+
+	pipeline, err := armruntime.NewPipeline("func-app-host", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	return &FuncAppHostClient{
+		hostName: hostName,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Options of a publish operation
+type PublishOptions struct {
+	// When true, the deployment endpoint builds the app from the published package, for example installing the
+	// dependencies of Python apps.
+	RemoteBuild bool
+}
+
+// Publishes the zip package to the function app and waits for the deployment to complete
+func (c *FuncAppHostClient) Publish(
+	ctx context.Context,
+	zipFile io.Reader,
+	options *PublishOptions,
+) (*DeployResponse, error) {
+	if options == nil {
+		options = &PublishOptions{}
+	}
+
+	endpoint := fmt.Sprintf("https://%s/api/publish", c.hostName)
+	req, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("creating publish request: %w", err)
+	}
+
+	rawRequest := req.Raw()
+	rawRequest.Body = io.NopCloser(zipFile)
+	query := url.Values{}
+	query.Set("RemoteBuild", strconv.FormatBool(options.RemoteBuild))
+	query.Set("Deployer", "azd")
+	rawRequest.URL.RawQuery = query.Encode()
+	rawRequest.Header.Set("Content-Type", "application/zip")
+	rawRequest.Header.Set("Accept", "application/json")
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusAccepted) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	// The response is the id of the deployment, as a JSON string
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading publish response: %w", err)
+	}
+
+	var deploymentId string
+	if err := json.Unmarshal(body, &deploymentId); err != nil {
+		deploymentId = strings.TrimSpace(string(body))
+	}
+
+	if deploymentId == "" {
+		return nil, fmt.Errorf("publish response is missing the deployment id")
+	}
+
+	return c.waitForDeployment(ctx, deploymentId)
+}
+
+// waitForDeployment polls the status of the deployment until it completes, returning an error when it fails
+func (c *FuncAppHostClient) waitForDeployment(ctx context.Context, deploymentId string) (*DeployResponse, error) {
+	endpoint := fmt.Sprintf("https://%s/api/deployments/%s", c.hostName, url.PathEscape(deploymentId))
+
+	for {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := c.pipeline.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		// the deployment is not found until the deployment endpoint starts processing it
+		case runtime.HasStatusCode(response, http.StatusNotFound, http.StatusAccepted):
+			response.Body.Close()
+		case runtime.HasStatusCode(response, http.StatusOK):
+			status, err := httputil.ReadRawResponse[DeployStatus](response)
+			response.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case status.Status == DeployStatusFailed:
+				return nil, fmt.Errorf("deployment '%s' failed: %s", deploymentId, deployStatusMessage(status))
+			case status.Status == DeployStatusSuccess || status.Complete:
+				return &DeployResponse{DeployStatus: *status}, nil
+			}
+		default:
+			return nil, runtime.NewResponseError(response)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(publishStatusInterval):
+		}
+	}
+}
+
+// deployStatusMessage returns the message of a deployment, falling back to its status text.
+func deployStatusMessage(status *DeployStatus) string {
+	if status.Message != "" {
+		return status.Message
+	}
+
+	if status.StatusText != "" {
+		return status.StatusText
+	}
+
+	return fmt.Sprintf("status %d", status.Status)
+}
@@ -0,0 +1,120 @@
+package azsdk
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncAppHostClientPublish(t *testing.T) {
+	originalInterval := publishStatusInterval
+	t.Cleanup(func() {
+		publishStatusInterval = originalInterval
+	})
+	publishStatusInterval = 0
+
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerPublishMocks(t, mockContext, true)
+		registerPublishStatusMocks(mockContext, DeployStatusSuccess)
+
+		client := newTestFuncAppHostClient(t, mockContext)
+		response, err := client.Publish(
+			*mockContext.Context, bytes.NewBuffer([]byte{}), &PublishOptions{RemoteBuild: true})
+		require.NoError(t, err)
+		require.Equal(t, DeployStatusSuccess, response.Status)
+		require.Equal(t, "Deployment Complete", response.StatusText)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerPublishMocks(t, mockContext, false)
+		registerPublishStatusMocks(mockContext, DeployStatusFailed)
+
+		client := newTestFuncAppHostClient(t, mockContext)
+		response, err := client.Publish(*mockContext.Context, bytes.NewBuffer([]byte{}), nil)
+		require.Nil(t, response)
+		require.EqualError(t, err, "deployment 'DEPLOYMENT_ID' failed: Bad deploy package")
+	})
+
+	t.Run("WithInitialError", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/publish")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusConflict)
+		})
+
+		client := newTestFuncAppHostClient(t, mockContext)
+		response, err := client.Publish(*mockContext.Context, bytes.NewBuffer([]byte{}), nil)
+		require.Nil(t, response)
+		require.Error(t, err)
+	})
+}
+
+func newTestFuncAppHostClient(t *testing.T, mockContext *mocks.MockContext) *FuncAppHostClient {
+	options := NewClientOptionsBuilder().
+		WithTransport(mockContext.HttpClient).
+		BuildArmClientOptions()
+
+	client, err := NewFuncAppHostClient("APP_NAME.scm.azurewebsites.net", &mocks.MockCredentials{}, options)
+	require.NoError(t, err)
+
+	return client
+}
+
+func registerPublishMocks(t *testing.T, mockContext *mocks.MockContext, remoteBuild bool) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/publish")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Equal(t, "APP_NAME.scm.azurewebsites.net", request.URL.Host)
+		require.Equal(t, "application/zip", request.Header.Get("Content-Type"))
+		require.Equal(t, strconv.FormatBool(remoteBuild), request.URL.Query().Get("RemoteBuild"))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "DEPLOYMENT_ID")
+	})
+}
+
+func registerPublishStatusMocks(mockContext *mocks.MockContext, finalStatus int) {
+	pollCount := 0
+
+	// The deployment is not found until the deployment endpoint starts processing it
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/api/deployments/DEPLOYMENT_ID")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		pollCount += 1
+
+		switch {
+		case pollCount == 1:
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		case pollCount == 2:
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, DeployStatus{
+				Id:         "DEPLOYMENT_ID",
+				Status:     1,
+				StatusText: "Building",
+			})
+		case finalStatus == DeployStatusFailed:
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, DeployStatus{
+				Id:       "DEPLOYMENT_ID",
+				Status:   DeployStatusFailed,
+				Message:  "Bad deploy package",
+				Complete: true,
+			})
+		default:
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, DeployStatus{
+				Id:         "DEPLOYMENT_ID",
+				Status:     DeployStatusSuccess,
+				StatusText: "Deployment Complete",
+				Complete:   true,
+				Active:     true,
+				SiteName:   "APP_NAME",
+			})
+		}
+	})
+}
@@ -74,6 +74,14 @@ func ContainerAppRID(subscriptionId, resourceGroupName, containerAppName string)
 	return returnValue
 }
 
+func ContainerGroupRID(subscriptionId, resourceGroupName, containerGroupName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.ContainerInstance/containerGroups/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		containerGroupName,
+	)
+}
+
 func SpringAppRID(subscriptionId, resourceGroupName, springAppName string) string {
 	returnValue := fmt.Sprintf(
 		"%s/providers/Microsoft.AppPlatform/Spring/%s",
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+var (
+	// hostname of Bitbucket Cloud
+	BitbucketHostName = "bitbucket.org"
+	// base url of the Bitbucket Cloud REST API
+	BitbucketApiUrl = "https://api.bitbucket.org/2.0"
+	// environment variable that holds the Bitbucket access token, or the API token of BitbucketUsernameName
+	BitbucketAccessTokenName = "BITBUCKET_ACCESS_TOKEN"
+	// environment variable that holds the Bitbucket username or email the access token is an API token of. When it
+	// isn't set, the access token is a repository, project or workspace access token
+	BitbucketUsernameName = "BITBUCKET_USERNAME"
+	// environment variable that holds the Bitbucket workspace to create new repositories in
+	BitbucketEnvironmentWorkspaceName = "BITBUCKET_WORKSPACE"
+	// path to the Bitbucket Pipelines yaml, relative to the root of the repository
+	PipelineYamlPath = "bitbucket-pipelines.yml"
+)
+
+// EnsureAccessTokenExists returns the Bitbucket access token from the .env file or the system environment variables,
+// prompting for it when it isn't set. The returned bool indicates whether the token was prompted for.
+func EnsureAccessTokenExists(ctx context.Context, env *environment.Environment, console input.Console) (
+	string, bool, error) {
+	if value, exists := env.LookupEnv(BitbucketAccessTokenName); exists && value != "" {
+		return value, false, nil
+	}
+
+	console.Message(ctx, fmt.Sprintf(
+		"You need a %s with the repository admin and pipeline variable permissions. Create one by following the "+
+			"instructions here %s",
+		output.WithWarningFormat("Bitbucket access token"),
+		output.WithLinkFormat("https://support.atlassian.com/bitbucket-cloud/docs/access-tokens/")))
+	console.Message(ctx, fmt.Sprintf("(%s this prompt by setting the token to env var: %s)",
+		output.WithWarningFormat("%s", "skip"),
+		output.WithHighLightFormat("%s", BitbucketAccessTokenName)))
+
+	token, err := console.Prompt(ctx, input.ConsoleOptions{
+		Message:    "Bitbucket access token:",
+		IsPassword: true,
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("asking for bitbucket access token: %w", err)
+	}
+
+	// set the token as an environment variable for this cmd run
+	// note: the scope of this env var is only this shell invocation and won't be available in the caller parent shell
+	os.Setenv(BitbucketAccessTokenName, token)
+	return token, true, nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// ErrRepositoryNameInUse is returned by CreateRepository when the workspace already has a repository with the slug
+var ErrRepositoryNameInUse = errors.New("repository name already in use")
+
+// Repository is a Bitbucket Cloud repository
+type Repository struct {
+	Uuid     string          `json:"uuid"`
+	Slug     string          `json:"slug"`
+	FullName string          `json:"full_name"`
+	Links    RepositoryLinks `json:"links"`
+}
+
+type RepositoryLinks struct {
+	Html  Link        `json:"html"`
+	Clone []CloneLink `json:"clone"`
+}
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+type CloneLink struct {
+	// The protocol of the link, https or ssh
+	Name string `json:"name"`
+	Href string `json:"href"`
+}
+
+// CloneUrl returns the clone url of the repository for the protocol, https or ssh
+func (r *Repository) CloneUrl(protocol string) string {
+	for _, link := range r.Links.Clone {
+		if link.Name == protocol {
+			return link.Href
+		}
+	}
+
+	return ""
+}
+
+// Variable is a repository variable of Bitbucket Pipelines
+type Variable struct {
+	Uuid    string `json:"uuid,omitempty"`
+	Key     string `json:"key"`
+	Value   string `json:"value,omitempty"`
+	Secured bool   `json:"secured"`
+}
+
+type variablesPage struct {
+	Values []Variable `json:"values"`
+	Next   string     `json:"next"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Client calls the Bitbucket Cloud REST API, authenticating with an access token, or with the API token of a user
+type Client struct {
+	httpClient httputil.HttpClient
+	baseUrl    string
+	username   string
+	token      string
+}
+
+// NewClient creates a client for the Bitbucket Cloud REST API. When username is empty, token is used as a bearer
+// access token, otherwise it's the API token or app password of username.
+func NewClient(httpClient httputil.HttpClient, username string, token string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		baseUrl:    BitbucketApiUrl,
+		username:   username,
+		token:      token,
+	}
+}
+
+// GetRepository gets the repository of the workspace
+func (c *Client) GetRepository(ctx context.Context, workspace string, repoSlug string) (*Repository, error) {
+	var repo Repository
+	if err := c.send(ctx, http.MethodGet, repositoryPath(workspace, repoSlug), nil, &repo); err != nil {
+		return nil, fmt.Errorf("getting repository %s/%s: %w", workspace, repoSlug, err)
+	}
+
+	return &repo, nil
+}
+
+// CreateRepository creates a private git repository in the workspace
+func (c *Client) CreateRepository(ctx context.Context, workspace string, repoSlug string) (*Repository, error) {
+	body := map[string]any{
+		"scm":        "git",
+		"is_private": true,
+	}
+
+	var repo Repository
+	err := c.send(ctx, http.MethodPost, repositoryPath(workspace, repoSlug), body, &repo)
+	var httpErr *httpError
+	if errors.As(err, &httpErr) && httpErr.statusCode == http.StatusBadRequest {
+		// Bitbucket responds "Repository with this Slug and Owner already exists."
+		return nil, fmt.Errorf("creating repository %s/%s: %w: %w", workspace, repoSlug, ErrRepositoryNameInUse, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("creating repository %s/%s: %w", workspace, repoSlug, err)
+	}
+
+	return &repo, nil
+}
+
+// EnablePipelines enables Bitbucket Pipelines for the repository
+func (c *Client) EnablePipelines(ctx context.Context, workspace string, repoSlug string) error {
+	body := map[string]any{
+		"enabled": true,
+	}
+
+	path := repositoryPath(workspace, repoSlug) + "/pipelines_config"
+	if err := c.send(ctx, http.MethodPut, path, body, nil); err != nil {
+		return fmt.Errorf("enabling pipelines for repository %s/%s: %w", workspace, repoSlug, err)
+	}
+
+	return nil
+}
+
+// SetVariable creates or updates the repository variable of Bitbucket Pipelines. Secured variables are masked in the
+// logs of the pipelines and can't be read back.
+func (c *Client) SetVariable(
+	ctx context.Context,
+	workspace string,
+	repoSlug string,
+	key string,
+	value string,
+	secured bool,
+) error {
+	variablesPath := repositoryPath(workspace, repoSlug) + "/pipelines_config/variables"
+	existing, err := c.findVariable(ctx, variablesPath, key)
+	if err != nil {
+		return fmt.Errorf("setting variable %s: %w", key, err)
+	}
+
+	variable := Variable{
+		Key:     key,
+		Value:   value,
+		Secured: secured,
+	}
+
+	if existing != nil {
+		err = c.send(ctx, http.MethodPut, variablesPath+"/"+url.PathEscape(existing.Uuid), variable, nil)
+	} else {
+		err = c.send(ctx, http.MethodPost, variablesPath, variable, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("setting variable %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// findVariable returns the existing repository variable with the key, or nil when there is none
+func (c *Client) findVariable(ctx context.Context, variablesPath string, key string) (*Variable, error) {
+	next := c.baseUrl + variablesPath + "?pagelen=100"
+	for next != "" {
+		var page variablesPage
+		if err := c.sendUrl(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, fmt.Errorf("listing variables: %w", err)
+		}
+
+		for i := range page.Values {
+			if page.Values[i].Key == key {
+				return &page.Values[i], nil
+			}
+		}
+
+		next = page.Next
+	}
+
+	return nil, nil
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, body any, result any) error {
+	return c.sendUrl(ctx, method, c.baseUrl+path, body, result)
+}
+
+func (c *Client) sendUrl(ctx context.Context, method string, requestUrl string, body any, result any) error {
+	var content io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshalling request: %w", err)
+		}
+		content = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, content)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newHttpError(res)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed unmarshalling JSON from response: %w", err)
+	}
+
+	return nil
+}
+
+// httpError is the error of a Bitbucket API response with an unsuccessful status code
+type httpError struct {
+	statusCode int
+	message    string
+}
+
+func newHttpError(res *http.Response) *httpError {
+	httpErr := &httpError{
+		statusCode: res.StatusCode,
+	}
+
+	var errorBody errorResponse
+	if data, err := io.ReadAll(res.Body); err == nil && json.Unmarshal(data, &errorBody) == nil {
+		httpErr.message = errorBody.Error.Message
+	}
+
+	return httpErr
+}
+
+func (e *httpError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("unexpected status code %d", e.statusCode)
+	}
+
+	return fmt.Sprintf("unexpected status code %d: %s", e.statusCode, e.message)
+}
+
+func repositoryPath(workspace string, repoSlug string) string {
+	return fmt.Sprintf("/repositories/%s/%s", url.PathEscape(workspace), url.PathEscape(repoSlug))
+}
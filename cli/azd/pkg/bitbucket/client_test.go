@@ -0,0 +1,182 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Client_CreateRepository(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		var body map[string]any
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost &&
+				request.URL.String() == "https://api.bitbucket.org/2.0/repositories/workspace/repo"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.Equal(t, "Bearer TOKEN", request.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, Repository{
+				Slug:     "repo",
+				FullName: "workspace/repo",
+				Links: RepositoryLinks{
+					Clone: []CloneLink{
+						{Name: "https", Href: "https://bitbucket.org/workspace/repo.git"},
+						{Name: "ssh", Href: "git@bitbucket.org:workspace/repo.git"},
+					},
+				},
+			})
+		})
+
+		client := NewClient(mockContext.HttpClient, "", "TOKEN")
+		repo, err := client.CreateRepository(*mockContext.Context, "workspace", "repo")
+		require.NoError(t, err)
+		require.Equal(t, "https://bitbucket.org/workspace/repo.git", repo.CloneUrl("https"))
+		require.Equal(t, "git@bitbucket.org:workspace/repo.git", repo.CloneUrl("ssh"))
+		require.Equal(t, map[string]any{"scm": "git", "is_private": true}, body)
+	})
+
+	t.Run("NameInUse", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body := errorResponse{}
+			body.Error.Message = "Repository with this Slug and Owner already exists."
+			return mocks.CreateHttpResponseWithBody(request, http.StatusBadRequest, body)
+		})
+
+		client := NewClient(mockContext.HttpClient, "", "TOKEN")
+		repo, err := client.CreateRepository(*mockContext.Context, "workspace", "repo")
+		require.Nil(t, repo)
+		require.ErrorIs(t, err, ErrRepositoryNameInUse)
+		require.ErrorContains(t, err, "Repository with this Slug and Owner already exists.")
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusUnauthorized)
+		})
+
+		client := NewClient(mockContext.HttpClient, "", "TOKEN")
+		_, err := client.CreateRepository(*mockContext.Context, "workspace", "repo")
+		require.ErrorContains(t, err, "unexpected status code 401")
+		require.NotErrorIs(t, err, ErrRepositoryNameInUse)
+	})
+}
+
+func Test_Client_SetVariable(t *testing.T) {
+	const variablesUrl = "https://api.bitbucket.org/2.0/repositories/workspace/repo/pipelines_config/variables"
+
+	t.Run("Create", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockVariables(mockContext, variablesUrl)
+
+		var created Variable
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && request.URL.String() == variablesUrl
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&created))
+			return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, created)
+		})
+
+		client := NewClient(mockContext.HttpClient, "", "TOKEN")
+		err := client.SetVariable(*mockContext.Context, "workspace", "repo", "AZURE_CLIENT_SECRET", "SECRET", true)
+		require.NoError(t, err)
+		require.Equal(t, Variable{Key: "AZURE_CLIENT_SECRET", Value: "SECRET", Secured: true}, created)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockVariables(mockContext, variablesUrl,
+			Variable{Uuid: "{1}", Key: "AZURE_ENV_NAME", Value: "dev"},
+			Variable{Uuid: "{2}", Key: "AZURE_LOCATION", Value: "westus"},
+		)
+
+		var updated Variable
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && request.URL.String() == variablesUrl+"/%7B2%7D"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.NoError(t, json.NewDecoder(request.Body).Decode(&updated))
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, updated)
+		})
+
+		client := NewClient(mockContext.HttpClient, "", "TOKEN")
+		err := client.SetVariable(*mockContext.Context, "workspace", "repo", "AZURE_LOCATION", "eastus2", false)
+		require.NoError(t, err)
+		require.Equal(t, Variable{Key: "AZURE_LOCATION", Value: "eastus2"}, updated)
+	})
+
+	t.Run("BasicAuth", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return true
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			username, password, ok := request.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "user@contoso.com", username)
+			require.Equal(t, "API_TOKEN", password)
+
+			if request.Method == http.MethodGet {
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, variablesPage{})
+			}
+
+			_, _ = io.Copy(io.Discard, request.Body)
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		})
+
+		client := NewClient(mockContext.HttpClient, "user@contoso.com", "API_TOKEN")
+		err := client.SetVariable(*mockContext.Context, "workspace", "repo", "AZURE_ENV_NAME", "dev", false)
+		require.NoError(t, err)
+	})
+}
+
+func Test_Client_EnablePipelines(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var body map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut &&
+			request.URL.String() == "https://api.bitbucket.org/2.0/repositories/workspace/repo/pipelines_config"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, body)
+	})
+
+	client := NewClient(mockContext.HttpClient, "", "TOKEN")
+	err := client.EnablePipelines(*mockContext.Context, "workspace", "repo")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"enabled": true}, body)
+}
+
+// mockVariables responds to listing the repository variables with two pages, the second one holding the variables
+func mockVariables(mockContext *mocks.MockContext, variablesUrl string, variables ...Variable) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.String() == variablesUrl+"?pagelen=100"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, variablesPage{
+			Next: variablesUrl + "?pagelen=100&page=2",
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.String() == variablesUrl+"?pagelen=100&page=2"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, variablesPage{
+			Values: variables,
+		})
+	})
+}
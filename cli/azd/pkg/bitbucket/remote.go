@@ -0,0 +1,34 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bitbucket
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrRemoteHostIsNotBitbucket = errors.New("not a bitbucket host")
+
+var bitbucketRemoteGitUrlRegex = regexp.MustCompile(`^(?:ssh://)?git@bitbucket\.org[:/](.*?)(?:\.git)?/?$`)
+var bitbucketRemoteHttpsUrlRegex = regexp.MustCompile(`^https://(?:[^@/]+@)?(?:www\.)?bitbucket\.org/(.*?)(?:\.git)?/?$`)
+
+// GetSlugForRemote returns the workspace and the repository slug of a Bitbucket remote url, in ssh or https format.
+func GetSlugForRemote(remoteUrl string) (workspace string, repoSlug string, err error) {
+	for _, r := range []*regexp.Regexp{bitbucketRemoteGitUrlRegex, bitbucketRemoteHttpsUrlRegex} {
+		captures := r.FindStringSubmatch(remoteUrl)
+		if captures == nil {
+			continue
+		}
+
+		parts := strings.Split(captures[1], "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			break
+		}
+
+		return parts[0], parts[1], nil
+	}
+
+	return "", "", ErrRemoteHostIsNotBitbucket
+}
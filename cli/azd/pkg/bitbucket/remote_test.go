@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bitbucket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSlugForRemote(t *testing.T) {
+	cases := []struct {
+		remote    string
+		workspace string
+		repoSlug  string
+		isError   bool
+	}{
+		{remote: "git@bitbucket.org:foo/bar.git", workspace: "foo", repoSlug: "bar"},
+		{remote: "ssh://git@bitbucket.org/foo/bar.git", workspace: "foo", repoSlug: "bar"},
+		{remote: "https://bitbucket.org/foo/bar.git", workspace: "foo", repoSlug: "bar"},
+		{remote: "https://user@bitbucket.org/foo/bar.git", workspace: "foo", repoSlug: "bar"},
+		{remote: "https://www.bitbucket.org/foo/bar.git", workspace: "foo", repoSlug: "bar"},
+
+		{remote: "git@bitbucket.org:foo/bar", workspace: "foo", repoSlug: "bar"},
+		{remote: "https://bitbucket.org/foo/bar", workspace: "foo", repoSlug: "bar"},
+		{remote: "https://bitbucket.org/foo/bar/", workspace: "foo", repoSlug: "bar"},
+
+		{remote: "https://github.com/foo/bar.git", isError: true},
+		{remote: "git@github.com:foo/bar.git", isError: true},
+		{remote: "https://bitbucket.org/foo", isError: true},
+		{remote: "https://bitbucket.org/foo/bar/src/main", isError: true},
+		{remote: "not-a-remote", isError: true},
+		{remote: "", isError: true},
+	}
+
+	for _, tst := range cases {
+		workspace, repoSlug, err := GetSlugForRemote(tst.remote)
+
+		if tst.isError {
+			require.ErrorIs(t, err, ErrRemoteHostIsNotBitbucket, "expected error for %s", tst.remote)
+		} else {
+			require.NoError(t, err, "expected no error for %s", tst.remote)
+		}
+
+		assert.Equal(t, tst.workspace, workspace, "expected equal workspace for %s", tst.remote)
+		assert.Equal(t, tst.repoSlug, repoSlug, "expected equal repository for %s", tst.remote)
+	}
+}
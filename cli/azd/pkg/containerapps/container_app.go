@@ -3,7 +3,14 @@ package containerapps
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
 	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
@@ -31,19 +38,41 @@ type ContainerAppService interface {
 		appName string,
 		containerAppYaml []byte,
 	) error
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app, running the given image with the given
+	// environment variables in addition to the existing environment variables of the container. The given probes
+	// replace the existing probes of the same type of the container, and the given scale settings replace the
+	// existing settings that are set.
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
 		resourceGroupName string,
 		appName string,
 		imageName string,
+		env []EnvironmentVariable,
+		probes []Probe,
+		scale *Scale,
 	) error
 	ListSecrets(ctx context.Context,
 		subscriptionId string,
 		resourceGroupName string,
 		appName string,
 	) ([]*armappcontainers.ContainerAppSecret, error)
+	// Gets the status of the latest revision of the specified container app
+	GetLatestRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+	) (*ContainerAppRevision, error)
+	// Gets the most recent entries of the system logs of the specified container app, which describe events such as
+	// image pull failures, container crashes and failed health probes
+	GetSystemLogs(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		tailLines int,
+	) (string, error)
 }
 
 // NewContainerAppService creates a new ContainerAppService
@@ -69,6 +98,49 @@ type containerAppService struct {
 
 type ContainerAppIngressConfiguration struct {
 	HostNames []string
+	// The port of the container that the ingress sends traffic to, or 0 when the container app has no ingress
+	TargetPort int32
+}
+
+// ContainerAppRevision is the status of a revision of a container app
+type ContainerAppRevision struct {
+	Name string
+	// The provisioning state of the revision, such as Provisioning, Provisioned or Failed
+	ProvisioningState armappcontainers.RevisionProvisioningState
+	// The health state of the revision, one of Healthy, Unhealthy or None
+	HealthState armappcontainers.RevisionHealthState
+	// The error that caused provisioning of the revision to fail, if any
+	ProvisioningError string
+	// The number of replicas of the revision
+	Replicas int32
+	// The scale settings of the revision, nil when the revision uses the default settings
+	Scale *Scale
+}
+
+// EnvironmentVariable is an environment variable set on the container of a container app
+type EnvironmentVariable struct {
+	Name  string
+	Value string
+	// When true, the value is stored as a secret of the container app, which the environment variable references
+	Secret bool
+}
+
+// Probe is a health probe of the container of a container app
+type Probe struct {
+	// The type of the probe, one of Liveness, Readiness or Startup
+	Type armappcontainers.Type
+	// The path of the HTTP GET request of the probe. When empty, the probe opens a TCP connection to the port.
+	Path string
+	// The port of the container that is probed
+	Port int32
+	// The number of seconds after the container has started before the probe is initiated. When 0, the default of
+	// Azure Container Apps is used.
+	InitialDelaySeconds int32
+	// The number of seconds between probes. When 0, the default of Azure Container Apps is used.
+	PeriodSeconds int32
+	// The number of consecutive failures for the probe to be considered failed. When 0, the default of Azure
+	// Container Apps is used.
+	FailureThreshold int32
 }
 
 // Gets the ingress configuration for the specified container app
@@ -84,17 +156,26 @@ func (cas *containerAppService) GetIngressConfiguration(
 	}
 
 	var hostNames []string
+	var targetPort int32
 	if containerApp.Properties != nil &&
 		containerApp.Properties.Configuration != nil &&
-		containerApp.Properties.Configuration.Ingress != nil &&
-		containerApp.Properties.Configuration.Ingress.Fqdn != nil {
-		hostNames = []string{*containerApp.Properties.Configuration.Ingress.Fqdn}
-	} else {
+		containerApp.Properties.Configuration.Ingress != nil {
+		ingress := containerApp.Properties.Configuration.Ingress
+		if ingress.Fqdn != nil {
+			hostNames = []string{*ingress.Fqdn}
+		}
+		if ingress.TargetPort != nil {
+			targetPort = *ingress.TargetPort
+		}
+	}
+
+	if hostNames == nil {
 		hostNames = []string{}
 	}
 
 	return &ContainerAppIngressConfiguration{
-		HostNames: hostNames,
+		HostNames:  hostNames,
+		TargetPort: targetPort,
 	}, nil
 }
 
@@ -138,13 +219,19 @@ func (cas *containerAppService) DeployYaml(
 	return nil
 }
 
-// Adds and activates a new revision to the specified container app
+// Adds and activates a new revision to the specified container app, running the given image with the given
+// environment variables in addition to the existing environment variables of the container. The given probes
+// replace the existing probes of the same type of the container, and the given scale settings replace the
+// existing settings that are set.
 func (cas *containerAppService) AddRevision(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	appName string,
 	imageName string,
+	env []EnvironmentVariable,
+	probes []Probe,
+	scale *Scale,
 ) error {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
 	if err != nil {
@@ -175,6 +262,10 @@ func (cas *containerAppService) AddRevision(
 		return fmt.Errorf("syncing secrets: %w", err)
 	}
 
+	applyEnvironmentVariables(containerApp, env)
+	applyProbes(containerApp, probes)
+	applyScale(containerApp, scale)
+
 	// Update the container app
 	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
@@ -212,6 +303,116 @@ func (cas *containerAppService) ListSecrets(
 	return secretsResponse.Value, nil
 }
 
+// Gets the status of the latest revision of the specified container app
+func (cas *containerAppService) GetLatestRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (*ContainerAppRevision, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if containerApp.Properties == nil || containerApp.Properties.LatestRevisionName == nil {
+		return nil, fmt.Errorf("container app '%s' has no revisions", appName)
+	}
+
+	revisionName := *containerApp.Properties.LatestRevisionName
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionResponse, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, revisionName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting revision '%s': %w", revisionName, err)
+	}
+
+	revision := &ContainerAppRevision{
+		Name: revisionName,
+	}
+
+	if properties := revisionResponse.Properties; properties != nil {
+		revision.ProvisioningState = convert.ToValueWithDefault(properties.ProvisioningState, "")
+		revision.HealthState = convert.ToValueWithDefault(properties.HealthState, "")
+		revision.ProvisioningError = convert.ToValueWithDefault(properties.ProvisioningError, "")
+		revision.Replicas = convert.ToValueWithDefault(properties.Replicas, 0)
+		if properties.Template != nil {
+			revision.Scale = scaleFromArm(properties.Template.Scale)
+		}
+	}
+
+	return revision, nil
+}
+
+// Gets the most recent entries of the system logs of the specified container app from its event stream
+func (cas *containerAppService) GetSystemLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	tailLines int,
+) (string, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return "", err
+	}
+
+	if containerApp.Properties == nil || containerApp.Properties.EventStreamEndpoint == nil {
+		return "", fmt.Errorf("container app '%s' has no event stream endpoint", appName)
+	}
+
+	appClient, err := cas.createContainerAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	tokenResponse, err := appClient.GetAuthToken(ctx, resourceGroupName, appName, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting event stream token: %w", err)
+	}
+
+	if tokenResponse.Properties == nil || tokenResponse.Properties.Token == nil {
+		return "", errors.New("getting event stream token: no token returned")
+	}
+
+	endpoint, err := url.Parse(*containerApp.Properties.EventStreamEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing event stream endpoint: %w", err)
+	}
+
+	query := endpoint.Query()
+	query.Set("tailLines", strconv.Itoa(tailLines))
+	query.Set("follow", "false")
+	endpoint.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating event stream request: %w", err)
+	}
+
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", *tokenResponse.Properties.Token))
+
+	response, err := cas.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("reading event stream: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("reading event stream: unexpected status code %d", response.StatusCode)
+	}
+
+	logs, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading event stream: %w", err)
+	}
+
+	return strings.TrimSpace(string(logs)), nil
+}
+
 func (cas *containerAppService) syncSecrets(
 	ctx context.Context,
 	subscriptionId string,
@@ -250,6 +451,102 @@ func (cas *containerAppService) syncSecrets(
 	return containerApp, nil
 }
 
+// applyEnvironmentVariables sets the environment variables on the first container of the container app, replacing
+// existing variables with the same name. Secret values are stored as secrets of the container app.
+func applyEnvironmentVariables(containerApp *armappcontainers.ContainerApp, env []EnvironmentVariable) {
+	if len(env) == 0 {
+		return
+	}
+
+	container := containerApp.Properties.Template.Containers[0]
+	for _, envVar := range env {
+		containerEnvVar := &armappcontainers.EnvironmentVar{
+			Name: convert.RefOf(envVar.Name),
+		}
+
+		if envVar.Secret {
+			secretName := envSecretName(envVar.Name)
+			containerEnvVar.SecretRef = convert.RefOf(secretName)
+			containerApp.Properties.Configuration.Secrets = slices.DeleteFunc(
+				containerApp.Properties.Configuration.Secrets,
+				func(secret *armappcontainers.Secret) bool {
+					return secret.Name != nil && *secret.Name == secretName
+				},
+			)
+			containerApp.Properties.Configuration.Secrets = append(
+				containerApp.Properties.Configuration.Secrets,
+				&armappcontainers.Secret{
+					Name:  convert.RefOf(secretName),
+					Value: convert.RefOf(envVar.Value),
+				},
+			)
+		} else {
+			containerEnvVar.Value = convert.RefOf(envVar.Value)
+		}
+
+		container.Env = slices.DeleteFunc(container.Env, func(existing *armappcontainers.EnvironmentVar) bool {
+			return existing.Name != nil && *existing.Name == envVar.Name
+		})
+		container.Env = append(container.Env, containerEnvVar)
+	}
+}
+
+// applyProbes sets the probes on the first container of the container app, replacing the existing probes of the same
+// type. The existing probes of other types are kept.
+func applyProbes(containerApp *armappcontainers.ContainerApp, probes []Probe) {
+	if len(probes) == 0 {
+		return
+	}
+
+	container := containerApp.Properties.Template.Containers[0]
+	for _, probe := range probes {
+		containerProbe := &armappcontainers.ContainerAppProbe{
+			Type: convert.RefOf(probe.Type),
+		}
+
+		if probe.Path != "" {
+			containerProbe.HTTPGet = &armappcontainers.ContainerAppProbeHTTPGet{
+				Path: convert.RefOf(probe.Path),
+				Port: convert.RefOf(probe.Port),
+			}
+		} else {
+			containerProbe.TCPSocket = &armappcontainers.ContainerAppProbeTCPSocket{
+				Port: convert.RefOf(probe.Port),
+			}
+		}
+
+		if probe.InitialDelaySeconds > 0 {
+			containerProbe.InitialDelaySeconds = convert.RefOf(probe.InitialDelaySeconds)
+		}
+		if probe.PeriodSeconds > 0 {
+			containerProbe.PeriodSeconds = convert.RefOf(probe.PeriodSeconds)
+		}
+		if probe.FailureThreshold > 0 {
+			containerProbe.FailureThreshold = convert.RefOf(probe.FailureThreshold)
+		}
+
+		container.Probes = slices.DeleteFunc(container.Probes, func(existing *armappcontainers.ContainerAppProbe) bool {
+			return existing.Type != nil && *existing.Type == probe.Type
+		})
+		container.Probes = append(container.Probes, containerProbe)
+	}
+}
+
+// envSecretName returns the name of the container app secret that stores the value of an environment variable.
+// Secret names may only contain lower case alphanumeric characters and '-'.
+func envSecretName(envVarName string) string {
+	var name strings.Builder
+	for _, r := range strings.ToLower(envVarName) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			name.WriteRune(r)
+		} else {
+			name.WriteRune('-')
+		}
+	}
+
+	return "azd-env-" + strings.Trim(name.String(), "-")
+}
+
 func (cas *containerAppService) setTrafficWeights(
 	ctx context.Context,
 	subscriptionId string,
@@ -28,7 +28,8 @@ func Test_ContainerApp_GetIngressConfiguration(t *testing.T) {
 			Configuration: &armappcontainers.Configuration{
 				ActiveRevisionsMode: convert.RefOf(armappcontainers.ActiveRevisionsModeSingle),
 				Ingress: &armappcontainers.Ingress{
-					Fqdn: &hostName,
+					Fqdn:       &hostName,
+					TargetPort: convert.RefOf[int32](8080),
 				},
 			},
 		},
@@ -51,6 +52,7 @@ func Test_ContainerApp_GetIngressConfiguration(t *testing.T) {
 
 	require.Equal(t, expectedPath, mockRequest.URL.Path)
 	require.Equal(t, hostName, ingressConfig.HostNames[0])
+	require.Equal(t, int32(8080), ingressConfig.TargetPort)
 }
 
 func Test_ContainerApp_AddRevision(t *testing.T) {
@@ -128,7 +130,7 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	)
 
 	cas := NewContainerAppService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, clock.NewMock())
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName)
+	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil, nil, nil)
 	require.NoError(t, err)
 
 	// Verify lastest revision is read
@@ -150,3 +152,149 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	require.Equal(t, updatedImageName, *updatedContainerApp.Properties.Template.Containers[0].Image)
 	require.Equal(t, "azd-0", *updatedContainerApp.Properties.Template.RevisionSuffix)
 }
+
+func Test_ContainerApp_ApplyEnvironmentVariables(t *testing.T) {
+	containerApp := &armappcontainers.ContainerApp{
+		Properties: &armappcontainers.ContainerAppProperties{
+			Configuration: &armappcontainers.Configuration{
+				Secrets: []*armappcontainers.Secret{
+					{
+						Name:  convert.RefOf("azd-env-api-key"),
+						Value: convert.RefOf("OLD_KEY"),
+					},
+				},
+			},
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Env: []*armappcontainers.EnvironmentVar{
+							{
+								Name:  convert.RefOf("EXISTING"),
+								Value: convert.RefOf("existing"),
+							},
+							{
+								Name:  convert.RefOf("LOG_LEVEL"),
+								Value: convert.RefOf("info"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	applyEnvironmentVariables(containerApp, []EnvironmentVariable{
+		{Name: "API_KEY", Value: "NEW_KEY", Secret: true},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	})
+
+	env := map[string]*armappcontainers.EnvironmentVar{}
+	for _, envVar := range containerApp.Properties.Template.Containers[0].Env {
+		env[*envVar.Name] = envVar
+	}
+
+	require.Len(t, env, 3)
+	require.Equal(t, "existing", *env["EXISTING"].Value)
+	require.Equal(t, "debug", *env["LOG_LEVEL"].Value)
+	require.Nil(t, env["API_KEY"].Value)
+	require.Equal(t, "azd-env-api-key", *env["API_KEY"].SecretRef)
+
+	secrets := containerApp.Properties.Configuration.Secrets
+	require.Len(t, secrets, 1)
+	require.Equal(t, "azd-env-api-key", *secrets[0].Name)
+	require.Equal(t, "NEW_KEY", *secrets[0].Value)
+}
+
+func Test_ContainerApp_ApplyProbes(t *testing.T) {
+	containerApp := &armappcontainers.ContainerApp{
+		Properties: &armappcontainers.ContainerAppProperties{
+			Template: &armappcontainers.Template{
+				Containers: []*armappcontainers.Container{
+					{
+						Probes: []*armappcontainers.ContainerAppProbe{
+							{
+								Type:      convert.RefOf(armappcontainers.TypeLiveness),
+								TCPSocket: &armappcontainers.ContainerAppProbeTCPSocket{Port: convert.RefOf[int32](80)},
+							},
+							{
+								Type:      convert.RefOf(armappcontainers.TypeStartup),
+								TCPSocket: &armappcontainers.ContainerAppProbeTCPSocket{Port: convert.RefOf[int32](80)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	applyProbes(containerApp, []Probe{
+		{
+			Type:                armappcontainers.TypeLiveness,
+			Path:                "/healthz",
+			Port:                8080,
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       15,
+			FailureThreshold:    4,
+		},
+		{Type: armappcontainers.TypeReadiness, Port: 8080},
+	})
+
+	probes := map[armappcontainers.Type]*armappcontainers.ContainerAppProbe{}
+	for _, probe := range containerApp.Properties.Template.Containers[0].Probes {
+		probes[*probe.Type] = probe
+	}
+
+	require.Len(t, probes, 3)
+
+	liveness := probes[armappcontainers.TypeLiveness]
+	require.Nil(t, liveness.TCPSocket)
+	require.Equal(t, "/healthz", *liveness.HTTPGet.Path)
+	require.Equal(t, int32(8080), *liveness.HTTPGet.Port)
+	require.Equal(t, int32(5), *liveness.InitialDelaySeconds)
+	require.Equal(t, int32(15), *liveness.PeriodSeconds)
+	require.Equal(t, int32(4), *liveness.FailureThreshold)
+
+	readiness := probes[armappcontainers.TypeReadiness]
+	require.Nil(t, readiness.HTTPGet)
+	require.Equal(t, int32(8080), *readiness.TCPSocket.Port)
+	require.Nil(t, readiness.InitialDelaySeconds)
+
+	// probes of other types are kept
+	require.Equal(t, int32(80), *probes[armappcontainers.TypeStartup].TCPSocket.Port)
+}
+
+func Test_ContainerApp_GetLatestRevision(t *testing.T) {
+	subscriptionId := "SUBSCRIPTION_ID"
+	resourceGroup := "RESOURCE_GROUP"
+	appName := "APP_NAME"
+	revisionName := "APP_NAME--azd-0"
+
+	containerApp := &armappcontainers.ContainerApp{
+		Name: &appName,
+		Properties: &armappcontainers.ContainerAppProperties{
+			LatestRevisionName: &revisionName,
+		},
+	}
+
+	revision := &armappcontainers.Revision{
+		Properties: &armappcontainers.RevisionProperties{
+			ProvisioningState: convert.RefOf(armappcontainers.RevisionProvisioningStateProvisioned),
+			HealthState:       convert.RefOf(armappcontainers.RevisionHealthStateUnhealthy),
+			Replicas:          convert.RefOf[int32](2),
+		},
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockazsdk.MockContainerAppGet(mockContext, subscriptionId, resourceGroup, appName, containerApp)
+	mockazsdk.MockContainerAppRevisionGet(mockContext, subscriptionId, resourceGroup, appName, revisionName, revision)
+
+	cas := NewContainerAppService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, clock.NewMock())
+	latestRevision, err := cas.GetLatestRevision(*mockContext.Context, subscriptionId, resourceGroup, appName)
+	require.NoError(t, err)
+	require.Equal(t, &ContainerAppRevision{
+		Name:              revisionName,
+		ProvisioningState: armappcontainers.RevisionProvisioningStateProvisioned,
+		HealthState:       armappcontainers.RevisionHealthStateUnhealthy,
+		Replicas:          2,
+	}, latestRevision)
+}
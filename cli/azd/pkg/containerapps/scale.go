@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerapps
+
+import (
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// The types of a ScaleRule
+const (
+	// Scales on the number of concurrent HTTP requests per replica
+	ScaleRuleHttp = "http"
+	// Scales on the average CPU utilization of the replicas
+	ScaleRuleCpu = "cpu"
+	// Scales on the average memory utilization of the replicas
+	ScaleRuleMemory = "memory"
+	// Scales on the number of messages in an Azure Storage queue
+	ScaleRuleAzureQueue = "azure-queue"
+)
+
+// Scale is the scale settings of a container app
+type Scale struct {
+	// The minimum number of replicas. When nil, the minimum of the container app is kept unchanged.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// The maximum number of replicas. When nil, the maximum of the container app is kept unchanged.
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// The scale rules. When nil, the rules of the container app are kept unchanged.
+	Rules []ScaleRule `json:"rules"`
+}
+
+// ScaleRule is a rule of the scale settings of a container app
+type ScaleRule struct {
+	Name string `json:"name"`
+	// One of ScaleRuleHttp, ScaleRuleCpu, ScaleRuleMemory or ScaleRuleAzureQueue. Rules of other types read from a
+	// container app have the type of their custom KEDA scaler.
+	Type string `json:"type"`
+	// The number of concurrent requests per replica of an http rule
+	ConcurrentRequests int32 `json:"concurrentRequests,omitempty"`
+	// The average utilization percentage of a cpu or memory rule
+	Utilization int32 `json:"utilization,omitempty"`
+	// The name of the queue of an azure-queue rule
+	QueueName string `json:"queueName,omitempty"`
+	// The number of messages per replica of an azure-queue rule
+	QueueLength int32 `json:"queueLength,omitempty"`
+	// The name of the secret of the container app with the connection string of the storage account of an azure-queue
+	// rule
+	SecretRef string `json:"secretRef,omitempty"`
+}
+
+// applyScale sets the scale settings on the template of the container app. The settings that aren't set in scale are
+// kept unchanged.
+func applyScale(containerApp *armappcontainers.ContainerApp, scale *Scale) {
+	if scale == nil {
+		return
+	}
+
+	template := containerApp.Properties.Template
+	if template.Scale == nil {
+		template.Scale = &armappcontainers.Scale{}
+	}
+
+	if scale.MinReplicas != nil {
+		template.Scale.MinReplicas = convert.RefOf(*scale.MinReplicas)
+	}
+
+	if scale.MaxReplicas != nil {
+		template.Scale.MaxReplicas = convert.RefOf(*scale.MaxReplicas)
+	}
+
+	if scale.Rules != nil {
+		rules := make([]*armappcontainers.ScaleRule, 0, len(scale.Rules))
+		for _, rule := range scale.Rules {
+			rules = append(rules, scaleRuleToArm(rule))
+		}
+
+		template.Scale.Rules = rules
+	}
+}
+
+func scaleRuleToArm(rule ScaleRule) *armappcontainers.ScaleRule {
+	armRule := &armappcontainers.ScaleRule{
+		Name: convert.RefOf(rule.Name),
+	}
+
+	switch rule.Type {
+	case ScaleRuleHttp:
+		armRule.HTTP = &armappcontainers.HTTPScaleRule{
+			Metadata: map[string]*string{
+				"concurrentRequests": convert.RefOf(strconv.Itoa(int(rule.ConcurrentRequests))),
+			},
+		}
+	case ScaleRuleAzureQueue:
+		armRule.AzureQueue = &armappcontainers.QueueScaleRule{
+			QueueName:   convert.RefOf(rule.QueueName),
+			QueueLength: convert.RefOf(rule.QueueLength),
+			Auth: []*armappcontainers.ScaleRuleAuth{
+				{
+					SecretRef:        convert.RefOf(rule.SecretRef),
+					TriggerParameter: convert.RefOf("connection"),
+				},
+			},
+		}
+	default:
+		// cpu and memory are custom KEDA scalers
+		armRule.Custom = &armappcontainers.CustomScaleRule{
+			Type: convert.RefOf(rule.Type),
+			Metadata: map[string]*string{
+				"type":  convert.RefOf("Utilization"),
+				"value": convert.RefOf(strconv.Itoa(int(rule.Utilization))),
+			},
+		}
+	}
+
+	return armRule
+}
+
+// scaleFromArm returns the scale settings of a container app template, or nil when the template has none
+func scaleFromArm(armScale *armappcontainers.Scale) *Scale {
+	if armScale == nil {
+		return nil
+	}
+
+	scale := &Scale{
+		MinReplicas: armScale.MinReplicas,
+		MaxReplicas: armScale.MaxReplicas,
+		Rules:       []ScaleRule{},
+	}
+
+	for _, armRule := range armScale.Rules {
+		if armRule == nil {
+			continue
+		}
+
+		rule := ScaleRule{
+			Name: convert.ToValueWithDefault(armRule.Name, ""),
+		}
+
+		switch {
+		case armRule.HTTP != nil:
+			rule.Type = ScaleRuleHttp
+			rule.ConcurrentRequests = metadataInt(armRule.HTTP.Metadata, "concurrentRequests")
+		case armRule.AzureQueue != nil:
+			rule.Type = ScaleRuleAzureQueue
+			rule.QueueName = convert.ToValueWithDefault(armRule.AzureQueue.QueueName, "")
+			rule.QueueLength = convert.ToValueWithDefault(armRule.AzureQueue.QueueLength, 0)
+			for _, auth := range armRule.AzureQueue.Auth {
+				if auth != nil && convert.ToValueWithDefault(auth.TriggerParameter, "") == "connection" {
+					rule.SecretRef = convert.ToValueWithDefault(auth.SecretRef, "")
+				}
+			}
+		case armRule.Custom != nil:
+			rule.Type = convert.ToValueWithDefault(armRule.Custom.Type, "")
+			if rule.Type == ScaleRuleCpu || rule.Type == ScaleRuleMemory {
+				rule.Utilization = metadataInt(armRule.Custom.Metadata, "value")
+			}
+		case armRule.TCP != nil:
+			rule.Type = "tcp"
+		}
+
+		scale.Rules = append(scale.Rules, rule)
+	}
+
+	return scale
+}
+
+// metadataInt returns the integer value of the key of the metadata of a scale rule, or 0 when it isn't an integer
+func metadataInt(metadata map[string]*string, key string) int32 {
+	value, err := strconv.ParseInt(convert.ToValueWithDefault(metadata[key], ""), 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int32(value)
+}
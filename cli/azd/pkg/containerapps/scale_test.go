@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package containerapps
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerApp_ApplyScale(t *testing.T) {
+	existingRules := []*armappcontainers.ScaleRule{
+		{
+			Name: convert.RefOf("servicebus"),
+			Custom: &armappcontainers.CustomScaleRule{
+				Type: convert.RefOf("azure-servicebus"),
+			},
+		},
+	}
+
+	newContainerApp := func() *armappcontainers.ContainerApp {
+		return &armappcontainers.ContainerApp{
+			Properties: &armappcontainers.ContainerAppProperties{
+				Template: &armappcontainers.Template{
+					Scale: &armappcontainers.Scale{
+						MinReplicas: convert.RefOf[int32](1),
+						MaxReplicas: convert.RefOf[int32](10),
+						Rules:       existingRules,
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("KeepsUnset", func(t *testing.T) {
+		containerApp := newContainerApp()
+		applyScale(containerApp, &Scale{MaxReplicas: convert.RefOf[int32](5)})
+
+		scale := containerApp.Properties.Template.Scale
+		require.Equal(t, int32(1), *scale.MinReplicas)
+		require.Equal(t, int32(5), *scale.MaxReplicas)
+		require.Equal(t, existingRules, scale.Rules)
+	})
+
+	t.Run("ReplacesRules", func(t *testing.T) {
+		containerApp := newContainerApp()
+		rules := []ScaleRule{
+			{Name: "http", Type: ScaleRuleHttp, ConcurrentRequests: 50},
+			{Name: "cpu", Type: ScaleRuleCpu, Utilization: 70},
+			{Name: "queue", Type: ScaleRuleAzureQueue, QueueName: "orders", QueueLength: 20, SecretRef: "queue-connection"},
+		}
+		applyScale(containerApp, &Scale{MinReplicas: convert.RefOf[int32](0), Rules: rules})
+
+		scale := containerApp.Properties.Template.Scale
+		require.Equal(t, int32(0), *scale.MinReplicas)
+		require.Equal(t, int32(10), *scale.MaxReplicas)
+		require.Len(t, scale.Rules, 3)
+
+		require.Equal(t, "50", *scale.Rules[0].HTTP.Metadata["concurrentRequests"])
+		require.Equal(t, "cpu", *scale.Rules[1].Custom.Type)
+		require.Equal(t, "Utilization", *scale.Rules[1].Custom.Metadata["type"])
+		require.Equal(t, "70", *scale.Rules[1].Custom.Metadata["value"])
+		require.Equal(t, "orders", *scale.Rules[2].AzureQueue.QueueName)
+		require.Equal(t, "queue-connection", *scale.Rules[2].AzureQueue.Auth[0].SecretRef)
+
+		// the rules are read back as they were set
+		require.Equal(t, rules, scaleFromArm(scale).Rules)
+	})
+
+	t.Run("NoTemplateScale", func(t *testing.T) {
+		containerApp := &armappcontainers.ContainerApp{
+			Properties: &armappcontainers.ContainerAppProperties{
+				Template: &armappcontainers.Template{},
+			},
+		}
+		applyScale(containerApp, &Scale{MaxReplicas: convert.RefOf[int32](3)})
+
+		require.Nil(t, containerApp.Properties.Template.Scale.MinReplicas)
+		require.Equal(t, int32(3), *containerApp.Properties.Template.Scale.MaxReplicas)
+	})
+}
+
+func Test_scaleFromArm(t *testing.T) {
+	require.Nil(t, scaleFromArm(nil))
+
+	scale := scaleFromArm(&armappcontainers.Scale{
+		MaxReplicas: convert.RefOf[int32](10),
+		Rules: []*armappcontainers.ScaleRule{
+			{
+				Name:   convert.RefOf("servicebus"),
+				Custom: &armappcontainers.CustomScaleRule{Type: convert.RefOf("azure-servicebus")},
+			},
+		},
+	})
+
+	require.Nil(t, scale.MinReplicas)
+	require.Equal(t, int32(10), *scale.MaxReplicas)
+	require.Equal(t, []ScaleRule{{Name: "servicebus", Type: "azure-servicebus"}}, scale.Rules)
+}
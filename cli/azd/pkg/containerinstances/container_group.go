@@ -0,0 +1,376 @@
+package containerinstances
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// The API version used for Microsoft.ContainerInstance/containerGroups resources
+const containerGroupApiVersion = "2023-05-01"
+
+// The restart policies supported by a container group
+const (
+	RestartPolicyAlways    = "Always"
+	RestartPolicyOnFailure = "OnFailure"
+	RestartPolicyNever     = "Never"
+)
+
+// ContainerInstanceService exposes operations for managing Azure Container Instances
+type ContainerInstanceService interface {
+	// Gets the status and network configuration of the specified container group
+	GetContainerGroup(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		containerGroupName string,
+	) (*ContainerGroup, error)
+	// Updates the first container of the specified container group with the given image, environment variables and
+	// restart policy, and waits for the container group to be redeployed
+	UpdateContainerGroup(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		containerGroupName string,
+		update ContainerGroupUpdate,
+	) error
+}
+
+// ContainerGroup is the status and network configuration of a container group
+type ContainerGroup struct {
+	// The provisioning state of the container group, ex) Succeeded, Failed
+	ProvisioningState string
+	// The state of the container group instance, ex) Pending, Running, Succeeded, Failed, Stopped
+	State string
+	// The fully qualified domain name of the container group, empty when no DNS name label is configured
+	Fqdn string
+	// The public IP address of the container group, empty when the container group has no public IP address
+	IpAddress string
+	// The ports exposed on the IP address of the container group
+	Ports []int
+	// The login servers of the registries the container group pulls images from with a username and password. ARM
+	// doesn't return the passwords, so they must be given again when the container group is updated.
+	PasswordRegistries []string
+}
+
+// ContainerGroupUpdate describes the changes applied to a container group during deployment
+type ContainerGroupUpdate struct {
+	// The container image to run
+	Image string
+	// Environment variables set on the container, in addition to the existing environment variables
+	Env map[string]string
+	// Environment variables set on the container as secure values, which are not returned by the container group API
+	SecureEnv map[string]string
+	// The restart policy of the container group. When empty, the existing restart policy is kept.
+	RestartPolicy string
+	// The credentials of the registries the container group pulls images from with a username and password, which
+	// must include the registries of ContainerGroup.PasswordRegistries
+	RegistryCredentials []RegistryCredential
+}
+
+// RegistryCredential is the username and password a container group pulls images from a registry with
+type RegistryCredential struct {
+	Server   string
+	Username string
+	Password string
+}
+
+// NewContainerInstanceService creates a new ContainerInstanceService
+func NewContainerInstanceService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) ContainerInstanceService {
+	return &containerInstanceService{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+		userAgent:          azdinternal.UserAgent(),
+	}
+}
+
+type containerInstanceService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+	userAgent          string
+}
+
+// containerGroupProperties models the subset of the container group properties interpreted by azd. Other properties
+// are preserved as-is when the container group is updated.
+type containerGroupProperties struct {
+	ProvisioningState string `json:"provisioningState"`
+	InstanceView      struct {
+		State string `json:"state"`
+	} `json:"instanceView"`
+	IpAddress *struct {
+		Ip    string `json:"ip"`
+		Fqdn  string `json:"fqdn"`
+		Ports []struct {
+			Port int `json:"port"`
+		} `json:"ports"`
+	} `json:"ipAddress"`
+	ImageRegistryCredentials []struct {
+		Server   string `json:"server"`
+		Identity string `json:"identity"`
+	} `json:"imageRegistryCredentials"`
+}
+
+// Gets the status and network configuration of the specified container group
+func (cis *containerInstanceService) GetContainerGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+) (*ContainerGroup, error) {
+	resource, err := cis.getContainerGroup(ctx, subscriptionId, resourceGroupName, containerGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	propertiesJson, err := json.Marshal(resource.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("encoding container group properties: %w", err)
+	}
+
+	var properties containerGroupProperties
+	if err := json.Unmarshal(propertiesJson, &properties); err != nil {
+		return nil, fmt.Errorf("decoding container group properties: %w", err)
+	}
+
+	containerGroup := &ContainerGroup{
+		ProvisioningState: properties.ProvisioningState,
+		State:             properties.InstanceView.State,
+		Ports:             []int{},
+	}
+
+	if properties.IpAddress != nil {
+		containerGroup.Fqdn = properties.IpAddress.Fqdn
+		containerGroup.IpAddress = properties.IpAddress.Ip
+		for _, port := range properties.IpAddress.Ports {
+			containerGroup.Ports = append(containerGroup.Ports, port.Port)
+		}
+	}
+
+	for _, credential := range properties.ImageRegistryCredentials {
+		if credential.Identity == "" {
+			containerGroup.PasswordRegistries = append(containerGroup.PasswordRegistries, credential.Server)
+		}
+	}
+
+	return containerGroup, nil
+}
+
+// Updates the first container of the specified container group with the given image, environment variables and
+// restart policy, and waits for the container group to be redeployed
+func (cis *containerInstanceService) UpdateContainerGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+	update ContainerGroupUpdate,
+) error {
+	resource, err := cis.getContainerGroup(ctx, subscriptionId, resourceGroupName, containerGroupName)
+	if err != nil {
+		return err
+	}
+
+	properties, ok := resource.Properties.(map[string]any)
+	if !ok {
+		return fmt.Errorf("container group '%s' has no properties", containerGroupName)
+	}
+
+	if err := applyUpdate(properties, update); err != nil {
+		return fmt.Errorf("updating container group '%s': %w", containerGroupName, err)
+	}
+
+	client, err := cis.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	containerGroup := armresources.GenericResource{
+		Location:   resource.Location,
+		Tags:       resource.Tags,
+		Identity:   resource.Identity,
+		Properties: properties,
+	}
+
+	poller, err := client.BeginCreateOrUpdateByID(
+		ctx,
+		azure.ContainerGroupRID(subscriptionId, resourceGroupName, containerGroupName),
+		containerGroupApiVersion,
+		containerGroup,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("updating container group: %w", err)
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("polling for container group update completion: %w", err)
+	}
+
+	return nil
+}
+
+// applyUpdate applies the update to the properties of a container group, as returned by ARM. ARM doesn't return secure
+// values, so the update fails instead of removing them when they aren't given again by the update.
+func applyUpdate(properties map[string]any, update ContainerGroupUpdate) error {
+	// read-only properties are not accepted when the container group is updated
+	delete(properties, "provisioningState")
+	delete(properties, "instanceView")
+
+	if update.RestartPolicy != "" {
+		properties["restartPolicy"] = update.RestartPolicy
+	}
+
+	containers, _ := properties["containers"].([]any)
+	if len(containers) == 0 {
+		return fmt.Errorf("container group has no containers")
+	}
+
+	container, _ := containers[0].(map[string]any)
+	containerProperties, _ := container["properties"].(map[string]any)
+	if containerProperties == nil {
+		return fmt.Errorf("container group has a container without properties")
+	}
+
+	delete(containerProperties, "instanceView")
+	containerProperties["image"] = update.Image
+
+	envVars, _ := containerProperties["environmentVariables"].([]any)
+	envVars = setEnvironmentVariables(envVars, update.Env, "value", "secureValue")
+	envVars = setEnvironmentVariables(envVars, update.SecureEnv, "secureValue", "value")
+
+	if len(envVars) > 0 {
+		containerProperties["environmentVariables"] = envVars
+	}
+
+	hidden := []string{}
+	for _, envVar := range envVars {
+		if envVar, ok := envVar.(map[string]any); ok {
+			_, hasValue := envVar["value"]
+			_, hasSecureValue := envVar["secureValue"]
+			if !hasValue && !hasSecureValue {
+				hidden = append(hidden, fmt.Sprint(envVar["name"]))
+			}
+		}
+	}
+
+	if len(hidden) > 0 {
+		return fmt.Errorf(
+			"the secure values of the environment variables %s aren't returned by Azure, and would be removed. "+
+				"Set them in the 'env' of the service in azure.yaml so they're kept", strings.Join(hidden, ", "))
+	}
+
+	return setRegistryCredentials(properties, update.RegistryCredentials)
+}
+
+// setRegistryCredentials sets the passwords of the image registry credentials of a container group that use a username
+// and password, which ARM doesn't return. Credentials that use a managed identity are kept as-is.
+func setRegistryCredentials(properties map[string]any, credentials []RegistryCredential) error {
+	registryCredentials, _ := properties["imageRegistryCredentials"].([]any)
+	for _, registryCredential := range registryCredentials {
+		registryCredential, ok := registryCredential.(map[string]any)
+		if !ok || registryCredential["identity"] != nil {
+			continue
+		}
+
+		server := fmt.Sprint(registryCredential["server"])
+		i := slices.IndexFunc(credentials, func(credential RegistryCredential) bool {
+			return strings.EqualFold(credential.Server, server)
+		})
+		if i == -1 {
+			return fmt.Errorf("the password of registry '%s' isn't returned by Azure, and would be removed", server)
+		}
+
+		registryCredential["username"] = credentials[i].Username
+		registryCredential["password"] = credentials[i].Password
+	}
+
+	return nil
+}
+
+// setEnvironmentVariables sets the values of env on the environment variables of a container, using the valueKey
+// property for the value and removing the otherKey property from existing environment variables.
+func setEnvironmentVariables(envVars []any, env map[string]string, valueKey string, otherKey string) []any {
+	for _, name := range sortedKeys(env) {
+		replaced := false
+		for _, envVar := range envVars {
+			if envVar, ok := envVar.(map[string]any); ok && envVar["name"] == name {
+				delete(envVar, otherKey)
+				envVar[valueKey] = env[name]
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			envVars = append(envVars, map[string]any{
+				"name":   name,
+				valueKey: env[name],
+			})
+		}
+	}
+
+	return envVars
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+func (cis *containerInstanceService) getContainerGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+) (*armresources.GenericResource, error) {
+	client, err := cis.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.GetByID(
+		ctx,
+		azure.ContainerGroupRID(subscriptionId, resourceGroupName, containerGroupName),
+		containerGroupApiVersion,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting container group: %w", err)
+	}
+
+	return &response.GenericResource, nil
+}
+
+func (cis *containerInstanceService) createResourcesClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armresources.Client, error) {
+	credential, err := cis.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := azsdk.DefaultClientOptionsBuilder(ctx, cis.httpClient, cis.userAgent).BuildArmClientOptions()
+	client, err := armresources.NewClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Resource client: %w", err)
+	}
+
+	return client, nil
+}
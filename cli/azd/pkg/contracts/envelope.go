@@ -9,6 +9,8 @@ type EventDataType string
 
 const (
 	ConsoleMessageEventDataType EventDataType = "consoleMessage"
+	ErrorEventDataType          EventDataType = "error"
+	LogEventDataType            EventDataType = "log"
 )
 
 type EventEnvelope struct {
@@ -0,0 +1,15 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// ErrorMessage is the data of the event written when a command fails and structured output is enabled.
+type ErrorMessage struct {
+	// Code classifies the failure, for example 'deploymentFailed' or 'toolFailed', so that consumers don't need to parse
+	// the message. It is 'error' when the failure isn't classified.
+	Code string `json:"code"`
+	// Message is the error message, without ANSI control sequences.
+	Message string `json:"message"`
+	// Details are optional values specific to the code, such as the exit code of the tool that failed.
+	Details map[string]any `json:"details,omitempty"`
+}
@@ -0,0 +1,12 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// LogMessage is a line of the output of a tool run by azd, such as the output of a docker build.
+type LogMessage struct {
+	// Phase is the step the tool is run for, for example 'Docker Output'.
+	Phase string `json:"phase"`
+	// Message is the line of output, without ANSI control sequences and the trailing newline.
+	Message string `json:"message"`
+}
@@ -0,0 +1,17 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+package contracts
+
+// ProvisionPreviewResult is the contract for the output of `azd provision --preview`.
+type ProvisionPreviewResult struct {
+	Changes []ProvisionPreviewChange `json:"changes"`
+}
+
+// ProvisionPreviewChange is the contract for a resource change in the "changes" array of a ProvisionPreviewResult.
+type ProvisionPreviewChange struct {
+	// The kind of change, for example "Create", "Modify" or "Delete"
+	ChangeType   string `json:"changeType"`
+	ResourceId   string `json:"resourceId,omitempty"`
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name"`
+}
@@ -10,12 +10,18 @@ const (
 	ShowTypePython ShowType = "python"
 	ShowTypeNode   ShowType = "node"
 	ShowTypeJava   ShowType = "java"
+	ShowTypeGo     ShowType = "go"
+	ShowTypeRust   ShowType = "rust"
+	ShowTypePhp    ShowType = "php"
+	ShowTypeRuby   ShowType = "ruby"
 )
 
 // ShowResult is the contract for the output of `azd show`
 type ShowResult struct {
 	Name     string                 `json:"name"`
 	Services map[string]ShowService `json:"services"`
+	// Resources contains the resources of the environment that match the query of `azd show --query`.
+	Resources []ShowResource `json:"resources,omitempty"`
 }
 
 // ShowService is the contract for a service returned by `azd show`
@@ -42,3 +48,14 @@ type ShowServiceProject struct {
 type ShowTargetArm struct {
 	ResourceIds []string `json:"resourceIds"`
 }
+
+// ShowResource is the contract for a resource of the environment returned by `azd show --query`
+type ShowResource struct {
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	// PortalUrl is the URL of the resource in the Azure Portal.
+	PortalUrl string `json:"portalUrl"`
+}
@@ -177,6 +177,12 @@ func (s *EnvironmentStore) Save(ctx context.Context, env *environment.Environmen
 	return nil
 }
 
+// Rename returns an error since the name of a devcenter environment is the name of the deployed environment,
+// which can't be changed
+func (s *EnvironmentStore) Rename(ctx context.Context, oldName string, newName string) error {
+	return fmt.Errorf("renaming devcenter environment '%s' is not supported", oldName)
+}
+
 // matchingEnvironments returns a list of environments matching the configured environment definition
 func (s *EnvironmentStore) matchingEnvironments(
 	ctx context.Context,
@@ -51,30 +51,59 @@ func (c *AzdContext) GetEnvironmentWorkDirectory(name string) string {
 // GetDefaultEnvironmentName returns the name of the default environment. Returns
 // an empty string if a default environment has not been set.
 func (c *AzdContext) GetDefaultEnvironmentName() (string, error) {
-	path := filepath.Join(c.EnvironmentDirectory(), ConfigFileName)
-	file, err := os.ReadFile(path)
-	switch {
-	case errors.Is(err, os.ErrNotExist):
-		return "", nil
-	case err != nil:
-		return "", fmt.Errorf("reading config file: %w", err)
-	}
-
-	var config configFile
-	if err := json.Unmarshal(file, &config); err != nil {
-		return "", fmt.Errorf("deserializing config file: %w", err)
+	config, err := readConfig(c.configPath())
+	if err != nil {
+		return "", err
 	}
 
 	return config.DefaultEnvironment, nil
 }
 
 func (c *AzdContext) SetDefaultEnvironmentName(name string) error {
-	path := filepath.Join(c.EnvironmentDirectory(), ConfigFileName)
-	config := configFile{
-		Version:            ConfigFileVersion,
-		DefaultEnvironment: name,
+	return c.updateConfig(func(config *configFile) {
+		config.DefaultEnvironment = name
+	})
+}
+
+// GetExistingInfraChoice returns what azd init does with an infra directory that already exists in the project, as
+// chosen by the user when the app was initialized. Returns an empty string if no choice was saved.
+func (c *AzdContext) GetExistingInfraChoice() (string, error) {
+	config, err := readConfig(c.configPath())
+	if err != nil {
+		return "", err
+	}
+
+	return config.ExistingInfra, nil
+}
+
+// SetExistingInfraChoice saves what azd init does with an infra directory that already exists in the project, so the
+// user isn't prompted again when the app is initialized again.
+func (c *AzdContext) SetExistingInfraChoice(choice string) error {
+	return c.updateConfig(func(config *configFile) {
+		config.ExistingInfra = choice
+	})
+}
+
+func (c *AzdContext) configPath() string {
+	return filepath.Join(c.EnvironmentDirectory(), ConfigFileName)
+}
+
+// updateConfig applies update to the config file, keeping the values that aren't updated. A config file that can't be
+// deserialized is replaced, as it was before the file held more than the default environment.
+func (c *AzdContext) updateConfig(update func(config *configFile)) error {
+	path := c.configPath()
+	config, err := readConfig(path)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr) || errors.As(err, &typeErr):
+		config = configFile{}
+	case err != nil:
+		return err
 	}
 
+	config.Version = ConfigFileVersion
+	update(&config)
 	return writeConfig(path, config)
 }
 
@@ -132,6 +161,25 @@ func NewAzdContext() (*AzdContext, error) {
 type configFile struct {
 	Version            int    `json:"version"`
 	DefaultEnvironment string `json:"defaultEnvironment"`
+	ExistingInfra      string `json:"existingInfra,omitempty"`
+}
+
+// readConfig reads the config file at path. Returns an empty config if the file does not exist.
+func readConfig(path string) (configFile, error) {
+	file, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return configFile{}, nil
+	case err != nil:
+		return configFile{}, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var config configFile
+	if err := json.Unmarshal(file, &config); err != nil {
+		return configFile{}, fmt.Errorf("deserializing config file: %w", err)
+	}
+
+	return config, nil
 }
 
 func writeConfig(path string, config configFile) error {
@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// outputsConfigPath is the path of the environment config that lists the keys whose values are infrastructure outputs
+const outputsConfigPath = "infra.outputs"
+
+// wellKnownOutputKeys are the keys of values that azd and its templates set from the outputs of the infrastructure. They
+// aren't cloned even when the outputs weren't recorded, as for environments provisioned before azd recorded them.
+var wellKnownOutputKeys = []string{
+	ResourceGroupEnvVarName,
+	ContainerRegistryEndpointEnvVarName,
+	AksClusterEnvVarName,
+}
+
+// isWellKnownOutputKey returns whether key is in wellKnownOutputKeys, or is a SERVICE_<name>_<property> value describing
+// the resource of a service, such as SERVICE_API_ENDPOINT_URL.
+func isWellKnownOutputKey(key string) bool {
+	if slices.Contains(wellKnownOutputKeys, key) {
+		return true
+	}
+
+	rest, isService := strings.CutPrefix(key, "SERVICE_")
+	name, property, _ := strings.Cut(rest, "_")
+	return isService && name != "" && property != ""
+}
+
+// OutputKeys returns the sorted keys of the environment whose values were set from the outputs of the infrastructure
+func (e *Environment) OutputKeys() []string {
+	var keys []string
+	if _, err := e.Config.GetSection(outputsConfigPath, &keys); err != nil {
+		return nil
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// AddOutputKeys records that the values of keys were set from the outputs of the infrastructure. [Save] should be called
+// to ensure this change is persisted.
+func (e *Environment) AddOutputKeys(keys ...string) error {
+	outputKeys := e.OutputKeys()
+	for _, key := range keys {
+		if !slices.Contains(outputKeys, key) {
+			outputKeys = append(outputKeys, key)
+		}
+	}
+
+	if len(outputKeys) == 0 {
+		return nil
+	}
+
+	slices.Sort(outputKeys)
+	return e.Config.Set(outputsConfigPath, outputKeys)
+}
+
+// CloneOptions controls the values copied when an environment is cloned
+type CloneOptions struct {
+	// When true, the values marked as secrets are not copied
+	ExcludeSecrets bool
+}
+
+// clone returns a new environment named name with the values and config of the environment. The values set from the
+// outputs of the infrastructure and the well-known output values aren't copied, since they describe resources of this
+// environment and are set again when the new environment is provisioned.
+func (e *Environment) clone(name string, options CloneOptions) (*Environment, error) {
+	cloned := New(name)
+	cloned.secretResolver = e.secretResolver
+
+	excludedKeys := e.OutputKeys()
+	if options.ExcludeSecrets {
+		excludedKeys = append(excludedKeys, e.SecretKeys()...)
+	}
+
+	for key, value := range e.Dotenv() {
+		if key != EnvNameEnvVarName && !slices.Contains(excludedKeys, key) && !isWellKnownOutputKey(key) {
+			cloned.DotenvSet(key, value)
+		}
+	}
+
+	// Copy the config through JSON so the environments don't share nested sections
+	configJson, err := json.Marshal(e.Config.Raw())
+	if err != nil {
+		return nil, fmt.Errorf("marshalling config: %w", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(configJson, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshalling config: %w", err)
+	}
+
+	cloned.Config = config.NewConfig(raw)
+	if err := cloned.Config.Unset(outputsConfigPath); err != nil {
+		return nil, err
+	}
+
+	if options.ExcludeSecrets {
+		if err := cloned.Config.Unset(secretsConfigPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return cloned, nil
+}
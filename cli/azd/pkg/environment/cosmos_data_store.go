@@ -0,0 +1,252 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/cosmos"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrCosmosAccessDenied     = errors.New("access denied connecting Azure Cosmos DB container.")
+	ErrConcurrentModification = errors.New("the remote environment was modified by another writer.")
+)
+
+// cosmosEnvDocument is the document that stores an environment in a Cosmos DB container
+type cosmosEnvDocument struct {
+	// The name of the environment
+	Id string `json:"id"`
+	// The contents of the .env file of the environment
+	DotEnv string `json:"dotenv"`
+	// The config of the environment
+	Config json.RawMessage `json:"config"`
+}
+
+// CosmosDataStore stores each environment as a document of a Cosmos DB container. Saves use the ETag of the document
+// last read or written by this data store, so a save fails with ErrConcurrentModification instead of overwriting changes
+// made by another writer in the meantime.
+type CosmosDataStore struct {
+	configManager   config.Manager
+	containerClient cosmos.ContainerClient
+	etags           map[string]string
+	etagsMu         sync.Mutex
+}
+
+func NewCosmosDataStore(configManager config.Manager, containerClient cosmos.ContainerClient) RemoteDataStore {
+	return &CosmosDataStore{
+		configManager:   configManager,
+		containerClient: containerClient,
+		etags:           map[string]string{},
+	}
+}
+
+// EnvPath returns the path to the .env file for the given environment
+func (cs *CosmosDataStore) EnvPath(env *Environment) string {
+	return fmt.Sprintf("%s/%s", env.name, DotEnvFileName)
+}
+
+// ConfigPath returns the path to the config.json file for the given environment
+func (cs *CosmosDataStore) ConfigPath(env *Environment) string {
+	return fmt.Sprintf("%s/%s", env.name, ConfigFileName)
+}
+
+func (cs *CosmosDataStore) List(ctx context.Context) ([]*contracts.EnvListEnvironment, error) {
+	items, err := cs.containerClient.ListItems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing documents: %w", describeCosmosError(err))
+	}
+
+	envs := []*contracts.EnvListEnvironment{}
+	for _, item := range items {
+		env := &Environment{name: item.Id}
+		envs = append(envs, &contracts.EnvListEnvironment{
+			Name:       item.Id,
+			DotEnvPath: cs.EnvPath(env),
+			ConfigPath: cs.ConfigPath(env),
+		})
+	}
+
+	slices.SortFunc(envs, func(a, b *contracts.EnvListEnvironment) bool {
+		return a.Name < b.Name
+	})
+
+	return envs, nil
+}
+
+func (cs *CosmosDataStore) Get(ctx context.Context, name string) (*Environment, error) {
+	env := &Environment{
+		name: name,
+	}
+
+	if err := cs.Reload(ctx, env); err != nil {
+		if errors.Is(err, cosmos.ErrItemNotFound) {
+			return nil, fmt.Errorf("%s %w", name, ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (cs *CosmosDataStore) Save(ctx context.Context, env *Environment) error {
+	cfgWriter := new(bytes.Buffer)
+
+	if err := cs.configManager.Save(env.Config, cfgWriter); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	marshalled, err := marshallDotEnv(env)
+	if err != nil {
+		return fmt.Errorf("marshalling .env: %w", err)
+	}
+
+	body, err := json.Marshal(cosmosEnvDocument{
+		Id:     env.name,
+		DotEnv: marshalled,
+		Config: cfgWriter.Bytes(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling document: %w", err)
+	}
+
+	item := &cosmos.Item{
+		Id:   env.name,
+		ETag: cs.etag(env.name),
+		Body: body,
+	}
+
+	var saved *cosmos.Item
+	if item.ETag != "" {
+		saved, err = cs.containerClient.ReplaceItem(ctx, item)
+	} else {
+		// There's no version of the document to compare with, so it's only created when nobody else created it since
+		saved, err = cs.containerClient.CreateItem(ctx, item)
+	}
+
+	if errors.Is(err, cosmos.ErrPreconditionFailed) || errors.Is(err, cosmos.ErrItemConflict) {
+		return fmt.Errorf(
+			"saving environment '%s': %w Run the command again to use the latest version of the environment.",
+			env.name,
+			ErrConcurrentModification,
+		)
+	} else if err != nil {
+		return fmt.Errorf("saving environment '%s': %w", env.name, describeCosmosError(err))
+	}
+
+	cs.setEtag(env.name, saved.ETag)
+
+	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.GetEnvName()))
+	return nil
+}
+
+func (cs *CosmosDataStore) Reload(ctx context.Context, env *Environment) error {
+	item, err := cs.containerClient.ReadItem(ctx, env.name)
+	if err != nil {
+		return describeCosmosError(err)
+	}
+
+	var document cosmosEnvDocument
+	if err := json.Unmarshal(item.Body, &document); err != nil {
+		return fmt.Errorf("unmarshalling document: %w", err)
+	}
+
+	envMap, err := godotenv.Unmarshal(document.DotEnv)
+	if err != nil {
+		env.setDotenv(make(map[string]string))
+	} else {
+		env.setDotenv(envMap)
+	}
+
+	if len(document.Config) == 0 {
+		env.Config = config.NewEmptyConfig()
+	} else if cfg, err := cs.configManager.Load(bytes.NewReader(document.Config)); err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	} else {
+		env.Config = cfg
+	}
+
+	cs.setEtag(env.name, item.ETag)
+
+	if env.GetEnvName() != "" {
+		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.GetEnvName()))
+	}
+
+	if _, err := uuid.Parse(env.GetSubscriptionId()); err == nil {
+		tracing.SetGlobalAttributes(fields.SubscriptionIdKey.String(env.GetSubscriptionId()))
+	} else {
+		tracing.SetGlobalAttributes(fields.StringHashed(fields.SubscriptionIdKey, env.GetSubscriptionId()))
+	}
+
+	return nil
+}
+
+// Rename creates the document of the environment under the new name, and then deletes the document of the old name
+func (cs *CosmosDataStore) Rename(ctx context.Context, oldName string, newName string) error {
+	env, err := cs.Get(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cs.Get(ctx, newName); err == nil {
+		return fmt.Errorf("'%s' %w", newName, ErrExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("checking for existing environment: %w", err)
+	}
+
+	env.name = newName
+	env.SetEnvName(newName)
+
+	if err := cs.Save(ctx, env); err != nil {
+		return err
+	}
+
+	if err := cs.containerClient.DeleteItem(ctx, oldName, cs.etag(oldName)); err != nil {
+		return fmt.Errorf("deleting environment '%s': %w", oldName, describeCosmosError(err))
+	}
+
+	cs.etagsMu.Lock()
+	delete(cs.etags, oldName)
+	cs.etagsMu.Unlock()
+
+	return nil
+}
+
+func (cs *CosmosDataStore) etag(name string) string {
+	cs.etagsMu.Lock()
+	defer cs.etagsMu.Unlock()
+
+	return cs.etags[name]
+}
+
+func (cs *CosmosDataStore) setEtag(name string, etag string) {
+	cs.etagsMu.Lock()
+	defer cs.etagsMu.Unlock()
+
+	cs.etags[name] = etag
+}
+
+func describeCosmosError(err error) error {
+	var responseErr *azcore.ResponseError
+
+	if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+		//nolint:lll
+		errorMsg := "Ensure your Azure account has the `Cosmos DB Built-in Data Contributor` role on the Cosmos DB account."
+		return fmt.Errorf("%w %s %w", ErrCosmosAccessDenied, errorMsg, err)
+	}
+
+	return err
+}
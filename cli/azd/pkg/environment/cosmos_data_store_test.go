@@ -0,0 +1,171 @@
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/cosmos"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CosmosDataStore_SaveAndGet(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	documents := registerCosmosMocks(mockContext)
+	dataStore := newTestCosmosDataStore(mockContext)
+
+	env1 := New("env1")
+	env1.DotenvSet("key1", "value1")
+	require.NoError(t, env1.Config.Set("infra.provider", "bicep"))
+	require.NoError(t, dataStore.Save(*mockContext.Context, env1))
+	require.Contains(t, documents, "env1")
+
+	// A second data store reads the environment written by the first one
+	env, err := newTestCosmosDataStore(mockContext).Get(*mockContext.Context, "env1")
+	require.NoError(t, err)
+	require.Equal(t, "value1", env.Getenv("key1"))
+	provider, _ := env.Config.Get("infra.provider")
+	require.Equal(t, "bicep", provider)
+
+	envList, err := dataStore.List(*mockContext.Context)
+	require.NoError(t, err)
+	require.Len(t, envList, 1)
+	require.Equal(t, "env1", envList[0].Name)
+	require.Equal(t, "env1/.env", envList[0].DotEnvPath)
+
+	_, err = dataStore.Get(*mockContext.Context, "env2")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_CosmosDataStore_ConcurrentSave(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	registerCosmosMocks(mockContext)
+
+	writer1 := newTestCosmosDataStore(mockContext)
+	require.NoError(t, writer1.Save(*mockContext.Context, New("env1")))
+
+	writer2 := newTestCosmosDataStore(mockContext)
+	env, err := writer2.Get(*mockContext.Context, "env1")
+	require.NoError(t, err)
+
+	env.DotenvSet("key1", "writer2")
+	require.NoError(t, writer2.Save(*mockContext.Context, env))
+
+	// writer1 last saved a version of the environment that writer2 has since replaced
+	env.DotenvSet("key1", "writer1")
+	err = writer1.Save(*mockContext.Context, env)
+	require.ErrorIs(t, err, ErrConcurrentModification)
+
+	require.NoError(t, writer1.Reload(*mockContext.Context, env))
+	require.Equal(t, "writer2", env.Getenv("key1"))
+
+	env.DotenvSet("key1", "writer1")
+	require.NoError(t, writer1.Save(*mockContext.Context, env))
+}
+
+func Test_CosmosDataStore_SaveWithoutRead(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	documents := registerCosmosMocks(mockContext)
+
+	writer1 := newTestCosmosDataStore(mockContext)
+	env := New("env1")
+	env.DotenvSet("key1", "writer1")
+	require.NoError(t, writer1.Save(*mockContext.Context, env))
+
+	// writer2 never read the environment, so it can't know whether it would overwrite the changes of writer1
+	env.DotenvSet("key1", "writer2")
+	err := newTestCosmosDataStore(mockContext).Save(*mockContext.Context, env)
+	require.ErrorIs(t, err, ErrConcurrentModification)
+	require.Contains(t, documents["env1"]["dotenv"], "writer1")
+}
+
+func newTestCosmosDataStore(mockContext *mocks.MockContext) RemoteDataStore {
+	containerClient := cosmos.NewContainerClient(
+		*mockContext.Context,
+		mockContext.Credentials,
+		&cosmos.AccountConfig{
+			AccountName:   "account",
+			DatabaseName:  "database",
+			ContainerName: "container",
+		},
+		mockContext.HttpClient,
+		"azd",
+	)
+
+	return NewCosmosDataStore(config.NewManager(), containerClient)
+}
+
+// registerCosmosMocks registers mocks that store the documents of a Cosmos DB container in memory
+func registerCosmosMocks(mockContext *mocks.MockContext) map[string]map[string]any {
+	const docsPath = "/dbs/database/colls/container/docs"
+	documents := map[string]map[string]any{}
+	version := 0
+
+	save := func(request *http.Request, document map[string]any, statusCode int) (*http.Response, error) {
+		version++
+		document["_etag"] = fmt.Sprintf("\"%d\"", version)
+		documents[document["id"].(string)] = document
+
+		return mocks.CreateHttpResponseWithBody(request, statusCode, document)
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasPrefix(request.URL.Path, docsPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if request.Header.Get("Authorization") != "type%3Daad%26ver%3D1.0%26sig%3DABC123" {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusUnauthorized)
+		}
+
+		var body map[string]any
+		if request.Body != nil && request.Body != http.NoBody {
+			if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+		}
+
+		if request.URL.Path == docsPath {
+			switch request.Method {
+			case http.MethodGet:
+				page := []map[string]any{}
+				for _, document := range documents {
+					page = append(page, document)
+				}
+
+				return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"Documents": page})
+			case http.MethodPost:
+				if _, has := documents[body["id"].(string)]; has {
+					return mocks.CreateEmptyHttpResponse(request, http.StatusConflict)
+				}
+
+				return save(request, body, http.StatusCreated)
+			}
+		}
+
+		id := strings.TrimPrefix(request.URL.Path, docsPath+"/")
+		document, has := documents[id]
+		if !has {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		}
+
+		switch request.Method {
+		case http.MethodGet:
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, document)
+		case http.MethodPut:
+			if etag := request.Header.Get("If-Match"); etag != "" && etag != document["_etag"] {
+				return mocks.CreateEmptyHttpResponse(request, http.StatusPreconditionFailed)
+			}
+
+			return save(request, body, http.StatusOK)
+		}
+
+		return nil, errors.New("unexpected request")
+	})
+
+	return documents
+}
@@ -12,10 +12,12 @@ type RemoteKind string
 
 const (
 	RemoteKindAzureBlobStorage RemoteKind = "AzureBlobStorage"
+	RemoteKindAzureCosmosDB    RemoteKind = "cosmos"
 )
 
 var ValidRemoteKinds = []string{
 	string(RemoteKindAzureBlobStorage),
+	string(RemoteKindAzureCosmosDB),
 }
 
 type DataStore interface {
@@ -36,6 +38,10 @@ type DataStore interface {
 
 	// Saves the environment to the persistent data store
 	Save(ctx context.Context, env *Environment) error
+
+	// Renames the environment within the persistent data store, failing with ErrExists when an environment with the
+	// new name already exists
+	Rename(ctx context.Context, oldName string, newName string) error
 }
 
 type LocalDataStore DataStore
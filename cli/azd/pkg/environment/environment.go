@@ -6,9 +6,11 @@ package environment
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"maps"
 
@@ -41,6 +43,9 @@ const AksClusterEnvVarName = "AZURE_AKS_CLUSTER_NAME"
 // ResourceGroupEnvVarName is the name of the azure resource group that should be used for deployments
 const ResourceGroupEnvVarName = "AZURE_RESOURCE_GROUP"
 
+// KeyVaultNameEnvVarName is the name of the key that templates set to the name of the Key Vault they provision.
+const KeyVaultNameEnvVarName = "AZURE_KEY_VAULT_NAME"
+
 // The zero value of an Environment is not valid. Use [New] to create one. When writing tests,
 // [Ephemeral] and [EphemeralWithValues] are useful to create environments which are not persisted to disk.
 type Environment struct {
@@ -53,6 +58,12 @@ type Environment struct {
 	// happens in Save
 	deletedKeys map[string]struct{}
 
+	// mu guards dotenv and deletedKeys, which are accessed concurrently when services are deployed in parallel
+	mu sync.RWMutex
+
+	// secretResolver resolves the values that are Key Vault references, set when the environment is loaded by a [Manager]
+	secretResolver SecretResolver
+
 	// Config is environment specific config
 	Config config.Config
 }
@@ -78,7 +89,7 @@ func NewWithValues(name string, values map[string]string) *Environment {
 	env := New(name)
 
 	if values != nil {
-		env.dotenv = values
+		env.setDotenv(values)
 	}
 
 	env.SetEnvName(name)
@@ -124,6 +135,9 @@ func CleanName(name string) string {
 // Getenv behaves like os.Getenv, except that any keys in the `.env` file associated with this environment are considered
 // first.
 func (e *Environment) Getenv(key string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	if v, has := e.dotenv[key]; has {
 		return v
 	}
@@ -131,9 +145,29 @@ func (e *Environment) Getenv(key string) string {
 	return os.Getenv(key)
 }
 
+// GetenvResolved behaves like Getenv, except that a value that is a Key Vault reference (see [KeyVaultReference]) is
+// resolved to the value of the secret it references, using the credential of the current user.
+func (e *Environment) GetenvResolved(ctx context.Context, key string) (string, error) {
+	value := e.Getenv(key)
+
+	reference, isReference := ParseKeyVaultReference(value)
+	if !isReference {
+		return value, nil
+	}
+
+	if e.secretResolver == nil {
+		return "", fmt.Errorf("the value of '%s' is a key vault reference, which can't be resolved in this context", key)
+	}
+
+	return e.secretResolver.ResolveSecret(ctx, e.GetSubscriptionId(), reference)
+}
+
 // LookupEnv behaves like os.LookupEnv, except that any keys in the `.env` file associated with this environment are
 // considered first.
 func (e *Environment) LookupEnv(key string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	if v, has := e.dotenv[key]; has {
 		return v, true
 	}
@@ -144,22 +178,41 @@ func (e *Environment) LookupEnv(key string) (string, bool) {
 // DotenvDelete removes the given key from the .env file in the environment, it is a no-op if the key
 // does not exist. [Save] should be called to ensure this change is persisted.
 func (e *Environment) DotenvDelete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	delete(e.dotenv, key)
 	e.deletedKeys[key] = struct{}{}
 }
 
 // Dotenv returns a copy of the key value pairs from the .env file in the environment.
 func (e *Environment) Dotenv() map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	return maps.Clone(e.dotenv)
 }
 
 // DotenvSet sets the value of [key] to [value] in the .env file associated with the environment. [Save] should be
 // called to ensure this change is persisted.
 func (e *Environment) DotenvSet(key string, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.dotenv[key] = value
 	delete(e.deletedKeys, key)
 }
 
+// setDotenv replaces the values of the .env file associated with the environment and forgets deleted keys. It is used
+// by data stores when the environment is loaded.
+func (e *Environment) setDotenv(values map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.dotenv = values
+	e.deletedKeys = make(map[string]struct{})
+}
+
 // GetEnvName is shorthand for Getenv(EnvNameEnvVarName)
 func (e *Environment) GetEnvName() string {
 	return e.Getenv(EnvNameEnvVarName)
@@ -212,6 +265,9 @@ func (e *Environment) SetServiceProperty(serviceName string, propertyName string
 // Creates a slice of key value pairs, based on the entries in the `.env` file like `KEY=VALUE` that
 // can be used to pass into command runner or similar constructs.
 func (e *Environment) Environ() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	envVars := []string{}
 	for k, v := range e.dotenv {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
@@ -258,11 +314,28 @@ func fixupUnquotedDotenv(values map[string]string, dotenv string) string {
 // Prepare dotenv for saving and returns a marshalled string that can be save to the underlying data store
 // Instead of calling `godotenv.Write` directly, we need to save the file ourselves, so we can fixup any numeric values
 // that were incorrectly unquoted.
+//
+// Values marked as secrets are only saved when they are Key Vault references, so secrets are never saved in plaintext.
 func marshallDotEnv(env *Environment) (string, error) {
-	marshalled, err := godotenv.Marshal(env.dotenv)
+	secretKeys := env.SecretKeys()
+
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+
+	values := maps.Clone(env.dotenv)
+	for _, key := range secretKeys {
+		if value, has := values[key]; has {
+			if _, isReference := ParseKeyVaultReference(value); !isReference {
+				log.Printf("not saving the value of secret '%s', which isn't a key vault reference", key)
+				delete(values, key)
+			}
+		}
+	}
+
+	marshalled, err := godotenv.Marshal(values)
 	if err != nil {
 		return "", fmt.Errorf("marshalling .env: %w", err)
 	}
 
-	return fixupUnquotedDotenv(env.dotenv, marshalled), nil
+	return fixupUnquotedDotenv(values, marshalled), nil
 }
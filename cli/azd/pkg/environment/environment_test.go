@@ -7,6 +7,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -196,6 +197,31 @@ func TestRoundTripNumberWithLeadingZeros(t *testing.T) {
 	require.Equal(t, "01", env2.dotenv["TEST"])
 }
 
+func TestConcurrentSaveAndServiceProperties(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	envManager, _ := createEnvManager(t, mockContext, t.TempDir())
+	env := New("test")
+
+	services := []string{"api", "web", "worker"}
+	var wg sync.WaitGroup
+	for _, service := range services {
+		wg.Add(1)
+		go func(service string) {
+			defer wg.Done()
+			env.SetServiceProperty(service, "ENDPOINT_URL", "https://"+service)
+			_ = env.Getenv("AZURE_ENV_NAME")
+			require.NoError(t, envManager.Save(*mockContext.Context, env))
+		}(service)
+	}
+	wg.Wait()
+
+	env2, err := envManager.Get(*mockContext.Context, "test")
+	require.NoError(t, err)
+	for _, service := range services {
+		require.Equal(t, "https://"+service, env2.GetServiceProperty(service, "ENDPOINT_URL"))
+	}
+}
+
 func Test_fixupUnquotedDotenv(t *testing.T) {
 	test := map[string]string{
 		"TEST_SHOULD_NOT_QUOTE": "1",
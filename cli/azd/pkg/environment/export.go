@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// SecretMask replaces the values of secrets exported with [ExportOptions.MaskSecrets]
+const SecretMask = "********"
+
+// secretsConfigPath is the path of the environment config that lists the keys whose values are secrets
+const secretsConfigPath = "secrets"
+
+// SecretKeys returns the sorted keys of the environment whose values are marked as secrets
+func (e *Environment) SecretKeys() []string {
+	var keys []string
+	if _, err := e.Config.GetSection(secretsConfigPath, &keys); err != nil {
+		return nil
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// IsSecret returns true when the value of key is marked as a secret
+func (e *Environment) IsSecret(key string) bool {
+	return slices.Contains(e.SecretKeys(), key)
+}
+
+// SetSecret marks or unmarks the value of key as a secret. [Save] should be called to ensure this change is persisted.
+func (e *Environment) SetSecret(key string, secret bool) error {
+	keys := slices.DeleteFunc(e.SecretKeys(), func(k string) bool {
+		return k == key
+	})
+	if secret {
+		keys = append(keys, key)
+		slices.Sort(keys)
+	}
+
+	if len(keys) == 0 {
+		return e.Config.Unset(secretsConfigPath)
+	}
+
+	return e.Config.Set(secretsConfigPath, keys)
+}
+
+// ExportOptions controls the values exported from an environment
+type ExportOptions struct {
+	// When set, only the keys that start with Prefix are exported
+	Prefix string
+	// When true, the values marked as secrets are exported, otherwise they are excluded
+	IncludeSecrets bool
+	// When true, the values marked as secrets are replaced with [SecretMask]. Only applies when IncludeSecrets is true.
+	MaskSecrets bool
+}
+
+// Export returns a copy of the values of the environment selected by options
+func (e *Environment) Export(options ExportOptions) map[string]string {
+	values := e.Dotenv()
+	secretKeys := e.SecretKeys()
+
+	maps.DeleteFunc(values, func(key string, _ string) bool {
+		return !strings.HasPrefix(key, options.Prefix) ||
+			(!options.IncludeSecrets && slices.Contains(secretKeys, key))
+	})
+
+	if options.MaskSecrets {
+		for _, key := range secretKeys {
+			if _, has := values[key]; has {
+				values[key] = SecretMask
+			}
+		}
+	}
+
+	return values
+}
+
+// ExportDotenv writes the values of the environment selected by options to writer in the dotenv format, one KEY=VALUE
+// line per key sorted by key. Values with characters other than letters, digits and the punctuation of paths and URLs
+// are double quoted, so the output can be read by docker compose and other dotenv parsers.
+func (e *Environment) ExportDotenv(writer io.Writer, options ExportOptions) error {
+	values := e.Export(options)
+	keys := maps.Keys(values)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(writer, "%s=%s\n", key, dotenvValue(values[key])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "$", `\$`)
+
+// dotenvValue returns value as written in a dotenv file, double quoted and escaped when it contains special characters
+func dotenvValue(value string) string {
+	special := strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-./:@,+%", r))
+	})
+	if special < 0 {
+		return value
+	}
+
+	return `"` + dotenvEscaper.Replace(value) + `"`
+}
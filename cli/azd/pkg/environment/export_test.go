@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSecret(t *testing.T) {
+	env := New("test")
+
+	require.NoError(t, env.SetSecret("B_KEY", true))
+	require.NoError(t, env.SetSecret("A_KEY", true))
+	require.Equal(t, []string{"A_KEY", "B_KEY"}, env.SecretKeys())
+	require.True(t, env.IsSecret("A_KEY"))
+
+	require.NoError(t, env.SetSecret("A_KEY", false))
+	require.NoError(t, env.SetSecret("B_KEY", false))
+	require.Empty(t, env.SecretKeys())
+	require.False(t, env.IsSecret("A_KEY"))
+}
+
+func TestExportDotenv(t *testing.T) {
+	env := NewWithValues("test", map[string]string{
+		"API_ENDPOINT": "https://api.contoso.com/v1",
+		"API_KEY":      "s3cret",
+		"GREETING":     `say "hi" $USER`,
+		"MULTILINE":    "line1\nline2",
+		"PORT":         "8080",
+	})
+	require.NoError(t, env.SetSecret("API_KEY", true))
+
+	tests := []struct {
+		name     string
+		options  ExportOptions
+		expected string
+	}{
+		{
+			name:    "ExcludesSecrets",
+			options: ExportOptions{},
+			expected: "API_ENDPOINT=https://api.contoso.com/v1\n" +
+				"AZURE_ENV_NAME=test\n" +
+				"GREETING=\"say \\\"hi\\\" \\$USER\"\n" +
+				"MULTILINE=\"line1\\nline2\"\n" +
+				"PORT=8080\n",
+		},
+		{
+			name:     "PrefixWithSecrets",
+			options:  ExportOptions{Prefix: "API_", IncludeSecrets: true},
+			expected: "API_ENDPOINT=https://api.contoso.com/v1\nAPI_KEY=s3cret\n",
+		},
+		{
+			name:     "MasksSecrets",
+			options:  ExportOptions{Prefix: "API_", IncludeSecrets: true, MaskSecrets: true},
+			expected: "API_ENDPOINT=https://api.contoso.com/v1\nAPI_KEY=\"********\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			require.NoError(t, env.ExportDotenv(buffer, tt.options))
+			require.Equal(t, tt.expected, buffer.String())
+		})
+	}
+}
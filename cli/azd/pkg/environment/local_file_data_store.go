@@ -97,13 +97,11 @@ func (fs *LocalFileDataStore) Get(ctx context.Context, name string) (*Environmen
 func (fs *LocalFileDataStore) Reload(ctx context.Context, env *Environment) error {
 	// Reload env values
 	if envMap, err := godotenv.Read(fs.EnvPath(env)); errors.Is(err, os.ErrNotExist) {
-		env.dotenv = make(map[string]string)
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(make(map[string]string))
 	} else if err != nil {
 		return fmt.Errorf("loading .env: %w", err)
 	} else {
-		env.dotenv = envMap
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(envMap)
 	}
 
 	// Reload env config
@@ -136,13 +134,16 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment) error
 	}
 
 	// Cache current values & reload to get any new env vars
+	env.mu.RLock()
 	currentValues := env.dotenv
 	deletedValues := env.deletedKeys
+	env.mu.RUnlock()
 	if err := fs.Reload(ctx, env); err != nil {
 		return fmt.Errorf("failed reloading env vars, %w", err)
 	}
 
 	// Overlay current values before saving
+	env.mu.Lock()
 	for key, value := range currentValues {
 		env.dotenv[key] = value
 	}
@@ -151,6 +152,7 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment) error
 	for key := range deletedValues {
 		delete(env.dotenv, key)
 	}
+	env.mu.Unlock()
 
 	marshalled, err := marshallDotEnv(env)
 	if err != nil {
@@ -175,3 +177,30 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment) error
 	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.GetEnvName()))
 	return nil
 }
+
+// Rename moves the environment directory of the environment, and updates the environment name within the .env file
+func (fs *LocalFileDataStore) Rename(ctx context.Context, oldName string, newName string) error {
+	oldRoot := fs.azdContext.EnvironmentRoot(oldName)
+	if _, err := os.Stat(oldRoot); err != nil {
+		return fmt.Errorf("'%s' %w, %w", oldName, ErrNotFound, err)
+	}
+
+	newRoot := fs.azdContext.EnvironmentRoot(newName)
+	if _, err := os.Stat(newRoot); err == nil {
+		return fmt.Errorf("'%s' %w", newName, ErrExists)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("checking for existing environment: %w", err)
+	}
+
+	if err := os.Rename(oldRoot, newRoot); err != nil {
+		return fmt.Errorf("moving environment directory: %w", err)
+	}
+
+	env, err := fs.Get(ctx, newName)
+	if err != nil {
+		return err
+	}
+
+	env.SetEnvName(newName)
+	return fs.Save(ctx, env)
+}
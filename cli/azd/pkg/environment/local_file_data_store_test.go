@@ -83,3 +83,38 @@ func Test_LocalFileDataStore_ConfigPath(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func Test_LocalFileDataStore_Rename(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+
+	env1 := New("env1")
+	env1.DotenvSet("key1", "value1")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env1))
+	require.NoError(t, dataStore.Save(*mockContext.Context, New("env2")))
+
+	t.Run("Success", func(t *testing.T) {
+		err := dataStore.Rename(*mockContext.Context, "env1", "dev")
+		require.NoError(t, err)
+
+		env, err := dataStore.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		require.Equal(t, "dev", env.GetEnvName())
+		require.Equal(t, "value1", env.Getenv("key1"))
+
+		_, err = dataStore.Get(*mockContext.Context, "env1")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		err := dataStore.Rename(*mockContext.Context, "dev", "env2")
+		require.ErrorIs(t, err, ErrExists)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		err := dataStore.Rename(*mockContext.Context, "env1", "test")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -11,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -56,7 +59,10 @@ type Manager interface {
 	List(ctx context.Context) ([]*Description, error)
 	Get(ctx context.Context, name string) (*Environment, error)
 	Save(ctx context.Context, env *Environment) error
+	SaveSecret(ctx context.Context, env *Environment, key string, reference KeyVaultReference, value string) error
 	Reload(ctx context.Context, env *Environment) error
+	Rename(ctx context.Context, oldName string, newName string) error
+	Clone(ctx context.Context, source string, dest string, options CloneOptions) (*Environment, error)
 	EnvPath(env *Environment) string
 	ConfigPath(env *Environment) string
 }
@@ -66,6 +72,11 @@ type manager struct {
 	remote     DataStore
 	azdContext *azdcontext.AzdContext
 	console    input.Console
+	// secretResolver is set on the environments returned by the manager
+	secretResolver SecretResolver
+
+	// saveMu serializes saves, which read and rewrite the files of the environment
+	saveMu sync.Mutex
 }
 
 // NewManager creates a new Manager instance
@@ -75,6 +86,7 @@ func NewManager(
 	console input.Console,
 	local LocalDataStore,
 	remoteConfig *state.RemoteConfig,
+	secretResolver SecretResolver,
 ) (Manager, error) {
 	var remote RemoteDataStore
 
@@ -97,10 +109,11 @@ func NewManager(
 	}
 
 	return &manager{
-		azdContext: azdContext,
-		local:      local,
-		remote:     remote,
-		console:    console,
+		azdContext:     azdContext,
+		local:          local,
+		remote:         remote,
+		console:        console,
+		secretResolver: secretResolver,
 	}, nil
 }
 
@@ -126,6 +139,7 @@ func (m *manager) Create(ctx context.Context, spec Spec) (*Environment, error) {
 	}
 
 	env := New(spec.Name)
+	env.secretResolver = m.secretResolver
 
 	if spec.Subscription != "" {
 		env.SetSubscriptionId(spec.Subscription)
@@ -292,29 +306,39 @@ func (m *manager) List(ctx context.Context) ([]*Description, error) {
 
 // Get returns the environment instance for the specified environment name
 func (m *manager) Get(ctx context.Context, name string) (*Environment, error) {
-	localEnv, err := m.local.Get(ctx, name)
-	if err != nil {
-		if m.remote == nil {
-			return nil, err
-		}
+	env, err := m.local.Get(ctx, name)
 
-		remoteEnv, err := m.remote.Get(ctx, name)
-		if err != nil {
-			return nil, err
-		}
+	if m.remote != nil {
+		// The remote copy is the source of truth. Reading it also records the version the next save is based on, so that
+		// the save fails instead of overwriting the changes of other writers.
+		remoteEnv, remoteErr := m.remote.Get(ctx, name)
+		switch {
+		case remoteErr == nil:
+			if err := m.local.Save(ctx, remoteEnv); err != nil {
+				return nil, err
+			}
 
-		if err := m.local.Save(ctx, remoteEnv); err != nil {
-			return nil, err
+			env, err = remoteEnv, nil
+		case errors.Is(remoteErr, ErrNotFound) && err == nil:
+			// the environment hasn't been saved remotely yet, it's created by the next save
+		default:
+			return nil, remoteErr
 		}
+	}
 
-		localEnv = remoteEnv
+	if err != nil {
+		return nil, err
 	}
 
-	return localEnv, nil
+	env.secretResolver = m.secretResolver
+	return env, nil
 }
 
 // Save saves the environment to the persistent data store
 func (m *manager) Save(ctx context.Context, env *Environment) error {
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+
 	if err := m.local.Save(ctx, env); err != nil {
 		return fmt.Errorf("saving local environment, %w", err)
 	}
@@ -330,11 +354,181 @@ func (m *manager) Save(ctx context.Context, env *Environment) error {
 	return nil
 }
 
+// SaveSecret stores value in the Key Vault secret referenced by reference, with the credential of the current user, and
+// saves the environment with key set to the reference and marked as a secret, so that the value itself is never written
+// to the environment. The value is read back from Key Vault when the key is resolved with GetenvResolved.
+func (m *manager) SaveSecret(
+	ctx context.Context, env *Environment, key string, reference KeyVaultReference, value string) error {
+	subscriptionId := env.GetSubscriptionId()
+	if subscriptionId == "" {
+		return fmt.Errorf("storing secret '%s': the environment doesn't have a subscription (%s)",
+			key, SubscriptionIdEnvVarName)
+	}
+
+	// new versions of the secret replace the value, so the reference always tracks the latest version
+	reference.SecretVersion = ""
+	if err := m.secretResolver.StoreSecret(ctx, subscriptionId, reference, value); err != nil {
+		return err
+	}
+
+	env.DotenvSet(key, reference.String())
+	if err := env.SetSecret(key, true); err != nil {
+		return fmt.Errorf("marking secret: %w", err)
+	}
+
+	return m.Save(ctx, env)
+}
+
 // Reload reloads the environment from the persistent data store
 func (m *manager) Reload(ctx context.Context, env *Environment) error {
 	return m.local.Reload(ctx, env)
 }
 
+// Rename renames the local and remote copies of the environment, and updates the default environment when it is the
+// renamed environment. AZURE_ENV_NAME is set to the new name, so when the environment was provisioned, the user is warned
+// that its resources, which are named and tagged from the old name, aren't found by the renamed environment.
+func (m *manager) Rename(ctx context.Context, oldName string, newName string) error {
+	if !IsValidEnvironmentName(newName) {
+		return errors.New(invalidEnvironmentNameMsg(newName))
+	}
+
+	m.saveMu.Lock()
+	defer m.saveMu.Unlock()
+
+	envs, err := m.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(envs, func(env *Description) bool { return env.Name == newName }) {
+		return fmt.Errorf("'%s' %w", newName, ErrExists)
+	}
+
+	index := slices.IndexFunc(envs, func(env *Description) bool { return env.Name == oldName })
+	if index < 0 {
+		return fmt.Errorf("'%s' %w", oldName, ErrNotFound)
+	}
+
+	store := m.local
+	if !envs[index].HasLocal {
+		store = m.remote
+	}
+
+	env, err := store.Get(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	if envs[index].HasLocal {
+		if err := m.local.Rename(ctx, oldName, newName); err != nil {
+			return fmt.Errorf("renaming local environment, %w", err)
+		}
+	}
+
+	if envs[index].HasRemote {
+		if err := m.remote.Rename(ctx, oldName, newName); err != nil {
+			// keep the local and remote copies under the same name
+			if envs[index].HasLocal {
+				if rollbackErr := m.local.Rename(ctx, newName, oldName); rollbackErr != nil {
+					return fmt.Errorf(
+						"renaming remote environment, %w, and restoring the name of the local environment, %w",
+						err, rollbackErr)
+				}
+			}
+
+			return fmt.Errorf("renaming remote environment, %w", err)
+		}
+	}
+
+	if envs[index].IsDefault {
+		if err := m.azdContext.SetDefaultEnvironmentName(newName); err != nil {
+			return fmt.Errorf("setting default environment: %w", err)
+		}
+	}
+
+	if len(env.OutputKeys()) > 0 || slices.ContainsFunc(maps.Keys(env.Dotenv()), isWellKnownOutputKey) {
+		m.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"the resources of environment '%s' are named and tagged from its name, so '%s' doesn't find them: "+
+					"'azd provision' creates new resources and 'azd down' doesn't delete the existing ones. Rename it "+
+					"back to '%s' to manage them.",
+				oldName, newName, oldName),
+		})
+	}
+
+	return nil
+}
+
+// Clone copies the values and config of the source environment into the dest environment, excluding the values set from
+// the outputs of the infrastructure. When dest already exists, the user is prompted before it is overwritten.
+func (m *manager) Clone(ctx context.Context, source string, dest string, options CloneOptions) (*Environment, error) {
+	if !IsValidEnvironmentName(dest) {
+		return nil, errors.New(invalidEnvironmentNameMsg(dest))
+	}
+
+	if source == dest {
+		return nil, fmt.Errorf("cannot clone environment '%s' into itself", source)
+	}
+
+	sourceEnv, err := m.Get(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	envs, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.ContainsFunc(envs, func(env *Description) bool { return env.Name == dest }) {
+		overwrite, err := m.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      fmt.Sprintf("Environment '%s' already exists, would you like to overwrite it?", dest),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("prompting to overwrite environment '%s': %w", dest, err)
+		}
+
+		if !overwrite {
+			return nil, fmt.Errorf("'%s' %w", dest, ErrExists)
+		}
+	}
+
+	destEnv, err := sourceEnv.clone(dest, options)
+	if err != nil {
+		return nil, fmt.Errorf("cloning environment '%s': %w", source, err)
+	}
+
+	// Environments provisioned before azd recorded the outputs of the infrastructure only have the well-known output
+	// values excluded, so other outputs describing the resources of the source may have been copied
+	if len(sourceEnv.OutputKeys()) == 0 && slices.ContainsFunc(maps.Keys(sourceEnv.Dotenv()), isWellKnownOutputKey) {
+		m.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"environment '%s' doesn't record which of its values are outputs of the infrastructure, so only "+
+					"%s and the SERVICE_<name>_* values weren't copied. Review the values of '%s' before provisioning it, "+
+					"or run 'azd provision' in '%s' to record its outputs and clone it again.",
+				source, strings.Join(wellKnownOutputKeys, ", "), dest, source),
+		})
+	}
+
+	// Saving merges the values of the environment with the values already saved, so the values of an existing environment
+	// that aren't cloned are deleted to replace its contents
+	if existing, err := m.local.Get(ctx, dest); err == nil {
+		clonedValues := destEnv.Dotenv()
+		for key := range existing.Dotenv() {
+			if _, has := clonedValues[key]; !has {
+				destEnv.DotenvDelete(key)
+			}
+		}
+	}
+
+	if err := m.Save(ctx, destEnv); err != nil {
+		return nil, err
+	}
+
+	return destEnv, nil
+}
+
 // ensureValidEnvironmentName ensures the environment name is valid, if it is not, an error is printed
 // and the user is prompted for a new name.
 func (m *manager) ensureValidEnvironmentName(ctx context.Context, spec *Spec) error {
@@ -204,6 +204,7 @@ func Test_EnvManager_Get(t *testing.T) {
 		remoteDataStore := &MockDataStore{}
 
 		localDataStore.On("Get", *mockContext.Context, "env1").Return(getEnv, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env1").Return(nil, ErrNotFound)
 
 		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
 		env, err := manager.Get(*mockContext.Context, "env1")
@@ -212,6 +213,22 @@ func Test_EnvManager_Get(t *testing.T) {
 		require.Equal(t, getEnv, env)
 	})
 
+	t.Run("ExistsLocallyAndRemotely", func(t *testing.T) {
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+		remoteEnv := NewWithValues("env1", map[string]string{"key1": "remote"})
+
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(getEnv, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env1").Return(remoteEnv, nil)
+		localDataStore.On("Save", *mockContext.Context, remoteEnv).Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		env, err := manager.Get(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Equal(t, "remote", env.Getenv("key1"))
+		localDataStore.AssertCalled(t, "Save", *mockContext.Context, remoteEnv)
+	})
+
 	t.Run("ExistsRemotely", func(t *testing.T) {
 		localDataStore := &MockDataStore{}
 		remoteDataStore := &MockDataStore{}
@@ -283,6 +300,233 @@ func Test_EnvManager_Save(t *testing.T) {
 	})
 }
 
+func Test_EnvManager_Rename(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	t.Run("LocalAndRemote", func(t *testing.T) {
+		azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+		require.NoError(t, azdContext.SetDefaultEnvironmentName("env1"))
+
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("List", *mockContext.Context).Return(localEnvList, nil)
+		remoteDataStore.On("List", *mockContext.Context).Return(remoteEnvList, nil)
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(New("env1"), nil)
+		localDataStore.On("Rename", *mockContext.Context, "env1", "dev").Return(nil)
+		remoteDataStore.On("Rename", *mockContext.Context, "env1", "dev").Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.Rename(*mockContext.Context, "env1", "dev")
+		require.NoError(t, err)
+
+		localDataStore.AssertCalled(t, "Rename", *mockContext.Context, "env1", "dev")
+		remoteDataStore.AssertCalled(t, "Rename", *mockContext.Context, "env1", "dev")
+
+		defaultEnvName, err := azdContext.GetDefaultEnvironmentName()
+		require.NoError(t, err)
+		require.Equal(t, "dev", defaultEnvName)
+	})
+
+	t.Run("Provisioned", func(t *testing.T) {
+		provisionedContext := mocks.NewMockContext(context.Background())
+		azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		provisioned := NewWithValues("env1", map[string]string{
+			"AZURE_RESOURCE_GROUP": "rg-env1",
+		})
+
+		localDataStore.On("List", *provisionedContext.Context).Return(localEnvList, nil)
+		remoteDataStore.On("List", *provisionedContext.Context).Return(remoteEnvList, nil)
+		localDataStore.On("Get", *provisionedContext.Context, "env1").Return(provisioned, nil)
+		localDataStore.On("Rename", *provisionedContext.Context, "env1", "dev").Return(nil)
+		remoteDataStore.On("Rename", *provisionedContext.Context, "env1", "dev").Return(nil)
+
+		manager := newManagerForTest(azdContext, provisionedContext.Console, localDataStore, remoteDataStore)
+		err := manager.Rename(*provisionedContext.Context, "env1", "dev")
+		require.NoError(t, err)
+
+		require.Contains(t, strings.Join(provisionedContext.Console.Output(), "\n"),
+			"the resources of environment 'env1' are named and tagged from its name, so 'dev' doesn't find them")
+	})
+
+	t.Run("RemoteFails", func(t *testing.T) {
+		azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+		require.NoError(t, azdContext.SetDefaultEnvironmentName("env1"))
+
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("List", *mockContext.Context).Return(localEnvList, nil)
+		remoteDataStore.On("List", *mockContext.Context).Return(remoteEnvList, nil)
+		localDataStore.On("Get", *mockContext.Context, "env1").Return(New("env1"), nil)
+		localDataStore.On("Rename", *mockContext.Context, "env1", "dev").Return(nil)
+		localDataStore.On("Rename", *mockContext.Context, "dev", "env1").Return(nil)
+		remoteDataStore.On("Rename", *mockContext.Context, "env1", "dev").Return(errors.New("error"))
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.Rename(*mockContext.Context, "env1", "dev")
+		require.ErrorContains(t, err, "renaming remote environment")
+
+		// the local environment is renamed back
+		localDataStore.AssertCalled(t, "Rename", *mockContext.Context, "dev", "env1")
+
+		defaultEnvName, err := azdContext.GetDefaultEnvironmentName()
+		require.NoError(t, err)
+		require.Equal(t, "env1", defaultEnvName)
+	})
+
+	t.Run("RemoteOnly", func(t *testing.T) {
+		azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+		require.NoError(t, azdContext.SetDefaultEnvironmentName("env1"))
+
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("List", *mockContext.Context).Return(localEnvList, nil)
+		remoteDataStore.On("List", *mockContext.Context).Return(remoteEnvList, nil)
+		remoteDataStore.On("Get", *mockContext.Context, "env3").Return(New("env3"), nil)
+		remoteDataStore.On("Rename", *mockContext.Context, "env3", "dev").Return(nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+		err := manager.Rename(*mockContext.Context, "env3", "dev")
+		require.NoError(t, err)
+
+		localDataStore.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything, mock.Anything)
+
+		defaultEnvName, err := azdContext.GetDefaultEnvironmentName()
+		require.NoError(t, err)
+		require.Equal(t, "env1", defaultEnvName)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+		localDataStore := &MockDataStore{}
+		remoteDataStore := &MockDataStore{}
+
+		localDataStore.On("List", *mockContext.Context).Return(localEnvList, nil)
+		remoteDataStore.On("List", *mockContext.Context).Return(remoteEnvList, nil)
+
+		manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+
+		err := manager.Rename(*mockContext.Context, "env1", "env2")
+		require.ErrorIs(t, err, ErrExists)
+
+		err = manager.Rename(*mockContext.Context, "env4", "dev")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		err = manager.Rename(*mockContext.Context, "env1", "not a valid name")
+		require.Error(t, err)
+
+		localDataStore.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything, mock.Anything)
+		remoteDataStore.AssertNotCalled(t, "Rename", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_EnvManager_Clone(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	localDataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+	manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, nil)
+
+	source := NewWithValues("prod", map[string]string{
+		"AZURE_LOCATION": "eastus2",
+		"API_KEY":        "keyvault://vault/api-key",
+		"WEBSITE_URL":    "https://prod.azurewebsites.net",
+	})
+	require.NoError(t, source.SetSecret("API_KEY", true))
+	require.NoError(t, source.AddOutputKeys("WEBSITE_URL"))
+	require.NoError(t, source.Config.Set("infra.parameters.sku", "P1"))
+	require.NoError(t, manager.Save(*mockContext.Context, source))
+
+	t.Run("Success", func(t *testing.T) {
+		cloned, err := manager.Clone(*mockContext.Context, "prod", "staging", CloneOptions{})
+		require.NoError(t, err)
+
+		staging, err := manager.Get(*mockContext.Context, "staging")
+		require.NoError(t, err)
+		require.Equal(t, cloned.Dotenv(), staging.Dotenv())
+		require.Equal(t, map[string]string{
+			"AZURE_ENV_NAME": "staging",
+			"AZURE_LOCATION": "eastus2",
+			"API_KEY":        "keyvault://vault/api-key",
+		}, staging.Dotenv())
+		require.Equal(t, []string{"API_KEY"}, staging.SecretKeys())
+		require.Empty(t, staging.OutputKeys())
+
+		sku, _ := staging.Config.Get("infra.parameters.sku")
+		require.Equal(t, "P1", sku)
+
+		// The config of the source isn't shared with the clone
+		require.NoError(t, staging.Config.Set("infra.parameters.sku", "B1"))
+		sku, _ = source.Config.Get("infra.parameters.sku")
+		require.Equal(t, "P1", sku)
+	})
+
+	t.Run("OverwriteExcludingSecrets", func(t *testing.T) {
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "'staging' already exists")
+		}).Respond(true)
+
+		_, err := manager.Clone(*mockContext.Context, "prod", "staging", CloneOptions{ExcludeSecrets: true})
+		require.NoError(t, err)
+
+		staging, err := manager.Get(*mockContext.Context, "staging")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"AZURE_ENV_NAME": "staging",
+			"AZURE_LOCATION": "eastus2",
+		}, staging.Dotenv())
+		require.Empty(t, staging.SecretKeys())
+	})
+
+	t.Run("DeclineOverwrite", func(t *testing.T) {
+		declineContext := mocks.NewMockContext(context.Background())
+		declineContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "'staging' already exists")
+		}).Respond(false)
+
+		manager := newManagerForTest(azdContext, declineContext.Console, localDataStore, nil)
+		_, err := manager.Clone(*declineContext.Context, "prod", "staging", CloneOptions{})
+		require.ErrorIs(t, err, ErrExists)
+	})
+
+	t.Run("OutputsNotRecorded", func(t *testing.T) {
+		outputsContext := mocks.NewMockContext(context.Background())
+		manager := newManagerForTest(azdContext, outputsContext.Console, localDataStore, nil)
+
+		legacy := NewWithValues("legacy", map[string]string{
+			"AZURE_LOCATION":           "eastus2",
+			"AZURE_RESOURCE_GROUP":     "rg-legacy",
+			"SERVICE_API_ENDPOINT_URL": "https://api.azurewebsites.net",
+			"SERVICE_":                 "not a service value",
+		})
+		require.NoError(t, manager.Save(*outputsContext.Context, legacy))
+
+		_, err := manager.Clone(*outputsContext.Context, "legacy", "legacy-copy", CloneOptions{})
+		require.NoError(t, err)
+
+		copied, err := manager.Get(*outputsContext.Context, "legacy-copy")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"AZURE_ENV_NAME": "legacy-copy",
+			"AZURE_LOCATION": "eastus2",
+			"SERVICE_":       "not a service value",
+		}, copied.Dotenv())
+		require.Contains(t, strings.Join(outputsContext.Console.Output(), "\n"),
+			"environment 'legacy' doesn't record which of its values are outputs of the infrastructure")
+	})
+
+	t.Run("SourceNotFound", func(t *testing.T) {
+		_, err := manager.Clone(*mockContext.Context, "test", "staging", CloneOptions{})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
 func Test_EnvManager_CreateFromContainer(t *testing.T) {
 	t.Run("WithRemoteConfig", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
@@ -332,6 +576,9 @@ func registerContainerComponents(t *testing.T, mockContext *mocks.MockContext) {
 	})
 	mockContext.Container.RegisterSingleton(NewManager)
 	mockContext.Container.RegisterSingleton(NewLocalFileDataStore)
+	mockContext.Container.RegisterSingleton(func() SecretResolver {
+		return &fakeSecretResolver{}
+	})
 	_ = mockContext.Container.RegisterNamedSingleton(string(RemoteKindAzureBlobStorage), NewStorageBlobDataStore)
 
 	mockContext.Container.RegisterSingleton(storage.NewBlobSdkClient)
@@ -394,3 +641,8 @@ func (m *MockDataStore) Save(ctx context.Context, env *Environment) error {
 	args := m.Called(ctx, env)
 	return args.Error(0)
 }
+
+func (m *MockDataStore) Rename(ctx context.Context, oldName string, newName string) error {
+	args := m.Called(ctx, oldName, newName)
+	return args.Error(0)
+}
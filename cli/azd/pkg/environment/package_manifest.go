@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"time"
+)
+
+// packagesConfigPath is the path of the environment config that holds the package manifest, the last package of each
+// service by service name
+const packagesConfigPath = "packages"
+
+// ServicePackage is the record of the package of a service in the package manifest of an environment. It's written by
+// azd package and read by azd deploy, so a service can be deployed without packaging it again.
+type ServicePackage struct {
+	// The kind of the service target the service was packaged for, e.g. containerapp
+	Target string `json:"target"`
+	// The local tag of the container image, for services packaged as a container image
+	Image string `json:"image,omitempty"`
+	// The id of the container image, the digest of its config
+	ImageId string `json:"imageId,omitempty"`
+	// The reference of the container image in the registry, pinned by its digest when it's known, for images pushed
+	// when the service was packaged. Images with a reference are deployed from the registry, from any machine.
+	ImageRef string `json:"imageRef,omitempty"`
+	// The path of the package artifact, for services packaged as a file or directory. The paths of artifacts in the
+	// project are relative to the directory of the environment, with forward slashes, others are absolute.
+	ArtifactPath string `json:"artifactPath,omitempty"`
+	// When the service was packaged
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PackageManifest returns the package manifest of the environment, the last package of each service by service name
+func (e *Environment) PackageManifest() (map[string]ServicePackage, error) {
+	manifest := map[string]ServicePackage{}
+	if _, err := e.Config.GetSection(packagesConfigPath, &manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// ServicePackage returns the last package of the service recorded in the package manifest of the environment, or nil
+// when no package of the service is recorded.
+func (e *Environment) ServicePackage(serviceName string) (*ServicePackage, error) {
+	manifest, err := e.PackageManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	servicePackage, has := manifest[serviceName]
+	if !has {
+		return nil, nil
+	}
+
+	return &servicePackage, nil
+}
+
+// SetServicePackage records the package of the service in the package manifest of the environment, replacing the
+// previous package of the service. When servicePackage is nil, the package of the service is removed. [Save] should be
+// called to ensure this change is persisted.
+func (e *Environment) SetServicePackage(serviceName string, servicePackage *ServicePackage) error {
+	manifest, err := e.PackageManifest()
+	if err != nil {
+		return err
+	}
+
+	if servicePackage == nil {
+		delete(manifest, serviceName)
+	} else {
+		manifest[serviceName] = *servicePackage
+	}
+
+	if len(manifest) == 0 {
+		return e.Config.Unset(packagesConfigPath)
+	}
+
+	return e.Config.Set(packagesConfigPath, manifest)
+}
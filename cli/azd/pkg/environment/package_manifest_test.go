@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicePackage(t *testing.T) {
+	env := New("test")
+	timestamp := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	servicePackage, err := env.ServicePackage("api")
+	require.NoError(t, err)
+	require.Nil(t, servicePackage)
+
+	api := &ServicePackage{
+		Target:    "containerapp",
+		Image:     "todo/api-test:azd-deploy-100",
+		ImageId:   "sha256:0123",
+		Timestamp: timestamp,
+	}
+	web := &ServicePackage{
+		Target:       "appservice",
+		ArtifactPath: "/packages/web.zip",
+		Timestamp:    timestamp,
+	}
+	require.NoError(t, env.SetServicePackage("api", api))
+	require.NoError(t, env.SetServicePackage("web", web))
+
+	// the manifest is read back after the config is saved and loaded as json
+	data, err := json.Marshal(env.Config.Raw())
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	env.Config = config.NewConfig(raw)
+
+	manifest, err := env.PackageManifest()
+	require.NoError(t, err)
+	require.Equal(t, map[string]ServicePackage{"api": *api, "web": *web}, manifest)
+
+	servicePackage, err = env.ServicePackage("web")
+	require.NoError(t, err)
+	require.Equal(t, web, servicePackage)
+
+	require.NoError(t, env.SetServicePackage("api", nil))
+	require.NoError(t, env.SetServicePackage("web", nil))
+	_, has := env.Config.Get(packagesConfigPath)
+	require.False(t, has)
+}
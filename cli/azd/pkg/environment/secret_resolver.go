@@ -0,0 +1,205 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// KeyVaultReference references a secret stored in Azure Key Vault. An environment value is a Key Vault reference when it
+// has one of the forms:
+//
+//	@Microsoft.KeyVault(SecretUri=https://<vault>.vault.azure.net/secrets/<secret>[/<version>])
+//	@Microsoft.KeyVault(VaultName=<vault>;SecretName=<secret>[;SecretVersion=<version>])
+//	keyvault://<vault>/<secret>[/<version>]
+type KeyVaultReference struct {
+	// The name of the vault, or the URL of the vault when the reference is a secret URI
+	Vault string
+	// The name of the secret
+	SecretName string
+	// The version of the secret, the latest version when empty
+	SecretVersion string
+}
+
+// ParseKeyVaultReference parses value as a Key Vault reference, returning false when value isn't a Key Vault reference.
+func ParseKeyVaultReference(value string) (KeyVaultReference, bool) {
+	value = strings.TrimSpace(value)
+
+	if path, has := strings.CutPrefix(value, "keyvault://"); has {
+		parts := strings.Split(path, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return KeyVaultReference{}, false
+		}
+
+		reference := KeyVaultReference{Vault: parts[0], SecretName: parts[1]}
+		if len(parts) == 3 {
+			reference.SecretVersion = parts[2]
+		}
+
+		return reference, true
+	}
+
+	args, has := strings.CutPrefix(value, "@Microsoft.KeyVault(")
+	if !has || !strings.HasSuffix(args, ")") {
+		return KeyVaultReference{}, false
+	}
+
+	reference := KeyVaultReference{}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, ")"), ";") {
+		name, argValue, _ := strings.Cut(arg, "=")
+		switch strings.TrimSpace(name) {
+		case "SecretUri":
+			secretUri, err := url.Parse(strings.TrimSpace(argValue))
+			if err != nil || secretUri.Scheme != "https" {
+				return KeyVaultReference{}, false
+			}
+
+			// the path of a secret URI is /secrets/<secret>[/<version>]
+			parts := strings.Split(strings.Trim(secretUri.Path, "/"), "/")
+			if len(parts) < 2 || len(parts) > 3 || parts[0] != "secrets" {
+				return KeyVaultReference{}, false
+			}
+
+			reference.Vault = fmt.Sprintf("https://%s", secretUri.Host)
+			reference.SecretName = parts[1]
+			if len(parts) == 3 {
+				reference.SecretVersion = parts[2]
+			}
+		case "VaultName":
+			reference.Vault = strings.TrimSpace(argValue)
+		case "SecretName":
+			reference.SecretName = strings.TrimSpace(argValue)
+		case "SecretVersion":
+			reference.SecretVersion = strings.TrimSpace(argValue)
+		}
+	}
+
+	if reference.Vault == "" || reference.SecretName == "" {
+		return KeyVaultReference{}, false
+	}
+
+	return reference, true
+}
+
+// String returns the reference in the keyvault://<vault>/<secret>[/<version>] form, or in the secret URI form when the
+// vault is a URL. It's the value stored in the environment for the secret.
+func (r KeyVaultReference) String() string {
+	path := r.SecretName
+	if r.SecretVersion != "" {
+		path += "/" + r.SecretVersion
+	}
+
+	if strings.HasPrefix(strings.ToLower(r.Vault), "https://") {
+		return fmt.Sprintf("@Microsoft.KeyVault(SecretUri=%s/secrets/%s)", strings.TrimSuffix(r.Vault, "/"), path)
+	}
+
+	return fmt.Sprintf("keyvault://%s/%s", r.Vault, path)
+}
+
+var invalidSecretNameChars = regexp.MustCompile(`[^0-9a-zA-Z-]`)
+
+// KeyVaultSecretName returns the name of the Key Vault secret that stores the value of the environment key, which
+// replaces the characters that aren't allowed in secret names, for example "DB_PASSWORD" is stored as "DB-PASSWORD".
+func KeyVaultSecretName(key string) string {
+	return invalidSecretNameChars.ReplaceAllString(key, "-")
+}
+
+// SecretResolver resolves Key Vault references to the values of the secrets they reference, and stores the values of
+// secrets in Key Vault.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, subscriptionId string, reference KeyVaultReference) (string, error)
+	// StoreSecret sets the value of the secret referenced by reference, creating a new version of the secret when it
+	// exists. The version of reference is ignored.
+	StoreSecret(ctx context.Context, subscriptionId string, reference KeyVaultReference, value string) error
+}
+
+type keyVaultSecretResolver struct {
+	azCli *lazy.Lazy[azcli.AzCli]
+	// cacheMu guards cache and stores, and isn't held while calling Key Vault, so that resolving one secret doesn't
+	// wait for another
+	cache   map[KeyVaultReference]string
+	cacheMu sync.Mutex
+	// the number of secrets stored, which invalidates the values resolved while a secret was stored
+	stores uint64
+}
+
+// NewKeyVaultSecretResolver creates a SecretResolver that reads and writes secrets in Key Vault with the credential of the
+// current user. The value of each secret is read once per process.
+func NewKeyVaultSecretResolver(azCli *lazy.Lazy[azcli.AzCli]) SecretResolver {
+	return &keyVaultSecretResolver{
+		azCli: azCli,
+		cache: map[KeyVaultReference]string{},
+	}
+}
+
+func (r *keyVaultSecretResolver) ResolveSecret(
+	ctx context.Context,
+	subscriptionId string,
+	reference KeyVaultReference,
+) (string, error) {
+	r.cacheMu.Lock()
+	value, has := r.cache[reference]
+	stores := r.stores
+	r.cacheMu.Unlock()
+
+	if has {
+		return value, nil
+	}
+
+	azCli, err := r.azCli.GetValue()
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := azCli.GetKeyVaultSecretVersion(
+		ctx, subscriptionId, reference.Vault, reference.SecretName, reference.SecretVersion)
+	if err != nil {
+		return "", fmt.Errorf(
+			"resolving secret '%s' from key vault '%s': %w", reference.SecretName, reference.Vault, err)
+	}
+
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+
+	// a secret stored while this one was read may have replaced the value that was read
+	if r.stores == stores {
+		r.cache[reference] = secret.Value
+	}
+
+	return secret.Value, nil
+}
+
+func (r *keyVaultSecretResolver) StoreSecret(
+	ctx context.Context,
+	subscriptionId string,
+	reference KeyVaultReference,
+	value string,
+) error {
+	azCli, err := r.azCli.GetValue()
+	if err != nil {
+		return err
+	}
+
+	_, err = azCli.SetKeyVaultSecret(ctx, subscriptionId, reference.Vault, reference.SecretName, value)
+	if err != nil {
+		return fmt.Errorf("storing secret '%s' in key vault '%s': %w", reference.SecretName, reference.Vault, err)
+	}
+
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+
+	// the latest version of the secret is now value
+	reference.SecretVersion = ""
+	r.cache[reference] = value
+	r.stores++
+	return nil
+}
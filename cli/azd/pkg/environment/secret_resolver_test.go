@@ -0,0 +1,171 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyVaultReference(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    KeyVaultReference
+		isReference bool
+	}{
+		{
+			value:       "keyvault://my-vault/api-key",
+			expected:    KeyVaultReference{Vault: "my-vault", SecretName: "api-key"},
+			isReference: true,
+		},
+		{
+			value:       "keyvault://my-vault/api-key/0123456789",
+			expected:    KeyVaultReference{Vault: "my-vault", SecretName: "api-key", SecretVersion: "0123456789"},
+			isReference: true,
+		},
+		{
+			value: "@Microsoft.KeyVault(SecretUri=https://my-vault.vault.azure.net/secrets/api-key/0123456789)",
+			expected: KeyVaultReference{
+				Vault:         "https://my-vault.vault.azure.net",
+				SecretName:    "api-key",
+				SecretVersion: "0123456789",
+			},
+			isReference: true,
+		},
+		{
+			value:       "@Microsoft.KeyVault(VaultName=my-vault;SecretName=api-key)",
+			expected:    KeyVaultReference{Vault: "my-vault", SecretName: "api-key"},
+			isReference: true,
+		},
+		{value: "https://my-vault.vault.azure.net/secrets/api-key"},
+		{value: "keyvault://my-vault"},
+		{value: "@Microsoft.KeyVault(VaultName=my-vault)"},
+		{value: "@Microsoft.KeyVault(SecretUri=https://my-vault.vault.azure.net/keys/api-key)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			reference, isReference := ParseKeyVaultReference(tt.value)
+			require.Equal(t, tt.isReference, isReference)
+			require.Equal(t, tt.expected, reference)
+		})
+	}
+}
+
+type fakeSecretResolver struct {
+	secrets map[string]string
+}
+
+func (r *fakeSecretResolver) ResolveSecret(
+	ctx context.Context,
+	subscriptionId string,
+	reference KeyVaultReference,
+) (string, error) {
+	return r.secrets[reference.SecretName], nil
+}
+
+func (r *fakeSecretResolver) StoreSecret(
+	ctx context.Context,
+	subscriptionId string,
+	reference KeyVaultReference,
+	value string,
+) error {
+	if r.secrets == nil {
+		r.secrets = map[string]string{}
+	}
+
+	r.secrets[reference.SecretName] = value
+	return nil
+}
+
+func TestKeyVaultReferenceString(t *testing.T) {
+	for _, value := range []string{
+		"keyvault://my-vault/api-key",
+		"keyvault://my-vault/api-key/0123456789",
+		"@Microsoft.KeyVault(SecretUri=https://my-vault.vault.azure.net/secrets/api-key)",
+	} {
+		reference, isReference := ParseKeyVaultReference(value)
+		require.True(t, isReference)
+		require.Equal(t, value, reference.String())
+	}
+}
+
+func TestKeyVaultSecretName(t *testing.T) {
+	require.Equal(t, "DB-PASSWORD", KeyVaultSecretName("DB_PASSWORD"))
+	require.Equal(t, "api-key-2", KeyVaultSecretName("api.key_2"))
+}
+
+func TestSaveSecret(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeSecretResolver{}
+	localDataStore := &MockDataStore{}
+	manager := &manager{local: localDataStore, secretResolver: resolver}
+
+	t.Run("Success", func(t *testing.T) {
+		env := NewWithValues("test", map[string]string{
+			SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		})
+		env.secretResolver = resolver
+		localDataStore.On("Save", ctx, env).Return(nil)
+
+		err := manager.SaveSecret(ctx, env, "API_KEY", KeyVaultReference{Vault: "my-vault", SecretName: "API-KEY"}, "s3cret")
+		require.NoError(t, err)
+		localDataStore.AssertCalled(t, "Save", ctx, env)
+
+		// only the reference is saved in the environment
+		require.Equal(t, "keyvault://my-vault/API-KEY", env.Getenv("API_KEY"))
+		require.Equal(t, []string{"API_KEY"}, env.SecretKeys())
+
+		value, err := env.GetenvResolved(ctx, "API_KEY")
+		require.NoError(t, err)
+		require.Equal(t, "s3cret", value)
+	})
+
+	t.Run("NoSubscription", func(t *testing.T) {
+		t.Setenv(SubscriptionIdEnvVarName, "")
+		env := New("test")
+		err := manager.SaveSecret(ctx, env, "API_KEY", KeyVaultReference{Vault: "my-vault", SecretName: "API-KEY"}, "s3cret")
+		require.ErrorContains(t, err, "doesn't have a subscription")
+		require.Empty(t, env.Getenv("API_KEY"))
+	})
+}
+
+func TestGetenvResolved(t *testing.T) {
+	env := NewWithValues("test", map[string]string{
+		"API_KEY":  "keyvault://my-vault/api-key",
+		"ENDPOINT": "https://api.contoso.com",
+	})
+
+	_, err := env.GetenvResolved(context.Background(), "API_KEY")
+	require.Error(t, err)
+
+	env.secretResolver = &fakeSecretResolver{secrets: map[string]string{"api-key": "s3cret"}}
+
+	value, err := env.GetenvResolved(context.Background(), "API_KEY")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", value)
+
+	value, err = env.GetenvResolved(context.Background(), "ENDPOINT")
+	require.NoError(t, err)
+	require.Equal(t, "https://api.contoso.com", value)
+
+	// the reference, not the secret, is returned when the value isn't consumed
+	require.Equal(t, "keyvault://my-vault/api-key", env.Getenv("API_KEY"))
+}
+
+func TestSaveSkipsPlaintextSecrets(t *testing.T) {
+	env := NewWithValues("test", map[string]string{
+		"API_KEY":  "keyvault://my-vault/api-key",
+		"PASSWORD": "plaintext",
+	})
+	require.NoError(t, env.SetSecret("API_KEY", true))
+	require.NoError(t, env.SetSecret("PASSWORD", true))
+
+	marshalled, err := marshallDotEnv(env)
+	require.NoError(t, err)
+	require.Equal(t, "API_KEY=\"keyvault://my-vault/api-key\"\nAZURE_ENV_NAME=\"test\"", marshalled)
+	require.Equal(t, "plaintext", env.Getenv("PASSWORD"))
+}
@@ -154,11 +154,9 @@ func (sbd *StorageBlobDataStore) Reload(ctx context.Context, env *Environment) e
 
 	envMap, err := godotenv.Parse(dotEnvBuffer)
 	if err != nil {
-		env.dotenv = make(map[string]string)
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(make(map[string]string))
 	} else {
-		env.dotenv = envMap
-		env.deletedKeys = make(map[string]struct{})
+		env.setDotenv(envMap)
 	}
 
 	// Reload config file
@@ -190,6 +188,36 @@ func (sbd *StorageBlobDataStore) Reload(ctx context.Context, env *Environment) e
 	return nil
 }
 
+// Rename uploads the blobs of the environment under the new name, and then deletes the blobs stored under the old name
+func (sbd *StorageBlobDataStore) Rename(ctx context.Context, oldName string, newName string) error {
+	env, err := sbd.Get(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := sbd.Get(ctx, newName); err == nil {
+		return fmt.Errorf("'%s' %w", newName, ErrExists)
+	} else if !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("checking for existing environment: %w", err)
+	}
+
+	oldEnv := &Environment{name: oldName}
+	env.name = newName
+	env.SetEnvName(newName)
+
+	if err := sbd.Save(ctx, env); err != nil {
+		return err
+	}
+
+	for _, blobPath := range []string{sbd.EnvPath(oldEnv), sbd.ConfigPath(oldEnv)} {
+		if err := sbd.blobClient.Delete(ctx, blobPath); err != nil {
+			return fmt.Errorf("deleting blob '%s': %w", blobPath, describeError(err))
+		}
+	}
+
+	return nil
+}
+
 func describeError(err error) error {
 	var responseErr *azcore.ResponseError
 
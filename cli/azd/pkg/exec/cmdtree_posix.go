@@ -35,5 +35,11 @@ func (o *CmdTree) Start() error {
 }
 
 func (o *CmdTree) Kill() {
+	// interactive commands aren't run in their own process group, so only the process itself can be killed
+	if o.Interactive {
+		_ = o.Cmd.Process.Kill()
+		return
+	}
+
 	_ = syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGKILL)
 }
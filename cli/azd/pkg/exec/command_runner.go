@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // Settings to modify the way CmdTree is executed
@@ -34,6 +35,14 @@ type RunnerOptions struct {
 	Stderr io.Writer
 	// Whether debug logging is enabled. False by default.
 	DebugLogging bool
+	// OutputTailSize is the number of bytes at the end of the combined stdout and stderr output of a command that are kept
+	// for the ExitError of the command, when the output is captured. DefaultOutputTailSize when 0. When negative, no tail
+	// is kept and the ExitError includes all of the output.
+	OutputTailSize int
+	// Timeout is the default duration after which commands are killed, along with the processes they started, and a
+	// *TimeoutError is returned. Commands don't time out when 0. Interactive commands, which may be waiting on the user,
+	// don't use it. RunArgs.Timeout overrides it for a command.
+	Timeout time.Duration
 }
 
 // Creates a new default instance of the CommandRunner.
@@ -47,10 +56,16 @@ func NewCommandRunner(opt *RunnerOptions) CommandRunner {
 	}
 
 	runner := &commandRunner{
-		stdin:        opt.Stdin,
-		stdout:       opt.Stdout,
-		stderr:       opt.Stderr,
-		debugLogging: opt.DebugLogging,
+		stdin:          opt.Stdin,
+		stdout:         opt.Stdout,
+		stderr:         opt.Stderr,
+		debugLogging:   opt.DebugLogging,
+		outputTailSize: opt.OutputTailSize,
+		timeout:        opt.Timeout,
+	}
+
+	if runner.outputTailSize == 0 {
+		runner.outputTailSize = DefaultOutputTailSize
 	}
 
 	if runner.stdin == nil {
@@ -76,6 +91,10 @@ type commandRunner struct {
 	stderr io.Writer
 	// Whether debugLogging logging is enabled
 	debugLogging bool
+	// The number of bytes of the output of a command that are kept for its ExitError
+	outputTailSize int
+	// The default duration after which commands are killed, none when 0
+	timeout time.Duration
 }
 
 // Run runs the command specified in 'args'.
@@ -85,6 +104,8 @@ type commandRunner struct {
 //     Instead, standard output/error is simply redirected to the os standard output/error.
 //   - If the underlying command exits unsuccessfully, *ExitError is returned. Other possible errors would likely be I/O
 //     errors or context cancellation.
+//   - If the command runs longer than its timeout, it is killed along with its child processes, and *TimeoutError is
+//     returned.
 //
 // NOTE: on Windows the command will automatically be run within a shell. This means .bat/.cmd
 // file based commands should just work.
@@ -108,6 +129,7 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 	}
 
 	var stdout, stderr bytes.Buffer
+	outputTail := newRingBuffer(r.outputTailSize)
 
 	cmd.Env = appendEnv(args.Env)
 
@@ -117,15 +139,17 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		cmd.Stderr = r.stderr
 	} else {
 		cmd.Stdin = stdin
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		cmd.Stdout = io.MultiWriter(&stdout, outputTail)
+		cmd.Stderr = io.MultiWriter(&stderr, outputTail)
 
+		// output streamed to the writers of args isn't kept in the result as well, since it can be large, such as the
+		// logs of builds. Only its tail is kept for errors.
 		if args.StdOut != nil {
-			cmd.Stdout = io.MultiWriter(args.StdOut, &stdout)
+			cmd.Stdout = io.MultiWriter(args.StdOut, outputTail)
 		}
 
 		if args.Stderr != nil {
-			cmd.Stderr = io.MultiWriter(args.Stderr, &stderr)
+			cmd.Stderr = io.MultiWriter(args.Stderr, outputTail)
 		}
 	}
 
@@ -147,11 +171,12 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		return RunResult{}, err
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
+	timeout := r.commandTimeout(args)
+	cmdCtx, cancel := withTimeout(ctx, timeout)
 	defer cancel()
 
 	go func() {
-		<-ctx.Done()
+		<-cmdCtx.Done()
 		cmd.Kill()
 	}()
 
@@ -175,15 +200,29 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 
 	logMsg.result = &result
 
+	if timedOut(ctx, cmdCtx) {
+		timeoutErr := &TimeoutError{Cmd: args.Cmd, Timeout: timeout}
+		if !args.Interactive {
+			timeoutErr.OutputTail, _ = outputTail.Tail()
+		}
+		logMsg.err = timeoutErr
+		return result, timeoutErr
+	}
+
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
 		outputAvailable := !args.Interactive
-		err = NewExitError(
+		exitError := newExitError(
 			*exitErr,
 			args.Cmd,
 			result.Stdout,
 			result.Stderr,
 			outputAvailable)
+		if outputAvailable {
+			exitError.OutputTail, exitError.outputTruncated = outputTail.Tail()
+			exitError.outputStreamed = args.StdOut != nil || args.Stderr != nil
+		}
+		err = exitError
 	}
 
 	return result, err
@@ -200,13 +239,14 @@ func (r *commandRunner) RunList(ctx context.Context, commands []string, args Run
 
 	var stdOutBuf bytes.Buffer
 	var stdErrBuf bytes.Buffer
+	outputTail := newRingBuffer(r.outputTailSize)
 
 	if process.Stdout == nil {
-		process.Stdout = &stdOutBuf
+		process.Stdout = io.MultiWriter(&stdOutBuf, outputTail)
 	}
 
 	if process.Stderr == nil {
-		process.Stderr = &stdErrBuf
+		process.Stderr = io.MultiWriter(&stdErrBuf, outputTail)
 	}
 
 	debugLogging := r.debugLogging
@@ -229,6 +269,15 @@ func (r *commandRunner) RunList(ctx context.Context, commands []string, args Run
 	}
 	defer process.Kill()
 
+	timeout := r.commandTimeout(args)
+	cmdCtx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+
+	go func() {
+		<-cmdCtx.Done()
+		process.Kill()
+	}()
+
 	err = process.Wait()
 	result := NewRunResult(
 		process.ProcessState.ExitCode(),
@@ -237,19 +286,73 @@ func (r *commandRunner) RunList(ctx context.Context, commands []string, args Run
 	)
 	logMsg.result = &result
 
+	if timedOut(ctx, cmdCtx) {
+		timeoutErr := &TimeoutError{Cmd: args.Cmd, Timeout: timeout}
+		timeoutErr.OutputTail, _ = outputTail.Tail()
+		logMsg.err = timeoutErr
+		return result, timeoutErr
+	}
+
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
-		err = NewExitError(
+		exitError := newExitError(
 			*exitErr,
 			args.Cmd,
 			result.Stdout,
 			result.Stderr,
 			true)
+		exitError.OutputTail, exitError.outputTruncated = outputTail.Tail()
+		err = exitError
 	}
 
 	return result, err
 }
 
+// commandTimeout returns the duration after which the command is killed, none when 0. Interactive commands only time
+// out when they set a timeout.
+func (r *commandRunner) commandTimeout(args RunArgs) time.Duration {
+	if args.Timeout != nil {
+		return *args.Timeout
+	}
+
+	if args.Interactive {
+		return 0
+	}
+
+	return r.timeout
+}
+
+// TimeoutFromEnv returns the timeout set by the environment variable as a duration like '30m', and whether it is set.
+// Invalid values are logged and ignored.
+func TimeoutFromEnv(name string) (time.Duration, bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, false
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		log.Printf("ignoring invalid value for %s: %s", name, value)
+		return 0, false
+	}
+
+	return timeout, true
+}
+
+// withTimeout returns a context that is done when ctx is done, or when the timeout elapses if it isn't 0.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// timedOut returns whether the command was killed because its timeout elapsed, and not because ctx is done.
+func timedOut(ctx context.Context, cmdCtx context.Context) bool {
+	return ctx.Err() == nil && errors.Is(cmdCtx.Err(), context.DeadlineExceeded)
+}
+
 func appendEnv(env []string) []string {
 	if len(env) > 0 {
 		return append(os.Environ(), env...)
@@ -263,7 +366,7 @@ type logBuilder struct {
 	args []string
 	env  []string
 
-	// Either result or err is expected to be set, but not both.
+	// Either result or err is expected to be set, or both when the command timed out.
 	result *RunResult
 	err    error
 }
@@ -273,7 +376,9 @@ func (l *logBuilder) Write(debug bool, sensitiveArgsData []string) {
 	msg := strings.Builder{}
 	insensitiveArgs := RedactSensitiveArgs(l.args, sensitiveArgsData)
 	msg.WriteString(fmt.Sprintf("Run exec: '%s' ", RedactSensitiveData(strings.Join(insensitiveArgs, " "))))
-	if l.result != nil {
+	if l.result != nil && l.err != nil {
+		msg.WriteString(fmt.Sprintf(", exit code: %d, err: %v\n", l.result.ExitCode, l.err))
+	} else if l.result != nil {
 		msg.WriteString(fmt.Sprintf(", exit code: %d\n", l.result.ExitCode))
 	} else if l.err != nil {
 		msg.WriteString(fmt.Sprintf(", err: %v\n", l.err))
@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands are sh scripts")
+	}
+
+	ctx := context.Background()
+	runner := NewCommandRunner(&RunnerOptions{Timeout: 200 * time.Millisecond})
+
+	t.Run("KillsProcessTree", func(t *testing.T) {
+		// the background child holds stdout open, so the run only completes once the child is killed too
+		start := time.Now()
+		_, err := runner.Run(ctx, NewRunArgs("sh", "-c", "(sleep 30; echo done) & echo started; wait"))
+		require.Less(t, time.Since(start), 10*time.Second)
+
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, "sh", timeoutErr.Cmd)
+		require.Equal(t, 200*time.Millisecond, timeoutErr.Timeout)
+		require.Equal(t, "started\n", timeoutErr.OutputTail)
+	})
+
+	t.Run("RunList", func(t *testing.T) {
+		_, err := runner.RunList(ctx, []string{"echo started", "sleep 30"}, RunArgs{})
+
+		var timeoutErr *TimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		require.Equal(t, "started\n", timeoutErr.OutputTail)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		res, err := runner.Run(ctx, NewRunArgs("sh", "-c", "sleep 0.5; echo done").WithTimeout(0))
+		require.NoError(t, err)
+		require.Equal(t, "done\n", res.Stdout)
+
+		_, err = NewCommandRunner(nil).Run(ctx, NewRunArgs("sleep", "30").WithTimeout(200*time.Millisecond))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Interactive", func(t *testing.T) {
+		// interactive commands may be waiting on the user, so they don't use the default timeout
+		_, err := runner.Run(ctx, NewRunArgs("sleep", "0.5").WithInteractive(true))
+		require.NoError(t, err)
+
+		_, err = runner.Run(ctx, NewRunArgs("sleep", "30").WithInteractive(true).WithTimeout(200*time.Millisecond))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		_, err := runner.Run(ctx, NewRunArgs("sleep", "30"))
+		require.Error(t, err)
+
+		var timeoutErr *TimeoutError
+		require.False(t, errors.As(err, &timeoutErr))
+	})
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	t.Setenv("AZD_TEST_TIMEOUT", "")
+	_, has := TimeoutFromEnv("AZD_TEST_TIMEOUT")
+	require.False(t, has)
+
+	t.Setenv("AZD_TEST_TIMEOUT", "90s")
+	timeout, has := TimeoutFromEnv("AZD_TEST_TIMEOUT")
+	require.True(t, has)
+	require.Equal(t, 90*time.Second, timeout)
+
+	t.Setenv("AZD_TEST_TIMEOUT", "0")
+	timeout, has = TimeoutFromEnv("AZD_TEST_TIMEOUT")
+	require.True(t, has)
+	require.Equal(t, time.Duration(0), timeout)
+
+	for _, value := range []string{"30", "-1m", "soon"} {
+		t.Setenv("AZD_TEST_TIMEOUT", value)
+		_, has = TimeoutFromEnv("AZD_TEST_TIMEOUT")
+		require.False(t, has, value)
+	}
+}
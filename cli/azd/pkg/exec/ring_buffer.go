@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import "sync"
+
+// DefaultOutputTailSize is the default number of bytes of the combined stdout and stderr output of a command that are kept
+// for the ExitError of the command, see RunnerOptions.OutputTailSize.
+const DefaultOutputTailSize = 16 * 1024
+
+// ringBuffer is an io.Writer that keeps the last size bytes written to it, so the end of the output of a command can be
+// reported without holding all of the output in memory. Writes are safe for concurrent use, since the stdout and stderr
+// of a command are written from different goroutines.
+type ringBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	size int
+	// the index in buf of the next byte to write, once buf is full
+	next int
+	// whether bytes were dropped because more than size bytes were written
+	truncated bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{
+		buf:  make([]byte, 0, max(size, 0)),
+		size: size,
+	}
+}
+
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	written := len(p)
+	if r.size <= 0 {
+		return written, nil
+	}
+
+	if len(p) > r.size {
+		p = p[len(p)-r.size:]
+		r.truncated = true
+	}
+
+	// fill the buffer before wrapping around it
+	if len(r.buf) < r.size {
+		n := min(r.size-len(r.buf), len(p))
+		r.buf = append(r.buf, p[:n]...)
+		p = p[n:]
+	}
+
+	for len(p) > 0 {
+		n := copy(r.buf[r.next:], p)
+		r.next = (r.next + n) % r.size
+		p = p[n:]
+		r.truncated = true
+	}
+
+	return written, nil
+}
+
+// Tail returns the bytes kept by the buffer, in the order they were written, and whether earlier bytes were dropped.
+func (r *ringBuffer) Tail() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.buf) < r.size {
+		return string(r.buf), r.truncated
+	}
+
+	return string(r.buf[r.next:]) + string(r.buf[:r.next]), r.truncated
+}
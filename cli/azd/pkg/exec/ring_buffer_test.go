@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		writes    []string
+		tail      string
+		truncated bool
+	}{
+		{name: "Empty", size: 4, writes: nil, tail: "", truncated: false},
+		{name: "Fits", size: 4, writes: []string{"ab", "cd"}, tail: "abcd", truncated: false},
+		{name: "Wraps", size: 4, writes: []string{"abc", "def"}, tail: "cdef", truncated: true},
+		{name: "WrapsTwice", size: 4, writes: []string{"abc", "de", "fgh", "i"}, tail: "fghi", truncated: true},
+		{name: "LargeWrite", size: 4, writes: []string{"a", "bcdefgh"}, tail: "efgh", truncated: true},
+		{name: "Disabled", size: -1, writes: []string{"abc"}, tail: "", truncated: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newRingBuffer(tt.size)
+			for _, write := range tt.writes {
+				n, err := buffer.Write([]byte(write))
+				require.NoError(t, err)
+				require.Equal(t, len(write), n)
+			}
+
+			tail, truncated := buffer.Tail()
+			require.Equal(t, tt.tail, tail)
+			require.Equal(t, tt.truncated, truncated)
+		})
+	}
+}
+
+func TestRunOutputTail(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command is a sh script")
+	}
+
+	script := `for i in 1 2 3 4 5 6 7 8 9; do echo "line $i"; done; echo "failed" >&2; exit 3`
+
+	t.Run("Truncated", func(t *testing.T) {
+		runner := NewCommandRunner(&RunnerOptions{OutputTailSize: 14})
+		// stderr is redirected to stdout, so the order of the output is deterministic
+		_, err := runner.Run(context.Background(), NewRunArgs("sh", "-c", "exec 2>&1; "+script))
+
+		var exitErr *ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.Equal(t, 3, exitErr.ExitCode)
+		require.True(t, exitErr.OutputTruncated())
+		require.Equal(t, "line 9\nfailed\n", exitErr.OutputTail)
+		require.Equal(t, "exit code: 3, last 14 bytes of output: line 9\nfailed\n", err.Error())
+	})
+
+	t.Run("Fits", func(t *testing.T) {
+		runner := NewCommandRunner(nil)
+		_, err := runner.Run(context.Background(), NewRunArgs("sh", "-c", script))
+
+		var exitErr *ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.False(t, exitErr.OutputTruncated())
+		require.True(t, strings.HasPrefix(exitErr.OutputTail, "line 1\n"))
+		require.Contains(t, exitErr.OutputTail, "failed\n")
+		require.Contains(t, err.Error(), "stderr: failed")
+	})
+
+	t.Run("Streamed", func(t *testing.T) {
+		runner := NewCommandRunner(nil)
+		stdout := &bytes.Buffer{}
+		res, err := runner.Run(context.Background(), NewRunArgs("sh", "-c", "exec 2>&1; "+script).WithStdOut(stdout))
+
+		// the streamed output is only kept in the error
+		require.Empty(t, res.Stdout)
+		require.True(t, strings.HasPrefix(stdout.String(), "line 1\n"))
+		require.Contains(t, err.Error(), "output: line 1\n")
+		require.Contains(t, err.Error(), "failed\n")
+	})
+}
@@ -2,6 +2,7 @@ package exec
 
 import (
 	"io"
+	"time"
 )
 
 // RunArgs exposes the command, arguments and other options when running console/shell commands
@@ -13,9 +14,8 @@ type RunArgs struct {
 	Cwd           string
 	Env           []string
 
-	// Stderr will receive a copy of the text written to Stderr by
-	// the command.
-	// NOTE: RunResult.Stderr will still contain stderr output.
+	// Stderr will receive the text written to Stderr by the command.
+	// NOTE: RunResult.Stderr is empty when set, the end of the output is available from ExitError.OutputTail.
 	Stderr io.Writer
 
 	// Enables debug logging.
@@ -31,7 +31,12 @@ type RunArgs struct {
 	StdIn io.Reader
 
 	// When set will call the command with the specified StdOut
+	// NOTE: RunResult.Stdout is empty when set, the end of the output is available from ExitError.OutputTail.
 	StdOut io.Writer
+
+	// When set overrides RunnerOptions.Timeout, the duration after which the command is killed, including when it is
+	// interactive. 0 disables the timeout.
+	Timeout *time.Duration
 }
 
 // NewRunArgs creates a new instance with the specified cmd and args
@@ -106,3 +111,10 @@ func (b RunArgs) WithStdErr(stdErr io.Writer) RunArgs {
 	b.Stderr = stdErr
 	return b
 }
+
+// Updates the duration after which the command is killed, overriding the default timeout of the runner. 0 disables the
+// timeout for the command.
+func (b RunArgs) WithTimeout(timeout time.Duration) RunArgs {
+	b.Timeout = &timeout
+	return b
+}
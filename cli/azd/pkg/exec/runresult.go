@@ -1,8 +1,10 @@
 package exec
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
+	"time"
 )
 
 // RunResult is the result of running a command.
@@ -29,11 +31,18 @@ type ExitError struct {
 	Cmd string
 	// The exit code of the command.
 	ExitCode int
+	// The end of the combined stdout and stderr output of the command, at most RunnerOptions.OutputTailSize bytes. Empty
+	// when the output isn't captured, such as for interactive commands.
+	OutputTail string
 
 	stdOut string
 	stdErr string
 
 	outputAvailable bool
+	// whether the output is larger than OutputTail, or was only streamed to the writers of the RunArgs, in which cases
+	// Error includes OutputTail instead of the output
+	outputTruncated bool
+	outputStreamed  bool
 
 	// The underlying exec.ExitError.
 	err exec.ExitError
@@ -45,6 +54,15 @@ func NewExitError(
 	stdOut string,
 	stdErr string,
 	outputAvailable bool) error {
+	return newExitError(exitErr, cmd, stdOut, stdErr, outputAvailable)
+}
+
+func newExitError(
+	exitErr exec.ExitError,
+	cmd string,
+	stdOut string,
+	stdErr string,
+	outputAvailable bool) *ExitError {
 	return &ExitError{
 		ExitCode:        exitErr.ExitCode(),
 		Cmd:             cmd,
@@ -56,6 +74,8 @@ func NewExitError(
 }
 
 // Error augments the underlying exec.ExitError's Error with the stdout and stderr output of the command, if available.
+// OutputTail is included instead when the output is larger than it, so errors of commands with lots of output stay
+// readable, or when the output was streamed to the writers of the RunArgs instead of being captured.
 func (e *ExitError) Error() string {
 	var errorPrefix string
 
@@ -71,5 +91,40 @@ func (e *ExitError) Error() string {
 		return errorPrefix
 	}
 
+	if e.outputTruncated {
+		return fmt.Sprintf("%s, last %d bytes of output: %s", errorPrefix, len(e.OutputTail), e.OutputTail)
+	}
+
+	if e.outputStreamed {
+		return fmt.Sprintf("%s, output: %s", errorPrefix, e.OutputTail)
+	}
+
 	return fmt.Sprintf("%s, stdout: %s, stderr: %s", errorPrefix, e.stdOut, e.stdErr)
 }
+
+// OutputTruncated returns whether the output of the command is larger than OutputTail, in which case Error only includes
+// OutputTail.
+func (e *ExitError) OutputTruncated() bool {
+	return e.outputTruncated
+}
+
+// TimeoutError is the error returned when a command runs longer than its timeout, and is killed along with the processes
+// it started.
+type TimeoutError struct {
+	// The path or name of the command being invoked.
+	Cmd string
+	// The duration after which the command was killed.
+	Timeout time.Duration
+	// The end of the combined stdout and stderr output of the command, at most RunnerOptions.OutputTailSize bytes. Empty
+	// when the output isn't captured, such as for interactive commands.
+	OutputTail string
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("'%s' was killed because it didn't complete within %s", e.Cmd, e.Timeout)
+}
+
+// Unwrap returns context.DeadlineExceeded, so timeouts can be checked with errors.Is.
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
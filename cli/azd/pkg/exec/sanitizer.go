@@ -49,6 +49,10 @@ func RedactSensitiveData(msg string) string {
 			regexp.MustCompile(`--from-literal=([^=]+)=(\S+)`),
 			"--from-literal=$1=" + cRedacted,
 		},
+		"authorization header": {
+			regexp.MustCompile(`(?i)(Authorization: ?\w+ )\S+`),
+			"$1" + cRedacted,
+		},
 		"combined-arg": {
 			regexp.MustCompile(`(.*)=(\S+)`),
 			"$1=" + cRedacted,
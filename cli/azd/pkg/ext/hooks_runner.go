@@ -30,6 +30,8 @@ type HooksRunner struct {
 
 // NewHooks creates a new instance of CommandHooks
 // When `cwd` is empty defaults to current shell working directory
+// When `envManager` is nil the environment isn't reloaded around the hooks, such as for hooks that run before
+// the environment is created
 func NewHooksRunner(
 	hooksManager *HooksManager,
 	commandRunner exec.CommandRunner,
@@ -92,7 +94,7 @@ func (h *HooksRunner) RunHooks(
 	}
 
 	for _, hookConfig := range hooks {
-		if err := h.envManager.Reload(ctx, h.env); err != nil {
+		if err := h.reloadEnv(ctx); err != nil {
 			return fmt.Errorf("reloading environment before running hook: %w", err)
 		}
 
@@ -101,7 +103,7 @@ func (h *HooksRunner) RunHooks(
 			return err
 		}
 
-		if err := h.envManager.Reload(ctx, h.env); err != nil {
+		if err := h.reloadEnv(ctx); err != nil {
 			return fmt.Errorf("reloading environment after running hook: %w", err)
 		}
 	}
@@ -109,6 +111,14 @@ func (h *HooksRunner) RunHooks(
 	return nil
 }
 
+func (h *HooksRunner) reloadEnv(ctx context.Context) error {
+	if h.envManager == nil {
+		return nil
+	}
+
+	return h.envManager.Reload(ctx, h.env)
+}
+
 // Gets the script to execute based on the hook configuration values
 // For inline scripts this will also create a temporary script file to execute
 func (h *HooksRunner) GetScript(hookConfig *HookConfig) (tools.Script, error) {
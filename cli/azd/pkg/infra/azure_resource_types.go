@@ -17,6 +17,7 @@ const (
 	AzureResourceTypeContainerApp            AzureResourceType = "Microsoft.App/containerApps"
 	AzureResourceTypeSpringApp               AzureResourceType = "Microsoft.AppPlatform/Spring"
 	AzureResourceTypeContainerAppEnvironment AzureResourceType = "Microsoft.App/managedEnvironments"
+	AzureResourceTypeContainerGroup          AzureResourceType = "Microsoft.ContainerInstance/containerGroups"
 	AzureResourceTypeDeployment              AzureResourceType = "Microsoft.Resources/deployments"
 	AzureResourceTypeKeyVault                AzureResourceType = "Microsoft.KeyVault/vaults"
 	AzureResourceTypeManagedHSM              AzureResourceType = "Microsoft.KeyVault/managedHSMs"
@@ -73,6 +74,8 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Container App"
 	case AzureResourceTypeContainerAppEnvironment:
 		return "Container Apps Environment"
+	case AzureResourceTypeContainerGroup:
+		return "Container Instances"
 	case AzureResourceTypeServiceBusNamespace:
 		return "Service Bus Namespace"
 	case AzureResourceTypeServicePlan:
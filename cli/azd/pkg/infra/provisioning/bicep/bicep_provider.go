@@ -47,8 +47,8 @@ import (
 )
 
 var Defaults = Options{
-	Module: "main",
-	Path:   "infra",
+	Module: DefaultModule,
+	Path:   DefaultPath,
 }
 
 type deploymentDetails struct {
@@ -343,6 +343,18 @@ func (p *BicepProvider) plan(ctx context.Context) (*deploymentDetails, error) {
 	p.console.ShowSpinner(ctx, "Creating a deployment plan", input.Step)
 
 	modulePath := p.modulePath()
+	if _, err := os.Stat(modulePath); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf(
+			"module '%s' not found, expected a %s or %s file in %s",
+			p.options.Module,
+			p.options.Module+bicepFileExtension,
+			p.options.Module+bicepparamFileExtension,
+			filepath.Dir(modulePath),
+		)
+	} else if err != nil {
+		return nil, fmt.Errorf("checking module '%s': %w", p.options.Module, err)
+	}
+
 	// TODO: Report progress, "Compiling Bicep template"
 	compileResult, err := p.compileBicep(ctx, modulePath)
 	if err != nil {
@@ -615,7 +627,7 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 	}()
 
 	// Start the deployment
-	p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
+	p.console.ShowSpinner(ctx, "Creating/Updating resources", input.StepElapsed)
 
 	deploymentTags := map[string]*string{
 		azure.TagKeyAzdEnvName: to.Ptr(p.env.GetEnvName()),
@@ -688,15 +700,22 @@ func (p *BicepProvider) Preview(ctx context.Context) (*DeployPreviewResult, erro
 
 	var changes []*DeploymentPreviewChange
 	for _, change := range deployPreviewResult.Properties.Changes {
-		resourceAfter := change.After.(map[string]interface{})
+		// resources that are deleted have no state after the deployment
+		resourceState, has := change.After.(map[string]interface{})
+		if !has {
+			resourceState, _ = change.Before.(map[string]interface{})
+		}
+
+		resourceType, _ := resourceState["type"].(string)
+		resourceName, _ := resourceState["name"].(string)
 
 		changes = append(changes, &DeploymentPreviewChange{
 			ChangeType: ChangeType(*change.ChangeType),
 			ResourceId: Resource{
 				Id: *change.ResourceID,
 			},
-			ResourceType: resourceAfter["type"].(string),
-			Name:         resourceAfter["name"].(string),
+			ResourceType: resourceType,
+			Name:         resourceName,
 		})
 	}
 
@@ -1598,7 +1617,10 @@ func (p *BicepProvider) createOutputParameters(
 // loadParameters reads the parameters file template for environment/module specified by Options,
 // doing environment and command substitutions, and returns the values.
 func (p *BicepProvider) loadParameters(ctx context.Context) (map[string]azure.ArmParameterValue, error) {
-	parametersFilename := fmt.Sprintf("%s.parameters.json", p.options.Module)
+	parametersFilename := p.options.Parameters
+	if parametersFilename == "" {
+		parametersFilename = fmt.Sprintf("%s.parameters.json", p.options.Module)
+	}
 	parametersRoot := p.options.Path
 
 	if !filepath.IsAbs(parametersRoot) {
@@ -1608,7 +1630,7 @@ func (p *BicepProvider) loadParameters(ctx context.Context) (map[string]azure.Ar
 	paramFilePath := filepath.Join(parametersRoot, parametersFilename)
 	parametersBytes, err := os.ReadFile(paramFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("reading parameters.json: %w", err)
+		return nil, fmt.Errorf("reading %s: %w", parametersFilename, err)
 	}
 
 	principalId, err := p.curPrincipal.CurrentPrincipalId(ctx)
@@ -62,6 +62,18 @@ func TestBicepPlan(t *testing.T) {
 	)
 }
 
+func TestBicepPlanModuleNotFound(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareBicepMocks(mockContext)
+	infraProvider := createBicepProvider(t, mockContext)
+	infraProvider.options.Module = "missing"
+
+	deploymentPlan, err := infraProvider.plan(*mockContext.Context)
+
+	require.Nil(t, deploymentPlan)
+	require.ErrorContains(t, err, "module 'missing' not found, expected a missing.bicep or missing.bicepparam file")
+}
+
 const paramsArmJson = `{
 	"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
 	"contentVersion": "1.0.0.0",
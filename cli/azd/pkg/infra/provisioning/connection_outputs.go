@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// connectionOutputSuffixes are the suffixes of the names of the outputs that hold the secrets needed to connect to the
+// provisioned resources, for example AZURE_COSMOS_MONGODB_CONNECTION_STRING.
+var connectionOutputSuffixes = []string{"_CONNECTION_STRING", "_PASSWORD"}
+
+// connectionOutputs returns the sorted names of the connection outputs, split between the outputs whose values are Key
+// Vault references and the outputs whose values are plaintext.
+func connectionOutputs(outputs map[string]OutputParameter) (references []string, plaintext []string) {
+	for key, param := range outputs {
+		if !isConnectionOutput(key) {
+			continue
+		}
+
+		value, ok := param.Value.(string)
+		if !ok {
+			continue
+		}
+
+		if _, isReference := environment.ParseKeyVaultReference(value); isReference {
+			references = append(references, key)
+		} else if value != "" {
+			plaintext = append(plaintext, key)
+		}
+	}
+
+	slices.Sort(references)
+	slices.Sort(plaintext)
+	return references, plaintext
+}
+
+func isConnectionOutput(key string) bool {
+	upper := strings.ToUpper(key)
+	return slices.ContainsFunc(connectionOutputSuffixes, func(suffix string) bool {
+		return strings.HasSuffix(upper, suffix)
+	})
+}
+
+// storeConnectionSecrets stores the plaintext values of the connection outputs in the Key Vault of the environment, named
+// by AZURE_KEY_VAULT_NAME, and replaces them in the environment with references to the secrets. Values that can't be
+// stored stay in the environment, marked as secrets.
+func (m *Manager) storeConnectionSecrets(ctx context.Context, env *environment.Environment, plaintext []string) {
+	vault := env.Getenv(environment.KeyVaultNameEnvVarName)
+	if vault == "" || len(plaintext) == 0 {
+		return
+	}
+
+	// all the values are replaced before any secret is stored, since storing a secret saves the environment
+	values := map[string]string{}
+	references := map[string]environment.KeyVaultReference{}
+	for _, key := range plaintext {
+		values[key] = env.Getenv(key)
+		references[key] = environment.KeyVaultReference{Vault: vault, SecretName: environment.KeyVaultSecretName(key)}
+		env.DotenvSet(key, references[key].String())
+	}
+
+	for _, key := range plaintext {
+		if err := m.envManager.SaveSecret(ctx, env, key, references[key], values[key]); err != nil {
+			log.Printf("storing output %s in key vault %s, saving it in the environment: %v", key, vault, err)
+			env.DotenvSet(key, values[key])
+		}
+	}
+}
+
+// displayConnectionOutputs lists the environment values that reference the secrets of the connection outputs, so users
+// know where to find them. The values of secrets are never displayed.
+func (m *Manager) displayConnectionOutputs(ctx context.Context, outputs map[string]OutputParameter) {
+	// the plaintext outputs that were stored in Key Vault are references in the environment
+	current := make(map[string]OutputParameter, len(outputs))
+	for key, param := range outputs {
+		if _, ok := param.Value.(string); ok {
+			param.Value = m.env.Getenv(key)
+		}
+		current[key] = param
+	}
+
+	references, plaintext := connectionOutputs(current)
+
+	if len(references) > 0 {
+		m.console.Message(ctx, "\nThe connection secrets of your resources are stored in Key Vault, and referenced by:")
+		for _, key := range references {
+			reference, _ := environment.ParseKeyVaultReference(current[key].Value.(string))
+			m.console.Message(ctx, fmt.Sprintf("  %s: %s", key, output.WithGrayFormat(reference.String())))
+		}
+		m.console.Message(ctx, fmt.Sprintf(
+			"The references are resolved when your services are deployed. View the secrets in the Azure Portal, or "+
+				"replace them with %s.\n",
+			output.WithHighLightFormat("azd env set-secret <key>")))
+	}
+
+	for _, key := range plaintext {
+		m.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The output %s is saved in plaintext in the environment, marked as a secret. To store it in Key "+
+				"Vault instead, output %s with the name of a Key Vault, or output a reference to a secret, such as "+
+				"'keyvault://<vault>/<secret>'.",
+			key,
+			environment.KeyVaultNameEnvVarName))
+	}
+}
@@ -6,7 +6,12 @@ package provisioning
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -14,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"golang.org/x/exp/maps"
 )
 
 type DefaultProviderResolver func() (ProviderKind, error)
@@ -35,11 +41,15 @@ func (m *Manager) Initialize(ctx context.Context, projectPath string, options Op
 	m.projectPath = projectPath
 	m.options = &options
 
-	provider, err := m.newProvider(ctx)
+	provider, providerKind, err := m.newProvider(ctx)
 	if err != nil {
 		return fmt.Errorf("initializing infrastructure provider: %w", err)
 	}
 
+	if err := m.resolveParameters(providerKind); err != nil {
+		return err
+	}
+
 	m.provider = provider
 	return m.provider.Initialize(ctx, projectPath, options)
 }
@@ -75,6 +85,8 @@ func (m *Manager) Deploy(ctx context.Context) (*DeployResult, error) {
 	// make sure any spinner is stopped
 	m.console.StopSpinner(ctx, "", input.StepDone)
 
+	m.displayConnectionOutputs(ctx, deployResult.Deployment.Outputs)
+
 	return deployResult, nil
 }
 
@@ -97,11 +109,13 @@ func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 
 	for index, result := range deployResult.Preview.Properties.Changes {
 		mappingName := infra.GetResourceTypeDisplayName(infra.AzureResourceType(result.ResourceType))
-		if mappingName == "" {
-			// ignore
+		if mappingName != "" {
+			deployResult.Preview.Properties.Changes[index].ResourceType = mappingName
+		} else if strings.Contains(result.ResourceType, "/") {
+			// ignore the Azure resource types without a display name. The resource types of other providers, like the
+			// types of terraform resources, are displayed as is.
 			continue
 		}
-		deployResult.Preview.Properties.Changes[index].ResourceType = mappingName
 		filteredResult.Preview.Properties.Changes = append(
 			filteredResult.Preview.Properties.Changes, deployResult.Preview.Properties.Changes[index])
 	}
@@ -152,6 +166,21 @@ func (m *Manager) UpdateEnvironment(
 			}
 		}
 
+		if err := env.AddOutputKeys(maps.Keys(outputs)...); err != nil {
+			return fmt.Errorf("recording output keys: %w", err)
+		}
+
+		// connection outputs are secrets, so they're masked when they're displayed. Plaintext values are stored in the
+		// Key Vault of the environment, when it has one, so that only references to them are saved
+		references, plaintext := connectionOutputs(outputs)
+		for _, key := range append(references, plaintext...) {
+			if err := env.SetSecret(key, true); err != nil {
+				return fmt.Errorf("marking secret: %w", err)
+			}
+		}
+
+		m.storeConnectionSecrets(ctx, env, plaintext)
+
 		if err := m.envManager.Save(ctx, env); err != nil {
 			return fmt.Errorf("writing environment: %w", err)
 		}
@@ -222,16 +251,58 @@ func NewManager(
 	}
 }
 
-func (m *Manager) newProvider(ctx context.Context) (Provider, error) {
+// parametersFileSuffixes are the suffixes of the parameters files of the modules of each provider, for example
+// '.parameters.json' for 'main.parameters.json'
+var parametersFileSuffixes = map[ProviderKind]string{
+	Bicep:     ".parameters.json",
+	Terraform: ".tfvars.json",
+}
+
+// resolveParameters selects the parameters file of the environment, '<module>.<environment name><suffix>', when the
+// parameters file isn't configured and the file exists. Otherwise the provider falls back to '<module><suffix>'.
+func (m *Manager) resolveParameters(providerKind ProviderKind) error {
+	suffix, has := parametersFileSuffixes[providerKind]
+	if !has || m.options.Parameters != "" {
+		return nil
+	}
+
+	infraRoot := m.options.Path
+	if infraRoot == "" {
+		infraRoot = DefaultPath
+	}
+
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(m.projectPath, infraRoot)
+	}
+
+	module := m.options.Module
+	if module == "" {
+		module = DefaultModule
+	}
+
+	envParameters := fmt.Sprintf("%s.%s%s", module, m.env.GetEnvName(), suffix)
+	if _, err := os.Stat(filepath.Join(infraRoot, envParameters)); errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("checking parameters file '%s': %w", envParameters, err)
+	}
+
+	log.Printf("using parameters file '%s' of environment '%s'", envParameters, m.env.GetEnvName())
+	m.options.Parameters = envParameters
+	return nil
+}
+
+func (m *Manager) newProvider(ctx context.Context) (Provider, ProviderKind, error) {
 	var err error
 	m.options.Provider, err = ParseProvider(m.options.Provider)
 	if err != nil {
-		return nil, err
+		return nil, NotSpecified, err
 	}
 
 	if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(m.options.Provider)); isAlphaFeature {
 		if !m.alphaFeatureManager.IsEnabled(alphaFeatureId) {
-			return nil, fmt.Errorf("provider '%s' is alpha feature and it is not enabled. Run `%s` to enable it.",
+			return nil, NotSpecified, fmt.Errorf(
+				"provider '%s' is alpha feature and it is not enabled. Run `%s` to enable it.",
 				m.options.Provider,
 				alpha.GetEnableCommand(alphaFeatureId),
 			)
@@ -244,7 +315,7 @@ func (m *Manager) newProvider(ctx context.Context) (Provider, error) {
 	if providerKey == NotSpecified {
 		defaultProvider, err := m.defaultProvider()
 		if err != nil {
-			return nil, err
+			return nil, NotSpecified, err
 		}
 
 		providerKey = defaultProvider
@@ -253,8 +324,8 @@ func (m *Manager) newProvider(ctx context.Context) (Provider, error) {
 	var provider Provider
 	err = m.serviceLocator.ResolveNamed(string(providerKey), &provider)
 	if err != nil {
-		return nil, fmt.Errorf("failed resolving IaC provider '%s': %w", providerKey, err)
+		return nil, NotSpecified, fmt.Errorf("failed resolving IaC provider '%s': %w", providerKey, err)
 	}
 
-	return provider, nil
+	return provider, providerKey, nil
 }
@@ -5,6 +5,9 @@ package provisioning_test
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -14,6 +17,7 @@ import (
 	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/test"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -21,6 +25,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -140,6 +145,83 @@ func TestManagerDeploy(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestManagerUpdateEnvironmentConnectionOutputs(t *testing.T) {
+	env := environment.NewWithValues("test-env", nil)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mgr := NewManager(
+		mockContext.Container,
+		defaultProvider,
+		envManager,
+		env,
+		mockContext.Console,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	err := mgr.UpdateEnvironment(*mockContext.Context, env, map[string]OutputParameter{
+		"AZURE_SQL_CONNECTION_STRING": {Type: ParameterTypeString, Value: "keyvault://kv-dev/sqlConnectionString"},
+		"AZURE_POSTGRES_PASSWORD":     {Type: ParameterTypeString, Value: "keyvault://kv-dev/databasePassword"},
+		"AZURE_POSTGRES_HOST":         {Type: ParameterTypeString, Value: "psql-dev.postgres.database.azure.com"},
+		"REDIS_CONNECTION_STRING":     {Type: ParameterTypeString, Value: "rd-dev:6380,password=PASSWORD"},
+		"AZURE_KEY_VAULT_REFERENCE":   {Type: ParameterTypeString, Value: "keyvault://kv-dev/other"},
+	})
+	require.NoError(t, err)
+
+	// all the connection outputs are secrets. Without a Key Vault, plaintext values stay in the environment
+	require.Equal(t, []string{
+		"AZURE_POSTGRES_PASSWORD", "AZURE_SQL_CONNECTION_STRING", "REDIS_CONNECTION_STRING"}, env.SecretKeys())
+	require.Equal(t, "keyvault://kv-dev/sqlConnectionString", env.Getenv("AZURE_SQL_CONNECTION_STRING"))
+	require.Equal(t, "rd-dev:6380,password=PASSWORD", env.Getenv("REDIS_CONNECTION_STRING"))
+	envManager.AssertNotCalled(t, "SaveSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestManagerUpdateEnvironmentPlaintextConnectionOutputs(t *testing.T) {
+	env := environment.NewWithValues("test-env", nil)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	redisReference := environment.KeyVaultReference{
+		Vault: "kv-dev", SecretName: environment.KeyVaultSecretName("REDIS_CONNECTION_STRING")}
+	passwordReference := environment.KeyVaultReference{
+		Vault: "kv-dev", SecretName: environment.KeyVaultSecretName("AZURE_POSTGRES_PASSWORD")}
+	envManager.On("SaveSecret", *mockContext.Context, env, "AZURE_POSTGRES_PASSWORD", passwordReference, "PASSWORD").
+		Run(func(args mock.Arguments) {
+			// the other plaintext value isn't saved along with the secret
+			require.Equal(t, redisReference.String(), env.Getenv("REDIS_CONNECTION_STRING"))
+		}).
+		Return(errors.New("forbidden"))
+	envManager.On("SaveSecret", *mockContext.Context, env, "REDIS_CONNECTION_STRING", redisReference,
+		"rd-dev:6380,password=PASSWORD").
+		Return(nil)
+
+	mgr := NewManager(
+		mockContext.Container,
+		defaultProvider,
+		envManager,
+		env,
+		mockContext.Console,
+		mockContext.AlphaFeaturesManager,
+	)
+
+	err := mgr.UpdateEnvironment(*mockContext.Context, env, map[string]OutputParameter{
+		"AZURE_KEY_VAULT_NAME":    {Type: ParameterTypeString, Value: "kv-dev"},
+		"REDIS_CONNECTION_STRING": {Type: ParameterTypeString, Value: "rd-dev:6380,password=PASSWORD"},
+		"AZURE_POSTGRES_PASSWORD": {Type: ParameterTypeString, Value: "PASSWORD"},
+	})
+	require.NoError(t, err)
+
+	// plaintext values are stored in the Key Vault of the environment, values that can't be stored stay plaintext
+	require.Equal(t, []string{"AZURE_POSTGRES_PASSWORD", "REDIS_CONNECTION_STRING"}, env.SecretKeys())
+	require.Equal(t, redisReference.String(), env.Getenv("REDIS_CONNECTION_STRING"))
+	require.Equal(t, "PASSWORD", env.Getenv("AZURE_POSTGRES_PASSWORD"))
+	envManager.AssertNumberOfCalls(t, "SaveSecret", 2)
+}
+
 func TestManagerDestroyWithPositiveConfirmation(t *testing.T) {
 	env := environment.NewWithValues("test-env", map[string]string{
 		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
@@ -251,3 +333,65 @@ func registerContainerDependencies(mockContext *mocks.MockContext, env *environm
 func defaultProvider() (ProviderKind, error) {
 	return Bicep, nil
 }
+
+func TestManagerInitializeParameters(t *testing.T) {
+	projectDir := t.TempDir()
+	infraDir := filepath.Join(projectDir, "infra")
+	require.NoError(t, os.MkdirAll(infraDir, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "main.parameters.json"), []byte("{}"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "main.prod.parameters.json"), []byte("{}"), osutil.PermissionFile))
+
+	tests := []struct {
+		name     string
+		envName  string
+		options  Options
+		expected string
+	}{
+		{"EnvironmentParameters", "prod", Options{Provider: Bicep}, "main.prod.parameters.json"},
+		{"DefaultParameters", "dev", Options{Provider: Bicep}, ""},
+		{"DefaultProvider", "prod", Options{}, "main.prod.parameters.json"},
+		{"Module", "prod", Options{Provider: Bicep, Module: "app"}, ""},
+		{
+			"ConfiguredParameters",
+			"prod",
+			Options{Provider: Bicep, Parameters: "main.parameters.json"},
+			"main.parameters.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := environment.NewWithValues(tt.envName, nil)
+			mockContext := mocks.NewMockContext(context.Background())
+			registerContainerDependencies(mockContext, env)
+
+			provider := &optionsProvider{}
+			_ = mockContext.Container.RegisterNamedSingleton(string(Bicep), func() Provider {
+				return provider
+			})
+
+			mgr := NewManager(
+				mockContext.Container,
+				defaultProvider,
+				&mockenv.MockEnvManager{},
+				env,
+				mockContext.Console,
+				mockContext.AlphaFeaturesManager,
+			)
+			err := mgr.Initialize(*mockContext.Context, projectDir, tt.options)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, provider.options.Parameters)
+		})
+	}
+}
+
+// optionsProvider is a provider that records the options it is initialized with
+type optionsProvider struct {
+	test.TestProvider
+	options Options
+}
+
+func (p *optionsProvider) Initialize(ctx context.Context, projectPath string, options Options) error {
+	p.options = options
+	return nil
+}
@@ -18,10 +18,21 @@ const (
 	Test         ProviderKind = "test"
 )
 
+const (
+	// DefaultPath is the folder of the infrastructure when Options.Path is not set
+	DefaultPath = "infra"
+	// DefaultModule is the module provisioned when Options.Module is not set
+	DefaultModule = "main"
+)
+
 type Options struct {
 	Provider ProviderKind `yaml:"provider,omitempty"`
 	Path     string       `yaml:"path,omitempty"`
 	Module   string       `yaml:"module,omitempty"`
+	// The parameters file of the module, relative to Path. When not set, the parameters file of the environment,
+	// for example 'main.<environment name>.parameters.json', is used when it exists, otherwise the parameters file of
+	// the module, for example 'main.parameters.json'.
+	Parameters string `yaml:"parameters,omitempty"`
 	// Not expected to be defined at azure.yaml
 	IgnoreDeploymentState bool `yaml:"-"`
 }
@@ -5,6 +5,7 @@ package provisioning
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 )
@@ -50,9 +51,33 @@ func NewEnvRefreshResultFromState(state *State) contracts.EnvRefreshResult {
 	return result
 }
 
-// Parses the specified IaC Provider to ensure whether it is valid or not
+// NewProvisionPreviewResult creates a ProvisionPreviewResult from the changes of a deployment preview.
+func NewProvisionPreviewResult(previewResult *DeployPreviewResult) contracts.ProvisionPreviewResult {
+	result := contracts.ProvisionPreviewResult{
+		Changes: []contracts.ProvisionPreviewChange{},
+	}
+
+	if previewResult.Preview == nil || previewResult.Preview.Properties == nil {
+		return result
+	}
+
+	for _, change := range previewResult.Preview.Properties.Changes {
+		result.Changes = append(result.Changes, contracts.ProvisionPreviewChange{
+			ChangeType:   string(change.ChangeType),
+			ResourceId:   change.ResourceId.Id,
+			ResourceType: change.ResourceType,
+			Name:         change.Name,
+		})
+	}
+
+	return result
+}
+
+// Parses the specified IaC Provider to ensure whether it is valid or not. Provider names are case insensitive.
 // Defaults to `Bicep` if no provider is specified
 func ParseProvider(kind ProviderKind) (ProviderKind, error) {
+	kind = ProviderKind(strings.ToLower(strings.TrimSpace(string(kind))))
+
 	switch kind {
 	// For the time being we need to include `Test` here for the unit tests to work as expected
 	// App builds will pass this test but fail resolving the provider since `Test` won't be registered in the container
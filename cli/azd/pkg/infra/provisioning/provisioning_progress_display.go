@@ -22,6 +22,9 @@ const succeededProvisioningState string = "Succeeded"
 const runningProvisioningState string = "Running"
 const failedProvisioningState string = "Failed"
 
+// The progress is listed under the step of the spinner, with the same indentation
+const progressIndentation = "  "
+
 // ProvisioningProgressDisplay displays interactive progress for an ongoing Azure provisioning operation.
 type ProvisioningProgressDisplay struct {
 	// Whether the deployment has started
@@ -62,7 +65,7 @@ func (display *ProvisioningProgressDisplay) ReportProgress(
 		deploymentUrl := fmt.Sprintf(output.WithLinkFormat("%s\n"), display.target.PortalUrl())
 
 		display.console.EnsureBlankLine(ctx)
-		display.console.MessageUxItem(
+		display.logUxItem(
 			ctx,
 			&ux.MultilineMessage{
 				Lines: []string{
@@ -137,7 +140,7 @@ func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 		// Don't log resource types for Azure resources that we do not have a translation of the resource type for.
 		// This will be improved on in a future iteration.
 		if resourceTypeDisplayName != "" {
-			display.console.MessageUxItem(
+			display.logUxItem(
 				ctx,
 				&ux.DisplayedResource{
 					Type:  resourceTypeDisplayName,
@@ -188,8 +191,19 @@ func (display *ProvisioningProgressDisplay) logNewlyCreatedResources(
 
 	if len(inProgress) > 0 {
 		display.console.ShowSpinner(ctx,
-			fmt.Sprintf("Creating/Updating resources (%s)", strings.Join(inProgress, ", ")), input.Step)
+			fmt.Sprintf("Creating/Updating resources (%s)", strings.Join(inProgress, ", ")), input.StepElapsed)
 	} else {
-		display.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
+		display.console.ShowSpinner(ctx, "Creating/Updating resources", input.StepElapsed)
+	}
+}
+
+// logUxItem writes the item for the progress. Progress is reported from a background goroutine while the deployment
+// updates the spinner, so the item is logged without stopping and restarting the spinner.
+func (display *ProvisioningProgressDisplay) logUxItem(ctx context.Context, item ux.UxItem) {
+	if !display.console.IsUnformatted() {
+		display.console.MessageUxItem(ctx, item)
+		return
 	}
+
+	display.console.SafeLog(item.ToString(progressIndentation))
 }
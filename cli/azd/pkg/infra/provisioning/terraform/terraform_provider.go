@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"dario.cat/mergo"
@@ -25,8 +26,8 @@ import (
 )
 
 var Defaults = Options{
-	Module: "main",
-	Path:   "infra",
+	Module: DefaultModule,
+	Path:   DefaultPath,
 }
 
 // TerraformProvider exposes infrastructure provisioning using Azure Terraform templates
@@ -218,22 +219,85 @@ func (t *TerraformProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 	}, nil
 }
 
+// Previews the changes to the infrastructure through terraform plan, without applying the plan
 func (t *TerraformProvider) Preview(ctx context.Context) (*DeployPreviewResult, error) {
-	// terraform uses plan() to display the what-if output
-	// no changes are added to the properties
-	_, _, err := t.plan(ctx)
+	_, deploymentDetails, err := t.plan(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	runResult, err := t.cli.Show(ctx, t.modulePath(), deploymentDetails.PlanFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("showing plan failed: %s, err:%w", runResult, err)
+	}
+
+	var planOutput terraformPlanOutput
+	if err := json.Unmarshal([]byte(runResult), &planOutput); err != nil {
+		return nil, fmt.Errorf("unmarshalling plan: %w", err)
+	}
+
+	changes := []*DeploymentPreviewChange{}
+	for _, resourceChange := range planOutput.ResourceChanges {
+		// data sources are read during the plan and never change any resource
+		if resourceChange.Mode != terraformModeManaged {
+			continue
+		}
+
+		change := &DeploymentPreviewChange{
+			ChangeType:   planChangeType(resourceChange.Change.Actions),
+			ResourceType: resourceChange.Type,
+			Name:         resourceChange.Address,
+			Before:       resourceChange.Change.Before,
+			After:        resourceChange.Change.After,
+		}
+
+		if name, has := changedValue(resourceChange.Change, "name"); has {
+			change.Name = name
+		}
+
+		if id, has := resourceChange.Change.Before["id"].(string); has {
+			change.ResourceId = Resource{Id: id}
+		}
+
+		changes = append(changes, change)
+	}
+
 	return &DeployPreviewResult{
 		Preview: &DeploymentPreview{
-			Status:     "done",
-			Properties: &DeploymentPreviewProperties{},
+			Status: "done",
+			Properties: &DeploymentPreviewProperties{
+				Changes: changes,
+			},
 		},
 	}, nil
 }
 
+// planChangeType returns the change type for the actions of a resource change of a terraform plan
+func planChangeType(actions []string) ChangeType {
+	switch {
+	case slices.Equal(actions, []string{"create"}):
+		return ChangeTypeCreate
+	case slices.Equal(actions, []string{"delete"}):
+		return ChangeTypeDelete
+	case slices.Equal(actions, []string{"no-op"}), slices.Equal(actions, []string{"read"}):
+		return ChangeTypeNoChange
+	default:
+		// updates, and replacements, which delete and create the resource in either order
+		return ChangeTypeModify
+	}
+}
+
+// changedValue returns the string value of key of a resource after the change, or before the change when the resource
+// is deleted
+func changedValue(change terraformChange, key string) (string, bool) {
+	if value, has := change.After[key].(string); has && value != "" {
+		return value, true
+	}
+
+	value, has := change.Before[key].(string)
+	return value, has && value != ""
+}
+
 // Destroys the specified deployment through terraform destroy
 func (t *TerraformProvider) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
 	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
@@ -586,7 +650,10 @@ func (t *TerraformProvider) parametersTemplateFilePath() string {
 		infraPath = "infra"
 	}
 
-	parametersFilename := fmt.Sprintf("%s.tfvars.json", t.options.Module)
+	parametersFilename := t.options.Parameters
+	if parametersFilename == "" {
+		parametersFilename = fmt.Sprintf("%s.tfvars.json", t.options.Module)
+	}
 	return filepath.Join(t.projectPath, infraPath, parametersFilename)
 }
 
@@ -717,6 +784,33 @@ type terraformShowOutput struct {
 	Values        terraformValues `json:"values"`
 }
 
+// terraformPlanOutput is a model type for the output of `terraform show` for a plan file.
+// see https://www.terraform.io/internals/json-format#plan-representation for more information on the shape
+// of the JSON data
+type terraformPlanOutput struct {
+	FormatVersion   string                    `json:"format_version"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+}
+
+// terraformResourceChange is the model type for a change of a resource in a terraform plan.
+type terraformResourceChange struct {
+	Address string `json:"address"`
+	// "mode" can be "managed", for resources, or "data", for data resources
+	Mode   string          `json:"mode"`
+	Type   string          `json:"type"`
+	Name   string          `json:"name"`
+	Change terraformChange `json:"change"`
+}
+
+// terraformChange is the model type for the change-representation of a resource change. "actions" is one of
+// ["no-op"], ["create"], ["read"], ["update"], ["delete", "create"], ["create", "delete"] or ["delete"]. "before" and
+// "after" are the provider specific values of the resource, and are null when the resource doesn't exist.
+type terraformChange struct {
+	Actions []string       `json:"actions"`
+	Before  map[string]any `json:"before"`
+	After   map[string]any `json:"after"`
+}
+
 // terraformValues is a model type for the `values-representation` object in a JSON output from terraform.
 // see https://www.terraform.io/internals/json-format#values-representation for more information on the shape
 // of the JSON data.
@@ -58,6 +58,39 @@ func TestTerraformPlan(t *testing.T) {
 	require.NotEmpty(t, deploymentPlan.localStateFilePath)
 }
 
+func TestTerraformPreview(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+	preparePlanShowMocks(mockContext.CommandRunner)
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	previewResult, err := infraProvider.Preview(*mockContext.Context)
+
+	require.Nil(t, err)
+	require.NotNil(t, previewResult.Preview)
+
+	// the data source is read during the plan and isn't a change
+	changes := previewResult.Preview.Properties.Changes
+	require.Len(t, changes, 3)
+
+	require.Equal(t, ChangeTypeCreate, changes[0].ChangeType)
+	require.Equal(t, "azurerm_resource_group", changes[0].ResourceType)
+	require.Equal(t, "rg-test-env", changes[0].Name)
+	require.Empty(t, changes[0].ResourceId.Id)
+
+	require.Equal(t, ChangeTypeModify, changes[1].ChangeType)
+	require.Equal(t, "sttestenv", changes[1].Name)
+
+	require.Equal(t, ChangeTypeDelete, changes[2].ChangeType)
+	require.Equal(t, "kv-test-env", changes[2].Name)
+	require.Regexp(
+		t,
+		regexp.MustCompile(`^/subscriptions/[^/]*/resourceGroups/[^/]*/providers/Microsoft.KeyVault/vaults/kv-test-env$`),
+		changes[2].ResourceId.Id,
+	)
+}
+
 func TestTerraformDestroy(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	prepareGenericMocks(mockContext.CommandRunner)
@@ -187,6 +220,18 @@ func prepareShowMocks(commandRunner *mockexec.MockCommandRunner) {
 	})
 }
 
+//go:embed testdata/terraform_plan_show_mock.json
+var terraformPlanShowMockOutput string
+
+func preparePlanShowMocks(commandRunner *mockexec.MockCommandRunner) {
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "show") && strings.Contains(command, ".tfplan")
+	}).Respond(exec.RunResult{
+		Stdout: terraformPlanShowMockOutput,
+		Stderr: "",
+	})
+}
+
 func prepareDestroyMocks(commandRunner *mockexec.MockCommandRunner) {
 	commandRunner.When(func(args exec.RunArgs, command string) bool {
 		return args.Cmd == "terraform" && strings.Contains(command, "init")
@@ -0,0 +1,232 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+)
+
+// resourceQueryCacheDuration is how long the results of a query are reused for the same query.
+const resourceQueryCacheDuration = 30 * time.Second
+
+// ErrInvalidResourceFilter is returned when a filter can't be composed with the query of the resources of an environment.
+var ErrInvalidResourceFilter = errors.New("invalid resource filter")
+
+// EnvironmentResource is a resource of an environment, as returned by Azure Resource Graph.
+type EnvironmentResource struct {
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// PortalUrl returns the URL of the resource in the Azure Portal.
+func (r *EnvironmentResource) PortalUrl() string {
+	return fmt.Sprintf("https://portal.azure.com/#@/resource%s", r.Id)
+}
+
+// ResourceGraphQuery queries the resources of environments with Azure Resource Graph.
+//
+// The results of a query are cached for a short time, so the same query isn't sent again while a command runs.
+type ResourceGraphQuery struct {
+	client *lazy.Lazy[*armresourcegraph.Client]
+	// now returns the current time, and is replaced in tests
+	now func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedResources
+}
+
+type cachedResources struct {
+	resources []EnvironmentResource
+	expiresAt time.Time
+}
+
+// NewResourceGraphQuery creates a ResourceGraphQuery. The client is resolved the first time a query is sent, so that
+// commands that don't send queries don't require credentials.
+func NewResourceGraphQuery(client *lazy.Lazy[*armresourcegraph.Client]) *ResourceGraphQuery {
+	return &ResourceGraphQuery{
+		client: client,
+		now:    time.Now,
+		cache:  map[string]cachedResources{},
+	}
+}
+
+// EnvironmentResources returns the resources of the resource group of an environment that match the filter, which is a
+// KQL predicate such as "type =~ 'Microsoft.Web/sites' and tags['azd-service-name'] == 'api'". When the filter is empty,
+// all the resources of the resource group are returned.
+func (q *ResourceGraphQuery) EnvironmentResources(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	filter string,
+) ([]EnvironmentResource, error) {
+	query, err := EnvironmentResourcesQuery(subscriptionId, resourceGroupName, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if cached, has := q.cache[query]; has && q.now().Before(cached.expiresAt) {
+		return cached.resources, nil
+	}
+
+	client, err := q.client.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	request := armresourcegraph.QueryRequest{
+		Query:         &query,
+		Subscriptions: []*string{&subscriptionId},
+		Options: &armresourcegraph.QueryRequestOptions{
+			ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+		},
+	}
+
+	resources := []EnvironmentResource{}
+	for {
+		res, err := client.Resources(ctx, request, nil)
+		if err != nil {
+			return nil, fmt.Errorf("querying resources of resource group %s: %w", resourceGroupName, err)
+		}
+
+		rows, ok := res.Data.([]any)
+		if !ok {
+			return nil, errors.New("error converting data to list")
+		}
+
+		for _, row := range rows {
+			if values, ok := row.(map[string]any); ok {
+				resources = append(resources, environmentResourceFromRow(values))
+			}
+		}
+
+		if res.SkipToken == nil || *res.SkipToken == "" {
+			break
+		}
+
+		request.Options.SkipToken = res.SkipToken
+	}
+
+	q.cache[query] = cachedResources{
+		resources: resources,
+		expiresAt: q.now().Add(resourceQueryCacheDuration),
+	}
+
+	return resources, nil
+}
+
+// EnvironmentResourcesQuery returns the KQL query of the resources of a resource group that match the filter.
+//
+// The filter is applied after the resources are scoped to the resource group, and must be a single predicate, so that it
+// only narrows down the resources of the resource group: pipes, statement separators and comments aren't allowed outside
+// of string literals, and the parentheses of the filter must be balanced.
+func EnvironmentResourcesQuery(subscriptionId string, resourceGroupName string, filter string) (string, error) {
+	if err := validateResourceFilter(filter); err != nil {
+		return "", err
+	}
+
+	query := fmt.Sprintf(
+		"Resources | where subscriptionId =~ %s and resourceGroup =~ %s",
+		kqlString(subscriptionId),
+		kqlString(resourceGroupName))
+
+	if strings.TrimSpace(filter) != "" {
+		query += fmt.Sprintf(" | where (%s)", strings.TrimSpace(filter))
+	}
+
+	return query + " | project id, name, type, location, tags | order by type asc, name asc", nil
+}
+
+// validateResourceFilter checks that the filter is a single KQL predicate, which can't end the where clause it's placed in.
+func validateResourceFilter(filter string) error {
+	depth := 0
+	// the quote of the string literal the current character is in, if any
+	var quote rune
+	verbatim := false
+	escaped := false
+
+	runes := []rune(filter)
+	for idx, r := range runes {
+		if quote != 0 {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\' && !verbatim:
+				escaped = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch r {
+		case '\'', '"':
+			quote = r
+			verbatim = idx > 0 && (runes[idx-1] == '@')
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("%w: unbalanced ')'", ErrInvalidResourceFilter)
+			}
+		case '|', ';':
+			return fmt.Errorf("%w: '%c' isn't allowed outside of a string", ErrInvalidResourceFilter, r)
+		case '/':
+			if idx+1 < len(runes) && runes[idx+1] == '/' {
+				return fmt.Errorf("%w: comments aren't allowed", ErrInvalidResourceFilter)
+			}
+		case '\n', '\r':
+			return fmt.Errorf("%w: the filter must be on a single line", ErrInvalidResourceFilter)
+		}
+	}
+
+	if quote != 0 {
+		return fmt.Errorf("%w: unterminated string", ErrInvalidResourceFilter)
+	}
+
+	if depth != 0 {
+		return fmt.Errorf("%w: unbalanced '('", ErrInvalidResourceFilter)
+	}
+
+	return nil
+}
+
+// kqlString returns the value as a KQL string literal.
+func kqlString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+func environmentResourceFromRow(values map[string]any) EnvironmentResource {
+	resource := EnvironmentResource{}
+	resource.Id, _ = values["id"].(string)
+	resource.Name, _ = values["name"].(string)
+	resource.Type, _ = values["type"].(string)
+	resource.Location, _ = values["location"].(string)
+
+	if tags, ok := values["tags"].(map[string]any); ok {
+		resource.Tags = make(map[string]string, len(tags))
+		for key, value := range tags {
+			if value, ok := value.(string); ok {
+				resource.Tags[key] = value
+			}
+		}
+	}
+
+	return resource
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvironmentResourcesQuery(t *testing.T) {
+	t.Run("NoFilter", func(t *testing.T) {
+		query, err := EnvironmentResourcesQuery("SUBSCRIPTION_ID", "rg-dev", "")
+		require.NoError(t, err)
+		require.Equal(t,
+			"Resources | where subscriptionId =~ 'SUBSCRIPTION_ID' and resourceGroup =~ 'rg-dev'"+
+				" | project id, name, type, location, tags | order by type asc, name asc",
+			query)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		query, err := EnvironmentResourcesQuery(
+			"SUBSCRIPTION_ID", "rg-dev", "type =~ 'Microsoft.Web/sites' and tags['azd-service-name'] == 'a|b;c'")
+		require.NoError(t, err)
+		require.Equal(t,
+			"Resources | where subscriptionId =~ 'SUBSCRIPTION_ID' and resourceGroup =~ 'rg-dev'"+
+				" | where (type =~ 'Microsoft.Web/sites' and tags['azd-service-name'] == 'a|b;c')"+
+				" | project id, name, type, location, tags | order by type asc, name asc",
+			query)
+	})
+
+	t.Run("EscapesScope", func(t *testing.T) {
+		query, err := EnvironmentResourcesQuery("SUBSCRIPTION_ID", `rg' or 1 == 1 or '\`, "")
+		require.NoError(t, err)
+		require.Contains(t, query, `resourceGroup =~ 'rg\' or 1 == 1 or \'\\'`)
+	})
+
+	invalid := map[string]string{
+		"Pipe":             "true) | union (Resources",
+		"Semicolon":        "true; Resources",
+		"Comment":          "true // comment",
+		"Unbalanced":       "true) or (true",
+		"UnclosedParen":    "(true",
+		"UnclosedString":   "name == 'web",
+		"EscapedBackslash": `name == 'web\\' | project name`,
+		"Newline":          "true\n| project name",
+		"PipeAfterLiteral": `name == "web" | take 1`,
+	}
+
+	for name, filter := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := EnvironmentResourcesQuery("SUBSCRIPTION_ID", "rg-dev", filter)
+			require.ErrorIs(t, err, ErrInvalidResourceFilter)
+		})
+	}
+
+	t.Run("VerbatimString", func(t *testing.T) {
+		_, err := EnvironmentResourcesQuery("SUBSCRIPTION_ID", "rg-dev", `name == @'C:\path|' and (true)`)
+		require.NoError(t, err)
+	})
+}
+
+func Test_ResourceGraphQuery_EnvironmentResources(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var queries []string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.Contains(request.URL.Path, "providers/Microsoft.ResourceGraph/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, string(body))
+
+		response := armresourcegraph.ClientResourcesResponse{
+			QueryResponse: armresourcegraph.QueryResponse{
+				Data: []any{
+					map[string]any{
+						"id":       "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-dev/providers/Microsoft.Web/sites/app-web",
+						"name":     "app-web",
+						"type":     "microsoft.web/sites",
+						"location": "eastus2",
+						"tags":     map[string]any{"azd-service-name": "web"},
+					},
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	armOptions := azsdk.
+		DefaultClientOptionsBuilder(*mockContext.Context, mockContext.HttpClient, "azd").
+		BuildArmClientOptions()
+
+	client, err := armresourcegraph.NewClient(mockContext.Credentials, armOptions)
+	require.NoError(t, err)
+
+	now := time.Now()
+	query := NewResourceGraphQuery(lazy.From(client))
+	query.now = func() time.Time { return now }
+
+	resources, err := query.EnvironmentResources(*mockContext.Context, "SUBSCRIPTION_ID", "rg-dev", "type =~ 'x'")
+	require.NoError(t, err)
+	require.Equal(t, []EnvironmentResource{
+		{
+			Id:       "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-dev/providers/Microsoft.Web/sites/app-web",
+			Name:     "app-web",
+			Type:     "microsoft.web/sites",
+			Location: "eastus2",
+			Tags:     map[string]string{"azd-service-name": "web"},
+		},
+	}, resources)
+	require.Equal(t,
+		"https://portal.azure.com/#@/resource/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-dev/providers/"+
+			"Microsoft.Web/sites/app-web",
+		resources[0].PortalUrl())
+	require.Len(t, queries, 1)
+
+	// the results of the same query are reused until they expire
+	_, err = query.EnvironmentResources(*mockContext.Context, "SUBSCRIPTION_ID", "rg-dev", "type =~ 'x'")
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+
+	_, err = query.EnvironmentResources(*mockContext.Context, "SUBSCRIPTION_ID", "rg-dev", "type =~ 'y'")
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+
+	now = now.Add(resourceQueryCacheDuration)
+	_, err = query.EnvironmentResources(*mockContext.Context, "SUBSCRIPTION_ID", "rg-dev", "type =~ 'x'")
+	require.NoError(t, err)
+	require.Len(t, queries, 3)
+
+	// invalid filters aren't sent
+	_, err = query.EnvironmentResources(*mockContext.Context, "SUBSCRIPTION_ID", "rg-dev", "true | take 1")
+	require.ErrorIs(t, err, ErrInvalidResourceFilter)
+	require.Len(t, queries, 3)
+}
@@ -15,25 +15,48 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 )
 
-type Asker func(p survey.Prompt, response interface{}) error
+type Asker func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error
 
 func NewAsker(noPrompt bool, isTerminal bool, w io.Writer, r io.Reader) Asker {
 	if noPrompt {
 		return askOneNoPrompt
 	}
 
-	return func(p survey.Prompt, response interface{}) error {
-		return askOnePrompt(p, response, isTerminal, w, r)
+	return func(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
+		return askOnePrompt(p, response, isTerminal, w, r, opts...)
 	}
 }
 
-func askOneNoPrompt(p survey.Prompt, response interface{}) error {
+// validateAnswer runs the validators configured in opts against the answer, returning the first failure.
+func validateAnswer(answer interface{}, opts []survey.AskOpt) error {
+	var options survey.AskOptions
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return err
+		}
+	}
+
+	for _, validator := range options.Validators {
+		if err := validator(answer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func askOneNoPrompt(p survey.Prompt, response interface{}, opts ...survey.AskOpt) error {
 	switch v := p.(type) {
 	case *survey.Input:
 		if v.Default == "" {
 			return fmt.Errorf("no default response for prompt '%s'", v.Message)
 		}
 
+		// There is no one to re-prompt, so an invalid default is returned as an error.
+		if err := validateAnswer(v.Default, opts); err != nil {
+			return fmt.Errorf("default response for prompt '%s' is not valid: %w", v.Message, err)
+		}
+
 		*(response.(*string)) = v.Default
 	case *survey.Select:
 		if v.Default == nil {
@@ -81,7 +104,14 @@ func withShowCursor(o *survey.AskOptions) error {
 	return nil
 }
 
-func askOnePrompt(p survey.Prompt, response interface{}, isTerminal bool, stdout io.Writer, stdin io.Reader) error {
+func askOnePrompt(
+	p survey.Prompt,
+	response interface{},
+	isTerminal bool,
+	stdout io.Writer,
+	stdin io.Reader,
+	askOpts ...survey.AskOpt,
+) error {
 	// Like (*bufio.Reader).ReadString(byte) except that it does not buffer input from the input stream.
 	// Instead, it reads a byte at a time until a delimiter is found or EOF is encountered,
 	// returning bytes read with no extra characters consumed.
@@ -106,7 +136,7 @@ func askOnePrompt(p survey.Prompt, response interface{}, isTerminal bool, stdout
 	}
 
 	if isTerminal && os.Getenv("AZD_DEBUG_FORCE_NO_TTY") != "1" {
-		opts := []survey.AskOpt{}
+		opts := slices.Clone(askOpts)
 
 		// When asking a question which requires a text response, show the cursor, it helps
 		// users understand we need some input.
@@ -131,21 +161,34 @@ func askOnePrompt(p survey.Prompt, response interface{}, isTerminal bool, stdout
 	switch v := p.(type) {
 	case *survey.Input:
 		var pResponse = response.(*string)
-		fmt.Fprintf(stdout, "%s", v.Message[0:len(v.Message)-1])
-		if v.Default != "" {
-			fmt.Fprintf(stdout, " (or hit enter to use the default %s)", v.Default)
-		}
-		fmt.Fprintf(stdout, "%s ", v.Message[len(v.Message)-1:])
-		result, err := readStringNoBuffer(stdin, '\n')
-		if err != nil && !errors.Is(err, io.EOF) {
-			return fmt.Errorf("reading response: %w", err)
-		}
-		result = strings.TrimSpace(result)
-		if result == "" && v.Default != "" {
-			result = v.Default
+		for {
+			fmt.Fprintf(stdout, "%s", v.Message[0:len(v.Message)-1])
+			if v.Default != "" {
+				fmt.Fprintf(stdout, " (or hit enter to use the default %s)", v.Default)
+			}
+			fmt.Fprintf(stdout, "%s ", v.Message[len(v.Message)-1:])
+			result, err := readStringNoBuffer(stdin, '\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("reading response: %w", err)
+			}
+			result = strings.TrimSpace(result)
+			if result == "" && v.Default != "" {
+				result = v.Default
+			}
+
+			if validationErr := validateAnswer(result, askOpts); validationErr != nil {
+				// at the end of the input there is nothing more to read, so re-prompting would never finish.
+				if errors.Is(err, io.EOF) {
+					return validationErr
+				}
+
+				fmt.Fprintf(stdout, "%s\n", validationErr.Error())
+				continue
+			}
+
+			*pResponse = result
+			return nil
 		}
-		*pResponse = result
-		return nil
 	case *survey.MultiSelect:
 		// For multi-selection, azd will do a Select for each item, using the default to control the Y or N
 		defValue, err := v.Default.([]string)
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func notEmpty(value string) error {
+	if value == "" {
+		return errors.New("value must not be empty")
+	}
+
+	return nil
+}
+
+func TestAskOneNoPromptValidation(t *testing.T) {
+	_, opts := promptFromOptions(ConsoleOptions{Validate: func(value string) error {
+		if value != "valid" {
+			return errors.New("not valid")
+		}
+		return nil
+	}})
+
+	var response string
+	err := askOneNoPrompt(&survey.Input{Message: "Value:", Default: "invalid"}, &response, opts...)
+	require.ErrorContains(t, err, "not valid")
+	require.Empty(t, response)
+
+	err = askOneNoPrompt(&survey.Input{Message: "Value:", Default: "valid"}, &response, opts...)
+	require.NoError(t, err)
+	require.Equal(t, "valid", response)
+}
+
+func TestAskOnePromptValidationReprompts(t *testing.T) {
+	_, opts := promptFromOptions(ConsoleOptions{Validate: notEmpty})
+
+	stdout := &bytes.Buffer{}
+	stdin := strings.NewReader("\nvalue\n")
+
+	var response string
+	err := askOnePrompt(&survey.Input{Message: "Value:"}, &response, false, stdout, stdin, opts...)
+	require.NoError(t, err)
+	require.Equal(t, "value", response)
+	require.Contains(t, stdout.String(), "value must not be empty")
+}
+
+func TestAskOnePromptValidationEndOfInput(t *testing.T) {
+	_, opts := promptFromOptions(ConsoleOptions{Validate: notEmpty})
+
+	var response string
+	err := askOnePrompt(&survey.Input{Message: "Value:"}, &response, false, &bytes.Buffer{}, strings.NewReader(""), opts...)
+	require.ErrorContains(t, err, "value must not be empty")
+}
@@ -4,16 +4,18 @@
 package input
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -22,8 +24,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/resource"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/mattn/go-colorable"
 	"github.com/nathan-fiscaletti/consolesize-go"
 	"github.com/theckman/yacspin"
 	"go.uber.org/atomic"
@@ -37,6 +41,9 @@ const (
 	StepFailed
 	StepWarning
 	StepSkipped
+	// StepElapsed shows a spinner like Step, with the time elapsed since the title was set after the title. The elapsed
+	// time is only shown by interactive spinners.
+	StepElapsed
 )
 
 // A shim to allow a single Console construction in the application.
@@ -61,6 +68,9 @@ type Console interface {
 	Message(ctx context.Context, message string)
 	// Prints out a message following a contract ux item
 	MessageUxItem(ctx context.Context, item ux.UxItem)
+	// Prints out a message on its own line without garbling the spinner, progress bar or previewer being shown.
+	// Safe to call from background goroutines.
+	SafeLog(msg string)
 	WarnForFeature(ctx context.Context, id alpha.FeatureId)
 	// Prints progress spinner with the given title.
 	// If a previous spinner is running, the title is updated.
@@ -75,14 +85,22 @@ type Console interface {
 	ShowPreviewer(ctx context.Context, options *ShowPreviewerOptions) io.Writer
 	// Finalize the preview mode from console.
 	StopPreviewer(ctx context.Context, keepLogs bool)
+	// Shows a progress bar with the given title for an operation which can estimate its completion.
+	// Use the returned ProgressReporter to update the progress and to stop the progress bar.
+	ShowProgress(ctx context.Context, title string) ProgressReporter
 	// Determines if there is a current spinner running.
 	IsSpinnerRunning(ctx context.Context) bool
 	// Determines if the current spinner is an interactive spinner, where messages are updated periodically.
 	// If false, the spinner is non-interactive, which means messages are rendered as a new console message on each
 	// call to ShowSpinner, even when the title is unchanged.
 	IsSpinnerInteractive() bool
+	// Determines if the console is in no-prompt mode, where prompts return their default value, or fail when there is
+	// no default value.
+	IsNoPromptMode() bool
 	// Prompts the user for a single value
 	Prompt(ctx context.Context, options ConsoleOptions) (string, error)
+	// Prompts the user for a single integer value, re-prompting until a valid integer within the bounds is entered
+	PromptInt(ctx context.Context, options ConsoleOptions) (int, error)
 	// Prompts the user to select a single value from a set of values
 	Select(ctx context.Context, options ConsoleOptions) (int, error)
 	// Prompts the user to select zero or more values from a set of values
@@ -91,14 +109,21 @@ type Console interface {
 	Confirm(ctx context.Context, options ConsoleOptions) (bool, error)
 	// block terminal until the next enter
 	WaitForEnter()
+	// block terminal until the next enter, or until the context is cancelled or its deadline elapses
+	WaitForEnterCtx(ctx context.Context)
 	// Writes a new line to the writer if there if the last two characters written are not '\n'
 	EnsureBlankLine(ctx context.Context)
 	// Sets the underlying writer for the console
 	SetWriter(writer io.Writer)
 	// Gets the underlying writer for the console
 	GetWriter() io.Writer
+	// Adds a writer which receives a copy of the messages, ux items and spinner stop messages written to the console,
+	// with ANSI control sequences removed. The transcript is not affected by SetWriter or the previewer.
+	AddTranscript(writer io.Writer)
 	// Gets the standard input, output and error stream
 	Handles() ConsoleHandles
+	// Stops watching for console resizes. The console can still be written to after it is closed.
+	Close() error
 	ConsoleShim
 }
 
@@ -108,10 +133,16 @@ type AskerConsole struct {
 	// the writer the console was constructed with, and what we reset to when SetWriter(nil) is called.
 	defaultWriter io.Writer
 	// the writer which output is written to.
-	writer     io.Writer
+	writer io.Writer
+	// writers which receive an uncolored copy of the human-readable output, see AddTranscript.
+	transcripts   []io.Writer
+	transcriptsMu sync.Mutex
+
 	formatter  output.Formatter
 	isTerminal bool
 	noPrompt   bool
+	// when true, Confirm returns true without prompting, see NewConsole.
+	assumeYes bool
 
 	showProgressMu sync.Mutex // ensures atomicity when swapping the current progress renderer (spinner or previewer)
 
@@ -119,26 +150,81 @@ type AskerConsole struct {
 	spinnerLineMu       sync.Mutex // secures spinnerCurrentTitle and the line of spinner text
 	spinnerTerminalMode yacspin.TerminalMode
 	spinnerCurrentTitle string
+	// when the current title of the spinner was set, and whether the time elapsed since then is shown after the title
+	spinnerTitleStart  time.Time
+	spinnerShowElapsed bool
+	// closed to stop updating the elapsed time of the spinner
+	spinnerElapsedStop chan struct{}
+	// the interval between the messages printed while a non-interactive spinner is running, zero when disabled
+	spinnerHeartbeatInterval time.Duration
+	// closed to stop printing messages while a non-interactive spinner is running
+	spinnerHeartbeatStop chan struct{}
+	// when set, non-interactive spinners do not print the titles of ShowSpinner calls made while the spinner is running
+	quietSpinner bool
 
 	previewer *progressLog
+	// emits the lines written to the previewer as log events instead, when the console writes json
+	previewerEvents *logEventWriter
+
+	progress *consoleProgress
 
 	currentIndent *atomic.String
 	consoleWidth  *atomic.Int32
 	// holds the last 2 bytes written by message or messageUX. This is used to detect when there is already an empty
 	// line (\n\n)
 	last2Byte [2]byte
+
+	// closed when the console is closed, which stops watching for console resizes
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 type ConsoleOptions struct {
-	Message      string
-	Help         string
-	Options      []string
+	Message string
+	Help    string
+	Options []string
+	// DefaultValue is the value used when the user does not provide one. Its type depends on the prompt:
+	// a string for Prompt and Select, a bool for Confirm, an int for PromptInt, and a []string of the options
+	// that are pre-selected for MultiSelect.
 	DefaultValue any
+	// DefaultValueFn computes the default value when the prompt is shown, for defaults that depend on data which is only
+	// available then, such as the current git branch. When set, the value it returns replaces DefaultValue, and an error
+	// it returns is returned by the prompt.
+	DefaultValueFn func(ctx context.Context) (any, error)
 
 	// Prompt-only options
 
 	IsPassword bool
 	Suggest    func(input string) (completions []string)
+	// Validate is called with the value entered by the user. When it returns an error, the error is shown and the user
+	// is prompted again. When prompting is disabled, the error is returned from Prompt instead.
+	Validate func(value string) error
+	// Required rejects an empty (or whitespace only) value with an error, which is checked before Validate. When prompting
+	// is disabled and there is no DefaultValue, Prompt returns an error instead of an empty value.
+	Required bool
+
+	// PromptInt-only options
+
+	// MinValue and MaxValue are the optional inclusive bounds of the value. A nil bound is not enforced.
+	MinValue *int
+	MaxValue *int
+
+	// Select and MultiSelect options
+
+	// Filter returns the options matching a query, so that long lists don't have to be loaded into the prompt at once.
+	// When it is set, the user is first asked for a query, with the matching options suggested while they type, and
+	// then selects from the options Filter returns for the query, which don't have to be in Options. An empty query
+	// selects from Options instead. Select returns the index of the selected option in Options, so the options Filter
+	// returns for Select must be in Options, while MultiSelect returns any selected option. When prompting is disabled,
+	// Filter isn't called and the default value is used as usual.
+	Filter func(query string) []string
+
+	// MultiSelect-only options
+
+	// MinSelections and MaxSelections are the inclusive bounds of the number of options that must be selected.
+	// Zero means the bound is not enforced.
+	MinSelections int
+	MaxSelections int
 }
 
 type ConsoleHandles struct {
@@ -157,6 +243,27 @@ func (c *AskerConsole) SetWriter(writer io.Writer) {
 	c.writer = writer
 }
 
+// Adds a writer which receives a copy of the messages, ux items and spinner stop messages written to the console,
+// with ANSI control sequences removed. The transcript is not affected by SetWriter or the previewer.
+func (c *AskerConsole) AddTranscript(writer io.Writer) {
+	c.transcriptsMu.Lock()
+	defer c.transcriptsMu.Unlock()
+
+	c.transcripts = append(c.transcripts, colorable.NewNonColorable(writer))
+}
+
+// writeTranscript writes msg, followed by a new line, to each transcript writer.
+func (c *AskerConsole) writeTranscript(msg string) {
+	c.transcriptsMu.Lock()
+	defer c.transcriptsMu.Unlock()
+
+	for _, transcript := range c.transcripts {
+		if _, err := fmt.Fprintln(transcript, msg); err != nil {
+			log.Printf("failed to write console transcript: %v", err)
+		}
+	}
+}
+
 func (c *AskerConsole) GetFormatter() output.Formatter {
 	return c.formatter
 }
@@ -168,14 +275,8 @@ func (c *AskerConsole) IsUnformatted() bool {
 // Prints out a message to the underlying console write
 func (c *AskerConsole) Message(ctx context.Context, message string) {
 	// Disable output when formatting is enabled
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
-		// we call json.Marshal directly, because the formatter marshalls using indentation, and we would prefer
-		// these objects be written on a single line.
-		jsonMessage, err := json.Marshal(output.EventForMessage(message))
-		if err != nil {
-			panic(fmt.Sprintf("Message: unexpected error during marshaling for a valid object: %v", err))
-		}
-		fmt.Fprintln(c.writer, string(jsonMessage))
+	if c.isStructuredOutput() {
+		c.writeStructured(output.EventForMessage(message))
 	} else if c.formatter == nil || c.formatter.Kind() == output.NoneFormat {
 		c.println(ctx, message)
 	} else {
@@ -221,21 +322,68 @@ func shouldWarn(key alpha.FeatureId) bool {
 }
 
 func (c *AskerConsole) MessageUxItem(ctx context.Context, item ux.UxItem) {
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
-		// no need to check the spinner for json format, as the spinner won't start when using json format
+	if c.isStructuredOutput() {
+		// no need to check the spinner for structured formats, as the spinner won't start when using them
 		// instead, there would be a message about starting spinner
-		json, _ := json.Marshal(item)
-		fmt.Fprintln(c.writer, string(json))
+		c.writeStructured(item)
 		return
 	}
 
+	if table, ok := item.(*ux.Table); ok && table.Width <= 0 {
+		// fit the table into the console, leaving the last column free so that lines don't wrap
+		fitted := *table
+		fitted.Width = int(c.consoleWidth.Load()) - 1
+		item = &fitted
+	}
+
+	if markdown, ok := item.(*ux.Markdown); ok {
+		// wrap the markdown to the console, and display it as is when it can't be rendered
+		rendered := *markdown
+		if rendered.Width <= 0 {
+			rendered.Width = int(c.consoleWidth.Load()) - 1
+		}
+		rendered.Plain = rendered.Plain || !c.isTerminal || os.Getenv("NO_COLOR") != ""
+		item = &rendered
+	}
+
 	msg := item.ToString(c.currentIndent.Load())
 	c.println(ctx, msg)
 	// Adding "\n" b/c calling Fprintln is adding one new line at the end to the msg
 	c.updateLastBytes(msg + "\n")
 }
 
+// isStructuredOutput returns true when the console emits structured events (json or yaml) instead of plain text.
+func (c *AskerConsole) isStructuredOutput() bool {
+	return c.formatter != nil &&
+		(c.formatter.Kind() == output.JsonFormat || c.formatter.Kind() == output.YamlFormat)
+}
+
+// writeStructured writes obj to the console writer as a single event in the structured format of the console.
+func (c *AskerConsole) writeStructured(obj any) {
+	if c.formatter.Kind() == output.YamlFormat {
+		// each event is written as its own document in the yaml stream.
+		yamlDoc, err := output.MarshalYaml(obj)
+		if err != nil {
+			log.Printf("failed to marshal console event as yaml: %v", err)
+			return
+		}
+		fmt.Fprintf(c.writer, "---\n%s", string(yamlDoc))
+		return
+	}
+
+	// we call json.Marshal directly, because the formatter marshalls using indentation, and we would prefer
+	// these objects be written on a single line.
+	jsonMessage, err := json.Marshal(obj)
+	if err != nil {
+		log.Printf("failed to marshal console event as json: %v", err)
+		return
+	}
+	fmt.Fprintln(c.writer, string(jsonMessage))
+}
+
 func (c *AskerConsole) println(ctx context.Context, msg string) {
+	c.writeTranscript(msg)
+
 	if c.spinner.Status() == yacspin.SpinnerRunning {
 		c.StopSpinner(ctx, "", Step)
 		// default non-format
@@ -246,6 +394,57 @@ func (c *AskerConsole) println(ctx context.Context, msg string) {
 	}
 }
 
+// SafeLog writes the message on its own line, coordinated with the spinner, progress bar or previewer being shown. A
+// running spinner is paused and its line cleared while the message is written, and resumes below it. It's safe to call
+// from background goroutines while another goroutine updates the spinner.
+func (c *AskerConsole) SafeLog(msg string) {
+	c.showProgressMu.Lock()
+	defer c.showProgressMu.Unlock()
+
+	if c.isStructuredOutput() {
+		c.writeStructured(output.EventForMessage(msg))
+		return
+	}
+
+	c.spinnerLineMu.Lock()
+	defer c.spinnerLineMu.Unlock()
+
+	c.writeTranscript(msg)
+	c.updateLastBytes(msg + "\n")
+
+	switch {
+	case c.previewer != nil:
+		// the previewer renders the lines written to it
+		fmt.Fprintln(c.writer, msg)
+	case c.progress != nil && c.progress.mode == progressModeInteractive:
+		fmt.Fprintf(c.writer, "\r%s\033[K\n", msg)
+		c.progress.redraw()
+	case c.spinner.Status() == yacspin.SpinnerRunning:
+		// the painter of the spinner doesn't write while it's paused
+		_ = c.spinner.Pause()
+		if c.IsSpinnerInteractive() {
+			c.clearSpinnerLine()
+		}
+		fmt.Fprintln(c.writer, msg)
+		_ = c.spinner.Unpause()
+	default:
+		fmt.Fprintln(c.writer, msg)
+	}
+}
+
+// clearSpinnerLine erases the line of the interactive spinner, so a message can be written in its place. The spinner must
+// be paused.
+func (c *AskerConsole) clearSpinnerLine() {
+	if c.spinnerTerminalMode&yacspin.ForceDumbTerminalMode > 0 {
+		// dumb terminals don't support erasing the line, it's overwritten with spaces instead
+		width := max(int(c.consoleWidth.Load())-1, 0)
+		fmt.Fprintf(c.writer, "\r%s\r", strings.Repeat(" ", width))
+		return
+	}
+
+	fmt.Fprint(c.writer, "\r\033[K")
+}
+
 func defaultShowPreviewerOptions() *ShowPreviewerOptions {
 	return &ShowPreviewerOptions{
 		MaxLineCount: 5,
@@ -264,6 +463,25 @@ func (c *AskerConsole) ShowPreviewer(ctx context.Context, options *ShowPreviewer
 		options = defaultShowPreviewerOptions()
 	}
 
+	if !c.IsUnformatted() {
+		// the lines written are emitted as log events of the title of the previewer, or of the spinner when the
+		// previewer has no title
+		phase := options.Title
+		if phase == "" {
+			phase = currentMsg
+		}
+
+		c.previewerEvents = &logEventWriter{
+			phase: phase,
+			emit: func(event contracts.EventEnvelope) {
+				c.writeStructured(event)
+			},
+		}
+		return &consolePreviewerWriter{
+			events: &c.previewerEvents,
+		}
+	}
+
 	c.previewer = NewProgressLog(options.MaxLineCount, options.Prefix, options.Title, c.currentIndent.Load()+currentMsg)
 	c.previewer.Start()
 	c.writer = c.previewer
@@ -273,6 +491,13 @@ func (c *AskerConsole) ShowPreviewer(ctx context.Context, options *ShowPreviewer
 }
 
 func (c *AskerConsole) StopPreviewer(ctx context.Context, keepLogs bool) {
+	if c.previewerEvents != nil {
+		c.previewerEvents.Flush()
+		c.previewerEvents = nil
+		_ = c.spinner.Unpause()
+		return
+	}
+
 	c.previewer.Stop(keepLogs)
 	c.previewer = nil
 	c.writer = c.defaultWriter
@@ -294,9 +519,25 @@ type spinnerLine struct {
 	Message string
 }
 
-func (c *AskerConsole) spinnerLine(title string, indent string) spinnerLine {
-	spinnerLen := len(indent) + len(spinnerCharSet[0]) + 1 // adding one for the empty space before the message
+// spinnerLine returns the line of the spinner for the title, fitted to the width of the console. The elapsed time is
+// shown after the title when it is set and the line is wide enough, the title is truncated first to make room for it.
+func (c *AskerConsole) spinnerLine(title string, indent string, elapsed string) spinnerLine {
 	width := int(c.consoleWidth.Load())
+	if elapsed != "" {
+		suffix := fmt.Sprintf(" (%s)", elapsed)
+		spinnerLen := len(indent) + len(spinnerCharSet[0]) + 1
+		if width-len(suffix) > spinnerLen+len(cPostfix) {
+			line := spinnerLineForWidth(title, indent, width-len(suffix))
+			line.Message += suffix
+			return line
+		}
+	}
+
+	return spinnerLineForWidth(title, indent, width)
+}
+
+func spinnerLineForWidth(title string, indent string, width int) spinnerLine {
+	spinnerLen := len(indent) + len(spinnerCharSet[0]) + 1 // adding one for the empty space before the message
 
 	switch {
 	case width <= 3: // show number of dots up to 3
@@ -326,8 +567,8 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 	c.showProgressMu.Lock()
 	defer c.showProgressMu.Unlock()
 
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
-		// Spinner is disabled when using json format.
+	if c.isStructuredOutput() {
+		// Spinner is disabled when using structured formats.
 		return
 	}
 
@@ -338,10 +579,24 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 	}
 
 	c.spinnerLineMu.Lock()
+	if c.quietSpinner && !c.IsSpinnerInteractive() && c.spinnerCurrentTitle != "" {
+		// In quiet mode, a non-interactive spinner only prints the first title of a step. The title of the running
+		// step is cleared when the spinner is stopped.
+		c.spinnerLineMu.Unlock()
+		return
+	}
+	if title != c.spinnerCurrentTitle {
+		c.spinnerTitleStart = time.Now()
+	}
 	c.spinnerCurrentTitle = title
+	c.setSpinnerShowElapsed(format == StepElapsed && c.IsSpinnerInteractive())
+	if !c.IsSpinnerInteractive() && c.spinnerHeartbeatInterval > 0 && c.spinnerHeartbeatStop == nil {
+		c.spinnerHeartbeatStop = make(chan struct{})
+		go c.spinnerHeartbeat(c.spinnerHeartbeatStop)
+	}
 
 	indentPrefix := c.getIndent(format)
-	line := c.spinnerLine(title, indentPrefix)
+	line := c.spinnerLine(title, indentPrefix, c.spinnerElapsed())
 	c.spinner.Message(line.Message)
 	_ = c.spinner.CharSet(line.CharSet)
 	c.spinner.Prefix(line.Prefix)
@@ -350,6 +605,99 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 	c.spinnerLineMu.Unlock()
 }
 
+// spinnerElapsed returns the time elapsed since the current title of the spinner was set, or an empty string when the
+// elapsed time isn't shown. It must be called with spinnerLineMu held.
+func (c *AskerConsole) spinnerElapsed() string {
+	if !c.spinnerShowElapsed {
+		return ""
+	}
+
+	return time.Since(c.spinnerTitleStart).Truncate(time.Second).String()
+}
+
+// setSpinnerShowElapsed starts or stops updating the elapsed time after the title of the spinner. It must be called with
+// spinnerLineMu held.
+func (c *AskerConsole) setSpinnerShowElapsed(show bool) {
+	c.spinnerShowElapsed = show
+	if show && c.spinnerElapsedStop == nil {
+		c.spinnerElapsedStop = make(chan struct{})
+		go c.updateSpinnerElapsed(c.spinnerElapsedStop)
+	} else if !show && c.spinnerElapsedStop != nil {
+		close(c.spinnerElapsedStop)
+		c.spinnerElapsedStop = nil
+	}
+}
+
+// updateSpinnerElapsed updates the elapsed time after the title of the spinner on each frame of the spinner, until stop
+// or the console is closed.
+func (c *AskerConsole) updateSpinnerElapsed(stop chan struct{}) {
+	ticker := time.NewTicker(spinnerFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			c.spinnerLineMu.Lock()
+			if c.spinnerShowElapsed && c.spinner.Status() == yacspin.SpinnerRunning {
+				line := c.spinnerLine(c.spinnerCurrentTitle, c.currentIndent.Load(), c.spinnerElapsed())
+				c.spinner.Message(line.Message)
+			}
+			c.spinnerLineMu.Unlock()
+		}
+	}
+}
+
+// The message printed periodically while a non-interactive spinner is running
+const cSpinnerHeartbeat = "Still working..."
+
+// The default interval between the messages printed while a non-interactive spinner is running
+const defaultSpinnerHeartbeatInterval = 60 * time.Second
+
+// spinnerHeartbeat prints a message periodically while a non-interactive spinner is running, so that the output doesn't
+// stop during long running steps, which CI systems may consider as hung. It stops when stop or the console is closed.
+func (c *AskerConsole) spinnerHeartbeat(stop chan struct{}) {
+	ticker := time.NewTicker(c.spinnerHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			c.spinnerLineMu.Lock()
+			if c.spinner.Status() == yacspin.SpinnerRunning && !c.isStructuredOutput() {
+				elapsed := time.Since(c.spinnerTitleStart).Truncate(time.Second)
+				fmt.Fprintf(c.writer, "%s%s (%s)\n", c.currentIndent.Load(), cSpinnerHeartbeat, elapsed)
+			}
+			c.spinnerLineMu.Unlock()
+		}
+	}
+}
+
+// spinnerHeartbeatInterval returns the interval between the messages printed while a non-interactive spinner is running,
+// or 0 when no messages are printed. AZD_SPINNER_HEARTBEAT_SECONDS overrides the interval, setting it to 0 disables the
+// messages.
+func spinnerHeartbeatInterval() time.Duration {
+	strVal, has := os.LookupEnv("AZD_SPINNER_HEARTBEAT_SECONDS")
+	if !has {
+		return defaultSpinnerHeartbeatInterval
+	}
+
+	seconds, err := strconv.Atoi(strVal)
+	if err != nil || seconds < 0 {
+		log.Printf("ignoring invalid value for AZD_SPINNER_HEARTBEAT_SECONDS: %q", strVal)
+		return defaultSpinnerHeartbeatInterval
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
 // spinnerTerminalMode determines the appropriate terminal mode for the spinner based on the current environment,
 // taking into account of environment variables that can control the terminal mode behavior.
 func spinnerTerminalMode(isTerminal bool) yacspin.TerminalMode {
@@ -388,6 +736,22 @@ func spinnerTerminalMode(isTerminal bool) yacspin.TerminalMode {
 	return termMode
 }
 
+// quietSpinner returns true when AZD_QUIET_SPINNER is set to a true value.
+func quietSpinner() bool {
+	strVal, has := os.LookupEnv("AZD_QUIET_SPINNER")
+	if !has {
+		return false
+	}
+
+	quiet, err := strconv.ParseBool(strVal)
+	if err != nil {
+		log.Println("AZD_QUIET_SPINNER is not a valid boolean value")
+		return false
+	}
+
+	return quiet
+}
+
 var spinnerCharSet []string = []string{
 	"|       |", "|=      |", "|==     |", "|===    |", "|====   |", "|=====  |", "|====== |",
 	"|=======|", "| ======|", "|  =====|", "|   ====|", "|    ===|", "|     ==|", "|      =|",
@@ -395,6 +759,9 @@ var spinnerCharSet []string = []string{
 
 var spinnerShortCharSet []string = []string{".", "..", "..."}
 
+// The interval between the frames of the spinner
+const spinnerFrequency = 200 * time.Millisecond
+
 func setIndentation(spaces int) string {
 	bytes := make([]byte, spaces)
 	for i := range bytes {
@@ -412,8 +779,8 @@ func (c *AskerConsole) getIndent(format SpinnerUxType) string {
 }
 
 func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, format SpinnerUxType) {
-	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
-		// Spinner is disabled when using json format.
+	if c.isStructuredOutput() {
+		// Spinner is disabled when using structured formats.
 		return
 	}
 
@@ -424,9 +791,15 @@ func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, form
 
 	c.spinnerLineMu.Lock()
 	c.spinnerCurrentTitle = ""
+	c.setSpinnerShowElapsed(false)
+	if c.spinnerHeartbeatStop != nil {
+		close(c.spinnerHeartbeatStop)
+		c.spinnerHeartbeatStop = nil
+	}
 	// Update style according to MessageUxType
 	if lastMessage != "" {
 		lastMessage = c.getStopChar(format) + " " + lastMessage
+		c.writeTranscript(lastMessage)
 	}
 
 	c.spinner.StopMessage(lastMessage)
@@ -442,13 +815,15 @@ func (c *AskerConsole) IsSpinnerInteractive() bool {
 	return c.spinnerTerminalMode&yacspin.ForceTTYMode > 0
 }
 
-var donePrefix string = output.WithSuccessFormat("(✓) Done:")
+func (c *AskerConsole) IsNoPromptMode() bool {
+	return c.noPrompt
+}
 
 func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
 	var stopChar string
 	switch format {
 	case StepDone:
-		stopChar = donePrefix
+		stopChar = output.WithSuccessFormat("(✓) Done:")
 	case StepFailed:
 		stopChar = output.WithErrorFormat("(x) Failed:")
 	case StepWarning:
@@ -459,11 +834,31 @@ func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
 	return fmt.Sprintf("%s%s", c.getIndent(format), stopChar)
 }
 
-func promptFromOptions(options ConsoleOptions) survey.Prompt {
+func promptFromOptions(options ConsoleOptions) (survey.Prompt, []survey.AskOpt) {
+	var opts []survey.AskOpt
+	if options.Validate != nil || options.Required {
+		opts = append(opts, survey.WithValidator(func(ans interface{}) error {
+			value, ok := ans.(string)
+			if !ok {
+				return nil
+			}
+
+			if options.Required && strings.TrimSpace(value) == "" {
+				return errValueRequired
+			}
+
+			if options.Validate != nil {
+				return options.Validate(value)
+			}
+
+			return nil
+		}))
+	}
+
 	if options.IsPassword {
 		return &survey.Password{
 			Message: options.Message,
-		}
+		}, opts
 	}
 
 	var defaultValue string
@@ -475,7 +870,7 @@ func promptFromOptions(options ConsoleOptions) survey.Prompt {
 		Default: defaultValue,
 		Help:    options.Help,
 		Suggest: options.Suggest,
-	}
+	}, opts
 }
 
 // cAfterIO is a sentinel used after Input/Output operations as the state for the last 2-bytes written.
@@ -483,12 +878,42 @@ func promptFromOptions(options ConsoleOptions) survey.Prompt {
 // 0 in the sentinel), followed by a new line.
 const cAfterIO = "0\n"
 
+// errValueRequired is the error shown when no value is entered for a prompt with the Required option
+var errValueRequired = errors.New("a value is required")
+
+// ResolveDefaultValue returns options with DefaultValue set to the value returned by DefaultValueFn, and DefaultValueFn
+// cleared, or options as they are when DefaultValueFn isn't set. It's called by consoles before they show a prompt.
+func ResolveDefaultValue(ctx context.Context, options ConsoleOptions) (ConsoleOptions, error) {
+	if options.DefaultValueFn == nil {
+		return options, nil
+	}
+
+	value, err := options.DefaultValueFn(ctx)
+	if err != nil {
+		return options, fmt.Errorf("computing the default value of prompt '%s': %w", options.Message, err)
+	}
+
+	options.DefaultValue = value
+	options.DefaultValueFn = nil
+	return options, nil
+}
+
 // Prompts the user for a single value
 func (c *AskerConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
 	var response string
 
-	err := c.doInteraction(func(c *AskerConsole) error {
-		return c.asker(promptFromOptions(options), &response)
+	options, err := ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return "", err
+	}
+
+	if defaultValue, _ := options.DefaultValue.(string); c.noPrompt && options.Required && defaultValue == "" {
+		return "", fmt.Errorf("prompt '%s': %w", options.Message, errValueRequired)
+	}
+
+	err = c.doInteraction(func(c *AskerConsole) error {
+		prompt, opts := promptFromOptions(options)
+		return c.asker(prompt, &response, opts...)
 	})
 	if err != nil {
 		return response, err
@@ -497,18 +922,81 @@ func (c *AskerConsole) Prompt(ctx context.Context, options ConsoleOptions) (stri
 	return response, nil
 }
 
+// Prompts the user for a single integer value
+func (c *AskerConsole) PromptInt(ctx context.Context, options ConsoleOptions) (int, error) {
+	options, err := ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return 0, err
+	}
+
+	intOptions := options
+	switch value := options.DefaultValue.(type) {
+	case int:
+		intOptions.DefaultValue = strconv.Itoa(value)
+	case nil, string:
+	default:
+		return 0, fmt.Errorf("default value for prompt '%s' is not an integer: %v", options.Message, value)
+	}
+
+	intOptions.Validate = func(value string) error {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid integer", value)
+		}
+
+		if options.MinValue != nil && number < *options.MinValue {
+			return fmt.Errorf("value must be greater than or equal to %d", *options.MinValue)
+		}
+
+		if options.MaxValue != nil && number > *options.MaxValue {
+			return fmt.Errorf("value must be less than or equal to %d", *options.MaxValue)
+		}
+
+		if options.Validate != nil {
+			return options.Validate(value)
+		}
+
+		return nil
+	}
+
+	// When prompting is disabled, Prompt returns the (validated) default value, or an error when there is none.
+	response, err := c.Prompt(ctx, intOptions)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(response)
+}
+
 // Prompts the user to select from a set of values
 func (c *AskerConsole) Select(ctx context.Context, options ConsoleOptions) (int, error) {
+	options, err := ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	selectOptions, err := c.filterOptions(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
+	var defaultValue any
+	if value, ok := options.DefaultValue.(string); ok && slices.Contains(selectOptions, value) {
+		defaultValue = value
+	} else if options.Filter == nil {
+		defaultValue = options.DefaultValue
+	}
+
 	survey := &survey.Select{
 		Message: options.Message,
-		Options: options.Options,
-		Default: options.DefaultValue,
+		Options: selectOptions,
+		Default: defaultValue,
 		Help:    options.Help,
 	}
 
-	var response int
+	var response string
 
-	err := c.doInteraction(func(c *AskerConsole) error {
+	err = c.doInteraction(func(c *AskerConsole) error {
 		return c.asker(survey, &response)
 	})
 	if err != nil {
@@ -516,31 +1004,139 @@ func (c *AskerConsole) Select(ctx context.Context, options ConsoleOptions) (int,
 	}
 
 	c.updateLastBytes(cAfterIO)
-	return response, nil
+
+	index := slices.Index(options.Options, response)
+	if index == -1 {
+		return -1, fmt.Errorf("prompt '%s': the selected option '%s' is not one of the options", options.Message, response)
+	}
+
+	return index, nil
+}
+
+// filterOptions returns the options to select from. When there is a Filter, the user is asked for a query first, with the
+// options matching what they type suggested, and the options Filter returns for the query are selected from. An empty
+// query selects from Options, and is only accepted when there are Options. A query without matches is asked again.
+func (c *AskerConsole) filterOptions(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	if options.Filter == nil || c.noPrompt {
+		return options.Options, nil
+	}
+
+	for {
+		query, err := c.Prompt(ctx, ConsoleOptions{
+			Message:  "Search the options:",
+			Help:     "Type part of an option and press Tab to see the matching options.",
+			Suggest:  options.Filter,
+			Required: len(options.Options) == 0,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		query = strings.TrimSpace(query)
+		if query == "" {
+			return options.Options, nil
+		}
+
+		if matches := options.Filter(query); len(matches) > 0 {
+			return matches, nil
+		}
+
+		c.Message(ctx, output.WithWarningFormat("No options match '%s'.", query))
+	}
 }
 
 func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
-	survey := &survey.MultiSelect{
-		Message: options.Message,
-		Options: options.Options,
-		Default: options.DefaultValue,
-		Help:    options.Help,
+	options, err := ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return nil, err
 	}
 
-	var response []string
+	var defaultValue any
+	if options.DefaultValue != nil {
+		selected, ok := options.DefaultValue.([]string)
+		if !ok {
+			return nil, fmt.Errorf("default value for prompt '%s' is not a string list", options.Message)
+		}
 
-	err := c.doInteraction(func(c *AskerConsole) error {
-		return c.asker(survey, &response)
-	})
+		// only pre-select values which are part of the options.
+		defaults := []string{}
+		for _, value := range selected {
+			if slices.Contains(options.Options, value) {
+				defaults = append(defaults, value)
+			}
+		}
+		defaultValue = defaults
+	}
+
+	selectOptions, err := c.filterOptions(ctx, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return response, nil
+	if defaults, ok := defaultValue.([]string); ok && options.Filter != nil {
+		defaultValue = slices.DeleteFunc(defaults, func(value string) bool {
+			return !slices.Contains(selectOptions, value)
+		})
+	}
+
+	for {
+		survey := &survey.MultiSelect{
+			Message: options.Message,
+			Options: selectOptions,
+			Default: defaultValue,
+			Help:    options.Help,
+		}
+
+		var response []string
+
+		err := c.doInteraction(func(c *AskerConsole) error {
+			return c.asker(survey, &response)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		countErr := validateSelectionCount(response, options)
+		if countErr == nil {
+			return response, nil
+		}
+
+		if c.noPrompt {
+			return nil, fmt.Errorf("prompt '%s': %w", options.Message, countErr)
+		}
+
+		// keep what the user picked so far and ask again.
+		c.Message(ctx, output.WithErrorFormat(countErr.Error()))
+		defaultValue = response
+	}
+}
+
+// validateSelectionCount checks the number of selected values against the MinSelections and MaxSelections options.
+func validateSelectionCount(selected []string, options ConsoleOptions) error {
+	if options.MinSelections > 0 && len(selected) < options.MinSelections {
+		return fmt.Errorf("select at least %d option(s)", options.MinSelections)
+	}
+
+	if options.MaxSelections > 0 && len(selected) > options.MaxSelections {
+		return fmt.Errorf("select at most %d option(s)", options.MaxSelections)
+	}
+
+	return nil
 }
 
 // Prompts the user to confirm an operation
 func (c *AskerConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
+	// --yes answers confirmations even when --no-prompt is also set, which would answer with the default value instead.
+	if c.assumeYes {
+		log.Printf("confirming '%s' without prompting", options.Message)
+		return true, nil
+	}
+
+	options, err := ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return false, err
+	}
+
 	var defaultValue bool
 	if value, ok := options.DefaultValue.(bool); ok {
 		defaultValue = value
@@ -554,7 +1150,7 @@ func (c *AskerConsole) Confirm(ctx context.Context, options ConsoleOptions) (boo
 
 	var response bool
 
-	err := c.doInteraction(func(c *AskerConsole) error {
+	err = c.doInteraction(func(c *AskerConsole) error {
 		return c.asker(survey, &response)
 	})
 	if err != nil {
@@ -581,15 +1177,53 @@ func (c *AskerConsole) EnsureBlankLine(ctx context.Context) {
 
 // wait until the next enter
 func (c *AskerConsole) WaitForEnter() {
+	c.WaitForEnterCtx(context.Background())
+}
+
+// WaitForEnterCtx blocks until the next enter, or until ctx is cancelled or its deadline elapses, in which case azd
+// proceeds without the input. It returns immediately when prompting is disabled.
+func (c *AskerConsole) WaitForEnterCtx(ctx context.Context) {
 	if c.noPrompt {
 		return
 	}
 
-	inputScanner := bufio.NewScanner(c.handles.Stdin)
-	if scan := inputScanner.Scan(); !scan {
-		if err := inputScanner.Err(); err != nil {
+	// the spinner is paused while waiting, so that it doesn't hide the instructions printed before
+	_ = c.doInteraction(func(c *AskerConsole) error {
+		if err := readLine(ctx, c.handles.Stdin); ctx.Err() != nil {
+			log.Printf("stopped waiting for enter, proceeding without input: %v", ctx.Err())
+		} else if err != nil && !errors.Is(err, io.EOF) {
 			log.Printf("error while waiting for enter: %v", err)
 		}
+		return nil
+	})
+}
+
+// readLineInBackground reads stdin up to the next newline, or until ctx is done, in which case ctx.Err() is returned.
+// Readers other than files can't be interrupted, so the read continues in the background when ctx is done first.
+func readLineInBackground(ctx context.Context, stdin io.Reader) error {
+	read := make(chan error, 1)
+	go func() {
+		read <- readLineFrom(stdin)
+	}()
+
+	select {
+	case err := <-read:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// readLineFrom reads r up to the next newline. A single byte is read at a time so the input after the newline is left
+// for the next reader.
+func readLineFrom(r io.Reader) error {
+	buf := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		} else if buf[0] == '\n' {
+			return nil
+		}
 	}
 }
 
@@ -607,48 +1241,106 @@ func getConsoleWidth() int {
 	return width
 }
 
+// handleResize updates the console width and redraws the spinner and progress bar. It is a no-op when the width is
+// unchanged.
 func (c *AskerConsole) handleResize(width int) {
-	c.consoleWidth.Store(int32(width))
+	if c.consoleWidth.Swap(int32(width)) == int32(width) {
+		return
+	}
 
 	c.spinnerLineMu.Lock()
 	if c.spinner.Status() == yacspin.SpinnerRunning {
-		line := c.spinnerLine(c.spinnerCurrentTitle, c.currentIndent.Load())
+		line := c.spinnerLine(c.spinnerCurrentTitle, c.currentIndent.Load(), c.spinnerElapsed())
 		c.spinner.Message(line.Message)
 		_ = c.spinner.CharSet(line.CharSet)
 		c.spinner.Prefix(line.Prefix)
 	}
 	c.spinnerLineMu.Unlock()
+
+	c.showProgressMu.Lock()
+	if c.progress != nil {
+		c.progress.redraw()
+	}
+	c.showProgressMu.Unlock()
 }
 
-func watchConsoleWidth(c *AskerConsole) {
+// The default interval between console width checks on Windows, where there is no resize signal.
+const defaultResizePollInterval = 250 * time.Millisecond
+
+// resizePollInterval returns the interval between console width checks, or 0 when the console width is not polled.
+// AZD_TERM_RESIZE_POLL_MS overrides the interval on all platforms, which is useful on terminals (ex. some terminal
+// multiplexers) that do not deliver SIGWINCH. Setting it to 0 disables polling.
+func resizePollInterval() time.Duration {
+	defaultInterval := time.Duration(0)
 	if runtime.GOOS == "windows" {
-		go func() {
-			prevWidth := getConsoleWidth()
-			for {
-				time.Sleep(time.Millisecond * 250)
-				width := getConsoleWidth()
-
-				if prevWidth != width {
-					c.handleResize(width)
-				}
-				prevWidth = width
-			}
-		}()
-	} else {
+		defaultInterval = defaultResizePollInterval
+	}
+
+	strVal, has := os.LookupEnv("AZD_TERM_RESIZE_POLL_MS")
+	if !has {
+		return defaultInterval
+	}
+
+	ms, err := strconv.Atoi(strVal)
+	if err != nil || ms < 0 {
+		log.Printf("ignoring invalid value for AZD_TERM_RESIZE_POLL_MS: %q", strVal)
+		return defaultInterval
+	}
+
+	return time.Duration(ms) * time.Millisecond
+}
+
+// watchConsoleWidth calls handleResize whenever the console is resized, until the console is closed.
+func watchConsoleWidth(c *AskerConsole, pollInterval time.Duration) {
+	var signalChan chan os.Signal
+	if runtime.GOOS != "windows" {
 		// avoid taking a dependency on syscall.SIGWINCH (unix-only constant) directly
 		const SIGWINCH = syscall.Signal(0x1c)
-		signalChan := make(chan os.Signal, 1)
+		signalChan = make(chan os.Signal, 1)
 		signal.Notify(signalChan, SIGWINCH)
-		go func() {
-			for range signalChan {
-				c.handleResize(getConsoleWidth())
-			}
-		}()
+		defer signal.Stop(signalChan)
+	}
+
+	// a nil channel is never ready, so the ticker case is disabled when polling is disabled
+	var tick <-chan time.Time
+	if pollInterval > 0 {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
 	}
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-signalChan:
+			c.handleResize(getConsoleWidth())
+		case <-tick:
+			c.handleResize(getConsoleWidth())
+		}
+	}
+}
+
+// Close stops watching for console resizes. The console can still be written to after it is closed.
+func (c *AskerConsole) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	return nil
 }
 
-// Creates a new console with the specified writer, handles and formatter.
-func NewConsole(noPrompt bool, isTerminal bool, w io.Writer, handles ConsoleHandles, formatter output.Formatter) Console {
+// NewConsole creates a console that writes to w. When noPrompt is true, prompts return their default value, or fail when
+// there is no default value. When assumeYes is true, confirmations are answered yes without prompting, while the other
+// prompts are unaffected.
+func NewConsole(
+	noPrompt bool,
+	assumeYes bool,
+	isTerminal bool,
+	w io.Writer,
+	handles ConsoleHandles,
+	formatter output.Formatter,
+) Console {
 	asker := NewAsker(noPrompt, isTerminal, handles.Stdout, handles.Stdin)
 
 	c := &AskerConsole{
@@ -661,10 +1353,15 @@ func NewConsole(noPrompt bool, isTerminal bool, w io.Writer, handles ConsoleHand
 		consoleWidth:  atomic.NewInt32(int32(getConsoleWidth())),
 		currentIndent: atomic.NewString(""),
 		noPrompt:      noPrompt,
+		assumeYes:     assumeYes,
+		closed:        make(chan struct{}),
+		quietSpinner:  quietSpinner(),
+
+		spinnerHeartbeatInterval: spinnerHeartbeatInterval(),
 	}
 
 	spinnerConfig := yacspin.Config{
-		Frequency:    200 * time.Millisecond,
+		Frequency:    spinnerFrequency,
 		Writer:       c.writer,
 		Suffix:       " ",
 		TerminalMode: spinnerTerminalMode(isTerminal),
@@ -673,7 +1370,7 @@ func NewConsole(noPrompt bool, isTerminal bool, w io.Writer, handles ConsoleHand
 	c.spinner, _ = yacspin.New(spinnerConfig)
 	c.spinnerTerminalMode = spinnerConfig.TerminalMode
 
-	go watchConsoleWidth(c)
+	go watchConsoleWidth(c, resizePollInterval())
 	return c
 }
 
@@ -3,7 +3,14 @@
 
 package input
 
-import "log"
+import (
+	"bytes"
+	"log"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
 
 // ConsolePreviewerWriter implements io.Writer and is used to wrap a progress log
 // and panic if the writer is used after the previewer is stopped.
@@ -11,9 +18,22 @@ type consolePreviewerWriter struct {
 	// holds the address of a previously created progressLog
 	// when the references progressLog becomes nil, this component should write no more.
 	previewer **progressLog
+	// holds the address of a previously created logEventWriter, used instead of the progressLog when the console
+	// writes json. When the referenced logEventWriter becomes nil, this component should write no more.
+	events **logEventWriter
 }
 
 func (cp *consolePreviewerWriter) Write(logBytes []byte) (int, error) {
+	if cp.events != nil {
+		events := *cp.events
+		if events == nil {
+			//dev-bug - tried to write to a closed console previewer
+			log.Panic("tried to write to a closed console previewer.")
+		}
+
+		return events.Write(logBytes)
+	}
+
 	writer := *cp.previewer
 	if writer == nil {
 		//dev-bug - tried to write to a closed console previewer
@@ -22,3 +42,45 @@ func (cp *consolePreviewerWriter) Write(logBytes []byte) (int, error) {
 
 	return writer.Write(logBytes)
 }
+
+// logEventWriter implements io.Writer, emitting each line written to it as a log event, instead of showing the lines in
+// a progress log.
+type logEventWriter struct {
+	phase string
+	emit  func(event contracts.EventEnvelope)
+
+	// secures pending, as the output of a tool may be written from multiple goroutines
+	mu sync.Mutex
+	// the last line written, which isn't emitted until it is terminated by a newline or the writer is flushed
+	pending []byte
+}
+
+func (w *logEventWriter) Write(logBytes []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, logBytes...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := bytes.TrimSuffix(w.pending[:idx], []byte("\r"))
+		w.emit(output.EventForLog(w.phase, string(line)))
+		w.pending = w.pending[idx+1:]
+	}
+
+	return len(logBytes), nil
+}
+
+// Flush emits the last line written when it is not terminated by a newline.
+func (w *logEventWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		w.emit(output.EventForLog(w.phase, string(w.pending)))
+		w.pending = nil
+	}
+}
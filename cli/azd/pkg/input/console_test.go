@@ -0,0 +1,920 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/stretchr/testify/require"
+	"github.com/theckman/yacspin"
+)
+
+func newTestConsole(noPrompt bool, stdin string) Console {
+	stdout := &bytes.Buffer{}
+	return NewConsole(noPrompt, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{})
+}
+
+func TestPromptInt(t *testing.T) {
+	minValue := 1
+	maxValue := 10
+
+	t.Run("Reprompts", func(t *testing.T) {
+		console := newTestConsole(false, "abc\n42\n5\n")
+		value, err := console.PromptInt(context.Background(), ConsoleOptions{
+			Message:  "Replicas:",
+			MinValue: &minValue,
+			MaxValue: &maxValue,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, value)
+	})
+
+	t.Run("NoPromptDefault", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		value, err := console.PromptInt(context.Background(), ConsoleOptions{
+			Message:      "Replicas:",
+			DefaultValue: 3,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, value)
+	})
+
+	t.Run("NoPromptDefaultOutOfBounds", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		_, err := console.PromptInt(context.Background(), ConsoleOptions{
+			Message:      "Replicas:",
+			DefaultValue: 30,
+			MaxValue:     &maxValue,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("NoPromptNoDefault", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		_, err := console.PromptInt(context.Background(), ConsoleOptions{
+			Message: "Replicas:",
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestPromptRequired(t *testing.T) {
+	t.Run("Reprompts", func(t *testing.T) {
+		console := newTestConsole(false, "\n  \nvalue\n")
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:  "Name:",
+			Required: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "value", value)
+	})
+
+	t.Run("WithValidate", func(t *testing.T) {
+		console := newTestConsole(false, "\ninvalid\nvalid\n")
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:  "Name:",
+			Required: true,
+			Validate: func(value string) error {
+				if value != "valid" {
+					return errors.New("not valid")
+				}
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "valid", value)
+	})
+
+	t.Run("NoPromptDefault", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:      "Name:",
+			DefaultValue: "default",
+			Required:     true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "default", value)
+	})
+
+	t.Run("NoPromptNoDefault", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		_, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:    "Password:",
+			IsPassword: true,
+			Required:   true,
+		})
+		require.ErrorContains(t, err, "prompt 'Password:': a value is required")
+	})
+}
+
+func TestConfirmAssumeYes(t *testing.T) {
+	newAssumeYesConsole := func(noPrompt bool, stdin string) Console {
+		stdout := &bytes.Buffer{}
+		return NewConsole(noPrompt, true, false, stdout, ConsoleHandles{
+			Stdin:  strings.NewReader(stdin),
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+	}
+
+	t.Run("ConfirmsWithoutPrompting", func(t *testing.T) {
+		console := newAssumeYesConsole(false, "n\n")
+		confirmed, err := console.Confirm(context.Background(), ConsoleOptions{
+			Message:      "Delete the resources?",
+			DefaultValue: false,
+		})
+		require.NoError(t, err)
+		require.True(t, confirmed)
+
+		// the answer to the confirmation isn't read, and other prompts still read their value
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message: "Name:",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "n", value)
+	})
+
+	t.Run("PrecedesNoPrompt", func(t *testing.T) {
+		console := newAssumeYesConsole(true, "")
+		confirmed, err := console.Confirm(context.Background(), ConsoleOptions{
+			Message:      "Delete the resources?",
+			DefaultValue: false,
+		})
+		require.NoError(t, err)
+		require.True(t, confirmed)
+	})
+}
+
+func TestMultiSelectSelectionCount(t *testing.T) {
+	t.Run("Reprompts", func(t *testing.T) {
+		console := newTestConsole(false, "n\nn\ny\nn\n")
+		selected, err := console.MultiSelect(context.Background(), ConsoleOptions{
+			Message:       "Select services",
+			Options:       []string{"api", "web"},
+			DefaultValue:  []string{},
+			MinSelections: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api"}, selected)
+	})
+
+	t.Run("NoPromptDefaults", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		selected, err := console.MultiSelect(context.Background(), ConsoleOptions{
+			Message:       "Select services",
+			Options:       []string{"api", "web"},
+			DefaultValue:  []string{"web", "unknown"},
+			MaxSelections: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"web"}, selected)
+	})
+
+	t.Run("NoPromptTooFew", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		_, err := console.MultiSelect(context.Background(), ConsoleOptions{
+			Message:       "Select services",
+			Options:       []string{"api", "web"},
+			DefaultValue:  []string{},
+			MinSelections: 1,
+		})
+		require.ErrorContains(t, err, "select at least 1 option(s)")
+	})
+}
+
+func TestSelectFilter(t *testing.T) {
+	locations := []string{"eastus", "eastus2", "westus", "westus2"}
+	filter := func(query string) []string {
+		matches := []string{}
+		for _, location := range locations {
+			if strings.HasPrefix(location, query) {
+				matches = append(matches, location)
+			}
+		}
+		return matches
+	}
+
+	t.Run("Select", func(t *testing.T) {
+		// the first query has no matches, so it is asked again
+		console := newTestConsole(false, "north\neast\neastus2\n")
+		selected, err := console.Select(context.Background(), ConsoleOptions{
+			Message: "Select a location",
+			Options: locations,
+			Filter:  filter,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, selected)
+	})
+
+	t.Run("SelectEmptyQuery", func(t *testing.T) {
+		console := newTestConsole(false, "\nwestus\n")
+		selected, err := console.Select(context.Background(), ConsoleOptions{
+			Message: "Select a location",
+			Options: locations,
+			Filter: func(query string) []string {
+				require.Fail(t, "filter called without a query")
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, selected)
+	})
+
+	t.Run("SelectOnlyFromMatches", func(t *testing.T) {
+		console := newTestConsole(false, "west\neastus\n")
+		_, err := console.Select(context.Background(), ConsoleOptions{
+			Message: "Select a location",
+			Options: locations,
+			Filter:  filter,
+		})
+		require.ErrorContains(t, err, "'eastus' is not an allowed choice")
+	})
+
+	t.Run("MultiSelectWithoutOptions", func(t *testing.T) {
+		// the options are only supplied by the filter, so an empty query isn't accepted
+		console := newTestConsole(false, "west\nn\ny\n")
+		selected, err := console.MultiSelect(context.Background(), ConsoleOptions{
+			Message:      "Select locations",
+			Filter:       filter,
+			DefaultValue: []string{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"westus2"}, selected)
+	})
+
+	t.Run("NoPromptDefaults", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		selected, err := console.Select(context.Background(), ConsoleOptions{
+			Message:      "Select a location",
+			Options:      locations,
+			DefaultValue: "westus",
+			Filter: func(query string) []string {
+				require.Fail(t, "filter called without prompting")
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, selected)
+	})
+}
+
+func TestDefaultValueFn(t *testing.T) {
+	t.Run("Prompt", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:      "Branch:",
+			DefaultValue: "main",
+			DefaultValueFn: func(ctx context.Context) (any, error) {
+				return "feature", nil
+			},
+			Required: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "feature", value)
+	})
+
+	t.Run("PromptInt", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		value, err := console.PromptInt(context.Background(), ConsoleOptions{
+			Message: "Replicas:",
+			DefaultValueFn: func(ctx context.Context) (any, error) {
+				return 3, nil
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, value)
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		console := newTestConsole(true, "")
+		selected, err := console.Select(context.Background(), ConsoleOptions{
+			Message: "Select a location",
+			Options: []string{"eastus", "westus"},
+			DefaultValueFn: func(ctx context.Context) (any, error) {
+				return "westus", nil
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, selected)
+	})
+
+	t.Run("Confirm", func(t *testing.T) {
+		calls := 0
+		console := newTestConsole(true, "")
+		confirmed, err := console.Confirm(context.Background(), ConsoleOptions{
+			Message: "Continue?",
+			DefaultValueFn: func(ctx context.Context) (any, error) {
+				calls++
+				return true, nil
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, confirmed)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		console := newTestConsole(false, "value\n")
+		_, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:      "Branch:",
+			DefaultValue: "main",
+			DefaultValueFn: func(ctx context.Context) (any, error) {
+				return nil, errors.New("not a git repository")
+			},
+		})
+		require.ErrorContains(t, err, "computing the default value of prompt 'Branch:': not a git repository")
+	})
+}
+
+func TestShowProgressNonInteractive(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{})
+
+	progress := console.ShowProgress(context.Background(), "Pushing image")
+	progress.Report(0.1, "layer 1")
+	// reported again within the message interval, so it is not printed.
+	progress.Report(0.5, "layer 2")
+	progress.Report(1, "")
+	progress.Stop()
+
+	require.Equal(t, "Pushing image: 10% layer 1\nPushing image: 100%\n", stdout.String())
+}
+
+func TestShowProgressJson(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.JsonFormatter{})
+
+	progress := console.ShowProgress(context.Background(), "Pushing image")
+	progress.Report(0.5, "layer 2")
+	progress.Stop()
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], "Pushing image: 0%")
+}
+
+func TestShowPreviewerJson(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.JsonFormatter{})
+
+	previewer := console.ShowPreviewer(context.Background(), &ShowPreviewerOptions{Title: "Docker Output"})
+	_, err := previewer.Write([]byte("Step 1/2 : FROM node:18\r\nStep 2/2"))
+	require.NoError(t, err)
+	_, err = previewer.Write([]byte(" : COPY . .\n\x1b[32mSuccessfully built\x1b[0m"))
+	require.NoError(t, err)
+	console.StopPreviewer(context.Background(), false)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 3)
+
+	messages := []contracts.LogMessage{}
+	for _, line := range lines {
+		var event struct {
+			Type contracts.EventDataType `json:"type"`
+			Data contracts.LogMessage    `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		require.Equal(t, contracts.LogEventDataType, event.Type)
+		messages = append(messages, event.Data)
+	}
+
+	require.Equal(t, []contracts.LogMessage{
+		{Phase: "Docker Output", Message: "Step 1/2 : FROM node:18"},
+		{Phase: "Docker Output", Message: "Step 2/2 : COPY . ."},
+		// the last line is emitted when the previewer is stopped
+		{Phase: "Docker Output", Message: "Successfully built"},
+	}, messages)
+
+	require.Panics(t, func() {
+		_, _ = previewer.Write([]byte("after stop\n"))
+	})
+}
+
+func TestShowPreviewerYaml(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.YamlFormatter{})
+
+	previewer := console.ShowPreviewer(context.Background(), &ShowPreviewerOptions{Title: "Docker Output"})
+	_, err := previewer.Write([]byte("Step 1/2 : FROM node:18\n"))
+	require.NoError(t, err)
+	console.StopPreviewer(context.Background(), false)
+
+	// the lines are emitted as yaml documents, without the frames of the previewer
+	require.True(t, strings.HasPrefix(stdout.String(), "---\n"))
+	require.Contains(t, stdout.String(), "Step 1/2 : FROM node:18")
+	require.NotContains(t, stdout.String(), "Docker Output:")
+}
+
+func TestWaitForEnterCtx(t *testing.T) {
+	t.Run("Enter", func(t *testing.T) {
+		console := newTestConsole(false, "\n")
+		console.WaitForEnterCtx(context.Background())
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		stdin, stdinWriter := io.Pipe()
+		defer stdinWriter.Close()
+
+		stdout := &bytes.Buffer{}
+		console := NewConsole(false, false, false, stdout, ConsoleHandles{
+			Stdin:  stdin,
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// no input is ever written, so this only returns once the deadline elapses
+		console.WaitForEnterCtx(ctx)
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("CancelledFile", func(t *testing.T) {
+		stdin, stdinWriter, err := os.Pipe()
+		require.NoError(t, err)
+		defer stdin.Close()
+		defer stdinWriter.Close()
+
+		stdout := &bytes.Buffer{}
+		console := NewConsole(false, false, false, stdout, ConsoleHandles{
+			Stdin:  stdin,
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		console.WaitForEnterCtx(ctx)
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+		// the cancelled wait doesn't leave a read behind that would consume the next line
+		_, err = stdinWriter.WriteString("next\n")
+		require.NoError(t, err)
+
+		line := make([]byte, 5)
+		_, err = io.ReadFull(stdin, line)
+		require.NoError(t, err)
+		require.Equal(t, "next\n", string(line))
+	})
+
+	t.Run("NoPrompt", func(t *testing.T) {
+		stdin, stdinWriter := io.Pipe()
+		defer stdinWriter.Close()
+
+		stdout := &bytes.Buffer{}
+		console := NewConsole(true, false, false, stdout, ConsoleHandles{
+			Stdin:  stdin,
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+
+		console.WaitForEnterCtx(context.Background())
+	})
+}
+
+func TestMessageUxItemMarkdown(t *testing.T) {
+	markdown := &ux.Markdown{Content: "# Next Steps\n\nRun `azd up`."}
+
+	t.Run("NotTerminal", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		console := NewConsole(false, false, false, stdout, ConsoleHandles{
+			Stdin:  strings.NewReader(""),
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+
+		console.MessageUxItem(context.Background(), markdown)
+		require.Equal(t, "# Next Steps\n\nRun `azd up`.\n", stdout.String())
+	})
+
+	t.Run("Terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		stdout := &bytes.Buffer{}
+		console := NewConsole(false, false, true, stdout, ConsoleHandles{
+			Stdin:  strings.NewReader(""),
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+
+		console.MessageUxItem(context.Background(), markdown)
+		require.Equal(t, "Next Steps\n\nRun azd up.\n", stdout.String())
+	})
+
+	t.Run("NoColor", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		stdout := &bytes.Buffer{}
+		console := NewConsole(false, false, true, stdout, ConsoleHandles{
+			Stdin:  strings.NewReader(""),
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.NoneFormatter{})
+
+		console.MessageUxItem(context.Background(), markdown)
+		require.Equal(t, "# Next Steps\n\nRun `azd up`.\n", stdout.String())
+	})
+
+	t.Run("Json", func(t *testing.T) {
+		stdout := &bytes.Buffer{}
+		console := NewConsole(false, false, true, stdout, ConsoleHandles{
+			Stdin:  strings.NewReader(""),
+			Stdout: stdout,
+			Stderr: &bytes.Buffer{},
+		}, &output.JsonFormatter{})
+
+		console.MessageUxItem(context.Background(), markdown)
+
+		var event struct {
+			Data contracts.ConsoleMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &event))
+		require.Equal(t, "# Next Steps\n\nRun `azd up`.\n", event.Data.Message)
+	})
+}
+
+func TestAddTranscript(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{})
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.log")
+	transcript, err := os.Create(transcriptPath)
+	require.NoError(t, err)
+	defer transcript.Close()
+
+	ctx := context.Background()
+	console.AddTranscript(transcript)
+
+	console.Message(ctx, "\x1b[32mProvisioning\x1b[0m resources")
+	previewer := console.ShowPreviewer(ctx, nil)
+	_, err = previewer.Write([]byte("previewer log\n"))
+	require.NoError(t, err)
+	console.Message(ctx, "Message while previewing")
+	console.StopPreviewer(ctx, false)
+
+	console.MessageUxItem(ctx, &ux.WarningMessage{Description: "\x1b[33mquota\x1b[0m is low"})
+	console.ShowSpinner(ctx, "Deploying", Step)
+	console.StopSpinner(ctx, "Deploying", StepDone)
+
+	require.Contains(t, stdout.String(), "\x1b[32mProvisioning\x1b[0m resources")
+
+	content, err := os.ReadFile(transcriptPath)
+	require.NoError(t, err)
+	require.Equal(t,
+		"Provisioning resources\n"+
+			"Message while previewing\n"+
+			(&ux.WarningMessage{Description: "quota is low"}).ToString("")+"\n"+
+			"  (✓) Done: Deploying\n",
+		string(content))
+}
+
+func TestResizePollInterval(t *testing.T) {
+	defaultInterval := time.Duration(0)
+	if runtime.GOOS == "windows" {
+		defaultInterval = defaultResizePollInterval
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		require.Equal(t, defaultInterval, resizePollInterval())
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		t.Setenv("AZD_TERM_RESIZE_POLL_MS", "100")
+		require.Equal(t, 100*time.Millisecond, resizePollInterval())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Setenv("AZD_TERM_RESIZE_POLL_MS", "0")
+		require.Equal(t, time.Duration(0), resizePollInterval())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv("AZD_TERM_RESIZE_POLL_MS", "fast")
+		require.Equal(t, defaultInterval, resizePollInterval())
+	})
+}
+
+func TestWatchConsoleWidthStopsOnClose(t *testing.T) {
+	console := newTestConsole(false, "").(*AskerConsole)
+
+	stopped := make(chan struct{})
+	go func() {
+		watchConsoleWidth(console, time.Millisecond)
+		close(stopped)
+	}()
+
+	require.NoError(t, console.Close())
+	// closing again is a no-op
+	require.NoError(t, console.Close())
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "watchConsoleWidth did not stop after the console was closed")
+	}
+}
+
+func TestQuietSpinner(t *testing.T) {
+	showSteps := func(t *testing.T, console *AskerConsole, expectedTitle string) {
+		ctx := context.Background()
+		for _, service := range []string{"api", "web", "worker"} {
+			console.ShowSpinner(ctx, "Packaging service "+service, Step)
+		}
+		require.Equal(t, expectedTitle, console.spinnerCurrentTitle)
+		console.StopSpinner(ctx, "Packaging services", StepDone)
+
+		// the next step shows its first title again
+		console.ShowSpinner(ctx, "Deploying service api", Step)
+		require.Equal(t, "Deploying service api", console.spinnerCurrentTitle)
+		console.StopSpinner(ctx, "Deploying services", StepDone)
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		console := newTestConsole(false, "").(*AskerConsole)
+		showSteps(t, console, "Packaging service worker")
+	})
+
+	t.Run("Quiet", func(t *testing.T) {
+		t.Setenv("AZD_QUIET_SPINNER", "true")
+		console := newTestConsole(false, "").(*AskerConsole)
+		showSteps(t, console, "Packaging service api")
+	})
+}
+
+func TestSpinnerLineElapsed(t *testing.T) {
+	console := newTestConsole(false, "").(*AskerConsole)
+	title := "Deploying service api"
+
+	tcs := []struct {
+		name     string
+		width    int
+		elapsed  string
+		expected string
+	}{
+		{
+			name:     "NoElapsed",
+			width:    80,
+			expected: "Deploying service api",
+		},
+		{
+			name:     "Elapsed",
+			width:    80,
+			elapsed:  "1m12s",
+			expected: "Deploying service api (1m12s)",
+		},
+		{
+			name:     "TruncatesTitle",
+			width:    40,
+			elapsed:  "1m12s",
+			expected: "Deploying service... (1m12s)",
+		},
+		{
+			name:     "DropsElapsed",
+			width:    20,
+			elapsed:  "1m12s",
+			expected: "Deplo...",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			console.consoleWidth.Store(int32(tc.width))
+			line := console.spinnerLine(title, "  ", tc.elapsed)
+			require.Equal(t, tc.expected, line.Message)
+			require.LessOrEqual(t, len(line.Prefix)+len(line.CharSet[0])+1+len(line.Message), tc.width)
+		})
+	}
+}
+
+func TestShowSpinnerElapsed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NonInteractive", func(t *testing.T) {
+		console := newTestConsole(false, "").(*AskerConsole)
+		console.ShowSpinner(ctx, "Deploying service api", StepElapsed)
+		defer console.StopSpinner(ctx, "", Step)
+
+		require.False(t, console.spinnerShowElapsed)
+		require.Empty(t, console.spinnerElapsed())
+	})
+
+	t.Run("Interactive", func(t *testing.T) {
+		console := newTestConsole(false, "").(*AskerConsole)
+		console.spinnerTerminalMode = yacspin.ForceTTYMode | yacspin.ForceDumbTerminalMode
+		defer console.Close()
+
+		console.ShowSpinner(ctx, "Deploying service api", StepElapsed)
+		require.True(t, console.spinnerShowElapsed)
+		require.NotNil(t, console.spinnerElapsedStop)
+
+		// the timer keeps running while the title is unchanged, and is reset when the title changes
+		console.spinnerLineMu.Lock()
+		console.spinnerTitleStart = time.Now().Add(-72 * time.Second)
+		console.spinnerLineMu.Unlock()
+
+		console.ShowSpinner(ctx, "Deploying service api", StepElapsed)
+		console.spinnerLineMu.Lock()
+		require.Equal(t, "1m12s", console.spinnerElapsed())
+		console.spinnerLineMu.Unlock()
+
+		console.ShowSpinner(ctx, "Deploying service web", StepElapsed)
+		console.spinnerLineMu.Lock()
+		require.Equal(t, "0s", console.spinnerElapsed())
+		console.spinnerLineMu.Unlock()
+
+		console.StopSpinner(ctx, "Deploying services", StepDone)
+		require.False(t, console.spinnerShowElapsed)
+		require.Nil(t, console.spinnerElapsedStop)
+	})
+}
+
+func TestSpinnerHeartbeatInterval(t *testing.T) {
+	require.Equal(t, defaultSpinnerHeartbeatInterval, spinnerHeartbeatInterval())
+
+	t.Setenv("AZD_SPINNER_HEARTBEAT_SECONDS", "30")
+	require.Equal(t, 30*time.Second, spinnerHeartbeatInterval())
+
+	t.Setenv("AZD_SPINNER_HEARTBEAT_SECONDS", "0")
+	require.Equal(t, time.Duration(0), spinnerHeartbeatInterval())
+
+	t.Setenv("AZD_SPINNER_HEARTBEAT_SECONDS", "soon")
+	require.Equal(t, defaultSpinnerHeartbeatInterval, spinnerHeartbeatInterval())
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use, since the spinner writes from its own goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinnerHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	stdout := &syncBuffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{}).(*AskerConsole)
+	defer console.Close()
+	console.spinnerHeartbeatInterval = 10 * time.Millisecond
+
+	console.ShowSpinner(ctx, "Provisioning resources", Step)
+	require.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), cSpinnerHeartbeat)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	console.StopSpinner(ctx, "Provisioned resources", StepDone)
+	require.Nil(t, console.spinnerHeartbeatStop)
+
+	// no more messages are printed once the spinner is stopped
+	printed := stdout.String()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, printed, stdout.String())
+}
+
+func TestSpinnerHeartbeatJson(t *testing.T) {
+	console := NewConsole(false, false, false, &bytes.Buffer{}, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	}, &output.JsonFormatter{}).(*AskerConsole)
+	defer console.Close()
+	console.spinnerHeartbeatInterval = 10 * time.Millisecond
+
+	console.ShowSpinner(context.Background(), "Provisioning resources", Step)
+	require.Nil(t, console.spinnerHeartbeatStop)
+}
+
+func TestSafeLog(t *testing.T) {
+	ctx := context.Background()
+	stdout := &syncBuffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{}).(*AskerConsole)
+	defer console.Close()
+
+	// an interactive spinner that paints often, so its frames are written while the messages are logged
+	console.spinnerTerminalMode = yacspin.ForceTTYMode | yacspin.ForceSmartTerminalMode
+	spinner, err := yacspin.New(yacspin.Config{
+		Frequency:    time.Millisecond,
+		Writer:       stdout,
+		Suffix:       " ",
+		TerminalMode: console.spinnerTerminalMode,
+		CharSet:      spinnerCharSet,
+	})
+	require.NoError(t, err)
+	console.spinner = spinner
+
+	console.ShowSpinner(ctx, "Deploying services", Step)
+
+	const writers = 8
+	const messages = 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < messages; j++ {
+				console.SafeLog(fmt.Sprintf("message %d-%d", i, j))
+			}
+		}(i)
+	}
+
+	// the title of the spinner is updated while the messages are logged
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < messages; j++ {
+			console.ShowSpinner(ctx, fmt.Sprintf("Deploying service %d", j), Step)
+		}
+	}()
+
+	wg.Wait()
+	console.StopSpinner(ctx, "Deployed services", StepDone)
+
+	logged := map[string]bool{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if !strings.Contains(line, "message ") {
+			continue
+		}
+
+		// the line of the spinner is erased before the message is written, and nothing is written after it
+		erased := strings.LastIndex(line, "\033[K")
+		require.GreaterOrEqual(t, erased, 0, "spinner line wasn't erased before %q", line)
+		message := line[erased+len("\033[K"):]
+		require.Regexp(t, `^message \d+-\d+$`, message)
+		require.False(t, logged[message], "%q was logged twice", message)
+		logged[message] = true
+	}
+	require.Len(t, logged, writers*messages)
+}
+
+func TestSafeLogNoSpinner(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	transcript := &bytes.Buffer{}
+	console := NewConsole(false, false, false, stdout, ConsoleHandles{
+		Stdin:  strings.NewReader(""),
+		Stdout: stdout,
+		Stderr: &bytes.Buffer{},
+	}, &output.NoneFormatter{}).(*AskerConsole)
+	defer console.Close()
+	console.AddTranscript(transcript)
+
+	console.SafeLog("resized console")
+	require.Equal(t, "resized console\n", stdout.String())
+	require.Equal(t, "resized console\n", transcript.String())
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// ProgressReporter reports the completion of an operation started with Console.ShowProgress.
+type ProgressReporter interface {
+	// Report updates the completed fraction of the operation, between 0 and 1, and the message displayed with it.
+	Report(fraction float64, msg string)
+	// Stop ends the progress display. Report must not be called after Stop.
+	Stop()
+}
+
+// the minimum time between two percentage messages when the progress bar can't be redrawn in place.
+const progressMessageInterval = 5 * time.Second
+
+type progressMode int
+
+const (
+	// the bar is redrawn in place on each report.
+	progressModeInteractive progressMode = iota
+	// a percentage message is printed periodically.
+	progressModeMessages
+	// nothing is displayed after the initial event.
+	progressModeNone
+)
+
+type consoleProgress struct {
+	ctx     context.Context
+	console *AskerConsole
+	mode    progressMode
+
+	// secures bar, lastMessageTime and lastPercentage
+	mu              sync.Mutex
+	bar             ux.ProgressBar
+	lastMessageTime time.Time
+	lastPercentage  int
+}
+
+// Shows a progress bar with the given title, pausing any active spinner until the returned reporter is stopped.
+func (c *AskerConsole) ShowProgress(ctx context.Context, title string) ProgressReporter {
+	c.showProgressMu.Lock()
+	defer c.showProgressMu.Unlock()
+
+	if c.isStructuredOutput() {
+		// progress is not reported for structured formats, only that the operation started.
+		c.MessageUxItem(ctx, &ux.ProgressBar{Title: title})
+		return &consoleProgress{ctx: ctx, console: c, mode: progressModeNone}
+	}
+
+	mode := progressModeMessages
+	if c.IsSpinnerInteractive() {
+		mode = progressModeInteractive
+	}
+
+	_ = c.spinner.Pause()
+
+	progress := &consoleProgress{
+		ctx:            ctx,
+		console:        c,
+		mode:           mode,
+		bar:            ux.ProgressBar{Title: title},
+		lastPercentage: -1,
+	}
+	c.progress = progress
+
+	if mode == progressModeInteractive {
+		progress.redraw()
+	}
+
+	return progress
+}
+
+func (p *consoleProgress) Report(fraction float64, msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.bar.Fraction = fraction
+	p.bar.Message = msg
+
+	switch p.mode {
+	case progressModeInteractive:
+		p.redrawLocked()
+	case progressModeMessages:
+		percentage := p.bar.Percentage()
+		if percentage == p.lastPercentage {
+			return
+		}
+
+		if percentage < 100 && p.lastPercentage >= 0 && time.Since(p.lastMessageTime) < progressMessageInterval {
+			return
+		}
+
+		p.lastPercentage = percentage
+		p.lastMessageTime = time.Now()
+
+		message := fmt.Sprintf("%s%s: %d%%", p.console.currentIndent.Load(), p.bar.Title, percentage)
+		if p.bar.Message != "" {
+			message += " " + p.bar.Message
+		}
+		p.console.Message(p.ctx, message)
+	}
+}
+
+func (p *consoleProgress) Stop() {
+	if p.mode == progressModeNone {
+		return
+	}
+
+	c := p.console
+	c.showProgressMu.Lock()
+	defer c.showProgressMu.Unlock()
+
+	if c.progress != p {
+		// already stopped
+		return
+	}
+
+	p.mu.Lock()
+	if p.mode == progressModeInteractive {
+		p.redrawLocked()
+		fmt.Fprintln(c.writer)
+		c.updateLastBytes(cAfterIO)
+	}
+	p.mu.Unlock()
+
+	c.progress = nil
+	_ = c.spinner.Unpause()
+}
+
+// redraw renders the bar again, for example after the console was resized.
+func (p *consoleProgress) redraw() {
+	if p.mode != progressModeInteractive {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.redrawLocked()
+}
+
+func (p *consoleProgress) redrawLocked() {
+	// leave the last column empty so the line does not wrap.
+	p.bar.Width = int(p.console.consoleWidth.Load()) - 1
+	line := p.bar.ToString(p.console.currentIndent.Load())
+
+	// move to the start of the line, write the bar and clear whatever is left from the previous (longer) line.
+	fmt.Fprintf(p.console.writer, "\r%s\033[K", line)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build !windows
+// +build !windows
+
+package input
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// readLine reads stdin up to the next newline, or until ctx is done, in which case ctx.Err() is returned. When stdin is a
+// file, it's only read once select reports input, so that no read is left pending, which would consume the next line, when
+// ctx is done first.
+func readLine(ctx context.Context, stdin io.Reader) error {
+	file, ok := stdin.(*os.File)
+	if !ok {
+		return readLineInBackground(ctx, stdin)
+	}
+
+	fd := int(file.Fd())
+	buf := make([]byte, 1)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fds := &unix.FdSet{}
+		fds.Set(fd)
+		timeout := unix.NsecToTimeval((100 * time.Millisecond).Nanoseconds())
+		n, err := unix.Select(fd+1, fds, nil, nil, &timeout)
+		if errors.Is(err, unix.EINTR) || (err == nil && n == 0) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		// a single byte is read at a time so the input after the newline is left for the next reader
+		if _, err := file.Read(buf); err != nil {
+			return err
+		} else if buf[0] == '\n' {
+			return nil
+		}
+	}
+}
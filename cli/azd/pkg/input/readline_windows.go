@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build windows
+// +build windows
+
+package input
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// readLine reads stdin up to the next newline, or until ctx is done, in which case ctx.Err() is returned. When stdin is a
+// file, the pending read is cancelled when ctx is done first, so that it doesn't consume the next line.
+func readLine(ctx context.Context, stdin io.Reader) error {
+	file, ok := stdin.(*os.File)
+	if !ok {
+		return readLineInBackground(ctx, stdin)
+	}
+
+	read := make(chan error, 1)
+	go func() {
+		read <- readLineFrom(file)
+	}()
+
+	select {
+	case err := <-read:
+		return err
+	case <-ctx.Done():
+		if err := windows.CancelIoEx(windows.Handle(file.Fd()), nil); err != nil {
+			// the read couldn't be cancelled, and continues in the background
+			return ctx.Err()
+		}
+
+		<-read
+		return ctx.Err()
+	}
+}
@@ -2,12 +2,17 @@
 // 1. Easier usage of lazy type resolvers and ability to register specific type instances
 // 2. Support for hierarchical/nested containers to resolve types from parent containers
 // 3. Helper methods for easier/streamlined usage of of the IoC container
+// 4. Scoped lifetimes, with instances created once per scope and disposed when the scope is closed
 package ioc
 
 import (
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"reflect"
 	"regexp"
+	"sync"
 
 	"github.com/golobby/container/v3"
 )
@@ -18,10 +23,7 @@ var (
 	containerErrorRegex *regexp.Regexp = regexp.MustCompile("container:")
 
 	// The global/root level container
-	Global *NestedContainer = &NestedContainer{
-		inner:  container.Global,
-		parent: nil,
-	}
+	Global *NestedContainer = newGlobalContainer()
 
 	ErrResolveInstance error = errors.New("failed resolving instance from container")
 )
@@ -31,22 +33,84 @@ var (
 type NestedContainer struct {
 	inner  container.Container
 	parent *NestedContainer
+
+	// The resolvers registered with a scoped lifetime, bound again in each new scope
+	scoped []any
+
+	// The scoped instances created by this container, disposed when it is closed
+	instancesMu sync.Mutex
+	instances   []any
 }
 
 // Creates a new nested container from the specified parent container
 func NewNestedContainer(parent *NestedContainer) *NestedContainer {
 	current := container.New()
+	var scoped []any
 	if parent != nil {
-		// Copy the resolvers to the new container
+		// Copy the resolvers to the new container, registrations in the new container don't change the parent
 		for key, value := range parent.inner {
-			current[key] = value
+			current[key] = maps.Clone(value)
 		}
+		scoped = append(scoped, parent.scoped...)
 	}
 
-	return &NestedContainer{
+	nested := &NestedContainer{
 		inner:  current,
 		parent: parent,
+		scoped: scoped,
 	}
+	// Resolvers can depend on the container resolving them, like the scope of the command
+	RegisterInstance(nested, nested)
+
+	return nested
+}
+
+func newGlobalContainer() *NestedContainer {
+	global := &NestedContainer{
+		inner:  container.Global,
+		parent: nil,
+	}
+	RegisterInstance(global, global)
+
+	return global
+}
+
+// Creates a new scope from the container, a nested container where each resolver registered with a scoped lifetime
+// creates a new instance, shared by all resolutions in the scope. Close the scope to dispose of its instances.
+func (c *NestedContainer) NewScope() *NestedContainer {
+	scope := NewNestedContainer(c)
+	for _, resolveFn := range scope.scoped {
+		scope.bindScoped(resolveFn)
+	}
+
+	return scope
+}
+
+// Disposes of the scoped instances created by the container, in the reverse order of their creation. Instances are
+// disposed by calling Close or Dispose when they implement either.
+// Returns the errors of disposing the instances
+func (c *NestedContainer) Close() error {
+	c.instancesMu.Lock()
+	instances := c.instances
+	c.instances = nil
+	c.instancesMu.Unlock()
+
+	var errs []error
+	for i := len(instances) - 1; i >= 0; i-- {
+		var err error
+		switch instance := instances[i].(type) {
+		case io.Closer:
+			err = instance.Close()
+		case disposable:
+			err = instance.Dispose()
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("disposing %T: %w", instances[i], err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // Registers a resolver with a singleton lifetime
@@ -68,6 +132,15 @@ func (c *NestedContainer) RegisterNamedSingleton(name string, resolveFn any) err
 	return c.inner.NamedSingletonLazy(name, resolveFn)
 }
 
+// Registers a resolver with a scoped lifetime (instance per scope)
+// Resolutions outside of a scope created with NewScope share the instance of the container the resolver is registered in.
+// Singletons that depend on a scoped instance keep the instance of the scope they're first resolved in.
+// Panics if the resolver is not valid
+func (c *NestedContainer) RegisterScoped(resolveFn any) {
+	c.bindScoped(resolveFn)
+	c.scoped = append(c.scoped, resolveFn)
+}
+
 // Registers a resolver with a transient lifetime (instance per resolution)
 // Returns an error if the resolver is not valid
 func (c *NestedContainer) RegisterTransient(resolveFn any) {
@@ -90,7 +163,9 @@ func (c *NestedContainer) Resolve(instance any) error {
 			return nil
 		}
 
-		if current.parent == nil {
+		// Only registrations missing from the current container are resolved from its parent, errors returned by
+		// the resolvers are returned as is
+		if current.parent == nil || !isContainerError(err) {
 			return inspectResolveError(err)
 		}
 		current = current.parent
@@ -107,7 +182,9 @@ func (c *NestedContainer) ResolveNamed(name string, instance any) error {
 			return nil
 		}
 
-		if current.parent == nil {
+		// Only registrations missing from the current container are resolved from its parent, errors returned by
+		// the resolvers are returned as is
+		if current.parent == nil || !isContainerError(err) {
 			return inspectResolveError(err)
 		}
 		current = current.parent
@@ -136,11 +213,49 @@ func RegisterNamedInstance[F any](c *NestedContainer, name string, instance F) {
 	})
 }
 
+// Binds the scoped resolver as a singleton of the container, tracking the instance it creates to dispose of it
+// when the container is closed
+func (c *NestedContainer) bindScoped(resolveFn any) {
+	resolver := reflect.ValueOf(resolveFn)
+	if resolver.Kind() != reflect.Func {
+		// Let the container panic with its validation error
+		container.MustSingletonLazy(c.inner, resolveFn)
+		return
+	}
+
+	tracked := reflect.MakeFunc(resolver.Type(), func(args []reflect.Value) []reflect.Value {
+		results := resolver.Call(args)
+		if len(results) == 2 && !results[1].IsNil() {
+			return results
+		}
+
+		if len(results) > 0 && results[0].IsValid() && results[0].CanInterface() {
+			c.instancesMu.Lock()
+			c.instances = append(c.instances, results[0].Interface())
+			c.instancesMu.Unlock()
+		}
+
+		return results
+	})
+
+	container.MustSingletonLazy(c.inner, tracked.Interface())
+}
+
+// Returns whether the error is a container registration error rather than an error returned by a resolver
+func isContainerError(err error) bool {
+	return containerErrorRegex.Match([]byte(err.Error()))
+}
+
+// disposable is implemented by scoped instances that release their resources with Dispose
+type disposable interface {
+	Dispose() error
+}
+
 // Inspects the specified error to determine whether the error is a
 // developer container registration error or an error that was
 // returned while instantiating a dependency.
 func inspectResolveError(err error) error {
-	if containerErrorRegex.Match([]byte(err.Error())) {
+	if isContainerError(err) {
 		return fmt.Errorf("%w: %w", ErrResolveInstance, err)
 	}
 
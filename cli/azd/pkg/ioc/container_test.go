@@ -49,3 +49,119 @@ func Test_Resolve(t *testing.T) {
 		require.True(t, errors.Is(err, azdcontext.ErrNoProject))
 	})
 }
+
+func Test_RegisterScoped(t *testing.T) {
+	t.Run("InstancePerScope", func(t *testing.T) {
+		root := NewNestedContainer(nil)
+		root.RegisterScoped(func() *scopedResource {
+			return &scopedResource{}
+		})
+
+		scope := root.NewScope()
+		var first, second *scopedResource
+		require.NoError(t, scope.Resolve(&first))
+		require.NoError(t, NewNestedContainer(scope).Resolve(&second))
+		require.Same(t, first, second)
+
+		otherScope := root.NewScope()
+		var other *scopedResource
+		require.NoError(t, otherScope.Resolve(&other))
+		require.NotSame(t, first, other)
+
+		var rootInstance *scopedResource
+		require.NoError(t, root.Resolve(&rootInstance))
+		require.NotSame(t, first, rootInstance)
+		require.NotSame(t, other, rootInstance)
+	})
+
+	t.Run("DisposedOnClose", func(t *testing.T) {
+		root := NewNestedContainer(nil)
+		closed := []string{}
+		root.RegisterScoped(func() *scopedResource {
+			return &scopedResource{onClose: func() error {
+				closed = append(closed, "resource")
+				return nil
+			}}
+		})
+		root.RegisterScoped(func(resource *scopedResource) *scopedDisposable {
+			return &scopedDisposable{onDispose: func() error {
+				closed = append(closed, "disposable")
+				return errors.New("dispose failed")
+			}}
+		})
+
+		scope := root.NewScope()
+		var instance *scopedDisposable
+		require.NoError(t, scope.Resolve(&instance))
+		require.Empty(t, closed)
+
+		err := scope.Close()
+		require.ErrorContains(t, err, "dispose failed")
+		// Disposed in the reverse order of creation
+		require.Equal(t, []string{"disposable", "resource"}, closed)
+
+		// Instances are only disposed once
+		require.NoError(t, scope.Close())
+		require.Len(t, closed, 2)
+	})
+
+	t.Run("FailedResolutionNotDisposed", func(t *testing.T) {
+		root := NewNestedContainer(nil)
+		root.RegisterScoped(func() (*scopedResource, error) {
+			return nil, errors.New("resolve failed")
+		})
+
+		scope := root.NewScope()
+		var instance *scopedResource
+		require.ErrorContains(t, scope.Resolve(&instance), "resolve failed")
+		require.NoError(t, scope.Close())
+	})
+}
+
+func Test_NewNestedContainer_RegistrationsDontChangeParent(t *testing.T) {
+	parent := NewNestedContainer(nil)
+	RegisterInstance(parent, "parent")
+
+	child := NewNestedContainer(parent)
+	RegisterInstance(child, "child")
+
+	var instance string
+	require.NoError(t, parent.Resolve(&instance))
+	require.Equal(t, "parent", instance)
+
+	require.NoError(t, child.Resolve(&instance))
+	require.Equal(t, "child", instance)
+}
+
+type scopedResource struct {
+	onClose func() error
+}
+
+func (r *scopedResource) Close() error {
+	if r.onClose == nil {
+		return nil
+	}
+
+	return r.onClose()
+}
+
+type scopedDisposable struct {
+	onDispose func() error
+}
+
+func (d *scopedDisposable) Dispose() error {
+	return d.onDispose()
+}
+
+func Test_Resolve_NestedResolverError(t *testing.T) {
+	parent := NewNestedContainer(nil)
+	parent.RegisterSingleton(azdcontext.NewAzdContext)
+
+	child := NewNestedContainer(parent)
+
+	// The error of the resolver is returned instead of resolving the instance again from the parent
+	var instance *azdcontext.AzdContext
+	err := child.Resolve(&instance)
+	require.ErrorIs(t, err, azdcontext.ErrNoProject)
+	require.False(t, errors.Is(err, ErrResolveInstance))
+}
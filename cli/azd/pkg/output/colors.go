@@ -8,28 +8,28 @@ import (
 
 // withLinkFormat creates string with hyperlink-looking color
 func WithLinkFormat(link string, a ...interface{}) string {
-	return color.HiCyanString(link, a...)
+	return colorString(currentPalette().link, link, a...)
 }
 
 // withHighLightFormat creates string with highlight-looking color
 func WithHighLightFormat(text string, a ...interface{}) string {
-	return color.CyanString(text, a...)
+	return colorString(currentPalette().highlight, text, a...)
 }
 
 func WithErrorFormat(text string, a ...interface{}) string {
-	return color.RedString(text, a...)
+	return colorString(currentPalette().error, text, a...)
 }
 
 func WithWarningFormat(text string, a ...interface{}) string {
-	return color.YellowString(text, a...)
+	return colorString(currentPalette().warning, text, a...)
 }
 
 func WithSuccessFormat(text string, a ...interface{}) string {
-	return color.GreenString(text, a...)
+	return colorString(currentPalette().success, text, a...)
 }
 
 func WithGrayFormat(text string, a ...interface{}) string {
-	return color.HiBlackString(text, a...)
+	return colorString(currentPalette().gray, text, a...)
 }
 
 func WithBold(text string, a ...interface{}) string {
@@ -13,8 +13,10 @@ type Format string
 const (
 	EnvVarsFormat Format = "dotenv"
 	JsonFormat    Format = "json"
+	YamlFormat    Format = "yaml"
 	TableFormat   Format = "table"
 	NoneFormat    Format = "none"
+	ShellFormat   Format = "shell"
 )
 
 type Formatter interface {
@@ -26,12 +28,16 @@ func NewFormatter(format string) (Formatter, error) {
 	switch format {
 	case string(JsonFormat):
 		return &JsonFormatter{}, nil
+	case string(YamlFormat):
+		return &YamlFormatter{}, nil
 	case string(EnvVarsFormat):
 		return &EnvVarsFormatter{}, nil
 	case string(TableFormat):
 		return &TableFormatter{}, nil
 	case string(NoneFormat):
 		return &NoneFormatter{}, nil
+	case string(ShellFormat):
+		return &ShellFormatter{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported format %v", format)
 	}
@@ -46,19 +46,48 @@ var _ Formatter = (*JsonFormatter)(nil)
 // jsonObjectForMessage creates a json object representing a message. Any ANSI control sequences from the message are
 // removed. A trailing newline is added to the message.
 func EventForMessage(message string) contracts.EventEnvelope {
-	// Strip any ANSI colors for the message.
+	// Add the newline that would have been added by fmt.Println when we wrote the message directly to the console.
+	return newConsoleMessageEvent(withoutColors(message) + "\n")
+}
+
+// EventForError creates an error event for a failed command, with the code that classifies the failure and optional
+// details. Any ANSI control sequences from the message are removed.
+func EventForError(code string, message string, details map[string]any) contracts.EventEnvelope {
+	return contracts.EventEnvelope{
+		Type:      contracts.ErrorEventDataType,
+		Timestamp: time.Now(),
+		Data: contracts.ErrorMessage{
+			Code:    code,
+			Message: withoutColors(message),
+			Details: details,
+		},
+	}
+}
+
+// EventForLog creates a log event for a line of the output of a tool, produced during the given phase. Any ANSI control
+// sequences from the line are removed.
+func EventForLog(phase string, line string) contracts.EventEnvelope {
+	return contracts.EventEnvelope{
+		Type:      contracts.LogEventDataType,
+		Timestamp: time.Now(),
+		Data: contracts.LogMessage{
+			Phase:   phase,
+			Message: withoutColors(line),
+		},
+	}
+}
+
+// withoutColors returns the message with any ANSI colors removed.
+func withoutColors(message string) string {
 	var buf bytes.Buffer
 
 	// We do not expect the io.Copy to fail since none of these sub-calls will ever return an error (other than
 	// EOF when we hit the end of the string)
 	if _, err := io.Copy(colorable.NewNonColorable(&buf), strings.NewReader(message)); err != nil {
-		panic(fmt.Sprintf("consoleMessageForMessage: did not expect error from io.Copy but got: %v", err))
+		panic(fmt.Sprintf("withoutColors: did not expect error from io.Copy but got: %v", err))
 	}
 
-	// Add the newline that would have been added by fmt.Println when we wrote the message directly to the console.
-	buf.WriteByte('\n')
-
-	return newConsoleMessageEvent(buf.String())
+	return buf.String()
 }
 
 func newConsoleMessageEvent(msg string) contracts.EventEnvelope {
@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// shellNameRegex matches the names of POSIX shell variables
+var shellNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ShellFormatter formats a map[string]string as POSIX shell `export KEY='VALUE'` lines sorted by key, to be evaluated by
+// sh, bash and zsh.
+type ShellFormatter struct {
+}
+
+func (f *ShellFormatter) Kind() Format {
+	return ShellFormat
+}
+
+func (f *ShellFormatter) Format(obj interface{}, writer io.Writer, _ interface{}) error {
+	values, ok := obj.(map[string]string)
+	if !ok {
+		return fmt.Errorf("ShellFormatter can only format objects of type map[string]string")
+	}
+
+	keys := maps.Keys(values)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if !shellNameRegex.MatchString(key) {
+			return fmt.Errorf("could not format values: %s is not a valid shell variable name", key)
+		}
+
+		if _, err := fmt.Fprintf(writer, "export %s=%s\n", key, shellQuote(values[key])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shellQuote single quotes value, so the shell doesn't expand any of its characters. A single quote can't be escaped
+// inside single quotes, so it ends the quoted string, adds an escaped quote and starts a new quoted string.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+var _ Formatter = (*ShellFormatter)(nil)
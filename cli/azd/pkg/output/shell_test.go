@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellFormatterStringMap(t *testing.T) {
+	formatter := &ShellFormatter{}
+
+	m := map[string]string{
+		"Charlie": "it's $HOME",
+		"Alpha":   "1",
+		"Bravo":   "line1\nline2",
+	}
+
+	buffer := &bytes.Buffer{}
+	err := formatter.Format(m, buffer, nil)
+	require.NoError(t, err)
+
+	expected := "export Alpha='1'\nexport Bravo='line1\nline2'\nexport Charlie='it'\\''s $HOME'\n"
+	require.Equal(t, expected, buffer.String())
+}
+
+func TestShellFormatterInvalidName(t *testing.T) {
+	formatter := &ShellFormatter{}
+
+	buffer := &bytes.Buffer{}
+	err := formatter.Format(map[string]string{"NOT-VALID": "1"}, buffer, nil)
+	require.Error(t, err)
+}
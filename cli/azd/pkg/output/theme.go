@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// Theme is the color theme used by the output formatters, such as WithSuccessFormat and WithLinkFormat.
+type Theme string
+
+const (
+	// ThemeDark is the default theme, suited to terminals with a dark background.
+	ThemeDark Theme = "dark"
+	// ThemeLight is suited to terminals with a light background.
+	ThemeLight Theme = "light"
+	// ThemeHighContrast uses bright, bold colors.
+	ThemeHighContrast Theme = "high-contrast"
+)
+
+// DefaultTheme is the theme used when no theme is selected.
+const DefaultTheme = ThemeDark
+
+// palette holds the colors of a theme.
+type palette struct {
+	link      *color.Color
+	highlight *color.Color
+	error     *color.Color
+	warning   *color.Color
+	success   *color.Color
+	gray      *color.Color
+}
+
+var palettes = map[Theme]*palette{
+	ThemeDark: {
+		link:      color.New(color.FgHiCyan),
+		highlight: color.New(color.FgCyan),
+		error:     color.New(color.FgRed),
+		warning:   color.New(color.FgYellow),
+		success:   color.New(color.FgGreen),
+		gray:      color.New(color.FgHiBlack),
+	},
+	ThemeLight: {
+		link:      color.New(color.FgBlue),
+		highlight: color.New(color.FgBlue),
+		error:     color.New(color.FgRed),
+		warning:   color.New(color.FgMagenta),
+		success:   color.New(color.FgGreen),
+		gray:      color.New(color.FgBlack),
+	},
+	ThemeHighContrast: {
+		link:      color.New(color.FgHiCyan, color.Underline),
+		highlight: color.New(color.FgHiCyan, color.Bold),
+		error:     color.New(color.FgHiRed, color.Bold),
+		warning:   color.New(color.FgHiYellow, color.Bold),
+		success:   color.New(color.FgHiGreen, color.Bold),
+		gray:      color.New(color.FgHiWhite),
+	},
+}
+
+var currentTheme atomic.Pointer[Theme]
+
+// ParseTheme returns the theme with the given name, ignoring case.
+func ParseTheme(value string) (Theme, error) {
+	theme := Theme(strings.ToLower(strings.TrimSpace(value)))
+	if _, has := palettes[theme]; !has {
+		return "", fmt.Errorf(
+			"unsupported theme '%s', supported themes are: %s, %s, %s", value, ThemeDark, ThemeLight, ThemeHighContrast)
+	}
+
+	return theme, nil
+}
+
+// SetTheme sets the theme used by the output formatters. Unknown themes select the default theme.
+func SetTheme(theme Theme) {
+	if _, has := palettes[theme]; !has {
+		theme = DefaultTheme
+	}
+
+	currentTheme.Store(&theme)
+}
+
+// CurrentTheme returns the theme used by the output formatters.
+func CurrentTheme() Theme {
+	if theme := currentTheme.Load(); theme != nil {
+		return *theme
+	}
+
+	return DefaultTheme
+}
+
+func currentPalette() *palette {
+	return palettes[CurrentTheme()]
+}
+
+// colorString formats the text with the color. Like the fatih/color string helpers, the text is used as is when there
+// are no arguments.
+func colorString(c *color.Color, text string, a ...interface{}) string {
+	if len(a) == 0 {
+		return c.SprintFunc()(text)
+	}
+
+	return c.SprintfFunc()(text, a...)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeFormats(t *testing.T) {
+	originalNoColor := color.NoColor
+	t.Cleanup(func() {
+		color.NoColor = originalNoColor
+		SetTheme(DefaultTheme)
+	})
+	color.NoColor = false
+
+	formats := []struct {
+		name   string
+		format func(text string, a ...interface{}) string
+	}{
+		{"link", WithLinkFormat},
+		{"highlight", WithHighLightFormat},
+		{"error", WithErrorFormat},
+		{"warning", WithWarningFormat},
+		{"success", WithSuccessFormat},
+		{"gray", WithGrayFormat},
+	}
+
+	tests := map[Theme][]string{
+		ThemeDark:         {"\x1b[96m", "\x1b[36m", "\x1b[31m", "\x1b[33m", "\x1b[32m", "\x1b[90m"},
+		ThemeLight:        {"\x1b[34m", "\x1b[34m", "\x1b[31m", "\x1b[35m", "\x1b[32m", "\x1b[30m"},
+		ThemeHighContrast: {"\x1b[96;4m", "\x1b[96;1m", "\x1b[91;1m", "\x1b[93;1m", "\x1b[92;1m", "\x1b[97m"},
+	}
+
+	for theme, codes := range tests {
+		t.Run(string(theme), func(t *testing.T) {
+			SetTheme(theme)
+			require.Equal(t, theme, CurrentTheme())
+
+			for i, f := range formats {
+				require.Equal(t, codes[i]+"100% done\x1b[0m", f.format("100% done"), f.name)
+				require.Equal(t, codes[i]+"2 done\x1b[0m", f.format("%d done", 2), f.name)
+			}
+		})
+	}
+
+	t.Run("NoColor", func(t *testing.T) {
+		color.NoColor = true
+		defer func() { color.NoColor = false }()
+
+		for _, theme := range []Theme{ThemeDark, ThemeLight, ThemeHighContrast} {
+			SetTheme(theme)
+			for _, f := range formats {
+				require.Equal(t, "done", f.format("done"), f.name)
+			}
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		SetTheme("solarized")
+		require.Equal(t, DefaultTheme, CurrentTheme())
+	})
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme(" High-Contrast ")
+	require.NoError(t, err)
+	require.Equal(t, ThemeHighContrast, theme)
+
+	_, err = ParseTheme("solarized")
+	require.ErrorContains(t, err, "unsupported theme 'solarized'")
+}
@@ -14,6 +14,10 @@ type ActionResult struct {
 	SuccessMessage string
 	FollowUp       string
 	Err            error
+	// ErrCode classifies Err in the error event written for structured output. Defaults to 'error' when empty.
+	ErrCode string
+	// ErrDetails are the optional details of Err in the error event written for structured output.
+	ErrDetails map[string]any
 }
 
 func (ar *ActionResult) ToString(currentIndentation string) (result string) {
@@ -31,7 +35,11 @@ func (ar *ActionResult) ToString(currentIndentation string) (result string) {
 
 func (ar *ActionResult) MarshalJSON() ([]byte, error) {
 	if ar.Err != nil {
-		return json.Marshal(output.EventForMessage(ar.Err.Error()))
+		code := ar.ErrCode
+		if code == "" {
+			code = "error"
+		}
+		return json.Marshal(output.EventForError(code, ar.Err.Error(), ar.ErrDetails))
 	}
 	result := ""
 	if ar.SuccessMessage != "" {
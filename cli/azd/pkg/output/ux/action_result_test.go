@@ -4,6 +4,7 @@
 package ux
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -46,3 +47,37 @@ func TestActionResult_ToString(t *testing.T) {
 		})
 	}
 }
+
+func TestActionResult_MarshalJSON(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		ar := &ActionResult{
+			Err:        errors.New(output.WithErrorFormat("deployment failed")),
+			ErrCode:    "deploymentFailed",
+			ErrDetails: map[string]any{"traceId": "trace"},
+		}
+
+		var event struct {
+			Type string `json:"type"`
+			Data struct {
+				Code    string         `json:"code"`
+				Message string         `json:"message"`
+				Details map[string]any `json:"details"`
+			} `json:"data"`
+		}
+		b, err := json.Marshal(ar)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &event))
+
+		require.Equal(t, "error", event.Type)
+		require.Equal(t, "deploymentFailed", event.Data.Code)
+		require.Equal(t, "deployment failed", event.Data.Message)
+		require.Equal(t, map[string]any{"traceId": "trace"}, event.Data.Details)
+	})
+
+	t.Run("ErrorWithoutCode", func(t *testing.T) {
+		b, err := json.Marshal(&ActionResult{Err: errors.New("failed")})
+		require.NoError(t, err)
+		require.Contains(t, string(b), `"type":"error"`)
+		require.Contains(t, string(b), `"data":{"code":"error","message":"failed"}`)
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// CommandOutput shows the end of the output of a command that failed, such as the tail kept by exec.ExitError when the
+// output is too large to include in the error.
+type CommandOutput struct {
+	// The name of the command
+	Cmd string
+	// The end of the combined stdout and stderr output of the command
+	Output string
+}
+
+func (c *CommandOutput) ToString(currentIndentation string) string {
+	outputLines := strings.Split(strings.TrimRight(c.Output, "\r\n"), "\n")
+	lines := make([]string, 0, len(outputLines)+1)
+	lines = append(lines, fmt.Sprintf("%s%s Last output of %s:", currentIndentation, failedPrefix(), c.Cmd))
+
+	for _, line := range outputLines {
+		lines = append(lines, fmt.Sprintf("%s  %s", currentIndentation, strings.TrimRight(line, "\r")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (c *CommandOutput) MarshalJSON() ([]byte, error) {
+	// reusing the same envelope from console messages
+	return json.Marshal(output.EventForMessage(fmt.Sprintf("Last output of %s: %s", c.Cmd, c.Output)))
+}
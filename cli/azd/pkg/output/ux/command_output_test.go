@@ -0,0 +1,30 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandOutput(t *testing.T) {
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = originalNoColor
+	})
+
+	commandOutput := &CommandOutput{
+		Cmd:    "npm",
+		Output: "npm ERR! code ELIFECYCLE\r\nnpm ERR! errno 1\n",
+	}
+
+	require.Equal(t,
+		"(x) Failed: Last output of npm:\n"+
+			"  npm ERR! code ELIFECYCLE\n"+
+			"  npm ERR! errno 1",
+		commandOutput.ToString(""))
+}
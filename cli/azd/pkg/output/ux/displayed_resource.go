@@ -28,11 +28,11 @@ func (cr *DisplayedResource) ToString(currentIndentation string) string {
 
 	switch cr.State {
 	case SucceededState:
-		prefix = donePrefix
+		prefix = donePrefix()
 	case FailedState:
-		prefix = failedPrefix
+		prefix = failedPrefix()
 	default:
-		prefix = donePrefix
+		prefix = donePrefix()
 	}
 
 	return fmt.Sprintf("%s%s %s: %s", currentIndentation, prefix, cr.Type, cr.Name)
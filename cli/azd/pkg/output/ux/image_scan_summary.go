@@ -0,0 +1,69 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// ImageScanSummary shows the number of vulnerabilities, by severity, found by the scan of a container image.
+type ImageScanSummary struct {
+	Scanner  string
+	Image    string
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+	Unknown  int
+}
+
+func (s *ImageScanSummary) ToString(currentIndentation string) string {
+	counts := s.counts()
+	lines := make([]string, 0, len(counts)+1)
+	lines = append(lines, fmt.Sprintf("%sVulnerabilities found by %s in %s:",
+		currentIndentation, s.Scanner, output.WithHighLightFormat(s.Image)))
+
+	for _, c := range counts {
+		count := fmt.Sprint(c.count)
+		if c.count > 0 {
+			count = c.format(count)
+		}
+		lines = append(lines, fmt.Sprintf("%s- %s: %s", currentIndentation, c.severity, count))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *ImageScanSummary) MarshalJSON() ([]byte, error) {
+	counts := s.counts()
+	parts := make([]string, 0, len(counts))
+	for _, c := range counts {
+		parts = append(parts, fmt.Sprintf("%d %s", c.count, strings.ToLower(c.severity)))
+	}
+
+	// reusing the same envelope from console messages
+	return json.Marshal(output.EventForMessage(
+		fmt.Sprintf("Vulnerabilities found by %s in %s: %s", s.Scanner, s.Image, strings.Join(parts, ", "))))
+}
+
+type severityCount struct {
+	severity string
+	count    int
+	// format colors the count when it is not zero
+	format func(text string, a ...interface{}) string
+}
+
+func (s *ImageScanSummary) counts() []severityCount {
+	return []severityCount{
+		{severity: "Critical", count: s.Critical, format: output.WithErrorFormat},
+		{severity: "High", count: s.High, format: output.WithErrorFormat},
+		{severity: "Medium", count: s.Medium, format: output.WithWarningFormat},
+		{severity: "Low", count: s.Low, format: output.WithGrayFormat},
+		{severity: "Unknown", count: s.Unknown, format: output.WithGrayFormat},
+	}
+}
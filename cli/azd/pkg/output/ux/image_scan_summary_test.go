@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageScanSummary(t *testing.T) {
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = originalNoColor
+	})
+
+	summary := &ImageScanSummary{
+		Scanner:  "Trivy",
+		Image:    "api:latest",
+		Critical: 1,
+		High:     2,
+		Low:      5,
+	}
+
+	require.Equal(t,
+		"  Vulnerabilities found by Trivy in api:latest:\n"+
+			"  - Critical: 1\n"+
+			"  - High: 2\n"+
+			"  - Medium: 0\n"+
+			"  - Low: 5\n"+
+			"  - Unknown: 0",
+		summary.ToString("  "))
+
+	content, err := json.Marshal(summary)
+	require.NoError(t, err)
+
+	var event struct {
+		Data contracts.ConsoleMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(content, &event))
+	require.Equal(t,
+		"Vulnerabilities found by Trivy in api:latest: 1 critical, 2 high, 0 medium, 5 low, 0 unknown\n",
+		event.Data.Message)
+}
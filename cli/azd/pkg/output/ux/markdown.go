@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// Markdown displays basic Markdown, such as the content of next-steps.md. Headings, bold text, inline code, links and
+// lists are rendered with the output formats, and the text is wrapped to Width. When Plain is set, the markdown is
+// displayed as is.
+type Markdown struct {
+	Content string
+	// Width is the maximum width of the rendered lines, including the indentation. 0 doesn't wrap the lines.
+	Width int
+	// Plain displays the raw markdown, for consoles that aren't terminals or don't use colors.
+	Plain bool
+}
+
+var (
+	markdownHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	markdownListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	markdownInline   = regexp.MustCompile("\\*\\*(.+?)\\*\\*|__(.+?)__|`([^`]+)`|\\[([^\\]]+)\\]\\(([^)\\s]+)\\)")
+)
+
+func (m *Markdown) ToString(currentIndentation string) string {
+	lines := strings.Split(strings.ReplaceAll(m.Content, "\r\n", "\n"), "\n")
+	if m.Plain {
+		rendered := make([]string, len(lines))
+		for i, line := range lines {
+			if len(line) > 0 {
+				rendered[i] = currentIndentation + line
+			}
+		}
+		return strings.Join(rendered, "\n")
+	}
+
+	rendered := []string{}
+	inCodeBlock := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			// the fences aren't displayed, the code is displayed as is
+			inCodeBlock = !inCodeBlock
+		case inCodeBlock:
+			rendered = append(rendered, currentIndentation+"  "+markdownCode(line))
+		case trimmed == "":
+			rendered = append(rendered, "")
+		case markdownHeading.MatchString(trimmed):
+			heading := markdownHeading.FindStringSubmatch(trimmed)[2]
+			rendered = append(rendered, m.wrap(currentIndentation, "", heading, markdownBold)...)
+		case markdownListItem.MatchString(line):
+			match := markdownListItem.FindStringSubmatch(line)
+			marker := match[2]
+			if !strings.ContainsAny(marker, ".)") {
+				marker = "•"
+			}
+			rendered = append(rendered, m.wrap(currentIndentation+match[1], marker+" ", match[3], nil)...)
+		default:
+			rendered = append(rendered, m.wrap(currentIndentation, "", trimmed, nil)...)
+		}
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+func (m *Markdown) MarshalJSON() ([]byte, error) {
+	// reusing the same envelope from console messages
+	return json.Marshal(output.EventForMessage(m.Content))
+}
+
+// markdownWord is a word of rendered markdown, with the length of its visible text.
+type markdownWord struct {
+	text   string
+	length int
+}
+
+// wrap renders the inline markdown of the text and wraps it to the width, indenting the lines that follow the first one
+// so that they are aligned after the prefix. format styles the whole text, for example for headings.
+func (m *Markdown) wrap(indentation string, prefix string, text string, format func(string) string) []string {
+	words := markdownWords(text, format)
+	hangingIndent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	lineStart := indentation + prefix
+	lineWidth := utf8.RuneCountInString(lineStart)
+
+	lines := []string{}
+	var line strings.Builder
+	line.WriteString(lineStart)
+	length := lineWidth
+	empty := true
+	for _, word := range words {
+		if !empty && m.Width > 0 && length+1+word.length > m.Width {
+			lines = append(lines, line.String())
+			line.Reset()
+			line.WriteString(indentation + hangingIndent)
+			length = lineWidth
+			empty = true
+		}
+
+		if !empty {
+			line.WriteString(" ")
+			length++
+		}
+		line.WriteString(word.text)
+		length += word.length
+		empty = false
+	}
+
+	return append(lines, line.String())
+}
+
+// markdownWords splits the text into words, formatting the inline markdown of each one.
+func markdownWords(text string, format func(string) string) []markdownWord {
+	words := []markdownWord{}
+	var current markdownWord
+
+	// add appends the text to the words, starting a new word after each space
+	add := func(text string, style func(string) string) {
+		for i, part := range strings.Split(text, " ") {
+			if i > 0 && current.length > 0 {
+				words = append(words, current)
+				current = markdownWord{}
+			}
+			if part == "" {
+				continue
+			}
+
+			if style != nil {
+				current.text += style(part)
+			} else if format != nil {
+				current.text += format(part)
+			} else {
+				current.text += part
+			}
+			current.length += utf8.RuneCountInString(part)
+		}
+	}
+
+	position := 0
+	for _, match := range markdownInline.FindAllStringSubmatchIndex(text, -1) {
+		add(text[position:match[0]], nil)
+		position = match[1]
+
+		switch {
+		case match[2] >= 0:
+			add(text[match[2]:match[3]], markdownBold)
+		case match[4] >= 0:
+			add(text[match[4]:match[5]], markdownBold)
+		case match[6] >= 0:
+			add(text[match[6]:match[7]], markdownCode)
+		default:
+			linkText, url := text[match[8]:match[9]], text[match[10]:match[11]]
+			// links to files and anchors of the document aren't meaningful on the console, only their text is displayed
+			switch {
+			case !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://"):
+				add(linkText, nil)
+			case linkText == url:
+				add(url, markdownLink)
+			default:
+				add(linkText, nil)
+				add(" (", nil)
+				add(url, markdownLink)
+				add(")", nil)
+			}
+		}
+	}
+	add(text[position:], nil)
+
+	if current.length > 0 {
+		words = append(words, current)
+	}
+
+	return words
+}
+
+// The styles of markdown, the text is never used as a format string
+func markdownBold(text string) string {
+	return output.WithBold("%s", text)
+}
+
+func markdownCode(text string) string {
+	return output.WithHighLightFormat("%s", text)
+}
+
+func markdownLink(text string) string {
+	return output.WithLinkFormat("%s", text)
+}
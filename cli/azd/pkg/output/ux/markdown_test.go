@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+const testMarkdown = "# Next Steps\n" +
+	"\n" +
+	"Run `azd up` to provision **all** resources, see [troubleshooting](#troubleshooting).\n" +
+	"\n" +
+	"1. Read the [docs](https://aka.ms/azd) for 100% of the details\n" +
+	"   - [app/api.bicep](./infra/app/api.bicep)\n" +
+	"\n" +
+	"```yaml\n" +
+	"- azure.yaml     # project\n" +
+	"```"
+
+func TestMarkdownToString(t *testing.T) {
+	originalNoColor := color.NoColor
+	t.Cleanup(func() {
+		color.NoColor = originalNoColor
+	})
+
+	t.Run("Rendered", func(t *testing.T) {
+		color.NoColor = true
+
+		md := &Markdown{Content: testMarkdown, Width: 40}
+		require.Equal(t,
+			"  Next Steps\n"+
+				"\n"+
+				"  Run azd up to provision all resources,\n"+
+				"  see troubleshooting.\n"+
+				"\n"+
+				"  1. Read the docs (https://aka.ms/azd)\n"+
+				"     for 100% of the details\n"+
+				"     • app/api.bicep\n"+
+				"\n"+
+				"    - azure.yaml     # project",
+			md.ToString("  "))
+	})
+
+	t.Run("NoWrap", func(t *testing.T) {
+		color.NoColor = true
+
+		md := &Markdown{Content: "- a **bold** and `code` item"}
+		require.Equal(t, "• a bold and code item", md.ToString(""))
+	})
+
+	t.Run("Colors", func(t *testing.T) {
+		color.NoColor = false
+
+		md := &Markdown{Content: "## Run `azd up`"}
+		require.Equal(t, "\x1b[1mRun\x1b[0m \x1b[36mazd\x1b[0m \x1b[36mup\x1b[0m", md.ToString(""))
+	})
+
+	t.Run("Plain", func(t *testing.T) {
+		color.NoColor = false
+
+		md := &Markdown{Content: "# Title\n\n- **item**", Width: 4, Plain: true}
+		require.Equal(t, "  # Title\n\n  - **item**", md.ToString("  "))
+	})
+}
+
+func TestMarkdownMarshalJSON(t *testing.T) {
+	md := &Markdown{Content: testMarkdown, Width: 40}
+
+	content, err := json.Marshal(md)
+	require.NoError(t, err)
+
+	var event struct {
+		Type contracts.EventDataType  `json:"type"`
+		Data contracts.ConsoleMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(content, &event))
+	require.Equal(t, contracts.ConsoleMessageEventDataType, event.Type)
+	// console messages end with a new line
+	require.Equal(t, testMarkdown+"\n", event.Data.Message)
+}
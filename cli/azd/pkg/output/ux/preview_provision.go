@@ -6,6 +6,7 @@ package ux
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -68,6 +69,42 @@ func colorType(opType OperationType) func(string, ...interface{}) string {
 	return final
 }
 
+// operationGroupOrder returns the position of the group of opType when the operations are displayed. Creates are displayed
+// first, then modifications and deletions, followed by the operations that don't change any resource.
+func operationGroupOrder(opType OperationType) int {
+	switch opType {
+	case OperationTypeCreate:
+		return 0
+	case OperationTypeModify:
+		return 1
+	case OperationTypeDelete:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// summary returns the number of resources to create, modify and delete, or an empty string when no resource changes.
+func (pp *PreviewProvision) summary() string {
+	counts := map[OperationType]int{}
+	for _, op := range pp.Operations {
+		counts[op.Operation]++
+	}
+
+	var parts []string
+	for _, opType := range []OperationType{OperationTypeCreate, OperationTypeModify, OperationTypeDelete} {
+		if count := counts[opType]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d to %s", count, strings.ToLower(string(opType))))
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Changes: %s.", strings.Join(parts, ", "))
+}
+
 func (pp *PreviewProvision) ToString(currentIndentation string) string {
 	if len(pp.Operations) == 0 {
 		// no output when there are no operations
@@ -76,14 +113,20 @@ func (pp *PreviewProvision) ToString(currentIndentation string) string {
 
 	title := currentIndentation + "Resources:"
 
-	changes := make([]string, len(pp.Operations))
-	actions := make([]string, len(pp.Operations))
-	resources := make([]string, len(pp.Operations))
+	// group the operations by type, keeping the order of the operations within each group
+	operations := slices.Clone(pp.Operations)
+	slices.SortStableFunc(operations, func(a, b *Resource) int {
+		return operationGroupOrder(a.Operation) - operationGroupOrder(b.Operation)
+	})
+
+	changes := make([]string, len(operations))
+	actions := make([]string, len(operations))
+	resources := make([]string, len(operations))
 
 	var maxActionLen int
 	var maxResourceLen int
 	// get max
-	for _, op := range pp.Operations {
+	for _, op := range operations {
 		if actionLen := len(op.Operation); actionLen > maxActionLen {
 			maxActionLen = actionLen
 		}
@@ -93,7 +136,7 @@ func (pp *PreviewProvision) ToString(currentIndentation string) string {
 	}
 
 	// Align
-	for index, op := range pp.Operations {
+	for index, op := range operations {
 		displayNameOp := op.Operation.String()
 		opGapToFill := strings.Repeat(" ", maxActionLen-len(displayNameOp))
 		typeGapToFill := strings.Repeat(" ", maxResourceLen-len(op.Type))
@@ -101,7 +144,7 @@ func (pp *PreviewProvision) ToString(currentIndentation string) string {
 		resources[index] = op.Type + typeGapToFill + " :"
 	}
 
-	for index, op := range pp.Operations {
+	for index, op := range operations {
 		changes[index] = fmt.Sprintf("%s%s %s %s",
 			currentIndentation,
 			colorType(op.Operation)(actions[index]),
@@ -110,7 +153,12 @@ func (pp *PreviewProvision) ToString(currentIndentation string) string {
 		)
 	}
 
-	return fmt.Sprintf("%s\n\n%s", title, strings.Join(changes, "\n"))
+	result := fmt.Sprintf("%s\n\n%s", title, strings.Join(changes, "\n"))
+	if summary := pp.summary(); summary != "" {
+		result = fmt.Sprintf("%s\n\n%s%s", result, currentIndentation, summary)
+	}
+
+	return result
 }
 
 func (pp *PreviewProvision) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// the narrowest bar (not counting the brackets) worth rendering.
+const progressBarMinWidth = 10
+
+// the bar width used when the available width is unknown.
+const progressBarDefaultWidth = 20
+
+// ProgressBar displays the completion of an operation, like:
+//
+//	<indentation><title> [=========>          ]  45% <message>
+type ProgressBar struct {
+	Title string
+	// Fraction is the completed portion of the operation, between 0 and 1.
+	Fraction float64
+	Message  string
+	// Width is the total width available for the line. When zero or negative, a default bar width is used.
+	Width int
+}
+
+// Percentage returns the completion of the operation as an integer percentage between 0 and 100.
+func (p *ProgressBar) Percentage() int {
+	fraction := math.Max(0, math.Min(1, p.Fraction))
+	return int(math.Floor(fraction * 100))
+}
+
+func (p *ProgressBar) ToString(currentIndentation string) string {
+	percentage := fmt.Sprintf("%3d%%", p.Percentage())
+
+	if p.Width <= 0 {
+		return p.line(currentIndentation, progressBarDefaultWidth, percentage, p.Message)
+	}
+
+	// the space taken by everything but the bar: indentation, title, " [", "] ", the percentage and " <message>".
+	fixedLen := len(currentIndentation) + len(p.Title) + 4 + len(percentage)
+	message := p.Message
+	if message != "" {
+		fixedLen += len(message) + 1
+	}
+
+	if p.Width-fixedLen < progressBarMinWidth && message != "" {
+		// not enough room, drop the message first.
+		fixedLen -= len(message) + 1
+		message = ""
+	}
+
+	if p.Width-fixedLen < progressBarMinWidth {
+		// not even room for the bar, fallback to the percentage only.
+		return fmt.Sprintf("%s%s %s", currentIndentation, p.Title, strings.TrimSpace(percentage))
+	}
+
+	return p.line(currentIndentation, p.Width-fixedLen, percentage, message)
+}
+
+func (p *ProgressBar) line(indentation string, barWidth int, percentage string, message string) string {
+	filled := int(math.Round(float64(barWidth) * float64(p.Percentage()) / 100))
+	bar := strings.Repeat("=", filled)
+	if filled > 0 && filled < barWidth {
+		bar = bar[:filled-1] + ">"
+	}
+	bar += strings.Repeat(" ", barWidth-filled)
+
+	line := fmt.Sprintf("%s%s [%s] %s", indentation, p.Title, bar, percentage)
+	if message != "" {
+		line += " " + message
+	}
+
+	return line
+}
+
+func (p *ProgressBar) MarshalJSON() ([]byte, error) {
+	message := fmt.Sprintf("%s: %d%%", p.Title, p.Percentage())
+	if p.Message != "" {
+		message += " " + p.Message
+	}
+
+	// reusing the same envelope from console messages
+	return json.Marshal(output.EventForMessage(message))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressBar(t *testing.T) {
+	tcs := []struct {
+		name     string
+		bar      ProgressBar
+		expected string
+	}{
+		{
+			name:     "DefaultWidth",
+			bar:      ProgressBar{Title: "Pushing", Fraction: 0.5, Message: "api"},
+			expected: "  Pushing [=========>          ]  50% api",
+		},
+		{
+			name:     "Done",
+			bar:      ProgressBar{Title: "Pushing", Fraction: 1},
+			expected: "  Pushing [====================] 100%",
+		},
+		{
+			name:     "FitsWidth",
+			bar:      ProgressBar{Title: "Pushing", Fraction: 0.25, Message: "api", Width: 40},
+			expected: "  Pushing [====>              ]  25% api",
+		},
+		{
+			name:     "DropsMessage",
+			bar:      ProgressBar{Title: "Pushing", Fraction: 0.25, Message: "a long message", Width: 40},
+			expected: "  Pushing [=====>                 ]  25%",
+		},
+		{
+			name:     "PercentageOnly",
+			bar:      ProgressBar{Title: "Pushing", Fraction: 0.25, Width: 20},
+			expected: "  Pushing 25%",
+		},
+		{
+			name:     "Clamped",
+			bar:      ProgressBar{Title: "Pushing", Fraction: 1.5},
+			expected: "  Pushing [====================] 100%",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.bar.ToString("  "))
+		})
+	}
+}
@@ -80,15 +80,19 @@ func services(services []*ShowService) string {
 			color.HiBlueString("azure.yaml"),
 		)
 	}
-	lines := make([]string, servicesCount)
+
+	// the width of the table isn't limited, so the endpoints aren't truncated
+	table := &Table{
+		Headers: []string{"Name", "Endpoint"},
+		Rows:    make([][]string, servicesCount),
+	}
 	for index, service := range services {
-		lines[index] = fmt.Sprintf(
-			"    %s  %s",
+		table.Rows[index] = []string{
 			color.HiBlueString(service.Name),
 			output.WithLinkFormat(service.IngresUrl),
-		)
+		}
 	}
-	return strings.Join(lines, "\n")
+	return table.ToString("    ")
 }
 
 func environments(environments []*ShowEnvironment) string {
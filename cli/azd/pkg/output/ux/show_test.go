@@ -104,3 +104,29 @@ func TestShowRemoteAndCurrent(t *testing.T) {
 	output := pp.ToString("")
 	snapshot.SnapshotT(t, output)
 }
+
+func TestShowServices(t *testing.T) {
+	pp := &Show{
+		AppName: "Foo",
+		Services: []*ShowService{
+			{
+				Name:      "api",
+				IngresUrl: "https://api.contoso.com",
+			},
+			{
+				Name:      "frontend",
+				IngresUrl: "https://www.contoso.com",
+			},
+		},
+		Environments: []*ShowEnvironment{
+			{
+				Name:      "foo",
+				IsCurrent: true,
+			},
+		},
+		AzurePortalLink: "foo.com",
+	}
+
+	output := pp.ToString("")
+	snapshot.SnapshotT(t, output)
+}
@@ -0,0 +1,255 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+const (
+	// The spaces between the columns of a table
+	tableColumnGap = "  "
+	// Columns aren't truncated to fewer characters than this to fit a table into the console
+	tableMinColumnWidth = 6
+	// Appended to the cells that are truncated
+	tableTruncatedSuffix = "..."
+	// Resets the colors of a truncated cell, whose sequence to reset its colors may have been truncated
+	ansiReset = "\x1b[0m"
+)
+
+// Table lists rows of cells in columns, which are aligned under their headers, for example:
+//
+//	Name  Default  Location
+//	dev   true     eastus2
+//	prod  false    westus3
+//
+// Cells may contain ANSI escape sequences, such as colors, which aren't counted in the width of a column. When the table
+// is wider than the console, the widest columns are truncated to fit it.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	// Width is the total width available for the lines of the table. When zero or negative, the width is unlimited.
+	// When the table is written with MessageUxItem, the width of the console is used unless it is set.
+	Width int
+}
+
+func (t *Table) ToString(currentIndentation string) string {
+	widths := t.columnWidths()
+	if len(widths) == 0 {
+		return ""
+	}
+
+	if t.Width > 0 {
+		fitColumnWidths(widths, t.Width-len(currentIndentation)-len(tableColumnGap)*(len(widths)-1))
+	}
+
+	lines := make([]string, 0, len(t.Rows)+1)
+	lines = append(lines, currentIndentation+formatTableRow(t.Headers, widths, output.WithBold))
+	for _, row := range t.Rows {
+		lines = append(lines, currentIndentation+formatTableRow(row, widths, nil))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// MarshalJSON returns an event whose data lists each row as an object, with the cells of the row keyed by their headers.
+// ANSI escape sequences are removed from the cells.
+func (t *Table) MarshalJSON() ([]byte, error) {
+	rows := make([]map[string]string, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		values := make(map[string]string, len(t.Headers))
+		for idx, header := range t.Headers {
+			if idx < len(row) {
+				values[stripAnsi(header)] = stripAnsi(row[idx])
+			} else {
+				values[stripAnsi(header)] = ""
+			}
+		}
+
+		rows = append(rows, values)
+	}
+
+	return json.Marshal(contracts.EventEnvelope{
+		Type:      contracts.ConsoleMessageEventDataType,
+		Timestamp: time.Now(),
+		Data:      rows,
+	})
+}
+
+// columnWidths returns the width of the widest cell of each column.
+func (t *Table) columnWidths() []int {
+	var widths []int
+	for _, row := range append([][]string{t.Headers}, t.Rows...) {
+		for idx, cell := range row {
+			if idx == len(widths) {
+				widths = append(widths, 0)
+			}
+
+			widths[idx] = max(widths[idx], visibleWidth(cell))
+		}
+	}
+
+	return widths
+}
+
+// fitColumnWidths narrows the widest columns until the total width of the columns fits into available, or all the columns
+// are as narrow as they are allowed to be.
+func fitColumnWidths(widths []int, available int) {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+
+	for ; total > available; total-- {
+		widest := 0
+		for idx, width := range widths {
+			if width > widths[widest] {
+				widest = idx
+			}
+		}
+
+		if widths[widest] <= tableMinColumnWidth {
+			return
+		}
+
+		widths[widest]--
+	}
+}
+
+// formatTableRow pads each cell of the row to the width of its column, truncating the cells that are wider. The last
+// column isn't padded, so lines don't end with spaces.
+func formatTableRow(row []string, widths []int, format func(string, ...interface{}) string) string {
+	var sb strings.Builder
+	for idx, width := range widths {
+		cell := ""
+		if idx < len(row) {
+			cell = truncateCell(row[idx], width)
+		}
+
+		padding := width - visibleWidth(cell)
+		if format != nil && cell != "" {
+			cell = format("%s", cell)
+		}
+
+		sb.WriteString(cell)
+		if idx < len(widths)-1 {
+			sb.WriteString(strings.Repeat(" ", padding))
+			sb.WriteString(tableColumnGap)
+		}
+	}
+
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// truncateCell shortens the visible text of cell to width characters, ending with tableTruncatedSuffix. ANSI escape
+// sequences are kept, and the colors are reset after a truncated cell.
+func truncateCell(cell string, width int) string {
+	if visibleWidth(cell) <= width {
+		return cell
+	}
+
+	keep := width - len(tableTruncatedSuffix)
+	suffix := tableTruncatedSuffix
+	if keep < 1 {
+		keep = width
+		suffix = ""
+	}
+
+	var sb strings.Builder
+	hasEscapes := false
+	for i := 0; i < len(cell); {
+		if n := ansiSequenceLen(cell, i); n > 0 {
+			sb.WriteString(cell[i : i+n])
+			hasEscapes = true
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(cell[i:])
+		if keep > 0 {
+			sb.WriteRune(r)
+			keep--
+		}
+		i += size
+	}
+
+	sb.WriteString(suffix)
+	if hasEscapes {
+		sb.WriteString(ansiReset)
+	}
+
+	return sb.String()
+}
+
+// visibleWidth returns the number of characters of s that are printed, not counting ANSI escape sequences.
+func visibleWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLen(s, i); n > 0 {
+			i += n
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		width++
+		i += size
+	}
+
+	return width
+}
+
+// stripAnsi removes the ANSI escape sequences from s.
+func stripAnsi(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLen(s, i); n > 0 {
+			i += n
+			continue
+		}
+
+		sb.WriteByte(s[i])
+		i++
+	}
+
+	return sb.String()
+}
+
+// ansiSequenceLen returns the length of the ANSI escape sequence that starts at s[i], or 0 when there is none. Control
+// sequences, such as colors, and operating system commands, such as hyperlinks, are recognized.
+func ansiSequenceLen(s string, i int) int {
+	if s[i] != '\x1b' || i+1 >= len(s) {
+		return 0
+	}
+
+	switch s[i+1] {
+	case '[':
+		// control sequence, ends with a byte in the range 0x40-0x7E
+		for j := i + 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				return j + 1 - i
+			}
+		}
+	case ']':
+		// operating system command, ends with BEL or ESC \
+		for j := i + 2; j < len(s); j++ {
+			if s[j] == '\a' {
+				return j + 1 - i
+			}
+
+			if s[j] == '\x1b' && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2 - i
+			}
+		}
+	default:
+		return 2
+	}
+
+	return len(s) - i
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = noColor
+	})
+
+	tcs := []struct {
+		name     string
+		table    Table
+		expected string
+	}{
+		{
+			name: "Aligned",
+			table: Table{
+				Headers: []string{"Name", "Default", "Location"},
+				Rows: [][]string{
+					{"dev", "true", "eastus2"},
+					{"production", "false", ""},
+				},
+			},
+			expected: "  Name        Default  Location\n" +
+				"  dev         true     eastus2\n" +
+				"  production  false",
+		},
+		{
+			name: "ColoredCells",
+			table: Table{
+				Headers: []string{"Name", "Status"},
+				Rows: [][]string{
+					{"\x1b[34mapi\x1b[0m", "\x1b[32mRunning\x1b[0m"},
+					{"web", "Stopped"},
+				},
+			},
+			expected: "  Name  Status\n" +
+				"  \x1b[34mapi\x1b[0m   \x1b[32mRunning\x1b[0m\n" +
+				"  web   Stopped",
+		},
+		{
+			name: "Truncated",
+			table: Table{
+				Headers: []string{"Name", "Endpoint"},
+				Rows: [][]string{
+					{"api", "https://api.contoso.azurecontainerapps.io"},
+				},
+				Width: 30,
+			},
+			expected: "  Name  Endpoint\n" +
+				"  api   https://api.contoso...",
+		},
+		{
+			name: "TruncatedColoredCell",
+			table: Table{
+				Headers: []string{"Name", "Endpoint"},
+				Rows: [][]string{
+					{"api", "\x1b[36mhttps://api.contoso.azurecontainerapps.io\x1b[0m"},
+				},
+				Width: 30,
+			},
+			expected: "  Name  Endpoint\n" +
+				"  api   \x1b[36mhttps://api.contoso\x1b[0m...\x1b[0m",
+		},
+		{
+			name:     "Empty",
+			table:    Table{},
+			expected: "",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.table.ToString("  "))
+		})
+	}
+}
+
+func TestTableMarshalJSON(t *testing.T) {
+	table := &Table{
+		Headers: []string{"Name", "Status"},
+		Rows: [][]string{
+			{"\x1b[34mapi\x1b[0m", "Running"},
+			{"web"},
+		},
+	}
+
+	data, err := json.Marshal(table)
+	require.NoError(t, err)
+
+	var event struct {
+		Type string              `json:"type"`
+		Data []map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &event))
+	require.Equal(t, "consoleMessage", event.Type)
+	require.Equal(t, []map[string]string{
+		{"Name": "api", "Status": "Running"},
+		{"Name": "web", "Status": ""},
+	}, event.Data)
+}
@@ -13,8 +13,15 @@ type UxItem interface {
 	// Defines how the object is transformed into a printable string.
 	// The current indentation can be used to make the string to be aligned to the previous lines.
 	ToString(currentIndentation string) string
+	// Defines the structured representation of the object. It is also used for yaml output, see output.MarshalYaml.
 	json.Marshaler
 }
 
-var donePrefix string = output.WithSuccessFormat("(✓) Done:")
-var failedPrefix string = output.WithErrorFormat("(x) Failed:")
+// donePrefix and failedPrefix are formatted when used, so they follow the current output theme.
+func donePrefix() string {
+	return output.WithSuccessFormat("(✓) Done:")
+}
+
+func failedPrefix() string {
+	return output.WithErrorFormat("(x) Failed:")
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+type YamlFormatter struct {
+}
+
+func (f *YamlFormatter) Kind() Format {
+	return YamlFormat
+}
+
+func (f *YamlFormatter) Format(obj interface{}, writer io.Writer, _ interface{}) error {
+	b, err := MarshalYaml(obj)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(b)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ Formatter = (*YamlFormatter)(nil)
+
+// MarshalYaml returns the YAML encoding of obj.
+//
+// The object is first marshaled to JSON and the result is then re-encoded as YAML. This means `json` struct tags and
+// custom json.Marshaler implementations (like the ones on ux.UxItem) are honored, and the YAML document has the same
+// shape and key order as the JSON document azd would produce for the same object.
+func MarshalYaml(obj interface{}) ([]byte, error) {
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is a subset of YAML, so the YAML decoder can read it directly into a node tree, which preserves key order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonBytes, &node); err != nil {
+		return nil, fmt.Errorf("converting json to yaml: %w", err)
+	}
+
+	clearYamlStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// clearYamlStyle resets the style of the node tree, which would otherwise keep the JSON flow style (braces, brackets
+// and double quoted strings) it was decoded with. The encoder still quotes scalars that need it.
+func clearYamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYamlStyle(child)
+	}
+}
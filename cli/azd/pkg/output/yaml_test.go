@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type yamlInput struct {
+	Size   string `json:"size"`
+	IsCool bool   `json:"isCool"`
+	Count  string `json:"count,omitempty"`
+}
+
+func TestYamlFormatterScalar(t *testing.T) {
+	obj := yamlInput{
+		Size:   "mega",
+		IsCool: true,
+		Count:  "10",
+	}
+
+	formatter := &YamlFormatter{}
+
+	buffer := &bytes.Buffer{}
+	err := formatter.Format(obj, buffer, nil)
+	require.NoError(t, err)
+
+	expected := `size: mega
+isCool: true
+count: "10"
+`
+	require.Equal(t, expected, buffer.String())
+}
+
+func TestYamlFormatterSlice(t *testing.T) {
+	obj := []interface{}{
+		yamlInput{
+			Size:   "mega",
+			IsCool: true,
+		},
+		yamlInput{
+			Size:   "medium",
+			IsCool: false,
+		},
+	}
+
+	formatter := &YamlFormatter{}
+
+	buffer := &bytes.Buffer{}
+	err := formatter.Format(obj, buffer, nil)
+	require.NoError(t, err)
+
+	expected := `- size: mega
+  isCool: true
+- size: medium
+  isCool: false
+`
+	require.Equal(t, expected, buffer.String())
+}
+
+type customMarshaler struct{}
+
+func (c *customMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"custom":{"nested":["a","b"]}}`), nil
+}
+
+func TestYamlFormatterCustomJsonMarshaler(t *testing.T) {
+	formatter := &YamlFormatter{}
+
+	buffer := &bytes.Buffer{}
+	err := formatter.Format(&customMarshaler{}, buffer, nil)
+	require.NoError(t, err)
+
+	expected := `custom:
+  nested:
+    - a
+    - b
+`
+	require.Equal(t, expected, buffer.String())
+}
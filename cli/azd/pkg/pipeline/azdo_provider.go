@@ -681,6 +681,14 @@ func (p *AzdoCiProvider) preConfigureCheck(
 		)
 	}
 
+	if pipelineManagerArgs.PipelineGitHubEnvironment != "" {
+		return false, fmt.Errorf(
+			"the %s and %s flags are only valid for the GitHub provider",
+			output.WithBackticks("--github-environment"),
+			output.WithBackticks("--github-environment-from-env"),
+		)
+	}
+
 	_, updatedPat, err := azdo.EnsurePatExists(ctx, p.Env, p.console)
 	if err != nil {
 		return updatedPat, err
@@ -701,8 +709,9 @@ func (p *AzdoCiProvider) credentialOptions(
 	ctx context.Context,
 	repoDetails *gitRepositoryDetails,
 	infraOptions provisioning.Options,
-	authType PipelineAuthType,
+	pipelineManagerArgs PipelineManagerArgs,
 ) *CredentialOptions {
+	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
 	if authType == "" || authType == AuthTypeClientCredentials {
 		return &CredentialOptions{
 			EnableClientCredentials: true,
@@ -738,7 +747,7 @@ func (p *AzdoCiProvider) configureConnection(
 	if err != nil {
 		return err
 	}
-	err = azdo.CreateServiceConnection(ctx, connection, details.projectId, *p.Env, p.credentials, p.console)
+	err = azdo.CreateServiceConnection(ctx, connection, details.projectId, p.Env, p.credentials, p.console)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,481 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/bitbucket"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+)
+
+// BitbucketScmProvider implements ScmProvider using Bitbucket Cloud as the provider
+// for source control manager.
+type BitbucketScmProvider struct {
+	env        *environment.Environment
+	console    input.Console
+	gitCli     git.GitCli
+	httpClient httputil.HttpClient
+}
+
+func NewBitbucketScmProvider(
+	env *environment.Environment,
+	console input.Console,
+	gitCli git.GitCli,
+	httpClient httputil.HttpClient,
+) ScmProvider {
+	return &BitbucketScmProvider{
+		env:        env,
+		console:    console,
+		gitCli:     gitCli,
+		httpClient: httpClient,
+	}
+}
+
+// ***  subareaProvider implementation ******
+
+// requiredTools return the list of external tools required by
+// Bitbucket provider during its execution.
+func (p *BitbucketScmProvider) requiredTools(_ context.Context) ([]tools.ExternalTool, error) {
+	return []tools.ExternalTool{}, nil
+}
+
+// preConfigureCheck makes sure a Bitbucket access token is available.
+func (p *BitbucketScmProvider) preConfigureCheck(
+	ctx context.Context,
+	pipelineManagerArgs PipelineManagerArgs,
+	infraOptions provisioning.Options,
+	projectPath string,
+) (bool, error) {
+	_, updated, err := bitbucket.EnsureAccessTokenExists(ctx, p.env, p.console)
+	return updated, err
+}
+
+// name returns the name of the provider
+func (p *BitbucketScmProvider) Name() string {
+	return "Bitbucket"
+}
+
+// ***  scmProvider implementation ******
+
+// configureGitRemote guides the user on creating a new Bitbucket repository or setting the remote url
+// for the local git project
+func (p *BitbucketScmProvider) configureGitRemote(
+	ctx context.Context,
+	repoPath string,
+	remoteName string,
+) (string, error) {
+	idx, err := p.console.Select(ctx, input.ConsoleOptions{
+		Message: "How would you like to configure your git remote to Bitbucket?",
+		Options: []string{
+			"Create a new private Bitbucket repository",
+			"Enter a remote URL directly",
+		},
+		DefaultValue: "Create a new private Bitbucket repository",
+	})
+	if err != nil {
+		return "", fmt.Errorf("prompting for remote configuration type: %w", err)
+	}
+
+	switch idx {
+	// Create a new repository
+	case 0:
+		remoteUrl, err := p.createRepository(ctx, repoPath)
+		if err != nil {
+			return "", fmt.Errorf("getting remote from new repository: %w", err)
+		}
+		return remoteUrl, nil
+	// Enter a URL directly.
+	case 1:
+		remoteUrl, err := p.promptForRemoteUrl(ctx, remoteName)
+		if err != nil {
+			return "", fmt.Errorf("getting remote from prompt: %w", err)
+		}
+		return remoteUrl, nil
+	default:
+		panic(fmt.Sprintf("unexpected selection index %d", idx))
+	}
+}
+
+// createRepository prompts for the workspace and the name of a new private repository, creates it and returns its
+// https remote url
+func (p *BitbucketScmProvider) createRepository(ctx context.Context, repoPath string) (string, error) {
+	workspace, err := p.console.Prompt(ctx, input.ConsoleOptions{
+		Message:      "Enter the Bitbucket workspace for your new repository:",
+		DefaultValue: p.env.Getenv(bitbucket.BitbucketEnvironmentWorkspaceName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("asking for workspace: %w", err)
+	}
+
+	client, err := newBitbucketClient(ctx, p.env, p.console, p.httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		name, err := p.console.Prompt(ctx, input.ConsoleOptions{
+			Message:      "Enter the name for your new repository OR Hit enter to use this name:",
+			DefaultValue: strings.ToLower(filepath.Base(repoPath)),
+		})
+		if err != nil {
+			return "", fmt.Errorf("asking for new repository name: %w", err)
+		}
+
+		repo, err := client.CreateRepository(ctx, workspace, name)
+		if errors.Is(err, bitbucket.ErrRepositoryNameInUse) {
+			p.console.Message(ctx, fmt.Sprintf("error: the repository name '%s' can't be used: %s\n", name, err))
+			continue // try again
+		} else if err != nil {
+			return "", err
+		}
+
+		return repo.CloneUrl("https"), nil
+	}
+}
+
+// promptForRemoteUrl prompts for the url of a Bitbucket repository until a valid one is entered
+func (p *BitbucketScmProvider) promptForRemoteUrl(ctx context.Context, remoteName string) (string, error) {
+	for {
+		remoteUrl, err := p.console.Prompt(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf("Enter the url to use for remote %s:", remoteName),
+		})
+		if err != nil {
+			return "", fmt.Errorf("prompting for remote url: %w", err)
+		}
+
+		if _, _, err := bitbucket.GetSlugForRemote(remoteUrl); err == nil {
+			return remoteUrl, nil
+		}
+
+		fmt.Fprintf(p.console.Handles().Stdout, "error: \"%s\" is not a valid Bitbucket URL.\n", remoteUrl)
+	}
+}
+
+// gitRepoDetails extracts the information from a Bitbucket remote url into general scm concepts
+// like owner, name and path
+func (p *BitbucketScmProvider) gitRepoDetails(ctx context.Context, remoteUrl string) (*gitRepositoryDetails, error) {
+	workspace, repoSlug, err := bitbucket.GetSlugForRemote(remoteUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitRepositoryDetails{
+		owner:    workspace,
+		repoName: repoSlug,
+		remote:   remoteUrl,
+		url:      fmt.Sprintf("https://%s/%s/%s", bitbucket.BitbucketHostName, workspace, repoSlug),
+	}, nil
+}
+
+// preventGitPush is nil for Bitbucket
+func (p *BitbucketScmProvider) preventGitPush(
+	ctx context.Context,
+	gitRepo *gitRepositoryDetails,
+	remoteName string,
+	branchName string) (bool, error) {
+	return false, nil
+}
+
+func (p *BitbucketScmProvider) GitPush(
+	ctx context.Context,
+	gitRepo *gitRepositoryDetails,
+	remoteName string,
+	branchName string) error {
+	return p.gitCli.PushUpstream(ctx, gitRepo.gitProjectPath, remoteName, branchName)
+}
+
+// BitbucketCiProvider implements a CiProvider using Bitbucket Pipelines.
+type BitbucketCiProvider struct {
+	env        *environment.Environment
+	console    input.Console
+	httpClient httputil.HttpClient
+}
+
+func NewBitbucketCiProvider(
+	env *environment.Environment,
+	console input.Console,
+	httpClient httputil.HttpClient,
+) CiProvider {
+	return &BitbucketCiProvider{
+		env:        env,
+		console:    console,
+		httpClient: httpClient,
+	}
+}
+
+// ***  subareaProvider implementation ******
+
+// requiredTools defines the requires tools for Bitbucket to be used as CI manager
+func (p *BitbucketCiProvider) requiredTools(_ context.Context) ([]tools.ExternalTool, error) {
+	return []tools.ExternalTool{}, nil
+}
+
+// preConfigureCheck validates the authentication type and makes sure a Bitbucket access token is available.
+func (p *BitbucketCiProvider) preConfigureCheck(
+	ctx context.Context,
+	pipelineManagerArgs PipelineManagerArgs,
+	infraOptions provisioning.Options,
+	projectPath string,
+) (bool, error) {
+	// The subject of the OIDC tokens of Bitbucket Pipelines includes the UUID of the step, which changes on every run,
+	// so it can't match the subject of a federated identity credential.
+	if PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName) == AuthTypeFederated {
+		return false, fmt.Errorf(
+			//nolint:lll
+			"Bitbucket Pipelines does not support federated authentication. To explicitly use client credentials set the %s flag. %w",
+			output.WithBackticks("--auth-type client-credentials"),
+			ErrAuthNotSupported,
+		)
+	}
+
+	if pipelineManagerArgs.PipelineGitHubEnvironment != "" {
+		return false, fmt.Errorf(
+			"the %s and %s flags are only valid for the GitHub provider",
+			output.WithBackticks("--github-environment"),
+			output.WithBackticks("--github-environment-from-env"),
+		)
+	}
+
+	_, updated, err := bitbucket.EnsureAccessTokenExists(ctx, p.env, p.console)
+	return updated, err
+}
+
+// name returns the name of the provider.
+func (p *BitbucketCiProvider) Name() string {
+	return "Bitbucket"
+}
+
+// ***  ciProvider implementation ******
+
+func (p *BitbucketCiProvider) credentialOptions(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	infraOptions provisioning.Options,
+	pipelineManagerArgs PipelineManagerArgs,
+) *CredentialOptions {
+	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
+	if authType == "" || authType == AuthTypeClientCredentials {
+		return &CredentialOptions{
+			EnableClientCredentials: true,
+		}
+	}
+
+	return &CredentialOptions{
+		EnableClientCredentials:    false,
+		EnableFederatedCredentials: false,
+	}
+}
+
+// configureConnection sets the repository variables Bitbucket Pipelines uses to log in to Azure
+// with the client credentials of the service principal, and to provision the environment.
+func (p *BitbucketCiProvider) configureConnection(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	infraOptions provisioning.Options,
+	servicePrincipal *graphsdk.ServicePrincipal,
+	authType PipelineAuthType,
+	credentials *azcli.AzureCredentials,
+) error {
+	client, err := newBitbucketClient(ctx, p.env, p.console, p.httpClient)
+	if err != nil {
+		return err
+	}
+
+	variables := []bitbucket.Variable{
+		{Key: environment.EnvNameEnvVarName, Value: p.env.GetEnvName()},
+		{Key: environment.LocationEnvVarName, Value: p.env.GetLocation()},
+		{Key: environment.SubscriptionIdEnvVarName, Value: p.env.GetSubscriptionId()},
+		{Key: environment.TenantIdEnvVarName, Value: credentials.TenantId},
+		{Key: "AZURE_CLIENT_ID", Value: credentials.ClientId},
+		{Key: "AZURE_CLIENT_SECRET", Value: credentials.ClientSecret, Secured: true},
+	}
+
+	switch infraOptions.Provider {
+	case provisioning.Terraform:
+		variables = append(variables,
+			bitbucket.Variable{Key: "ARM_TENANT_ID", Value: credentials.TenantId},
+			bitbucket.Variable{Key: "ARM_CLIENT_ID", Value: credentials.ClientId},
+			bitbucket.Variable{Key: "ARM_CLIENT_SECRET", Value: credentials.ClientSecret, Secured: true},
+		)
+
+		for _, key := range []string{"RS_RESOURCE_GROUP", "RS_STORAGE_ACCOUNT", "RS_CONTAINER_NAME"} {
+			value, ok := p.env.LookupEnv(key)
+			if !ok || strings.TrimSpace(value) == "" {
+				p.console.StopSpinner(ctx, "Configuring terraform", input.StepWarning)
+				p.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: "Terraform Remote State configuration is invalid",
+					HidePrefix:  true,
+				})
+				p.console.Message(
+					ctx,
+					fmt.Sprintf(
+						"Visit %s for more information on configuring Terraform remote state",
+						output.WithLinkFormat("https://aka.ms/azure-dev/terraform"),
+					),
+				)
+				p.console.Message(ctx, "")
+				return errors.New("terraform remote state is not correctly configured")
+			}
+
+			variables = append(variables, bitbucket.Variable{Key: key, Value: value})
+		}
+	case provisioning.Bicep:
+		if rgName, has := p.env.LookupEnv(environment.ResourceGroupEnvVarName); has {
+			variables = append(variables, bitbucket.Variable{Key: environment.ResourceGroupEnvVarName, Value: rgName})
+		}
+	}
+
+	for _, variable := range variables {
+		if err := client.SetVariable(
+			ctx, repoDetails.owner, repoDetails.repoName, variable.Key, variable.Value, variable.Secured); err != nil {
+			return fmt.Errorf("failed setting pipeline variables: %w", err)
+		}
+
+		kind := ux.GitHubVariable
+		if variable.Secured {
+			kind = ux.GitHubSecret
+		}
+		p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
+			Name: variable.Key,
+			Kind: kind,
+		})
+	}
+
+	p.console.MessageUxItem(ctx, &ux.MultilineMessage{
+		Lines: []string{
+			"",
+			"Bitbucket repository variables are now configured. You can view the variables that were created at this link:",
+			output.WithLinkFormat("%s/admin/pipelines/repository-variables", repoDetails.url),
+			""},
+	})
+
+	return nil
+}
+
+// configurePipeline generates bitbucket-pipelines.yml when the repository doesn't have one, and enables
+// Bitbucket Pipelines for the repository.
+func (p *BitbucketCiProvider) configurePipeline(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	provisioningProvider provisioning.Options,
+) (CiPipeline, error) {
+	pipelinePath := filepath.Join(repoDetails.gitProjectPath, bitbucket.PipelineYamlPath)
+	if _, err := os.Stat(pipelinePath); errors.Is(err, os.ErrNotExist) {
+		branch := repoDetails.branch
+		if branch == "" {
+			branch = "main"
+		}
+
+		contents, err := bitbucketPipelineYaml(branch)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(pipelinePath, []byte(contents), osutil.PermissionFile); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", bitbucket.PipelineYamlPath, err)
+		}
+
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "Bitbucket Pipelines definition",
+			Name: bitbucket.PipelineYamlPath,
+		})
+	} else if err != nil {
+		return nil, fmt.Errorf("checking for %s: %w", bitbucket.PipelineYamlPath, err)
+	}
+
+	client, err := newBitbucketClient(ctx, p.env, p.console, p.httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.EnablePipelines(ctx, repoDetails.owner, repoDetails.repoName); err != nil {
+		return nil, err
+	}
+
+	return &bitbucketPipeline{
+		repoDetails: repoDetails,
+	}, nil
+}
+
+// bitbucketPipeline is the implementation for a CiPipeline for Bitbucket Pipelines
+type bitbucketPipeline struct {
+	repoDetails *gitRepositoryDetails
+}
+
+func (p *bitbucketPipeline) name() string {
+	return "pipelines"
+}
+
+func (p *bitbucketPipeline) url() string {
+	return p.repoDetails.url + "/pipelines"
+}
+
+// bitbucketPipelineTemplate provisions and deploys the environment on pushes to the branch, or when the pipeline is run
+// manually. The variables it uses are the repository variables set by configureConnection.
+//
+//nolint:lll
+var bitbucketPipelineTemplate = template.Must(template.New("bitbucket-pipelines").Parse(
+	`# Generated by azd pipeline config. Provisions and deploys the environment on pushes to {{.Branch}},
+# or when the azure-dev pipeline is run from the Pipelines page of the repository.
+image: mcr.microsoft.com/azure-dev-cli-apps:latest
+
+definitions:
+  steps:
+    - step: &deploy
+        name: Provision and deploy
+        script:
+          - azd auth login --client-id "$AZURE_CLIENT_ID" --client-secret "$AZURE_CLIENT_SECRET" --tenant-id "$AZURE_TENANT_ID"
+          - azd provision --no-prompt
+          - azd deploy --no-prompt
+
+pipelines:
+  branches:
+    {{.Branch}}:
+      - step: *deploy
+  custom:
+    azure-dev:
+      - step: *deploy
+`))
+
+// bitbucketPipelineYaml returns the contents of the bitbucket-pipelines.yml generated for the branch
+func bitbucketPipelineYaml(branch string) (string, error) {
+	builder := strings.Builder{}
+	if err := bitbucketPipelineTemplate.Execute(&builder, struct{ Branch string }{Branch: branch}); err != nil {
+		return "", fmt.Errorf("generating %s: %w", bitbucket.PipelineYamlPath, err)
+	}
+
+	return builder.String(), nil
+}
+
+// newBitbucketClient returns a client of the Bitbucket API authenticated with the access token of the environment
+func newBitbucketClient(
+	ctx context.Context,
+	env *environment.Environment,
+	console input.Console,
+	httpClient httputil.HttpClient,
+) (*bitbucket.Client, error) {
+	token, _, err := bitbucket.EnsureAccessTokenExists(ctx, env, console)
+	if err != nil {
+		return nil, err
+	}
+
+	return bitbucket.NewClient(httpClient, env.Getenv(bitbucket.BitbucketUsernameName), token), nil
+}
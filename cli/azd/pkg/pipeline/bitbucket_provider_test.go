@@ -0,0 +1,281 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/bitbucket"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_bitbucket_provider_getRepoDetails(t *testing.T) {
+	t.Run("https remote", func(t *testing.T) {
+		provider := &BitbucketScmProvider{}
+
+		details, err := provider.gitRepoDetails(context.Background(), "https://user@bitbucket.org/workspace/repo.git")
+		require.NoError(t, err)
+		require.Equal(t, "workspace", details.owner)
+		require.Equal(t, "repo", details.repoName)
+		require.Equal(t, "https://bitbucket.org/workspace/repo", details.url)
+	})
+
+	t.Run("ssh remote", func(t *testing.T) {
+		provider := &BitbucketScmProvider{}
+
+		details, err := provider.gitRepoDetails(context.Background(), "git@bitbucket.org:workspace/repo.git")
+		require.NoError(t, err)
+		require.Equal(t, "workspace", details.owner)
+		require.Equal(t, "repo", details.repoName)
+		require.Equal(t, "https://bitbucket.org/workspace/repo", details.url)
+	})
+
+	t.Run("non bitbucket remote", func(t *testing.T) {
+		provider := &BitbucketScmProvider{}
+
+		details, err := provider.gitRepoDetails(context.Background(), "https://github.com/Azure/azure-dev.git")
+		require.ErrorIs(t, err, bitbucket.ErrRemoteHostIsNotBitbucket)
+		require.Nil(t, details)
+	})
+}
+
+func Test_bitbucket_ci_provider_preConfigureCheck(t *testing.T) {
+	t.Run("prompts for access token", func(t *testing.T) {
+		ostest.Unsetenv(t, bitbucket.BitbucketAccessTokenName)
+
+		testConsole := mockinput.NewMockConsole()
+		testConsole.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return options.Message == "Bitbucket access token:"
+		}).Respond("testToken")
+		provider := &BitbucketCiProvider{env: environment.New("test"), console: testConsole}
+
+		updatedConfig, err := provider.preConfigureCheck(
+			context.Background(), PipelineManagerArgs{}, provisioning.Options{}, "")
+		require.NoError(t, err)
+		require.True(t, updatedConfig)
+		require.Equal(t, "testToken", os.Getenv(bitbucket.BitbucketAccessTokenName))
+	})
+
+	t.Run("uses access token from environment", func(t *testing.T) {
+		t.Setenv(bitbucket.BitbucketAccessTokenName, "testToken")
+		provider := &BitbucketCiProvider{env: environment.New("test"), console: mockinput.NewMockConsole()}
+
+		updatedConfig, err := provider.preConfigureCheck(
+			context.Background(), PipelineManagerArgs{}, provisioning.Options{}, "")
+		require.NoError(t, err)
+		require.False(t, updatedConfig)
+	})
+
+	t.Run("fails if auth type is set to federated", func(t *testing.T) {
+		provider := &BitbucketCiProvider{env: environment.New("test"), console: mockinput.NewMockConsole()}
+		pipelineManagerArgs := PipelineManagerArgs{
+			PipelineAuthTypeName: string(AuthTypeFederated),
+		}
+
+		updatedConfig, err := provider.preConfigureCheck(
+			context.Background(), pipelineManagerArgs, provisioning.Options{}, "")
+		require.False(t, updatedConfig)
+		require.True(t, errors.Is(err, ErrAuthNotSupported))
+	})
+
+	t.Run("fails if github environment is set", func(t *testing.T) {
+		provider := &BitbucketCiProvider{env: environment.New("test"), console: mockinput.NewMockConsole()}
+		pipelineManagerArgs := PipelineManagerArgs{
+			PipelineGitHubEnvironment: "production",
+		}
+
+		updatedConfig, err := provider.preConfigureCheck(
+			context.Background(), pipelineManagerArgs, provisioning.Options{}, "")
+		require.False(t, updatedConfig)
+		require.ErrorContains(t, err, "only valid for the GitHub provider")
+	})
+}
+
+func Test_bitbucket_ci_provider_credentialOptions(t *testing.T) {
+	provider := &BitbucketCiProvider{}
+
+	options := provider.credentialOptions(
+		context.Background(), &gitRepositoryDetails{}, provisioning.Options{}, PipelineManagerArgs{})
+	require.True(t, options.EnableClientCredentials)
+	require.False(t, options.EnableFederatedCredentials)
+
+	options = provider.credentialOptions(
+		context.Background(),
+		&gitRepositoryDetails{},
+		provisioning.Options{},
+		PipelineManagerArgs{PipelineAuthTypeName: string(AuthTypeClientCredentials)},
+	)
+	require.True(t, options.EnableClientCredentials)
+	require.False(t, options.EnableFederatedCredentials)
+}
+
+func Test_bitbucket_ci_provider_configureConnection(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("test", map[string]string{
+		bitbucket.BitbucketAccessTokenName:   "testToken",
+		environment.EnvNameEnvVarName:        "test",
+		environment.LocationEnvVarName:       "eastus2",
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		environment.ResourceGroupEnvVarName:  "rg-test",
+	})
+
+	variables := map[string]bitbucket.Variable{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"values": []any{}})
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			request.URL.Path == "/2.0/repositories/workspace/repo/pipelines_config/variables"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var variable bitbucket.Variable
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&variable))
+		variables[variable.Key] = variable
+		return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, variable)
+	})
+
+	provider := NewBitbucketCiProvider(env, mockContext.Console, mockContext.HttpClient)
+	err := provider.configureConnection(
+		*mockContext.Context,
+		&gitRepositoryDetails{owner: "workspace", repoName: "repo", url: "https://bitbucket.org/workspace/repo"},
+		provisioning.Options{Provider: provisioning.Bicep},
+		nil,
+		AuthTypeClientCredentials,
+		&azcli.AzureCredentials{ClientId: "CLIENT_ID", ClientSecret: "CLIENT_SECRET", TenantId: "TENANT_ID"},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]bitbucket.Variable{
+		"AZURE_ENV_NAME":        {Key: "AZURE_ENV_NAME", Value: "test"},
+		"AZURE_LOCATION":        {Key: "AZURE_LOCATION", Value: "eastus2"},
+		"AZURE_SUBSCRIPTION_ID": {Key: "AZURE_SUBSCRIPTION_ID", Value: "SUBSCRIPTION_ID"},
+		"AZURE_TENANT_ID":       {Key: "AZURE_TENANT_ID", Value: "TENANT_ID"},
+		"AZURE_CLIENT_ID":       {Key: "AZURE_CLIENT_ID", Value: "CLIENT_ID"},
+		"AZURE_CLIENT_SECRET":   {Key: "AZURE_CLIENT_SECRET", Value: "CLIENT_SECRET", Secured: true},
+		"AZURE_RESOURCE_GROUP":  {Key: "AZURE_RESOURCE_GROUP", Value: "rg-test"},
+	}, variables)
+}
+
+func Test_bitbucket_ci_provider_configurePipeline(t *testing.T) {
+	setup := func(t *testing.T) (CiProvider, *mocks.MockContext, *bool) {
+		mockContext := mocks.NewMockContext(context.Background())
+		env := environment.NewWithValues("test", map[string]string{
+			bitbucket.BitbucketAccessTokenName: "testToken",
+		})
+
+		enabled := false
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut &&
+				request.URL.Path == "/2.0/repositories/workspace/repo/pipelines_config"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			enabled = true
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"enabled": true})
+		})
+
+		return NewBitbucketCiProvider(env, mockContext.Console, mockContext.HttpClient), mockContext, &enabled
+	}
+
+	t.Run("generates pipeline", func(t *testing.T) {
+		provider, mockContext, enabled := setup(t)
+		repoDetails := &gitRepositoryDetails{
+			owner:          "workspace",
+			repoName:       "repo",
+			url:            "https://bitbucket.org/workspace/repo",
+			branch:         "dev",
+			gitProjectPath: t.TempDir(),
+		}
+
+		pipeline, err := provider.configurePipeline(*mockContext.Context, repoDetails, provisioning.Options{})
+		require.NoError(t, err)
+		require.True(t, *enabled)
+		require.Equal(t, "https://bitbucket.org/workspace/repo/pipelines", pipeline.url())
+
+		contents, err := os.ReadFile(filepath.Join(repoDetails.gitProjectPath, bitbucket.PipelineYamlPath))
+		require.NoError(t, err)
+
+		var definition struct {
+			Image     string `yaml:"image"`
+			Pipelines struct {
+				Branches map[string][]struct {
+					Step struct {
+						Name   string   `yaml:"name"`
+						Script []string `yaml:"script"`
+					} `yaml:"step"`
+				} `yaml:"branches"`
+				Custom map[string][]any `yaml:"custom"`
+			} `yaml:"pipelines"`
+		}
+		require.NoError(t, yaml.Unmarshal(contents, &definition))
+
+		require.Equal(t, "mcr.microsoft.com/azure-dev-cli-apps:latest", definition.Image)
+		require.Contains(t, definition.Pipelines.Custom, "azure-dev")
+		require.Len(t, definition.Pipelines.Branches, 1)
+		require.Len(t, definition.Pipelines.Branches["dev"], 1)
+		require.Equal(t, []string{
+			`azd auth login --client-id "$AZURE_CLIENT_ID" --client-secret "$AZURE_CLIENT_SECRET" ` +
+				`--tenant-id "$AZURE_TENANT_ID"`,
+			"azd provision --no-prompt",
+			"azd deploy --no-prompt",
+		}, definition.Pipelines.Branches["dev"][0].Step.Script)
+	})
+
+	t.Run("defaults to main branch", func(t *testing.T) {
+		provider, mockContext, _ := setup(t)
+		repoDetails := &gitRepositoryDetails{
+			owner:          "workspace",
+			repoName:       "repo",
+			url:            "https://bitbucket.org/workspace/repo",
+			gitProjectPath: t.TempDir(),
+		}
+
+		_, err := provider.configurePipeline(*mockContext.Context, repoDetails, provisioning.Options{})
+		require.NoError(t, err)
+
+		contents, err := os.ReadFile(filepath.Join(repoDetails.gitProjectPath, bitbucket.PipelineYamlPath))
+		require.NoError(t, err)
+
+		var definition map[string]any
+		require.NoError(t, yaml.Unmarshal(contents, &definition))
+		branches := definition["pipelines"].(map[string]any)["branches"].(map[string]any)
+		require.Contains(t, branches, "main")
+	})
+
+	t.Run("keeps existing pipeline", func(t *testing.T) {
+		provider, mockContext, enabled := setup(t)
+		repoDetails := &gitRepositoryDetails{
+			owner:          "workspace",
+			repoName:       "repo",
+			url:            "https://bitbucket.org/workspace/repo",
+			gitProjectPath: t.TempDir(),
+		}
+
+		pipelinePath := filepath.Join(repoDetails.gitProjectPath, bitbucket.PipelineYamlPath)
+		existing := "pipelines:\n  default:\n    - step:\n        script:\n          - echo hello\n"
+		require.NoError(t, os.WriteFile(pipelinePath, []byte(existing), osutil.PermissionFile))
+
+		_, err := provider.configurePipeline(*mockContext.Context, repoDetails, provisioning.Options{})
+		require.NoError(t, err)
+		require.True(t, *enabled)
+
+		contents, err := os.ReadFile(pipelinePath)
+		require.NoError(t, err)
+		require.Equal(t, existing, string(contents))
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/fs"
 	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -319,6 +320,9 @@ type GitHubCiProvider struct {
 	gitCli             git.GitCli
 	console            input.Console
 	httpClient         httputil.HttpClient
+	// The GitHub Actions environment the workflow deploys from, set by preConfigureCheck. When empty, the variables and
+	// secrets of the pipeline are set on the repository.
+	environmentName string
 }
 
 func NewGitHubCiProvider(
@@ -361,6 +365,7 @@ func (p *GitHubCiProvider) preConfigureCheck(
 	}
 
 	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
+	p.environmentName = pipelineManagerArgs.PipelineGitHubEnvironment
 
 	// Federated Auth + Terraform is not a supported combination
 	if infraOptions.Provider == provisioning.Terraform {
@@ -396,8 +401,10 @@ func (p *GitHubCiProvider) credentialOptions(
 	ctx context.Context,
 	repoDetails *gitRepositoryDetails,
 	infraOptions provisioning.Options,
-	authType PipelineAuthType,
+	pipelineManagerArgs PipelineManagerArgs,
 ) *CredentialOptions {
+	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
+
 	// Default auth type to client-credentials for terraform
 	if infraOptions.Provider == provisioning.Terraform && authType == "" {
 		authType = AuthTypeClientCredentials
@@ -442,6 +449,16 @@ func (p *GitHubCiProvider) credentialOptions(
 			federatedCredentials = append(federatedCredentials, branchCredentials)
 		}
 
+		if environmentName := pipelineManagerArgs.PipelineGitHubEnvironment; environmentName != "" {
+			federatedCredentials = append(federatedCredentials, &graphsdk.FederatedIdentityCredential{
+				Name:        url.PathEscape(fmt.Sprintf("%s-environment-%s", credentialSafeName, environmentName)),
+				Issuer:      federatedIdentityIssuer,
+				Subject:     fmt.Sprintf("repo:%s:environment:%s", repoSlug, environmentName),
+				Description: convert.RefOf("Created by Azure Developer CLI"),
+				Audiences:   []string{federatedIdentityAudience},
+			})
+		}
+
 		return &CredentialOptions{
 			EnableFederatedCredentials: true,
 			FederatedCredentialOptions: federatedCredentials,
@@ -473,6 +490,12 @@ func (p *GitHubCiProvider) configureConnection(
 	}
 
 	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+	if p.environmentName != "" {
+		if err := p.ensureEnvironment(ctx, repoSlug); err != nil {
+			return err
+		}
+	}
+
 	if authType == AuthTypeClientCredentials {
 		err := p.configureClientCredentialsAuth(ctx, infraOptions, repoSlug, servicePrincipal, credentials)
 		if err != nil {
@@ -484,6 +507,21 @@ func (p *GitHubCiProvider) configureConnection(
 		return fmt.Errorf("failed setting pipeline variables: %w", err)
 	}
 
+	if p.environmentName != "" {
+		p.console.MessageUxItem(ctx, &ux.MultilineMessage{
+			Lines: []string{
+				"",
+				fmt.Sprintf(
+					"GitHub Action secrets are now configured in the %s environment. You can view the environment and "+
+						"add required reviewers and other protection rules at this link:",
+					output.WithHighLightFormat(p.environmentName)),
+				output.WithLinkFormat("https://github.com/%s/settings/environments", repoSlug),
+				""},
+		})
+
+		return nil
+	}
+
 	p.console.MessageUxItem(ctx, &ux.MultilineMessage{
 		Lines: []string{
 			"",
@@ -495,6 +533,50 @@ func (p *GitHubCiProvider) configureConnection(
 	return nil
 }
 
+// ensureEnvironment creates the GitHub environment the workflow deploys from, when it doesn't exist
+func (p *GitHubCiProvider) ensureEnvironment(ctx context.Context, repoSlug string) error {
+	err := p.ghCli.CreateEnvironment(ctx, repoSlug, p.environmentName)
+	if errors.Is(err, github.ErrEnvironmentsNotSupported) {
+		return &azcli.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: fmt.Sprintf(
+				"Suggestion: environments in private repositories require GitHub Pro, GitHub Team or GitHub Enterprise, "+
+					"and creating them requires admin access to the repository. Run without %s to set the pipeline "+
+					"variables and secrets on the repository.",
+				output.WithHighLightFormat("--github-environment")),
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed creating environment %s: %w", p.environmentName, err)
+	}
+
+	p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+		Type: "GitHub environment",
+		Name: p.environmentName,
+	})
+
+	return nil
+}
+
+// setVariable sets the variable in the GitHub environment the workflow deploys from, or in the repository when there
+// is no environment
+func (p *GitHubCiProvider) setVariable(ctx context.Context, repoSlug string, name string, value string) error {
+	if p.environmentName != "" {
+		return p.ghCli.SetEnvironmentVariable(ctx, repoSlug, p.environmentName, name, value)
+	}
+
+	return p.ghCli.SetVariable(ctx, repoSlug, name, value)
+}
+
+// setSecret sets the secret in the GitHub environment the workflow deploys from, or in the repository when there
+// is no environment
+func (p *GitHubCiProvider) setSecret(ctx context.Context, repoSlug string, name string, value string) error {
+	if p.environmentName != "" {
+		return p.ghCli.SetEnvironmentSecret(ctx, repoSlug, p.environmentName, name, value)
+	}
+
+	return p.ghCli.SetSecret(ctx, repoSlug, name, value)
+}
+
 // setPipelineVariables sets all the pipeline variables required for the pipeline to run.  This includes the environment
 // variables that the core of AZD uses (AZURE_ENV_NAME) as well as the variables that the provisioning system needs to run
 // (AZURE_SUBSCRIPTION_ID, AZURE_LOCATION) as well as scenario specific variables (AZURE_RESOURCE_GROUP for resource group
@@ -512,12 +594,13 @@ func (p *GitHubCiProvider) setPipelineVariables(
 		environment.TenantIdEnvVarName:       *servicePrincipal.AppOwnerOrganizationId,
 		"AZURE_CLIENT_ID":                    servicePrincipal.AppId,
 	} {
-		if err := p.ghCli.SetVariable(ctx, repoSlug, name, value); err != nil {
+		if err := p.setVariable(ctx, repoSlug, name, value); err != nil {
 			return fmt.Errorf("failed setting %s variable: %w", name, err)
 		}
 		p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
-			Name: name,
-			Kind: ux.GitHubVariable,
+			Name:        name,
+			Kind:        ux.GitHubVariable,
+			Environment: p.environmentName,
 		})
 	}
 
@@ -543,19 +626,20 @@ func (p *GitHubCiProvider) setPipelineVariables(
 			}
 
 			// env var was found
-			if err := p.ghCli.SetVariable(ctx, repoSlug, key, value); err != nil {
+			if err := p.setVariable(ctx, repoSlug, key, value); err != nil {
 				return fmt.Errorf("setting terraform remote state variables: %w", err)
 			}
 			p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
-				Name: key,
-				Kind: ux.GitHubVariable,
+				Name:        key,
+				Kind:        ux.GitHubVariable,
+				Environment: p.environmentName,
 			})
 		}
 	}
 
 	if infraOptions.Provider == provisioning.Bicep {
 		if rgName, has := p.env.LookupEnv(environment.ResourceGroupEnvVarName); has {
-			if err := p.ghCli.SetVariable(ctx, repoSlug, environment.ResourceGroupEnvVarName, rgName); err != nil {
+			if err := p.setVariable(ctx, repoSlug, environment.ResourceGroupEnvVarName, rgName); err != nil {
 				return fmt.Errorf("failed setting %s variable: %w", environment.ResourceGroupEnvVarName, err)
 			}
 		}
@@ -579,12 +663,13 @@ func (p *GitHubCiProvider) configureClientCredentialsAuth(
 		return fmt.Errorf("failed marshalling azure credentials: %w", err)
 	}
 
-	if err := p.ghCli.SetSecret(ctx, repoSlug, secretName, string(credsJson)); err != nil {
+	if err := p.setSecret(ctx, repoSlug, secretName, string(credsJson)); err != nil {
 		return fmt.Errorf("failed setting %s secret: %w", secretName, err)
 	}
 	p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
-		Name: secretName,
-		Kind: ux.GitHubSecret,
+		Name:        secretName,
+		Kind:        ux.GitHubSecret,
+		Environment: p.environmentName,
 	})
 
 	if infraOptions.Provider == provisioning.Terraform {
@@ -597,20 +682,22 @@ func (p *GitHubCiProvider) configureClientCredentialsAuth(
 			"ARM_CLIENT_SECRET": {credentials.ClientSecret, true},
 		} {
 			if !info.secret {
-				if err := p.ghCli.SetVariable(ctx, repoSlug, key, info.value); err != nil {
+				if err := p.setVariable(ctx, repoSlug, key, info.value); err != nil {
 					return fmt.Errorf("setting github variable %s:: %w", key, err)
 				}
 				p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
-					Name: key,
-					Kind: ux.GitHubVariable,
+					Name:        key,
+					Kind:        ux.GitHubVariable,
+					Environment: p.environmentName,
 				})
 			} else {
-				if err := p.ghCli.SetSecret(ctx, repoSlug, key, info.value); err != nil {
+				if err := p.setSecret(ctx, repoSlug, key, info.value); err != nil {
 					return fmt.Errorf("setting github secret %s:: %w", key, err)
 				}
 				p.console.MessageUxItem(ctx, &ux.CreatedRepoValue{
-					Name: key,
-					Kind: ux.GitHubSecret,
+					Name:        key,
+					Kind:        ux.GitHubSecret,
+					Environment: p.environmentName,
 				})
 			}
 		}
@@ -619,18 +706,72 @@ func (p *GitHubCiProvider) configureClientCredentialsAuth(
 	return nil
 }
 
-// configurePipeline is a no-op for GitHub, as the pipeline is automatically
-// created by creating the workflow files in .github folder.
+// configurePipeline doesn't create a pipeline for GitHub, as the pipeline is automatically
+// created by creating the workflow files in .github folder. When the workflow deploys from a GitHub environment,
+// the environment is added to the jobs of the workflows that use azd.
 func (p *GitHubCiProvider) configurePipeline(
 	ctx context.Context,
 	repoDetails *gitRepositoryDetails,
 	provisioningProvider provisioning.Options,
 ) (CiPipeline, error) {
+	if p.environmentName != "" {
+		if err := p.addWorkflowsEnvironment(ctx, repoDetails.gitProjectPath); err != nil {
+			return nil, err
+		}
+	}
+
 	return &workflow{
 		repoDetails: repoDetails,
 	}, nil
 }
 
+// addWorkflowsEnvironment adds the environment to the jobs that use azd in the workflows of the project
+func (p *GitHubCiProvider) addWorkflowsEnvironment(ctx context.Context, projectPath string) error {
+	entries, err := os.ReadDir(filepath.Join(projectPath, githubFolder))
+	if err != nil {
+		return fmt.Errorf("reading workflows: %w", err)
+	}
+
+	for _, entry := range entries {
+		extension := filepath.Ext(entry.Name())
+		if entry.IsDir() || (extension != ".yml" && extension != ".yaml") {
+			continue
+		}
+
+		workflowPath := filepath.Join(githubFolder, entry.Name())
+		fullPath := filepath.Join(projectPath, workflowPath)
+		contents, err := os.ReadFile(fullPath)
+		if err != nil {
+			return fmt.Errorf("reading workflow %s: %w", workflowPath, err)
+		}
+
+		updated, changed, err := addWorkflowEnvironment(contents, p.environmentName)
+		if err != nil {
+			return fmt.Errorf("adding environment to workflow %s: %w", workflowPath, err)
+		}
+
+		if !changed {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("reading workflow %s: %w", workflowPath, err)
+		}
+
+		if err := os.WriteFile(fullPath, updated, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("writing workflow %s: %w", workflowPath, err)
+		}
+
+		p.console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: fmt.Sprintf("Workflow deploying from environment %s", p.environmentName),
+			Name: workflowPath,
+		})
+	}
+
+	return nil
+}
+
 // workflow is the implementation for a CiPipeline for GitHub
 type workflow struct {
 	repoDetails *gitRepositoryDetails
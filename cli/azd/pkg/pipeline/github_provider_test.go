@@ -7,12 +7,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
@@ -107,6 +112,216 @@ func Test_gitHub_provider_preConfigure_check(t *testing.T) {
 		require.Len(t, consoleLog, 1)
 		require.Contains(t, consoleLog[0], "Warning: Terraform provisioning does not support federated authentication")
 	})
+
+	t.Run("success with environment & client credentials", func(t *testing.T) {
+		pipelineManagerArgs := PipelineManagerArgs{
+			PipelineAuthTypeName:      string(AuthTypeClientCredentials),
+			PipelineGitHubEnvironment: "production",
+		}
+
+		mockContext := mocks.NewMockContext(context.Background())
+		setupGithubCliMocks(mockContext)
+
+		provider := createGitHubCiProvider(t, mockContext)
+		updatedConfig, err := provider.preConfigureCheck(
+			*mockContext.Context, pipelineManagerArgs, provisioning.Options{}, "")
+		require.NoError(t, err)
+		require.False(t, updatedConfig)
+		require.Equal(t, "production", provider.(*GitHubCiProvider).environmentName)
+	})
+}
+
+func Test_gitHub_provider_environment(t *testing.T) {
+	repoDetails := &gitRepositoryDetails{
+		owner:    "Azure",
+		repoName: "azure-dev",
+		branch:   "main",
+		url:      "https://github.com/Azure/azure-dev",
+	}
+	servicePrincipal := &graphsdk.ServicePrincipal{
+		AppId:                  "CLIENT_ID",
+		AppOwnerOrganizationId: convert.RefOf("TENANT_ID"),
+	}
+	credentials := &azcli.AzureCredentials{
+		ClientId:     "CLIENT_ID",
+		ClientSecret: "CLIENT_SECRET",
+		TenantId:     "TENANT_ID",
+	}
+
+	newProvider := func(mockContext *mocks.MockContext, ghCli github.GitHubCli) *GitHubCiProvider {
+		return &GitHubCiProvider{
+			env: environment.NewWithValues("prod", map[string]string{
+				environment.LocationEnvVarName:       "eastus2",
+				environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			}),
+			ghCli:           ghCli,
+			console:         mockContext.Console,
+			environmentName: "production",
+		}
+	}
+
+	t.Run("sets values in environment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		ghCli := &fakeGitHubCli{}
+		provider := newProvider(mockContext, ghCli)
+
+		err := provider.configureConnection(
+			*mockContext.Context,
+			repoDetails,
+			provisioning.Options{Provider: provisioning.Bicep},
+			servicePrincipal,
+			AuthTypeClientCredentials,
+			credentials,
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"Azure/azure-dev production"}, ghCli.environments)
+		require.Empty(t, ghCli.repoValues)
+		require.ElementsMatch(t, []string{
+			"secret production AZURE_CREDENTIALS",
+			"variable production AZURE_ENV_NAME",
+			"variable production AZURE_LOCATION",
+			"variable production AZURE_SUBSCRIPTION_ID",
+			"variable production AZURE_TENANT_ID",
+			"variable production AZURE_CLIENT_ID",
+		}, ghCli.environmentValues)
+	})
+
+	t.Run("environments not supported", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		ghCli := &fakeGitHubCli{createEnvironmentErr: github.ErrEnvironmentsNotSupported}
+		provider := newProvider(mockContext, ghCli)
+
+		err := provider.configureConnection(
+			*mockContext.Context,
+			repoDetails,
+			provisioning.Options{Provider: provisioning.Bicep},
+			servicePrincipal,
+			AuthTypeFederated,
+			credentials,
+		)
+		require.ErrorIs(t, err, github.ErrEnvironmentsNotSupported)
+
+		var errWithSuggestion *azcli.ErrorWithSuggestion
+		require.True(t, errors.As(err, &errWithSuggestion))
+		require.Contains(t, errWithSuggestion.Suggestion, "GitHub Pro, GitHub Team or GitHub Enterprise")
+		require.Empty(t, ghCli.environmentValues)
+	})
+
+	t.Run("adds environment to workflows", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		provider := newProvider(mockContext, &fakeGitHubCli{})
+
+		projectPath := t.TempDir()
+		workflowsPath := filepath.Join(projectPath, githubFolder)
+		require.NoError(t, os.MkdirAll(workflowsPath, osutil.PermissionDirectory))
+
+		workflow := "jobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - run: azd up --no-prompt\n"
+		workflowPath := filepath.Join(workflowsPath, "azure-dev.yml")
+		require.NoError(t, os.WriteFile(workflowPath, []byte(workflow), osutil.PermissionFile))
+
+		details := *repoDetails
+		details.gitProjectPath = projectPath
+		_, err := provider.configurePipeline(*mockContext.Context, &details, provisioning.Options{})
+		require.NoError(t, err)
+
+		contents, err := os.ReadFile(workflowPath)
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			"jobs:\n  build:\n    environment: production\n    runs-on: ubuntu-latest\n    steps:\n"+
+				"      - run: azd up --no-prompt\n",
+			string(contents),
+		)
+	})
+}
+
+// fakeGitHubCli records the values set by the GitHub provider
+type fakeGitHubCli struct {
+	github.GitHubCli
+	createEnvironmentErr error
+	environments         []string
+	environmentValues    []string
+	repoValues           []string
+}
+
+func (f *fakeGitHubCli) GetAuthStatus(ctx context.Context, hostname string) (github.AuthStatus, error) {
+	return github.AuthStatus{LoggedIn: true}, nil
+}
+
+func (f *fakeGitHubCli) CreateEnvironment(ctx context.Context, repoSlug string, environment string) error {
+	if f.createEnvironmentErr != nil {
+		return f.createEnvironmentErr
+	}
+
+	f.environments = append(f.environments, repoSlug+" "+environment)
+	return nil
+}
+
+func (f *fakeGitHubCli) SetEnvironmentSecret(
+	ctx context.Context, repoSlug string, environment string, name string, value string) error {
+	f.environmentValues = append(f.environmentValues, "secret "+environment+" "+name)
+	return nil
+}
+
+func (f *fakeGitHubCli) SetEnvironmentVariable(
+	ctx context.Context, repoSlug string, environment string, name string, value string) error {
+	f.environmentValues = append(f.environmentValues, "variable "+environment+" "+name)
+	return nil
+}
+
+func (f *fakeGitHubCli) SetSecret(ctx context.Context, repoSlug string, name string, value string) error {
+	f.repoValues = append(f.repoValues, "secret "+name)
+	return nil
+}
+
+func (f *fakeGitHubCli) SetVariable(ctx context.Context, repoSlug string, name string, value string) error {
+	f.repoValues = append(f.repoValues, "variable "+name)
+	return nil
+}
+
+func Test_gitHub_provider_credentialOptions(t *testing.T) {
+	repoDetails := &gitRepositoryDetails{
+		owner:    "Azure",
+		repoName: "azure-dev",
+		branch:   "feature",
+	}
+
+	t.Run("federated with environment", func(t *testing.T) {
+		provider := &GitHubCiProvider{}
+		credentialOptions := provider.credentialOptions(
+			context.Background(),
+			repoDetails,
+			provisioning.Options{},
+			PipelineManagerArgs{PipelineGitHubEnvironment: "production"},
+		)
+		require.True(t, credentialOptions.EnableFederatedCredentials)
+		require.False(t, credentialOptions.EnableClientCredentials)
+
+		subjects := []string{}
+		for _, credential := range credentialOptions.FederatedCredentialOptions {
+			subjects = append(subjects, credential.Subject)
+		}
+
+		require.Equal(t, []string{
+			"repo:Azure/azure-dev:pull_request",
+			"repo:Azure/azure-dev:ref:refs/heads/feature",
+			"repo:Azure/azure-dev:ref:refs/heads/main",
+			"repo:Azure/azure-dev:environment:production",
+		}, subjects)
+	})
+
+	t.Run("client credentials", func(t *testing.T) {
+		provider := &GitHubCiProvider{}
+		credentialOptions := provider.credentialOptions(
+			context.Background(),
+			repoDetails,
+			provisioning.Options{},
+			PipelineManagerArgs{PipelineAuthTypeName: string(AuthTypeClientCredentials)},
+		)
+		require.True(t, credentialOptions.EnableClientCredentials)
+		require.False(t, credentialOptions.EnableFederatedCredentials)
+	})
 }
 
 func createGitHubCiProvider(t *testing.T, mockContext *mocks.MockContext) CiProvider {
@@ -0,0 +1,115 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// azdCommandRegex matches a run script that invokes azd
+var azdCommandRegex = regexp.MustCompile(`(?m)(^|[\s;&|(])azd(\s|$)`)
+
+// addWorkflowEnvironment adds the `environment` key to the jobs of the GitHub workflow that use azd and don't already
+// deploy from an environment. The key is inserted in the text of the workflow, so its formatting and comments are kept.
+// Returns false when no job was updated.
+func addWorkflowEnvironment(contents []byte, environment string) ([]byte, bool, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, false, fmt.Errorf("parsing workflow: %w", err)
+	}
+
+	if len(document.Content) == 0 {
+		return contents, false, nil
+	}
+
+	jobs := mappingValue(document.Content[0], "jobs")
+	if jobs == nil || jobs.Kind != yaml.MappingNode {
+		return contents, false, nil
+	}
+
+	value, err := yaml.Marshal(environment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	newline := "\n"
+	if bytes.Contains(contents, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	lines := strings.Split(string(contents), "\n")
+
+	// the lines to insert the key at, in the order of the jobs
+	var insertAt []int
+	var indents []int
+	for i := 1; i < len(jobs.Content); i += 2 {
+		job := jobs.Content[i]
+		if job.Kind != yaml.MappingNode || job.Style&yaml.FlowStyle != 0 || len(job.Content) == 0 {
+			continue
+		}
+
+		if mappingValue(job, "environment") != nil || !jobUsesAzd(job) {
+			continue
+		}
+
+		insertAt = append(insertAt, job.Content[0].Line-1)
+		indents = append(indents, job.Content[0].Column-1)
+	}
+
+	if len(insertAt) == 0 {
+		return contents, false, nil
+	}
+
+	// insert from the last line so the line numbers of the other jobs don't move
+	for i := len(insertAt) - 1; i >= 0; i-- {
+		line := strings.Repeat(" ", indents[i]) + "environment: " + strings.TrimSpace(string(value))
+		if newline == "\r\n" {
+			line += "\r"
+		}
+		lines = slices.Insert(lines, insertAt[i], line)
+	}
+
+	return []byte(strings.Join(lines, "\n")), true, nil
+}
+
+// jobUsesAzd returns true when a step of the job sets up or runs azd
+func jobUsesAzd(job *yaml.Node) bool {
+	steps := mappingValue(job, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return false
+	}
+
+	for _, step := range steps.Content {
+		if uses := mappingValue(step, "uses"); uses != nil &&
+			strings.HasPrefix(strings.ToLower(uses.Value), "azure/setup-azd") {
+			return true
+		}
+
+		if run := mappingValue(step, "run"); run != nil && azdCommandRegex.MatchString(run.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mappingValue returns the value of the key of a yaml mapping, or nil when the node isn't a mapping with the key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_addWorkflowEnvironment(t *testing.T) {
+	t.Run("adds environment to azd jobs", func(t *testing.T) {
+		workflow := `on:
+  workflow_dispatch:
+
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: npm run lint
+  build:
+    # deploys the app
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Install azd
+        uses: Azure/setup-azd@v1.0.0
+      - run: azd provision --no-prompt
+  deploy:
+      runs-on: ubuntu-latest
+      steps:
+        - run: |
+            az version
+            azd deploy --no-prompt
+`
+
+		updated, changed, err := addWorkflowEnvironment([]byte(workflow), "production")
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(t, `on:
+  workflow_dispatch:
+
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+      - run: npm run lint
+  build:
+    # deploys the app
+    environment: production
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - name: Install azd
+        uses: Azure/setup-azd@v1.0.0
+      - run: azd provision --no-prompt
+  deploy:
+      environment: production
+      runs-on: ubuntu-latest
+      steps:
+        - run: |
+            az version
+            azd deploy --no-prompt
+`, string(updated))
+	})
+
+	t.Run("keeps existing environment", func(t *testing.T) {
+		workflow := `jobs:
+  build:
+    environment: staging
+    steps:
+      - run: azd up --no-prompt
+`
+
+		updated, changed, err := addWorkflowEnvironment([]byte(workflow), "production")
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Equal(t, workflow, string(updated))
+	})
+
+	t.Run("quotes environment name", func(t *testing.T) {
+		workflow := "jobs:\r\n  build:\r\n    steps:\r\n      - run: azd up\r\n"
+
+		updated, changed, err := addWorkflowEnvironment([]byte(workflow), "prod: west")
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Equal(
+			t,
+			"jobs:\r\n  build:\r\n    environment: 'prod: west'\r\n    steps:\r\n      - run: azd up\r\n",
+			string(updated),
+		)
+	})
+
+	t.Run("no jobs use azd", func(t *testing.T) {
+		workflow := `jobs:
+  build:
+    steps:
+      - run: go build ./...
+      - run: echo azdo
+`
+
+		updated, changed, err := addWorkflowEnvironment([]byte(workflow), "production")
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Equal(t, workflow, string(updated))
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		_, _, err := addWorkflowEnvironment([]byte("jobs: [build"), "production")
+		require.Error(t, err)
+	})
+}
@@ -112,7 +112,7 @@ type CiProvider interface {
 		ctx context.Context,
 		repoDetails *gitRepositoryDetails,
 		infraOptions provisioning.Options,
-		authType PipelineAuthType,
+		pipelineManagerArgs PipelineManagerArgs,
 	) *CredentialOptions
 }
 
@@ -135,6 +135,7 @@ func ymlExists(ymlPath string) bool {
 const (
 	gitHubLabel     string = "github"
 	azdoLabel       string = "azdo"
+	bitbucketLabel  string = "bitbucket"
 	envPersistedKey string = "AZD_PIPELINE_PROVIDER"
 )
 
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/bitbucket"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
@@ -55,6 +56,12 @@ type PipelineManagerArgs struct {
 	PipelineRoleNames            []string
 	PipelineProvider             string
 	PipelineAuthTypeName         string
+	// The GitHub Actions environment the workflow deploys from. When set, the environment is created, the pipeline
+	// variables and secrets are set in it, the federated identity credentials include a credential scoped to the
+	// environment and the jobs of the workflows that use azd deploy from it.
+	PipelineGitHubEnvironment string
+	// When true, the name of the azd environment is used as PipelineGitHubEnvironment.
+	PipelineGitHubEnvironmentFromEnv bool
 }
 
 // CredentialOptions represents the options for configuring credentials for a pipeline.
@@ -270,7 +277,7 @@ func (pm *PipelineManager) Configure(ctx context.Context) (result *PipelineConfi
 		ctx,
 		gitRepoInfo,
 		infra.Options,
-		PipelineAuthType(pm.args.PipelineAuthTypeName),
+		*pm.args,
 	)
 
 	subscriptionId := pm.env.GetSubscriptionId()
@@ -412,6 +419,17 @@ func (pm *PipelineManager) requiredTools(ctx context.Context) ([]tools.ExternalT
 func (pm *PipelineManager) preConfigureCheck(ctx context.Context, infraOptions provisioning.Options, projectPath string) (
 	configurationWasUpdated bool,
 	err error) {
+	if pm.args.PipelineGitHubEnvironmentFromEnv {
+		if pm.args.PipelineGitHubEnvironment != "" && pm.args.PipelineGitHubEnvironment != pm.env.GetEnvName() {
+			return configurationWasUpdated, fmt.Errorf(
+				"you have specified both --github-environment and --github-environment-from-env, but only one of " +
+					"these parameters should be used at a time.",
+			)
+		}
+
+		pm.args.PipelineGitHubEnvironment = pm.env.GetEnvName()
+	}
+
 	// Validate the authentication types
 	// auth-type argument must either be an empty string or one of the following values.
 	validAuthTypes := []string{string(AuthTypeFederated), string(AuthTypeClientCredentials)}
@@ -606,9 +624,12 @@ func (pm *PipelineManager) resolveProvider(ctx context.Context, projectPath stri
 //   - both .github and .azdo folders found: GitHub scm and ci as provider
 //   - overrideProvider set to github (regardless of folders): GitHub scm and ci as provider
 //   - overrideProvider set to azdo (regardless of folders): Azdo scm and ci as provider
-//   - none of the folders found: return error
+//   - overrideProvider set to bitbucket (regardless of folders): Bitbucket scm and ci as provider
+//   - no overrideProvider and the git remote is on Bitbucket: Bitbucket scm and ci as provider
+//   - no overrideProvider and only bitbucket-pipelines.yml is found: Bitbucket scm and ci as provider
+//   - none of the folders, bitbucket-pipelines.yml or a Bitbucket remote found: return error
 //   - no azd context in the ctx: return error
-//   - overrideProvider set to neither github or azdo: return error
+//   - overrideProvider set to neither github, azdo or bitbucket: return error
 //   - Note: The provider is persisted in the environment so the next time the function is run
 //     the same provider is used directly, unless the overrideProvider is used to change
 //     the last used configuration
@@ -621,9 +642,14 @@ func (pm *PipelineManager) initialize(ctx context.Context, override string) erro
 	hasGitHubFolder := folderExists(filepath.Join(projectDir, githubFolder))
 	hasAzDevOpsFolder := folderExists(filepath.Join(projectDir, azdoFolder))
 	hasAzDevOpsYml := ymlExists(filepath.Join(projectDir, azdoYml))
-
-	// Error missing config for any provider
-	if !hasGitHubFolder && !hasAzDevOpsFolder {
+	// Bitbucket Pipelines doesn't need a folder, its yml is generated when it's missing
+	hasBitbucketYml := ymlExists(filepath.Join(projectDir, bitbucket.PipelineYamlPath))
+	hasBitbucketRemote := pm.hasBitbucketRemote(ctx, projectDir)
+
+	// Error missing config for any provider, unless bitbucket was selected by the arg, azure.yaml or a previous
+	// run, as it doesn't need any configuration
+	if !hasGitHubFolder && !hasAzDevOpsFolder && !hasBitbucketYml && !hasBitbucketRemote &&
+		!pm.isBitbucketSelected(ctx, pipelineProvider) {
 		return fmt.Errorf(
 			"no CI/CD provider configuration found. Expecting either %s and/or %s folder in the project root directory.",
 			gitHubLabel,
@@ -641,6 +667,13 @@ func (pm *PipelineManager) initialize(ctx context.Context, override string) erro
 		pipelineProvider = resolved
 	}
 
+	// Nothing was configured. Bitbucket is used when the git remote is on Bitbucket, or when there's no
+	// configuration for other providers
+	if pipelineProvider == "" &&
+		(hasBitbucketRemote || hasBitbucketYml && !hasGitHubFolder && !hasAzDevOpsFolder) {
+		pipelineProvider = bitbucketLabel
+	}
+
 	// Check override errors for missing folder
 	if pipelineProvider == gitHubLabel && !hasGitHubFolder {
 		return fmt.Errorf("%s folder is missing. Can't use selected provider", githubFolder)
@@ -653,18 +686,24 @@ func (pm *PipelineManager) initialize(ctx context.Context, override string) erro
 		return fmt.Errorf("%s file is missing in %s folder. Can't use selected provider", azdoYml, azdoFolder)
 	}
 	// using wrong override value
-	if pipelineProvider != "" && pipelineProvider != azdoLabel && pipelineProvider != gitHubLabel {
+	if pipelineProvider != "" && pipelineProvider != azdoLabel && pipelineProvider != gitHubLabel &&
+		pipelineProvider != bitbucketLabel {
 		return fmt.Errorf("%s is not a known pipeline provider", pipelineProvider)
 	}
 
 	var scmProviderName, ciProviderName string
 
 	// At this point, we know that override value has either:
-	// - github or azdo value
+	// - github, azdo or bitbucket value
 	// - OR is not set
 	// And we know that github and azdo folders are present.
 	// checking positive cases for overriding
-	if pipelineProvider == azdoLabel || hasAzDevOpsFolder && !hasGitHubFolder {
+	if pipelineProvider == bitbucketLabel {
+		log.Printf("Using pipeline provider: %s", output.WithHighLightFormat("Bitbucket"))
+
+		scmProviderName = bitbucketLabel
+		ciProviderName = bitbucketLabel
+	} else if pipelineProvider == azdoLabel || hasAzDevOpsFolder && !hasGitHubFolder {
 		// Azdo only either by override or by finding only that folder
 		log.Printf("Using pipeline provider: %s", output.WithHighLightFormat("Azure DevOps"))
 
@@ -697,6 +736,26 @@ func (pm *PipelineManager) initialize(ctx context.Context, override string) erro
 	return nil
 }
 
+// hasBitbucketRemote returns true when the pipeline remote of the project is a Bitbucket repository
+func (pm *PipelineManager) hasBitbucketRemote(ctx context.Context, projectDir string) bool {
+	remoteUrl, err := pm.gitCli.GetRemoteUrl(ctx, projectDir, pm.args.PipelineRemoteName)
+	if err != nil {
+		return false
+	}
+
+	_, _, err = bitbucket.GetSlugForRemote(remoteUrl)
+	return err == nil
+}
+
+// isBitbucketSelected returns true when the provider arg, or the provider resolved when the arg is empty, is bitbucket
+func (pm *PipelineManager) isBitbucketSelected(ctx context.Context, pipelineProvider string) bool {
+	if pipelineProvider == "" {
+		pipelineProvider, _ = pm.resolveProvider(ctx, pm.azdCtx.ProjectPath())
+	}
+
+	return strings.ToLower(pipelineProvider) == bitbucketLabel
+}
+
 func (pm *PipelineManager) savePipelineProviderToEnv(
 	ctx context.Context,
 	provider string,
@@ -5,14 +5,19 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/bitbucket"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
@@ -21,6 +26,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -32,6 +38,7 @@ func Test_PipelineManager_Initialize(t *testing.T) {
 	azdContext := azdcontext.NewAzdContextWithDirectory(tempDir)
 	mockContext := mocks.NewMockContext(ctx)
 	setupGithubCliMocks(mockContext)
+	mockGitRemote(mockContext, "")
 
 	t.Run("no folders error", func(t *testing.T) {
 		manager, err := createPipelineManager(t, mockContext, azdContext, nil, nil)
@@ -387,6 +394,150 @@ func Test_PipelineManager_Initialize(t *testing.T) {
 	})
 }
 
+func Test_PipelineManager_Initialize_Bitbucket(t *testing.T) {
+	setup := func(t *testing.T, remoteUrl string) (*mocks.MockContext, *azdcontext.AzdContext) {
+		tempDir := t.TempDir()
+		mockContext := mocks.NewMockContext(context.Background())
+		mockGitRemote(mockContext, remoteUrl)
+
+		err := os.WriteFile(filepath.Join(tempDir, "azure.yaml"), []byte("name: test\n"), osutil.PermissionFile)
+		assert.NoError(t, err)
+
+		return mockContext, azdcontext.NewAzdContextWithDirectory(tempDir)
+	}
+
+	t.Run("override value from arg", func(t *testing.T) {
+		mockContext, azdContext := setup(t, "")
+		args := &PipelineManagerArgs{
+			PipelineProvider: bitbucketLabel,
+		}
+
+		manager, err := createPipelineManager(t, mockContext, azdContext, nil, args)
+		assert.NoError(t, err)
+		assert.IsType(t, &BitbucketScmProvider{}, manager.scmProvider)
+		assert.IsType(t, &BitbucketCiProvider{}, manager.ciProvider)
+	})
+
+	t.Run("override value from yaml", func(t *testing.T) {
+		mockContext, azdContext := setup(t, "")
+		projectFile := "name: test\npipeline:\n  provider: bitbucket\n"
+		err := os.WriteFile(azdContext.ProjectPath(), []byte(projectFile), osutil.PermissionFile)
+		assert.NoError(t, err)
+
+		manager, err := createPipelineManager(t, mockContext, azdContext, nil, nil)
+		assert.NoError(t, err)
+		assert.IsType(t, &BitbucketScmProvider{}, manager.scmProvider)
+		assert.IsType(t, &BitbucketCiProvider{}, manager.ciProvider)
+	})
+
+	t.Run("bitbucket remote", func(t *testing.T) {
+		mockContext, azdContext := setup(t, "git@bitbucket.org:workspace/repo.git")
+		err := os.MkdirAll(filepath.Join(azdContext.ProjectDirectory(), githubFolder), osutil.PermissionDirectory)
+		assert.NoError(t, err)
+
+		env := environment.New("test")
+		manager, err := createPipelineManager(t, mockContext, azdContext, env, nil)
+		assert.NoError(t, err)
+		assert.IsType(t, &BitbucketScmProvider{}, manager.scmProvider)
+		assert.IsType(t, &BitbucketCiProvider{}, manager.ciProvider)
+
+		// the selection is persisted on the environment
+		assert.Equal(t, bitbucketLabel, env.Dotenv()[envPersistedKey])
+	})
+
+	t.Run("bitbucket pipelines yml only", func(t *testing.T) {
+		mockContext, azdContext := setup(t, "")
+		pipelinePath := filepath.Join(azdContext.ProjectDirectory(), bitbucket.PipelineYamlPath)
+		err := os.WriteFile(pipelinePath, []byte("pipelines:\n"), osutil.PermissionFile)
+		assert.NoError(t, err)
+
+		manager, err := createPipelineManager(t, mockContext, azdContext, nil, nil)
+		assert.NoError(t, err)
+		assert.IsType(t, &BitbucketScmProvider{}, manager.scmProvider)
+		assert.IsType(t, &BitbucketCiProvider{}, manager.ciProvider)
+	})
+
+	t.Run("bitbucket pipelines yml and azdo folder", func(t *testing.T) {
+		mockContext, azdContext := setup(t, "")
+		pipelinePath := filepath.Join(azdContext.ProjectDirectory(), bitbucket.PipelineYamlPath)
+		err := os.WriteFile(pipelinePath, []byte("pipelines:\n"), osutil.PermissionFile)
+		assert.NoError(t, err)
+		createAzdoPipeline(t, azdContext)
+
+		manager, err := createPipelineManager(t, mockContext, azdContext, nil, nil)
+		assert.NoError(t, err)
+		assert.IsType(t, &AzdoScmProvider{}, manager.scmProvider)
+		assert.IsType(t, &AzdoCiProvider{}, manager.ciProvider)
+	})
+
+	t.Run("persisted value overrides remote", func(t *testing.T) {
+		mockContext, azdContext := setup(t, "https://bitbucket.org/workspace/repo.git")
+		createAzdoPipeline(t, azdContext)
+
+		env := environment.NewWithValues("test", map[string]string{
+			envPersistedKey: azdoLabel,
+		})
+		manager, err := createPipelineManager(t, mockContext, azdContext, env, nil)
+		assert.NoError(t, err)
+		assert.IsType(t, &AzdoScmProvider{}, manager.scmProvider)
+		assert.IsType(t, &AzdoCiProvider{}, manager.ciProvider)
+	})
+}
+
+// createAzdoPipeline creates the Azure Pipelines definition in the project directory
+func createAzdoPipeline(t *testing.T, azdContext *azdcontext.AzdContext) {
+	err := os.MkdirAll(filepath.Join(azdContext.ProjectDirectory(), azdoFolder), osutil.PermissionDirectory)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(azdContext.ProjectDirectory(), azdoYml), []byte(""), osutil.PermissionFile)
+	assert.NoError(t, err)
+}
+
+func Test_PipelineManager_preConfigureCheck_GitHubEnvironmentFromEnv(t *testing.T) {
+	newManager := func(args *PipelineManagerArgs) *PipelineManager {
+		ghCli := &fakeGitHubCli{}
+		return &PipelineManager{
+			args:        args,
+			env:         environment.New("prod"),
+			console:     mockinput.NewMockConsole(),
+			scmProvider: &GitHubScmProvider{ghCli: ghCli},
+			ciProvider:  &GitHubCiProvider{ghCli: ghCli},
+		}
+	}
+
+	t.Run("uses azd environment name", func(t *testing.T) {
+		manager := newManager(&PipelineManagerArgs{PipelineGitHubEnvironmentFromEnv: true})
+
+		_, err := manager.preConfigureCheck(context.Background(), provisioning.Options{}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "prod", manager.args.PipelineGitHubEnvironment)
+		assert.Equal(t, "prod", manager.ciProvider.(*GitHubCiProvider).environmentName)
+	})
+
+	t.Run("both flags error", func(t *testing.T) {
+		manager := newManager(&PipelineManagerArgs{
+			PipelineGitHubEnvironment:        "production",
+			PipelineGitHubEnvironmentFromEnv: true,
+		})
+
+		_, err := manager.preConfigureCheck(context.Background(), provisioning.Options{}, "")
+		assert.ErrorContains(t, err, "only one of these parameters should be used at a time")
+	})
+}
+
+// mockGitRemote responds to getting the url of the git remote with remoteUrl, or with the error of a missing remote
+// when remoteUrl is empty
+func mockGitRemote(mockContext *mocks.MockContext, remoteUrl string) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "remote get-url")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		if remoteUrl == "" {
+			return exec.NewRunResult(2, "", "error: No such remote"), errors.New("exit code: 2")
+		}
+
+		return exec.NewRunResult(0, remoteUrl, ""), nil
+	})
+}
+
 func createPipelineManager(
 	t *testing.T,
 	mockContext *mocks.MockContext,
@@ -422,10 +573,12 @@ func createPipelineManager(
 
 	// Pipeline providers
 	pipelineProviderMap := map[string]any{
-		"github-ci":  NewGitHubCiProvider,
-		"github-scm": NewGitHubScmProvider,
-		"azdo-ci":    NewAzdoCiProvider,
-		"azdo-scm":   NewAzdoScmProvider,
+		"github-ci":     NewGitHubCiProvider,
+		"github-scm":    NewGitHubScmProvider,
+		"azdo-ci":       NewAzdoCiProvider,
+		"azdo-scm":      NewAzdoScmProvider,
+		"bitbucket-ci":  NewBitbucketCiProvider,
+		"bitbucket-scm": NewBitbucketScmProvider,
 	}
 
 	for provider, constructor := range pipelineProviderMap {
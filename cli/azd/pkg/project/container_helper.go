@@ -2,16 +2,23 @@ package project
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/benbjohnson/clock"
 )
 
@@ -20,7 +27,9 @@ type ContainerHelper struct {
 	envManager               environment.Manager
 	containerRegistryService azcli.ContainerRegistryService
 	docker                   docker.Docker
+	gitCli                   git.GitCli
 	clock                    clock.Clock
+	console                  input.Console
 }
 
 func NewContainerHelper(
@@ -29,16 +38,74 @@ func NewContainerHelper(
 	clock clock.Clock,
 	containerRegistryService azcli.ContainerRegistryService,
 	docker docker.Docker,
+	gitCli git.GitCli,
+	console input.Console,
 ) *ContainerHelper {
 	return &ContainerHelper{
 		env:                      env,
 		envManager:               envManager,
 		containerRegistryService: containerRegistryService,
 		docker:                   docker,
+		gitCli:                   gitCli,
 		clock:                    clock,
+		console:                  console,
 	}
 }
 
+// Image tag strategies that can be configured with the `docker.tagStrategy` property of a service
+const (
+	// Tags the image with the abbreviated SHA of the commit checked out in the service directory
+	TagStrategyGitSha = "git-sha"
+	// Tags the image with azd-deploy-<unix time (seconds)>, this is the default
+	TagStrategyTimestamp = "timestamp"
+	// Tags the image with the value of an environment variable, as env-var:<NAME>
+	TagStrategyEnvVarPrefix = "env-var:"
+	// Tags the image with a literal tag, as literal:<TAG>
+	TagStrategyLiteralPrefix = "literal:"
+)
+
+// dockerTagRegex matches valid docker image tags
+var dockerTagRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+
+// ValidateTagStrategy returns an error if the image tag strategy configured for the service is not supported.
+func ValidateTagStrategy(serviceConfig *ServiceConfig) error {
+	strategy := serviceConfig.Docker.TagStrategy
+	switch {
+	case strategy == "", strategy == TagStrategyGitSha, strategy == TagStrategyTimestamp:
+	case strings.HasPrefix(strategy, TagStrategyEnvVarPrefix):
+		if strings.TrimPrefix(strategy, TagStrategyEnvVarPrefix) == "" {
+			return fmt.Errorf(
+				"image tag strategy '%s' of service '%s' is missing the name of the environment variable, "+
+					"use %s<NAME>",
+				strategy,
+				serviceConfig.Name,
+				TagStrategyEnvVarPrefix)
+		}
+	case strings.HasPrefix(strategy, TagStrategyLiteralPrefix):
+		tag := strings.TrimPrefix(strategy, TagStrategyLiteralPrefix)
+		if !dockerTagRegex.MatchString(tag) {
+			return fmt.Errorf("image tag strategy '%s' of service '%s' has invalid tag '%s'", strategy, serviceConfig.Name, tag)
+		}
+	default:
+		return fmt.Errorf(
+			"unsupported image tag strategy '%s' for service '%s', supported strategies are '%s', '%s', "+
+				"'%s<NAME>' and '%s<TAG>'",
+			strategy,
+			serviceConfig.Name,
+			TagStrategyGitSha,
+			TagStrategyTimestamp,
+			TagStrategyEnvVarPrefix,
+			TagStrategyLiteralPrefix)
+	}
+
+	if strategy != "" && serviceConfig.Docker.Tag != (ExpandableString{}) {
+		return fmt.Errorf(
+			"service '%s' sets both 'docker.tag' and 'docker.tagStrategy', only one of them can be set", serviceConfig.Name)
+	}
+
+	return nil
+}
+
 func (ch *ContainerHelper) RegistryName(ctx context.Context) (string, error) {
 	loginServer, has := ch.env.LookupEnv(environment.ContainerRegistryEndpointEnvVarName)
 	if !has {
@@ -51,6 +118,43 @@ func (ch *ContainerHelper) RegistryName(ctx context.Context) (string, error) {
 	return loginServer, nil
 }
 
+// Registries returns the login servers of the container registries the image of the service is pushed to, which are
+// the registries of `docker.registries`, with references to environment variables substituted, or the registry
+// specified by AZURE_CONTAINER_REGISTRY_ENDPOINT when `docker.registries` isn't set. The service is deployed from the
+// first registry.
+func (ch *ContainerHelper) Registries(ctx context.Context, serviceConfig *ServiceConfig) ([]string, error) {
+	if len(serviceConfig.Docker.Registries) == 0 {
+		loginServer, err := ch.RegistryName(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{loginServer}, nil
+	}
+
+	registries := []string{}
+	for idx, registry := range serviceConfig.Docker.Registries {
+		loginServer, err := registry.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving 'docker.registries' of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		if loginServer == "" {
+			return nil, fmt.Errorf(
+				"registry %d of 'docker.registries' of service '%s' is empty, ensure the environment variables it uses "+
+					"are set",
+				idx,
+				serviceConfig.Name)
+		}
+
+		if !slices.Contains(registries, loginServer) {
+			registries = append(registries, loginServer)
+		}
+	}
+
+	return registries, nil
+}
+
 func (ch *ContainerHelper) RemoteImageTag(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -61,11 +165,16 @@ func (ch *ContainerHelper) RemoteImageTag(
 		return "", err
 	}
 
+	return remoteImageTag(loginServer, localImageTag), nil
+}
+
+// remoteImageTag returns the tag of the image with the given local tag in the registry with the given login server.
+func remoteImageTag(loginServer string, localImageTag string) string {
 	return fmt.Sprintf(
 		"%s/%s",
 		loginServer,
 		localImageTag,
-	), nil
+	)
 }
 
 func (ch *ContainerHelper) LocalImageTag(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
@@ -78,12 +187,190 @@ func (ch *ContainerHelper) LocalImageTag(ctx context.Context, serviceConfig *Ser
 		return configuredTag, nil
 	}
 
-	return fmt.Sprintf("%s/%s-%s:azd-deploy-%d",
+	tag, err := ch.resolveTag(ctx, serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s:%s", ch.localImageName(serviceConfig), tag), nil
+}
+
+// PackageImageTag returns the local image tag for the image with the given id, built for the service. When a tag
+// strategy is configured and the image is unchanged since it was last packaged, the tag resolved then is reused.
+// Otherwise the tag is resolved with the tag strategy and saved to the environment.
+func (ch *ContainerHelper) PackageImageTag(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	imageId string,
+) (string, error) {
+	if serviceConfig.Docker.TagStrategy == "" {
+		return ch.LocalImageTag(ctx, serviceConfig)
+	}
+
+	if savedTag := ch.env.GetServiceProperty(serviceConfig.Name, "IMAGE_TAG"); savedTag != "" {
+		savedImage := fmt.Sprintf("%s:%s", ch.localImageName(serviceConfig), savedTag)
+		savedImageId, err := ch.docker.Inspect(ctx, savedImage, "{{.Id}}")
+		if err != nil {
+			log.Printf("inspecting image %s, resolving new tag: %v", savedImage, err)
+		} else if strings.TrimSpace(savedImageId) == imageId {
+			return savedImage, nil
+		}
+	}
+
+	localTag, err := ch.LocalImageTag(ctx, serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	_, tag := docker.SplitDockerImage(localTag)
+	ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_TAG", tag)
+	if err := ch.envManager.Save(ctx, ch.env); err != nil {
+		return "", fmt.Errorf("saving image tag to environment: %w", err)
+	}
+
+	return localTag, nil
+}
+
+// LoginCacheRegistries logs into the registries of the service, see Registries, that the build cache refers to, since
+// the cache is read from and written to them while the image is built.
+func (ch *ContainerHelper) LoginCacheRegistries(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	cache *docker.BuildCache,
+) error {
+	if cache.IsEmpty() {
+		return nil
+	}
+
+	registries, err := ch.Registries(ctx, serviceConfig)
+	if err != nil {
+		// the cache can still refer to other registries, which the user logs into
+		log.Printf("not logging into container registries for the build cache of service %s: %v", serviceConfig.Name, err)
+		return nil
+	}
+
+	entries := append(slices.Clone(cache.From), cache.To...)
+	for _, loginServer := range registries {
+		refersTo := func(entry string) bool {
+			return strings.EqualFold(cacheRegistry(entry), loginServer)
+		}
+		if !slices.ContainsFunc(entries, refersTo) {
+			continue
+		}
+
+		log.Printf("logging into container registry '%s' for the build cache of service %s", loginServer, serviceConfig.Name)
+		if err := ch.containerRegistryService.Login(ctx, ch.env.GetSubscriptionId(), loginServer); err != nil {
+			return fmt.Errorf("logging into container registry '%s' for the build cache: %w", loginServer, err)
+		}
+	}
+
+	return nil
+}
+
+// cacheRegistry returns the registry of the image that a cache entry refers to, for example 'contoso.azurecr.io' for
+// 'type=registry,ref=contoso.azurecr.io/api:cache', or "" when the cache isn't stored in a registry.
+func cacheRegistry(entry string) string {
+	ref := entry
+	if strings.Contains(entry, "=") {
+		ref = ""
+		for _, attr := range strings.Split(entry, ",") {
+			key, value, _ := strings.Cut(attr, "=")
+			switch strings.TrimSpace(key) {
+			case "type":
+				if strings.TrimSpace(value) != "registry" {
+					return ""
+				}
+			case "ref":
+				ref = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	registry, _, found := strings.Cut(ref, "/")
+	if !found {
+		return ""
+	}
+
+	return registry
+}
+
+// BuildCache returns the cache configured with the `docker.cacheFrom` and `docker.cacheTo` properties of the service,
+// with references to environment variables substituted. Nil is returned when no cache is configured.
+func (ch *ContainerHelper) BuildCache(ctx context.Context, serviceConfig *ServiceConfig) (*docker.BuildCache, error) {
+	cache := &docker.BuildCache{}
+	for _, from := range serviceConfig.Docker.CacheFrom {
+		value, err := from.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving 'docker.cacheFrom' of service '%s': %w", serviceConfig.Name, err)
+		}
+		if value != "" {
+			cache.From = append(cache.From, value)
+		}
+	}
+
+	for _, to := range serviceConfig.Docker.CacheTo {
+		value, err := to.Envsubst(ch.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving 'docker.cacheTo' of service '%s': %w", serviceConfig.Name, err)
+		}
+		if value != "" {
+			cache.To = append(cache.To, value)
+		}
+	}
+
+	if cache.IsEmpty() {
+		return nil, nil
+	}
+
+	return cache, nil
+}
+
+// localImageName returns the name, without a tag, of the image built for the service.
+func (ch *ContainerHelper) localImageName(serviceConfig *ServiceConfig) string {
+	return fmt.Sprintf("%s/%s-%s",
 		strings.ToLower(serviceConfig.Project.Name),
 		strings.ToLower(serviceConfig.Name),
 		strings.ToLower(ch.env.GetEnvName()),
-		ch.clock.Now().Unix(),
-	), nil
+	)
+}
+
+// resolveTag resolves the image tag for the service with the configured tag strategy.
+func (ch *ContainerHelper) resolveTag(ctx context.Context, serviceConfig *ServiceConfig) (string, error) {
+	if err := ValidateTagStrategy(serviceConfig); err != nil {
+		return "", err
+	}
+
+	strategy := serviceConfig.Docker.TagStrategy
+	switch {
+	case strategy == TagStrategyGitSha:
+		sha, err := ch.gitCli.GetShortCommitSha(ctx, serviceConfig.Path())
+		if err != nil {
+			return "", fmt.Errorf("resolving image tag with strategy '%s': %w", strategy, err)
+		}
+
+		return sha, nil
+	case strings.HasPrefix(strategy, TagStrategyEnvVarPrefix):
+		name := strings.TrimPrefix(strategy, TagStrategyEnvVarPrefix)
+		tag := ch.env.Getenv(name)
+		if tag == "" {
+			return "", fmt.Errorf(
+				"environment variable '%s' used by the image tag strategy of service '%s' is not set", name, serviceConfig.Name)
+		}
+
+		if !dockerTagRegex.MatchString(tag) {
+			return "", fmt.Errorf(
+				"environment variable '%s' used by the image tag strategy of service '%s' has invalid tag '%s'",
+				name,
+				serviceConfig.Name,
+				tag)
+		}
+
+		return tag, nil
+	case strings.HasPrefix(strategy, TagStrategyLiteralPrefix):
+		return strings.TrimPrefix(strategy, TagStrategyLiteralPrefix), nil
+	default:
+		return fmt.Sprintf("azd-deploy-%d", ch.clock.Now().Unix()), nil
+	}
 }
 
 func (ch *ContainerHelper) RequiredExternalTools(context.Context) []tools.ExternalTool {
@@ -104,6 +391,16 @@ func (ch *ContainerHelper) Login(
 	return loginServer, ch.containerRegistryService.Login(ctx, targetResource.SubscriptionId(), loginServer)
 }
 
+// AdminCredentials returns the credentials of the admin user of the container registry loginServer, for resources that
+// keep pulling images from the registry with a username and password.
+func (ch *ContainerHelper) AdminCredentials(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	loginServer string,
+) (*azcli.DockerCredentials, error) {
+	return ch.containerRegistryService.AdminCredentials(ctx, targetResource.SubscriptionId(), loginServer)
+}
+
 func (ch *ContainerHelper) Deploy(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -112,12 +409,13 @@ func (ch *ContainerHelper) Deploy(
 ) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
-			// Get ACR Login Server
-			loginServer, err := ch.RegistryName(ctx)
+			// Get the login servers of the registries, the service is deployed from the first one
+			registries, err := ch.Registries(ctx, serviceConfig)
 			if err != nil {
 				task.SetError(err)
 				return
 			}
+			loginServer := registries[0]
 
 			localImageTag := packageOutput.PackagePath
 			packageDetails, ok := packageOutput.Details.(*dockerPackageResult)
@@ -130,32 +428,23 @@ func (ch *ContainerHelper) Deploy(
 				return
 			}
 
-			// Tag image
-			// Get remote tag from the container helper then call docker cli tag command
-			remoteTag, err := ch.RemoteImageTag(ctx, serviceConfig, localImageTag)
-			if err != nil {
-				task.SetError(fmt.Errorf("getting remote image tag: %w", err))
-				return
-			}
+			remoteTag := remoteImageTag(loginServer, localImageTag)
 
-			task.SetProgress(NewServiceProgress("Tagging container image"))
-			if err := ch.docker.Tag(ctx, serviceConfig.Path(), localImageTag, remoteTag); err != nil {
-				task.SetError(err)
-				return
-			}
-
-			log.Printf("logging into container registry '%s'\n", loginServer)
-			task.SetProgress(NewServiceProgress("Logging into container registry"))
-			err = ch.containerRegistryService.Login(ctx, targetResource.SubscriptionId(), loginServer)
-			if err != nil {
-				task.SetError(err)
-				return
-			}
-
-			// Push image.
-			log.Printf("pushing %s to registry", remoteTag)
-			task.SetProgress(NewServiceProgress("Pushing container image"))
-			if err := ch.docker.Push(ctx, serviceConfig.Path(), remoteTag); err != nil {
+			if ok && packageDetails != nil && packageDetails.RemoteImage != "" {
+				// the image was pushed when the service was packaged, see PushPackage
+				log.Printf("deploying %s pushed by azd package", packageDetails.RemoteImage)
+				remoteTag = packageDetails.RemoteImage
+			} else if serviceConfig.Docker.RemoteBuild {
+				// Build the image in the registry, which pushes it with the remote tag.
+				log.Printf("building %s in registry", remoteTag)
+				task.SetProgress(NewServiceProgress("Building container image in registry"))
+				if err := ch.remoteBuild(ctx, serviceConfig, targetResource, loginServer, localImageTag); err != nil {
+					task.SetError(err)
+					return
+				}
+			} else if err := ch.push(
+				ctx, task.SetProgress, serviceConfig, targetResource.SubscriptionId(), registries, localImageTag,
+			); err != nil {
 				task.SetError(err)
 				return
 			}
@@ -174,3 +463,276 @@ func (ch *ContainerHelper) Deploy(
 			})
 		})
 }
+
+// DeployPreview returns the steps that build and push the image of the service when it's deployed, and compares the image
+// with the image the service was last deployed with. When the package is nil, the image would be built before it's
+// pushed. Nothing is built, tagged or pushed.
+func (ch *ContainerHelper) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) ([]ServiceDeployStep, *ServiceDeployImage, error) {
+	registries, err := ch.Registries(ctx, serviceConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	loginServer := registries[0]
+
+	steps := []ServiceDeployStep{}
+	image := &ServiceDeployImage{
+		Current: ch.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME"),
+	}
+
+	var localImageTag string
+	var pushedImage string
+	if packageOutput == nil {
+		localImageTag, err = ch.LocalImageTag(ctx, serviceConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating local image tag: %w", err)
+		}
+
+		dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+		dockerfilePath := filepath.Join(serviceConfig.Path(), dockerOptions.Path)
+		switch _, statErr := os.Stat(dockerfilePath); {
+		case serviceConfig.Docker.RemoteBuild:
+			steps = append(steps, ServiceDeployStep{
+				Kind:        DeployStepBuild,
+				Description: fmt.Sprintf("Build the image from %s in registry %s", dockerOptions.Path, loginServer),
+			})
+		case statErr == nil:
+			steps = append(steps, ServiceDeployStep{
+				Kind:        DeployStepBuild,
+				Description: fmt.Sprintf("Build the image from %s", dockerOptions.Path),
+			})
+		default:
+			steps = append(steps, ServiceDeployStep{
+				Kind:        DeployStepBuild,
+				Description: "Build the image from source with pack",
+			})
+		}
+	} else {
+		localImageTag = packageOutput.PackagePath
+		packageDetails, ok := packageOutput.Details.(*dockerPackageResult)
+		if ok && packageDetails != nil {
+			localImageTag = packageDetails.ImageTag
+			pushedImage = packageDetails.RemoteImage
+		}
+
+		if pushedImage != "" {
+			steps = append(steps, ServiceDeployStep{
+				Kind:        DeployStepPackage,
+				Description: fmt.Sprintf("Deploy the image %s pushed when the service was packaged", pushedImage),
+			})
+			image.PlannedId = packageDetails.ImageHash
+		} else {
+			steps = append(steps, ServiceDeployStep{
+				Kind:        DeployStepPackage,
+				Description: fmt.Sprintf("Deploy the existing image %s", localImageTag),
+			})
+			image.PlannedId = ch.localImageId(ctx, localImageTag)
+		}
+	}
+
+	// images built in the registry are pushed by the build, and packaged images may have been pushed already
+	if !serviceConfig.Docker.RemoteBuild && pushedImage == "" {
+		for _, registry := range registries {
+			steps = append(steps, ServiceDeployStep{
+				Kind:        DeployStepPush,
+				Description: fmt.Sprintf("Push the image %s", remoteImageTag(registry, localImageTag)),
+			})
+		}
+	}
+
+	image.Planned = remoteImageTag(loginServer, localImageTag)
+	if pushedImage != "" {
+		image.Planned = pushedImage
+	}
+	if image.Current != "" {
+		image.CurrentId = ch.localImageId(ctx, image.Current)
+	}
+
+	return steps, image, nil
+}
+
+// localImageId returns the id of the image in the local image store, or an empty string when it isn't in the store.
+func (ch *ContainerHelper) localImageId(ctx context.Context, image string) string {
+	imageId, err := ch.docker.Inspect(ctx, image, "{{.Id}}")
+	if err != nil {
+		log.Printf("inspecting image %s: %v", image, err)
+		return ""
+	}
+
+	return strings.TrimSpace(imageId)
+}
+
+// push tags the image with the given local tag for each registry, logs into each registry and then pushes the image to
+// each registry. All the registries are logged into before pushing, so that a registry that can't be accessed fails the
+// deployment before the image is pushed to any registry. When pushing to a registry fails, the error reports the
+// registries the image was pushed to.
+func (ch *ContainerHelper) push(
+	ctx context.Context,
+	setProgress func(ServiceProgress),
+	serviceConfig *ServiceConfig,
+	subscriptionId string,
+	registries []string,
+	localImageTag string,
+) error {
+	// progress reports the progress of a step, with the registry when pushing to multiple registries
+	progress := func(message string, loginServer string) {
+		if len(registries) > 1 {
+			message = fmt.Sprintf("%s (%s)", message, loginServer)
+		}
+		setProgress(NewServiceProgress(message))
+	}
+
+	remoteTags := make([]string, len(registries))
+	for idx, loginServer := range registries {
+		remoteTags[idx] = remoteImageTag(loginServer, localImageTag)
+
+		progress("Tagging container image", loginServer)
+		if err := ch.docker.Tag(ctx, serviceConfig.Path(), localImageTag, remoteTags[idx]); err != nil {
+			return err
+		}
+	}
+
+	for _, loginServer := range registries {
+		log.Printf("logging into container registry '%s'\n", loginServer)
+		progress("Logging into container registry", loginServer)
+		err := ch.containerRegistryService.Login(ctx, subscriptionId, loginServer)
+		if err != nil && len(registries) > 1 {
+			return fmt.Errorf("logging into container registry '%s': %w", loginServer, err)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	for idx, remoteTag := range remoteTags {
+		log.Printf("pushing %s to registry", remoteTag)
+		progress("Pushing container image", registries[idx])
+		err := ch.docker.Push(ctx, serviceConfig.Path(), remoteTag)
+		switch {
+		case err != nil && idx > 0:
+			return fmt.Errorf(
+				"pushing container image to registry '%s' failed, the image was pushed to %s and not to %s: %w",
+				registries[idx],
+				strings.Join(registries[:idx], ", "),
+				strings.Join(registries[idx:], ", "),
+				err)
+		case err != nil && len(registries) > 1:
+			return fmt.Errorf("pushing container image to registry '%s': %w", registries[idx], err)
+		case err != nil:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PushPackage pushes the image packaged for the service to the registries of the service, see Registries, and records the
+// reference of the image in the first registry, pinned by its digest, in the package result. A package result recorded
+// in the package manifest then refers to an image that can be deployed from another machine, see PackageManifest.
+// Returns the recorded reference, or an empty string when the package isn't an image in the local image store, like an
+// image that is built in the registry when it's deployed, which is left unchanged.
+func (ch *ContainerHelper) PushPackage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+	setProgress func(ServiceProgress),
+) (string, error) {
+	packageDetails, ok := packageResult.Details.(*dockerPackageResult)
+	if !ok || packageDetails == nil || packageDetails.ImageHash == "" {
+		return "", nil
+	}
+
+	registries, err := ch.Registries(ctx, serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	err = ch.push(ctx, setProgress, serviceConfig, ch.env.GetSubscriptionId(), registries, packageDetails.ImageTag)
+	if err != nil {
+		return "", err
+	}
+
+	remoteTag := remoteImageTag(registries[0], packageDetails.ImageTag)
+	packageDetails.RemoteImage = remoteTag
+	if digest := ch.repoDigest(ctx, remoteTag); digest != "" {
+		imageName, _ := docker.SplitDockerImage(remoteTag)
+		packageDetails.RemoteImage = imageName + "@" + digest
+	}
+
+	return packageDetails.RemoteImage, nil
+}
+
+// repoDigest returns the digest of the image pushed with the given remote tag, or an empty string when it isn't known.
+func (ch *ContainerHelper) repoDigest(ctx context.Context, remoteTag string) string {
+	output, err := ch.docker.Inspect(ctx, remoteTag, "{{json .RepoDigests}}")
+	if err != nil {
+		log.Printf("inspecting image %s: %v", remoteTag, err)
+		return ""
+	}
+
+	var repoDigests []string
+	if err := json.Unmarshal([]byte(output), &repoDigests); err != nil {
+		log.Printf("parsing digests of image %s: %v", remoteTag, err)
+		return ""
+	}
+
+	// the digests are listed as <repository>@<digest> for each repository the image was pushed to
+	imageName, _ := docker.SplitDockerImage(remoteTag)
+	for _, repoDigest := range repoDigests {
+		if digest, has := strings.CutPrefix(repoDigest, imageName+"@"); has {
+			return digest
+		}
+	}
+
+	return ""
+}
+
+// remoteBuild builds the image of the service with an ACR task run in the registry, instead of the local docker daemon,
+// streaming the log of the run to the console. The run pushes the image to the registry as localImageTag.
+func (ch *ContainerHelper) remoteBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	loginServer string,
+	localImageTag string,
+) error {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	contextDir := filepath.Join(serviceConfig.Path(), dockerOptions.Context)
+	dockerfilePath, err := filepath.Rel(contextDir, filepath.Join(serviceConfig.Path(), dockerOptions.Path))
+	if err != nil || dockerfilePath == ".." || strings.HasPrefix(dockerfilePath, ".."+string(filepath.Separator)) {
+		return fmt.Errorf(
+			"building service '%s' in the registry requires the Dockerfile '%s' to be within the build context '%s'",
+			serviceConfig.Name,
+			dockerOptions.Path,
+			dockerOptions.Context,
+		)
+	}
+
+	previewerWriter := ch.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        "Registry Build Output",
+		})
+	err = ch.containerRegistryService.Build(
+		ctx,
+		targetResource.SubscriptionId(),
+		loginServer,
+		&azcli.RemoteBuildRequest{
+			ContextDir:     contextDir,
+			DockerfilePath: filepath.ToSlash(dockerfilePath),
+			ImageNames:     []string{localImageTag},
+			Platform:       dockerOptions.Platform,
+			BuildArgs:      dockerOptions.BuildArgs,
+		},
+		previewerWriter,
+	)
+	ch.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return fmt.Errorf("building container: %s in registry %s: %w", serviceConfig.Name, loginServer, err)
+	}
+
+	return nil
+}
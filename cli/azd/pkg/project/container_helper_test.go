@@ -2,10 +2,20 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/benbjohnson/clock"
@@ -50,7 +60,7 @@ func Test_ContainerHelper_LocalImageTag(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			env := environment.NewWithValues("dev", map[string]string{})
-			containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil)
+			containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil)
 			serviceConfig.Docker = tt.dockerConfig
 
 			tag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
@@ -66,7 +76,7 @@ func Test_ContainerHelper_RemoteImageTag(t *testing.T) {
 		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
 	})
 	envManager := &mockenv.MockEnvManager{}
-	containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil)
+	containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil)
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 	localTag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
 	require.NoError(t, err)
@@ -81,9 +91,523 @@ func Test_ContainerHelper_RemoteImageTag_NoContainer_Registry(t *testing.T) {
 	env := environment.New("test")
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 	envManager := &mockenv.MockEnvManager{}
-	containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil)
+	containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, nil, nil)
 
 	imageTag, err := containerHelper.RemoteImageTag(*mockContext.Context, serviceConfig, "local_tag")
 	require.Error(t, err)
 	require.Empty(t, imageTag)
 }
+
+func Test_ContainerHelper_LocalImageTag_TagStrategy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "git") && strings.Contains(command, "rev-parse --short HEAD")
+	}).Respond(exec.NewRunResult(0, "abc1234\n", ""))
+
+	envManager := &mockenv.MockEnvManager{}
+	defaultImageName := "test-app/api-dev"
+
+	tests := []struct {
+		name        string
+		tagStrategy string
+		want        string
+		wantErr     string
+	}{
+		{"Timestamp", TagStrategyTimestamp, defaultImageName + ":azd-deploy-0", ""},
+		{"GitSha", TagStrategyGitSha, defaultImageName + ":abc1234", ""},
+		{"EnvVar", "env-var:RELEASE", defaultImageName + ":v1.2.3", ""},
+		{"EnvVarNotSet", "env-var:MISSING", "", "environment variable 'MISSING'"},
+		{"EnvVarNoName", "env-var:", "", "missing the name of the environment variable"},
+		{"Literal", "literal:stable", defaultImageName + ":stable", ""},
+		{"LiteralInvalid", "literal:not/valid", "", "invalid tag 'not/valid'"},
+		{"Unknown", "semver", "", "unsupported image tag strategy 'semver'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := environment.NewWithValues("dev", map[string]string{
+				"RELEASE": "v1.2.3",
+			})
+			containerHelper := NewContainerHelper(
+				env, envManager, clock.NewMock(), nil, nil, git.NewGitCli(mockContext.CommandRunner), nil)
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.TagStrategy = tt.tagStrategy
+
+			tag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tag)
+		})
+	}
+}
+
+func Test_ValidateTagStrategy_TagAlsoSet(t *testing.T) {
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.TagStrategy = TagStrategyGitSha
+	serviceConfig.Docker.Tag = NewExpandableString("contoso/contoso-image:latest")
+
+	require.ErrorContains(t, ValidateTagStrategy(serviceConfig), "only one of them can be set")
+}
+
+func Test_ContainerHelper_PackageImageTag(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect") &&
+			strings.Contains(command, "test-app/api-dev:azd-deploy-100")
+	}).Respond(exec.NewRunResult(0, "IMAGE_ID\n", ""))
+
+	env := environment.New("dev")
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	mockClock := clock.NewMock()
+	mockClock.Add(100 * time.Second)
+	containerHelper := NewContainerHelper(
+		env, envManager, mockClock, nil, docker.NewDocker(mockContext.CommandRunner), nil, nil)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.TagStrategy = TagStrategyTimestamp
+
+	// the resolved tag is saved to the environment
+	tag, err := containerHelper.PackageImageTag(*mockContext.Context, serviceConfig, "IMAGE_ID")
+	require.NoError(t, err)
+	require.Equal(t, "test-app/api-dev:azd-deploy-100", tag)
+	require.Equal(t, "azd-deploy-100", env.GetServiceProperty("api", "IMAGE_TAG"))
+
+	// packaging the same image again reuses the saved tag
+	mockClock.Add(100 * time.Second)
+	tag, err = containerHelper.PackageImageTag(*mockContext.Context, serviceConfig, "IMAGE_ID")
+	require.NoError(t, err)
+	require.Equal(t, "test-app/api-dev:azd-deploy-100", tag)
+
+	// a different image gets a new tag
+	tag, err = containerHelper.PackageImageTag(*mockContext.Context, serviceConfig, "OTHER_IMAGE_ID")
+	require.NoError(t, err)
+	require.Equal(t, "test-app/api-dev:azd-deploy-200", tag)
+	require.Equal(t, "azd-deploy-200", env.GetServiceProperty("api", "IMAGE_TAG"))
+}
+
+func Test_ContainerHelper_Deploy_RemoteBuild(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+	})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	registryService := &fakeContainerRegistryService{}
+	// docker is not used to build images in the registry, so no docker commands are mocked
+	containerHelper := NewContainerHelper(
+		env, envManager, clock.NewMock(), registryService, docker.NewDocker(mockContext.CommandRunner), nil,
+		mockContext.Console)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker = DockerProjectOptions{
+		Context:     "..",
+		Path:        "Dockerfile.api",
+		Platform:    "linux/arm64",
+		BuildArgs:   []string{"NODE_ENV=production"},
+		RemoteBuild: true,
+	}
+	targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{
+		PackagePath: "test-app/api-dev:azd-deploy-0",
+	}, targetResource)
+	logProgress(deployTask)
+
+	_, err := deployTask.Await()
+	require.NoError(t, err)
+
+	require.Equal(t, "SUBSCRIPTION_ID", registryService.subscriptionId)
+	require.Equal(t, "contoso.azurecr.io", registryService.loginServer)
+	require.Equal(t, &azcli.RemoteBuildRequest{
+		ContextDir:     filepath.Join(serviceConfig.Path(), ".."),
+		DockerfilePath: "api/Dockerfile.api",
+		ImageNames:     []string{"test-app/api-dev:azd-deploy-0"},
+		Platform:       "linux/arm64",
+		BuildArgs:      []string{"NODE_ENV=production"},
+	}, registryService.request)
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0", env.GetServiceProperty("api", "IMAGE_NAME"))
+}
+
+func Test_ContainerHelper_Deploy_RemoteBuild_DockerfileOutsideContext(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+	})
+
+	registryService := &fakeContainerRegistryService{}
+	containerHelper := NewContainerHelper(
+		env, &mockenv.MockEnvManager{}, clock.NewMock(), registryService, nil, nil, mockContext.Console)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker = DockerProjectOptions{
+		Context:     "./app",
+		Path:        "./Dockerfile",
+		RemoteBuild: true,
+	}
+	targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{
+		PackagePath: "test-app/api-dev:azd-deploy-0",
+	}, targetResource)
+	logProgress(deployTask)
+
+	_, err := deployTask.Await()
+	require.ErrorContains(t, err, "requires the Dockerfile './Dockerfile' to be within the build context './app'")
+	require.Nil(t, registryService.request)
+}
+
+func Test_ContainerHelper_Deploy_Registries(t *testing.T) {
+	tests := []struct {
+		name          string
+		failLogin     string
+		failPush      string
+		expectPushed  []string
+		expectedError string
+	}{
+		{
+			name: "Success",
+			expectPushed: []string{
+				"contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+				"contosoeu.azurecr.io/test-app/api-dev:azd-deploy-0",
+			},
+		},
+		{
+			name:          "LoginFailure",
+			failLogin:     "contosoeu.azurecr.io",
+			expectedError: "logging into container registry 'contosoeu.azurecr.io': unauthorized",
+		},
+		{
+			name:         "PushFailure",
+			failPush:     "contosoeu.azurecr.io/test-app/api-dev:azd-deploy-0",
+			expectPushed: []string{"contoso.azurecr.io/test-app/api-dev:azd-deploy-0"},
+			expectedError: "pushing container image to registry 'contosoeu.azurecr.io' failed, the image was pushed " +
+				"to contoso.azurecr.io and not to contosoeu.azurecr.io",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			env := environment.NewWithValues("dev", map[string]string{
+				environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+			})
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+			tagged := []string{}
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker tag")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				tagged = append(tagged, args.Args[2])
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			var pushed []string
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker push")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if args.Args[1] == tt.failPush {
+					return exec.NewRunResult(1, "", "denied"), errors.New("denied")
+				}
+				pushed = append(pushed, args.Args[1])
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			registryService := &loginRegistryService{failLogin: tt.failLogin}
+			containerHelper := NewContainerHelper(
+				env, envManager, clock.NewMock(), registryService, docker.NewDocker(mockContext.CommandRunner), nil,
+				mockContext.Console)
+
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.Registries = []ExpandableString{
+				NewExpandableString("${AZURE_CONTAINER_REGISTRY_ENDPOINT}"),
+				NewExpandableString("contosoeu.azurecr.io"),
+				NewExpandableString("contoso.azurecr.io"),
+			}
+			targetResource := environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", "")
+
+			deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, &ServicePackageResult{
+				PackagePath: "test-app/api-dev:azd-deploy-0",
+			}, targetResource)
+			logProgress(deployTask)
+			_, err := deployTask.Await()
+
+			// the image is tagged for each registry once
+			require.Equal(t, []string{
+				"contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+				"contosoeu.azurecr.io/test-app/api-dev:azd-deploy-0",
+			}, tagged)
+			require.Equal(t, tt.expectPushed, pushed)
+
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				require.Empty(t, env.GetServiceProperty("api", "IMAGE_NAME"))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []string{"contoso.azurecr.io", "contosoeu.azurecr.io"}, registryService.logins)
+			// the service is deployed from the first registry
+			require.Equal(t, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0", env.GetServiceProperty("api", "IMAGE_NAME"))
+		})
+	}
+}
+
+func Test_ContainerHelper_PushPackage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+		environment.SubscriptionIdEnvVarName:            "SUBSCRIPTION_ID",
+	})
+
+	var pushed []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker tag")
+	}).Respond(exec.NewRunResult(0, "", ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker push")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		pushed = append(pushed, args.Args[1])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).Respond(exec.NewRunResult(0, `["contoso.azurecr.io/test-app/api-dev@sha256:4567"]`, ""))
+
+	registryService := &loginRegistryService{}
+	containerHelper := NewContainerHelper(
+		env, &mockenv.MockEnvManager{}, clock.NewMock(), registryService, docker.NewDocker(mockContext.CommandRunner), nil,
+		mockContext.Console)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-dev:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "sha256:0123",
+			ImageTag:  "test-app/api-dev:azd-deploy-0",
+		},
+	}
+	remoteImage, err := containerHelper.PushPackage(
+		*mockContext.Context, serviceConfig, packageResult, func(ServiceProgress) {})
+	require.NoError(t, err)
+
+	// the pushed image is pinned by its digest
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev@sha256:4567", remoteImage)
+	require.Equal(t, remoteImage, packageResult.Details.(*dockerPackageResult).RemoteImage)
+	require.Equal(t, []string{"contoso.azurecr.io/test-app/api-dev:azd-deploy-0"}, pushed)
+	require.Equal(t, []string{"contoso.azurecr.io"}, registryService.logins)
+
+	// images built in the registry aren't pushed
+	remoteImage, err = containerHelper.PushPackage(*mockContext.Context, serviceConfig, &ServicePackageResult{
+		PackagePath: "test-app/api-dev:azd-deploy-0",
+		Details:     &dockerPackageResult{ImageTag: "test-app/api-dev:azd-deploy-0"},
+	}, func(ServiceProgress) {})
+	require.NoError(t, err)
+	require.Empty(t, remoteImage)
+	require.Len(t, pushed, 1)
+
+	// deploying the pushed package doesn't push the image again
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+	containerHelper = NewContainerHelper(
+		env, envManager, clock.NewMock(), registryService, docker.NewDocker(mockContext.CommandRunner), nil,
+		mockContext.Console)
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, packageResult,
+		environment.NewTargetResource("SUBSCRIPTION_ID", "RESOURCE_GROUP", "", ""))
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+	require.Len(t, pushed, 1)
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev@sha256:4567", env.GetServiceProperty("api", "IMAGE_NAME"))
+}
+
+func Test_ContainerHelper_Registries_Empty(t *testing.T) {
+	env := environment.NewWithValues("dev", map[string]string{})
+	containerHelper := NewContainerHelper(env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, nil, nil, nil)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registries = []ExpandableString{NewExpandableString("${REGISTRY_ENDPOINT}")}
+
+	_, err := containerHelper.Registries(context.Background(), serviceConfig)
+	require.EqualError(t, err, "registry 0 of 'docker.registries' of service 'api' is empty, ensure the environment "+
+		"variables it uses are set")
+}
+
+func Test_cacheRegistry(t *testing.T) {
+	tests := map[string]string{
+		"type=registry,ref=contoso.azurecr.io/api:cache":          "contoso.azurecr.io",
+		"ref=contoso.azurecr.io/api:cache,type=registry,mode=max": "contoso.azurecr.io",
+		"contoso.azurecr.io/api:cache":                            "contoso.azurecr.io",
+		"type=gha":                                                "",
+		"type=local,src=/tmp/cache":                               "",
+		"api:cache":                                               "",
+	}
+
+	for entry, want := range tests {
+		require.Equal(t, want, cacheRegistry(entry), entry)
+	}
+}
+
+// loginRegistryService records the registries logged into, failing to log into the registry failLogin.
+type loginRegistryService struct {
+	fakeContainerRegistryService
+	failLogin string
+	logins    []string
+}
+
+func (l *loginRegistryService) Login(ctx context.Context, subscriptionId string, loginServer string) error {
+	if loginServer == l.failLogin {
+		return errors.New("unauthorized")
+	}
+
+	l.logins = append(l.logins, loginServer)
+	return nil
+}
+
+// fakeContainerRegistryService records the image built in the registry.
+type fakeContainerRegistryService struct {
+	subscriptionId string
+	loginServer    string
+	request        *azcli.RemoteBuildRequest
+}
+
+func (f *fakeContainerRegistryService) Login(ctx context.Context, subscriptionId string, loginServer string) error {
+	return errors.New("images built in the registry don't require a docker login")
+}
+
+func (f *fakeContainerRegistryService) GetContainerRegistries(
+	ctx context.Context,
+	subscriptionId string,
+) ([]*armcontainerregistry.Registry, error) {
+	return nil, nil
+}
+
+func (f *fakeContainerRegistryService) AdminCredentials(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+) (*azcli.DockerCredentials, error) {
+	return nil, errors.New("images built in the registry don't require credentials")
+}
+
+func (f *fakeContainerRegistryService) Build(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	request *azcli.RemoteBuildRequest,
+	progress io.Writer,
+) error {
+	f.subscriptionId = subscriptionId
+	f.loginServer = loginServer
+	f.request = request
+	_, err := progress.Write([]byte("Run ID: ca1 was successful\n"))
+	return err
+}
+
+func Test_ContainerHelper_DeployPreview(t *testing.T) {
+	newContainerHelper := func(mockContext *mocks.MockContext) (*ContainerHelper, *ServiceConfig) {
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker image inspect") &&
+				strings.Contains(command, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0")
+		}).Respond(exec.NewRunResult(0, "CURRENT_ID\n", ""))
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker image inspect") &&
+				strings.Contains(command, "test-app/api-dev:azd-deploy-1")
+		}).Respond(exec.NewRunResult(0, "PACKAGE_ID\n", ""))
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker image inspect") &&
+				strings.Contains(command, "test-app/api-dev:azd-deploy-0")
+		}).Respond(exec.NewRunResult(0, "CURRENT_ID\n", ""))
+
+		env := environment.NewWithValues("dev", map[string]string{
+			environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+			"SERVICE_API_IMAGE_NAME":                        "contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+		})
+		mockClock := clock.NewMock()
+		mockClock.Add(100 * time.Second)
+
+		// nothing is saved, tagged or pushed, so no environment manager or registry service is used
+		containerHelper := NewContainerHelper(
+			env, nil, mockClock, nil, docker.NewDocker(mockContext.CommandRunner), nil, mockContext.Console)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		return containerHelper, serviceConfig
+	}
+
+	t.Run("Build", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper, serviceConfig := newContainerHelper(mockContext)
+		serviceConfig.Docker.Registries = []ExpandableString{
+			NewExpandableString("contoso.azurecr.io"),
+			NewExpandableString("fabrikam.azurecr.io"),
+		}
+
+		steps, image, err := containerHelper.DeployPreview(*mockContext.Context, serviceConfig, nil)
+		require.NoError(t, err)
+		require.Equal(t, []ServiceDeployStep{
+			{Kind: DeployStepBuild, Description: "Build the image from source with pack"},
+			{Kind: DeployStepPush, Description: "Push the image contoso.azurecr.io/test-app/api-dev:azd-deploy-100"},
+			{Kind: DeployStepPush, Description: "Push the image fabrikam.azurecr.io/test-app/api-dev:azd-deploy-100"},
+		}, steps)
+		require.Equal(t, &ServiceDeployImage{
+			Current:   "contoso.azurecr.io/test-app/api-dev:azd-deploy-0",
+			CurrentId: "CURRENT_ID",
+			Planned:   "contoso.azurecr.io/test-app/api-dev:azd-deploy-100",
+		}, image)
+	})
+
+	t.Run("RemoteBuild", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper, serviceConfig := newContainerHelper(mockContext)
+		serviceConfig.Docker.RemoteBuild = true
+
+		steps, _, err := containerHelper.DeployPreview(*mockContext.Context, serviceConfig, nil)
+		require.NoError(t, err)
+		require.Equal(t, []ServiceDeployStep{
+			{Kind: DeployStepBuild, Description: "Build the image from ./Dockerfile in registry contoso.azurecr.io"},
+		}, steps)
+	})
+
+	t.Run("FromPackage", func(t *testing.T) {
+		for name, test := range map[string]struct {
+			packagePath string
+			changed     bool
+		}{
+			"Changed":   {packagePath: "test-app/api-dev:azd-deploy-1", changed: true},
+			"Unchanged": {packagePath: "test-app/api-dev:azd-deploy-0", changed: false},
+		} {
+			t.Run(name, func(t *testing.T) {
+				mockContext := mocks.NewMockContext(context.Background())
+				containerHelper, serviceConfig := newContainerHelper(mockContext)
+
+				steps, image, err := containerHelper.DeployPreview(*mockContext.Context, serviceConfig, &ServicePackageResult{
+					PackagePath: test.packagePath,
+				})
+				require.NoError(t, err)
+				require.Equal(t, ServiceDeployStep{
+					Kind:        DeployStepPackage,
+					Description: fmt.Sprintf("Deploy the existing image %s", test.packagePath),
+				}, steps[0])
+				require.NotEmpty(t, image.CurrentId)
+				require.NotEmpty(t, image.PlannedId)
+				require.Equal(t, test.changed, image.CurrentId != image.PlannedId)
+			})
+		}
+	})
+
+	t.Run("NoRegistry", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper := NewContainerHelper(
+			environment.New("dev"), nil, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner), nil, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		_, _, err := containerHelper.DeployPreview(*mockContext.Context, serviceConfig, nil)
+		require.ErrorContains(t, err, environment.ContainerRegistryEndpointEnvVarName)
+	})
+}
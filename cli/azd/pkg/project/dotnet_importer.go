@@ -2,21 +2,28 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/psanford/memfs"
 )
@@ -26,6 +33,14 @@ type hostCheckResult struct {
 	err error
 }
 
+type manifestCacheEntry struct {
+	manifest *apphost.Manifest
+
+	// the time the manifest generation started, inputs of the manifest modified after this time invalidate the entry,
+	// see appHostModTime.
+	generatedAt time.Time
+}
+
 // DotNetImporter is an importer that is able to import projects and infrastructure from a manifest produced by a .NET App.
 type DotNetImporter struct {
 	dotnetCli      dotnet.DotNetCli
@@ -37,7 +52,7 @@ type DotNetImporter struct {
 	// operation and it is expensive to generate. We should consider if this is the correct location for the cache or if
 	// it should be in some higher level component. Right now the lifetime issues are not too large of a deal, since
 	// `azd` processes are short lived.
-	cache   map[string]*apphost.Manifest
+	cache   map[string]manifestCacheEntry
 	cacheMu sync.Mutex
 
 	hostCheck   map[string]hostCheckResult
@@ -55,7 +70,7 @@ func NewDotNetImporter(
 		console:        console,
 		lazyEnv:        lazyEnv,
 		lazyEnvManager: lazyEnvManager,
-		cache:          make(map[string]*apphost.Manifest),
+		cache:          make(map[string]manifestCacheEntry),
 		hostCheck:      make(map[string]hostCheckResult),
 	}
 }
@@ -94,6 +109,10 @@ func (ai *DotNetImporter) ProjectInfrastructure(ctx context.Context, svcConfig *
 		return nil, fmt.Errorf("generating app host manifest: %w", err)
 	}
 
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
 	files, err := apphost.BicepTemplate(manifest)
 	if err != nil {
 		return nil, fmt.Errorf("generating bicep from manifest: %w", err)
@@ -133,7 +152,7 @@ func (ai *DotNetImporter) ProjectInfrastructure(ctx context.Context, svcConfig *
 		Options: provisioning.Options{
 			Provider: provisioning.Bicep,
 			Path:     tmpDir,
-			Module:   "main",
+			Module:   provisioning.DefaultModule,
 		},
 		cleanupDir: tmpDir,
 	}, nil
@@ -186,11 +205,20 @@ func (ai *DotNetImporter) Services(
 func (ai *DotNetImporter) SynthAllInfrastructure(
 	ctx context.Context, p *ProjectConfig, svcConfig *ServiceConfig,
 ) (fs.FS, error) {
+	infraDir, err := synthInfraDir(p)
+	if err != nil {
+		return nil, err
+	}
+
 	manifest, err := ai.readManifest(ctx, svcConfig)
 	if err != nil {
 		return nil, fmt.Errorf("generating apphost manifest: %w", err)
 	}
 
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
 	generatedFS := memfs.New()
 
 	infraFS, err := apphost.BicepTemplate(manifest)
@@ -207,7 +235,7 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 			return nil
 		}
 
-		err = generatedFS.MkdirAll(filepath.Join("infra", filepath.Dir(path)), osutil.PermissionDirectoryOwnerOnly)
+		err = generatedFS.MkdirAll(filepath.Join(infraDir, filepath.Dir(path)), osutil.PermissionDirectoryOwnerOnly)
 		if err != nil {
 			return err
 		}
@@ -217,7 +245,7 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 			return err
 		}
 
-		return generatedFS.WriteFile(filepath.Join("infra", path), contents, d.Type().Perm())
+		return generatedFS.WriteFile(filepath.Join(infraDir, path), contents, d.Type().Perm())
 
 	})
 	if err != nil {
@@ -249,73 +277,279 @@ func (ai *DotNetImporter) SynthAllInfrastructure(
 	return generatedFS, nil
 }
 
-// readManifest reads the manifest for the given app host service, and caches the result. It also reads the value of
-// the `services.<name>.config.exposedServices` property from the environment and sets the `External` property on
-// each binding for the exposed services. If this key does not exist in the config for the environment, the user
-// is prompted to select which services should be exposed. This can happen after an environment is created with
-// `azd env new`.
+// readManifest reads the manifest for the given app host service, and caches the result. It also sets the `External`
+// property on each binding for the exposed services, see exposedServices.
 func (ai *DotNetImporter) readManifest(ctx context.Context, svcConfig *ServiceConfig) (*apphost.Manifest, error) {
 	ai.cacheMu.Lock()
 	defer ai.cacheMu.Unlock()
 
 	if cached, has := ai.cache[svcConfig.Path()]; has {
-		return cached, nil
+		if !cached.stale(svcConfig.Path()) {
+			return cached.manifest, nil
+		}
+
+		delete(ai.cache, svcConfig.Path())
 	}
 
+	generatedAt := time.Now()
 	manifest, err := apphost.ManifestFromAppHost(ctx, svcConfig.Path(), ai.dotnetCli)
 	if err != nil {
 		return nil, err
 	}
 
-	env, err := ai.lazyEnv.GetValue()
-	if err == nil {
-		if cfgValue, has := env.Config.Get(fmt.Sprintf("services.%s.config.exposedServices", svcConfig.Name)); has {
-			if exposedServices, is := cfgValue.([]interface{}); !is {
-				log.Printf("services.%s.config.exposedServices is not an array, ignoring setting.", svcConfig.Name)
-			} else {
-				for idx, name := range exposedServices {
-					if strName, ok := name.(string); !ok {
-						log.Printf("services.%s.config.exposedServices[%d] is not a string, ignoring value.",
-							svcConfig.Name, idx)
-					} else {
-						for _, binding := range manifest.Resources[strName].Bindings {
-							binding.External = true
-						}
-					}
-				}
+	exposed, err := ai.exposedServices(ctx, svcConfig, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range exposed {
+		if res, has := manifest.Resources[name]; has {
+			for _, binding := range res.Bindings {
+				binding.External = true
+			}
+		}
+	}
+
+	ai.cache[svcConfig.Path()] = manifestCacheEntry{
+		manifest:    manifest,
+		generatedAt: generatedAt,
+	}
+	return manifest, nil
+}
+
+// exposedServices returns the services of the manifest to expose to the Internet. The services are read from
+// the `exposedServices` config of the service in azure.yaml, or else from the `services.<name>.config.exposedServices`
+// property of the environment. If neither is set, the user is prompted to select the services and the selection is
+// saved to the environment. This can happen after an environment is created with `azd env new`. When prompting is
+// disabled, an error is returned instead.
+func (ai *DotNetImporter) exposedServices(
+	ctx context.Context, svcConfig *ServiceConfig, manifest *apphost.Manifest,
+) ([]string, error) {
+	envKey := fmt.Sprintf("services.%s.config.exposedServices", svcConfig.Name)
+
+	var envExposed []string
+	var hasEnvExposed bool
+	env, envErr := ai.lazyEnv.GetValue()
+	if envErr == nil {
+		if cfgValue, has := env.Config.Get(envKey); has {
+			envExposed, hasEnvExposed = stringSliceConfig(envKey, cfgValue)
+		}
+	}
+
+	if cfgValue, has := svcConfig.Config["exposedServices"]; has {
+		projectKey := fmt.Sprintf("%s in %s", envKey, azdcontext.ProjectFileName)
+		if exposed, ok := stringSliceConfig(projectKey, cfgValue); ok {
+			if hasEnvExposed && !sameElements(exposed, envExposed) {
+				log.Printf("%s overrides the value in environment '%s'", projectKey, env.GetEnvName())
 			}
+
+			return exposed, nil
+		}
+	}
+
+	if envErr != nil {
+		log.Printf("unexpected error fetching environment: %s, exposed services may not be correct", envErr)
+		return nil, nil
+	}
+
+	if hasEnvExposed {
+		return envExposed, nil
+	}
+
+	if ai.console.IsNoPromptMode() {
+		if services := apphost.ExposableServices(manifest); len(services) > 0 {
+			return nil, fmt.Errorf(
+				"the services to expose to the Internet must be selected for service '%s', which can expose: %s. "+
+					"Set 'config.exposedServices' for service '%s' in %s to the services to expose, "+
+					"or run without --no-prompt to select them",
+				svcConfig.Name,
+				strings.Join(services, ", "),
+				svcConfig.Name,
+				azdcontext.ProjectFileName)
+		}
+
+		return nil, nil
+	}
+
+	selector := apphost.NewIngressSelector(manifest, ai.console)
+	exposed, err := selector.SelectPublicServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("selecting public services: %w", err)
+	}
+
+	err = env.Config.Set(envKey, exposed)
+	if err != nil {
+		return nil, err
+	}
+
+	envManager, err := ai.lazyEnvManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := envManager.Save(ctx, env); err != nil {
+		return nil, err
+	}
+
+	return exposed, nil
+}
+
+// stringSliceConfig converts a config value to a slice of strings. Values that are not strings are ignored. ok is false
+// if the value is not an array.
+func stringSliceConfig(key string, value any) (values []string, ok bool) {
+	items, is := value.([]interface{})
+	if !is {
+		log.Printf("%s is not an array, ignoring setting.", key)
+		return nil, false
+	}
+
+	values = []string{}
+	for idx, item := range items {
+		if str, is := item.(string); !is {
+			log.Printf("%s[%d] is not a string, ignoring value.", key, idx)
 		} else {
-			selector := apphost.NewIngressSelector(manifest, ai.console)
-			exposed, err := selector.SelectPublicServices(ctx)
+			values = append(values, str)
+		}
+	}
+
+	return values, true
+}
+
+// sameElements returns true if a and b contain the same elements, in any order.
+func sameElements(a []string, b []string) bool {
+	a = slices.Clone(a)
+	b = slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// InvalidateManifestCache removes the cached manifest for the app host project at the given path, if any, so the
+// manifest is generated again the next time it is needed. projectPath is the path of the app host service, as returned
+// by ServiceConfig.Path().
+func (ai *DotNetImporter) InvalidateManifestCache(projectPath string) {
+	ai.cacheMu.Lock()
+	defer ai.cacheMu.Unlock()
+
+	delete(ai.cache, projectPath)
+}
+
+// ResetCache removes all cached manifests.
+func (ai *DotNetImporter) ResetCache() {
+	ai.cacheMu.Lock()
+	defer ai.cacheMu.Unlock()
+
+	ai.cache = make(map[string]manifestCacheEntry)
+}
+
+// manifestStale returns whether the manifest cached for the app host project at the given path is stale because any of
+// its inputs changed since it was generated. Returns false when no manifest is cached.
+func (ai *DotNetImporter) manifestStale(projectPath string) bool {
+	ai.cacheMu.Lock()
+	defer ai.cacheMu.Unlock()
+
+	cached, has := ai.cache[projectPath]
+	return has && cached.stale(projectPath)
+}
+
+// stale returns whether any input of the manifest of the app host project at projectPath was modified after the entry
+// was generated, or the modification times can't be checked.
+func (e manifestCacheEntry) stale(projectPath string) bool {
+	modTime, err := appHostModTime(projectPath, e.manifest)
+	if err != nil {
+		log.Printf("checking modification time of %s, regenerating manifest: %v", projectPath, err)
+		return true
+	}
+
+	return modTime.After(e.generatedAt)
+}
+
+// appHostModTime returns the latest modification time of the inputs of the app host manifest: the files under the
+// directory of the app host project and under the directories of the projects in the manifest, which the app host
+// references. Build output (bin and obj) and hidden directories are skipped. projectPath may be either the project file
+// or its directory.
+func appHostModTime(projectPath string, manifest *apphost.Manifest) (time.Time, error) {
+	projectDir := projectPath
+	if stat, err := os.Stat(projectPath); err != nil {
+		return time.Time{}, err
+	} else if !stat.IsDir() {
+		projectDir = filepath.Dir(projectPath)
+	}
+
+	dirs := []string{projectDir}
+	for _, path := range apphost.ProjectPaths(manifest) {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+
+	var latest time.Time
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
-				return nil, fmt.Errorf("selecting public services: %w", err)
+				return err
 			}
 
-			for _, name := range exposed {
-				for _, binding := range manifest.Resources[name].Bindings {
-					binding.External = true
+			if d.IsDir() {
+				name := d.Name()
+				if path != dir && (name == "bin" || name == "obj" || strings.HasPrefix(name, ".")) {
+					return filepath.SkipDir
 				}
+				return nil
 			}
 
-			err = env.Config.Set(fmt.Sprintf("services.%s.config.exposedServices", svcConfig.Name), exposed)
+			info, err := d.Info()
 			if err != nil {
-				return nil, err
+				return err
 			}
 
-			envManager, err := ai.lazyEnvManager.GetValue()
-			if err != nil {
-				return nil, err
+			if info.ModTime().After(latest) {
+				latest = info.ModTime()
 			}
+			return nil
+		})
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
 
-			if err := envManager.Save(ctx, env); err != nil {
-				return nil, err
-			}
+	return latest, nil
+}
 
+// validateManifest returns an error with a suggestion when the manifest has resources that azd doesn't support, which
+// would otherwise produce infrastructure that fails to deploy.
+func validateManifest(manifest *apphost.Manifest) error {
+	err := apphost.ValidateManifest(manifest)
+
+	var unsupportedErr *apphost.UnsupportedResourcesError
+	if errors.As(err, &unsupportedErr) {
+		suggestion := fmt.Sprintf("These resources require a newer azd than %s, or aren't supported by azd yet. "+
+			"Update azd to the latest version, following the install instructions at https://aka.ms/azure-dev/install. "+
+			"The resource types azd supports are listed at %s.",
+			internal.VersionInfo().Version,
+			aspireResourceTypesDocsUrl)
+		if aspireVersion := aspireSchemaVersion(manifest.Schema); aspireVersion != "" {
+			suggestion = fmt.Sprintf("The manifest was generated by .NET Aspire %s. %s", aspireVersion, suggestion)
+		}
+
+		return &azcli.ErrorWithSuggestion{
+			Suggestion: "\nSuggested Action: " + suggestion,
+			Err:        err,
 		}
-	} else {
-		log.Printf("unexpected error fetching environment: %s, exposed services may not be correct", err)
 	}
 
-	ai.cache[svcConfig.Path()] = manifest
-	return manifest, nil
+	return err
+}
+
+// aspireResourceTypesDocsUrl documents how azd deploys the resources of an app host manifest, by resource type.
+const aspireResourceTypesDocsUrl = "https://learn.microsoft.com/dotnet/aspire/deployment/azure/aca-deployment-azd-in-depth"
+
+var aspireSchemaRegex = regexp.MustCompile(`aspire-(\d+(?:\.\d+)*)\.json$`)
+
+// aspireSchemaVersion returns the version of .NET Aspire in the schema URL of a manifest, for example '8.0' for
+// https://json.schemastore.org/aspire-8.0.json, or an empty string if the schema URL has no version.
+func aspireSchemaVersion(schema string) string {
+	if match := aspireSchemaRegex.FindStringSubmatch(schema); match != nil {
+		return match[1]
+	}
+
+	return ""
 }
@@ -0,0 +1,236 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DotNetImporter_ManifestCache(t *testing.T) {
+	appHostDir := t.TempDir()
+	projectFile := filepath.Join(appHostDir, "AppHost.csproj")
+	apiDir := t.TempDir()
+	apiFile := filepath.Join(apiDir, "Controllers", "ApiController.cs")
+	apiOutput := filepath.Join(apiDir, "obj", "project.assets.json")
+
+	// make the files older than any manifest generated below
+	past := time.Now().Add(-time.Hour)
+	for _, file := range []string{projectFile, filepath.Join(apiDir, "Api.csproj"), apiFile, apiOutput} {
+		err := os.MkdirAll(filepath.Dir(file), osutil.PermissionDirectory)
+		require.NoError(t, err)
+		err = os.WriteFile(file, nil, osutil.PermissionFile)
+		require.NoError(t, err)
+		err = os.Chtimes(file, past, past)
+		require.NoError(t, err)
+	}
+
+	manifest, err := json.Marshal(map[string]any{
+		"resources": map[string]any{
+			"api": map[string]any{
+				"type": "project.v0",
+				"path": filepath.Join(apiDir, "Api.csproj"),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	publishCount := 0
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "--publisher manifest")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			publishCount++
+			manifestPath := args.Args[len(args.Args)-1]
+			return exec.NewRunResult(0, "", ""), os.WriteFile(manifestPath, manifest, osutil.PermissionFile)
+		})
+
+	importer := NewDotNetImporter(
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		mockContext.Console,
+		lazy.NewLazy(func() (*environment.Environment, error) {
+			return nil, errors.New("no environment")
+		}),
+		lazy.From[environment.Manager](nil),
+	)
+
+	serviceConfig := createTestServiceConfig(projectFile, ContainerAppTarget, ServiceLanguageDotNet)
+	readManifest := func() {
+		_, err := importer.readManifest(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+	}
+	touch := func(file string) {
+		future := time.Now().Add(time.Duration(publishCount+1) * time.Hour)
+		err := os.Chtimes(file, future, future)
+		require.NoError(t, err)
+	}
+
+	readManifest()
+	readManifest()
+	require.Equal(t, 1, publishCount)
+
+	// build output of the referenced projects doesn't invalidate the cached manifest
+	touch(apiOutput)
+	readManifest()
+	require.Equal(t, 1, publishCount)
+
+	// editing the app host project invalidates the cached manifest
+	touch(projectFile)
+	readManifest()
+	require.Equal(t, 2, publishCount)
+
+	// and so does editing a file of a project referenced by the app host
+	touch(apiFile)
+	require.True(t, importer.manifestStale(serviceConfig.Path()))
+	readManifest()
+	require.Equal(t, 3, publishCount)
+
+	// the cached manifest can also be invalidated explicitly
+	importer.InvalidateManifestCache(serviceConfig.Path())
+	readManifest()
+	require.Equal(t, 4, publishCount)
+
+	importer.ResetCache()
+	readManifest()
+	require.Equal(t, 5, publishCount)
+}
+
+func Test_DotNetImporter_ExposedServices(t *testing.T) {
+	manifest := `{
+  "resources": {
+    "web": {
+      "type": "container.v0",
+      "image": "nginx",
+      "bindings": { "http": { "scheme": "http", "protocol": "tcp", "transport": "http" } }
+    },
+    "worker": {
+      "type": "project.v0",
+      "path": "../Worker/Worker.csproj",
+      "bindings": { "http": { "scheme": "http", "protocol": "tcp", "transport": "http" } }
+    }
+  }
+}`
+
+	setup := func(t *testing.T, env *environment.Environment) (*mocks.MockContext, *DotNetImporter, *ServiceConfig) {
+		appHostDir := t.TempDir()
+		projectFile := filepath.Join(appHostDir, "AppHost.csproj")
+		err := os.WriteFile(projectFile, nil, osutil.PermissionFile)
+		require.NoError(t, err)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "--publisher manifest")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				manifestPath := args.Args[len(args.Args)-1]
+				return exec.NewRunResult(0, "", ""), os.WriteFile(manifestPath, []byte(manifest), osutil.PermissionFile)
+			})
+
+		importer := NewDotNetImporter(
+			dotnet.NewDotNetCli(mockContext.CommandRunner),
+			mockContext.Console,
+			lazy.From(env),
+			lazy.From[environment.Manager](nil),
+		)
+
+		return mockContext, importer, createTestServiceConfig(projectFile, ContainerAppTarget, ServiceLanguageDotNet)
+	}
+
+	t.Run("ProjectConfigOverridesEnvironment", func(t *testing.T) {
+		env := environment.New("test")
+		err := env.Config.Set("services.api.config.exposedServices", []any{"web"})
+		require.NoError(t, err)
+
+		mockContext, importer, serviceConfig := setup(t, env)
+		serviceConfig.Config = map[string]any{
+			"exposedServices": []any{"worker"},
+		}
+
+		m, err := importer.readManifest(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.False(t, m.Resources["web"].Bindings["http"].External)
+		require.True(t, m.Resources["worker"].Bindings["http"].External)
+	})
+
+	t.Run("NoPromptWithoutConfig", func(t *testing.T) {
+		mockContext, importer, serviceConfig := setup(t, environment.New("test"))
+		mockContext.Console.SetNoPromptMode(true)
+
+		_, err := importer.readManifest(*mockContext.Context, serviceConfig)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "web, worker")
+		require.Contains(t, err.Error(), "config.exposedServices")
+	})
+}
+
+func Test_DotNetImporter_UnsupportedResources(t *testing.T) {
+	manifest := `{
+  "$schema": "https://json.schemastore.org/aspire-8.0.json",
+  "resources": {
+    "search": { "type": "azure.search.v0" },
+    "cache": { "type": "redis.v0" },
+    "sql": { "type": "azure.sql.v0" }
+  }
+}`
+
+	appHostDir := t.TempDir()
+	projectFile := filepath.Join(appHostDir, "AppHost.csproj")
+	err := os.WriteFile(projectFile, nil, osutil.PermissionFile)
+	require.NoError(t, err)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "--publisher manifest")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			manifestPath := args.Args[len(args.Args)-1]
+			return exec.NewRunResult(0, "", ""), os.WriteFile(manifestPath, []byte(manifest), osutil.PermissionFile)
+		})
+
+	importer := NewDotNetImporter(
+		dotnet.NewDotNetCli(mockContext.CommandRunner),
+		mockContext.Console,
+		lazy.NewLazy(func() (*environment.Environment, error) {
+			return nil, errors.New("no environment")
+		}),
+		lazy.From[environment.Manager](nil),
+	)
+	serviceConfig := createTestServiceConfig(projectFile, ContainerAppTarget, ServiceLanguageDotNet)
+
+	_, err = importer.ProjectInfrastructure(*mockContext.Context, serviceConfig)
+
+	var suggestionErr *azcli.ErrorWithSuggestion
+	require.ErrorAs(t, err, &suggestionErr)
+	require.Contains(t, suggestionErr.Suggestion, "generated by .NET Aspire 8.0")
+	require.Contains(t, suggestionErr.Suggestion, "require a newer azd than "+internal.VersionInfo().Version.String())
+	require.Contains(t, suggestionErr.Suggestion, aspireResourceTypesDocsUrl)
+
+	var unsupportedErr *apphost.UnsupportedResourcesError
+	require.ErrorAs(t, err, &unsupportedErr)
+	require.Equal(t, []apphost.UnsupportedResource{
+		{Name: "search", Type: "azure.search.v0"},
+		{Name: "sql", Type: "azure.sql.v0"},
+	}, unsupportedErr.Resources)
+
+	_, err = importer.SynthAllInfrastructure(*mockContext.Context, serviceConfig.Project, serviceConfig)
+	require.ErrorAs(t, err, &unsupportedErr)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"golang.org/x/exp/slices"
 )
 
 type ServiceLanguageKind string
@@ -22,34 +23,57 @@ const (
 	ServiceLanguageTypeScript ServiceLanguageKind = "ts"
 	ServiceLanguagePython     ServiceLanguageKind = "python"
 	ServiceLanguageJava       ServiceLanguageKind = "java"
+	ServiceLanguageGo         ServiceLanguageKind = "go"
+	ServiceLanguageRust       ServiceLanguageKind = "rust"
+	ServiceLanguagePhp        ServiceLanguageKind = "php"
+	ServiceLanguageRuby       ServiceLanguageKind = "ruby"
 	ServiceLanguageDocker     ServiceLanguageKind = "docker"
 )
 
+// serviceLanguages are the languages that services can set in azure.yaml, which excludes ServiceLanguageDocker since it
+// is implicitly derived currently, and not an actual language.
+var serviceLanguages = []ServiceLanguageKind{
+	ServiceLanguageDotNet,
+	ServiceLanguageCsharp,
+	ServiceLanguageFsharp,
+	ServiceLanguageJavaScript,
+	ServiceLanguageTypeScript,
+	ServiceLanguagePython,
+	ServiceLanguageJava,
+	ServiceLanguageGo,
+	ServiceLanguageRust,
+	ServiceLanguagePhp,
+	ServiceLanguageRuby,
+}
+
+// serviceLanguageAliases maps the aliases of languages that services can set in azure.yaml to the languages.
+var serviceLanguageAliases = map[ServiceLanguageKind]ServiceLanguageKind{
+	"py": ServiceLanguagePython,
+}
+
 func parseServiceLanguage(kind ServiceLanguageKind) (ServiceLanguageKind, error) {
 	if string(kind) == "" {
 		return ServiceLanguageKind(""), fmt.Errorf("language property must not be empty")
 	}
 
-	// aliases
-	if string(kind) == "py" {
-		return ServiceLanguagePython, nil
+	if language, has := serviceLanguageAliases[kind]; has {
+		return language, nil
 	}
 
-	switch kind {
-	case ServiceLanguageDotNet,
-		ServiceLanguageCsharp,
-		ServiceLanguageFsharp,
-		ServiceLanguageJavaScript,
-		ServiceLanguageTypeScript,
-		ServiceLanguagePython,
-		ServiceLanguageJava:
-		// Excluding ServiceLanguageDocker since it is implicitly derived currently, and not an actual language
+	if isBuiltInServiceLanguage(kind) || isPluginServiceLanguage(kind) {
 		return kind, nil
 	}
 
 	return ServiceLanguageKind(""), fmt.Errorf("unsupported language '%s'", kind)
 }
 
+// isBuiltInServiceLanguage returns whether kind is a language, or an alias of a language, built into azd that services can
+// set in azure.yaml.
+func isBuiltInServiceLanguage(kind ServiceLanguageKind) bool {
+	_, isAlias := serviceLanguageAliases[kind]
+	return isAlias || slices.Contains(serviceLanguages, kind)
+}
+
 type FrameworkRequirements struct {
 	Package FrameworkPackageRequirements
 }
@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bundler"
+)
+
+type bundlerProject struct {
+	bundlerCli bundler.BundlerCli
+}
+
+// NewBundlerProject creates a new instance of a Ruby project whose dependencies are managed with Bundler.
+//
+// Ruby services are packaged as a container image that serves the app with Puma, so the project is only supported on
+// container based hosts.
+func NewBundlerProject(bundlerCli bundler.BundlerCli) FrameworkService {
+	return &bundlerProject{
+		bundlerCli: bundlerCli,
+	}
+}
+
+func (bp *bundlerProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		Package: FrameworkPackageRequirements{
+			RequireRestore: true,
+			// Ruby apps aren't compiled, Rails assets are precompiled when the image is built
+			RequireBuild: false,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (bp *bundlerProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{bp.bundlerCli}
+}
+
+// Initializes the Bundler project
+func (bp *bundlerProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if serviceConfig.Host != ContainerAppTarget && serviceConfig.Host != AksTarget && serviceConfig.Host != AciTarget {
+		return fmt.Errorf(
+			"service '%s' uses language '%s' which is only supported with host '%s', '%s' or '%s'",
+			serviceConfig.Name,
+			ServiceLanguageRuby,
+			ContainerAppTarget,
+			AksTarget,
+			AciTarget,
+		)
+	}
+
+	return nil
+}
+
+// Restores the dependencies of the project using bundle install
+func (bp *bundlerProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Installing gem dependencies"))
+			if err := bp.bundlerCli.Install(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Build for Bundler apps performs a no-op and returns the project directory, which is served as is.
+func (bp *bundlerProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: serviceConfig.Path(),
+			})
+		},
+	)
+}
+
+// Package for Bundler apps performs a no-op and returns the build output, the app is packaged in the container image.
+func (bp *bundlerProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: buildOutput.BuildOutputPath,
+			})
+		},
+	)
+}
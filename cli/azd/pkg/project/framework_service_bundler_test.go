@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bundler"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BundlerProject_Initialize(t *testing.T) {
+	bundlerProject := NewBundlerProject(bundler.NewBundlerCli(exec.NewCommandRunner(nil)))
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageRuby)
+	require.NoError(t, bundlerProject.Initialize(context.Background(), serviceConfig))
+
+	serviceConfig = createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageRuby)
+	require.Error(t, bundlerProject.Initialize(context.Background(), serviceConfig))
+}
+
+func Test_BundlerProject_Restore_Build(t *testing.T) {
+	var runArgs []exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "bundle")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = append(runArgs, args)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageRuby)
+	bundlerProject := NewBundlerProject(bundler.NewBundlerCli(mockContext.CommandRunner))
+
+	restoreTask := bundlerProject.Restore(*mockContext.Context, serviceConfig)
+	logProgress(restoreTask)
+	restoreResult, err := restoreTask.Await()
+	require.NoError(t, err)
+
+	buildTask := bundlerProject.Build(*mockContext.Context, serviceConfig, restoreResult)
+	logProgress(buildTask)
+	buildResult, err := buildTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, serviceConfig.Path(), buildResult.BuildOutputPath)
+
+	require.Len(t, runArgs, 1)
+	require.Equal(t, []string{"install"}, runArgs[0].Args)
+	require.Equal(t, serviceConfig.Path(), runArgs[0].Cwd)
+}
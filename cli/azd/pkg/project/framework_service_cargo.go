@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cargo"
+)
+
+type cargoProject struct {
+	cargoCli cargo.CargoCli
+}
+
+// NewCargoProject creates a new instance of a Rust project built with Cargo.
+//
+// Rust services are packaged as a container image, so the project is only supported on container based hosts.
+func NewCargoProject(cargoCli cargo.CargoCli) FrameworkService {
+	return &cargoProject{
+		cargoCli: cargoCli,
+	}
+}
+
+func (cp *cargoProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		Package: FrameworkPackageRequirements{
+			// cargo build downloads missing dependencies
+			RequireRestore: false,
+			RequireBuild:   true,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (cp *cargoProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{cp.cargoCli}
+}
+
+// Initializes the Cargo project
+func (cp *cargoProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if serviceConfig.Host != ContainerAppTarget && serviceConfig.Host != AksTarget && serviceConfig.Host != AciTarget {
+		return fmt.Errorf(
+			"service '%s' uses language '%s' which is only supported with host '%s', '%s' or '%s'",
+			serviceConfig.Name,
+			ServiceLanguageRust,
+			ContainerAppTarget,
+			AksTarget,
+			AciTarget,
+		)
+	}
+
+	return nil
+}
+
+// Restores the dependencies of the project using cargo fetch
+func (cp *cargoProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Fetching cargo dependencies"))
+			if err := cp.cargoCli.Fetch(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Builds the release binary of the project using cargo build
+func (cp *cargoProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Building release binary"))
+			if err := cp.cargoCli.BuildRelease(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: filepath.Join(serviceConfig.Path(), "target", "release"),
+			})
+		},
+	)
+}
+
+// Package for Cargo apps performs a no-op and returns the build output, the binary is packaged in the container image.
+func (cp *cargoProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: buildOutput.BuildOutputPath,
+			})
+		},
+	)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/cargo"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CargoProject_Initialize(t *testing.T) {
+	cargoProject := NewCargoProject(cargo.NewCargoCli(exec.NewCommandRunner(nil)))
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageRust)
+	require.NoError(t, cargoProject.Initialize(context.Background(), serviceConfig))
+
+	serviceConfig = createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageRust)
+	require.Error(t, cargoProject.Initialize(context.Background(), serviceConfig))
+}
+
+func Test_CargoProject_Restore_Build(t *testing.T) {
+	var runArgs []exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "cargo")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = append(runArgs, args)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageRust)
+	cargoProject := NewCargoProject(cargo.NewCargoCli(mockContext.CommandRunner))
+
+	restoreTask := cargoProject.Restore(*mockContext.Context, serviceConfig)
+	logProgress(restoreTask)
+	restoreResult, err := restoreTask.Await()
+	require.NoError(t, err)
+
+	buildTask := cargoProject.Build(*mockContext.Context, serviceConfig, restoreResult)
+	logProgress(buildTask)
+	buildResult, err := buildTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(serviceConfig.Path(), "target", "release"), buildResult.BuildOutputPath)
+
+	require.Len(t, runArgs, 2)
+	require.Equal(t, []string{"fetch"}, runArgs[0].Args)
+	require.Equal(t, []string{"build", "--release"}, runArgs[1].Args)
+	require.Equal(t, serviceConfig.Path(), runArgs[1].Cwd)
+}
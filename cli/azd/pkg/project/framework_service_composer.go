@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/composer"
+)
+
+type composerProject struct {
+	composerCli composer.ComposerCli
+}
+
+// NewComposerProject creates a new instance of a PHP project whose dependencies are managed with Composer.
+//
+// PHP services are packaged as a container image that serves the app with PHP-FPM and nginx, so the project is only
+// supported on container based hosts.
+func NewComposerProject(composerCli composer.ComposerCli) FrameworkService {
+	return &composerProject{
+		composerCli: composerCli,
+	}
+}
+
+func (cp *composerProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		Package: FrameworkPackageRequirements{
+			RequireRestore: true,
+			// PHP apps aren't compiled
+			RequireBuild: false,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (cp *composerProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{cp.composerCli}
+}
+
+// Initializes the Composer project
+func (cp *composerProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if serviceConfig.Host != ContainerAppTarget && serviceConfig.Host != AksTarget && serviceConfig.Host != AciTarget {
+		return fmt.Errorf(
+			"service '%s' uses language '%s' which is only supported with host '%s', '%s' or '%s'",
+			serviceConfig.Name,
+			ServiceLanguagePhp,
+			ContainerAppTarget,
+			AksTarget,
+			AciTarget,
+		)
+	}
+
+	return nil
+}
+
+// Restores the dependencies of the project using composer install
+func (cp *composerProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Installing composer dependencies"))
+			if err := cp.composerCli.Install(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Build for Composer apps performs a no-op and returns the project directory, which is served as is.
+func (cp *composerProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: serviceConfig.Path(),
+			})
+		},
+	)
+}
+
+// Package for Composer apps performs a no-op and returns the build output, the app is packaged in the container image.
+func (cp *composerProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: buildOutput.BuildOutputPath,
+			})
+		},
+	)
+}
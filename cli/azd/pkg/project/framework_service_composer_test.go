@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/composer"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ComposerProject_Initialize(t *testing.T) {
+	composerProject := NewComposerProject(composer.NewComposerCli(exec.NewCommandRunner(nil)))
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguagePhp)
+	require.NoError(t, composerProject.Initialize(context.Background(), serviceConfig))
+
+	serviceConfig = createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePhp)
+	require.Error(t, composerProject.Initialize(context.Background(), serviceConfig))
+}
+
+func Test_ComposerProject_Restore_Build(t *testing.T) {
+	var runArgs []exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "composer")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = append(runArgs, args)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguagePhp)
+	composerProject := NewComposerProject(composer.NewComposerCli(mockContext.CommandRunner))
+
+	restoreTask := composerProject.Restore(*mockContext.Context, serviceConfig)
+	logProgress(restoreTask)
+	restoreResult, err := restoreTask.Await()
+	require.NoError(t, err)
+
+	buildTask := composerProject.Build(*mockContext.Context, serviceConfig, restoreResult)
+	logProgress(buildTask)
+	buildResult, err := buildTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, serviceConfig.Path(), buildResult.BuildOutputPath)
+
+	require.Len(t, runArgs, 1)
+	require.Equal(t, []string{"install", "--no-interaction"}, runArgs[0].Args)
+	require.Equal(t, serviceConfig.Path(), runArgs[0].Cwd)
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
@@ -24,6 +25,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
@@ -32,11 +34,23 @@ import (
 )
 
 type DockerProjectOptions struct {
-	Path      string           `yaml:"path,omitempty"      json:"path,omitempty"`
-	Context   string           `yaml:"context,omitempty"   json:"context,omitempty"`
-	Platform  string           `yaml:"platform,omitempty"  json:"platform,omitempty"`
-	Tag       ExpandableString `yaml:"tag,omitempty"       json:"tag,omitempty"`
-	BuildArgs []string         `yaml:"buildArgs,omitempty" json:"buildArgs,omitempty"`
+	Path        string             `yaml:"path,omitempty"        json:"path,omitempty"`
+	Context     string             `yaml:"context,omitempty"     json:"context,omitempty"`
+	Platform    string             `yaml:"platform,omitempty"    json:"platform,omitempty"`
+	Tag         ExpandableString   `yaml:"tag,omitempty"         json:"tag,omitempty"`
+	TagStrategy string             `yaml:"tagStrategy,omitempty" json:"tagStrategy,omitempty"`
+	BuildArgs   []string           `yaml:"buildArgs,omitempty"   json:"buildArgs,omitempty"`
+	CacheFrom   []ExpandableString `yaml:"cacheFrom,omitempty"   json:"cacheFrom,omitempty"`
+	CacheTo     []ExpandableString `yaml:"cacheTo,omitempty"     json:"cacheTo,omitempty"`
+	RemoteBuild bool               `yaml:"remoteBuild,omitempty" json:"remoteBuild,omitempty"`
+	Registries  []ExpandableString `yaml:"registries,omitempty"  json:"registries,omitempty"`
+	Scan        *DockerScanOptions `yaml:"scan,omitempty"        json:"scan,omitempty"`
+}
+
+// DockerScanOptions configures the vulnerability scan of the image of a service when it is packaged.
+type DockerScanOptions struct {
+	// The number of high and critical vulnerabilities the image can have, packaging fails when it has more.
+	Threshold int `yaml:"threshold,omitempty" json:"threshold,omitempty"`
 }
 
 type dockerBuildResult struct {
@@ -60,12 +74,20 @@ func (dbr *dockerBuildResult) MarshalJSON() ([]byte, error) {
 type dockerPackageResult struct {
 	ImageHash string `json:"imageHash"`
 	ImageTag  string `json:"imageTag"`
+	// The reference of the image in the registry it's deployed from, when it was pushed by azd package --push
+	RemoteImage string `json:"remoteImage,omitempty"`
 }
 
 func (dpr *dockerPackageResult) ToString(currentIndentation string) string {
-	lines := []string{
-		fmt.Sprintf("%s- Image Hash: %s", currentIndentation, output.WithLinkFormat(dpr.ImageHash)),
-		fmt.Sprintf("%s- Image Tag: %s", currentIndentation, output.WithLinkFormat(dpr.ImageTag)),
+	lines := []string{}
+	// images built in the registry have no local image hash
+	if dpr.ImageHash != "" {
+		lines = append(lines, fmt.Sprintf("%s- Image Hash: %s", currentIndentation, output.WithLinkFormat(dpr.ImageHash)))
+	}
+	lines = append(lines, fmt.Sprintf("%s- Image Tag: %s", currentIndentation, output.WithLinkFormat(dpr.ImageTag)))
+	if dpr.RemoteImage != "" {
+		lines = append(lines,
+			fmt.Sprintf("%s- Remote Image: %s", currentIndentation, output.WithLinkFormat(dpr.RemoteImage)))
 	}
 
 	return strings.Join(lines, "\n")
@@ -122,6 +144,32 @@ func (p *dockerProject) RequiredExternalTools(context.Context) []tools.ExternalT
 
 // Initializes the docker project
 func (p *dockerProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if err := ValidateTagStrategy(serviceConfig); err != nil {
+		return err
+	}
+
+	if serviceConfig.Docker.RemoteBuild &&
+		(len(serviceConfig.Docker.CacheFrom) > 0 || len(serviceConfig.Docker.CacheTo) > 0) {
+		return fmt.Errorf(
+			"service '%s' sets 'docker.remoteBuild' and 'docker.cacheFrom' or 'docker.cacheTo', images built in the "+
+				"registry don't use an external cache",
+			serviceConfig.Name)
+	}
+
+	if serviceConfig.Docker.RemoteBuild && len(serviceConfig.Docker.Registries) > 1 {
+		return fmt.Errorf(
+			"service '%s' sets 'docker.remoteBuild' and multiple 'docker.registries', images built in the registry "+
+				"can't be pushed to other registries",
+			serviceConfig.Name)
+	}
+
+	if serviceConfig.Docker.Scan != nil && serviceConfig.Docker.Scan.Threshold < 0 {
+		return fmt.Errorf(
+			"'docker.scan.threshold' of service '%s' is %d, it can't be negative",
+			serviceConfig.Name,
+			serviceConfig.Docker.Scan.Threshold)
+	}
+
 	return p.framework.Initialize(ctx, serviceConfig)
 }
 
@@ -177,6 +225,21 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			if serviceConfig.Docker.RemoteBuild {
+				if errors.Is(err, os.ErrNotExist) {
+					task.SetError(fmt.Errorf(
+						"service '%s' sets 'docker.remoteBuild', which requires a Dockerfile at %s", serviceConfig.Name, path))
+					return
+				}
+
+				// The image is built in the registry when the service is deployed
+				log.Printf("skipping local build of service %s, the image is built in the registry", serviceConfig.Name)
+				task.SetResult(&ServiceBuildResult{
+					Restore: restoreOutput,
+				})
+				return
+			}
+
 			if errors.Is(err, os.ErrNotExist) {
 				// Build the container from source
 				task.SetProgress(NewServiceProgress("Building Docker image from source"))
@@ -191,6 +254,17 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			cache, err := p.buildCache(ctx, serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if err := p.containerHelper.LoginCacheRegistries(ctx, serviceConfig, cache); err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Build the container
 			task.SetProgress(NewServiceProgress("Building Docker image"))
 			previewerWriter := p.console.ShowPreviewer(ctx,
@@ -207,6 +281,7 @@ func (p *dockerProject) Build(
 				dockerOptions.Context,
 				imageName,
 				dockerOptions.BuildArgs,
+				cache,
 				previewerWriter,
 			)
 			p.console.StopPreviewer(ctx, false)
@@ -228,6 +303,62 @@ func (p *dockerProject) Build(
 	)
 }
 
+// buildCache returns the build cache configured for the service. Building with a cache requires docker buildx, when it
+// is not available a warning is shown and nil is returned, so the image is built without the cache. The default 'docker'
+// driver of buildx can only export the cache inline, so other cache destinations are dropped with a warning.
+func (p *dockerProject) buildCache(ctx context.Context, serviceConfig *ServiceConfig) (*docker.BuildCache, error) {
+	cache, err := p.containerHelper.BuildCache(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache.IsEmpty() {
+		return nil, nil
+	}
+
+	if _, err := p.docker.BuildxVersion(ctx); err != nil {
+		log.Printf("building image for service %s without cache: %v", serviceConfig.Name, err)
+		p.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"'docker.cacheFrom' and 'docker.cacheTo' of service '%s' require docker buildx, which is not "+
+					"available. Building the image without the cache. Install buildx to use the cache: "+
+					"https://docs.docker.com/go/buildx/",
+				serviceConfig.Name,
+			),
+		})
+		return nil, nil
+	}
+
+	if len(cache.To) > 0 {
+		if driver, err := p.docker.BuildxDriver(ctx); err != nil {
+			log.Printf("checking the buildx driver for service %s: %v", serviceConfig.Name, err)
+		} else if driver == "docker" {
+			inline := slices.DeleteFunc(slices.Clone(cache.To), func(to string) bool {
+				return !slices.Contains(strings.Split(to, ","), "type=inline")
+			})
+
+			if len(inline) < len(cache.To) {
+				p.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: fmt.Sprintf(
+						"The docker buildx builder uses the 'docker' driver, which can only export the cache of "+
+							"'docker.cacheTo' of service '%s' with 'type=inline'. Building the image without exporting "+
+							"the cache to the other destinations. Use a builder with the 'docker-container' driver to "+
+							"export them: https://docs.docker.com/go/build-cache-backends/",
+						serviceConfig.Name,
+					),
+				})
+				cache.To = inline
+			}
+		}
+	}
+
+	if cache.IsEmpty() {
+		return nil, nil
+	}
+
+	return cache, nil
+}
+
 func (p *dockerProject) Package(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -235,13 +366,31 @@ func (p *dockerProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if serviceConfig.Docker.RemoteBuild {
+				// The image is built and tagged in the registry when the service is deployed
+				localTag, err := p.containerHelper.LocalImageTag(ctx, serviceConfig)
+				if err != nil {
+					task.SetError(fmt.Errorf("generating local image tag: %w", err))
+					return
+				}
+
+				task.SetResult(&ServicePackageResult{
+					Build:       buildOutput,
+					PackagePath: localTag,
+					Details: &dockerPackageResult{
+						ImageTag: localTag,
+					},
+				})
+				return
+			}
+
 			imageId := buildOutput.BuildOutputPath
 			if imageId == "" {
 				task.SetError(errors.New("missing container image id from build output"))
 				return
 			}
 
-			localTag, err := p.containerHelper.LocalImageTag(ctx, serviceConfig)
+			localTag, err := p.containerHelper.PackageImageTag(ctx, serviceConfig, imageId)
 			if err != nil {
 				task.SetError(fmt.Errorf("generating local image tag: %w", err))
 				return
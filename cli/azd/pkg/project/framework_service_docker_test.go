@@ -5,6 +5,7 @@ package project
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -100,13 +101,13 @@ services:
 
 	done := make(chan bool)
 
-	internalFramework := NewNpmProject(npmCli, env)
+	internalFramework := NewNpmProject(npmCli, env, mockContext.Console)
 	progressMessages := []string{}
 
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -208,13 +209,13 @@ services:
 
 	done := make(chan bool)
 
-	internalFramework := NewNpmProject(npmCli, env)
+	internalFramework := NewNpmProject(npmCli, env, mockContext.Console)
 	status := ""
 
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -270,7 +271,7 @@ func Test_DockerProject_Build(t *testing.T) {
 	dockerProject := NewDockerProject(
 		env,
 		dockerCli,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -301,6 +302,148 @@ func Test_DockerProject_Build(t *testing.T) {
 	require.NotEmpty(t, dockerBuildResult.ImageId)
 }
 
+func Test_DockerProject_Build_Cache(t *testing.T) {
+	setup := func(
+		t *testing.T, buildxErr error, driver string,
+	) (*mocks.MockContext, *mockinput.MockConsole, *exec.RunArgs) {
+		runArgs := &exec.RunArgs{}
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx version")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if buildxErr != nil {
+					return exec.NewRunResult(1, "", ""), buildxErr
+				}
+				return exec.NewRunResult(0, "github.com/docker/buildx v0.12.1", ""), nil
+			})
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker buildx inspect")
+			}).
+			Respond(exec.NewRunResult(0, "Name:          default\nDriver:        "+driver+"\n", ""))
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "build -f")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				// extract img id file arg. "--iidfile" and path args are expected always at the end
+				argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+				*runArgs = args
+				runArgs.Args = argsNoFile
+				err := os.WriteFile(value, []byte("IMAGE_ID"), 0600)
+				require.NoError(t, err)
+				return exec.NewRunResult(0, "IMAGE_ID", ""), nil
+			})
+
+		return mockContext, mockinput.NewMockConsole(), runArgs
+	}
+
+	// build builds the image and returns the registries logged into
+	build := func(t *testing.T, mockContext *mocks.MockContext, console *mockinput.MockConsole) []string {
+		env := environment.NewWithValues("test", map[string]string{
+			environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+		})
+		dockerCli := docker.NewDocker(mockContext.CommandRunner)
+		registryService := &loginRegistryService{}
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		temp := t.TempDir()
+		serviceConfig.Project.Path = temp
+		serviceConfig.RelativePath = ""
+		serviceConfig.Docker.CacheFrom = []ExpandableString{
+			NewExpandableString("type=registry,ref=${AZURE_CONTAINER_REGISTRY_ENDPOINT}/api:cache"),
+		}
+		serviceConfig.Docker.CacheTo = []ExpandableString{
+			NewExpandableString("type=registry,ref=${AZURE_CONTAINER_REGISTRY_ENDPOINT}/api:cache,mode=max"),
+			NewExpandableString("type=inline"),
+		}
+		err := os.WriteFile(filepath.Join(temp, "Dockerfile"), []byte("FROM node:14"), 0600)
+		require.NoError(t, err)
+
+		dockerProject := NewDockerProject(
+			env,
+			dockerCli,
+			NewContainerHelper(env, &mockenv.MockEnvManager{}, clock.NewMock(), registryService, dockerCli, nil, nil),
+			console,
+			mockContext.AlphaFeaturesManager,
+			mockContext.CommandRunner)
+		buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+		logProgress(buildTask)
+
+		result, err := buildTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "IMAGE_ID", result.BuildOutputPath)
+
+		return registryService.logins
+	}
+
+	t.Run("Buildx", func(t *testing.T) {
+		mockContext, console, runArgs := setup(t, nil, "docker-container")
+		logins := build(t, mockContext, console)
+
+		require.Equal(t,
+			[]string{
+				"buildx", "build",
+				"-f", "./Dockerfile",
+				"--platform", docker.DefaultPlatform,
+				"-t", "test-app-api",
+				"--cache-from", "type=registry,ref=contoso.azurecr.io/api:cache",
+				"--cache-to", "type=registry,ref=contoso.azurecr.io/api:cache,mode=max",
+				"--cache-to", "type=inline",
+				"--load",
+				".",
+			},
+			runArgs.Args,
+		)
+		require.Empty(t, console.Output())
+		// the cache is in the registry of the service
+		require.Equal(t, []string{"contoso.azurecr.io"}, logins)
+	})
+
+	t.Run("DockerDriver", func(t *testing.T) {
+		mockContext, console, runArgs := setup(t, nil, "docker")
+		logins := build(t, mockContext, console)
+
+		// the default driver can't export the cache to a registry
+		require.Equal(t,
+			[]string{
+				"buildx", "build",
+				"-f", "./Dockerfile",
+				"--platform", docker.DefaultPlatform,
+				"-t", "test-app-api",
+				"--cache-from", "type=registry,ref=contoso.azurecr.io/api:cache",
+				"--cache-to", "type=inline",
+				"--load",
+				".",
+			},
+			runArgs.Args,
+		)
+		require.Len(t, console.Output(), 1)
+		require.Contains(t, console.Output()[0], "uses the 'docker' driver")
+		require.Equal(t, []string{"contoso.azurecr.io"}, logins)
+	})
+
+	t.Run("MissingBuildx", func(t *testing.T) {
+		mockContext, console, runArgs := setup(t, errors.New("'buildx' is not a docker command"), "")
+		logins := build(t, mockContext, console)
+
+		require.Equal(t,
+			[]string{
+				"build",
+				"-f", "./Dockerfile",
+				"--platform", docker.DefaultPlatform,
+				"-t", "test-app-api",
+				".",
+			},
+			runArgs.Args,
+		)
+		require.Len(t, console.Output(), 1)
+		require.Contains(t, console.Output()[0], "Building the image without the cache")
+		require.Empty(t, logins)
+	})
+}
+
 func Test_DockerProject_Package(t *testing.T) {
 	var runArgs exec.RunArgs
 
@@ -323,7 +466,7 @@ func Test_DockerProject_Package(t *testing.T) {
 	dockerProject := NewDockerProject(
 		env,
 		dockerCli,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -354,3 +497,72 @@ func Test_DockerProject_Package(t *testing.T) {
 		runArgs.Args,
 	)
 }
+
+func Test_DockerProject_RemoteBuild(t *testing.T) {
+	// no docker commands are mocked, the image is built in the registry when the service is deployed
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("test", map[string]string{})
+	dockerCli := docker.NewDocker(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	temp := t.TempDir()
+	serviceConfig.Project.Path = temp
+	serviceConfig.RelativePath = ""
+	serviceConfig.Docker.RemoteBuild = true
+
+	dockerProject := NewDockerProject(
+		env,
+		dockerCli,
+		NewContainerHelper(env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, dockerCli, nil, nil),
+		mockinput.NewMockConsole(),
+		mockContext.AlphaFeaturesManager,
+		mockContext.CommandRunner)
+
+	t.Run("MissingDockerfile", func(t *testing.T) {
+		buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+		logProgress(buildTask)
+
+		_, err := buildTask.Await()
+		require.ErrorContains(t, err, "service 'api' sets 'docker.remoteBuild', which requires a Dockerfile")
+	})
+
+	t.Run("BuildAndPackage", func(t *testing.T) {
+		err := os.WriteFile(filepath.Join(temp, "Dockerfile"), []byte("FROM node:14"), 0600)
+		require.NoError(t, err)
+
+		buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+		logProgress(buildTask)
+
+		buildResult, err := buildTask.Await()
+		require.NoError(t, err)
+		require.Empty(t, buildResult.BuildOutputPath)
+
+		packageTask := dockerProject.Package(*mockContext.Context, serviceConfig, buildResult)
+		logProgress(packageTask)
+
+		packageResult, err := packageTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "test-app/api-test:azd-deploy-0", packageResult.PackagePath)
+		require.Equal(t, &dockerPackageResult{ImageTag: "test-app/api-test:azd-deploy-0"}, packageResult.Details)
+	})
+
+	t.Run("CacheNotSupported", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.RemoteBuild = true
+		serviceConfig.Docker.CacheFrom = []ExpandableString{NewExpandableString("type=gha")}
+
+		err := dockerProject.Initialize(*mockContext.Context, serviceConfig)
+		require.ErrorContains(t, err, "sets 'docker.remoteBuild' and 'docker.cacheFrom' or 'docker.cacheTo'")
+	})
+
+	t.Run("MultipleRegistriesNotSupported", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.RemoteBuild = true
+		serviceConfig.Docker.Registries = []ExpandableString{
+			NewExpandableString("contoso.azurecr.io"),
+			NewExpandableString("contosoeu.azurecr.io"),
+		}
+
+		err := dockerProject.Initialize(*mockContext.Context, serviceConfig)
+		require.ErrorContains(t, err, "sets 'docker.remoteBuild' and multiple 'docker.registries'")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -22,6 +23,21 @@ const (
 	defaultDotNetBuildConfiguration string = "Release"
 )
 
+// The .NET configuration options of a service
+type DotNetOptions struct {
+	// The build configuration of the service, for example Debug. Defaults to Release.
+	Configuration string `yaml:"configuration,omitempty"`
+	// The MSBuild publish profile used to publish the service.
+	PublishProfile string `yaml:"publishProfile,omitempty"`
+	// The runtime identifier the service is published for, for example linux-x64. Required by PublishAot and
+	// PublishReadyToRun.
+	RuntimeIdentifier string `yaml:"runtimeIdentifier,omitempty"`
+	// When true, the service is published as a Native AOT app.
+	PublishAot bool `yaml:"publishAot,omitempty"`
+	// When true, the assemblies of the service are published in the ReadyToRun format.
+	PublishReadyToRun bool `yaml:"publishReadyToRun,omitempty"`
+}
+
 type dotnetProject struct {
 	env       *environment.Environment
 	dotnetCli dotnet.DotNetCli
@@ -55,6 +71,10 @@ func (dp *dotnetProject) RequiredExternalTools(context.Context) []tools.External
 
 // Initializes the dotnet project
 func (dp *dotnetProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if _, err := dotnetPublishOptions(serviceConfig); err != nil {
+		return err
+	}
+
 	// NOTE(ellismg): For dotnet based apps, we installed a lifecycle hook that would write all the outputs from a deployment
 	// into dotnet user-secrets. The goal of this was to make it easy to consume the values from your infrastructure in your
 	// dotnet app, but the strategy doesn't work well in practice and it ends up being an abuse of the user secrets setup.
@@ -118,12 +138,13 @@ func (dp *dotnetProject) Build(
 				task.SetError(err)
 				return
 			}
-			if err := dp.dotnetCli.Build(ctx, projFile, defaultDotNetBuildConfiguration, ""); err != nil {
+			configuration := dotnetConfiguration(serviceConfig)
+			if err := dp.dotnetCli.Build(ctx, projFile, configuration, ""); err != nil {
 				task.SetError(err)
 				return
 			}
 
-			defaultOutputDir := filepath.Join("./bin", defaultDotNetBuildConfiguration)
+			defaultOutputDir := filepath.Join("./bin", configuration)
 
 			// Attempt to find the default build output location
 			buildOutputDir := serviceConfig.Path()
@@ -168,7 +189,14 @@ func (dp *dotnetProject) Package(
 				task.SetError(err)
 				return
 			}
-			if err := dp.dotnetCli.Publish(ctx, projFile, defaultDotNetBuildConfiguration, packageDest); err != nil {
+			publishOptions, err := dotnetPublishOptions(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			err = dp.dotnetCli.Publish(ctx, projFile, dotnetConfiguration(serviceConfig), packageDest, publishOptions)
+			if err != nil {
 				task.SetError(err)
 				return
 			}
@@ -190,6 +218,81 @@ func (dp *dotnetProject) Package(
 	)
 }
 
+// dotnetConfiguration returns the build configuration of the service.
+func dotnetConfiguration(serviceConfig *ServiceConfig) string {
+	if serviceConfig.DotNet.Configuration != "" {
+		return serviceConfig.DotNet.Configuration
+	}
+
+	return defaultDotNetBuildConfiguration
+}
+
+// dotnetPublishOptions returns the dotnet publish options of the service, or an error when the options of the service
+// conflict with each other or with the host of the service.
+func dotnetPublishOptions(serviceConfig *ServiceConfig) (*dotnet.PublishOptions, error) {
+	options := serviceConfig.DotNet
+	if options.PublishAot && options.PublishReadyToRun {
+		return nil, fmt.Errorf(
+			"service '%s' sets both publishAot and publishReadyToRun, only one of them can be set", serviceConfig.Name)
+	}
+
+	if (options.PublishAot || options.PublishReadyToRun) && options.RuntimeIdentifier == "" {
+		return nil, fmt.Errorf(
+			"service '%s' requires a runtimeIdentifier, such as linux-x64, when publishAot or publishReadyToRun is set",
+			serviceConfig.Name)
+	}
+
+	if options.PublishAot {
+		switch serviceConfig.Host {
+		case AzureFunctionTarget:
+			return nil, fmt.Errorf(
+				"service '%s' sets publishAot, which isn't supported by Azure Functions. Use publishReadyToRun instead",
+				serviceConfig.Name)
+		case DotNetContainerAppTarget:
+			return nil, fmt.Errorf(
+				"service '%s' sets publishAot, which isn't supported for .NET Aspire projects", serviceConfig.Name)
+		}
+
+		// Native AOT compiles for the operating system the app is published on
+		if osPrefix := runtimeIdentifierOs(); !strings.HasPrefix(options.RuntimeIdentifier, osPrefix) {
+			return nil, fmt.Errorf(
+				"service '%s' sets publishAot for runtimeIdentifier %s, but Native AOT apps can only be published for the "+
+					"operating system they are published on. Use a runtimeIdentifier starting with %s, or publish the "+
+					"service on the operating system of %s",
+				serviceConfig.Name,
+				options.RuntimeIdentifier,
+				osPrefix,
+				options.RuntimeIdentifier)
+		}
+	}
+
+	publishOptions := &dotnet.PublishOptions{
+		PublishProfile:    options.PublishProfile,
+		RuntimeIdentifier: options.RuntimeIdentifier,
+		PublishAot:        options.PublishAot,
+		PublishReadyToRun: options.PublishReadyToRun,
+	}
+
+	if *publishOptions == (dotnet.PublishOptions{}) {
+		return nil, nil
+	}
+
+	return publishOptions, nil
+}
+
+// runtimeIdentifierOs returns the prefix of the runtime identifiers of the current operating system, for example
+// 'linux'.
+func runtimeIdentifierOs() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "win"
+	case "darwin":
+		return "osx"
+	default:
+		return runtime.GOOS
+	}
+}
+
 func (dp *dotnetProject) setUserSecretsFromOutputs(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
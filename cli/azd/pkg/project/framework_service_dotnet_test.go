@@ -272,3 +272,105 @@ func Test_DotNetProject_Package(t *testing.T) {
 		runArgs.Args,
 	)
 }
+
+func Test_dotnetPublishOptions(t *testing.T) {
+	localRid := runtimeIdentifierOs() + "-x64"
+	otherRid := "linux-x64"
+	if runtimeIdentifierOs() == "linux" {
+		otherRid = "win-x64"
+	}
+
+	tests := []struct {
+		name    string
+		host    ServiceTargetKind
+		options DotNetOptions
+		want    *dotnet.PublishOptions
+		wantErr string
+	}{
+		{
+			name: "Defaults",
+			host: AppServiceTarget,
+		},
+		{
+			name:    "ConfigurationOnly",
+			host:    AppServiceTarget,
+			options: DotNetOptions{Configuration: "Debug"},
+		},
+		{
+			name: "PublishProfile",
+			host: AppServiceTarget,
+			options: DotNetOptions{
+				PublishProfile:    "FolderProfile",
+				RuntimeIdentifier: "linux-x64",
+			},
+			want: &dotnet.PublishOptions{
+				PublishProfile:    "FolderProfile",
+				RuntimeIdentifier: "linux-x64",
+			},
+		},
+		{
+			name:    "PublishAot",
+			host:    AppServiceTarget,
+			options: DotNetOptions{RuntimeIdentifier: localRid, PublishAot: true},
+			want:    &dotnet.PublishOptions{RuntimeIdentifier: localRid, PublishAot: true},
+		},
+		{
+			name:    "PublishReadyToRunForFunctions",
+			host:    AzureFunctionTarget,
+			options: DotNetOptions{RuntimeIdentifier: otherRid, PublishReadyToRun: true},
+			want:    &dotnet.PublishOptions{RuntimeIdentifier: otherRid, PublishReadyToRun: true},
+		},
+		{
+			name:    "AotAndReadyToRun",
+			host:    AppServiceTarget,
+			options: DotNetOptions{RuntimeIdentifier: localRid, PublishAot: true, PublishReadyToRun: true},
+			wantErr: "sets both publishAot and publishReadyToRun",
+		},
+		{
+			name:    "AotWithoutRuntimeIdentifier",
+			host:    AppServiceTarget,
+			options: DotNetOptions{PublishAot: true},
+			wantErr: "requires a runtimeIdentifier",
+		},
+		{
+			name:    "ReadyToRunWithoutRuntimeIdentifier",
+			host:    AppServiceTarget,
+			options: DotNetOptions{PublishReadyToRun: true},
+			wantErr: "requires a runtimeIdentifier",
+		},
+		{
+			name:    "AotForFunctions",
+			host:    AzureFunctionTarget,
+			options: DotNetOptions{RuntimeIdentifier: localRid, PublishAot: true},
+			wantErr: "isn't supported by Azure Functions",
+		},
+		{
+			name:    "AotForAspire",
+			host:    DotNetContainerAppTarget,
+			options: DotNetOptions{RuntimeIdentifier: localRid, PublishAot: true},
+			wantErr: "isn't supported for .NET Aspire projects",
+		},
+		{
+			name:    "AotForOtherOs",
+			host:    AppServiceTarget,
+			options: DotNetOptions{RuntimeIdentifier: otherRid, PublishAot: true},
+			wantErr: "can only be published for the operating system they are published on",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceConfig := createTestServiceConfig("./src/api", tt.host, ServiceLanguageDotNet)
+			serviceConfig.DotNet = tt.options
+
+			options, err := dotnetPublishOptions(serviceConfig)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, options)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+type goProject struct {
+}
+
+// NewGoProject creates a new instance of a Go project.
+//
+// Go services are compiled as part of building their container image, so restore, build and package are no-ops
+// and the project is only supported on container based hosts.
+func NewGoProject() FrameworkService {
+	return &goProject{}
+}
+
+func (gp *goProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		Package: FrameworkPackageRequirements{
+			RequireRestore: false,
+			RequireBuild:   false,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (gp *goProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the Go project
+func (gp *goProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if serviceConfig.Host != ContainerAppTarget && serviceConfig.Host != AksTarget && serviceConfig.Host != AciTarget {
+		return fmt.Errorf(
+			"service '%s' uses language '%s' which is only supported with host '%s', '%s' or '%s'",
+			serviceConfig.Name,
+			ServiceLanguageGo,
+			ContainerAppTarget,
+			AksTarget,
+			AciTarget,
+		)
+	}
+
+	return nil
+}
+
+// Restore for Go apps performs a no-op, dependencies are downloaded when the container image is built.
+func (gp *goProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Build for Go apps performs a no-op and returns the service path, the binary is built in the container image.
+func (gp *goProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: serviceConfig.Path(),
+			})
+		},
+	)
+}
+
+// Package for Go apps performs a no-op and returns the build output.
+func (gp *goProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: buildOutput.BuildOutputPath,
+			})
+		},
+	)
+}
@@ -8,23 +8,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
 )
 
 type npmProject struct {
-	env *environment.Environment
-	cli npm.NpmCli
+	env     *environment.Environment
+	cli     npm.NpmCli
+	console input.Console
 }
 
 // NewNpmProject creates a new instance of a NPM project
-func NewNpmProject(cli npm.NpmCli, env *environment.Environment) FrameworkService {
+func NewNpmProject(cli npm.NpmCli, env *environment.Environment, console input.Console) FrameworkService {
 	return &npmProject{
-		env: env,
-		cli: cli,
+		env:     env,
+		cli:     cli,
+		console: console,
 	}
 }
 
@@ -48,14 +53,33 @@ func (np *npmProject) Initialize(ctx context.Context, serviceConfig *ServiceConf
 	return nil
 }
 
-// Restores dependencies for the NPM project using npm install command
+// Restores dependencies for the NPM project using the install command of the package manager inferred from the
+// project lockfile (npm, yarn or pnpm)
 func (np *npmProject) Restore(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 ) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
-			task.SetProgress(NewServiceProgress("Installing NPM dependencies"))
+			packageManager, err := np.cli.PackageManager(serviceConfig.Path())
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if len(packageManager.LockFiles) > 1 {
+				np.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: fmt.Sprintf(
+						"multiple lockfiles found for service '%s' (%s), using %s since %s is the most recently modified",
+						serviceConfig.Name,
+						strings.Join(packageManager.LockFiles, ", "),
+						packageManager.Kind,
+						packageManager.LockFile,
+					),
+				})
+			}
+
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing %s dependencies", packageManager.Kind)))
 			if err := np.cli.Install(ctx, serviceConfig.Path()); err != nil {
 				task.SetError(err)
 				return
@@ -74,9 +98,15 @@ func (np *npmProject) Build(
 ) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			packageManager, err := np.cli.PackageManager(serviceConfig.Path())
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Exec custom `build` script if available
 			// If `build`` script is not defined in the package.json the NPM script will NOT fail
-			task.SetProgress(NewServiceProgress("Running NPM build script"))
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Running %s build script", packageManager.Kind)))
 			if err := np.cli.RunScript(ctx, serviceConfig.Path(), "build"); err != nil {
 				task.SetError(err)
 				return
@@ -109,7 +139,13 @@ func (np *npmProject) Package(
 				return
 			}
 
-			task.SetProgress(NewServiceProgress("Running NPM package script"))
+			packageManager, err := np.cli.PackageManager(serviceConfig.Path())
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Running %s package script", packageManager.Kind)))
 
 			// Long term this script we call should better align with our inner-loop scenarios
 			// Keeping this defaulted to `build` will create confusion for users when we start to support
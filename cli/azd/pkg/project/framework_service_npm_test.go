@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -33,7 +34,7 @@ func Test_NpmProject_Restore(t *testing.T) {
 	npmCli := npm.NewNpmCli(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
 
-	npmProject := NewNpmProject(npmCli, env)
+	npmProject := NewNpmProject(npmCli, env, mockContext.Console)
 	restoreTask := npmProject.Restore(*mockContext.Context, serviceConfig)
 	logProgress(restoreTask)
 
@@ -48,6 +49,75 @@ func Test_NpmProject_Restore(t *testing.T) {
 	)
 }
 
+func Test_NpmProject_Restore_DetectedPackageManager(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "install") || strings.Contains(command, "run build")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	env := environment.New("test")
+	npmCli := npm.NewNpmCli(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+	err := os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory)
+	require.NoError(t, err)
+
+	// the newest lockfile determines the package manager
+	lockFiles := []string{"package-lock.json", "pnpm-lock.yaml"}
+	for i, lockFile := range lockFiles {
+		lockFilePath := filepath.Join(serviceConfig.Path(), lockFile)
+		err = os.WriteFile(lockFilePath, nil, osutil.PermissionFile)
+		require.NoError(t, err)
+
+		modTime := time.Now().Add(time.Duration(i-len(lockFiles)) * time.Hour)
+		err = os.Chtimes(lockFilePath, modTime, modTime)
+		require.NoError(t, err)
+	}
+
+	npmProject := NewNpmProject(npmCli, env, mockContext.Console)
+	restoreTask := npmProject.Restore(*mockContext.Context, serviceConfig)
+	progress := []string{}
+	done := make(chan bool)
+	go func() {
+		for value := range restoreTask.Progress() {
+			progress = append(progress, value.Message)
+		}
+		done <- true
+	}()
+
+	result, err := restoreTask.Await()
+	<-done
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, "pnpm", runArgs.Cmd)
+	require.Equal(t, []string{"install"}, runArgs.Args)
+	require.Contains(t, progress, "Installing pnpm dependencies")
+
+	consoleOutput := strings.Join(mockContext.Console.Output(), "\n")
+	require.Contains(t, consoleOutput, "multiple lockfiles found for service 'api'")
+
+	// the package manager detected for the service is reused, rather than detected again
+	err = os.Remove(filepath.Join(serviceConfig.Path(), "pnpm-lock.yaml"))
+	require.NoError(t, err)
+
+	buildTask := npmProject.Build(*mockContext.Context, serviceConfig, result)
+	logProgress(buildTask)
+
+	_, err = buildTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, "pnpm", runArgs.Cmd)
+	require.Equal(t, []string{"run", "build", "--if-present"}, runArgs.Args)
+}
+
 func Test_NpmProject_Build(t *testing.T) {
 	var runArgs exec.RunArgs
 
@@ -65,7 +135,7 @@ func Test_NpmProject_Build(t *testing.T) {
 	npmCli := npm.NewNpmCli(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
 
-	npmProject := NewNpmProject(npmCli, env)
+	npmProject := NewNpmProject(npmCli, env, mockContext.Console)
 	buildTask := npmProject.Build(*mockContext.Context, serviceConfig, nil)
 	logProgress(buildTask)
 
@@ -103,7 +173,7 @@ func Test_NpmProject_Package(t *testing.T) {
 	err = os.WriteFile(filepath.Join(serviceConfig.Path(), "package.json"), nil, osutil.PermissionFile)
 	require.NoError(t, err)
 
-	npmProject := NewNpmProject(npmCli, env)
+	npmProject := NewNpmProject(npmCli, env, mockContext.Console)
 	packageTask := npmProject.Package(
 		*mockContext.Context,
 		serviceConfig,
@@ -50,40 +50,53 @@ func (pp *pythonProject) Initialize(ctx context.Context, serviceConfig *ServiceC
 	return nil
 }
 
-// Restores the project dependencies using PIP requirements.txt
+// Restores the project dependencies with the package manager inferred for the project (pip, poetry, pipenv or uv). pip
+// installs the dependencies of requirements.txt or pyproject.toml into a virtual environment created for the project.
 func (pp *pythonProject) Restore(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 ) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
-			task.SetProgress(NewServiceProgress("Checking for Python virtual environment"))
-			vEnvName := pp.getVenvName(serviceConfig)
-			vEnvPath := path.Join(serviceConfig.Path(), vEnvName)
-
-			_, err := os.Stat(vEnvPath)
+			packageManager, err := python.DetectPackageManager(serviceConfig.Path())
 			if err != nil {
-				if os.IsNotExist(err) {
-					task.SetProgress(NewServiceProgress("Creating Python virtual environment"))
-					err = pp.cli.CreateVirtualEnv(ctx, serviceConfig.Path(), vEnvName)
-					if err != nil {
-						task.SetError(fmt.Errorf(
-							"python virtual environment for project '%s' could not be created: %w",
-							serviceConfig.Path(),
-							err,
-						))
+				task.SetError(fmt.Errorf("detecting package manager for %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			vEnvName := pp.getVenvName(serviceConfig)
+			if packageManager.Kind == python.PackageManagerPip {
+				task.SetProgress(NewServiceProgress("Checking for Python virtual environment"))
+				vEnvPath := path.Join(serviceConfig.Path(), vEnvName)
+
+				_, err := os.Stat(vEnvPath)
+				if err != nil {
+					if os.IsNotExist(err) {
+						task.SetProgress(NewServiceProgress("Creating Python virtual environment"))
+						err = pp.cli.CreateVirtualEnv(ctx, serviceConfig.Path(), vEnvName)
+						if err != nil {
+							task.SetError(fmt.Errorf(
+								"python virtual environment for project '%s' could not be created: %w",
+								serviceConfig.Path(),
+								err,
+							))
+							return
+						}
+					} else {
+						task.SetError(
+							fmt.Errorf(
+								"python virtual environment for project '%s' is not accessible: %w", serviceConfig.Path(), err),
+						)
 						return
 					}
-				} else {
-					task.SetError(
-						fmt.Errorf("python virtual environment for project '%s' is not accessible: %w", serviceConfig.Path(), err),
-					)
-					return
 				}
+
+				task.SetProgress(NewServiceProgress("Installing Python PIP dependencies"))
+			} else {
+				task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing Python dependencies with %s", packageManager.Kind)))
 			}
 
-			task.SetProgress(NewServiceProgress("Installing Python PIP dependencies"))
-			err = pp.cli.InstallRequirements(ctx, serviceConfig.Path(), vEnvName, "requirements.txt")
+			err = pp.cli.InstallDependencies(ctx, serviceConfig.Path(), vEnvName, packageManager)
 			if err != nil {
 				task.SetError(
 					fmt.Errorf("requirements for project '%s' could not be installed: %w", serviceConfig.Path(), err),
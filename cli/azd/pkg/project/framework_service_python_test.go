@@ -85,6 +85,84 @@ func Test_PythonProject_Restore(t *testing.T) {
 	}
 }
 
+func Test_PythonProject_Restore_PackageManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{
+			name:     "Poetry",
+			files:    map[string]string{"pyproject.toml": "[tool.poetry]\n", "poetry.lock": ""},
+			wantCmd:  "poetry",
+			wantArgs: []string{"install", "--no-interaction", "--no-root"},
+		},
+		{
+			name:     "Pipenv",
+			files:    map[string]string{"Pipfile": "[packages]\n"},
+			wantCmd:  "pipenv",
+			wantArgs: []string{"install"},
+		},
+		{
+			name:     "Uv",
+			files:    map[string]string{"pyproject.toml": "[project]\n", "uv.lock": ""},
+			wantCmd:  "uv",
+			wantArgs: []string{"sync"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runArgs exec.RunArgs
+			ranVenv := false
+
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return strings.Contains(command, "-m venv")
+				}).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					ranVenv = true
+					return exec.NewRunResult(0, "", ""), nil
+				})
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return args.Cmd == tt.wantCmd
+				}).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					runArgs = args
+					return exec.NewRunResult(0, "", ""), nil
+				})
+
+			env := environment.New("test")
+			pythonCli := python.NewPythonCli(mockContext.CommandRunner)
+			serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+			require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+			for name, contents := range tt.files {
+				err := os.WriteFile(filepath.Join(serviceConfig.Path(), name), []byte(contents), osutil.PermissionFile)
+				require.NoError(t, err)
+			}
+
+			pythonProject := NewPythonProject(pythonCli, env)
+			restoreTask := pythonProject.Restore(*mockContext.Context, serviceConfig)
+			logProgress(restoreTask)
+
+			result, err := restoreTask.Await()
+			require.NoError(t, err)
+			require.NotNil(t, result)
+
+			// the package managers create their own virtual environments
+			require.False(t, ranVenv)
+			require.Equal(t, tt.wantArgs, runArgs.Args)
+			require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+		})
+	}
+}
+
 func Test_PythonProject_Build(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 
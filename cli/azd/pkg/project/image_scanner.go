@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/imagescan"
+)
+
+// ErrVulnerabilityThreshold is returned by CheckImageScanThreshold when an image has more high or critical
+// vulnerabilities than the threshold of the service.
+var ErrVulnerabilityThreshold = errors.New("too many high or critical vulnerabilities")
+
+// ImageScanner scans the container images of packaged services for vulnerabilities, with the first scanner installed.
+type ImageScanner struct {
+	scanners []imagescan.Scanner
+	console  input.Console
+}
+
+func NewImageScanner(commandRunner exec.CommandRunner, console input.Console) *ImageScanner {
+	return &ImageScanner{
+		scanners: []imagescan.Scanner{
+			imagescan.NewTrivy(commandRunner),
+			imagescan.NewDockerScout(commandRunner),
+		},
+		console: console,
+	}
+}
+
+// Scan scans the image of the packaged service, streaming the output of the scanner to the console. It returns nil when
+// the service has no local image to scan, or, unless the scan is [explicit], when no scanner is installed, in which case a
+// warning is shown.
+func (s *ImageScanner) Scan(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+	explicit bool,
+) (*imagescan.Result, error) {
+	packageDetails, ok := packageResult.Details.(*dockerPackageResult)
+	if !ok || packageDetails == nil {
+		log.Printf("skipping image scan of service %s, it isn't packaged as a container image", serviceConfig.Name)
+		return nil, nil
+	}
+
+	// images built in the registry aren't in the local image store
+	if packageDetails.ImageHash == "" {
+		s.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"skipping the vulnerability scan of service '%s', its image is built in the registry when it's deployed",
+				serviceConfig.Name),
+		})
+		return nil, nil
+	}
+
+	scanner, err := imagescan.Find(ctx, s.scanners...)
+	if errors.Is(err, imagescan.ErrNoScanner) {
+		installUrls := ""
+		for i, scanner := range s.scanners {
+			if i > 0 {
+				installUrls += " or "
+			}
+			installUrls += fmt.Sprintf("%s (%s)", scanner.Name(), scanner.InstallUrl())
+		}
+
+		if explicit {
+			return nil, fmt.Errorf("scanning the image of service '%s': %w, install %s", serviceConfig.Name, err, installUrls)
+		}
+
+		s.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"skipping the vulnerability scan of service '%s', %s. Install %s to scan the image",
+				serviceConfig.Name, err, installUrls),
+		})
+		return nil, nil
+	}
+
+	previewerWriter := s.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        fmt.Sprintf("%s Output", scanner.Name()),
+		})
+	result, err := scanner.Scan(ctx, packageDetails.ImageTag, previewerWriter)
+	s.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("scanning the image of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return result, nil
+}
+
+// CheckImageScanThreshold returns ErrVulnerabilityThreshold when the scanned image has more high or critical
+// vulnerabilities than `docker.scan.threshold` of the service, which is 0 when it isn't set.
+func CheckImageScanThreshold(serviceConfig *ServiceConfig, result *imagescan.Result) error {
+	threshold := 0
+	if serviceConfig.Docker.Scan != nil {
+		threshold = serviceConfig.Docker.Scan.Threshold
+	}
+
+	if count := result.Count(imagescan.SeverityCritical, imagescan.SeverityHigh); count > threshold {
+		return fmt.Errorf(
+			"%w: image '%s' of service '%s' has %d, the threshold is %d. Set 'docker.scan.threshold' of the "+
+				"service to allow more",
+			ErrVulnerabilityThreshold, result.Image, serviceConfig.Name, count, threshold)
+	}
+
+	return nil
+}
+
+// ImageScanSummary returns the ux item that shows the number of vulnerabilities found by the scan.
+func ImageScanSummary(result *imagescan.Result) *ux.ImageScanSummary {
+	return &ux.ImageScanSummary{
+		Scanner:  result.Scanner,
+		Image:    result.Image,
+		Critical: result.Vulnerabilities[imagescan.SeverityCritical],
+		High:     result.Vulnerabilities[imagescan.SeverityHigh],
+		Medium:   result.Vulnerabilities[imagescan.SeverityMedium],
+		Low:      result.Vulnerabilities[imagescan.SeverityLow],
+		Unknown:  result.Vulnerabilities[imagescan.SeverityUnknown],
+	}
+}
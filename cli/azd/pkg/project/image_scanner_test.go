@@ -0,0 +1,144 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/imagescan"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageScannerScan(t *testing.T) {
+	localPackage := &ServicePackageResult{
+		PackagePath: "api:azd-deploy-1",
+		Details: &dockerPackageResult{
+			ImageHash: "sha256:1234",
+			ImageTag:  "api:azd-deploy-1",
+		},
+	}
+	serviceConfig := &ServiceConfig{Name: "api"}
+
+	t.Run("Scanned", func(t *testing.T) {
+		scanner := &fakeImageScanner{name: "Trivy"}
+		imageScanner := &ImageScanner{
+			scanners: []imagescan.Scanner{&fakeImageScanner{name: "missing", missing: true}, scanner},
+			console:  mockinput.NewMockConsole(),
+		}
+
+		result, err := imageScanner.Scan(context.Background(), serviceConfig, localPackage, false)
+		require.NoError(t, err)
+		require.Equal(t, "Trivy", result.Scanner)
+		require.Equal(t, "api:azd-deploy-1", scanner.scannedImage)
+	})
+
+	t.Run("NotContainer", func(t *testing.T) {
+		imageScanner := &ImageScanner{
+			scanners: []imagescan.Scanner{&fakeImageScanner{name: "Trivy"}},
+			console:  mockinput.NewMockConsole(),
+		}
+
+		result, err := imageScanner.Scan(
+			context.Background(), serviceConfig, &ServicePackageResult{PackagePath: "api.zip"}, true)
+		require.NoError(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("RemoteBuild", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		imageScanner := &ImageScanner{
+			scanners: []imagescan.Scanner{&fakeImageScanner{name: "Trivy"}},
+			console:  console,
+		}
+
+		result, err := imageScanner.Scan(context.Background(), serviceConfig, &ServicePackageResult{
+			PackagePath: "api:azd-deploy-1",
+			Details:     &dockerPackageResult{ImageTag: "api:azd-deploy-1"},
+		}, true)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Contains(t, console.Output()[0], "its image is built in the registry")
+	})
+
+	t.Run("NoScanner", func(t *testing.T) {
+		console := mockinput.NewMockConsole()
+		imageScanner := &ImageScanner{
+			scanners: []imagescan.Scanner{&fakeImageScanner{name: "Trivy", missing: true}},
+			console:  console,
+		}
+
+		result, err := imageScanner.Scan(context.Background(), serviceConfig, localPackage, false)
+		require.NoError(t, err)
+		require.Nil(t, result)
+		require.Contains(t, console.Output()[0], "skipping the vulnerability scan of service 'api'")
+	})
+
+	t.Run("NoScannerExplicit", func(t *testing.T) {
+		imageScanner := &ImageScanner{
+			scanners: []imagescan.Scanner{&fakeImageScanner{name: "Trivy", missing: true}},
+			console:  mockinput.NewMockConsole(),
+		}
+
+		_, err := imageScanner.Scan(context.Background(), serviceConfig, localPackage, true)
+		require.ErrorIs(t, err, imagescan.ErrNoScanner)
+		require.ErrorContains(t, err, "install Trivy (https://example.com/Trivy)")
+	})
+}
+
+func TestCheckImageScanThreshold(t *testing.T) {
+	result := &imagescan.Result{
+		Scanner: "Trivy",
+		Image:   "api:azd-deploy-1",
+		Vulnerabilities: map[imagescan.Severity]int{
+			imagescan.SeverityCritical: 1,
+			imagescan.SeverityHigh:     2,
+			imagescan.SeverityMedium:   10,
+		},
+	}
+
+	err := CheckImageScanThreshold(&ServiceConfig{Name: "api"}, result)
+	require.ErrorIs(t, err, ErrVulnerabilityThreshold)
+	require.ErrorContains(t, err, "has 3, the threshold is 0")
+
+	err = CheckImageScanThreshold(&ServiceConfig{
+		Name:   "api",
+		Docker: DockerProjectOptions{Scan: &DockerScanOptions{Threshold: 3}},
+	}, result)
+	require.NoError(t, err)
+}
+
+type fakeImageScanner struct {
+	name         string
+	missing      bool
+	scannedImage string
+}
+
+func (f *fakeImageScanner) CheckInstalled(ctx context.Context) error {
+	if f.missing {
+		return errors.New("not installed")
+	}
+
+	return nil
+}
+
+func (f *fakeImageScanner) InstallUrl() string {
+	return "https://example.com/" + f.name
+}
+
+func (f *fakeImageScanner) Name() string {
+	return f.name
+}
+
+func (f *fakeImageScanner) Scan(ctx context.Context, image string, progress io.Writer) (*imagescan.Result, error) {
+	f.scannedImage = image
+	return &imagescan.Result{
+		Scanner:         f.name,
+		Image:           image,
+		Vulnerabilities: map[imagescan.Severity]int{},
+	}, nil
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/psanford/memfs"
 )
 
 type ImportManager struct {
@@ -23,6 +25,13 @@ func NewImportManager(dotNetImporter *DotNetImporter) *ImportManager {
 	}
 }
 
+// ResetCache removes the cached manifests of the app hosts, so they're generated again the next time they're needed.
+func (im *ImportManager) ResetCache() {
+	if im.dotNetImporter != nil {
+		im.dotNetImporter.ResetCache()
+	}
+}
+
 func (im *ImportManager) HasService(ctx context.Context, projectConfig *ProjectConfig, name string) (bool, error) {
 	services, err := im.ServiceStable(ctx, projectConfig)
 	if err != nil {
@@ -44,6 +53,10 @@ var (
 
 	errAppHostMustTargetContainerApp = fmt.Errorf(
 		"Aspire services must be configured to target the container app host at this time.")
+
+	errAppHostRequiresBicep = fmt.Errorf(
+		"the infrastructure of Aspire services is generated as Bicep. To use Terraform, add an 'infra' folder with " +
+			"the Terraform infrastructure of the project.")
 )
 
 // Retrieves the list of services in the project, in a stable ordering that is deterministic.
@@ -122,6 +135,15 @@ func (im *ImportManager) ProjectInfrastructure(ctx context.Context, projectConfi
 					return nil, errAppHostMustTargetContainerApp
 				}
 
+				// the providers are compared as they're parsed, since azure.yaml may not use their canonical case
+				for _, provider := range []provisioning.ProviderKind{projectConfig.Infra.Provider, svcConfig.Infra.Provider} {
+					if kind, err := provisioning.ParseProvider(provider); err != nil {
+						return nil, err
+					} else if kind == provisioning.Terraform {
+						return nil, errAppHostRequiresBicep
+					}
+				}
+
 				return im.dotNetImporter.ProjectInfrastructure(ctx, svcConfig)
 			} else if err != nil {
 				log.Printf("error checking if %s is an app host project: %v", svcConfig.Path(), err)
@@ -133,18 +155,96 @@ func (im *ImportManager) ProjectInfrastructure(ctx context.Context, projectConfi
 		"this project does not contain any infrastructure, have you created an '%s' folder?", filepath.Base(infraRoot))
 }
 
+// SynthAllInfrastructure returns the effective infrastructure of the project, with paths relative to the project
+// directory: the infrastructure generated for app hosts, or the infrastructure used to provision the project, which is
+// written to the infra.path of the project, see [synthInfraDir].
 func (im *ImportManager) SynthAllInfrastructure(ctx context.Context, projectConfig *ProjectConfig) (fs.FS, error) {
+	infraDir, err := synthInfraDir(projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, svcConfig := range projectConfig.Services {
 		if svcConfig.Language == ServiceLanguageDotNet {
-			if len(projectConfig.Services) != 1 {
-				return nil, errNoMultipleServicesWithAppHost
+			if canImport, err := im.dotNetImporter.CanImport(ctx, svcConfig.Path()); canImport {
+				if len(projectConfig.Services) != 1 {
+					return nil, errNoMultipleServicesWithAppHost
+				}
+
+				return im.dotNetImporter.SynthAllInfrastructure(ctx, projectConfig, svcConfig)
+			} else if err != nil {
+				log.Printf("error checking if %s is an app host project: %v", svcConfig.Path(), err)
 			}
+		}
+	}
+
+	infra, err := im.ProjectInfrastructure(ctx, projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	infraRoot := infra.Options.Path
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(projectConfig.Path, infraRoot)
+	}
+
+	generatedFS := memfs.New()
+	infraFS := os.DirFS(infraRoot)
+	err = fs.WalkDir(infraFS, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		target := filepath.Join(infraDir, path)
+		if err := generatedFS.MkdirAll(filepath.Dir(target), osutil.PermissionDirectoryOwnerOnly); err != nil {
+			return err
+		}
+
+		contents, err := fs.ReadFile(infraFS, path)
+		if err != nil {
+			return err
+		}
 
-			return im.dotNetImporter.SynthAllInfrastructure(ctx, projectConfig, svcConfig)
+		return generatedFS.WriteFile(target, contents, osutil.PermissionFile)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading infrastructure from %s: %w", infraRoot, err)
+	}
+
+	return generatedFS, nil
+}
+
+// synthInfraDir returns the directory, relative to the project directory, that synthesized infrastructure is written to:
+// the infra.path of the project, or the default infra folder when it isn't set. An error is returned when infra.path is
+// outside of the project directory.
+func synthInfraDir(projectConfig *ProjectConfig) (string, error) {
+	infraPath := projectConfig.Infra.Path
+	if infraPath == "" {
+		return provisioning.DefaultPath, nil
+	}
+
+	if filepath.IsAbs(infraPath) {
+		rel, err := filepath.Rel(projectConfig.Path, infraPath)
+		if err != nil {
+			return "", fmt.Errorf("infra.path %s is outside of the project directory: %w", infraPath, err)
 		}
+
+		infraPath = rel
+	}
+
+	infraPath = filepath.Clean(infraPath)
+	if infraPath == ".." || strings.HasPrefix(infraPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf(
+			"infra.path %s is outside of the project directory, set it to a directory of the project to synthesize the "+
+				"infrastructure into", projectConfig.Infra.Path)
 	}
 
-	return nil, fmt.Errorf("this project does not contain any infrastructure to synthesize")
+	return infraPath, nil
 }
 
 // Infra represents the (possibly temporarily generated) infrastructure. Call [Cleanup] when done with infrastructure,
@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ImportManager_SynthAllInfrastructure(t *testing.T) {
+	tests := map[string]struct {
+		infraPath string
+		synthDir  string
+	}{
+		"InfraFolder":   {infraPath: "infra", synthDir: "infra"},
+		"InfraPath":     {infraPath: filepath.Join("deploy", "bicep"), synthDir: filepath.Join("deploy", "bicep")},
+		"AbsoluteInfra": {synthDir: filepath.Join("deploy", "bicep")},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			infraPath := tt.infraPath
+			infraRoot := filepath.Join(projectDir, infraPath)
+			if infraPath == "" {
+				// an absolute infra.path within the project is written to relative to the project
+				infraRoot = filepath.Join(projectDir, tt.synthDir)
+				infraPath = infraRoot
+			}
+
+			require.NoError(t, os.MkdirAll(filepath.Join(infraRoot, "app"), osutil.PermissionDirectory))
+			require.NoError(t, os.WriteFile(filepath.Join(infraRoot, "main.bicep"), []byte("main"), osutil.PermissionFile))
+			require.NoError(t, os.WriteFile(
+				filepath.Join(infraRoot, "main.parameters.json"), []byte("{}"), osutil.PermissionFile))
+			require.NoError(t, os.WriteFile(filepath.Join(infraRoot, "app", "api.bicep"), []byte("api"), osutil.PermissionFile))
+
+			projectConfig := &ProjectConfig{
+				Name: "test-proj",
+				Path: projectDir,
+				Services: map[string]*ServiceConfig{
+					"api": {
+						Name:         "api",
+						Language:     ServiceLanguageTypeScript,
+						RelativePath: "src/api",
+					},
+				},
+			}
+			projectConfig.Infra.Path = infraPath
+
+			importManager := NewImportManager(nil)
+			synthFS, err := importManager.SynthAllInfrastructure(context.Background(), projectConfig)
+			require.NoError(t, err)
+
+			contents, err := fs.ReadFile(synthFS, filepath.Join(tt.synthDir, "main.bicep"))
+			require.NoError(t, err)
+			require.Equal(t, "main", string(contents))
+
+			contents, err = fs.ReadFile(synthFS, filepath.Join(tt.synthDir, "app", "api.bicep"))
+			require.NoError(t, err)
+			require.Equal(t, "api", string(contents))
+
+			_, err = fs.Stat(synthFS, filepath.Join(tt.synthDir, "main.parameters.json"))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_ImportManager_SynthAllInfrastructure_OutsideProject(t *testing.T) {
+	projectDir := t.TempDir()
+	for _, infraPath := range []string{t.TempDir(), filepath.Join("..", "shared")} {
+		projectConfig := &ProjectConfig{
+			Name:     "test-proj",
+			Path:     projectDir,
+			Services: map[string]*ServiceConfig{},
+		}
+		projectConfig.Infra.Path = infraPath
+
+		importManager := NewImportManager(nil)
+		_, err := importManager.SynthAllInfrastructure(context.Background(), projectConfig)
+		require.ErrorContains(t, err, "is outside of the project directory")
+	}
+}
+
+func Test_ImportManager_SynthAllInfrastructure_NoInfra(t *testing.T) {
+	projectConfig := &ProjectConfig{
+		Name:     "test-proj",
+		Path:     t.TempDir(),
+		Services: map[string]*ServiceConfig{},
+	}
+	projectConfig.Infra.Path = "infra"
+
+	importManager := NewImportManager(nil)
+	_, err := importManager.SynthAllInfrastructure(context.Background(), projectConfig)
+	require.ErrorContains(t, err, "this project does not contain any infrastructure")
+}
+
+func Test_ImportManager_ProjectInfrastructure_AppHostTerraform(t *testing.T) {
+	tests := map[string]struct {
+		projectProvider provisioning.ProviderKind
+		serviceProvider provisioning.ProviderKind
+	}{
+		"Project": {projectProvider: " Terraform"},
+		"Service": {serviceProvider: "TERRAFORM"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "IsAspireHost")
+			}).Respond(exec.NewRunResult(0, "true", ""))
+
+			importer := NewDotNetImporter(
+				dotnet.NewDotNetCli(mockContext.CommandRunner),
+				mockContext.Console,
+				lazy.NewLazy(func() (*environment.Environment, error) {
+					return nil, errors.New("no environment")
+				}),
+				lazy.From[environment.Manager](nil),
+			)
+
+			projectConfig := &ProjectConfig{
+				Name: "test-proj",
+				Path: t.TempDir(),
+				Services: map[string]*ServiceConfig{
+					"app": {
+						Name:         "app",
+						Language:     ServiceLanguageDotNet,
+						Host:         ContainerAppTarget,
+						RelativePath: "AppHost",
+					},
+				},
+			}
+			projectConfig.Infra.Path = "infra"
+			projectConfig.Infra.Provider = tt.projectProvider
+			projectConfig.Services["app"].Project = projectConfig
+			projectConfig.Services["app"].Infra.Provider = tt.serviceProvider
+
+			_, err := NewImportManager(importer).ProjectInfrastructure(*mockContext.Context, projectConfig)
+			require.ErrorIs(t, err, errAppHostRequiresBicep)
+		})
+	}
+}
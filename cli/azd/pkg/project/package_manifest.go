@@ -0,0 +1,198 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/benbjohnson/clock"
+)
+
+// ErrNoRecordedPackage is returned by PackageManifest.Load when no package of the service is recorded in the environment.
+var ErrNoRecordedPackage = errors.New("no recorded package")
+
+// PackageManifest saves the packages of the services to the package manifest of the environment and loads them back, so
+// a service packaged by azd package can be deployed later without packaging it again, for example by another job of a
+// pipeline. Images pushed by azd package --push are recorded by their reference in the registry, and the paths of the
+// artifacts in the project are recorded relative to the directory of the environment, so the packages can be deployed
+// from another machine. Images that weren't pushed can only be deployed from the image store they were built in.
+type PackageManifest struct {
+	env             *environment.Environment
+	envManager      environment.Manager
+	azdCtx          *azdcontext.AzdContext
+	containerHelper *ContainerHelper
+	clock           clock.Clock
+}
+
+func NewPackageManifest(
+	env *environment.Environment,
+	envManager environment.Manager,
+	azdCtx *azdcontext.AzdContext,
+	containerHelper *ContainerHelper,
+	clock clock.Clock,
+) *PackageManifest {
+	return &PackageManifest{
+		env:             env,
+		envManager:      envManager,
+		azdCtx:          azdCtx,
+		containerHelper: containerHelper,
+		clock:           clock,
+	}
+}
+
+// Save records the package of the service in the package manifest of the environment and saves the environment. When the
+// package can't be deployed again, like an image that is built in the registry, the previous record of the service is
+// removed instead.
+func (m *PackageManifest) Save(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+) error {
+	var servicePackage *environment.ServicePackage
+	if packageDetails, ok := packageResult.Details.(*dockerPackageResult); ok && packageDetails != nil {
+		// images built in the registry are built when the service is deployed
+		if packageDetails.ImageHash != "" {
+			servicePackage = &environment.ServicePackage{
+				Image:    packageDetails.ImageTag,
+				ImageId:  packageDetails.ImageHash,
+				ImageRef: packageDetails.RemoteImage,
+			}
+		}
+	} else if _, err := os.Stat(packageResult.PackagePath); err == nil {
+		artifactPath, err := filepath.Abs(packageResult.PackagePath)
+		if err != nil {
+			return fmt.Errorf("resolving package path of service '%s': %w", serviceConfig.Name, err)
+		}
+
+		// artifacts in the project are recorded relative to the environment, so they can be deployed from another
+		// checkout of the project. Others, like the temporary files of packages without --output-path, stay absolute.
+		projectPath, err := filepath.Rel(m.azdCtx.ProjectDirectory(), artifactPath)
+		if err == nil && filepath.IsLocal(projectPath) {
+			if relPath, err := filepath.Rel(m.envRoot(), artifactPath); err == nil {
+				artifactPath = filepath.ToSlash(relPath)
+			}
+		}
+
+		servicePackage = &environment.ServicePackage{
+			ArtifactPath: artifactPath,
+		}
+	}
+
+	if servicePackage != nil {
+		servicePackage.Target = string(serviceConfig.Host)
+		servicePackage.Timestamp = m.clock.Now().UTC()
+	}
+
+	if err := m.env.SetServicePackage(serviceConfig.Name, servicePackage); err != nil {
+		return fmt.Errorf("recording package of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if err := m.envManager.Save(ctx, m.env); err != nil {
+		return fmt.Errorf("saving package manifest to environment: %w", err)
+	}
+
+	return nil
+}
+
+// Load returns the package result of the package of the service recorded in the package manifest of the environment. An
+// error wrapping [ErrNoRecordedPackage] is returned when no package is recorded, and an error is returned when the
+// package was recorded for another service target, or it no longer exists: the image wasn't pushed and isn't in the
+// local image store or its tag refers to a different image, or the artifact was removed.
+func (m *PackageManifest) Load(ctx context.Context, serviceConfig *ServiceConfig) (*ServicePackageResult, error) {
+	if serviceConfig.Docker.RemoteBuild {
+		return nil, fmt.Errorf(
+			"%w for service '%s', its image is built in the registry when it's deployed",
+			ErrNoRecordedPackage,
+			serviceConfig.Name)
+	}
+
+	servicePackage, err := m.env.ServicePackage(serviceConfig.Name)
+	if err != nil {
+		return nil, fmt.Errorf("reading package manifest of environment '%s': %w", m.env.GetEnvName(), err)
+	}
+
+	if servicePackage == nil {
+		return nil, fmt.Errorf(
+			"%w for service '%s' in environment '%s', run 'azd package %s' first",
+			ErrNoRecordedPackage,
+			serviceConfig.Name,
+			m.env.GetEnvName(),
+			serviceConfig.Name)
+	}
+
+	if servicePackage.Target != string(serviceConfig.Host) {
+		return nil, fmt.Errorf(
+			"service '%s' was packaged for host '%s' and is now hosted by '%s', run 'azd package %s' again",
+			serviceConfig.Name,
+			servicePackage.Target,
+			serviceConfig.Host,
+			serviceConfig.Name)
+	}
+
+	if servicePackage.Image == "" {
+		artifactPath := filepath.FromSlash(servicePackage.ArtifactPath)
+		if !filepath.IsAbs(artifactPath) {
+			artifactPath = filepath.Join(m.envRoot(), artifactPath)
+		}
+
+		if _, err := os.Stat(artifactPath); err != nil {
+			return nil, fmt.Errorf(
+				"the package '%s' of service '%s' no longer exists, run 'azd package %s' again: %w",
+				artifactPath,
+				serviceConfig.Name,
+				serviceConfig.Name,
+				err)
+		}
+
+		return &ServicePackageResult{
+			PackagePath: artifactPath,
+		}, nil
+	}
+
+	// pushed images are deployed from the registry, so they don't need to be in the local image store
+	if servicePackage.ImageRef != "" {
+		return &ServicePackageResult{
+			PackagePath: servicePackage.Image,
+			Details: &dockerPackageResult{
+				ImageHash:   servicePackage.ImageId,
+				ImageTag:    servicePackage.Image,
+				RemoteImage: servicePackage.ImageRef,
+			},
+		}, nil
+	}
+
+	switch imageId := m.containerHelper.localImageId(ctx, servicePackage.Image); imageId {
+	case "":
+		return nil, fmt.Errorf(
+			"the image '%s' packaged for service '%s' is not in the local image store, run 'azd package %s' again",
+			servicePackage.Image,
+			serviceConfig.Name,
+			serviceConfig.Name)
+	case servicePackage.ImageId:
+		return &ServicePackageResult{
+			PackagePath: servicePackage.Image,
+			Details: &dockerPackageResult{
+				ImageHash: servicePackage.ImageId,
+				ImageTag:  servicePackage.Image,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf(
+			"the image '%s' packaged for service '%s' was re-tagged since it was packaged, run 'azd package %s' again",
+			servicePackage.Image,
+			serviceConfig.Name,
+			serviceConfig.Name)
+	}
+}
+
+// envRoot returns the directory of the environment, which artifact paths in the package manifest are relative to.
+func (m *PackageManifest) envRoot() string {
+	return m.azdCtx.EnvironmentRoot(m.env.GetEnvName())
+}
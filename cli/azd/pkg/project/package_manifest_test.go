@@ -0,0 +1,274 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PackageManifest_Save(t *testing.T) {
+	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	artifactPath := filepath.Join(azdCtx.ProjectDirectory(), "dist", "api.zip")
+	require.NoError(t, os.MkdirAll(filepath.Dir(artifactPath), 0700))
+	require.NoError(t, os.WriteFile(artifactPath, []byte("zip"), 0600))
+	tempArtifactPath := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, os.WriteFile(tempArtifactPath, []byte("zip"), 0600))
+
+	tests := []struct {
+		name          string
+		packageResult *ServicePackageResult
+		expected      *environment.ServicePackage
+	}{
+		{
+			name: "Image",
+			packageResult: &ServicePackageResult{
+				PackagePath: "test-app/api-dev:azd-deploy-100",
+				Details: &dockerPackageResult{
+					ImageHash: "sha256:0123",
+					ImageTag:  "test-app/api-dev:azd-deploy-100",
+				},
+			},
+			expected: &environment.ServicePackage{
+				Target:    string(ContainerAppTarget),
+				Image:     "test-app/api-dev:azd-deploy-100",
+				ImageId:   "sha256:0123",
+				Timestamp: time.Unix(100, 0).UTC(),
+			},
+		},
+		{
+			name: "PushedImage",
+			packageResult: &ServicePackageResult{
+				PackagePath: "test-app/api-dev:azd-deploy-100",
+				Details: &dockerPackageResult{
+					ImageHash:   "sha256:0123",
+					ImageTag:    "test-app/api-dev:azd-deploy-100",
+					RemoteImage: "contoso.azurecr.io/test-app/api-dev@sha256:4567",
+				},
+			},
+			expected: &environment.ServicePackage{
+				Target:    string(ContainerAppTarget),
+				Image:     "test-app/api-dev:azd-deploy-100",
+				ImageId:   "sha256:0123",
+				ImageRef:  "contoso.azurecr.io/test-app/api-dev@sha256:4567",
+				Timestamp: time.Unix(100, 0).UTC(),
+			},
+		},
+		{
+			// the path is relative to the environment directory, ./.azure/dev
+			name: "Artifact",
+			packageResult: &ServicePackageResult{
+				PackagePath: artifactPath,
+			},
+			expected: &environment.ServicePackage{
+				Target:       string(ContainerAppTarget),
+				ArtifactPath: "../../dist/api.zip",
+				Timestamp:    time.Unix(100, 0).UTC(),
+			},
+		},
+		{
+			name: "ArtifactOutsideProject",
+			packageResult: &ServicePackageResult{
+				PackagePath: tempArtifactPath,
+			},
+			expected: &environment.ServicePackage{
+				Target:       string(ContainerAppTarget),
+				ArtifactPath: tempArtifactPath,
+				Timestamp:    time.Unix(100, 0).UTC(),
+			},
+		},
+		{
+			name: "RemoteBuild",
+			packageResult: &ServicePackageResult{
+				PackagePath: "test-app/api-dev:azd-deploy-100",
+				Details: &dockerPackageResult{
+					ImageTag: "test-app/api-dev:azd-deploy-100",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			env := environment.New("dev")
+			// the package of a previous azd package is replaced
+			require.NoError(t, env.SetServicePackage("api", &environment.ServicePackage{Image: "test-app/api-dev:old"}))
+
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", *mockContext.Context, env).Return(nil)
+			mockClock := clock.NewMock()
+			mockClock.Add(100 * time.Second)
+
+			packageManifest := NewPackageManifest(env, envManager, azdCtx, nil, mockClock)
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+			require.NoError(t, packageManifest.Save(*mockContext.Context, serviceConfig, tt.packageResult))
+			envManager.AssertCalled(t, "Save", *mockContext.Context, env)
+
+			servicePackage, err := env.ServicePackage("api")
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, servicePackage)
+		})
+	}
+}
+
+func Test_PackageManifest_Load(t *testing.T) {
+	const image = "test-app/api-dev:azd-deploy-100"
+	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	artifactPath := filepath.Join(azdCtx.ProjectDirectory(), "dist", "api.zip")
+	require.NoError(t, os.MkdirAll(filepath.Dir(artifactPath), 0700))
+	require.NoError(t, os.WriteFile(artifactPath, []byte("zip"), 0600))
+
+	imagePackage := &environment.ServicePackage{
+		Target:  string(ContainerAppTarget),
+		Image:   image,
+		ImageId: "sha256:0123",
+	}
+
+	tests := []struct {
+		name           string
+		servicePackage *environment.ServicePackage
+		localImageId   string
+		remoteBuild    bool
+		expected       *ServicePackageResult
+		expectedError  string
+	}{
+		{
+			name:           "Image",
+			servicePackage: imagePackage,
+			localImageId:   "sha256:0123",
+			expected: &ServicePackageResult{
+				PackagePath: image,
+				Details: &dockerPackageResult{
+					ImageHash: "sha256:0123",
+					ImageTag:  image,
+				},
+			},
+		},
+		{
+			name: "Artifact",
+			servicePackage: &environment.ServicePackage{
+				Target:       string(ContainerAppTarget),
+				ArtifactPath: artifactPath,
+			},
+			expected: &ServicePackageResult{
+				PackagePath: artifactPath,
+			},
+		},
+		{
+			name: "RelativeArtifact",
+			servicePackage: &environment.ServicePackage{
+				Target:       string(ContainerAppTarget),
+				ArtifactPath: "../../dist/api.zip",
+			},
+			expected: &ServicePackageResult{
+				PackagePath: artifactPath,
+			},
+		},
+		{
+			// pushed images are deployed from the registry, from any machine
+			name: "PushedImage",
+			servicePackage: &environment.ServicePackage{
+				Target:   string(ContainerAppTarget),
+				Image:    image,
+				ImageId:  "sha256:0123",
+				ImageRef: "contoso.azurecr.io/test-app/api-dev@sha256:4567",
+			},
+			expected: &ServicePackageResult{
+				PackagePath: image,
+				Details: &dockerPackageResult{
+					ImageHash:   "sha256:0123",
+					ImageTag:    image,
+					RemoteImage: "contoso.azurecr.io/test-app/api-dev@sha256:4567",
+				},
+			},
+		},
+		{
+			name:          "NotPackaged",
+			expectedError: "no recorded package for service 'api' in environment 'dev', run 'azd package api' first",
+		},
+		{
+			name: "OtherTarget",
+			servicePackage: &environment.ServicePackage{
+				Target:       string(AppServiceTarget),
+				ArtifactPath: artifactPath,
+			},
+			expectedError: "was packaged for host 'appservice' and is now hosted by 'containerapp'",
+		},
+		{
+			name: "ArtifactRemoved",
+			servicePackage: &environment.ServicePackage{
+				Target:       string(ContainerAppTarget),
+				ArtifactPath: filepath.Join(filepath.Dir(artifactPath), "removed.zip"),
+			},
+			expectedError: "no longer exists",
+		},
+		{
+			name:           "NotInImageStore",
+			servicePackage: imagePackage,
+			expectedError:  "is not in the local image store",
+		},
+		{
+			name:           "Retagged",
+			servicePackage: imagePackage,
+			localImageId:   "sha256:4567",
+			expectedError:  "was re-tagged since it was packaged",
+		},
+		{
+			name:          "RemoteBuild",
+			remoteBuild:   true,
+			expectedError: "its image is built in the registry when it's deployed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "docker image inspect")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				if tt.localImageId == "" {
+					return exec.NewRunResult(1, "", "No such image"), errors.New("no such image")
+				}
+
+				return exec.NewRunResult(0, tt.localImageId+"\n", ""), nil
+			})
+
+			env := environment.New("dev")
+			if tt.servicePackage != nil {
+				require.NoError(t, env.SetServicePackage("api", tt.servicePackage))
+			}
+
+			containerHelper := NewContainerHelper(
+				env, &mockenv.MockEnvManager{}, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner), nil, nil)
+			packageManifest := NewPackageManifest(env, &mockenv.MockEnvManager{}, azdCtx, containerHelper, clock.NewMock())
+			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+			serviceConfig.Docker.RemoteBuild = tt.remoteBuild
+
+			packageResult, err := packageManifest.Load(*mockContext.Context, serviceConfig)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, packageResult)
+		})
+	}
+}
@@ -47,6 +47,10 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 		return nil, fmt.Errorf("unable to parse azure.yaml file. File is empty.")
 	}
 
+	if err := validateProject([]byte(yamlContent)); err != nil {
+		return nil, err
+	}
+
 	if err := yaml.Unmarshal([]byte(yamlContent), &projectConfig); err != nil {
 		return nil, fmt.Errorf(
 			"unable to parse azure.yaml file. Check the format of the file, "+
@@ -79,7 +83,7 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 	}
 
 	if projectConfig.Infra.Path == "" {
-		projectConfig.Infra.Path = "infra"
+		projectConfig.Infra.Path = provisioning.DefaultPath
 	}
 
 	for key, svc := range projectConfig.Services {
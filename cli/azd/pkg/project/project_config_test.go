@@ -9,6 +9,7 @@ import (
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -94,6 +95,29 @@ services:
 	}
 }
 
+func TestProjectConfigTerraformProvider(t *testing.T) {
+	const testProj = `
+name: test-proj
+infra:
+  provider: Terraform
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+    infra:
+      provider: terraform
+`
+
+	mockContext := mocks.NewMockContext(context.Background())
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	require.Equal(t, provisioning.Terraform, projectConfig.Infra.Provider)
+	require.Equal(t, "infra", projectConfig.Infra.Path)
+	require.Equal(t, provisioning.Terraform, projectConfig.Services["web"].Infra.Provider)
+}
+
 func TestProjectWithCustomDockerOptions(t *testing.T) {
 	const testProj = `
 name: test-proj
@@ -0,0 +1,164 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is returned when azure.yaml doesn't match the schema of the project file. It lists every problem
+// found in the file, rather than only the first one.
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+// ValidationProblem is a problem found when validating azure.yaml
+type ValidationProblem struct {
+	// The line of azure.yaml with the problem, or 0 when the line isn't known
+	Line    int
+	Message string
+}
+
+func (p ValidationProblem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("azure.yaml has %d problem(s):", len(e.Problems)))
+	for _, problem := range e.Problems {
+		sb.WriteString("\n  " + problem.String())
+	}
+
+	return sb.String()
+}
+
+var (
+	typeErrorLineRegex = regexp.MustCompile(`^line (\d+): (.*)$`)
+	unknownFieldRegex  = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+)
+
+// validateProject validates azure.yaml against the fields of ProjectConfig, which reports the fields that azd doesn't
+// know and the values that have the wrong type, and checks the hosts and languages of the services. It returns a
+// *ValidationError listing the problems, or nil when there are none.
+func validateProject(yamlContent []byte) error {
+	problems := []ValidationProblem{}
+
+	var projectConfig ProjectConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlContent))
+	decoder.KnownFields(true)
+
+	var typeErr *yaml.TypeError
+	if err := decoder.Decode(&projectConfig); errors.As(err, &typeErr) {
+		for _, msg := range typeErr.Errors {
+			problem := ValidationProblem{Message: msg}
+			if match := typeErrorLineRegex.FindStringSubmatch(msg); match != nil {
+				problem.Line, _ = strconv.Atoi(match[1])
+				problem.Message = match[2]
+			}
+
+			if match := unknownFieldRegex.FindStringSubmatch(problem.Message); match != nil {
+				problem.Message = fmt.Sprintf("unknown field '%s'", match[1])
+			}
+
+			problems = append(problems, problem)
+		}
+	} else if err != nil {
+		// syntax errors are reported when the project is parsed
+		return nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(yamlContent, &root); err != nil {
+		return nil
+	}
+
+	if services := mappingValue(documentNode(&root), "services"); services != nil && services.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(services.Content); i += 2 {
+			name := services.Content[i].Value
+			service := services.Content[i+1]
+
+			if host := mappingValue(service, "host"); isScalar(host) && host.Value != "" {
+				kind := ServiceTargetKind(host.Value)
+				if !isBuiltInServiceHost(kind) && !isPluginServiceHost(kind) {
+					problems = append(problems, ValidationProblem{
+						Line: host.Line,
+						Message: fmt.Sprintf("service '%s' has unsupported host '%s', the supported hosts are: %s",
+							name, host.Value, joinKinds(append(slices.Clone(serviceHosts), pluginServiceHosts()...))),
+					})
+				}
+			}
+
+			if language := mappingValue(service, "language"); isScalar(language) && language.Value != "" {
+				kind := ServiceLanguageKind(language.Value)
+				if !isBuiltInServiceLanguage(kind) && !isPluginServiceLanguage(kind) {
+					problems = append(problems, ValidationProblem{
+						Line: language.Line,
+						Message: fmt.Sprintf("service '%s' has unsupported language '%s', the supported languages are: %s",
+							name, language.Value, joinKinds(append(slices.Clone(serviceLanguages), pluginServiceLanguages()...))),
+					})
+				}
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(problems, func(a, b ValidationProblem) bool {
+		return a.Line < b.Line
+	})
+
+	return &ValidationError{Problems: problems}
+}
+
+// documentNode returns the content of a document node, or node when it isn't a document.
+func documentNode(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		return node.Content[0]
+	}
+
+	return node
+}
+
+// mappingValue returns the value of key in a mapping node, or nil when node isn't a mapping or doesn't have the key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func isScalar(node *yaml.Node) bool {
+	return node != nil && node.Kind == yaml.ScalarNode
+}
+
+func joinKinds[T ~string](kinds []T) string {
+	values := make([]string, len(kinds))
+	for i, kind := range kinds {
+		values[i] = string(kind)
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProject(t *testing.T) {
+	tests := []struct {
+		file     string
+		problems []string
+	}{
+		{
+			file: "unknown-fields.yaml",
+			problems: []string{
+				"line 2: unknown field 'servces'",
+				"line 11: unknown field 'dockr'",
+			},
+		},
+		{
+			file: "invalid-enums.yaml",
+			problems: []string{
+				"line 5: service 'api' has unsupported host 'containerap', the supported hosts are: " +
+					"appservice, containerapp, function, staticwebapp, springapp, aks, aci",
+				"line 10: service 'web' has unsupported language 'javascipt', the supported languages are: " +
+					"dotnet, csharp, fsharp, js, ts, python, java, go, rust, php, ruby",
+			},
+		},
+		{
+			file: "type-mismatch.yaml",
+			problems: []string{
+				"line 7: cannot unmarshal !!str `web` into []string",
+				"line 9: cannot unmarshal !!str `soon` into int",
+			},
+		},
+		{
+			file: "valid.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			yamlContent, err := os.ReadFile(filepath.Join("testdata", "validation", tt.file))
+			require.NoError(t, err)
+
+			_, err = Parse(context.Background(), string(yamlContent))
+			if tt.problems == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a validation error, got %v", err)
+
+			problems := []string{}
+			for _, problem := range validationErr.Problems {
+				problems = append(problems, problem.String())
+			}
+			require.Equal(t, tt.problems, problems)
+			require.True(t, strings.HasPrefix(err.Error(), "azure.yaml has"))
+		})
+	}
+}
@@ -23,16 +23,34 @@ type ServiceConfig struct {
 	Language ServiceLanguageKind `yaml:"language"`
 	// The output path for build artifacts
 	OutputPath string `yaml:"dist,omitempty"`
+	// Deprecated: the infrastructure module of the service, which is no longer used. It's kept so that azure.yaml files
+	// that set it still load.
+	Module string `yaml:"module,omitempty"`
+	// The names of the services that are deployed before this service
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// Environment variables set on the target resource when the service is deployed
+	Env map[string]ServiceEnvValue `yaml:"env,omitempty"`
 	// The optional docker options
 	Docker DockerProjectOptions `yaml:"docker,omitempty"`
 	// The optional K8S / AKS options
 	K8s AksOptions `yaml:"k8s,omitempty"`
 	// The optional Azure Spring Apps options
 	Spring SpringOptions `yaml:"spring,omitempty"`
+	// The optional Azure Container Instances options
+	Aci AciOptions `yaml:"aci,omitempty"`
+	// The optional Azure Container Apps options
+	ContainerApp ContainerAppOptions `yaml:"containerApp,omitempty"`
+	// The optional .NET publish options
+	DotNet DotNetOptions `yaml:"dotnet,omitempty"`
+	// The optional Azure Functions options
+	Functions FunctionsOptions `yaml:"functions,omitempty"`
 	// The infrastructure provisioning configuration
 	Infra provisioning.Options `yaml:"infra,omitempty"`
 	// Hook configuration for service
 	Hooks map[string]*ext.HookConfig `yaml:"hooks,omitempty"`
+	// Additional configuration used when importing services from the service, such as the `exposedServices` of an
+	// Aspire app host. Values set here take precedence over the `services.<name>.config` values of the environment.
+	Config map[string]any `yaml:"config,omitempty"`
 	// Options specific to the DotNetContainerApp target. These are set by the importer and
 	// can not be controlled via the project file today.
 	DotNetContainerApp *DotNetContainerAppOptions `yaml:"-,omitempty"`
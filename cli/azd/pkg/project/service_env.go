@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ServiceEnvValue is the value of an environment variable that is set on the target resource of a service when the
+// service is deployed. In azure.yaml, the value is either a string or an object with the 'value' and 'secret' properties.
+type ServiceEnvValue struct {
+	// The value of the environment variable, which can reference variables of the azd environment, ex) ${API_KEY}
+	Value ExpandableString `yaml:"value"`
+	// When true, the value is not logged and is stored as a secret on hosts that support secrets
+	Secret bool `yaml:"secret,omitempty"`
+}
+
+func (v ServiceEnvValue) MarshalYAML() (interface{}, error) {
+	if !v.Secret {
+		return v.Value, nil
+	}
+
+	type rawServiceEnvValue ServiceEnvValue
+	return rawServiceEnvValue(v), nil
+}
+
+func (v *ServiceEnvValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value ExpandableString
+	if err := unmarshal(&value); err == nil {
+		*v = ServiceEnvValue{Value: value}
+		return nil
+	}
+
+	type rawServiceEnvValue ServiceEnvValue
+	var raw rawServiceEnvValue
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	*v = ServiceEnvValue(raw)
+	return nil
+}
+
+// serviceEnvVar is an environment variable of a service, with its value expanded from the azd environment
+type serviceEnvVar struct {
+	Name   string
+	Value  string
+	Secret bool
+}
+
+// serviceEnv expands the environment variables declared in the env section of a service, sorted by name.
+// Variables that reference Key Vault secrets of the azd environment are secrets.
+func serviceEnv(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	env *environment.Environment,
+) ([]serviceEnvVar, error) {
+	names := maps.Keys(serviceConfig.Env)
+	slices.Sort(names)
+
+	envVars := make([]serviceEnvVar, 0, len(names))
+	for _, name := range names {
+		value, secret, err := expandResolved(ctx, serviceConfig.Env[name].Value, env)
+		if err != nil {
+			return nil, fmt.Errorf("expanding environment variable '%s' of service '%s': %w", name, serviceConfig.Name, err)
+		}
+
+		envVars = append(envVars, serviceEnvVar{
+			Name:   name,
+			Value:  value,
+			Secret: secret || serviceConfig.Env[name].Secret,
+		})
+	}
+
+	return envVars, nil
+}
+
+// expandResolved expands the references to the azd environment in value, resolving the environment values that are
+// Key Vault references. secret is true when a Key Vault reference was resolved.
+func expandResolved(
+	ctx context.Context,
+	value ExpandableString,
+	env *environment.Environment,
+) (expanded string, secret bool, err error) {
+	var resolveErr error
+	expanded, err = value.Envsubst(func(key string) string {
+		if _, isReference := environment.ParseKeyVaultReference(env.Getenv(key)); isReference {
+			secret = true
+		}
+
+		resolved, err := env.GetenvResolved(ctx, key)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return resolved
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	return expanded, secret, resolveErr
+}
+
+// validateServiceEnvSupported returns an error when the service declares environment variables, for service targets
+// that can't set environment variables on the target resource.
+func validateServiceEnvSupported(serviceConfig *ServiceConfig) error {
+	if len(serviceConfig.Env) > 0 {
+		return fmt.Errorf(
+			"service '%s' declares environment variables, which are not supported by host '%s'",
+			serviceConfig.Name,
+			serviceConfig.Host,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestServiceEnvValueYaml(t *testing.T) {
+	var env map[string]ServiceEnvValue
+
+	err := yaml.Unmarshal([]byte("LOG_LEVEL: debug\nAPI_KEY:\n  value: ${API_KEY}\n  secret: true\n"), &env)
+	require.NoError(t, err)
+
+	require.Equal(t, "debug", env["LOG_LEVEL"].Value.template)
+	require.False(t, env["LOG_LEVEL"].Secret)
+	require.Equal(t, "${API_KEY}", env["API_KEY"].Value.template)
+	require.True(t, env["API_KEY"].Secret)
+
+	marshalled, err := yaml.Marshal(env)
+	require.NoError(t, err)
+
+	require.Equal(t, "API_KEY:\n    value: ${API_KEY}\n    secret: true\nLOG_LEVEL: debug\n", string(marshalled))
+}
+
+func TestServiceEnv(t *testing.T) {
+	serviceConfig := &ServiceConfig{
+		Name: "api",
+		Env: map[string]ServiceEnvValue{
+			"LOG_LEVEL": {Value: NewExpandableString("debug")},
+			"API_KEY":   {Value: NewExpandableString("${API_KEY}"), Secret: true},
+		},
+	}
+	env := environment.NewWithValues("dev", map[string]string{
+		"API_KEY": "KEY_VALUE",
+	})
+
+	envVars, err := serviceEnv(context.Background(), serviceConfig, env)
+	require.NoError(t, err)
+	require.Equal(t, []serviceEnvVar{
+		{Name: "API_KEY", Value: "KEY_VALUE", Secret: true},
+		{Name: "LOG_LEVEL", Value: "debug"},
+	}, envVars)
+}
@@ -9,7 +9,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -19,6 +21,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 )
 
 const (
@@ -88,6 +91,18 @@ type ServiceManager interface {
 		packageOutput *ServicePackageResult,
 	) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress]
 
+	// Returns what Deploy would do for the specified service config, without changing anything
+	// The package output is nil when the service would be packaged before it's deployed
+	DeployPreview(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		packageOutput *ServicePackageResult,
+	) (*ServiceDeployPlan, error)
+
+	// Orders the specified services so that every service follows the services it depends on
+	// Returns an error naming the services of the cycle when services depend on each other
+	DeploymentOrder(services []*ServiceConfig) ([]*ServiceConfig, error)
+
 	// Gets the framework service for the specified service config
 	// The framework service performs the restoration and building of the service app code
 	GetFrameworkService(ctx context.Context, serviceConfig *ServiceConfig) (FrameworkService, error)
@@ -100,21 +115,25 @@ type ServiceManager interface {
 
 type serviceManager struct {
 	env                 *environment.Environment
+	envManager          environment.Manager
 	resourceManager     ResourceManager
 	serviceLocator      ioc.ServiceLocator
 	operationCache      map[string]any
+	operationCacheMu    sync.Mutex
 	alphaFeatureManager *alpha.FeatureManager
 }
 
 // NewServiceManager creates a new instance of the ServiceManager component
 func NewServiceManager(
 	env *environment.Environment,
+	envManager environment.Manager,
 	resourceManager ResourceManager,
 	serviceLocator ioc.ServiceLocator,
 	alphaFeatureManager *alpha.FeatureManager,
 ) ServiceManager {
 	return &serviceManager{
 		env:                 env,
+		envManager:          envManager,
 		resourceManager:     resourceManager,
 		serviceLocator:      serviceLocator,
 		operationCache:      map[string]any{},
@@ -138,6 +157,14 @@ func (sm *serviceManager) GetRequiredTools(ctx context.Context, serviceConfig *S
 	requiredTools = append(requiredTools, frameworkService.RequiredExternalTools(ctx)...)
 	requiredTools = append(requiredTools, serviceTarget.RequiredExternalTools(ctx)...)
 
+	if serviceConfig.Docker.RemoteBuild {
+		// Images are built in the container registry, without the local docker daemon
+		requiredTools = slices.DeleteFunc(requiredTools, func(tool tools.ExternalTool) bool {
+			_, isDocker := tool.(docker.Docker)
+			return isDocker
+		})
+	}
+
 	return tools.Unique(requiredTools), nil
 }
 
@@ -252,6 +279,7 @@ func (sm *serviceManager) Build(
 			return
 		}
 
+		sm.invalidateAppHostManifest(serviceConfig)
 		task.SetResult(buildResult)
 		sm.setOperationResult(ctx, serviceConfig, string(ServiceEventBuild), buildResult)
 	})
@@ -413,10 +441,32 @@ func (sm *serviceManager) Package(
 			packageResult.PackagePath = destFilePath
 		}
 
+		sm.invalidateAppHostManifest(serviceConfig)
 		task.SetResult(packageResult)
 	})
 }
 
+// invalidateAppHostManifest removes the cached manifest of the app host that the service was imported from when building
+// or packaging the service, including the hooks that run around it, changed the inputs of the app host. The manifest is
+// then generated again when it's next needed, such as to synthesize the infrastructure of the app.
+func (sm *serviceManager) invalidateAppHostManifest(serviceConfig *ServiceConfig) {
+	if serviceConfig.DotNetContainerApp == nil {
+		return
+	}
+
+	var importer *DotNetImporter
+	if err := sm.serviceLocator.Resolve(&importer); err != nil {
+		log.Printf("resolving the .NET importer, not checking the app host manifest: %v", err)
+		return
+	}
+
+	appHostPath := serviceConfig.DotNetContainerApp.ProjectPath
+	if importer.manifestStale(appHostPath) {
+		log.Printf("the inputs of the app host %s changed, invalidating its manifest", appHostPath)
+		importer.InvalidateManifestCache(appHostPath)
+	}
+}
+
 // Deploys the generated artifacts to the Azure resource that will host the service application
 // Common examples would be uploading zip archive using ZipDeploy deployment or
 // pushing container images to a container registry.
@@ -445,45 +495,10 @@ func (sm *serviceManager) Deploy(
 			return
 		}
 
-		var targetResource *environment.TargetResource
-
-		if serviceConfig.Host == DotNetContainerAppTarget {
-			containerEnvName := sm.env.GetServiceProperty(serviceConfig.Name, "CONTAINER_ENVIRONMENT_NAME")
-			if containerEnvName == "" {
-				containerEnvName = sm.env.Getenv("AZURE_CONTAINER_APPS_ENVIRONMENT_ID")
-				if containerEnvName == "" {
-					task.SetError(fmt.Errorf(
-						"could not determine container app environment for service %s, "+
-							"have you set AZURE_CONTAINER_ENVIRONMENT_NAME or "+
-							"SERVICE_%s_CONTAINER_ENVIRONMENT_NAME as an output of your "+
-							"infrastructure?", serviceConfig.Name, strings.ToUpper(serviceConfig.Name)))
-
-					return
-				}
-
-				parts := strings.Split(containerEnvName, "/")
-				containerEnvName = parts[len(parts)-1]
-			}
-
-			resourceGroupName, err := sm.resourceManager.GetResourceGroupName(
-				ctx, sm.env.GetSubscriptionId(), serviceConfig.Project)
-			if err != nil {
-				task.SetError(fmt.Errorf("getting resource group name: %w", err))
-				return
-			}
-
-			targetResource = environment.NewTargetResource(
-				sm.env.GetSubscriptionId(),
-				resourceGroupName,
-				containerEnvName,
-				string(infra.AzureResourceTypeContainerAppEnvironment),
-			)
-		} else {
-			targetResource, err = sm.resourceManager.GetTargetResource(ctx, sm.env.GetSubscriptionId(), serviceConfig)
-			if err != nil {
-				task.SetError(fmt.Errorf("getting target resource: %w", err))
-				return
-			}
+		targetResource, err := sm.targetResource(ctx, serviceConfig)
+		if err != nil {
+			task.SetError(err)
+			return
 		}
 
 		deployResult, err := runCommand(
@@ -508,11 +523,141 @@ func (sm *serviceManager) Deploy(
 			deployResult.Endpoints = overriddenEndpoints
 		}
 
+		// Make the endpoint of the service available to the services that depend on it, ex) SERVICE_API_ENDPOINT_URL.
+		// The value is overwritten on each deployment, since the endpoint can change between deployments.
+		if len(deployResult.Endpoints) > 0 {
+			sm.env.SetServiceProperty(serviceConfig.Name, "ENDPOINT_URL", deployResult.Endpoints[0])
+			if err := sm.envManager.Save(ctx, sm.env); err != nil {
+				task.SetError(fmt.Errorf("saving endpoint of service '%s': %w", serviceConfig.Name, err))
+				return
+			}
+		}
+
 		task.SetResult(deployResult)
 		sm.setOperationResult(ctx, serviceConfig, string(ServiceEventDeploy), deployResult)
 	})
 }
 
+// DeployPreview returns what Deploy would do for the service with the package, without changing anything. The package is
+// nil when the service would be packaged before it's deployed.
+func (sm *serviceManager) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) (*ServiceDeployPlan, error) {
+	serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting service target: %w", err)
+	}
+
+	targetResource, err := sm.targetResource(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := serviceTarget.DeployPreview(ctx, serviceConfig, packageOutput, targetResource)
+	if err != nil {
+		return nil, fmt.Errorf("previewing deployment of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	return plan, nil
+}
+
+// targetResource returns the Azure resource the service is deployed to. Services hosted as .NET container apps are
+// deployed to the container apps environment.
+func (sm *serviceManager) targetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) (*environment.TargetResource, error) {
+	if serviceConfig.Host != DotNetContainerAppTarget {
+		targetResource, err := sm.resourceManager.GetTargetResource(ctx, sm.env.GetSubscriptionId(), serviceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("getting target resource: %w", err)
+		}
+
+		return targetResource, nil
+	}
+
+	containerEnvName := sm.env.GetServiceProperty(serviceConfig.Name, "CONTAINER_ENVIRONMENT_NAME")
+	if containerEnvName == "" {
+		containerEnvName = sm.env.Getenv("AZURE_CONTAINER_APPS_ENVIRONMENT_ID")
+		if containerEnvName == "" {
+			return nil, fmt.Errorf(
+				"could not determine container app environment for service %s, "+
+					"have you set AZURE_CONTAINER_ENVIRONMENT_NAME or "+
+					"SERVICE_%s_CONTAINER_ENVIRONMENT_NAME as an output of your "+
+					"infrastructure?", serviceConfig.Name, strings.ToUpper(serviceConfig.Name))
+		}
+
+		parts := strings.Split(containerEnvName, "/")
+		containerEnvName = parts[len(parts)-1]
+	}
+
+	resourceGroupName, err := sm.resourceManager.GetResourceGroupName(
+		ctx, sm.env.GetSubscriptionId(), serviceConfig.Project)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource group name: %w", err)
+	}
+
+	return environment.NewTargetResource(
+		sm.env.GetSubscriptionId(),
+		resourceGroupName,
+		containerEnvName,
+		string(infra.AzureResourceTypeContainerAppEnvironment),
+	), nil
+}
+
+// Orders the specified services so that every service follows the services it depends on
+// Services without dependencies between them keep their relative order
+func (sm *serviceManager) DeploymentOrder(services []*ServiceConfig) ([]*ServiceConfig, error) {
+	servicesByName := map[string]*ServiceConfig{}
+	for _, serviceConfig := range services {
+		servicesByName[serviceConfig.Name] = serviceConfig
+	}
+
+	ordered := make([]*ServiceConfig, 0, len(services))
+	visited := map[string]bool{}
+	// The services currently being visited, in the order they were visited
+	path := []string{}
+
+	var visit func(serviceConfig *ServiceConfig) error
+	visit = func(serviceConfig *ServiceConfig) error {
+		if visited[serviceConfig.Name] {
+			return nil
+		}
+
+		if i := slices.Index(path, serviceConfig.Name); i >= 0 {
+			cycle := append(slices.Clone(path[i:]), serviceConfig.Name)
+			return fmt.Errorf("service dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		path = append(path, serviceConfig.Name)
+		for _, dependency := range serviceConfig.DependsOn {
+			dependencyConfig, has := servicesByName[dependency]
+			if !has {
+				return fmt.Errorf("service '%s' depends on unknown service '%s'", serviceConfig.Name, dependency)
+			}
+
+			if err := visit(dependencyConfig); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+
+		visited[serviceConfig.Name] = true
+		ordered = append(ordered, serviceConfig)
+		return nil
+	}
+
+	for _, serviceConfig := range services {
+		if err := visit(serviceConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
 // GetServiceTarget constructs a ServiceTarget from the underlying service configuration
 func (sm *serviceManager) GetServiceTarget(ctx context.Context, serviceConfig *ServiceConfig) (ServiceTarget, error) {
 	var target ServiceTarget
@@ -555,7 +700,7 @@ func (sm *serviceManager) GetFrameworkService(ctx context.Context, serviceConfig
 	}
 
 	// For containerized applications we use a composite framework service
-	if serviceConfig.Host == ContainerAppTarget || serviceConfig.Host == AksTarget {
+	if serviceConfig.Host == ContainerAppTarget || serviceConfig.Host == AksTarget || serviceConfig.Host == AciTarget {
 		var compositeFramework CompositeFrameworkService
 		if err := sm.serviceLocator.ResolveNamed(string(ServiceLanguageDocker), &compositeFramework); err != nil {
 			panic(fmt.Errorf(
@@ -600,6 +745,9 @@ func (sm *serviceManager) getOperationResult(
 	serviceConfig *ServiceConfig,
 	operationName string,
 ) (any, bool) {
+	sm.operationCacheMu.Lock()
+	defer sm.operationCacheMu.Unlock()
+
 	key := fmt.Sprintf("%s:%s", serviceConfig.Name, operationName)
 	value, ok := sm.operationCache[key]
 
@@ -613,6 +761,9 @@ func (sm *serviceManager) setOperationResult(
 	operationName string,
 	result any,
 ) {
+	sm.operationCacheMu.Lock()
+	defer sm.operationCacheMu.Unlock()
+
 	key := fmt.Sprintf("%s:%s", serviceConfig.Name, operationName)
 	sm.operationCache[key] = result
 }
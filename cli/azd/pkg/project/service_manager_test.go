@@ -3,11 +3,15 @@ package project
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/apphost"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -17,10 +21,13 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -38,6 +45,15 @@ const (
 )
 
 func createServiceManager(mockContext *mocks.MockContext, env *environment.Environment) ServiceManager {
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, env).Return(nil)
+
+	return createServiceManagerWithEnvManager(mockContext, env, envManager)
+}
+
+func createServiceManagerWithEnvManager(
+	mockContext *mocks.MockContext, env *environment.Environment, envManager environment.Manager,
+) ServiceManager {
 	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
 	depOpService := mockazcli.NewDeploymentOperationsServiceFromMockContext(mockContext)
 	resourceManager := NewResourceManager(env, azCli, depOpService)
@@ -50,7 +66,7 @@ func createServiceManager(mockContext *mocks.MockContext, env *environment.Envir
 			},
 		}))
 
-	return NewServiceManager(env, resourceManager, serviceLocator, alphaManager)
+	return NewServiceManager(env, envManager, resourceManager, serviceLocator, alphaManager)
 }
 
 func Test_ServiceManager_GetRequiredTools(t *testing.T) {
@@ -145,6 +161,65 @@ func Test_ServiceManager_Build(t *testing.T) {
 	require.True(t, raisedPostBuildEvent)
 }
 
+// Verify that building a service imported from an app host invalidates the cached manifest of the app host when the
+// build changes its inputs.
+func Test_ServiceManager_Build_InvalidatesAppHostManifest(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+
+	appHostFile := filepath.Join(t.TempDir(), "AppHost.csproj")
+	err := os.WriteFile(appHostFile, nil, osutil.PermissionFile)
+	require.NoError(t, err)
+
+	importer := NewDotNetImporter(nil, mockContext.Console, nil, nil)
+	mockContext.Container.RegisterSingleton(func() *DotNetImporter { return importer })
+
+	cacheManifest := func() {
+		importer.cache[appHostFile] = manifestCacheEntry{
+			manifest:    &apphost.Manifest{},
+			generatedAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	env := environment.New("test")
+	sm := createServiceManager(mockContext, env)
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.DotNetContainerApp = &DotNetContainerAppOptions{
+		Manifest:    &apphost.Manifest{},
+		ProjectName: "api",
+		ProjectPath: appHostFile,
+	}
+
+	editAppHost := false
+	_ = serviceConfig.AddHandler("prebuild", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
+		if editAppHost {
+			future := time.Now().Add(2 * time.Hour)
+			return os.Chtimes(appHostFile, future, future)
+		}
+		return nil
+	})
+
+	build := func() {
+		ctx := context.WithValue(*mockContext.Context, frameworkBuildCalled, convert.RefOf(false))
+		buildTask := sm.Build(ctx, serviceConfig, nil)
+		logProgress(buildTask)
+
+		_, err := buildTask.Await()
+		require.NoError(t, err)
+	}
+
+	// the manifest is kept when the inputs of the app host don't change
+	cacheManifest()
+	build()
+	require.Contains(t, importer.cache, appHostFile)
+
+	// builds are cached for the service manager, so a new one builds the service again
+	sm = createServiceManager(mockContext, env)
+	editAppHost = true
+	build()
+	require.NotContains(t, importer.cache, appHostFile)
+}
+
 func Test_ServiceManager_Package(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
@@ -187,8 +262,12 @@ func Test_ServiceManager_Deploy(t *testing.T) {
 	setupMocksForServiceManager(mockContext)
 	env := environment.NewWithValues("test", map[string]string{
 		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		// the endpoint of a previous deployment
+		"SERVICE_API_ENDPOINT_URL": "https://previous.azurewebsites.net",
 	})
-	sm := createServiceManager(mockContext, env)
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, env).Return(nil)
+	sm := createServiceManagerWithEnvManager(mockContext, env, envManager)
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	raisedPreDeployEvent := false
@@ -216,6 +295,92 @@ func Test_ServiceManager_Deploy(t *testing.T) {
 	require.True(t, *deployCalled)
 	require.True(t, raisedPreDeployEvent)
 	require.True(t, raisedPostDeployEvent)
+
+	// the endpoint of the deployment replaces the previous one, and is saved to the environment
+	require.Equal(t, "https://test.azurewebsites.net", env.Getenv("SERVICE_API_ENDPOINT_URL"))
+	envManager.AssertCalled(t, "Save", mock.Anything, env)
+}
+
+func Test_ServiceManager_DeployPreview(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	env := environment.NewWithValues("test", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	sm := createServiceManager(mockContext, env)
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+
+	raisedPreDeployEvent := false
+	_ = serviceConfig.AddHandler("predeploy", func(ctx context.Context, args ServiceLifecycleEventArgs) error {
+		raisedPreDeployEvent = true
+		return nil
+	})
+
+	deployCalled := convert.RefOf(false)
+	ctx := context.WithValue(*mockContext.Context, serviceTargetDeployCalled, deployCalled)
+
+	plan, err := sm.DeployPreview(ctx, serviceConfig, nil)
+	require.NoError(t, err)
+	require.Equal(t, []ServiceDeployStep{
+		{Kind: DeployStepPackage, Description: "Package the service"},
+	}, plan.Steps)
+	require.False(t, *deployCalled)
+	require.False(t, raisedPreDeployEvent)
+}
+
+func Test_ServiceManager_DeploymentOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		dependsOn map[string][]string
+		expected  []string
+		errorText string
+	}{
+		{
+			name:      "NoDependencies",
+			dependsOn: map[string][]string{"api": nil, "web": nil, "worker": nil},
+			expected:  []string{"api", "web", "worker"},
+		},
+		{
+			name:      "DependencyAfterDependent",
+			dependsOn: map[string][]string{"api": {"worker"}, "web": {"api"}, "worker": nil},
+			expected:  []string{"worker", "api", "web"},
+		},
+		{
+			name:      "Cycle",
+			dependsOn: map[string][]string{"api": {"worker"}, "web": nil, "worker": {"api"}},
+			errorText: "service dependency cycle detected: api -> worker -> api",
+		},
+		{
+			name:      "UnknownDependency",
+			dependsOn: map[string][]string{"api": nil, "web": {"apii"}, "worker": nil},
+			errorText: "service 'web' depends on unknown service 'apii'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			sm := createServiceManager(mockContext, environment.New("test"))
+
+			services := []*ServiceConfig{}
+			for _, name := range []string{"api", "web", "worker"} {
+				services = append(services, &ServiceConfig{Name: name, DependsOn: tt.dependsOn[name]})
+			}
+
+			ordered, err := sm.DeploymentOrder(services)
+			if tt.errorText != "" {
+				require.EqualError(t, err, tt.errorText)
+				return
+			}
+
+			require.NoError(t, err)
+			names := []string{}
+			for _, serviceConfig := range ordered {
+				names = append(names, serviceConfig.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
 }
 
 func Test_ServiceManager_GetFrameworkService(t *testing.T) {
@@ -566,12 +731,25 @@ func (st *fakeServiceTarget) Deploy(
 		}
 
 		task.SetResult(&ServiceDeployResult{
-			Package: packageOutput,
-			Details: result,
+			Package:   packageOutput,
+			Details:   result,
+			Endpoints: []string{"https://test.azurewebsites.net"},
 		})
 	})
 }
 
+func (st *fakeServiceTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	return &ServiceDeployPlan{
+		TargetResourceId: targetResource.ResourceName(),
+		Steps:            []ServiceDeployStep{packageDeployStep(packageOutput)},
+	}, nil
+}
+
 func (st *fakeServiceTarget) Endpoints(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -102,12 +102,17 @@ func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
 		return uxItem.ToString(currentIndentation)
 	}
 
+	return endpointsString(currentIndentation, spr.Endpoints)
+}
+
+// endpointsString lists the endpoints of a deployed service for the deploy summary
+func endpointsString(currentIndentation string, endpoints []string) string {
 	builder := strings.Builder{}
 
-	if len(spr.Endpoints) == 0 {
+	if len(endpoints) == 0 {
 		builder.WriteString(fmt.Sprintf("%s- No endpoints were found\n", currentIndentation))
 	} else {
-		for _, endpoint := range spr.Endpoints {
+		for _, endpoint := range endpoints {
 			builder.WriteString(fmt.Sprintf("%s- Endpoint: %s\n", currentIndentation, output.WithLinkFormat(endpoint)))
 		}
 	}
@@ -118,3 +123,82 @@ func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
 func (spr *ServiceDeployResult) MarshalJSON() ([]byte, error) {
 	return json.Marshal(*spr)
 }
+
+// Kinds of the steps of a ServiceDeployPlan
+const (
+	// Packages the service, as azd package does
+	DeployStepPackage = "package"
+	// Builds the container image of the service
+	DeployStepBuild = "build"
+	// Pushes the container image of the service to a registry
+	DeployStepPush = "push"
+	// Updates the Azure resource that hosts the service
+	DeployStepUpdate = "update"
+)
+
+// ServiceDeployStep is a step of a ServiceDeployPlan
+type ServiceDeployStep struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+// ServiceDeployImage compares the container image the service runs with the image it would be deployed with. The ids
+// are those of the images in the local image store, empty when the image isn't in the store.
+type ServiceDeployImage struct {
+	// The image the service was last deployed with
+	Current   string `json:"current,omitempty"`
+	CurrentId string `json:"currentId,omitempty"`
+	// The image the service would be deployed with. When the tag of the image is resolved from the time of the
+	// deployment, it's the time of the plan.
+	Planned   string `json:"planned"`
+	PlannedId string `json:"plannedId,omitempty"`
+}
+
+// ServiceDeployPlan is what deploying a service would do, computed without changing anything.
+type ServiceDeployPlan struct {
+	// Related Azure resource ID
+	TargetResourceId string              `json:"targetResourceId"`
+	Kind             ServiceTargetKind   `json:"kind"`
+	Steps            []ServiceDeployStep `json:"steps"`
+	Image            *ServiceDeployImage `json:"image,omitempty"`
+}
+
+// Supports rendering messages for UX items
+func (sdp *ServiceDeployPlan) ToString(currentIndentation string) string {
+	lines := []string{}
+	if sdp.TargetResourceId != "" {
+		lines = append(lines,
+			fmt.Sprintf("%s- Target: %s", currentIndentation, output.WithLinkFormat(sdp.TargetResourceId)))
+	}
+
+	for _, step := range sdp.Steps {
+		lines = append(lines, fmt.Sprintf("%s- %s", currentIndentation, step.Description))
+	}
+
+	if image := sdp.Image; image != nil {
+		if image.Current != "" {
+			current := output.WithLinkFormat(image.Current)
+			if image.CurrentId != "" {
+				current += output.WithGrayFormat(" (%s)", image.CurrentId)
+			}
+			lines = append(lines, fmt.Sprintf("%s- Current image: %s", currentIndentation, current))
+		}
+
+		// whether the image changes is only known when the ids of both images are known
+		if image.CurrentId != "" && image.PlannedId != "" {
+			if image.CurrentId != image.PlannedId {
+				lines = append(lines, fmt.Sprintf("%s- Image changes: %s", currentIndentation,
+					output.WithWarningFormat("the image differs from the current image")))
+			} else {
+				lines = append(lines, fmt.Sprintf("%s- Image changes: none, the image is the current image",
+					currentIndentation))
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (sdp *ServiceDeployPlan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*sdp)
+}
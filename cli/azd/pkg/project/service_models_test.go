@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,3 +37,38 @@ func Test_ServiceResults_Json_Marshal(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, string(jsonBytes))
 }
+
+func Test_ServiceDeployPlan_ToString(t *testing.T) {
+	originalNoColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() {
+		color.NoColor = originalNoColor
+	})
+
+	plan := &ServiceDeployPlan{
+		TargetResourceId: "target-resource-id",
+		Kind:             ContainerAppTarget,
+		Steps: []ServiceDeployStep{
+			{Kind: DeployStepBuild, Description: "Build the image from ./Dockerfile"},
+			{Kind: DeployStepPush, Description: "Push the image contoso.azurecr.io/api:azd-deploy-1"},
+		},
+		Image: &ServiceDeployImage{
+			Current:   "contoso.azurecr.io/api:azd-deploy-0",
+			CurrentId: "sha256:1234",
+			Planned:   "contoso.azurecr.io/api:azd-deploy-1",
+		},
+	}
+
+	require.Equal(t,
+		"  - Target: target-resource-id\n"+
+			"  - Build the image from ./Dockerfile\n"+
+			"  - Push the image contoso.azurecr.io/api:azd-deploy-1\n"+
+			"  - Current image: contoso.azurecr.io/api:azd-deploy-0 (sha256:1234)",
+		plan.ToString("  "))
+
+	plan.Image.PlannedId = "sha256:1234"
+	require.Contains(t, plan.ToString("  "), "  - Image changes: none, the image is the current image")
+
+	plan.Image.PlannedId = "sha256:5678"
+	require.Contains(t, plan.ToString("  "), "  - Image changes: the image differs from the current image")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ServicePlugin provides service targets and framework services that aren't built into azd, so that services in
+// azure.yaml can set their host and language to them.
+//
+// Out-of-tree packages register their plugins with RegisterServicePlugin, typically from an init function, and are
+// imported for their side effects by the azd binary they're built into.
+type ServicePlugin interface {
+	// Name identifies the plugin in errors.
+	Name() string
+
+	// ServiceTargets returns the constructors of the service targets provided by the plugin, by host. The constructors
+	// are registered in the container like the built-in service targets, and can take any of its services as arguments.
+	ServiceTargets() map[ServiceTargetKind]any
+
+	// FrameworkServices returns the constructors of the framework services provided by the plugin, by language.
+	FrameworkServices() map[ServiceLanguageKind]any
+}
+
+var (
+	servicePluginsMu sync.RWMutex
+	servicePlugins   []ServicePlugin
+	// pluginHosts and pluginLanguages map the hosts and languages provided by plugins to the plugin providing them.
+	pluginHosts     = map[ServiceTargetKind]ServicePlugin{}
+	pluginLanguages = map[ServiceLanguageKind]ServicePlugin{}
+)
+
+// RegisterServicePlugin makes the service targets and framework services of plugin available to services in azure.yaml.
+// It panics when plugin provides a host or language that is built into azd or provided by another plugin, since they
+// can't be replaced.
+func RegisterServicePlugin(plugin ServicePlugin) {
+	servicePluginsMu.Lock()
+	defer servicePluginsMu.Unlock()
+
+	for host := range plugin.ServiceTargets() {
+		if other, has := pluginHosts[host]; has {
+			panic(fmt.Errorf("registering service plugin %s: host '%s' is provided by plugin %s", plugin.Name(), host,
+				other.Name()))
+		}
+
+		if host == "" || host == DotNetContainerAppTarget || isBuiltInServiceHost(host) {
+			panic(fmt.Errorf("registering service plugin %s: host '%s' is built into azd", plugin.Name(), host))
+		}
+	}
+
+	for language := range plugin.FrameworkServices() {
+		if other, has := pluginLanguages[language]; has {
+			panic(fmt.Errorf("registering service plugin %s: language '%s' is provided by plugin %s", plugin.Name(),
+				language, other.Name()))
+		}
+
+		if language == "" || language == ServiceLanguageDocker || isBuiltInServiceLanguage(language) {
+			panic(fmt.Errorf("registering service plugin %s: language '%s' is built into azd", plugin.Name(), language))
+		}
+	}
+
+	for host := range plugin.ServiceTargets() {
+		pluginHosts[host] = plugin
+	}
+
+	for language := range plugin.FrameworkServices() {
+		pluginLanguages[language] = plugin
+	}
+
+	servicePlugins = append(servicePlugins, plugin)
+}
+
+// ServicePlugins returns the registered service plugins, in the order they were registered.
+func ServicePlugins() []ServicePlugin {
+	servicePluginsMu.RLock()
+	defer servicePluginsMu.RUnlock()
+
+	return append([]ServicePlugin{}, servicePlugins...)
+}
+
+// isPluginServiceHost returns whether host is provided by a registered service plugin.
+func isPluginServiceHost(host ServiceTargetKind) bool {
+	servicePluginsMu.RLock()
+	defer servicePluginsMu.RUnlock()
+
+	_, has := pluginHosts[host]
+	return has
+}
+
+// isPluginServiceLanguage returns whether language is provided by a registered service plugin.
+func isPluginServiceLanguage(language ServiceLanguageKind) bool {
+	servicePluginsMu.RLock()
+	defer servicePluginsMu.RUnlock()
+
+	_, has := pluginLanguages[language]
+	return has
+}
+
+// pluginServiceHosts returns the sorted hosts provided by the registered service plugins.
+func pluginServiceHosts() []ServiceTargetKind {
+	servicePluginsMu.RLock()
+	defer servicePluginsMu.RUnlock()
+
+	hosts := maps.Keys(pluginHosts)
+	slices.Sort(hosts)
+	return hosts
+}
+
+// pluginServiceLanguages returns the sorted languages provided by the registered service plugins.
+func pluginServiceLanguages() []ServiceLanguageKind {
+	servicePluginsMu.RLock()
+	defer servicePluginsMu.RUnlock()
+
+	languages := maps.Keys(pluginLanguages)
+	slices.Sort(languages)
+	return languages
+}
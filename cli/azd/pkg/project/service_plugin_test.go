@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testServicePlugin struct {
+	name              string
+	serviceTargets    map[ServiceTargetKind]any
+	frameworkServices map[ServiceLanguageKind]any
+}
+
+func (p *testServicePlugin) Name() string {
+	return p.name
+}
+
+func (p *testServicePlugin) ServiceTargets() map[ServiceTargetKind]any {
+	return p.serviceTargets
+}
+
+func (p *testServicePlugin) FrameworkServices() map[ServiceLanguageKind]any {
+	return p.frameworkServices
+}
+
+// resetServicePlugins restores the registered service plugins when the test completes.
+func resetServicePlugins(t *testing.T) {
+	plugins := servicePlugins
+	hosts := pluginHosts
+	languages := pluginLanguages
+
+	servicePlugins = nil
+	pluginHosts = map[ServiceTargetKind]ServicePlugin{}
+	pluginLanguages = map[ServiceLanguageKind]ServicePlugin{}
+
+	t.Cleanup(func() {
+		servicePlugins = plugins
+		pluginHosts = hosts
+		pluginLanguages = languages
+	})
+}
+
+func Test_RegisterServicePlugin(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: fake-framework
+    host: fake-service-target
+`
+
+	t.Run("ResolvesPluginHostAndLanguage", func(t *testing.T) {
+		resetServicePlugins(t)
+
+		_, err := Parse(context.Background(), testProj)
+		require.ErrorContains(t, err, "unsupported language 'fake-framework'")
+
+		plugin := &testServicePlugin{
+			name:              "fake",
+			serviceTargets:    map[ServiceTargetKind]any{ServiceTargetFake: newFakeServiceTarget},
+			frameworkServices: map[ServiceLanguageKind]any{ServiceLanguageFake: newFakeFramework},
+		}
+		RegisterServicePlugin(plugin)
+		require.Equal(t, []ServicePlugin{plugin}, ServicePlugins())
+
+		projectConfig, err := Parse(context.Background(), testProj)
+		require.NoError(t, err)
+		require.Equal(t, ServiceTargetFake, projectConfig.Services["api"].Host)
+		require.Equal(t, ServiceLanguageFake, projectConfig.Services["api"].Language)
+	})
+
+	t.Run("ListsPluginHostsWhenUnsupported", func(t *testing.T) {
+		resetServicePlugins(t)
+
+		RegisterServicePlugin(&testServicePlugin{
+			name:           "fake",
+			serviceTargets: map[ServiceTargetKind]any{ServiceTargetFake: newFakeServiceTarget},
+		})
+
+		_, err := Parse(context.Background(), `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: fake-target
+`)
+		require.ErrorContains(t, err, "service 'api' has unsupported host 'fake-target', the supported hosts are: "+
+			"appservice, containerapp, function, staticwebapp, springapp, aks, aci, fake-service-target")
+	})
+
+	t.Run("BuiltInHost", func(t *testing.T) {
+		resetServicePlugins(t)
+
+		require.PanicsWithError(t, "registering service plugin fake: host 'containerapp' is built into azd", func() {
+			RegisterServicePlugin(&testServicePlugin{
+				name:           "fake",
+				serviceTargets: map[ServiceTargetKind]any{ContainerAppTarget: newFakeServiceTarget},
+			})
+		})
+		require.Empty(t, ServicePlugins())
+	})
+
+	t.Run("BuiltInLanguage", func(t *testing.T) {
+		resetServicePlugins(t)
+
+		require.PanicsWithError(t, "registering service plugin fake: language 'py' is built into azd", func() {
+			RegisterServicePlugin(&testServicePlugin{
+				name:              "fake",
+				frameworkServices: map[ServiceLanguageKind]any{"py": newFakeFramework},
+			})
+		})
+		require.Empty(t, ServicePlugins())
+	})
+
+	t.Run("ProvidedByAnotherPlugin", func(t *testing.T) {
+		resetServicePlugins(t)
+
+		RegisterServicePlugin(&testServicePlugin{
+			name:           "fake",
+			serviceTargets: map[ServiceTargetKind]any{ServiceTargetFake: newFakeServiceTarget},
+		})
+
+		require.PanicsWithError(t,
+			"registering service plugin other: host 'fake-service-target' is provided by plugin fake", func() {
+				RegisterServicePlugin(&testServicePlugin{
+					name:           "other",
+					serviceTargets: map[ServiceTargetKind]any{ServiceTargetFake: newFakeServiceTarget},
+				})
+			})
+		require.Len(t, ServicePlugins(), 1)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"golang.org/x/exp/slices"
 )
 
 type ServiceTargetKind string
@@ -24,28 +25,38 @@ const (
 	StaticWebAppTarget       ServiceTargetKind = "staticwebapp"
 	SpringAppTarget          ServiceTargetKind = "springapp"
 	AksTarget                ServiceTargetKind = "aks"
+	AciTarget                ServiceTargetKind = "aci"
 	DotNetContainerAppTarget ServiceTargetKind = "containerapp-dotnet"
 )
 
-func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
-	switch kind {
-
-	// NOTE: We do not support DotNetContainerAppTarget as a listed service host type in azure.yaml, hence
-	// it not include in this switch statement. We should think about if we should support this in azure.yaml because
-	// presently it's the only service target that is tied to a language.
-	case AppServiceTarget,
-		ContainerAppTarget,
-		AzureFunctionTarget,
-		StaticWebAppTarget,
-		SpringAppTarget,
-		AksTarget:
+// serviceHosts are the hosts that services can set in azure.yaml.
+//
+// NOTE: We do not support DotNetContainerAppTarget as a listed service host type in azure.yaml, hence
+// it not include in this list. We should think about if we should support this in azure.yaml because
+// presently it's the only service target that is tied to a language.
+var serviceHosts = []ServiceTargetKind{
+	AppServiceTarget,
+	ContainerAppTarget,
+	AzureFunctionTarget,
+	StaticWebAppTarget,
+	SpringAppTarget,
+	AksTarget,
+	AciTarget,
+}
 
+func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
+	if isBuiltInServiceHost(kind) || isPluginServiceHost(kind) {
 		return kind, nil
 	}
 
 	return ServiceTargetKind(""), fmt.Errorf("unsupported host '%s'", kind)
 }
 
+// isBuiltInServiceHost returns whether kind is a host built into azd that services can set in azure.yaml.
+func isBuiltInServiceHost(kind ServiceTargetKind) bool {
+	return slices.Contains(serviceHosts, kind)
+}
+
 type ServiceTarget interface {
 	// Initializes the service target for the specified service configuration.
 	// This allows service targets to opt-in to service lifecycle events
@@ -70,6 +81,15 @@ type ServiceTarget interface {
 		targetResource *environment.TargetResource,
 	) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress]
 
+	// DeployPreview returns what Deploy would do, without changing anything or pushing images. The package is nil when
+	// the service would be packaged before it's deployed.
+	DeployPreview(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		servicePackage *ServicePackageResult,
+		targetResource *environment.TargetResource,
+	) (*ServiceDeployPlan, error)
+
 	// Endpoints gets the endpoints a service exposes.
 	Endpoints(
 		ctx context.Context,
@@ -137,3 +157,16 @@ func checkResourceType(resource *environment.TargetResource, expectedResourceTyp
 
 	return nil
 }
+
+// packageDeployStep returns the step of a deploy plan that packages the service, or that deploys the existing package
+// when the package isn't nil.
+func packageDeployStep(servicePackage *ServicePackageResult) ServiceDeployStep {
+	if servicePackage == nil {
+		return ServiceDeployStep{Kind: DeployStepPackage, Description: "Package the service"}
+	}
+
+	return ServiceDeployStep{
+		Kind:        DeployStepPackage,
+		Description: fmt.Sprintf("Deploy the existing package %s", servicePackage.PackagePath),
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerinstances"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The Azure Container Instances configuration options
+type AciOptions struct {
+	// Environment variables set on the container. Values can reference environment variables of the azd environment.
+	Env map[string]ExpandableString `yaml:"env,omitempty"`
+	// The restart policy of the container group, one of Always, OnFailure or Never.
+	// When omitted, the restart policy of the provisioned container group is kept.
+	RestartPolicy string `yaml:"restartPolicy,omitempty"`
+}
+
+type aciTarget struct {
+	env                      *environment.Environment
+	containerHelper          *ContainerHelper
+	containerInstanceService containerinstances.ContainerInstanceService
+
+	// the interval between checks of the container group state after the container group is updated
+	pollInterval time.Duration
+}
+
+// NewAciTarget creates the Azure Container Instances service target.
+func NewAciTarget(
+	env *environment.Environment,
+	containerHelper *ContainerHelper,
+	containerInstanceService containerinstances.ContainerInstanceService,
+) ServiceTarget {
+	return &aciTarget{
+		env:                      env,
+		containerHelper:          containerHelper,
+		containerInstanceService: containerInstanceService,
+		pollInterval:             5 * time.Second,
+	}
+}
+
+// Gets the required external tools
+func (at *aciTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalTool {
+	return at.containerHelper.RequiredExternalTools(ctx)
+}
+
+// Initializes the Container Instances target
+func (at *aciTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if _, err := aciRestartPolicy(serviceConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Prepares and tags the container image from the build output based on the specified service configuration
+func (at *aciTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(packageOutput)
+		},
+	)
+}
+
+// Deploys service container images to ACR and updates the container group to run the new image.
+func (at *aciTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+				task.SetError(fmt.Errorf("validating target resource: %w", err))
+				return
+			}
+
+			restartPolicy, err := aciRestartPolicy(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			serviceEnvVars, err := serviceEnv(ctx, serviceConfig, at.env)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			envVars := map[string]string{}
+			secureEnvVars := map[string]string{}
+			for _, envVar := range serviceEnvVars {
+				if envVar.Secret {
+					secureEnvVars[envVar.Name] = envVar.Value
+				} else {
+					envVars[envVar.Name] = envVar.Value
+				}
+			}
+
+			// the Container Instances specific environment variables take precedence
+			for name, value := range serviceConfig.Aci.Env {
+				expanded, secret, err := expandResolved(ctx, value, at.env)
+				if err != nil {
+					task.SetError(fmt.Errorf("expanding environment variable '%s': %w", name, err))
+					return
+				}
+
+				delete(secureEnvVars, name)
+				delete(envVars, name)
+				if secret {
+					secureEnvVars[name] = expanded
+				} else {
+					envVars[name] = expanded
+				}
+			}
+
+			// Login, tag & push container image to ACR
+			containerDeployTask := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource)
+			syncProgress(task, containerDeployTask.Progress())
+
+			_, err = containerDeployTask.Await()
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			registryCredentials, err := at.registryCredentials(ctx, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			imageName := at.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
+			task.SetProgress(NewServiceProgress("Updating container group"))
+			err = at.containerInstanceService.UpdateContainerGroup(
+				ctx,
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+				containerinstances.ContainerGroupUpdate{
+					Image:               imageName,
+					Env:                 envVars,
+					SecureEnv:           secureEnvVars,
+					RestartPolicy:       restartPolicy,
+					RegistryCredentials: registryCredentials,
+				},
+			)
+			if err != nil {
+				task.SetError(fmt.Errorf("updating container group: %w", err))
+				return
+			}
+
+			if err := at.waitForContainerGroup(ctx, task, targetResource); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Fetching endpoints for container group"))
+			endpoints, err := at.Endpoints(ctx, serviceConfig, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceDeployResult{
+				Package: packageOutput,
+				TargetResourceId: azure.ContainerGroupRID(
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+				),
+				Kind:      AciTarget,
+				Endpoints: endpoints,
+			})
+		},
+	)
+}
+
+// registryCredentials returns the credentials of the registries the container group pulls images from with a username
+// and password, which are given again when the container group is updated since ARM doesn't return the passwords. The
+// credentials of the admin users of the registries are used, since the container group keeps pulling images with them.
+func (at *aciTarget) registryCredentials(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+) ([]containerinstances.RegistryCredential, error) {
+	containerGroup, err := at.containerInstanceService.GetContainerGroup(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching container group: %w", err)
+	}
+
+	credentials := []containerinstances.RegistryCredential{}
+	for _, server := range containerGroup.PasswordRegistries {
+		adminCredentials, err := at.containerHelper.AdminCredentials(ctx, targetResource, server)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"getting the credentials container group '%s' pulls images from registry '%s' with, enable the admin "+
+					"user of the registry, or pull images with a managed identity: %w",
+				targetResource.ResourceName(), server, err)
+		}
+
+		credentials = append(credentials, containerinstances.RegistryCredential{
+			Server:   server,
+			Username: adminCredentials.Username,
+			Password: adminCredentials.Password,
+		})
+	}
+
+	return credentials, nil
+}
+
+// waitForContainerGroup reports the state of the container group as progress until the container group leaves the
+// Pending state. An error is returned when the container group fails or is stopped.
+func (at *aciTarget) waitForContainerGroup(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	targetResource *environment.TargetResource,
+) error {
+	for {
+		containerGroup, err := at.containerInstanceService.GetContainerGroup(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+		if err != nil {
+			return fmt.Errorf("fetching container group status: %w", err)
+		}
+
+		state := containerGroup.State
+		if state == "" {
+			state = containerGroup.ProvisioningState
+		}
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Container group status: %s", state)))
+
+		switch state {
+		case "Failed", "Stopped":
+			return fmt.Errorf("container group '%s' is in state '%s'", targetResource.ResourceName(), state)
+		case "", "Pending", "Creating", "Updating", "Repairing":
+		default:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(at.pollInterval):
+		}
+	}
+}
+
+// DeployPreview returns the steps that push the image of the service and update the container group with it.
+func (at *aciTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	if _, err := aciRestartPolicy(serviceConfig); err != nil {
+		return nil, err
+	}
+
+	steps, image, err := at.containerHelper.DeployPreview(ctx, serviceConfig, packageOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	steps = append(steps, ServiceDeployStep{
+		Kind: DeployStepUpdate,
+		Description: fmt.Sprintf(
+			"Update container group %s with the image %s", targetResource.ResourceName(), image.Planned),
+	})
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.ContainerGroupRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:  AciTarget,
+		Steps: steps,
+		Image: image,
+	}, nil
+}
+
+// Gets endpoints for the container group
+func (at *aciTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	containerGroup, err := at.containerInstanceService.GetContainerGroup(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	host := containerGroup.Fqdn
+	if host == "" {
+		host = containerGroup.IpAddress
+	}
+
+	if host == "" {
+		return []string{}, nil
+	}
+
+	endpoints := make([]string, len(containerGroup.Ports))
+	for idx, port := range containerGroup.Ports {
+		if port == 80 {
+			endpoints[idx] = fmt.Sprintf("http://%s/", host)
+		} else {
+			endpoints[idx] = fmt.Sprintf("http://%s:%d/", host, port)
+		}
+	}
+
+	return endpoints, nil
+}
+
+func (at *aciTarget) validateTargetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	if targetResource.ResourceGroupName() == "" {
+		return fmt.Errorf("missing resource group name: %s", targetResource.ResourceGroupName())
+	}
+
+	if targetResource.ResourceType() != "" {
+		if err := checkResourceType(targetResource, infra.AzureResourceTypeContainerGroup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// aciRestartPolicy returns the restart policy configured for the service, in the casing expected by Azure.
+func aciRestartPolicy(serviceConfig *ServiceConfig) (string, error) {
+	if serviceConfig.Aci.RestartPolicy == "" {
+		return "", nil
+	}
+
+	for _, policy := range []string{
+		containerinstances.RestartPolicyAlways,
+		containerinstances.RestartPolicyOnFailure,
+		containerinstances.RestartPolicyNever,
+	} {
+		if strings.EqualFold(serviceConfig.Aci.RestartPolicy, policy) {
+			return policy, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"service '%s' has unsupported restart policy '%s', supported values are '%s', '%s' and '%s'",
+		serviceConfig.Name,
+		serviceConfig.Aci.RestartPolicy,
+		containerinstances.RestartPolicyAlways,
+		containerinstances.RestartPolicyOnFailure,
+		containerinstances.RestartPolicyNever,
+	)
+}
@@ -0,0 +1,266 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/containerinstances"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAciTargetTypeValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*serviceTargetValidationTest{
+		"ValidateTypeSuccess": {
+			targetResource: environment.NewTargetResource(
+				"SUB_ID",
+				"RG_ID",
+				"res",
+				string(infra.AzureResourceTypeContainerGroup),
+			),
+			expectError: false,
+		},
+		"ValidateTypeLowerCaseSuccess": {
+			targetResource: environment.NewTargetResource(
+				"SUB_ID",
+				"RG_ID",
+				"res",
+				strings.ToLower(string(infra.AzureResourceTypeContainerGroup)),
+			),
+			expectError: false,
+		},
+		"ValidateTypeFail": {
+			targetResource: environment.NewTargetResource("SUB_ID", "RG_ID", "res", "BadType"),
+			expectError:    true,
+		},
+	}
+
+	for test, data := range tests {
+		t.Run(test, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			serviceTarget := &aciTarget{}
+			serviceConfig := &ServiceConfig{}
+
+			err := serviceTarget.validateTargetResource(*mockContext.Context, serviceConfig, data.targetResource)
+			if data.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Aci_Initialize_RestartPolicy(t *testing.T) {
+	serviceTarget := &aciTarget{}
+	serviceConfig := createTestServiceConfig("./src/api", AciTarget, ServiceLanguageTypeScript)
+
+	serviceConfig.Aci.RestartPolicy = "onfailure"
+	require.NoError(t, serviceTarget.Initialize(context.Background(), serviceConfig))
+
+	serviceConfig.Aci.RestartPolicy = "Sometimes"
+	require.Error(t, serviceTarget.Initialize(context.Background(), serviceConfig))
+}
+
+func Test_Aci_Deploy(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	setupMocksForAcr(mockContext)
+	updatedContainerGroup := setupMocksForContainerInstances(mockContext)
+
+	serviceConfig := createTestServiceConfig(tempDir, AciTarget, ServiceLanguageTypeScript)
+	serviceConfig.Aci = AciOptions{
+		Env: map[string]ExpandableString{
+			"LOCATION": NewExpandableString("${AZURE_LOCATION}"),
+			"SECRET":   NewExpandableString("secret"),
+		},
+		RestartPolicy: "never",
+	}
+	env := createEnv()
+
+	serviceTarget := createAciServiceTarget(mockContext, env)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_GROUP",
+		string(infra.AzureResourceTypeContainerGroup),
+	)
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+	require.Equal(t, AciTarget, deployResult.Kind)
+	require.Equal(t, []string{"http://CONTAINER_GROUP.eastus2.azurecontainer.io:8080/"}, deployResult.Endpoints)
+
+	properties := updatedContainerGroup["properties"].(map[string]any)
+	require.Equal(t, "Never", properties["restartPolicy"])
+	require.NotContains(t, properties, "instanceView")
+
+	container := properties["containers"].([]any)[0].(map[string]any)["properties"].(map[string]any)
+	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", container["image"])
+	require.Equal(t, []any{
+		map[string]any{"name": "EXISTING", "value": "1"},
+		map[string]any{"name": "SECRET", "value": "secret"},
+		map[string]any{"name": "LOCATION", "value": "LOCATION"},
+	}, container["environmentVariables"])
+
+	// the password of the registry is given again, since ARM doesn't return it
+	require.Equal(t, []any{
+		map[string]any{"server": "REGISTRY.azurecr.io", "username": "admin", "password": "password"},
+		map[string]any{"server": "OTHER.azurecr.io", "identity": "IDENTITY_ID"},
+	}, properties["imageRegistryCredentials"])
+}
+
+func Test_Aci_Deploy_SecureValueNotGiven(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	setupMocksForAcr(mockContext)
+	updatedContainerGroup := setupMocksForContainerInstances(mockContext)
+
+	serviceConfig := createTestServiceConfig(tempDir, AciTarget, ServiceLanguageTypeScript)
+	serviceTarget := createAciServiceTarget(mockContext, createEnv())
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_GROUP",
+		string(infra.AzureResourceTypeContainerGroup),
+	)
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, scope)
+	logProgress(deployTask)
+	_, err := deployTask.Await()
+
+	require.ErrorContains(t, err, "the secure values of the environment variables SECRET aren't returned by Azure")
+	require.Empty(t, updatedContainerGroup)
+}
+
+func createAciServiceTarget(
+	mockContext *mocks.MockContext,
+	env *environment.Environment,
+) ServiceTarget {
+	dockerCli := docker.NewDocker(mockContext.CommandRunner)
+	credentialProvider := mockaccount.SubscriptionCredentialProviderFunc(
+		func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		})
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	containerInstanceService := containerinstances.NewContainerInstanceService(credentialProvider, mockContext.HttpClient)
+	containerRegistryService := azcli.NewContainerRegistryService(credentialProvider, mockContext.HttpClient, dockerCli)
+	containerHelper := NewContainerHelper(
+		env, envManager, clock.NewMock(), containerRegistryService, dockerCli, nil, mockContext.Console)
+
+	return NewAciTarget(env, containerHelper, containerInstanceService)
+}
+
+// setupMocksForContainerInstances mocks the GET and PUT requests of a container group. The returned map is populated
+// with the body of the PUT request.
+func setupMocksForContainerInstances(mockContext *mocks.MockContext) map[string]any {
+	containerGroupPath := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+		"Microsoft.ContainerInstance/containerGroups/CONTAINER_GROUP"
+	containerGroup := map[string]any{
+		"location": "eastus2",
+		"properties": map[string]any{
+			"provisioningState": "Succeeded",
+			"restartPolicy":     "Always",
+			"instanceView": map[string]any{
+				"state": "Running",
+			},
+			"containers": []any{
+				map[string]any{
+					"name": "api",
+					"properties": map[string]any{
+						"image": "ORIGINAL_IMAGE_NAME",
+						"environmentVariables": []any{
+							map[string]any{"name": "EXISTING", "value": "1"},
+							// ARM doesn't return secure values
+							map[string]any{"name": "SECRET"},
+						},
+					},
+				},
+			},
+			"imageRegistryCredentials": []any{
+				map[string]any{"server": "REGISTRY.azurecr.io", "username": "admin"},
+				map[string]any{"server": "OTHER.azurecr.io", "identity": "IDENTITY_ID"},
+			},
+			"ipAddress": map[string]any{
+				"type": "Public",
+				"ip":   "10.0.0.1",
+				"fqdn": "CONTAINER_GROUP.eastus2.azurecontainer.io",
+				"ports": []any{
+					map[string]any{"port": 8080, "protocol": "TCP"},
+				},
+			},
+		},
+	}
+
+	updated := map[string]any{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, containerGroupPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, containerGroup)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, containerGroupPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(body, &updated); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, containerGroup)
+	})
+
+	return updated
+}
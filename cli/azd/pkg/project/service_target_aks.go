@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -13,15 +14,24 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/helm"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	defaultDeploymentPath = "manifests"
+	defaultKustomizePath  = "kustomize"
+	// The folder of the kustomize directory that contains an overlay for each environment
+	kustomizeOverlaysPath = "overlays"
 )
 
+// The file names kustomize looks for in a kustomization directory
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
 // The AKS configuration options
 type AksOptions struct {
 	// The namespace used for deploying k8s resources. Defaults to the project name
@@ -34,6 +44,12 @@ type AksOptions struct {
 	Deployment AksDeploymentOptions `yaml:"deployment"`
 	// The services service configuration options
 	Service AksServiceOptions `yaml:"service"`
+	// The Helm chart configuration options. When set, the service is deployed by installing the chart as a Helm release
+	// instead of applying the k8s deployment manifests
+	Helm *AksHelmOptions `yaml:"helm,omitempty"`
+	// The Kustomize configuration options. When set, the service is deployed by applying the kustomization of the
+	// environment instead of the k8s deployment manifests
+	Kustomize *AksKustomizeOptions `yaml:"kustomize,omitempty"`
 }
 
 // The AKS ingress options
@@ -52,11 +68,22 @@ type AksServiceOptions struct {
 	Name string `yaml:"name"`
 }
 
+// The AKS Helm chart options
+type AksHelmOptions struct {
+	// The relative folder path from the service that contains the Helm chart
+	Chart string `yaml:"chart"`
+	// The name of the Helm release. Defaults to the service name
+	Release string `yaml:"release,omitempty"`
+	// The relative paths from the service of the values files, applied in order
+	Values []string `yaml:"values,omitempty"`
+}
+
 type aksTarget struct {
 	env                    *environment.Environment
 	envManager             environment.Manager
 	managedClustersService azcli.ManagedClustersService
 	kubectl                kubectl.KubectlCli
+	helm                   helm.HelmCli
 	containerHelper        *ContainerHelper
 }
 
@@ -66,6 +93,7 @@ func NewAksTarget(
 	envManager environment.Manager,
 	managedClustersService azcli.ManagedClustersService,
 	kubectlCli kubectl.KubectlCli,
+	helmCli helm.HelmCli,
 	containerHelper *ContainerHelper,
 ) ServiceTarget {
 	return &aksTarget{
@@ -73,11 +101,13 @@ func NewAksTarget(
 		envManager:             envManager,
 		managedClustersService: managedClustersService,
 		kubectl:                kubectlCli,
+		helm:                   helmCli,
 		containerHelper:        containerHelper,
 	}
 }
 
-// Gets the required external tools to support the AKS service
+// Gets the required external tools to support the AKS service. Helm is only required by services that deploy a Helm
+// chart, and is checked when those services are deployed.
 func (t *aksTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalTool {
 	allTools := []tools.ExternalTool{}
 	allTools = append(allTools, t.containerHelper.RequiredExternalTools(ctx)...)
@@ -88,6 +118,14 @@ func (t *aksTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalT
 
 // Initializes the AKS service target
 func (t *aksTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	// a service is deployed either by the Helm chart or by the kustomization, so setting both is ambiguous
+	if serviceConfig.K8s.Helm != nil && serviceConfig.K8s.Kustomize != nil {
+		return fmt.Errorf(
+			"service '%s' sets both k8s.helm and k8s.kustomize, remove one of them to choose how the service is deployed",
+			serviceConfig.Name,
+		)
+	}
+
 	// TODO: At some point in the future this an opportunity for the AKS target to
 	// subscript to a post-provision event to allow additional cluster configuration
 	// outside of the bicep provisioning.
@@ -201,21 +239,36 @@ func (t *aksTarget) Deploy(
 				return
 			}
 
-			task.SetProgress(NewServiceProgress("Applying k8s manifests"))
-			t.kubectl.SetEnv(t.env.Dotenv())
-			deploymentPath := serviceConfig.K8s.DeploymentPath
-			if deploymentPath == "" {
-				deploymentPath = defaultDeploymentPath
-			}
+			switch {
+			case serviceConfig.K8s.Helm != nil:
+				task.SetProgress(NewServiceProgress("Installing Helm chart"))
+				if err := t.upgradeHelmRelease(ctx, serviceConfig, namespace); err != nil {
+					task.SetError(fmt.Errorf("failed installing helm chart: %w", err))
+					return
+				}
+			case serviceConfig.K8s.Kustomize != nil:
+				task.SetProgress(NewServiceProgress("Applying k8s kustomization"))
+				if err := t.applyKustomization(ctx, serviceConfig, namespace); err != nil {
+					task.SetError(fmt.Errorf("failed applying kube kustomization: %w", err))
+					return
+				}
+			default:
+				task.SetProgress(NewServiceProgress("Applying k8s manifests"))
+				t.kubectl.SetEnv(t.env.Dotenv())
+				deploymentPath := serviceConfig.K8s.DeploymentPath
+				if deploymentPath == "" {
+					deploymentPath = defaultDeploymentPath
+				}
 
-			err = t.kubectl.Apply(
-				ctx,
-				filepath.Join(serviceConfig.RelativePath, deploymentPath),
-				&kubectl.KubeCliFlags{Namespace: namespace},
-			)
-			if err != nil {
-				task.SetError(fmt.Errorf("failed applying kube manifests: %w", err))
-				return
+				err = t.kubectl.Apply(
+					ctx,
+					filepath.Join(serviceConfig.RelativePath, deploymentPath),
+					&kubectl.KubeCliFlags{Namespace: namespace},
+				)
+				if err != nil {
+					task.SetError(fmt.Errorf("failed applying kube manifests: %w", err))
+					return
+				}
 			}
 
 			deploymentName := serviceConfig.K8s.Deployment.Name
@@ -232,6 +285,14 @@ func (t *aksTarget) Deploy(
 				return
 			}
 
+			if deployment != nil && len(serviceConfig.Env) > 0 {
+				task.SetProgress(NewServiceProgress("Updating deployment environment variables"))
+				if err := t.setDeploymentEnv(ctx, serviceConfig, namespace, deployment.Metadata.Name); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			task.SetProgress(NewServiceProgress("Fetching endpoints for AKS service"))
 			endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
 			if err != nil {
@@ -265,6 +326,64 @@ func (t *aksTarget) Deploy(
 		})
 }
 
+// DeployPreview returns the steps that push the image of the service and deploy the Kubernetes resources of the service
+// to the cluster.
+func (t *aksTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	clusterName, has := t.env.LookupEnv(environment.AksClusterEnvVarName)
+	if !has {
+		return nil, fmt.Errorf(
+			"could not determine AKS cluster, ensure %s is set as an output of your infrastructure",
+			environment.AksClusterEnvVarName,
+		)
+	}
+
+	steps, image, err := t.containerHelper.DeployPreview(ctx, serviceConfig, packageOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := t.getK8sNamespace(serviceConfig)
+	var description string
+	switch {
+	case serviceConfig.K8s.Helm != nil:
+		description = fmt.Sprintf("Install the Helm charts to namespace %s of AKS cluster %s", namespace, clusterName)
+	case serviceConfig.K8s.Kustomize != nil:
+		description = fmt.Sprintf("Apply the kustomization to namespace %s of AKS cluster %s", namespace, clusterName)
+	default:
+		deploymentPath := serviceConfig.K8s.DeploymentPath
+		if deploymentPath == "" {
+			deploymentPath = defaultDeploymentPath
+		}
+
+		description = fmt.Sprintf(
+			"Apply the manifests of %s to namespace %s of AKS cluster %s",
+			filepath.Join(serviceConfig.RelativePath, deploymentPath),
+			namespace,
+			clusterName)
+	}
+	steps = append(steps, ServiceDeployStep{Kind: DeployStepUpdate, Description: description})
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.KubernetesServiceRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:  AksTarget,
+		Steps: steps,
+		Image: image,
+	}, nil
+}
+
 // Gets the service endpoints for the AKS service target
 func (t *aksTarget) Endpoints(
 	ctx context.Context,
@@ -356,6 +475,171 @@ func (t *aksTarget) configureK8sContext(
 	return nil
 }
 
+// The AKS Kustomize options
+type AksKustomizeOptions struct {
+	// The relative folder path from the service that contains the kustomization. Defaults to 'kustomize'
+	Dir string `yaml:"dir,omitempty"`
+	// The overlay of the 'overlays' folder to apply. Defaults to the environment name
+	Overlay string `yaml:"overlay,omitempty"`
+	// The name of the image in the k8s resources that is replaced by the image pushed to the container registry.
+	// Defaults to the service name
+	Image string `yaml:"image,omitempty"`
+}
+
+// Installs the Helm chart of the service as a release in the namespace, or upgrades the release when it is already
+// installed. The image pushed to the container registry is set as the image.repository and image.tag values of the chart.
+func (t *aksTarget) upgradeHelmRelease(ctx context.Context, serviceConfig *ServiceConfig, namespace string) error {
+	helmOptions := serviceConfig.K8s.Helm
+	if helmOptions.Chart == "" {
+		return errors.New("missing chart path, ensure 'k8s.helm.chart' is set for the service")
+	}
+
+	if err := tools.EnsureInstalled(ctx, t.helm); err != nil {
+		return err
+	}
+
+	release := helmOptions.Release
+	if release == "" {
+		release = serviceConfig.Name
+	}
+
+	values := map[string]string{}
+	if imageName := t.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME"); imageName != "" {
+		repository, tag := splitImageTag(imageName)
+		values["image.repository"] = repository
+		if tag != "" {
+			values["image.tag"] = tag
+		}
+	}
+
+	log.Printf("upgrading helm release '%s' with chart '%s'\n", release, helmOptions.Chart)
+	_, err := t.helm.Upgrade(ctx, release, helmOptions.Chart, &helm.UpgradeFlags{
+		Namespace:   namespace,
+		ValuesFiles: helmOptions.Values,
+		Values:      values,
+		Cwd:         serviceConfig.Path(),
+	})
+
+	return err
+}
+
+// Applies the kustomization overlay of the environment, or the kustomization of the kustomize folder when the environment
+// has no overlay. The image pushed to the container registry replaces the image of the service with a kustomize image
+// transformer, by applying a generated kustomization that includes the selected one.
+func (t *aksTarget) applyKustomization(ctx context.Context, serviceConfig *ServiceConfig, namespace string) error {
+	kustomizeOptions := serviceConfig.K8s.Kustomize
+	kustomizePath := kustomizeOptions.Dir
+	if kustomizePath == "" {
+		kustomizePath = defaultKustomizePath
+	}
+	kustomizePath = filepath.Join(serviceConfig.Path(), kustomizePath)
+
+	overlay := kustomizeOptions.Overlay
+	if overlay == "" {
+		overlay = t.env.GetEnvName()
+	}
+
+	kustomizationPath := filepath.Join(kustomizePath, kustomizeOverlaysPath, overlay)
+	if !hasKustomization(kustomizationPath) {
+		if !hasKustomization(kustomizePath) {
+			return fmt.Errorf(
+				"no kustomization found in '%s' or '%s', ensure one of them contains a kustomization.yaml file",
+				kustomizationPath,
+				kustomizePath,
+			)
+		}
+
+		log.Printf("no kustomize overlay found for '%s', applying the kustomization in '%s'", overlay, kustomizePath)
+		kustomizationPath = kustomizePath
+	}
+
+	if imageName := t.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME"); imageName != "" {
+		image := kustomizeOptions.Image
+		if image == "" {
+			image = serviceConfig.Name
+		}
+
+		generatedPath, err := writeImageKustomization(kustomizationPath, image, imageName)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(generatedPath)
+
+		kustomizationPath = generatedPath
+	}
+
+	t.kubectl.SetEnv(t.env.Dotenv())
+	_, err := t.kubectl.ApplyWithKustomize(ctx, kustomizationPath, &kubectl.KubeCliFlags{Namespace: namespace})
+	return err
+}
+
+// Checks whether the directory contains a kustomization file
+func hasKustomization(path string) bool {
+	for _, fileName := range kustomizationFileNames {
+		if _, err := os.Stat(filepath.Join(path, fileName)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Writes a kustomization to a new temporary directory that includes the resources of the kustomization in
+// kustomizationPath, and replaces the image named image with imageName. Returns the path of the new directory.
+func writeImageKustomization(kustomizationPath string, image string, imageName string) (string, error) {
+	generatedPath, err := os.MkdirTemp("", "azd-kustomize")
+	if err != nil {
+		return "", fmt.Errorf("creating kustomization directory: %w", err)
+	}
+
+	// kustomize resolves the resources relative to the kustomization
+	resourcePath, err := filepath.Rel(generatedPath, kustomizationPath)
+	if err != nil {
+		resourcePath = kustomizationPath
+	}
+
+	newName, newTag := splitImageTag(imageName)
+	imageTransform := map[string]string{
+		"name":    image,
+		"newName": newName,
+	}
+	if newTag != "" {
+		imageTransform["newTag"] = newTag
+	}
+
+	kustomization := map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{filepath.ToSlash(resourcePath)},
+		"images":     []map[string]string{imageTransform},
+	}
+
+	contents, err := yaml.Marshal(kustomization)
+	if err != nil {
+		os.RemoveAll(generatedPath)
+		return "", fmt.Errorf("marshalling kustomization: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(generatedPath, kustomizationFileNames[0]), contents, osutil.PermissionFile)
+	if err != nil {
+		os.RemoveAll(generatedPath)
+		return "", fmt.Errorf("writing kustomization: %w", err)
+	}
+
+	return generatedPath, nil
+}
+
+// splitImageTag splits a container image name into its repository and its tag, which is empty when the image isn't tagged
+func splitImageTag(imageName string) (repository string, tag string) {
+	// A colon before the last slash separates the port of the registry, not the tag
+	lastSlash := strings.LastIndex(imageName, "/")
+	if i := strings.LastIndex(imageName, ":"); i > lastSlash {
+		return imageName[:i], imageName[i+1:]
+	}
+
+	return imageName, ""
+}
+
 // Finds a deployment using the specified deploymentNameFilter string
 // Waits until the deployment rollout is complete and all replicas are accessible
 // Additionally confirms rollout is complete by checking the rollout status
@@ -392,6 +676,51 @@ func (t *aksTarget) waitForDeployment(
 	return deployment, nil
 }
 
+// Sets the environment variables declared by the service on the containers of the deployment
+// and waits for the resulting rollout to complete
+func (t *aksTarget) setDeploymentEnv(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	namespace string,
+	deploymentName string,
+) error {
+	envVars, err := serviceEnv(ctx, serviceConfig, t.env)
+	if err != nil {
+		return err
+	}
+
+	// Secret values are stored in a secret of the deployment that the containers reference, rather than set on the
+	// deployment, where they could be read by anyone who can read the deployment.
+	env := map[string]string{}
+	secrets := map[string]string{}
+	for _, envVar := range envVars {
+		if envVar.Secret {
+			secrets[envVar.Name] = envVar.Value
+		} else {
+			env[envVar.Name] = envVar.Value
+		}
+	}
+
+	flags := &kubectl.KubeCliFlags{Namespace: namespace}
+	secretName := ""
+	if len(secrets) > 0 {
+		secretName = fmt.Sprintf("%s-env", deploymentName)
+		if _, err := t.kubectl.ApplySecret(ctx, secretName, secrets, flags); err != nil {
+			return err
+		}
+	}
+
+	if _, err := t.kubectl.SetDeploymentEnv(ctx, deploymentName, env, secretName, flags); err != nil {
+		return err
+	}
+
+	if _, err := t.kubectl.RolloutStatus(ctx, deploymentName, flags); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Finds an ingress using the specified ingressNameFilter string
 // Waits until the ingress LoadBalancer has assigned a valid IP address
 func (t *aksTarget) waitForIngress(
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/helm"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
@@ -44,6 +46,20 @@ func Test_NewAksTarget(t *testing.T) {
 	require.NotNil(t, serviceConfig)
 }
 
+func Test_Initialize_Helm_And_Kustomize(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig("./src/api", AksTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+	require.NoError(t, serviceTarget.Initialize(*mockContext.Context, serviceConfig))
+
+	serviceConfig.K8s.Helm = &AksHelmOptions{}
+	serviceConfig.K8s.Kustomize = &AksKustomizeOptions{}
+	err := serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+	require.ErrorContains(t, err, "service 'api' sets both k8s.helm and k8s.kustomize")
+}
+
 func Test_Required_Tools(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -110,6 +126,289 @@ func Test_Package_Deploy_HappyPath(t *testing.T) {
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
 }
 
+func Test_Deploy_Env(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	var secret map[string]any
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply --server-side")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Contains(t, args.Args, "-n")
+		return exec.NewRunResult(0, "", ""), json.NewDecoder(args.StdIn).Decode(&secret)
+	})
+
+	var setEnvArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl set env")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		setEnvArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Env = map[string]ServiceEnvValue{
+		"LOG_LEVEL": {Value: NewExpandableString("debug")},
+		"API_KEY":   {Value: NewExpandableString("${API_KEY}"), Secret: true},
+	}
+	env := createEnv()
+	env.DotenvSet("API_KEY", "KEY_VALUE")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "CLUSTER_NAME", string(infra.AzureResourceTypeManagedCluster))
+	deployTask := serviceTarget.Deploy(
+		*mockContext.Context,
+		serviceConfig,
+		&ServicePackageResult{
+			PackagePath: "test-app/api-test:azd-deploy-0",
+			Details: &dockerPackageResult{
+				ImageHash: "IMAGE_HASH",
+				ImageTag:  "test-app/api-test:azd-deploy-0",
+			},
+		},
+		scope,
+	)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+
+	// the secret value is stored in a secret, which the deployment references, rather than set on the deployment
+	require.Equal(t, "api-deployment-env", secret["metadata"].(map[string]any)["name"])
+	require.Equal(t, map[string]any{"API_KEY": "S0VZX1ZBTFVF"}, secret["data"])
+	require.Contains(t, setEnvArgs, "--from=secret/api-deployment-env")
+	require.Contains(t, setEnvArgs, "LOG_LEVEL=debug")
+	require.NotContains(t, strings.Join(setEnvArgs, " "), "KEY_VALUE")
+}
+
+func Test_Deploy_Helm(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	// The AKS target checks that helm is installed before installing the chart
+	binDir := t.TempDir()
+	helmPath := filepath.Join(binDir, "helm")
+	if runtime.GOOS == "windows" {
+		helmPath += ".exe"
+	}
+	err = os.WriteFile(helmPath, nil, 0700)
+	require.NoError(t, err)
+	t.Setenv("PATH", binDir)
+
+	var helmArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm version")
+	}).Respond(exec.NewRunResult(0, "v3.14.0", ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm upgrade --install")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		helmArgs = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	kubectlApplied := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f") && !strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		kubectlApplied = true
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Helm = &AksHelmOptions{
+		Chart:  "./chart",
+		Values: []string{"values.yaml"},
+	}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "CLUSTER_NAME", string(infra.AzureResourceTypeManagedCluster))
+	packageOutput := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.NoError(t, err)
+	require.NotNil(t, deployResult)
+	require.IsType(t, new(kubectl.Deployment), deployResult.Details)
+	require.False(t, kubectlApplied)
+
+	require.Equal(t, serviceConfig.Path(), helmArgs.Cwd)
+	require.Equal(t, []string{
+		"upgrade", "--install", "api", "./chart",
+		"--namespace", "Test-App",
+		"--values", "values.yaml",
+		"--set", "image.repository=REGISTRY.azurecr.io/test-app/api-test",
+		"--set", "image.tag=azd-deploy-0",
+	}, helmArgs.Args)
+}
+
+func Test_Deploy_Kustomize(t *testing.T) {
+	tests := []struct {
+		name string
+		// The kustomize folders that contain a kustomization
+		kustomizations []string
+		wantResource   string
+	}{
+		{
+			name:           "environment overlay",
+			kustomizations: []string{"kustomize", "kustomize/overlays/test"},
+			wantResource:   "kustomize/overlays/test",
+		},
+		{
+			name:           "no environment overlay",
+			kustomizations: []string{"kustomize", "kustomize/overlays/prod"},
+			wantResource:   "kustomize",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			err := setupMocksForAksTarget(mockContext)
+			require.NoError(t, err)
+
+			var kustomizationPath string
+			var kustomization map[string]any
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "kubectl apply -k")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				kustomizationPath = args.Args[2]
+				contents, err := os.ReadFile(filepath.Join(kustomizationPath, "kustomization.yaml"))
+				if err != nil {
+					return exec.NewRunResult(1, "", err.Error()), err
+				}
+
+				if err := yaml.Unmarshal(contents, &kustomization); err != nil {
+					return exec.NewRunResult(1, "", err.Error()), err
+				}
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+			serviceConfig.K8s.Kustomize = &AksKustomizeOptions{}
+			env := createEnv()
+
+			for _, path := range tt.kustomizations {
+				path = filepath.Join(serviceConfig.Path(), path)
+				err := os.MkdirAll(path, osutil.PermissionDirectory)
+				require.NoError(t, err)
+				err = os.WriteFile(filepath.Join(path, "kustomization.yaml"), nil, osutil.PermissionFile)
+				require.NoError(t, err)
+			}
+
+			serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+			scope := environment.NewTargetResource(
+				"SUB_ID", "RG_ID", "CLUSTER_NAME", string(infra.AzureResourceTypeManagedCluster))
+			packageOutput := &ServicePackageResult{
+				PackagePath: "test-app/api-test:azd-deploy-0",
+				Details: &dockerPackageResult{
+					ImageHash: "IMAGE_HASH",
+					ImageTag:  "test-app/api-test:azd-deploy-0",
+				},
+			}
+
+			deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+			logProgress(deployTask)
+			deployResult, err := deployTask.Await()
+
+			require.NoError(t, err)
+			require.NotNil(t, deployResult)
+			require.IsType(t, new(kubectl.Deployment), deployResult.Details)
+
+			require.NotNil(t, kustomization)
+			require.Equal(t, []any{
+				map[string]any{
+					"name":    "api",
+					"newName": "REGISTRY.azurecr.io/test-app/api-test",
+					"newTag":  "azd-deploy-0",
+				},
+			}, kustomization["images"])
+
+			resources := kustomization["resources"].([]any)
+			require.Len(t, resources, 1)
+			resourcePath := resources[0].(string)
+			if !filepath.IsAbs(resourcePath) {
+				resourcePath = filepath.Join(kustomizationPath, resourcePath)
+			}
+			require.Equal(t, filepath.Join(serviceConfig.Path(), tt.wantResource), resourcePath)
+		})
+	}
+}
+
+func Test_Deploy_Kustomize_No_Kustomization(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.Kustomize = &AksKustomizeOptions{}
+	env := createEnv()
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "CLUSTER_NAME", string(infra.AzureResourceTypeManagedCluster))
+	packageOutput := &ServicePackageResult{
+		Details: &dockerPackageResult{
+			ImageTag: "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.ErrorContains(t, err, "no kustomization found")
+	require.Nil(t, deployResult)
+}
+
+func Test_splitImageTag(t *testing.T) {
+	tests := []struct {
+		imageName  string
+		repository string
+		tag        string
+	}{
+		{"REGISTRY.azurecr.io/test-app/api:azd-deploy-0", "REGISTRY.azurecr.io/test-app/api", "azd-deploy-0"},
+		{"localhost:5000/test-app/api:latest", "localhost:5000/test-app/api", "latest"},
+		{"localhost:5000/test-app/api", "localhost:5000/test-app/api", ""},
+		{"api", "api", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.imageName, func(t *testing.T) {
+			repository, tag := splitImageTag(tt.imageName)
+			require.Equal(t, tt.repository, repository)
+			require.Equal(t, tt.tag, tag)
+		})
+	}
+}
+
 func Test_Deploy_No_Cluster_Name(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -486,13 +785,15 @@ func createAksServiceTarget(
 
 	managedClustersService := azcli.NewManagedClustersService(credentialProvider, mockContext.HttpClient)
 	containerRegistryService := azcli.NewContainerRegistryService(credentialProvider, mockContext.HttpClient, dockerCli)
-	containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), containerRegistryService, dockerCli)
+	containerHelper := NewContainerHelper(
+		env, envManager, clock.NewMock(), containerRegistryService, dockerCli, nil, mockContext.Console)
 
 	return NewAksTarget(
 		env,
 		envManager,
 		managedClustersService,
 		kubeCtl,
+		helm.NewHelmCli(mockContext.CommandRunner),
 		containerHelper,
 	)
 }
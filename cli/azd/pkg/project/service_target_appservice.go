@@ -85,6 +85,14 @@ func (st *appServiceTarget) Deploy(
 				return
 			}
 
+			if len(serviceConfig.Env) > 0 {
+				task.SetProgress(NewServiceProgress("Updating app settings"))
+				if err := updateAppSettings(ctx, st.cli, st.env, serviceConfig, targetResource); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			zipFile, err := os.Open(packageOutput.PackagePath)
 			if err != nil {
 				task.SetError(fmt.Errorf("failed reading deployment zip file: %w", err))
@@ -131,6 +139,50 @@ func (st *appServiceTarget) Deploy(
 	)
 }
 
+// DeployPreview returns the steps that update the app settings and deploy the zip package to the app service.
+func (st *appServiceTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.WebsiteRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:  AppServiceTarget,
+		Steps: zipDeploySteps(serviceConfig, packageOutput, "app service", targetResource.ResourceName()),
+	}, nil
+}
+
+// zipDeploySteps returns the steps of the deploy plan of App Service and Azure Functions apps, which update the app
+// settings and deploy the zip package of the service to the app.
+func zipDeploySteps(
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	kind string,
+	appName string,
+) []ServiceDeployStep {
+	steps := []ServiceDeployStep{packageDeployStep(packageOutput)}
+	if len(serviceConfig.Env) > 0 {
+		steps = append(steps, ServiceDeployStep{
+			Kind:        DeployStepUpdate,
+			Description: fmt.Sprintf("Update the app settings of %s %s", kind, appName),
+		})
+	}
+
+	return append(steps, ServiceDeployStep{
+		Kind:        DeployStepUpdate,
+		Description: fmt.Sprintf("Deploy the zip package to %s %s", kind, appName),
+	})
+}
+
 // Gets the exposed endpoints for the App Service
 func (st *appServiceTarget) Endpoints(
 	ctx context.Context,
@@ -170,3 +222,36 @@ func (st *appServiceTarget) validateTargetResource(
 
 	return nil
 }
+
+// updateAppSettings sets the environment variables declared for the service as application settings of the App Service
+// or Azure Functions app.
+func updateAppSettings(
+	ctx context.Context,
+	cli azcli.AzCli,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	envVars, err := serviceEnv(ctx, serviceConfig, env)
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]string{}
+	for _, envVar := range envVars {
+		settings[envVar.Name] = envVar.Value
+	}
+
+	err = cli.UpdateAppServiceAppSettings(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		settings,
+	)
+	if err != nil {
+		return fmt.Errorf("updating app settings of service %s: %w", serviceConfig.Name, err)
+	}
+
+	return nil
+}
@@ -5,23 +5,103 @@ package project
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
+const (
+	// The default time to wait for the deployed revision of a container app to be healthy
+	defaultRevisionTimeout = 5 * time.Minute
+	// The number of lines of the system logs of a container app included in the error of an unhealthy revision
+	revisionLogLines = 20
+)
+
+// The Azure Container Apps configuration options
+type ContainerAppOptions struct {
+	// The number of seconds to wait for the deployed revision to be provisioned and healthy. Defaults to 300 seconds.
+	RevisionTimeout int `yaml:"revisionTimeout,omitempty"`
+	// The health probes of the container. The probes that aren't set are kept unchanged on the deployed revision.
+	Probes ContainerAppProbes `yaml:"probes,omitempty"`
+	// The scale settings of the container app. The settings that aren't set are kept unchanged on the deployed revision.
+	Scale ContainerAppScale `yaml:"scale,omitempty"`
+}
+
+// The scale settings of a container app
+type ContainerAppScale struct {
+	// The minimum number of replicas, 0 allows the container app to scale to zero
+	MinReplicas *int `yaml:"minReplicas,omitempty"`
+	// The maximum number of replicas
+	MaxReplicas *int `yaml:"maxReplicas,omitempty"`
+	// The scale rules, which replace the rules of the deployed revision
+	Rules []ContainerAppScaleRule `yaml:"rules,omitempty"`
+}
+
+// A rule of the scale settings of a container app
+type ContainerAppScaleRule struct {
+	// The name of the rule. Defaults to the type of the rule.
+	Name string `yaml:"name,omitempty"`
+	// The type of the rule, one of http, cpu, memory or azure-queue
+	Type string `yaml:"type"`
+	// The number of concurrent requests per replica of an http rule
+	ConcurrentRequests int `yaml:"concurrentRequests,omitempty"`
+	// The average utilization percentage of the replicas of a cpu or memory rule
+	Utilization int `yaml:"utilization,omitempty"`
+	// The name of the queue of an azure-queue rule
+	QueueName string `yaml:"queueName,omitempty"`
+	// The number of messages per replica of an azure-queue rule
+	QueueLength int `yaml:"queueLength,omitempty"`
+	// The name of the secret of the container app with the connection string of the storage account of an azure-queue
+	// rule
+	SecretRef string `yaml:"secretRef,omitempty"`
+}
+
+// The health probes of the container of a container app
+type ContainerAppProbes struct {
+	Liveness  *ContainerAppProbe `yaml:"liveness,omitempty"`
+	Readiness *ContainerAppProbe `yaml:"readiness,omitempty"`
+	Startup   *ContainerAppProbe `yaml:"startup,omitempty"`
+}
+
+// A health probe of the container of a container app
+type ContainerAppProbe struct {
+	// The path of the HTTP GET request of the probe. When empty, the probe opens a TCP connection to the port.
+	Path string `yaml:"path,omitempty"`
+	// The port of the container that is probed. Defaults to the target port of the ingress of the container app.
+	Port int `yaml:"port,omitempty"`
+	// The number of seconds after the container has started before the probe is initiated
+	InitialDelay int `yaml:"initialDelay,omitempty"`
+	// The number of seconds between probes
+	Interval int `yaml:"interval,omitempty"`
+	// The number of consecutive failures for the probe to be considered failed
+	FailureThreshold int `yaml:"failureThreshold,omitempty"`
+}
+
 type containerAppTarget struct {
+	console             input.Console
 	env                 *environment.Environment
 	envManager          environment.Manager
 	containerHelper     *ContainerHelper
 	containerAppService containerapps.ContainerAppService
 	resourceManager     ResourceManager
+
+	// the interval between checks of the revision state after the container app is updated
+	pollInterval time.Duration
 }
 
 // NewContainerAppTarget creates the container app service target.
@@ -29,6 +109,7 @@ type containerAppTarget struct {
 // The target resource can be partially filled with only ResourceGroupName, since container apps
 // can be provisioned during deployment.
 func NewContainerAppTarget(
+	console input.Console,
 	env *environment.Environment,
 	envManager environment.Manager,
 	containerHelper *ContainerHelper,
@@ -36,11 +117,13 @@ func NewContainerAppTarget(
 	resourceManager ResourceManager,
 ) ServiceTarget {
 	return &containerAppTarget{
+		console:             console,
 		env:                 env,
 		envManager:          envManager,
 		containerHelper:     containerHelper,
 		containerAppService: containerAppService,
 		resourceManager:     resourceManager,
+		pollInterval:        5 * time.Second,
 	}
 }
 
@@ -51,6 +134,13 @@ func (at *containerAppTarget) RequiredExternalTools(ctx context.Context) []tools
 
 // Initializes the Container App target
 func (at *containerAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	// the probes are validated up front, so an invalid azure.yaml fails before the service is packaged
+	for _, c := range configuredProbes(serviceConfig) {
+		if err := validateProbe(c.probe); err != nil {
+			return fmt.Errorf("invalid %s probe of service '%s': %w", c.name, serviceConfig.Name, err)
+		}
+	}
+
 	if err := at.addPreProvisionChecks(ctx, serviceConfig); err != nil {
 		return fmt.Errorf("initializing container app target: %w", err)
 	}
@@ -85,11 +175,38 @@ func (at *containerAppTarget) Deploy(
 				return
 			}
 
+			envVars, err := serviceEnv(ctx, serviceConfig, at.env)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			containerEnv := make([]containerapps.EnvironmentVariable, len(envVars))
+			for idx, envVar := range envVars {
+				containerEnv[idx] = containerapps.EnvironmentVariable{
+					Name:   envVar.Name,
+					Value:  envVar.Value,
+					Secret: envVar.Secret,
+				}
+			}
+
+			probes, err := at.containerProbes(ctx, serviceConfig, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			scale, err := containerAppScale(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Login, tag & push container image to ACR
 			containerDeployTask := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource)
 			syncProgress(task, containerDeployTask.Progress())
 
-			_, err := containerDeployTask.Await()
+			_, err = containerDeployTask.Await()
 			if err != nil {
 				task.SetError(err)
 				return
@@ -103,12 +220,21 @@ func (at *containerAppTarget) Deploy(
 				targetResource.ResourceGroupName(),
 				targetResource.ResourceName(),
 				imageName,
+				containerEnv,
+				probes,
+				scale,
 			)
 			if err != nil {
 				task.SetError(fmt.Errorf("updating container app service: %w", err))
 				return
 			}
 
+			revision, err := at.waitForRevision(ctx, task, serviceConfig, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			task.SetProgress(NewServiceProgress("Fetching endpoints for container app service"))
 			endpoints, err := at.Endpoints(ctx, serviceConfig, targetResource)
 			if err != nil {
@@ -125,11 +251,378 @@ func (at *containerAppTarget) Deploy(
 				),
 				Kind:      ContainerAppTarget,
 				Endpoints: endpoints,
+				Details: &containerAppDeployDetails{
+					endpoints: endpoints,
+					Scale:     revision.Scale,
+				},
 			})
 		},
 	)
 }
 
+// namedProbe is a health probe set by a service, with its type and its name in azure.yaml
+type namedProbe struct {
+	probeType armappcontainers.Type
+	name      string
+	probe     *ContainerAppProbe
+}
+
+// configuredProbes returns the health probes set by the service, in the order liveness, readiness and startup
+func configuredProbes(serviceConfig *ServiceConfig) []namedProbe {
+	var configured []namedProbe
+	for _, c := range []namedProbe{
+		{armappcontainers.TypeLiveness, "liveness", serviceConfig.ContainerApp.Probes.Liveness},
+		{armappcontainers.TypeReadiness, "readiness", serviceConfig.ContainerApp.Probes.Readiness},
+		{armappcontainers.TypeStartup, "startup", serviceConfig.ContainerApp.Probes.Startup},
+	} {
+		if c.probe != nil {
+			configured = append(configured, c)
+		}
+	}
+
+	return configured
+}
+
+// containerProbes returns the health probes of the container of the service, or nil when the service has none. The port
+// of a probe defaults to the target port of the ingress of the container app, a warning is shown when a probe targets
+// another port. The probes are validated by Initialize.
+func (at *containerAppTarget) containerProbes(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]containerapps.Probe, error) {
+	configured := configuredProbes(serviceConfig)
+	if len(configured) == 0 {
+		return nil, nil
+	}
+
+	ingressConfig, err := at.containerAppService.GetIngressConfiguration(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching ingress of container app: %w", err)
+	}
+
+	probes := make([]containerapps.Probe, 0, len(configured))
+	for _, c := range configured {
+		port := int32(c.probe.Port)
+		if port == 0 {
+			if ingressConfig.TargetPort == 0 {
+				return nil, fmt.Errorf(
+					"invalid %s probe of service '%s': the container app has no ingress, 'port' is required",
+					c.name, serviceConfig.Name)
+			}
+
+			port = ingressConfig.TargetPort
+		} else if ingressConfig.TargetPort != 0 && port != ingressConfig.TargetPort {
+			at.console.MessageUxItem(ctx, &ux.WarningMessage{
+				Description: fmt.Sprintf(
+					"the %s probe of service '%s' targets port %d, but the ingress of the container app targets port %d",
+					c.name, serviceConfig.Name, port, ingressConfig.TargetPort),
+			})
+		}
+
+		probes = append(probes, containerapps.Probe{
+			Type:                c.probeType,
+			Path:                c.probe.Path,
+			Port:                port,
+			InitialDelaySeconds: int32(c.probe.InitialDelay),
+			PeriodSeconds:       int32(c.probe.Interval),
+			FailureThreshold:    int32(c.probe.FailureThreshold),
+		})
+	}
+
+	return probes, nil
+}
+
+// validateProbe returns an error when a value of the probe is outside of the range supported by Azure Container Apps
+func validateProbe(probe *ContainerAppProbe) error {
+	if probe.Path != "" && !strings.HasPrefix(probe.Path, "/") {
+		return fmt.Errorf("'path' must start with '/', got '%s'", probe.Path)
+	}
+
+	ranges := []struct {
+		name  string
+		value int
+		min   int
+		max   int
+	}{
+		{"port", probe.Port, 1, 65535},
+		{"initialDelay", probe.InitialDelay, 1, 60},
+		{"interval", probe.Interval, 1, 240},
+		{"failureThreshold", probe.FailureThreshold, 1, 10},
+	}
+
+	for _, r := range ranges {
+		// 0 is not set, the default is used
+		if r.value != 0 && (r.value < r.min || r.value > r.max) {
+			return fmt.Errorf("'%s' must be between %d and %d, got %d", r.name, r.min, r.max, r.value)
+		}
+	}
+
+	return nil
+}
+
+// scaleRuleNameRegex matches the names of the scale rules of a container app
+var scaleRuleNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+// containerAppScale returns the scale settings of the service, or nil when the service has none
+func containerAppScale(serviceConfig *ServiceConfig) (*containerapps.Scale, error) {
+	config := serviceConfig.ContainerApp.Scale
+	if config.MinReplicas == nil && config.MaxReplicas == nil && config.Rules == nil {
+		return nil, nil
+	}
+
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("invalid scale of service '%s': %s", serviceConfig.Name, fmt.Sprintf(format, args...))
+	}
+
+	scale := &containerapps.Scale{}
+	if config.MinReplicas != nil {
+		if *config.MinReplicas < 0 || *config.MinReplicas > 1000 {
+			return nil, invalid("'minReplicas' must be between 0 and 1000, got %d", *config.MinReplicas)
+		}
+		scale.MinReplicas = convert.RefOf(int32(*config.MinReplicas))
+	}
+
+	if config.MaxReplicas != nil {
+		if *config.MaxReplicas < 1 || *config.MaxReplicas > 1000 {
+			return nil, invalid("'maxReplicas' must be between 1 and 1000, got %d", *config.MaxReplicas)
+		}
+		scale.MaxReplicas = convert.RefOf(int32(*config.MaxReplicas))
+	}
+
+	if config.MinReplicas != nil && config.MaxReplicas != nil && *config.MinReplicas > *config.MaxReplicas {
+		return nil, invalid("'minReplicas' (%d) must not be greater than 'maxReplicas' (%d)",
+			*config.MinReplicas, *config.MaxReplicas)
+	}
+
+	if config.Rules != nil {
+		scale.Rules = []containerapps.ScaleRule{}
+	}
+
+	names := map[string]struct{}{}
+	for _, rule := range config.Rules {
+		name := rule.Name
+		if name == "" {
+			name = rule.Type
+		}
+
+		if !scaleRuleNameRegex.MatchString(name) {
+			return nil, invalid(
+				"the name of rule '%s' may only contain lower case letters, digits, '-' and '.'", name)
+		}
+
+		if _, has := names[name]; has {
+			return nil, invalid("there is more than one rule named '%s'", name)
+		}
+		names[name] = struct{}{}
+
+		switch rule.Type {
+		case containerapps.ScaleRuleHttp:
+			if rule.ConcurrentRequests < 1 {
+				return nil, invalid("rule '%s' requires 'concurrentRequests' of at least 1", name)
+			}
+		case containerapps.ScaleRuleCpu, containerapps.ScaleRuleMemory:
+			if rule.Utilization < 1 || rule.Utilization > 100 {
+				return nil, invalid("rule '%s' requires 'utilization' between 1 and 100", name)
+			}
+		case containerapps.ScaleRuleAzureQueue:
+			if rule.QueueName == "" || rule.QueueLength < 1 || rule.SecretRef == "" {
+				return nil, invalid(
+					"rule '%s' requires 'queueName', 'secretRef' and 'queueLength' of at least 1", name)
+			}
+		default:
+			return nil, invalid("rule '%s' has unknown type '%s', the supported types are %s, %s, %s and %s",
+				name, rule.Type, containerapps.ScaleRuleHttp, containerapps.ScaleRuleCpu,
+				containerapps.ScaleRuleMemory, containerapps.ScaleRuleAzureQueue)
+		}
+
+		scale.Rules = append(scale.Rules, containerapps.ScaleRule{
+			Name:               name,
+			Type:               rule.Type,
+			ConcurrentRequests: int32(rule.ConcurrentRequests),
+			Utilization:        int32(rule.Utilization),
+			QueueName:          rule.QueueName,
+			QueueLength:        int32(rule.QueueLength),
+			SecretRef:          rule.SecretRef,
+		})
+	}
+
+	return scale, nil
+}
+
+// waitForRevision reports the state of the latest revision of the container app as progress until the revision is
+// provisioned and healthy. An error with the latest system logs of the container app is returned when the revision fails
+// to provision, or isn't healthy before the revision timeout of the service elapses. The healthy revision is returned.
+func (at *containerAppTarget) waitForRevision(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (*containerapps.ContainerAppRevision, error) {
+	timeout := defaultRevisionTimeout
+	if serviceConfig.ContainerApp.RevisionTimeout > 0 {
+		timeout = time.Duration(serviceConfig.ContainerApp.RevisionTimeout) * time.Second
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		revision, err := at.containerAppService.GetLatestRevision(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("fetching revision status: %w", err)
+		}
+
+		state := string(revision.ProvisioningState)
+		if revision.ProvisioningState == armappcontainers.RevisionProvisioningStateProvisioned {
+			state = string(revision.HealthState)
+		}
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Revision status: %s", state)))
+
+		switch {
+		case revision.ProvisioningState == armappcontainers.RevisionProvisioningStateFailed:
+			return nil, at.revisionError(ctx, targetResource, fmt.Errorf(
+				"revision '%s' failed to provision: %s", revision.Name, revision.ProvisioningError))
+		case revision.ProvisioningState != armappcontainers.RevisionProvisioningStateProvisioned:
+		case revision.HealthState == armappcontainers.RevisionHealthStateHealthy:
+			return revision, nil
+		// A revision without replicas, such as a revision scaled to zero, has no health state
+		case revision.HealthState == armappcontainers.RevisionHealthStateNone && revision.Replicas == 0:
+			return revision, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, at.revisionError(ctx, targetResource, fmt.Errorf(
+				"revision '%s' wasn't healthy after %s, its last status was '%s'", revision.Name, timeout, state))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(at.pollInterval):
+		}
+	}
+}
+
+// The defaults of Azure Container Apps for the scale settings that aren't set
+const (
+	defaultMinReplicas = 0
+	defaultMaxReplicas = 10
+)
+
+// containerAppDeployDetails are the details of a service deployed to a container app, which are shown in the deploy
+// summary
+type containerAppDeployDetails struct {
+	endpoints []string
+	// The scale settings of the deployed revision, nil when the revision uses the default settings
+	Scale *containerapps.Scale `json:"scale,omitempty"`
+}
+
+func (d *containerAppDeployDetails) ToString(currentIndentation string) string {
+	minReplicas, maxReplicas := int32(defaultMinReplicas), int32(defaultMaxReplicas)
+	var rules []string
+	if d.Scale != nil {
+		minReplicas = convert.ToValueWithDefault(d.Scale.MinReplicas, minReplicas)
+		maxReplicas = convert.ToValueWithDefault(d.Scale.MaxReplicas, maxReplicas)
+		for _, rule := range d.Scale.Rules {
+			rules = append(rules, scaleRuleString(rule))
+		}
+	}
+
+	scale := fmt.Sprintf("%s- Scale: %d to %d replicas", currentIndentation, minReplicas, maxReplicas)
+	if len(rules) > 0 {
+		scale += ", scaled on " + strings.Join(rules, ", ")
+	}
+
+	return endpointsString(currentIndentation, d.endpoints) + scale + "\n"
+}
+
+func (d *containerAppDeployDetails) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*d)
+}
+
+// scaleRuleString describes a scale rule for the deploy summary
+func scaleRuleString(rule containerapps.ScaleRule) string {
+	switch rule.Type {
+	case containerapps.ScaleRuleHttp:
+		return fmt.Sprintf("%s (%d concurrent requests)", rule.Name, rule.ConcurrentRequests)
+	case containerapps.ScaleRuleCpu, containerapps.ScaleRuleMemory:
+		return fmt.Sprintf("%s (%d%% %s utilization)", rule.Name, rule.Utilization, rule.Type)
+	case containerapps.ScaleRuleAzureQueue:
+		return fmt.Sprintf("%s (%d messages in queue %s)", rule.Name, rule.QueueLength, rule.QueueName)
+	default:
+		return fmt.Sprintf("%s (%s)", rule.Name, rule.Type)
+	}
+}
+
+// revisionError adds the latest system logs of the container app to the error of an unhealthy revision
+func (at *containerAppTarget) revisionError(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	err error,
+) error {
+	logs, logsErr := at.containerAppService.GetSystemLogs(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		revisionLogLines,
+	)
+	if logsErr != nil {
+		log.Printf("failed fetching system logs of container app '%s': %v", targetResource.ResourceName(), logsErr)
+		return err
+	}
+
+	if logs == "" {
+		return err
+	}
+
+	return fmt.Errorf("%w\n\nLatest system logs of the container app:\n%s", err, logs)
+}
+
+// DeployPreview returns the steps that push the image of the service and add a revision of the container app with it.
+func (at *containerAppTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	steps, image, err := at.containerHelper.DeployPreview(ctx, serviceConfig, packageOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	steps = append(steps, ServiceDeployStep{
+		Kind: DeployStepUpdate,
+		Description: fmt.Sprintf(
+			"Add a revision of container app %s with the image %s", targetResource.ResourceName(), image.Planned),
+	})
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.ContainerAppRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:  ContainerAppTarget,
+		Steps: steps,
+		Image: image,
+	}, nil
+}
+
 // Gets endpoint for the container app service
 func (at *containerAppTarget) Endpoints(
 	ctx context.Context,
@@ -5,6 +5,7 @@ package project
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
@@ -119,6 +121,362 @@ func Test_ContainerApp_Deploy(t *testing.T) {
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Dotenv()["SERVICE_API_IMAGE_NAME"])
 }
 
+func Test_ContainerApp_DeployPreview(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForContainerAppTarget(mockContext)
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).Respond(exec.NewRunResult(0, "IMAGE_ID", ""))
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, serviceConfig, env)
+
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(infra.AzureResourceTypeContainerApp),
+	)
+	plan, err := serviceTarget.DeployPreview(*mockContext.Context, serviceConfig, &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+	}, scope)
+
+	require.NoError(t, err)
+	require.Equal(t, ContainerAppTarget, plan.Kind)
+	require.Equal(t,
+		"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.App/containerApps/CONTAINER_APP",
+		plan.TargetResourceId)
+	require.Equal(t, ServiceDeployStep{
+		Kind:        DeployStepUpdate,
+		Description: "Add a revision of container app CONTAINER_APP with the image " + plan.Image.Planned,
+	}, plan.Steps[len(plan.Steps)-1])
+	// Nothing is deployed
+	require.Empty(t, env.Dotenv()["SERVICE_API_IMAGE_NAME"])
+}
+
+func Test_ContainerApp_Deploy_FailedRevision(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForContainerAppTarget(mockContext)
+
+	mockazsdk.MockContainerAppRevisionGet(
+		mockContext,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		"ORIGINAL_REVISION_NAME",
+		&armappcontainers.Revision{
+			Properties: &armappcontainers.RevisionProperties{
+				ProvisioningState: convert.RefOf(armappcontainers.RevisionProvisioningStateFailed),
+				ProvisioningError: convert.RefOf("Container crashing: api"),
+				Template: &armappcontainers.Template{
+					Containers: []*armappcontainers.Container{
+						{
+							Image: convert.RefOf("UPDATED_IMAGE_NAME"),
+						},
+					},
+				},
+			},
+		},
+	)
+	eventStreamRequest := mockazsdk.MockContainerAppEventStream(
+		mockContext,
+		containerAppEventStreamEndpoint,
+		`{"Msg":"Container 'api' was terminated with exit code '1'"}`,
+	)
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	env := createEnv()
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, serviceConfig, env)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(infra.AzureResourceTypeContainerApp),
+	)
+	packageOutput := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+
+	require.Nil(t, deployResult)
+	require.ErrorContains(t, err, "revision 'ORIGINAL_REVISION_NAME' failed to provision: Container crashing: api")
+	require.ErrorContains(t, err, "Container 'api' was terminated with exit code '1'")
+
+	require.Equal(t, "Bearer EVENT_STREAM_TOKEN", eventStreamRequest.Header.Get("Authorization"))
+	require.Equal(t, "20", eventStreamRequest.URL.Query().Get("tailLines"))
+}
+
+func Test_ContainerApp_Deploy_Probes(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForContainerAppTarget(mockContext)
+	updateRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP", &armappcontainers.ContainerApp{})
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.ContainerApp.Probes = ContainerAppProbes{
+		Liveness:  &ContainerAppProbe{Path: "/healthz", InitialDelay: 5, Interval: 30, FailureThreshold: 5},
+		Readiness: &ContainerAppProbe{Port: 9090},
+	}
+	env := createEnv()
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, serviceConfig, env)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(infra.AzureResourceTypeContainerApp),
+	)
+	packageOutput := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	_, err := deployTask.Await()
+	require.NoError(t, err)
+
+	var updated armappcontainers.ContainerApp
+	require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&updated))
+
+	probes := updated.Properties.Template.Containers[0].Probes
+	require.Len(t, probes, 2)
+
+	// the liveness probe defaults to the target port of the ingress
+	require.Equal(t, armappcontainers.TypeLiveness, *probes[0].Type)
+	require.Equal(t, "/healthz", *probes[0].HTTPGet.Path)
+	require.Equal(t, int32(8080), *probes[0].HTTPGet.Port)
+	require.Equal(t, int32(5), *probes[0].InitialDelaySeconds)
+	require.Equal(t, int32(30), *probes[0].PeriodSeconds)
+	require.Equal(t, int32(5), *probes[0].FailureThreshold)
+
+	require.Equal(t, armappcontainers.TypeReadiness, *probes[1].Type)
+	require.Equal(t, int32(9090), *probes[1].TCPSocket.Port)
+
+	require.Contains(t, strings.Join(mockContext.Console.Output(), "\n"),
+		"the readiness probe of service 'api' targets port 9090, but the ingress of the container app targets port 8080")
+}
+
+func Test_ContainerApp_Initialize_InvalidProbe(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForContainerAppTarget(mockContext)
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.ContainerApp.Probes = ContainerAppProbes{
+		Readiness: &ContainerAppProbe{Path: "ready"},
+	}
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, serviceConfig, createEnv())
+	err := serviceTarget.Initialize(*mockContext.Context, serviceConfig)
+	require.ErrorContains(t, err, "invalid readiness probe of service 'api': 'path' must start with '/'")
+}
+
+func Test_validateProbe(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe ContainerAppProbe
+		err   string
+	}{
+		{"Defaults", ContainerAppProbe{}, ""},
+		{"Valid", ContainerAppProbe{Path: "/ready", Port: 80, InitialDelay: 60, Interval: 240, FailureThreshold: 10}, ""},
+		{"RelativePath", ContainerAppProbe{Path: "ready"}, "'path' must start with '/'"},
+		{"Port", ContainerAppProbe{Port: 70000}, "'port' must be between 1 and 65535"},
+		{"InitialDelay", ContainerAppProbe{InitialDelay: 61}, "'initialDelay' must be between 1 and 60"},
+		{"Interval", ContainerAppProbe{Interval: -1}, "'interval' must be between 1 and 240"},
+		{"FailureThreshold", ContainerAppProbe{FailureThreshold: 11}, "'failureThreshold' must be between 1 and 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProbe(&tt.probe)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_ContainerApp_Deploy_Scale(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForContainerAppTarget(mockContext)
+	updateRequest := mockazsdk.MockContainerAppUpdate(
+		mockContext, "SUBSCRIPTION_ID", "RESOURCE_GROUP", "CONTAINER_APP", &armappcontainers.ContainerApp{})
+	mockazsdk.MockContainerAppRevisionGet(
+		mockContext,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		"ORIGINAL_REVISION_NAME",
+		&armappcontainers.Revision{
+			Properties: &armappcontainers.RevisionProperties{
+				ProvisioningState: convert.RefOf(armappcontainers.RevisionProvisioningStateProvisioned),
+				HealthState:       convert.RefOf(armappcontainers.RevisionHealthStateHealthy),
+				Replicas:          convert.RefOf[int32](1),
+				Template: &armappcontainers.Template{
+					Containers: []*armappcontainers.Container{
+						{
+							Image: convert.RefOf("UPDATED_IMAGE_NAME"),
+						},
+					},
+					Scale: &armappcontainers.Scale{
+						MinReplicas: convert.RefOf[int32](1),
+						MaxReplicas: convert.RefOf[int32](5),
+						Rules: []*armappcontainers.ScaleRule{
+							{
+								Name: convert.RefOf("http"),
+								HTTP: &armappcontainers.HTTPScaleRule{
+									Metadata: map[string]*string{"concurrentRequests": convert.RefOf("50")},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	serviceConfig := createTestServiceConfig(tempDir, ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.ContainerApp.Scale = ContainerAppScale{
+		MinReplicas: convert.RefOf(1),
+		MaxReplicas: convert.RefOf(5),
+		Rules: []ContainerAppScaleRule{
+			{Type: "http", ConcurrentRequests: 50},
+		},
+	}
+	env := createEnv()
+
+	serviceTarget := createContainerAppServiceTarget(mockContext, serviceConfig, env)
+	scope := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		string(infra.AzureResourceTypeContainerApp),
+	)
+	packageOutput := &ServicePackageResult{
+		PackagePath: "test-app/api-test:azd-deploy-0",
+		Details: &dockerPackageResult{
+			ImageHash: "IMAGE_HASH",
+			ImageTag:  "test-app/api-test:azd-deploy-0",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+	require.NoError(t, err)
+
+	var updated armappcontainers.ContainerApp
+	require.NoError(t, json.NewDecoder(updateRequest.Body).Decode(&updated))
+
+	scale := updated.Properties.Template.Scale
+	require.Equal(t, int32(1), *scale.MinReplicas)
+	require.Equal(t, int32(5), *scale.MaxReplicas)
+	require.Len(t, scale.Rules, 1)
+	require.Equal(t, "http", *scale.Rules[0].Name)
+	require.Equal(t, "50", *scale.Rules[0].HTTP.Metadata["concurrentRequests"])
+
+	// the summary shows the scale settings of the deployed revision
+	summary := deployResult.ToString("")
+	require.Contains(t, summary, "- Endpoint: ")
+	require.Contains(t, summary, "- Scale: 1 to 5 replicas, scaled on http (50 concurrent requests)")
+}
+
+func Test_containerAppScale(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale ContainerAppScale
+		err   string
+	}{
+		{"MinOnly", ContainerAppScale{MinReplicas: convert.RefOf(0)}, ""},
+		{"Rules", ContainerAppScale{
+			MinReplicas: convert.RefOf(1),
+			MaxReplicas: convert.RefOf(10),
+			Rules: []ContainerAppScaleRule{
+				{Type: "http", ConcurrentRequests: 100},
+				{Name: "cpu-high", Type: "cpu", Utilization: 80},
+				{Type: "memory", Utilization: 75},
+				{Type: "azure-queue", QueueName: "orders", QueueLength: 10, SecretRef: "queue-connection"},
+			},
+		}, ""},
+		{"MinGreaterThanMax", ContainerAppScale{MinReplicas: convert.RefOf(5), MaxReplicas: convert.RefOf(2)},
+			"'minReplicas' (5) must not be greater than 'maxReplicas' (2)"},
+		{"NegativeMin", ContainerAppScale{MinReplicas: convert.RefOf(-1)}, "'minReplicas' must be between 0 and 1000"},
+		{"ZeroMax", ContainerAppScale{MaxReplicas: convert.RefOf(0)}, "'maxReplicas' must be between 1 and 1000"},
+		{"UnknownType", ContainerAppScale{Rules: []ContainerAppScaleRule{{Type: "redis"}}},
+			"rule 'redis' has unknown type 'redis'"},
+		{"DuplicateName", ContainerAppScale{Rules: []ContainerAppScaleRule{
+			{Type: "cpu", Utilization: 50},
+			{Name: "cpu", Type: "memory", Utilization: 50},
+		}}, "there is more than one rule named 'cpu'"},
+		{"InvalidName", ContainerAppScale{Rules: []ContainerAppScaleRule{
+			{Name: "Http_Rule", Type: "http", ConcurrentRequests: 10},
+		}}, "the name of rule 'Http_Rule' may only contain"},
+		{"HttpConcurrency", ContainerAppScale{Rules: []ContainerAppScaleRule{{Type: "http"}}},
+			"rule 'http' requires 'concurrentRequests' of at least 1"},
+		{"Utilization", ContainerAppScale{Rules: []ContainerAppScaleRule{{Type: "cpu", Utilization: 101}}},
+			"rule 'cpu' requires 'utilization' between 1 and 100"},
+		{"Queue", ContainerAppScale{Rules: []ContainerAppScaleRule{{Type: "azure-queue", QueueName: "orders"}}},
+			"rule 'azure-queue' requires 'queueName', 'secretRef' and 'queueLength' of at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceConfig := &ServiceConfig{
+				Name:         "api",
+				ContainerApp: ContainerAppOptions{Scale: tt.scale},
+			}
+
+			scale, err := containerAppScale(serviceConfig)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, scale)
+			require.Len(t, scale.Rules, len(tt.scale.Rules))
+			for i, rule := range scale.Rules {
+				require.NotEmpty(t, rule.Name)
+				require.Equal(t, tt.scale.Rules[i].Type, rule.Type)
+			}
+		})
+	}
+
+	scale, err := containerAppScale(&ServiceConfig{Name: "api"})
+	require.NoError(t, err)
+	require.Nil(t, scale)
+}
+
 func createContainerAppServiceTarget(
 	mockContext *mocks.MockContext,
 	serviceConfig *ServiceConfig,
@@ -135,12 +493,14 @@ func createContainerAppServiceTarget(
 
 	containerAppService := containerapps.NewContainerAppService(credentialProvider, mockContext.HttpClient, clock.NewMock())
 	containerRegistryService := azcli.NewContainerRegistryService(credentialProvider, mockContext.HttpClient, dockerCli)
-	containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), containerRegistryService, dockerCli)
+	containerHelper := NewContainerHelper(
+		env, envManager, clock.NewMock(), containerRegistryService, dockerCli, nil, mockContext.Console)
 	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
 	depOpService := mockazcli.NewDeploymentOperationsServiceFromMockContext(mockContext)
 	resourceManager := NewResourceManager(env, azCli, depOpService)
 
 	return NewContainerAppTarget(
+		mockContext.Console,
 		env,
 		envManager,
 		containerHelper,
@@ -149,6 +509,9 @@ func createContainerAppServiceTarget(
 	)
 }
 
+//nolint:lll
+const containerAppEventStreamEndpoint = "https://eastus2.azurecontainerapps.dev/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/containerApps/CONTAINER_APP/eventstream"
+
 func setupMocksForContainerAppTarget(mockContext *mocks.MockContext) {
 	setupMocksForDocker(mockContext)
 	setupMocksForAcr(mockContext)
@@ -169,7 +532,8 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 		Location: &location,
 		Name:     &appName,
 		Properties: &armappcontainers.ContainerAppProperties{
-			LatestRevisionName: &originalRevisionName,
+			LatestRevisionName:  &originalRevisionName,
+			EventStreamEndpoint: convert.RefOf(containerAppEventStreamEndpoint),
 			Configuration: &armappcontainers.Configuration{
 				ActiveRevisionsMode: convert.RefOf(armappcontainers.ActiveRevisionsModeSingle),
 				Secrets: []*armappcontainers.Secret{
@@ -179,7 +543,8 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 					},
 				},
 				Ingress: &armappcontainers.Ingress{
-					Fqdn: &hostName,
+					Fqdn:       &hostName,
+					TargetPort: convert.RefOf[int32](8080),
 				},
 			},
 			Template: &armappcontainers.Template{
@@ -194,6 +559,9 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 
 	revision := &armappcontainers.Revision{
 		Properties: &armappcontainers.RevisionProperties{
+			ProvisioningState: convert.RefOf(armappcontainers.RevisionProvisioningStateProvisioned),
+			HealthState:       convert.RefOf(armappcontainers.RevisionHealthStateHealthy),
+			Replicas:          convert.RefOf[int32](1),
 			Template: &armappcontainers.Template{
 				Containers: []*armappcontainers.Container{
 					{
@@ -220,4 +588,6 @@ func setupMocksForContainerApps(mockContext *mocks.MockContext) {
 	mockazsdk.MockContainerAppSecretsList(mockContext, subscriptionId, resourceGroup, appName, secrets)
 	mockazsdk.MockContainerAppUpdate(mockContext, subscriptionId, resourceGroup, appName, containerApp)
 	mockazsdk.MockContainerRegistryTokenExchange(mockContext, subscriptionId, subscriptionId, "REFRESH_TOKEN")
+	mockazsdk.MockContainerAppGetAuthToken(mockContext, subscriptionId, resourceGroup, appName, "EVENT_STREAM_TOKEN")
+	mockazsdk.MockContainerAppEventStream(mockContext, containerAppEventStreamEndpoint, "")
 }
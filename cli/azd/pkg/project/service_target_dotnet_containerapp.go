@@ -64,7 +64,7 @@ func (at *dotnetContainerAppTarget) RequiredExternalTools(ctx context.Context) [
 
 // Initializes the Container App target
 func (at *dotnetContainerAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
-	return nil
+	return validateServiceEnvSupported(serviceConfig)
 }
 
 // Prepares and tags the container image from the build output based on the specified service configuration
@@ -221,6 +221,43 @@ func (at *dotnetContainerAppTarget) Deploy(
 	)
 }
 
+// DeployPreview returns the steps that publish the image of the project to the registry and deploy the container app
+// from its manifest. The image is built by dotnet publish, so it can't be compared with the current image.
+func (at *dotnetContainerAppTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	loginServer, err := at.containerHelper.RegistryName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.ContainerAppRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			serviceConfig.Name,
+		),
+		Kind: ContainerAppTarget,
+		Steps: []ServiceDeployStep{
+			{
+				Kind:        DeployStepBuild,
+				Description: fmt.Sprintf("Build and push the image to registry %s with dotnet publish", loginServer),
+			},
+			{
+				Kind:        DeployStepUpdate,
+				Description: fmt.Sprintf("Deploy container app %s from its manifest", serviceConfig.Name),
+			},
+		},
+	}, nil
+}
+
 // Gets endpoint for the container app service
 func (at *dotnetContainerAppTarget) Endpoints(
 	ctx context.Context,
@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
@@ -17,6 +18,16 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
+// The Azure Functions configuration options
+type FunctionsOptions struct {
+	// The hosting plan of the function app. When set to flex, the service is deployed to a function app hosted on the
+	// Flex Consumption plan. When omitted, the plan is inferred from the SKU of the App Service plan of the function app.
+	Plan string `yaml:"plan,omitempty"`
+}
+
+// The value of `functions.plan` of functions deployed to the Flex Consumption plan
+const FunctionsPlanFlex = "flex"
+
 // functionAppTarget specifies an Azure Function to deploy to.
 // Implements `project.ServiceTarget`
 type functionAppTarget struct {
@@ -42,6 +53,15 @@ func (f *functionAppTarget) RequiredExternalTools(context.Context) []tools.Exter
 
 // Initializes the function app target
 func (f *functionAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	if plan := serviceConfig.Functions.Plan; plan != "" && plan != FunctionsPlanFlex {
+		return fmt.Errorf(
+			"service '%s' has invalid 'functions.plan' '%s', the supported value is '%s'",
+			serviceConfig.Name,
+			plan,
+			FunctionsPlanFlex,
+		)
+	}
+
 	return nil
 }
 
@@ -86,6 +106,14 @@ func (f *functionAppTarget) Deploy(
 				return
 			}
 
+			if len(serviceConfig.Env) > 0 {
+				task.SetProgress(NewServiceProgress("Updating app settings"))
+				if err := updateAppSettings(ctx, f.cli, f.env, serviceConfig, targetResource); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			zipFile, err := os.Open(packageOutput.PackagePath)
 			if err != nil {
 				task.SetError(fmt.Errorf("failed reading deployment zip file: %w", err))
@@ -95,14 +123,32 @@ func (f *functionAppTarget) Deploy(
 			defer os.Remove(packageOutput.PackagePath)
 			defer zipFile.Close()
 
+			flex, err := f.isFlexConsumption(ctx, serviceConfig, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			task.SetProgress(NewServiceProgress("Uploading deployment package"))
-			res, err := f.cli.DeployFunctionAppUsingZipFile(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				targetResource.ResourceName(),
-				zipFile,
-			)
+			var res *string
+			if flex {
+				res, err = f.cli.DeployFunctionAppUsingZipFileFlexConsumption(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					zipFile,
+					functionsRemoteBuild(serviceConfig),
+				)
+			} else {
+				res, err = f.cli.DeployFunctionAppUsingZipFile(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					zipFile,
+				)
+			}
 			if err != nil {
 				task.SetError(err)
 				return
@@ -132,6 +178,28 @@ func (f *functionAppTarget) Deploy(
 	)
 }
 
+// DeployPreview returns the steps that update the app settings and deploy the zip package to the function app.
+func (f *functionAppTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := f.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.WebsiteRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind:  AzureFunctionTarget,
+		Steps: zipDeploySteps(serviceConfig, packageOutput, "function app", targetResource.ResourceName()),
+	}, nil
+}
+
 // Gets the exposed endpoints for the Function App
 func (f *functionAppTarget) Endpoints(
 	ctx context.Context,
@@ -157,6 +225,56 @@ func (f *functionAppTarget) Endpoints(
 	}
 }
 
+// isFlexConsumption returns true when the function app is hosted on the Flex Consumption plan, which has its own
+// deployment api. An error is returned when `functions.plan` is set to flex and the function app is hosted on another
+// plan, or its plan can't be retrieved.
+func (f *functionAppTarget) isFlexConsumption(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (bool, error) {
+	tier, err := f.cli.GetFunctionAppPlanTier(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		if serviceConfig.Functions.Plan == FunctionsPlanFlex {
+			return false, fmt.Errorf("getting the plan of function app '%s': %w", targetResource.ResourceName(), err)
+		}
+
+		// the plan is only needed to detect Flex Consumption apps, other apps are deployed with zip deploy
+		log.Printf("getting the plan of function app '%s', using zip deploy: %v", targetResource.ResourceName(), err)
+		return false, nil
+	}
+
+	flex := strings.EqualFold(tier, azcli.FunctionAppPlanTierFlexConsumption)
+	if serviceConfig.Functions.Plan == FunctionsPlanFlex && !flex {
+		return false, fmt.Errorf(
+			"service '%s' sets 'functions.plan' to '%s', but function app '%s' is hosted on a '%s' plan instead of "+
+				"a Flex Consumption plan",
+			serviceConfig.Name,
+			FunctionsPlanFlex,
+			targetResource.ResourceName(),
+			tier,
+		)
+	}
+
+	return flex, nil
+}
+
+// functionsRemoteBuild returns true when the deployment endpoint of a Flex Consumption app builds the service, which
+// installs the dependencies of JavaScript, TypeScript and Python apps.
+func functionsRemoteBuild(serviceConfig *ServiceConfig) bool {
+	switch serviceConfig.Language {
+	case ServiceLanguageJavaScript, ServiceLanguageTypeScript, ServiceLanguagePython:
+		return true
+	default:
+		return false
+	}
+}
+
 func (f *functionAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
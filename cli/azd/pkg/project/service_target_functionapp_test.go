@@ -5,12 +5,21 @@ package project
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,3 +58,155 @@ func TestNewFunctionAppTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_FunctionApp_Initialize_Plan(t *testing.T) {
+	serviceTarget := &functionAppTarget{}
+	serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguageTypeScript)
+
+	serviceConfig.Functions.Plan = FunctionsPlanFlex
+	require.NoError(t, serviceTarget.Initialize(context.Background(), serviceConfig))
+
+	serviceConfig.Functions.Plan = "premium"
+	require.ErrorContains(t, serviceTarget.Initialize(context.Background(), serviceConfig), "invalid 'functions.plan'")
+}
+
+func Test_FunctionApp_Deploy(t *testing.T) {
+	tests := map[string]struct {
+		plan        string
+		tier        string
+		expectFlex  bool
+		expectError string
+	}{
+		"FlexConsumption": {
+			tier:       azcli.FunctionAppPlanTierFlexConsumption,
+			expectFlex: true,
+		},
+		"FlexConsumptionPlan": {
+			plan:       FunctionsPlanFlex,
+			tier:       azcli.FunctionAppPlanTierFlexConsumption,
+			expectFlex: true,
+		},
+		"Consumption": {
+			tier: "Dynamic",
+		},
+		"FlexPlanNotFlexConsumption": {
+			plan: FunctionsPlanFlex,
+			tier: "Dynamic",
+			expectError: "service 'api' sets 'functions.plan' to 'flex', but function app 'FUNC_APP' is hosted on a " +
+				"'Dynamic' plan instead of a Flex Consumption plan",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			published, zipDeployed := setupMocksForFunctionApp(mockContext, tt.tier)
+
+			serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguageTypeScript)
+			serviceConfig.Functions.Plan = tt.plan
+			serviceTarget := NewFunctionAppTarget(createEnv(), mockazcli.NewAzCliFromMockContext(mockContext))
+
+			zipPath := filepath.Join(t.TempDir(), "api.zip")
+			require.NoError(t, os.WriteFile(zipPath, []byte("zip"), osutil.PermissionFile))
+
+			scope := environment.NewTargetResource(
+				"SUBSCRIPTION_ID",
+				"RESOURCE_GROUP",
+				"FUNC_APP",
+				string(infra.AzureResourceTypeWebSite),
+			)
+			deployTask := serviceTarget.Deploy(
+				*mockContext.Context, serviceConfig, &ServicePackageResult{PackagePath: zipPath}, scope)
+			logProgress(deployTask)
+			deployResult, err := deployTask.Await()
+
+			if tt.expectError != "" {
+				require.EqualError(t, err, tt.expectError)
+				require.False(t, *published)
+				require.False(t, *zipDeployed)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, AzureFunctionTarget, deployResult.Kind)
+			require.Equal(t, []string{"https://FUNC_APP.azurewebsites.net/"}, deployResult.Endpoints)
+			require.Equal(t, tt.expectFlex, *published)
+			require.Equal(t, !tt.expectFlex, *zipDeployed)
+		})
+	}
+}
+
+// setupMocksForFunctionApp registers a function app hosted on an App Service plan with the given SKU tier, returning
+// whether the app was deployed with the Flex Consumption publish api or with zip deploy
+func setupMocksForFunctionApp(mockContext *mocks.MockContext, tier string) (published *bool, zipDeployed *bool) {
+	published = new(bool)
+	zipDeployed = new(bool)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Web/sites/FUNC_APP")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.Site{
+			Name: convert.RefOf("FUNC_APP"),
+			Properties: &armappservice.SiteProperties{
+				DefaultHostName: convert.RefOf("FUNC_APP.azurewebsites.net"),
+				ServerFarmID: convert.RefOf(
+					"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/serverfarms/PLAN"),
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Web/serverfarms/PLAN")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armappservice.Plan{
+			Name: convert.RefOf("PLAN"),
+			SKU:  &armappservice.SKUDescription{Tier: convert.RefOf(tier)},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Host == "FUNC_APP.scm.azurewebsites.net" &&
+			request.URL.Path == "/api/publish"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*published = true
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "DEPLOYMENT_ID")
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == "/api/deployments/DEPLOYMENT_ID"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DeployStatus{
+			Id:         "DEPLOYMENT_ID",
+			Status:     azsdk.DeployStatusSuccess,
+			StatusText: "Deployment Complete",
+			Complete:   true,
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Path == "/api/zipdeploy"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*zipDeployed = true
+		response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+		response.Header.Set("Location", "https://FUNC_APP.scm.azurewebsites.net/deployments/latest")
+
+		return response, nil
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == "/deployments/latest"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DeployStatusResponse{
+			DeployStatus: azsdk.DeployStatus{
+				Id:         "ID",
+				Status:     http.StatusOK,
+				StatusText: "OK",
+				Complete:   true,
+			},
+		})
+	})
+
+	return published, zipDeployed
+}
@@ -55,7 +55,7 @@ func (st *springAppTarget) RequiredExternalTools(context.Context) []tools.Extern
 }
 
 func (st *springAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
-	return nil
+	return validateServiceEnvSupported(serviceConfig)
 }
 
 // Do nothing for Spring Apps
@@ -185,6 +185,43 @@ func (st *springAppTarget) Deploy(
 	)
 }
 
+// DeployPreview returns the steps that upload the jar of the service and deploy it to the deployment of the spring app.
+func (st *springAppTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	deploymentName := serviceConfig.Spring.DeploymentName
+	if deploymentName == "" {
+		deploymentName = defaultDeploymentName
+	}
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.SpringAppRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind: SpringAppTarget,
+		Steps: []ServiceDeployStep{
+			packageDeployStep(packageOutput),
+			{
+				Kind: DeployStepUpdate,
+				Description: fmt.Sprintf(
+					"Upload the jar and deploy it to deployment %s of app %s of Azure Spring Apps instance %s",
+					deploymentName,
+					serviceConfig.Name,
+					targetResource.ResourceName()),
+			},
+		},
+	}, nil
+}
+
 // Gets the exposed endpoints for the Spring Apps Service
 func (st *springAppTarget) Endpoints(
 	ctx context.Context,
@@ -49,7 +49,7 @@ func (at *staticWebAppTarget) RequiredExternalTools(context.Context) []tools.Ext
 
 // Initializes the static web app target
 func (at *staticWebAppTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
-	return nil
+	return validateServiceEnvSupported(serviceConfig)
 }
 
 // Sets the build output that will be consumed for the deploy operation
@@ -150,6 +150,37 @@ func (at *staticWebAppTarget) Deploy(
 	)
 }
 
+// DeployPreview returns the steps that deploy the build output of the service to the static web app with the SWA CLI.
+func (at *staticWebAppTarget) DeployPreview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) (*ServiceDeployPlan, error) {
+	if err := at.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return nil, fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return &ServiceDeployPlan{
+		TargetResourceId: azure.StaticWebAppRID(
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		),
+		Kind: StaticWebAppTarget,
+		Steps: []ServiceDeployStep{
+			packageDeployStep(packageOutput),
+			{
+				Kind: DeployStepUpdate,
+				Description: fmt.Sprintf(
+					"Deploy the build output to the %s environment of static web app %s with the SWA CLI",
+					DefaultStaticWebAppEnvironmentName,
+					targetResource.ResourceName()),
+			},
+		},
+	}, nil
+}
+
 // Gets the endpoints for the static web app
 func (at *staticWebAppTarget) Endpoints(
 	ctx context.Context,
@@ -0,0 +1,186 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/benbjohnson/clock"
+)
+
+// SuggestionSource lists the values that are suggested while the user types the response to a prompt.
+type SuggestionSource func(ctx context.Context) ([]string, error)
+
+// The time to wait before listing suggestions again after listing them failed, which doubles with each failure up to
+// maxSuggestionRetryDelay.
+const (
+	minSuggestionRetryDelay = time.Second
+	maxSuggestionRetryDelay = 30 * time.Second
+)
+
+// NewSuggester returns a function to set as input.ConsoleOptions.Suggest, which suggests the values of source that start
+// with the input, ignoring case.
+//
+// The values are listed when suggestions are first requested, and reused for the suggestions requested after it. Nothing
+// is suggested while the values are being listed. When listing the values fails, the error is logged and nothing is
+// suggested until a retry delay has passed, after which the values are listed again.
+func NewSuggester(ctx context.Context, source SuggestionSource) func(input string) []string {
+	return newSuggester(ctx, source, clock.New()).suggest
+}
+
+type suggester struct {
+	ctx    context.Context
+	source SuggestionSource
+	clock  clock.Clock
+
+	mu sync.Mutex
+	// the sorted values of source, valid when listed is true
+	values  []string
+	listed  bool
+	listing bool
+	// when the values are listed again after listing them failed, and the delay before the next retry
+	retryAt    time.Time
+	retryDelay time.Duration
+}
+
+func newSuggester(ctx context.Context, source SuggestionSource, clock clock.Clock) *suggester {
+	return &suggester{
+		ctx:    ctx,
+		source: source,
+		clock:  clock,
+	}
+}
+
+func (s *suggester) suggest(input string) []string {
+	values := s.list()
+
+	prefix := strings.ToLower(input)
+	suggestions := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(strings.ToLower(value), prefix) {
+			suggestions = append(suggestions, value)
+		}
+	}
+
+	return suggestions
+}
+
+// list returns the values of the source, listing them when they haven't been listed yet. The lock isn't held while the
+// values are listed, so that concurrent suggestions return immediately instead of waiting for the listing.
+func (s *suggester) list() []string {
+	s.mu.Lock()
+	if s.listed || s.listing || s.clock.Now().Before(s.retryAt) {
+		defer s.mu.Unlock()
+		return s.values
+	}
+	s.listing = true
+	s.mu.Unlock()
+
+	all, err := s.source(s.ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listing = false
+
+	if err != nil {
+		s.retryDelay = min(max(2*s.retryDelay, minSuggestionRetryDelay), maxSuggestionRetryDelay)
+		s.retryAt = s.clock.Now().Add(s.retryDelay)
+		log.Printf("listing suggestions, retrying in %s: %v", s.retryDelay, err)
+		return nil
+	}
+
+	values := slices.Clone(all)
+	slices.Sort(values)
+	s.values = slices.Compact(values)
+	s.listed = true
+	return s.values
+}
+
+// SubscriptionSuggestions returns a SuggestionSource of the IDs of the subscriptions the account has access to.
+func SubscriptionSuggestions(accountManager account.Manager) SuggestionSource {
+	return func(ctx context.Context) ([]string, error) {
+		subscriptions, err := accountManager.GetSubscriptions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing subscriptions: %w", err)
+		}
+
+		ids := make([]string, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			ids = append(ids, subscription.Id)
+		}
+
+		return ids, nil
+	}
+}
+
+// ResourceGroupSuggestions returns a SuggestionSource of the names of the resource groups of the subscription.
+func ResourceGroupSuggestions(azCli azcli.AzCli, subscriptionId string) SuggestionSource {
+	return func(ctx context.Context) ([]string, error) {
+		groups, err := azCli.ListResourceGroup(ctx, subscriptionId, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing resource groups: %w", err)
+		}
+
+		names := make([]string, 0, len(groups))
+		for _, group := range groups {
+			names = append(names, group.Name)
+		}
+
+		return names, nil
+	}
+}
+
+// ResourceSuggestions returns a SuggestionSource of the names of the resources of the subscription with the resource type,
+// for example 'Microsoft.KeyVault/vaults'. The resources are queried with Azure Resource Graph.
+func ResourceSuggestions(
+	client *armresourcegraph.Client,
+	subscriptionId string,
+	resourceType string,
+) SuggestionSource {
+	return func(ctx context.Context) ([]string, error) {
+		query := fmt.Sprintf("Resources | where type =~ '%s' | project name", strings.ReplaceAll(resourceType, "'", ""))
+		request := armresourcegraph.QueryRequest{
+			Query:         &query,
+			Subscriptions: []*string{&subscriptionId},
+			Options: &armresourcegraph.QueryRequestOptions{
+				ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+			},
+		}
+
+		names := []string{}
+		for {
+			res, err := client.Resources(ctx, request, nil)
+			if err != nil {
+				return nil, fmt.Errorf("querying resources of type %s: %w", resourceType, err)
+			}
+
+			rows, ok := res.Data.([]any)
+			if !ok {
+				return nil, errors.New("error converting data to list")
+			}
+
+			for _, row := range rows {
+				if resource, ok := row.(map[string]any); ok {
+					if name, ok := resource["name"].(string); ok {
+						names = append(names, name)
+					}
+				}
+			}
+
+			if res.SkipToken == nil || *res.SkipToken == "" {
+				return names, nil
+			}
+
+			request.Options.SkipToken = res.SkipToken
+		}
+	}
+}
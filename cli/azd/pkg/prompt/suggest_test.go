@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewSuggester(t *testing.T) {
+	t.Run("PrefixFilter", func(t *testing.T) {
+		suggest := NewSuggester(context.Background(), func(ctx context.Context) ([]string, error) {
+			return []string{"rg-web", "RG-api", "other", "rg-web"}, nil
+		})
+
+		require.Equal(t, []string{"RG-api", "rg-web"}, suggest("rg-"))
+		require.Equal(t, []string{"RG-api"}, suggest("Rg-A"))
+		require.Equal(t, []string{"RG-api", "other", "rg-web"}, suggest(""))
+		require.Empty(t, suggest("missing"))
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		calls := 0
+		suggest := NewSuggester(context.Background(), func(ctx context.Context) ([]string, error) {
+			calls++
+			return []string{"rg-web"}, nil
+		})
+
+		require.Equal(t, []string{"rg-web"}, suggest("rg"))
+		require.Equal(t, []string{"rg-web"}, suggest("rg-w"))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		calls := 0
+		mockClock := clock.NewMock()
+		suggest := newSuggester(context.Background(), func(ctx context.Context) ([]string, error) {
+			calls++
+			if calls <= 2 {
+				return nil, errors.New("not signed in")
+			}
+
+			return []string{"rg-web"}, nil
+		}, mockClock).suggest
+
+		require.Empty(t, suggest("rg"))
+		// the values aren't listed again until the retry delay has passed
+		require.Empty(t, suggest("rg"))
+		require.Equal(t, 1, calls)
+
+		mockClock.Add(minSuggestionRetryDelay)
+		require.Empty(t, suggest("rg"))
+		require.Equal(t, 2, calls)
+
+		// the delay doubles after each failure
+		mockClock.Add(minSuggestionRetryDelay)
+		require.Empty(t, suggest("rg"))
+		require.Equal(t, 2, calls)
+
+		mockClock.Add(minSuggestionRetryDelay)
+		require.Equal(t, []string{"rg-web"}, suggest("rg"))
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("Listing", func(t *testing.T) {
+		listing := make(chan struct{})
+		done := make(chan struct{})
+		suggest := NewSuggester(context.Background(), func(ctx context.Context) ([]string, error) {
+			close(listing)
+			<-done
+			return []string{"rg-web"}, nil
+		})
+
+		result := make(chan []string)
+		go func() {
+			result <- suggest("rg")
+		}()
+
+		// suggestions requested while the values are listed return immediately
+		<-listing
+		require.Empty(t, suggest("rg"))
+
+		close(done)
+		require.Equal(t, []string{"rg-web"}, <-result)
+	})
+}
+
+func Test_SubscriptionSuggestions(t *testing.T) {
+	mockAccount := &mockaccount.MockAccountManager{
+		Subscriptions: []account.Subscription{
+			{Id: "SUBSCRIPTION_1", Name: "sub1"},
+			{Id: "SUBSCRIPTION_2", Name: "sub2"},
+		},
+	}
+
+	ids, err := SubscriptionSuggestions(mockAccount)(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"SUBSCRIPTION_1", "SUBSCRIPTION_2"}, ids)
+}
+
+func Test_ResourceSuggestions(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	var queries []string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.Contains(request.URL.Path, "providers/Microsoft.ResourceGraph/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, string(body))
+
+		response := armresourcegraph.ClientResourcesResponse{
+			QueryResponse: armresourcegraph.QueryResponse{
+				Data: []any{map[string]any{"name": "kv-web"}},
+			},
+		}
+
+		// the first page links to the second page
+		if len(queries) == 1 {
+			response.SkipToken = to.Ptr("PAGE_2")
+			response.Data = []any{map[string]any{"name": "kv-api"}}
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	armOptions := azsdk.
+		DefaultClientOptionsBuilder(*mockContext.Context, mockContext.HttpClient, "azd").
+		BuildArmClientOptions()
+
+	client, err := armresourcegraph.NewClient(mockContext.Credentials, armOptions)
+	require.NoError(t, err)
+
+	names, err := ResourceSuggestions(client, "SUBSCRIPTION_ID", "Microsoft.KeyVault/vaults")(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, []string{"kv-api", "kv-web"}, names)
+
+	require.Len(t, queries, 2)
+	require.Contains(t, queries[0], "Resources | where type =~ 'Microsoft.KeyVault/vaults' | project name")
+	require.Contains(t, queries[0], "SUBSCRIPTION_ID")
+	require.Contains(t, queries[1], "PAGE_2")
+}
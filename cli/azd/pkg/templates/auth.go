@@ -0,0 +1,98 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+const (
+	// TokenEnvVarName is the environment variable with the personal access token used to fetch private templates and
+	// template sources over HTTPS.
+	TokenEnvVarName = "AZD_TEMPLATE_TOKEN"
+	// TokenConfigKey is the user configuration key with the personal access token used to fetch private templates and
+	// template sources over HTTPS, when TokenEnvVarName isn't set.
+	TokenConfigKey = "template.token"
+	// TokenHostsEnvVarName is the environment variable with the comma separated hosts the personal access token is sent
+	// to. Hosts starting with a dot match their subdomains.
+	TokenHostsEnvVarName = "AZD_TEMPLATE_TOKEN_HOSTS"
+	// TokenHostsConfigKey is the user configuration key with the hosts the personal access token is sent to, when
+	// TokenHostsEnvVarName isn't set.
+	TokenHostsConfigKey = "template.tokenHosts"
+)
+
+// Token returns the personal access token used to fetch the template repository or template source at location, which
+// is read from TokenEnvVarName, or else from TokenConfigKey in config. The token is only returned for the hosts listed in
+// TokenHostsEnvVarName or TokenHostsConfigKey, and no hosts are listed by default. A token is usually valid for a single
+// server, and servers such as GitHub reject invalid credentials even for public repositories, so the token isn't sent
+// to a host unless asked to. Azure DevOps repositories fall back to the AZURE_DEVOPS_EXT_PAT token of the Azure DevOps
+// pipeline provider, which is only valid for Azure DevOps. No token is returned for locations that aren't HTTPS URLs,
+// such as SSH repositories, which authenticate with the SSH agent.
+func Token(config config.Config, location string) string {
+	locationUrl, err := url.Parse(location)
+	if err != nil || !strings.EqualFold(locationUrl.Scheme, "https") {
+		return ""
+	}
+
+	host := strings.ToLower(locationUrl.Hostname())
+	if slices.ContainsFunc(tokenHosts(config), func(tokenHost string) bool {
+		return matchesHost(host, tokenHost)
+	}) {
+		if token := os.Getenv(TokenEnvVarName); token != "" {
+			return token
+		}
+
+		if config != nil {
+			if token, ok := config.Get(TokenConfigKey); ok {
+				if token, ok := token.(string); ok && token != "" {
+					return token
+				}
+			}
+		}
+	}
+
+	if matchesHost(host, "dev.azure.com") || matchesHost(host, ".visualstudio.com") {
+		return os.Getenv(azdo.AzDoPatName)
+	}
+
+	return ""
+}
+
+// tokenHosts returns the hosts the personal access token is sent to.
+func tokenHosts(config config.Config) []string {
+	if hosts := os.Getenv(TokenHostsEnvVarName); hosts != "" {
+		return strings.Split(hosts, ",")
+	}
+
+	if config != nil {
+		if hosts, ok := config.Get(TokenHostsConfigKey); ok {
+			switch hosts := hosts.(type) {
+			case string:
+				return strings.Split(hosts, ",")
+			case []any:
+				values := []string{}
+				for _, host := range hosts {
+					values = append(values, fmt.Sprint(host))
+				}
+				return values
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchesHost returns whether host is tokenHost, or a subdomain of tokenHost when it starts with a dot.
+func matchesHost(host string, tokenHost string) bool {
+	tokenHost = strings.ToLower(strings.TrimSpace(tokenHost))
+	if strings.HasPrefix(tokenHost, ".") {
+		return strings.HasSuffix(host, tokenHost)
+	}
+
+	return tokenHost != "" && host == tokenHost
+}
@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azdo"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Token(t *testing.T) {
+	t.Setenv(TokenEnvVarName, "")
+	t.Setenv(azdo.AzDoPatName, "azdo-pat")
+
+	t.Setenv(TokenHostsEnvVarName, "")
+
+	userConfig := config.NewEmptyConfig()
+	require.Empty(t, Token(userConfig, "https://github.com/contoso/templates"))
+	require.Equal(t, "azdo-pat", Token(userConfig, "https://dev.azure.com/contoso/_git/templates"))
+
+	// the token isn't sent to any host until its hosts are listed, so an Azure DevOps token isn't sent to GitHub
+	t.Setenv(TokenEnvVarName, "env-token")
+	require.Empty(t, Token(userConfig, "https://github.com/contoso/templates"))
+	require.Empty(t, Token(userConfig, "https://attacker.example/contoso/templates"))
+	require.Equal(t, "azdo-pat", Token(userConfig, "https://contoso.visualstudio.com/_git/templates"))
+
+	require.NoError(t, userConfig.Set(TokenHostsConfigKey, []any{"dev.azure.com", ".visualstudio.com"}))
+	require.Equal(t, "env-token", Token(userConfig, "https://dev.azure.com/contoso/_git/templates"))
+	require.Equal(t, "env-token", Token(userConfig, "https://contoso.visualstudio.com/_git/templates"))
+	require.Empty(t, Token(userConfig, "https://visualstudio.com.attacker.example/_git/templates"))
+	require.Empty(t, Token(userConfig, "https://github.com/contoso/templates"))
+
+	t.Setenv(TokenEnvVarName, "")
+	require.NoError(t, userConfig.Set(TokenConfigKey, "config-token"))
+	require.Equal(t, "config-token", Token(userConfig, "https://dev.azure.com/contoso/_git/templates"))
+
+	t.Setenv(TokenEnvVarName, "env-token")
+	t.Setenv(TokenHostsEnvVarName, "github.com, .contoso.com")
+	require.Equal(t, "env-token", Token(userConfig, "https://github.com/contoso/templates"))
+	require.Equal(t, "env-token", Token(userConfig, "https://git.contoso.com/templates"))
+	require.Equal(t, "azdo-pat", Token(userConfig, "https://dev.azure.com/contoso/_git/templates"))
+
+	// SSH repositories authenticate with the SSH agent
+	require.Empty(t, Token(userConfig, "git@github.com:contoso/templates.git"))
+	require.Empty(t, Token(userConfig, "ssh://git@github.com/contoso/templates.git"))
+}
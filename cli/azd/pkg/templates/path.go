@@ -14,7 +14,7 @@ import (
 // See Template.Path for more details.
 func Absolute(path string) (string, error) {
 	// already a git URI, return as-is
-	if strings.HasPrefix(path, "git") || strings.HasPrefix(path, "http") {
+	if strings.HasPrefix(path, "git") || strings.HasPrefix(path, "http") || strings.HasPrefix(path, "ssh://") {
 		return path, nil
 	}
 
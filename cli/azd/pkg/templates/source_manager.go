@@ -223,7 +223,14 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 	case SourceKindFile:
 		source, err = NewFileTemplateSource(config.Name, config.Location)
 	case SourceKindUrl:
-		source, err = NewUrlTemplateSource(ctx, config.Name, config.Location, sm.httpClient)
+		// the token is only sent to the sources configured by the user, not to the well-known sources
+		userConfig, loadErr := sm.configManager.Load()
+		if loadErr != nil {
+			return nil, fmt.Errorf("unable to load user configuration: %w", loadErr)
+		}
+
+		token := Token(userConfig, config.Location)
+		source, err = NewUrlTemplateSource(ctx, config.Name, config.Location, sm.httpClient, token)
 	case SourceKindAwesomeAzd:
 		source, err = NewAwesomeAzdTemplateSource(ctx, SourceAwesomeAzd.Name, SourceAwesomeAzd.Location, sm.httpClient)
 	case SourceKindResource:
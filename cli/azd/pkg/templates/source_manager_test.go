@@ -162,6 +162,7 @@ func Test_sourceManager_CreateSource(t *testing.T) {
 	mockAwesomeAzdTemplateSource(mockContext)
 
 	configManager := &mockUserConfigManager{}
+	configManager.On("Load").Return(config.NewEmptyConfig(), nil)
 	sm := NewSourceManager(NewSourceOptions(), mockContext.Container, configManager, mockContext.HttpClient)
 
 	configDir, err := config.GetUserConfigDir()
@@ -2,6 +2,7 @@ package templates
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,8 +12,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
 
-// NewUrlTemplateSource creates a new template source from a URL.
-func NewUrlTemplateSource(ctx context.Context, name string, url string, httpClient httputil.HttpClient) (Source, error) {
+// NewUrlTemplateSource creates a new template source from a URL. When token is set, it's sent as the password of basic
+// authentication, which private GitHub and Azure DevOps repositories accept for personal access tokens.
+func NewUrlTemplateSource(
+	ctx context.Context, name string, url string, httpClient httputil.HttpClient, token string) (Source, error) {
 	pipeline := runtime.NewPipeline("azd-templates", "1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
 		Transport: httpClient,
 	})
@@ -22,12 +25,27 @@ func NewUrlTemplateSource(ctx context.Context, name string, url string, httpClie
 		return nil, err
 	}
 
+	if token != "" {
+		credential := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		req.Raw().Header.Set("Authorization", "Basic "+credential)
+	}
+
 	resp, err := pipeline.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed for template source '%s', %w", url, err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf(
+			"access denied to template source '%s'. If the source is private, set %s to a token with read access: %w",
+			url, TokenEnvVarName, runtime.NewResponseError(resp))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf(
+			"template source '%s' not found. If the source is private, set %s to a token with read access: %w",
+			url, TokenEnvVarName, runtime.NewResponseError(resp))
+	default:
 		return nil, runtime.NewResponseError(resp)
 	}
 
@@ -21,7 +21,7 @@ func Test_NewUrlTemplateSource_ValidUrl(t *testing.T) {
 		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, testTemplates)
 	})
 
-	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient)
+	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient, "")
 	require.Nil(t, err)
 
 	require.Equal(t, name, source.Name())
@@ -39,7 +39,7 @@ func Test_NewUrlTemplateSource_ValidUrl_InvalidJson(t *testing.T) {
 		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, "invalid json")
 	})
 
-	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient)
+	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient, "")
 	require.Nil(t, source)
 	require.Error(t, err)
 }
@@ -56,7 +56,32 @@ func Test_NewUrlTemplateSource_InvalidUrl(t *testing.T) {
 		return mocks.CreateEmptyHttpResponse(req, http.StatusNotFound)
 	})
 
-	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient)
+	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient, "")
 	require.Nil(t, source)
 	require.Error(t, err)
 }
+
+func Test_NewUrlTemplateSource_Token(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	name := "test"
+	url := "https://example.com/templates.json"
+
+	mockContext.HttpClient.When(func(req *http.Request) bool {
+		return req.Method == http.MethodGet && req.URL.String() == url
+	}).RespondFn(func(req *http.Request) (*http.Response, error) {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "x-access-token" || password != "s3cret" {
+			return mocks.CreateEmptyHttpResponse(req, http.StatusForbidden)
+		}
+
+		return mocks.CreateHttpResponseWithBody(req, http.StatusOK, testTemplates)
+	})
+
+	source, err := NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient, "s3cret")
+	require.NoError(t, err)
+	require.Equal(t, name, source.Name())
+
+	_, err = NewUrlTemplateSource(context.Background(), name, url, mockContext.HttpClient, "")
+	require.ErrorContains(t, err, "access denied to template source")
+}
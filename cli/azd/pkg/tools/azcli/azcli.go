@@ -61,6 +61,24 @@ type AzCli interface {
 		vaultName string,
 		secretName string,
 	) (*AzCliKeyVaultSecret, error)
+	// Gets a version of a key vault secret, the latest version when secretVersion is empty.
+	// vaultName is the name or the URL of the vault.
+	GetKeyVaultSecretVersion(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+		secretVersion string,
+	) (*AzCliKeyVaultSecret, error)
+	// Sets the value of a key vault secret, creating the secret when it doesn't exist or a new version of it when it does.
+	// vaultName is the name or the URL of the vault.
+	SetKeyVaultSecret(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+		value string,
+	) (*AzCliKeyVaultSecret, error)
 	GetAppConfig(
 		ctx context.Context, subscriptionId string, resourceGroupName string, configName string) (*AzCliAppConfig, error)
 	PurgeApim(ctx context.Context, subscriptionId string, apimName string, location string) error
@@ -84,12 +102,37 @@ type AzCli interface {
 		funcName string,
 		deployZipFile io.Reader,
 	) (*string, error)
+	// Deploys the zip file to a function app hosted on the Flex Consumption plan
+	DeployFunctionAppUsingZipFileFlexConsumption(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+		deployZipFile io.Reader,
+		remoteBuild bool,
+	) (*string, error)
 	GetFunctionAppProperties(
 		ctx context.Context,
 		subscriptionID string,
 		resourceGroup string,
 		funcName string,
 	) (*AzCliFunctionAppProperties, error)
+	// Gets the SKU tier of the App Service plan hosting the function app
+	GetFunctionAppPlanTier(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+	) (string, error)
+	// Adds the given settings to the application settings of an App Service or Azure Functions app, replacing
+	// existing settings with the same name.
+	UpdateAppServiceAppSettings(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		settings map[string]string,
+	) error
 
 	DeleteResourceGroup(ctx context.Context, subscriptionId string, resourceGroupName string) error
 	CreateOrUpdateResourceGroup(
@@ -128,6 +128,82 @@ func Test_DeployFunctionAppUsingZipFile(t *testing.T) {
 	})
 }
 
+func Test_GetFunctionAppPlanTier(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+		registerFlexFunctionAppMocks(mockContext, FunctionAppPlanTierFlexConsumption)
+
+		tier, err := azCli.GetFunctionAppPlanTier(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"FUNC_APP_NAME",
+		)
+		require.NoError(t, err)
+		require.Equal(t, FunctionAppPlanTierFlexConsumption, tier)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+		registerFlexFunctionAppMocks(mockContext, FunctionAppPlanTierFlexConsumption)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.Contains(request.URL.Path, "/providers/Microsoft.Web/serverfarms/PLAN_NAME")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		})
+
+		tier, err := azCli.GetFunctionAppPlanTier(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"FUNC_APP_NAME",
+		)
+		require.Empty(t, tier)
+		require.ErrorContains(t, err, "failed retrieving app service plan of function app 'FUNC_APP_NAME'")
+	})
+}
+
+func Test_DeployFunctionAppUsingZipFileFlexConsumption(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+	registerFlexFunctionAppMocks(mockContext, FunctionAppPlanTierFlexConsumption)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/publish")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		// the deployment endpoint is read from the host names of the function app
+		require.Equal(t, "FUNC_APP_NAME.scm.contoso.net", request.URL.Host)
+		require.Equal(t, "true", request.URL.Query().Get("RemoteBuild"))
+		return mocks.CreateHttpResponseWithBody(request, http.StatusAccepted, "DEPLOYMENT_ID")
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/api/deployments/DEPLOYMENT_ID")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, azsdk.DeployStatus{
+			Id:         "DEPLOYMENT_ID",
+			Status:     azsdk.DeployStatusSuccess,
+			StatusText: "Deployment Complete",
+			Complete:   true,
+		})
+	})
+
+	res, err := azCli.DeployFunctionAppUsingZipFileFlexConsumption(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"FUNC_APP_NAME",
+		bytes.NewBuffer([]byte{}),
+		true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Deployment Complete", *res)
+}
+
 func registerConflictMocks(mockContext *mocks.MockContext, ran *bool) {
 	// Original call to start the deployment operation
 	mockContext.HttpClient.When(func(request *http.Request) bool {
@@ -173,3 +249,51 @@ func registerPollingMocks(mockContext *mocks.MockContext, ran *bool) {
 		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, completeStatus)
 	})
 }
+
+// registerFlexFunctionAppMocks registers the function app and its App Service plan, with the given SKU tier
+func registerFlexFunctionAppMocks(mockContext *mocks.MockContext, tier string) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Web/sites/FUNC_APP_NAME")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.WebAppsClientGetResponse{
+			Site: armappservice.Site{
+				Name: convert.RefOf("FUNC_APP_NAME"),
+				Properties: &armappservice.SiteProperties{
+					DefaultHostName: convert.RefOf("FUNC_APP_NAME.azurewebsites.net"),
+					ServerFarmID: convert.RefOf(
+						"/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP_ID/providers/" +
+							"Microsoft.Web/serverfarms/PLAN_NAME"),
+					HostNameSSLStates: []*armappservice.HostNameSSLState{
+						{
+							Name:     convert.RefOf("FUNC_APP_NAME.azurewebsites.net"),
+							HostType: convert.RefOf(armappservice.HostTypeStandard),
+						},
+						{
+							Name:     convert.RefOf("FUNC_APP_NAME.scm.contoso.net"),
+							HostType: convert.RefOf(armappservice.HostTypeRepository),
+						},
+					},
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Web/serverfarms/PLAN_NAME")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.PlansClientGetResponse{
+			Plan: armappservice.Plan{
+				Name: convert.RefOf("PLAN_NAME"),
+				SKU: &armappservice.SKUDescription{
+					Tier: convert.RefOf(tier),
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+}
@@ -20,7 +20,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 )
 
-type dockerCredentials struct {
+// DockerCredentials are the credentials used to log into a container registry
+type DockerCredentials struct {
 	Username    string
 	Password    string
 	LoginServer string
@@ -36,6 +37,17 @@ type ContainerRegistryService interface {
 	Login(ctx context.Context, subscriptionId string, loginServer string) error
 	// Gets a list of container registries for the specified subscription
 	GetContainerRegistries(ctx context.Context, subscriptionId string) ([]*armcontainerregistry.Registry, error)
+	// Gets the credentials of the admin user of the specified container registry, which unlike the credentials of the
+	// logged in user don't expire
+	AdminCredentials(ctx context.Context, subscriptionId string, loginServer string) (*DockerCredentials, error)
+	// Builds an image with an ACR task run in the specified container registry, which pushes the image to the registry
+	Build(
+		ctx context.Context,
+		subscriptionId string,
+		loginServer string,
+		request *RemoteBuildRequest,
+		progress io.Writer,
+	) error
 }
 
 type containerRegistryService struct {
@@ -91,7 +103,7 @@ func (crs *containerRegistryService) Login(ctx context.Context, subscriptionId s
 		log.Printf("failed getting ACR token credentials: %s\n", tokenErr.Error())
 
 		// If that fails, attempt to get ACR credentials from the admin user
-		adminCreds, adminErr := crs.getAdminUserCredentials(ctx, subscriptionId, loginServer)
+		adminCreds, adminErr := crs.AdminCredentials(ctx, subscriptionId, loginServer)
 		if adminErr != nil {
 			return fmt.Errorf("failed logging into container registry, token: %w, admin: %w", tokenErr, adminErr)
 		}
@@ -114,25 +126,25 @@ func (crs *containerRegistryService) getTokenCredentials(
 	ctx context.Context,
 	subscriptionId string,
 	loginServer string,
-) (*dockerCredentials, error) {
+) (*DockerCredentials, error) {
 	acrToken, err := crs.getAcrToken(ctx, subscriptionId, loginServer)
 	if err != nil {
 		return nil, fmt.Errorf("failed getting ACR token: %w", err)
 	}
 
-	return &dockerCredentials{
+	return &DockerCredentials{
 		Username:    "00000000-0000-0000-0000-000000000000",
 		Password:    acrToken.RefreshToken,
 		LoginServer: loginServer,
 	}, nil
 }
 
-// Logs into the specified container registry
-func (crs *containerRegistryService) getAdminUserCredentials(
+// Gets the credentials of the admin user of the specified container registry
+func (crs *containerRegistryService) AdminCredentials(
 	ctx context.Context,
 	subscriptionId string,
 	loginServer string,
-) (*dockerCredentials, error) {
+) (*DockerCredentials, error) {
 	client, err := crs.createRegistriesClient(ctx, subscriptionId)
 	if err != nil {
 		return nil, err
@@ -153,7 +165,7 @@ func (crs *containerRegistryService) getAdminUserCredentials(
 		return nil, fmt.Errorf("getting container registry credentials: %w", err)
 	}
 
-	return &dockerCredentials{
+	return &DockerCredentials{
 		Username:    *credResponse.Username,
 		Password:    *credResponse.Passwords[0].Value,
 		LoginServer: loginServer,
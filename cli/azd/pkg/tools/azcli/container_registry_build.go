@@ -0,0 +1,467 @@
+package azcli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+// RemoteBuildRequest describes an image built by an ACR task run instead of the local docker daemon.
+type RemoteBuildRequest struct {
+	// The directory of the build context. It is uploaded to the registry without the files excluded by its
+	// .dockerignore file.
+	ContextDir string
+	// The path of the Dockerfile, relative to the build context
+	DockerfilePath string
+	// The names of the image, with a tag and without the login server of the registry, for example 'app/api:v1'
+	ImageNames []string
+	// The platform of the image, for example 'linux/amd64'
+	Platform string
+	// The build arguments, as NAME=VALUE
+	BuildArgs []string
+}
+
+// runLogPollInterval is the interval between requests for the status and log of an ACR task run.
+var runLogPollInterval = 2 * time.Second
+
+// Builds an image with an ACR task run in the specified container registry, writing the log of the run to progress when
+// it is not nil. The build context is uploaded to the registry, and the image is pushed to the registry by the run.
+func (crs *containerRegistryService) Build(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	request *RemoteBuildRequest,
+	progress io.Writer,
+) error {
+	registryName := strings.Split(loginServer, ".")[0]
+	_, resourceGroup, err := crs.findContainerRegistryByName(ctx, subscriptionId, registryName)
+	if err != nil {
+		return err
+	}
+
+	platform, err := platformProperties(request.Platform)
+	if err != nil {
+		return err
+	}
+
+	client, err := crs.createRegistriesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	upload, err := client.GetBuildSourceUploadURL(ctx, resourceGroup, registryName, nil)
+	if err != nil {
+		return fmt.Errorf("getting build source upload url: %w", err)
+	}
+
+	// the build context is packed to a temporary file rather than streamed to the upload, since the blob service requires
+	// the length of the blob upfront
+	source, err := os.CreateTemp("", "azd-build-context-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("packing build context: %w", err)
+	}
+	defer func() {
+		source.Close()
+		os.Remove(source.Name())
+	}()
+
+	if err := packBuildContext(request.ContextDir, request.DockerfilePath, source); err != nil {
+		return fmt.Errorf("packing build context: %w", err)
+	}
+
+	size, err := source.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("packing build context: %w", err)
+	}
+
+	if _, err := source.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("packing build context: %w", err)
+	}
+
+	log.Printf("uploading build context of %d bytes to registry '%s'", size, loginServer)
+	if err := crs.uploadBuildSource(ctx, *upload.UploadURL, source, size); err != nil {
+		return err
+	}
+
+	arguments := []*armcontainerregistry.Argument{}
+	for _, arg := range request.BuildArgs {
+		name, value, has := strings.Cut(arg, "=")
+		if !has {
+			// like docker, a build argument without a value takes the value of the environment variable
+			value = os.Getenv(name)
+		}
+
+		// the values of build arguments can be secrets, such as tokens for package feeds, so they're hidden from the
+		// logs and the run history of the registry
+		arguments = append(arguments, &armcontainerregistry.Argument{
+			Name:     to.Ptr(name),
+			Value:    to.Ptr(value),
+			IsSecret: to.Ptr(true),
+		})
+	}
+
+	poller, err := client.BeginScheduleRun(ctx, resourceGroup, registryName, &armcontainerregistry.DockerBuildRequest{
+		Type:           to.Ptr("DockerBuildRequest"),
+		SourceLocation: upload.RelativePath,
+		DockerFilePath: to.Ptr(request.DockerfilePath),
+		ImageNames:     to.SliceOfPtrs(request.ImageNames...),
+		IsPushEnabled:  to.Ptr(true),
+		Platform:       platform,
+		Arguments:      arguments,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("scheduling build run: %w", err)
+	}
+
+	scheduled, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("scheduling build run: %w", err)
+	}
+
+	if scheduled.Properties == nil || scheduled.Properties.RunID == nil {
+		return errors.New("scheduling build run: missing run id")
+	}
+
+	runId := *scheduled.Properties.RunID
+	log.Printf("scheduled build run '%s' in registry '%s'", runId, loginServer)
+
+	return crs.waitForRun(ctx, subscriptionId, resourceGroup, registryName, runId, progress)
+}
+
+// waitForRun waits for the ACR task run to finish, writing the log of the run to progress while it runs.
+func (crs *containerRegistryService) waitForRun(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	registryName string,
+	runId string,
+	progress io.Writer,
+) error {
+	client, err := crs.createRunsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	logs, err := client.GetLogSasURL(ctx, resourceGroup, registryName, runId, nil)
+	if err != nil {
+		return fmt.Errorf("getting log url of build run '%s': %w", runId, err)
+	}
+
+	if progress == nil {
+		progress = io.Discard
+	}
+
+	offset := int64(0)
+	for {
+		run, err := client.Get(ctx, resourceGroup, registryName, runId, nil)
+		if err != nil {
+			return fmt.Errorf("getting status of build run '%s': %w", runId, err)
+		}
+
+		// the log is read after the status, so the log is complete once the run is finished
+		if logs.LogLink != nil {
+			read, err := crs.readRunLog(ctx, *logs.LogLink, offset, progress)
+			if err != nil {
+				log.Printf("reading log of build run '%s': %v", runId, err)
+			}
+			offset += read
+		}
+
+		var status armcontainerregistry.RunStatus
+		if run.Properties != nil && run.Properties.Status != nil {
+			status = *run.Properties.Status
+		}
+
+		switch status {
+		case armcontainerregistry.RunStatusSucceeded:
+			return nil
+		case armcontainerregistry.RunStatusFailed,
+			armcontainerregistry.RunStatusCanceled,
+			armcontainerregistry.RunStatusError,
+			armcontainerregistry.RunStatusTimeout:
+			if run.Properties.RunErrorMessage != nil {
+				return fmt.Errorf("build run '%s' finished with status '%s': %s",
+					runId, status, *run.Properties.RunErrorMessage)
+			}
+
+			return fmt.Errorf("build run '%s' finished with status '%s'", runId, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(runLogPollInterval):
+		}
+	}
+}
+
+// readRunLog writes the log of an ACR task run from the offset to w, returning the number of bytes written. The log is
+// empty until the run writes to it.
+func (crs *containerRegistryService) readRunLog(
+	ctx context.Context,
+	logUrl string,
+	offset int64,
+	w io.Writer,
+) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logUrl, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+
+	res, err := crs.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable:
+		return 0, nil
+	case http.StatusOK:
+		// the range is ignored, the log is returned from the start
+		if _, err := io.CopyN(io.Discard, res.Body, offset); err != nil {
+			return 0, nil
+		}
+	case http.StatusPartialContent:
+	default:
+		return 0, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return io.Copy(w, res.Body)
+}
+
+// uploadBuildSource uploads the packed build context, of size bytes, to the blob url returned by GetBuildSourceUploadURL.
+func (crs *containerRegistryService) uploadBuildSource(
+	ctx context.Context,
+	uploadUrl string,
+	source io.Reader,
+	size int64,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadUrl, source)
+	if err != nil {
+		return fmt.Errorf("uploading build context: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-version", "2019-12-12")
+
+	res, err := crs.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading build context: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusOK {
+		return fmt.Errorf("uploading build context: unexpected status code %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func (crs *containerRegistryService) createRunsClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armcontainerregistry.RunsClient, error) {
+	credential, err := crs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := clientOptionsBuilder(ctx, crs.httpClient, crs.userAgent).BuildArmClientOptions()
+	client, err := armcontainerregistry.NewRunsClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating runs client: %w", err)
+	}
+
+	return client, nil
+}
+
+// platformProperties converts a docker platform, for example 'linux/arm64/v8', to the platform of an ACR task run.
+func platformProperties(platform string) (*armcontainerregistry.PlatformProperties, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid platform '%s', expected os/architecture[/variant]", platform)
+	}
+
+	properties := &armcontainerregistry.PlatformProperties{}
+	for _, value := range armcontainerregistry.PossibleOSValues() {
+		if strings.EqualFold(string(value), parts[0]) {
+			properties.OS = to.Ptr(value)
+		}
+	}
+
+	for _, arch := range armcontainerregistry.PossibleArchitectureValues() {
+		if strings.EqualFold(string(arch), parts[1]) {
+			properties.Architecture = to.Ptr(arch)
+		}
+	}
+
+	if properties.OS == nil || properties.Architecture == nil {
+		return nil, fmt.Errorf("platform '%s' is not supported by registry builds", platform)
+	}
+
+	if len(parts) == 3 {
+		for _, variant := range armcontainerregistry.PossibleVariantValues() {
+			if strings.EqualFold(string(variant), parts[2]) {
+				properties.Variant = to.Ptr(variant)
+			}
+		}
+
+		if properties.Variant == nil {
+			return nil, fmt.Errorf("platform '%s' is not supported by registry builds", platform)
+		}
+	}
+
+	return properties, nil
+}
+
+// packBuildContext writes the files of the build context in dir to w as a gzipped tarball, without the files excluded
+// by the .dockerignore file of the build context. The Dockerfile, at dockerfilePath relative to dir, is always written.
+//
+// The patterns of the .dockerignore file are matched like docker does. Excluded directories aren't walked, unless a
+// pattern prefixed with '!' may include files within them again.
+func packBuildContext(dir string, dockerfilePath string, w io.Writer) error {
+	matcher, err := readDockerignore(filepath.Join(dir, ".dockerignore"))
+	if err != nil {
+		return err
+	}
+
+	dockerfilePath = path.Clean(filepath.ToSlash(dockerfilePath))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// the match results of the directories walked, by path, so the patterns aren't matched against every parent of
+	// every file
+	parents := map[string]patternmatcher.MatchInfo{}
+
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		ignored, matchInfo, err := matcher.MatchesUsingParentResults(rel, parents[path.Dir(rel)])
+		if err != nil {
+			return fmt.Errorf("matching .dockerignore patterns: %w", err)
+		}
+
+		if entry.IsDir() {
+			parents[rel] = matchInfo
+			if ignored && !strings.HasPrefix(dockerfilePath, rel+"/") && !mayIncludeWithin(matcher, rel) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if rel != dockerfilePath && ignored {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(filePath); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// readDockerignore returns the matcher of the patterns of the .dockerignore file at path, which matches no file when the
+// .dockerignore file doesn't exist.
+func readDockerignore(path string) (*patternmatcher.PatternMatcher, error) {
+	var patterns []string
+	if file, err := os.Open(path); err == nil {
+		defer file.Close()
+
+		if patterns, err = ignorefile.ReadAll(file); err != nil {
+			return nil, fmt.Errorf("reading .dockerignore: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading .dockerignore: %w", err)
+	}
+
+	matcher, err := patternmatcher.New(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("parsing .dockerignore: %w", err)
+	}
+
+	return matcher, nil
+}
+
+// mayIncludeWithin returns whether a pattern prefixed with '!' may include files within the excluded directory at the
+// slash separated path rel, in which case the directory is walked. Like docker, only patterns starting with the path of
+// the directory are considered.
+func mayIncludeWithin(matcher *patternmatcher.PatternMatcher, rel string) bool {
+	for _, pattern := range matcher.Patterns() {
+		if pattern.Exclusion() && strings.HasPrefix(filepath.ToSlash(pattern.String())+"/", rel+"/") {
+			return true
+		}
+	}
+
+	return false
+}
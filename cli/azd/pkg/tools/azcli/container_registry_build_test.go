@@ -0,0 +1,260 @@
+package azcli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerRegistryService_Build(t *testing.T) {
+	originalInterval := runLogPollInterval
+	t.Cleanup(func() {
+		runLogPollInterval = originalInterval
+	})
+	runLogPollInterval = 0
+
+	contextDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte("FROM node:18"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "index.js"), []byte("console.log()"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, ".dockerignore"), []byte("Dockerfile\n*.log\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, "debug.log"), []byte("debug"), 0600))
+
+	setup := func(t *testing.T, finalStatus armcontainerregistry.RunStatus) (
+		*mocks.MockContext, *armcontainerregistry.DockerBuildRequest, *[]string) {
+		mockContext := mocks.NewMockContext(context.Background())
+		runRequest := &armcontainerregistry.DockerBuildRequest{}
+		uploaded := &[]string{}
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasSuffix(request.URL.Path, "/providers/Microsoft.ContainerRegistry/registries")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.RegistryListResult{
+				Value: []*armcontainerregistry.Registry{
+					{
+						Name: to.Ptr("contoso"),
+						ID: to.Ptr("/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/" +
+							"Microsoft.ContainerRegistry/registries/contoso"),
+					},
+				},
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/listBuildSourceUploadUrl")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.SourceUploadDefinition{
+				UploadURL:    to.Ptr("https://contoso.blob.core.windows.net/source/upload.tar.gz?sas"),
+				RelativePath: to.Ptr("source/upload.tar.gz"),
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && request.URL.Host == "contoso.blob.core.windows.net"
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			require.Equal(t, "BlockBlob", request.Header.Get("x-ms-blob-type"))
+			*uploaded = tarballFiles(t, request.Body)
+			return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/scheduleRun")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, runRequest))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.Run{
+				Properties: &armcontainerregistry.RunProperties{
+					RunID:  to.Ptr("ca1"),
+					Status: to.Ptr(armcontainerregistry.RunStatusQueued),
+				},
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/runs/ca1/listLogSasUrl")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.RunGetLogResult{
+				LogLink: to.Ptr("https://contoso.blob.core.windows.net/logs/ca1/rawtext.log?sas"),
+			})
+		})
+
+		statuses := []armcontainerregistry.RunStatus{armcontainerregistry.RunStatusRunning, finalStatus}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/runs/ca1")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			status := statuses[0]
+			statuses = statuses[1:]
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.Run{
+				Properties: &armcontainerregistry.RunProperties{
+					RunID:  to.Ptr("ca1"),
+					Status: to.Ptr(status),
+				},
+			})
+		})
+
+		runLog := "Step 1/2 : FROM node:18\nStep 2/2 : COPY . .\n"
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/logs/ca1/rawtext.log")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			// the first half of the log is written while the run is running, the rest once it finishes
+			var content string
+			switch request.Header.Get("Range") {
+			case "bytes=0-":
+				content = runLog[:len(runLog)/2]
+			default:
+				content = runLog[len(runLog)/2:]
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusPartialContent,
+				Header:     http.Header{},
+				Request:    request,
+				Body:       io.NopCloser(strings.NewReader(content)),
+			}, nil
+		})
+
+		return mockContext, runRequest, uploaded
+	}
+
+	request := &RemoteBuildRequest{
+		ContextDir:     contextDir,
+		DockerfilePath: "Dockerfile",
+		ImageNames:     []string{"app/api:azd-deploy-1"},
+		Platform:       "linux/arm64/v8",
+		BuildArgs:      []string{"NODE_ENV=production"},
+	}
+
+	t.Run("Succeeded", func(t *testing.T) {
+		mockContext, runRequest, uploaded := setup(t, armcontainerregistry.RunStatusSucceeded)
+		crs := NewContainerRegistryService(
+			mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, docker.NewDocker(mockContext.CommandRunner))
+
+		progress := &bytes.Buffer{}
+		err := crs.Build(*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", request, progress)
+		require.NoError(t, err)
+
+		require.Equal(t, "Step 1/2 : FROM node:18\nStep 2/2 : COPY . .\n", progress.String())
+		// the Dockerfile is always uploaded, even when it is excluded by .dockerignore
+		require.ElementsMatch(t, []string{".dockerignore", "Dockerfile", "index.js"}, *uploaded)
+
+		require.Equal(t, "source/upload.tar.gz", *runRequest.SourceLocation)
+		require.Equal(t, "Dockerfile", *runRequest.DockerFilePath)
+		require.Equal(t, []*string{to.Ptr("app/api:azd-deploy-1")}, runRequest.ImageNames)
+		require.True(t, *runRequest.IsPushEnabled)
+		require.Equal(t, &armcontainerregistry.PlatformProperties{
+			OS:           to.Ptr(armcontainerregistry.OSLinux),
+			Architecture: to.Ptr(armcontainerregistry.ArchitectureArm64),
+			Variant:      to.Ptr(armcontainerregistry.VariantV8),
+		}, runRequest.Platform)
+		require.Equal(t, []*armcontainerregistry.Argument{
+			{Name: to.Ptr("NODE_ENV"), Value: to.Ptr("production"), IsSecret: to.Ptr(true)},
+		}, runRequest.Arguments)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext, _, _ := setup(t, armcontainerregistry.RunStatusFailed)
+		crs := NewContainerRegistryService(
+			mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, docker.NewDocker(mockContext.CommandRunner))
+
+		err := crs.Build(*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", request, nil)
+		require.EqualError(t, err, "build run 'ca1' finished with status 'Failed'")
+	})
+
+	t.Run("RegistryNotFound", func(t *testing.T) {
+		mockContext, _, _ := setup(t, armcontainerregistry.RunStatusSucceeded)
+		crs := NewContainerRegistryService(
+			mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, docker.NewDocker(mockContext.CommandRunner))
+
+		err := crs.Build(*mockContext.Context, "SUBSCRIPTION_ID", "other.azurecr.io", request, nil)
+		require.ErrorContains(t, err, "cannot find registry with name 'other'")
+	})
+}
+
+func Test_platformProperties(t *testing.T) {
+	properties, err := platformProperties("linux/amd64")
+	require.NoError(t, err)
+	require.Equal(t, &armcontainerregistry.PlatformProperties{
+		OS:           to.Ptr(armcontainerregistry.OSLinux),
+		Architecture: to.Ptr(armcontainerregistry.ArchitectureAmd64),
+	}, properties)
+
+	_, err = platformProperties("linux")
+	require.ErrorContains(t, err, "expected os/architecture[/variant]")
+
+	_, err = platformProperties("linux/riscv64")
+	require.ErrorContains(t, err, "is not supported by registry builds")
+}
+
+func Test_packBuildContext(t *testing.T) {
+	contextDir := t.TempDir()
+	files := []string{
+		"index.js",
+		"debug.log",
+		"src/debug.log",
+		"node_modules/express/index.js",
+		"dist/main.js",
+		"docs/guide.md",
+		"docs/README.md",
+		"build/Dockerfile",
+		"build/script.sh",
+	}
+	for _, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(contextDir, filepath.Dir(file)), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(contextDir, file), []byte(file), 0600))
+	}
+
+	dockerignore := "# comment\nnode_modules\n*.log\n/dist\ndocs\n!docs/README.md\nbuild\n"
+	require.NoError(t, os.WriteFile(filepath.Join(contextDir, ".dockerignore"), []byte(dockerignore), 0600))
+
+	// an ignored directory isn't walked
+	require.NoError(t, os.Chmod(filepath.Join(contextDir, "node_modules"), 0))
+	t.Cleanup(func() {
+		_ = os.Chmod(filepath.Join(contextDir, "node_modules"), 0700)
+	})
+
+	packed := &bytes.Buffer{}
+	require.NoError(t, packBuildContext(contextDir, "build/Dockerfile", packed))
+
+	// the Dockerfile is always packed, even when it is excluded by .dockerignore
+	require.ElementsMatch(t, []string{
+		".dockerignore",
+		"index.js",
+		"src/debug.log",
+		"docs/README.md",
+		"build/Dockerfile",
+	}, tarballFiles(t, packed))
+}
+
+// tarballFiles returns the names of the files of the gzipped tarball.
+func tarballFiles(t *testing.T, r io.Reader) []string {
+	gz, err := gzip.NewReader(r)
+	require.NoError(t, err)
+
+	files := []string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		require.NoError(t, err)
+		files = append(files, header.Name)
+	}
+}
@@ -5,11 +5,21 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 )
 
+// The SKU tier of the App Service plans of function apps hosted on the Flex Consumption plan
+const FunctionAppPlanTierFlexConsumption = "FlexConsumption"
+
 type AzCliFunctionAppProperties struct {
 	HostNames []string
+	// The host name of the deployment endpoint of the function app, for example 'app.scm.azurewebsites.net'
+	ScmHostName string
+	// The resource id of the App Service plan hosting the function app
+	ServerFarmId string
 }
 
 func (cli *azCli) GetFunctionAppProperties(
@@ -28,9 +38,55 @@ func (cli *azCli) GetFunctionAppProperties(
 		return nil, fmt.Errorf("failed retrieving function app properties: %w", err)
 	}
 
-	return &AzCliFunctionAppProperties{
-		HostNames: []string{*webApp.Properties.DefaultHostName},
-	}, nil
+	props := &AzCliFunctionAppProperties{
+		HostNames:    []string{*webApp.Properties.DefaultHostName},
+		ScmHostName:  fmt.Sprintf("%s.scm.azurewebsites.net", appName),
+		ServerFarmId: convert.ToValueWithDefault(webApp.Properties.ServerFarmID, ""),
+	}
+
+	for _, state := range webApp.Properties.HostNameSSLStates {
+		if state.HostType != nil && *state.HostType == armappservice.HostTypeRepository && state.Name != nil {
+			props.ScmHostName = *state.Name
+			break
+		}
+	}
+
+	return props, nil
+}
+
+// GetFunctionAppPlanTier returns the SKU tier of the App Service plan hosting the function app, for example 'Dynamic'
+// for the Consumption plan, or FunctionAppPlanTierFlexConsumption for the Flex Consumption plan.
+func (cli *azCli) GetFunctionAppPlanTier(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (string, error) {
+	props, err := cli.GetFunctionAppProperties(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return "", err
+	}
+
+	planId, err := arm.ParseResourceID(props.ServerFarmId)
+	if err != nil {
+		return "", fmt.Errorf("parsing app service plan id of function app '%s': %w", appName, err)
+	}
+
+	client, err := cli.createAppServicePlansClient(ctx, planId.SubscriptionID)
+	if err != nil {
+		return "", err
+	}
+
+	plan, err := client.Get(ctx, planId.ResourceGroupName, planId.Name, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving app service plan of function app '%s': %w", appName, err)
+	}
+
+	if plan.SKU == nil || plan.SKU.Tier == nil {
+		return "", nil
+	}
+
+	return *plan.SKU.Tier, nil
 }
 
 func (cli *azCli) DeployFunctionAppUsingZipFile(
@@ -52,3 +108,69 @@ func (cli *azCli) DeployFunctionAppUsingZipFile(
 
 	return convert.RefOf(response.StatusText), nil
 }
+
+// DeployFunctionAppUsingZipFileFlexConsumption deploys the zip file to a function app hosted on the Flex Consumption
+// plan, with the publish api of the deployment endpoint of the app. When remoteBuild is true, the app is built by the
+// deployment endpoint.
+func (cli *azCli) DeployFunctionAppUsingZipFileFlexConsumption(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	deployZipFile io.Reader,
+	remoteBuild bool,
+) (*string, error) {
+	props, err := cli.GetFunctionAppProperties(ctx, subscriptionId, resourceGroup, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cli.createFuncAppHostClient(ctx, subscriptionId, props.ScmHostName)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Publish(ctx, deployZipFile, &azsdk.PublishOptions{RemoteBuild: remoteBuild})
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.RefOf(response.StatusText), nil
+}
+
+func (cli *azCli) createAppServicePlansClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armappservice.PlansClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.clientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := armappservice.NewPlansClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating App Service Plans client: %w", err)
+	}
+
+	return client, nil
+}
+
+func (cli *azCli) createFuncAppHostClient(
+	ctx context.Context,
+	subscriptionId string,
+	hostName string,
+) (*azsdk.FuncAppHostClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.clientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewFuncAppHostClient(hostName, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating function app host client: %w", err)
+	}
+
+	return client, nil
+}
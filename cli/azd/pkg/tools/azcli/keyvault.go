@@ -65,17 +65,22 @@ func (cli *azCli) GetKeyVaultSecret(
 	vaultName string,
 	secretName string,
 ) (*AzCliKeyVaultSecret, error) {
-	vaultUrl := vaultName
-	if !strings.Contains(strings.ToLower(vaultName), "https://") {
-		vaultUrl = fmt.Sprintf("https://%s.vault.azure.net", vaultName)
-	}
+	return cli.GetKeyVaultSecretVersion(ctx, subscriptionId, vaultName, secretName, "")
+}
 
-	client, err := cli.createSecretsDataClient(ctx, subscriptionId, vaultUrl)
+func (cli *azCli) GetKeyVaultSecretVersion(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	secretVersion string,
+) (*AzCliKeyVaultSecret, error) {
+	client, err := cli.createSecretsDataClient(ctx, subscriptionId, keyVaultUrl(vaultName))
 	if err != nil {
-		return nil, nil
+		return nil, err
 	}
 
-	response, err := client.GetSecret(ctx, secretName, "", nil)
+	response, err := client.GetSecret(ctx, secretName, secretVersion, nil)
 	if err != nil {
 		var httpErr *azcore.ResponseError
 		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
@@ -91,6 +96,39 @@ func (cli *azCli) GetKeyVaultSecret(
 	}, nil
 }
 
+func (cli *azCli) SetKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	value string,
+) (*AzCliKeyVaultSecret, error) {
+	client, err := cli.createSecretsDataClient(ctx, subscriptionId, keyVaultUrl(vaultName))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.SetSecret(ctx, secretName, azsecrets.SetSecretParameters{Value: &value}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("setting key vault secret: %w", err)
+	}
+
+	return &AzCliKeyVaultSecret{
+		Id:    response.SecretBundle.ID.Version(),
+		Name:  response.SecretBundle.ID.Name(),
+		Value: *response.SecretBundle.Value,
+	}, nil
+}
+
+// keyVaultUrl returns the URL of a vault given its name or URL.
+func keyVaultUrl(vaultName string) string {
+	if !strings.Contains(strings.ToLower(vaultName), "https://") {
+		return fmt.Sprintf("https://%s.vault.azure.net", vaultName)
+	}
+
+	return vaultName
+}
+
 func (cli *azCli) PurgeKeyVault(ctx context.Context, subscriptionId string, vaultName string, location string) error {
 	client, err := cli.createKeyVaultClient(ctx, subscriptionId)
 	if err != nil {
@@ -55,6 +55,46 @@ func (cli *azCli) DeployAppServiceZip(
 	return convert.RefOf(response.StatusText), nil
 }
 
+func (cli *azCli) UpdateAppServiceAppSettings(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	settings map[string]string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	current, err := client.ListApplicationSettings(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return fmt.Errorf("failed retrieving application settings: %w", err)
+	}
+
+	appSettings := current.Properties
+	if appSettings == nil {
+		appSettings = map[string]*string{}
+	}
+
+	for name, value := range settings {
+		appSettings[name] = convert.RefOf(value)
+	}
+
+	_, err = client.UpdateApplicationSettings(
+		ctx,
+		resourceGroup,
+		appName,
+		armappservice.StringDictionary{Properties: appSettings},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed updating application settings: %w", err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) createWebAppsClient(ctx context.Context, subscriptionId string) (*armappservice.WebAppsClient, error) {
 	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
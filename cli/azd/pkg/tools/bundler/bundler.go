@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bundler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// BundlerCli wraps bundler, the gem dependency manager of Ruby
+type BundlerCli interface {
+	tools.ExternalTool
+	// Installs the dependencies of the project in projectPath
+	Install(ctx context.Context, projectPath string) error
+}
+
+type bundlerCli struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewBundlerCli creates a new BundlerCli
+func NewBundlerCli(commandRunner exec.CommandRunner) BundlerCli {
+	return &bundlerCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *bundlerCli) Name() string {
+	return "Bundler"
+}
+
+func (cli *bundlerCli) InstallUrl() string {
+	return "https://bundler.io/"
+}
+
+func (cli *bundlerCli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("bundle"); err != nil {
+		return err
+	}
+
+	bundleRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "bundle", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("bundler version: %s", bundleRes)
+	return nil
+}
+
+func (cli *bundlerCli) Install(ctx context.Context, projectPath string) error {
+	runArgs := exec.NewRunArgs("bundle", "install").WithCwd(projectPath)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed to install dependencies for project '%s': %w", projectPath, err)
+	}
+
+	return nil
+}
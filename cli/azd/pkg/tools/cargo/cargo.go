@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cargo
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// CargoCli wraps the cargo command of the Rust toolchain
+type CargoCli interface {
+	tools.ExternalTool
+	// Downloads the dependencies of the package in projectPath
+	Fetch(ctx context.Context, projectPath string) error
+	// Builds the package in projectPath with the release profile
+	BuildRelease(ctx context.Context, projectPath string) error
+}
+
+type cargoCli struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewCargoCli creates a new CargoCli
+func NewCargoCli(commandRunner exec.CommandRunner) CargoCli {
+	return &cargoCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *cargoCli) Name() string {
+	return "Cargo"
+}
+
+func (cli *cargoCli) InstallUrl() string {
+	return "https://www.rust-lang.org/tools/install"
+}
+
+func (cli *cargoCli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("cargo"); err != nil {
+		return err
+	}
+
+	cargoRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "cargo", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("cargo version: %s", cargoRes)
+	return nil
+}
+
+func (cli *cargoCli) Fetch(ctx context.Context, projectPath string) error {
+	runArgs := exec.NewRunArgs("cargo", "fetch").WithCwd(projectPath)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed to fetch dependencies for project '%s': %w", projectPath, err)
+	}
+
+	return nil
+}
+
+func (cli *cargoCli) BuildRelease(ctx context.Context, projectPath string) error {
+	runArgs := exec.NewRunArgs("cargo", "build", "--release").WithCwd(projectPath)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed to build project '%s': %w", projectPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// ComposerCli wraps the composer dependency manager of PHP
+type ComposerCli interface {
+	tools.ExternalTool
+	// Installs the dependencies of the project in projectPath
+	Install(ctx context.Context, projectPath string) error
+}
+
+type composerCli struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewComposerCli creates a new ComposerCli
+func NewComposerCli(commandRunner exec.CommandRunner) ComposerCli {
+	return &composerCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *composerCli) Name() string {
+	return "Composer"
+}
+
+func (cli *composerCli) InstallUrl() string {
+	return "https://getcomposer.org/download/"
+}
+
+func (cli *composerCli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("composer"); err != nil {
+		return err
+	}
+
+	composerRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "composer", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("composer version: %s", composerRes)
+	return nil
+}
+
+func (cli *composerCli) Install(ctx context.Context, projectPath string) error {
+	runArgs := exec.NewRunArgs("composer", "install", "--no-interaction").WithCwd(projectPath)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("failed to install dependencies for project '%s': %w", projectPath, err)
+	}
+
+	return nil
+}
@@ -2,12 +2,14 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 
@@ -18,6 +20,10 @@ import (
 
 const DefaultPlatform string = "linux/amd64"
 
+// hostPlatform is the platform of images built by docker without emulation. Building for other platforms requires
+// buildx.
+var hostPlatform = "linux/" + runtime.GOARCH
+
 type Docker interface {
 	tools.ExternalTool
 	Login(ctx context.Context, loginServer string, username string, password string) error
@@ -29,13 +35,31 @@ type Docker interface {
 		buildContext string,
 		name string,
 		buildArgs []string,
+		cache *BuildCache,
 		buildProgress io.Writer,
 	) (string, error)
+	BuildxVersion(ctx context.Context) (string, error)
+	BuildxDriver(ctx context.Context) (string, error)
 	Tag(ctx context.Context, cwd string, imageName string, tag string) error
 	Push(ctx context.Context, cwd string, tag string) error
 	Inspect(ctx context.Context, imageName string, format string) (string, error)
 }
 
+// BuildCache is the external cache that a build imports layers from and exports layers to, for example a registry cache
+// image such as 'type=registry,ref=contoso.azurecr.io/api:cache' or the GitHub Actions cache 'type=gha'. Builds with a
+// cache require docker buildx.
+type BuildCache struct {
+	// The cache sources, each passed as --cache-from
+	From []string
+	// The cache destinations, each passed as --cache-to
+	To []string
+}
+
+// IsEmpty returns true when the cache has no source and no destination.
+func (c *BuildCache) IsEmpty() bool {
+	return c == nil || (len(c.From) == 0 && len(c.To) == 0)
+}
+
 func NewDocker(commandRunner exec.CommandRunner) Docker {
 	return &docker{
 		commandRunner: commandRunner,
@@ -63,8 +87,11 @@ func (d *docker) Login(ctx context.Context, loginServer string, username string,
 }
 
 // Runs a Docker build for a given Dockerfile, writing the output of docker build to [stdOut] when it is
-// not nil. If the platform is not specified (empty) it defaults to amd64. If the build is successful,
-// the function returns the image id of the built image.
+// not nil. If the platform is not specified (empty) it defaults to amd64. Building for a platform other than the
+// platform of the host requires docker buildx, the error returned when a build fails explains when it is missing.
+// When the cache is not empty, the image is built with docker buildx, importing and exporting layers with the cache,
+// and loaded into the local image store. Callers check that buildx is available with BuildxVersion beforehand.
+// If the build is successful, the function returns the image id of the built image.
 func (d *docker) Build(
 	ctx context.Context,
 	cwd string,
@@ -73,6 +100,7 @@ func (d *docker) Build(
 	buildContext string,
 	tagName string,
 	buildArgs []string,
+	cache *BuildCache,
 	buildProgress io.Writer,
 ) (string, error) {
 	if strings.TrimSpace(platform) == "" {
@@ -91,11 +119,16 @@ func (d *docker) Build(
 	}
 	imgIdFile := filepath.Join(tmpFolder, "imgId")
 
-	args := []string{
+	args := []string{}
+	if !cache.IsEmpty() {
+		args = append(args, "buildx")
+	}
+
+	args = append(args,
 		"build",
 		"-f", dockerFilePath,
 		"--platform", platform,
-	}
+	)
 
 	if tagName != "" {
 		args = append(args, "-t", tagName)
@@ -104,6 +137,19 @@ func (d *docker) Build(
 	for _, arg := range buildArgs {
 		args = append(args, "--build-arg", arg)
 	}
+
+	if !cache.IsEmpty() {
+		for _, from := range cache.From {
+			args = append(args, "--cache-from", from)
+		}
+		for _, to := range cache.To {
+			args = append(args, "--cache-to", to)
+		}
+
+		// images built by buildx drivers other than the default 'docker' driver are only kept in the build cache
+		// unless they are loaded.
+		args = append(args, "--load")
+	}
 	args = append(args, buildContext)
 
 	// create a file with the docker img id
@@ -112,6 +158,11 @@ func (d *docker) Build(
 	// Build and produce output
 	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd)
 
+	// builds can take longer than other tools, so they can be given their own timeout
+	if timeout, has := exec.TimeoutFromEnv("AZD_BUILD_TIMEOUT"); has {
+		runArgs = runArgs.WithTimeout(timeout)
+	}
+
 	if buildProgress != nil {
 		// setting stderr and stdout both, as it's been noticed
 		// that docker log goes to stderr on macOS, but stdout on Ubuntu.
@@ -120,6 +171,14 @@ func (d *docker) Build(
 
 	_, err = d.commandRunner.Run(ctx, runArgs)
 	if err != nil {
+		// building for a platform other than the platform of the host fails when buildx is missing, in which case
+		// the error is replaced with one that explains why.
+		if !strings.EqualFold(platform, hostPlatform) {
+			if buildxErr := d.checkBuildxInstalled(ctx, platform); buildxErr != nil {
+				return "", buildxErr
+			}
+		}
+
 		return "", fmt.Errorf("building image: %w", err)
 	}
 
@@ -130,6 +189,49 @@ func (d *docker) Build(
 	return strings.TrimSpace(string(imgId)), nil
 }
 
+// checkBuildxInstalled returns an error when docker buildx, which is needed to build images for a platform other
+// than the platform of the host, is not available.
+func (d *docker) checkBuildxInstalled(ctx context.Context, platform string) error {
+	if _, err := d.BuildxVersion(ctx); err != nil {
+		return fmt.Errorf(
+			"building an image for platform '%s' on a '%s' host requires docker buildx, which is not available: %w. "+
+				"Install buildx (https://docs.docker.com/go/buildx/), or set 'docker.platform' for the service to '%s'",
+			platform,
+			hostPlatform,
+			err,
+			hostPlatform,
+		)
+	}
+
+	return nil
+}
+
+// BuildxVersion returns the version of docker buildx, or an error when buildx is not available.
+func (d *docker) BuildxVersion(ctx context.Context) (string, error) {
+	out, err := d.executeCommand(ctx, "", "buildx", "version")
+	if err != nil {
+		return "", fmt.Errorf("checking docker buildx version: %w", err)
+	}
+
+	return strings.TrimSpace(out.Stdout), nil
+}
+
+// BuildxDriver returns the driver of the current buildx builder, such as 'docker' or 'docker-container'.
+func (d *docker) BuildxDriver(ctx context.Context) (string, error) {
+	out, err := d.executeCommand(ctx, "", "buildx", "inspect")
+	if err != nil {
+		return "", fmt.Errorf("inspecting docker buildx builder: %w", err)
+	}
+
+	for _, line := range strings.Split(out.Stdout, "\n") {
+		if driver, found := strings.CutPrefix(strings.TrimSpace(line), "Driver:"); found {
+			return strings.TrimSpace(driver), nil
+		}
+	}
+
+	return "", errors.New("inspecting docker buildx builder: the driver of the builder was not found")
+}
+
 func (d *docker) Tag(ctx context.Context, cwd string, imageName string, tag string) error {
 	_, err := d.executeCommand(ctx, cwd, "tag", imageName, tag)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -67,6 +68,7 @@ func Test_DockerBuild(t *testing.T) {
 			imageName,
 			buildArgs,
 			nil,
+			nil,
 		)
 
 		require.Equal(t, true, ran)
@@ -114,6 +116,11 @@ func Test_DockerBuild(t *testing.T) {
 			}, errors.New(customErrorMessage)
 		})
 
+		// buildx is checked when a build for a platform other than the platform of the host fails
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx version")
+		}).Respond(exec.NewRunResult(0, "", ""))
+
 		result, err := docker.Build(
 			context.Background(),
 			cwd,
@@ -123,6 +130,7 @@ func Test_DockerBuild(t *testing.T) {
 			imageName,
 			buildArgs,
 			nil,
+			nil,
 		)
 
 		require.Equal(t, true, ran)
@@ -178,13 +186,209 @@ func Test_DockerBuildEmptyPlatform(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, buildArgs, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
 	require.Equal(t, mockedDockerImgId, result)
 }
 
+func Test_DockerBuildPlatform(t *testing.T) {
+	cwd := "."
+	dockerFile := "./Dockerfile"
+	dockerContext := "../"
+	imageName := "IMAGE_NAME"
+
+	originalHostPlatform := hostPlatform
+	t.Cleanup(func() {
+		hostPlatform = originalHostPlatform
+	})
+	hostPlatform = "linux/arm64"
+
+	setupBuild := func(mockContext *mocks.MockContext, buildErr error) *[]string {
+		buildArgs := &[]string{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker build")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			// extract img id file arg. "--iidfile" and path args are expected always at the end
+			argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+			*buildArgs = argsNoFile
+
+			if buildErr != nil {
+				return exec.NewRunResult(1, "", "unknown flag: --platform"), buildErr
+			}
+
+			err := os.WriteFile(value, []byte(mockedDockerImgId), 0600)
+			require.NoError(t, err)
+
+			return exec.NewRunResult(0, mockedDockerImgId, ""), nil
+		})
+
+		return buildArgs
+	}
+
+	t.Run("CrossPlatform", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewDocker(mockContext.CommandRunner)
+		buildArgs := setupBuild(mockContext, nil)
+
+		result, err := docker.Build(
+			context.Background(), cwd, dockerFile, "linux/amd64", dockerContext, imageName, nil, nil, nil)
+
+		require.NoError(t, err)
+		require.Equal(t, mockedDockerImgId, result)
+		require.Equal(t, []string{
+			"build",
+			"-f", dockerFile,
+			"--platform", "linux/amd64",
+			"-t", imageName,
+			dockerContext,
+		}, *buildArgs)
+	})
+
+	t.Run("MissingBuildx", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewDocker(mockContext.CommandRunner)
+		_ = setupBuild(mockContext, errors.New("exit code: 125"))
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx version")
+		}).SetError(errors.New("'buildx' is not a docker command"))
+
+		_, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, nil, nil, nil)
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires docker buildx")
+		require.Contains(t, err.Error(), "'linux/amd64' on a 'linux/arm64' host")
+	})
+
+	t.Run("HostPlatformError", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewDocker(mockContext.CommandRunner)
+		_ = setupBuild(mockContext, errors.New("exit code: 1"))
+
+		// buildx is not needed to build for the platform of the host, so it isn't checked
+		_, err := docker.Build(context.Background(), cwd, dockerFile, "linux/arm64", dockerContext, imageName, nil, nil, nil)
+
+		require.Error(t, err)
+		require.Equal(t, "building image: exit code: 1", err.Error())
+	})
+}
+
+func Test_DockerBuildCache(t *testing.T) {
+	cwd := "."
+	dockerFile := "./Dockerfile"
+	dockerContext := "../"
+	imageName := "IMAGE_NAME"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewDocker(mockContext.CommandRunner)
+
+	var buildArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		// extract img id file arg. "--iidfile" and path args are expected always at the end
+		argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+		buildArgs = argsNoFile
+
+		err := os.WriteFile(value, []byte(mockedDockerImgId), 0600)
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, mockedDockerImgId, ""), nil
+	})
+
+	result, err := docker.Build(
+		context.Background(),
+		cwd,
+		dockerFile,
+		"",
+		dockerContext,
+		imageName,
+		[]string{"foo=bar"},
+		&BuildCache{
+			From: []string{"type=registry,ref=contoso.azurecr.io/api:cache", "type=gha"},
+			To:   []string{"type=registry,ref=contoso.azurecr.io/api:cache,mode=max"},
+		},
+		nil,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, mockedDockerImgId, result)
+	require.Equal(t, []string{
+		"buildx", "build",
+		"-f", dockerFile,
+		"--platform", DefaultPlatform,
+		"-t", imageName,
+		"--build-arg", "foo=bar",
+		"--cache-from", "type=registry,ref=contoso.azurecr.io/api:cache",
+		"--cache-from", "type=gha",
+		"--cache-to", "type=registry,ref=contoso.azurecr.io/api:cache,mode=max",
+		"--load",
+		dockerContext,
+	}, buildArgs)
+}
+
+func Test_DockerBuildTimeout(t *testing.T) {
+	t.Setenv("AZD_BUILD_TIMEOUT", "45m")
+
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewDocker(mockContext.CommandRunner)
+
+	var timeout *time.Duration
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		timeout = args.Timeout
+
+		err := os.WriteFile(args.Args[len(args.Args)-1], []byte(mockedDockerImgId), 0600)
+		require.NoError(t, err)
+
+		return exec.NewRunResult(0, mockedDockerImgId, ""), nil
+	})
+
+	_, err := docker.Build(
+		context.Background(), ".", "./Dockerfile", "", "../", "IMAGE_NAME", nil, nil, nil,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, timeout)
+	require.Equal(t, 45*time.Minute, *timeout)
+}
+
+func Test_DockerBuildxVersion(t *testing.T) {
+	t.Run("Installed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx version")
+		}).Respond(exec.NewRunResult(0, "github.com/docker/buildx v0.12.1 30feaa1\n", ""))
+
+		version, err := NewDocker(mockContext.CommandRunner).BuildxVersion(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "github.com/docker/buildx v0.12.1 30feaa1", version)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx version")
+		}).SetError(errors.New("'buildx' is not a docker command"))
+
+		_, err := NewDocker(mockContext.CommandRunner).BuildxVersion(context.Background())
+		require.ErrorContains(t, err, "'buildx' is not a docker command")
+	})
+}
+
+func Test_DockerBuildxDriver(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker buildx inspect")
+	}).Respond(exec.NewRunResult(0, "Name:          default\nDriver:        docker\n\nNodes:\nName:      default\n", ""))
+
+	driver, err := NewDocker(mockContext.CommandRunner).BuildxDriver(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "docker", driver)
+}
+
 func Test_DockerBuildArgsEmpty(t *testing.T) {
 	ran := false
 	cwd := "."
@@ -226,7 +430,7 @@ func Test_DockerBuildArgsEmpty(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, buildArgs, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -276,7 +480,7 @@ func Test_DockerBuildArgsMultiple(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, buildArgs, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -23,7 +23,7 @@ type DotNetCli interface {
 	tools.ExternalTool
 	Restore(ctx context.Context, project string) error
 	Build(ctx context.Context, project string, configuration string, output string) error
-	Publish(ctx context.Context, project string, configuration string, output string) error
+	Publish(ctx context.Context, project string, configuration string, output string, options *PublishOptions) error
 	PublishContainer(ctx context.Context, project string, configuration string, imageName string, server string) error
 	InitializeSecret(ctx context.Context, project string) error
 	PublishAppHostManifest(ctx context.Context, hostProject string, manifestPath string) error
@@ -100,16 +100,26 @@ func (cli *dotNetCli) Build(ctx context.Context, project string, configuration s
 	return nil
 }
 
-func (cli *dotNetCli) Publish(ctx context.Context, project string, configuration string, output string) error {
-	runArgs := exec.NewRunArgs("dotnet", "publish", project)
-	if configuration != "" {
-		runArgs = runArgs.AppendParams("-c", configuration)
-	}
-
-	if output != "" {
-		runArgs = runArgs.AppendParams("--output", output)
-	}
+// PublishOptions are the optional settings of dotnet publish.
+type PublishOptions struct {
+	// The MSBuild publish profile, for example 'FolderProfile'.
+	PublishProfile string
+	// The runtime identifier the app is published for, for example 'linux-x64'.
+	RuntimeIdentifier string
+	// When true, the app is compiled ahead-of-time to native code.
+	PublishAot bool
+	// When true, the assemblies of the app are compiled to the ReadyToRun format.
+	PublishReadyToRun bool
+}
 
+func (cli *dotNetCli) Publish(
+	ctx context.Context,
+	project string,
+	configuration string,
+	output string,
+	options *PublishOptions,
+) error {
+	runArgs := exec.NewRunArgs("dotnet", publishArgs(project, configuration, output, options)...)
 	_, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("dotnet publish on project '%s' failed: %w", project, err)
@@ -117,6 +127,35 @@ func (cli *dotNetCli) Publish(ctx context.Context, project string, configuration
 	return nil
 }
 
+// publishArgs returns the arguments of dotnet publish for the project.
+func publishArgs(project string, configuration string, output string, options *PublishOptions) []string {
+	args := []string{"publish", project}
+	if configuration != "" {
+		args = append(args, "-c", configuration)
+	}
+
+	if options != nil {
+		if options.RuntimeIdentifier != "" {
+			args = append(args, "-r", options.RuntimeIdentifier)
+		}
+		if options.PublishProfile != "" {
+			args = append(args, fmt.Sprintf("-p:PublishProfile=%s", options.PublishProfile))
+		}
+		if options.PublishAot {
+			args = append(args, "-p:PublishAot=true")
+		}
+		if options.PublishReadyToRun {
+			args = append(args, "-p:PublishReadyToRun=true")
+		}
+	}
+
+	if output != "" {
+		args = append(args, "--output", output)
+	}
+
+	return args
+}
+
 func (cli *dotNetCli) PublishAppHostManifest(
 	ctx context.Context, hostProject string, manifestPath string,
 ) error {
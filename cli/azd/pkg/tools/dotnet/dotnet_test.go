@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package dotnet
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Publish(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration string
+		output        string
+		options       *PublishOptions
+		want          []string
+	}{
+		{
+			name:          "Defaults",
+			configuration: "Release",
+			output:        "out",
+			want:          []string{"publish", "api.csproj", "-c", "Release", "--output", "out"},
+		},
+		{
+			name:    "NoConfigurationOrOutput",
+			options: &PublishOptions{},
+			want:    []string{"publish", "api.csproj"},
+		},
+		{
+			name:          "PublishProfile",
+			configuration: "Debug",
+			output:        "out",
+			options:       &PublishOptions{PublishProfile: "FolderProfile"},
+			want: []string{
+				"publish", "api.csproj", "-c", "Debug", "-p:PublishProfile=FolderProfile", "--output", "out",
+			},
+		},
+		{
+			name:          "RuntimeIdentifier",
+			configuration: "Release",
+			output:        "out",
+			options:       &PublishOptions{RuntimeIdentifier: "linux-x64"},
+			want:          []string{"publish", "api.csproj", "-c", "Release", "-r", "linux-x64", "--output", "out"},
+		},
+		{
+			name:          "PublishAot",
+			configuration: "Release",
+			output:        "out",
+			options:       &PublishOptions{RuntimeIdentifier: "linux-x64", PublishAot: true},
+			want: []string{
+				"publish", "api.csproj", "-c", "Release", "-r", "linux-x64", "-p:PublishAot=true", "--output", "out",
+			},
+		},
+		{
+			name:          "PublishReadyToRun",
+			configuration: "Release",
+			output:        "out",
+			options:       &PublishOptions{RuntimeIdentifier: "win-x64", PublishReadyToRun: true},
+			want: []string{
+				"publish", "api.csproj", "-c", "Release", "-r", "win-x64", "-p:PublishReadyToRun=true", "--output", "out",
+			},
+		},
+		{
+			name:          "All",
+			configuration: "Release",
+			output:        "out",
+			options: &PublishOptions{
+				PublishProfile:    "FolderProfile",
+				RuntimeIdentifier: "linux-arm64",
+				PublishReadyToRun: true,
+			},
+			want: []string{
+				"publish", "api.csproj", "-c", "Release",
+				"-r", "linux-arm64",
+				"-p:PublishProfile=FolderProfile",
+				"-p:PublishReadyToRun=true",
+				"--output", "out",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runArgs exec.RunArgs
+
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "dotnet publish")
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			cli := NewDotNetCli(mockContext.CommandRunner)
+			err := cli.Publish(*mockContext.Context, "api.csproj", tt.configuration, tt.output, tt.options)
+			require.NoError(t, err)
+
+			require.Equal(t, "dotnet", runArgs.Cmd)
+			require.Equal(t, tt.want, runArgs.Args)
+		})
+	}
+}
@@ -5,11 +5,14 @@ package git
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
+	"os"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -22,10 +25,16 @@ type GitCli interface {
 	tools.ExternalTool
 	GetRemoteUrl(ctx context.Context, string, remoteName string) (string, error)
 	ShallowClone(ctx context.Context, repositoryPath string, branch string, target string) error
+	// Clones the commit of a branch, tag or commit SHA of a repository at depth 1, or the commit of the default branch when
+	// no ref is set. ErrRefNotFound is returned when the repository doesn't have the ref, ErrRepositoryNotFound when the
+	// repository doesn't exist and ErrAccessDenied when the credentials don't grant access to the repository.
+	ShallowCloneRef(ctx context.Context, repositoryPath string, target string, options CloneOptions) error
 	InitRepo(ctx context.Context, repositoryPath string) error
 	AddRemote(ctx context.Context, repositoryPath string, remoteName string, remoteUrl string) error
 	UpdateRemote(ctx context.Context, repositoryPath string, remoteName string, remoteUrl string) error
 	GetCurrentBranch(ctx context.Context, repositoryPath string) (string, error)
+	// Gets the abbreviated SHA of the commit checked out in the repository
+	GetShortCommitSha(ctx context.Context, repositoryPath string) (string, error)
 	AddFile(ctx context.Context, repositoryPath string, filespec string) error
 	Commit(ctx context.Context, repositoryPath string, message string) error
 	PushUpstream(ctx context.Context, repositoryPath string, origin string, branch string) error
@@ -90,28 +99,148 @@ func (cli *gitCli) Name() string {
 }
 
 func (cli *gitCli) ShallowClone(ctx context.Context, repositoryPath string, branch string, target string) error {
+	return cli.shallowClone(ctx, repositoryPath, branch, target, "")
+}
+
+func (cli *gitCli) shallowClone(
+	ctx context.Context, repositoryPath string, branch string, target string, token string) error {
 	args := []string{"clone", "--depth", "1", repositoryPath}
 	if branch != "" {
 		args = append(args, "--branch", branch)
 	}
 	args = append(args, target)
 
-	// Do not call `newRunArgs()` here because we don't want to apply the codespaces special patch that removes
-	// default authentication. `git clone` should work for private repos within a codespace with default auth.
-	// See: https://github.com/Azure/azure-dev/issues/2582
-	runArgs := exec.NewRunArgs("git", args...)
-	_, err := cli.commandRunner.Run(ctx, runArgs)
+	_, err := cli.commandRunner.Run(ctx, remoteRunArgs(token, args...))
+	if err != nil {
+		return fmt.Errorf("failed to clone repository %s: %w", repositoryPath, remoteError(repositoryPath, err))
+	}
+
+	return nil
+}
+
+// CloneOptions are the options of ShallowCloneRef
+type CloneOptions struct {
+	// The branch, tag or full commit SHA to clone, the default branch when empty
+	Ref string
+	// A personal access token used to authenticate to HTTPS repositories. When empty, git authenticates with its
+	// credential helpers, or with the SSH agent for SSH repositories.
+	Token string
+}
+
+func (cli *gitCli) ShallowCloneRef(
+	ctx context.Context, repositoryPath string, target string, options CloneOptions) error {
+	ref := options.Ref
+	if ref == "" {
+		return cli.shallowClone(ctx, repositoryPath, "", target, options.Token)
+	}
+
+	res, err := cli.commandRunner.Run(ctx, remoteRunArgs(
+		options.Token, "ls-remote", "--heads", "--tags", repositoryPath, "refs/heads/"+ref, "refs/tags/"+ref))
 	if err != nil {
-		return fmt.Errorf("failed to clone repository %s: %w", repositoryPath, err)
+		return fmt.Errorf(
+			"failed to list the refs of repository %s: %w", repositoryPath, remoteError(repositoryPath, err))
+	}
+
+	if strings.TrimSpace(res.Stdout) != "" {
+		return cli.shallowClone(ctx, repositoryPath, ref, target, options.Token)
+	}
+
+	if !commitShaRegex.MatchString(ref) {
+		return fmt.Errorf(
+			"%w: '%s' isn't a branch or tag of repository %s, and commits must be given by their full SHA",
+			ErrRefNotFound, ref, repositoryPath)
+	}
+
+	// commits can't be cloned by their SHA, so the commit is fetched into a new repository instead
+	if err := cli.InitRepo(ctx, target); err != nil {
+		return err
+	}
+
+	if err := cli.AddRemote(ctx, target, "origin", repositoryPath); err != nil {
+		return err
+	}
+
+	_, err = cli.commandRunner.Run(ctx, remoteRunArgs(options.Token, "-C", target, "fetch", "--depth", "1", "origin", ref))
+	if err != nil && refNotFoundRegex.MatchString(err.Error()) {
+		log.Printf("fetching commit %s of %s: %v", ref, repositoryPath, err)
+		return fmt.Errorf("%w: '%s' isn't a commit of repository %s", ErrRefNotFound, ref, repositoryPath)
+	} else if err != nil {
+		return fmt.Errorf(
+			"failed to fetch commit %s of repository %s: %w", ref, repositoryPath, remoteError(repositoryPath, err))
+	}
+
+	if _, err := cli.commandRunner.Run(ctx, newRunArgs("-C", target, "checkout", "--quiet", "FETCH_HEAD")); err != nil {
+		return fmt.Errorf("failed to check out commit %s: %w", ref, err)
 	}
 
 	return nil
 }
 
+// remoteRunArgs returns the arguments of a git command that reads from a remote repository. When token is set, it's sent
+// in the authorization header of the HTTPS requests, which is passed in the environment of git so that it isn't part of
+// the logged command line.
+//
+// Do not call `newRunArgs()` here because we don't want to apply the codespaces special patch that removes
+// default authentication. Remote commands should work for private repos within a codespace with default auth.
+// See: https://github.com/Azure/azure-dev/issues/2582
+func remoteRunArgs(token string, args ...string) exec.RunArgs {
+	runArgs := exec.NewRunArgs("git", args...)
+	if token != "" {
+		// basic authentication with a personal access token as the password is accepted by both GitHub and Azure DevOps
+		credential := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+
+		// the header is added after the configuration the user passes in the environment of git, which is kept
+		count, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+		if err != nil || count < 0 {
+			count = 0
+		}
+
+		runArgs = runArgs.WithEnv([]string{
+			fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1),
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=http.extraHeader", count),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: Basic %s", count, credential),
+		})
+	}
+
+	return runArgs
+}
+
+var accessDeniedRegex = regexp.MustCompile(
+	`(?i)authentication failed|could not read (username|password)|returned error: 40[13]|permission denied|` +
+		`access denied|terminal prompts disabled`)
+var repositoryNotFoundRegex = regexp.MustCompile(
+	`(?i)repository not found|returned error: 404|does not appear to be a git repository|repository '.*' not found`)
+
+// refNotFoundRegex matches the errors of fetching an object that the remote repository doesn't have, or doesn't serve.
+var refNotFoundRegex = regexp.MustCompile(
+	`(?i)not our ref|couldn't find remote ref|unadvertised object|no such remote ref`)
+
+// remoteError classifies the error of a git command that read from a remote repository, wrapping it with
+// ErrAccessDenied when authentication failed or ErrRepositoryNotFound when the repository doesn't exist.
+func remoteError(repositoryPath string, err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	switch {
+	case accessDeniedRegex.MatchString(err.Error()):
+		return fmt.Errorf("%w to %s: %w", ErrAccessDenied, repositoryPath, err)
+	case repositoryNotFoundRegex.MatchString(err.Error()):
+		return fmt.Errorf("%w, %s: %w", ErrRepositoryNotFound, repositoryPath, err)
+	default:
+		return err
+	}
+}
+
 var noSuchRemoteRegex = regexp.MustCompile("(fatal|error): No such remote")
 var notGitRepositoryRegex = regexp.MustCompile("(fatal|error): not a git repository")
 var ErrNoSuchRemote = errors.New("no such remote")
 var ErrNotRepository = errors.New("not a git repository")
+var ErrRefNotFound = errors.New("ref not found")
+var ErrRepositoryNotFound = errors.New("repository not found")
+var ErrAccessDenied = errors.New("access denied")
+var commitShaRegex = regexp.MustCompile("^[0-9a-fA-F]{40}$")
 var gitUntrackedFileRegex = regexp.MustCompile("untracked files present|new file")
 
 func (cli *gitCli) GetRemoteUrl(ctx context.Context, repositoryPath string, remoteName string) (string, error) {
@@ -140,6 +269,18 @@ func (cli *gitCli) GetCurrentBranch(ctx context.Context, repositoryPath string)
 	return strings.TrimSpace(res.Stdout), nil
 }
 
+func (cli *gitCli) GetShortCommitSha(ctx context.Context, repositoryPath string) (string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "rev-parse", "--short", "HEAD")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if notGitRepositoryRegex.MatchString(res.Stderr) {
+		return "", ErrNotRepository
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get current commit: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
 func (cli *gitCli) InitRepo(ctx context.Context, repositoryPath string) error {
 	runArgs := newRunArgs("-C", repositoryPath, "init")
 	_, err := cli.commandRunner.Run(ctx, runArgs)
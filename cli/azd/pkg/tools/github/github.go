@@ -14,6 +14,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -37,6 +38,9 @@ type GitHubCli interface {
 	ListSecrets(ctx context.Context, repo string) error
 	SetSecret(ctx context.Context, repo string, name string, value string) error
 	SetVariable(ctx context.Context, repoSlug string, name string, value string) error
+	CreateEnvironment(ctx context.Context, repoSlug string, environment string) error
+	SetEnvironmentSecret(ctx context.Context, repoSlug string, environment string, name string, value string) error
+	SetEnvironmentVariable(ctx context.Context, repoSlug string, environment string, name string, value string) error
 	Login(ctx context.Context, hostname string) error
 	ListRepositories(ctx context.Context) ([]GhCliRepository, error)
 	ViewRepository(ctx context.Context, name string) (GhCliRepository, error)
@@ -130,6 +134,9 @@ var (
 	ErrUserNotAuthorized    = errors.New("user is not authorized. " +
 		"Try running gh auth refresh with the required scopes to request additional authorization")
 	ErrRepositoryNameInUse = errors.New("repository name already in use")
+	// ErrEnvironmentsNotSupported is returned when GitHub doesn't allow creating environments in the repository, which
+	// happens for private repositories on plans without environments, or without admin access to the repository
+	ErrEnvironmentsNotSupported = errors.New("environments are not available for the repository")
 
 	// The hostname of the public GitHub service.
 	GitHubHostName = "github.com"
@@ -241,6 +248,41 @@ func (cli *ghCli) SetVariable(ctx context.Context, repoSlug string, name string,
 	return nil
 }
 
+// CreateEnvironment creates the GitHub Actions environment of the repository, or does nothing when it already exists.
+// Returns ErrEnvironmentsNotSupported when the repository can't have environments.
+func (cli *ghCli) CreateEnvironment(ctx context.Context, repoSlug string, environment string) error {
+	runArgs := cli.newRunArgs(
+		"api", "--method", "PUT", fmt.Sprintf("/repos/%s/environments/%s", repoSlug, url.PathEscape(environment)))
+	res, err := cli.run(ctx, runArgs)
+	if err != nil && environmentNotAvailableRegex.MatchString(res.Stderr) {
+		return fmt.Errorf("creating environment %s: %w", environment, ErrEnvironmentsNotSupported)
+	} else if err != nil {
+		return fmt.Errorf("failed running gh api to create environment %s: %w", environment, err)
+	}
+
+	return nil
+}
+
+func (cli *ghCli) SetEnvironmentSecret(
+	ctx context.Context, repoSlug string, environment string, name string, value string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "set", name, "--env", environment, "--body", value)
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh secret set: %w", err)
+	}
+	return nil
+}
+
+func (cli *ghCli) SetEnvironmentVariable(
+	ctx context.Context, repoSlug string, environment string, name string, value string) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "variable", "set", name, "--env", environment, "--body", value)
+	_, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh variable set: %w", err)
+	}
+	return nil
+}
+
 // cGhCliVersionRegexp fetches the version number from the output of gh --version, which looks like this:
 //
 // gh version 2.6.0 (2022-03-15)
@@ -396,6 +438,10 @@ var notLoggedIntoAnyGitHubHostsMessageRegex = regexp.MustCompile(
 	"You are not logged into any GitHub hosts. Run gh auth login to authenticate.",
 )
 
+// GitHub responds 404 when the plan of a private repository doesn't include environments, or when the user can't
+// administer the repository, and 422 when the environment can't be created
+var environmentNotAvailableRegex = regexp.MustCompile(`\(HTTP (404|422)\)`)
+
 var isUserNotAuthorizedMessageRegex = regexp.MustCompile(
 	"HTTP 403: Resource not accessible by integration",
 )
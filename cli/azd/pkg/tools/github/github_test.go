@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -40,6 +41,61 @@ func TestRepositoryNameInUse(t *testing.T) {
 	require.True(t, repositoryNameInUseRegex.MatchString("GraphQL: Name already exists on this account (createRepository)"))
 }
 
+func TestCreateEnvironment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		var ranArgs []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "api")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ranArgs = args.Args
+			return exec.NewRunResult(0, `{"name":"prod west"}`, ""), nil
+		})
+
+		cli := &ghCli{commandRunner: mockContext.CommandRunner, path: "gh"}
+		err := cli.CreateEnvironment(*mockContext.Context, "Azure/azure-dev", "prod west")
+		require.NoError(t, err)
+		require.Equal(t, []string{"api", "--method", "PUT", "/repos/Azure/azure-dev/environments/prod%20west"}, ranArgs)
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "api")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, `{"message":"Not Found"}`, "gh: Not Found (HTTP 404)"), errors.New("exit code: 1")
+		})
+
+		cli := &ghCli{commandRunner: mockContext.CommandRunner, path: "gh"}
+		err := cli.CreateEnvironment(*mockContext.Context, "Azure/azure-dev", "production")
+		require.ErrorIs(t, err, ErrEnvironmentsNotSupported)
+	})
+}
+
+func TestSetEnvironmentValues(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	ranArgs := [][]string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "set")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ranArgs = append(ranArgs, args.Args)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := &ghCli{commandRunner: mockContext.CommandRunner, path: "gh"}
+	err := cli.SetEnvironmentVariable(*mockContext.Context, "Azure/azure-dev", "production", "AZURE_ENV_NAME", "prod")
+	require.NoError(t, err)
+	err = cli.SetEnvironmentSecret(*mockContext.Context, "Azure/azure-dev", "production", "AZURE_CREDENTIALS", "{}")
+	require.NoError(t, err)
+
+	require.Equal(t, [][]string{
+		{"-R", "Azure/azure-dev", "variable", "set", "AZURE_ENV_NAME", "--env", "production", "--body", "prod"},
+		{"-R", "Azure/azure-dev", "secret", "set", "AZURE_CREDENTIALS", "--env", "production", "--body", "{}"},
+	}, ranArgs)
+}
+
 func TestZipGhNotFound(t *testing.T) {
 	testPath := t.TempDir()
 	expectedPhrase := "this will be inside a zip file"
@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Executes commands against the Helm CLI, the package manager for Kubernetes
+type HelmCli interface {
+	tools.ExternalTool
+	// Installs the chart as the specified release, or upgrades the release when it is already installed
+	Upgrade(ctx context.Context, release string, chart string, flags *UpgradeFlags) (*exec.RunResult, error)
+}
+
+// Helm CLI flags of the upgrade command
+type UpgradeFlags struct {
+	// The namespace of the release
+	Namespace string
+	// The values files, applied in order
+	ValuesFiles []string
+	// Values set on the command line, which override the values of the values files
+	Values map[string]string
+	// The working directory of the command, values files are relative to it
+	Cwd string
+}
+
+type helmCli struct {
+	commandRunner exec.CommandRunner
+}
+
+// Creates a new Helm CLI instance
+func NewHelmCli(commandRunner exec.CommandRunner) HelmCli {
+	return &helmCli{
+		commandRunner: commandRunner,
+	}
+}
+
+// Checks whether or not the Helm CLI is installed and available within the PATH
+func (cli *helmCli) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("helm"); err != nil {
+		return err
+	}
+
+	// We don't have a minimum required version of helm today, but for diagnostics purposes, let's fetch and log the
+	// version of helm we're using.
+	if ver, err := tools.ExecuteCommand(ctx, cli.commandRunner, "helm", "version", "--short"); err != nil {
+		log.Printf("error fetching helm version: %s", err)
+	} else {
+		log.Printf("helm version: %s", ver)
+	}
+
+	return nil
+}
+
+// Returns the installation URL to install the Helm CLI
+func (cli *helmCli) InstallUrl() string {
+	return "https://helm.sh/docs/intro/install/"
+}
+
+// Gets the name of the Tool
+func (cli *helmCli) Name() string {
+	return "Helm"
+}
+
+// Installs the chart as the specified release, or upgrades the release when it is already installed
+func (cli *helmCli) Upgrade(
+	ctx context.Context,
+	release string,
+	chart string,
+	flags *UpgradeFlags,
+) (*exec.RunResult, error) {
+	runArgs := exec.NewRunArgs("helm", "upgrade", "--install", release, chart)
+
+	if flags != nil {
+		if flags.Cwd != "" {
+			runArgs = runArgs.WithCwd(flags.Cwd)
+		}
+		if flags.Namespace != "" {
+			runArgs = runArgs.AppendParams("--namespace", flags.Namespace)
+		}
+		for _, valuesFile := range flags.ValuesFiles {
+			runArgs = runArgs.AppendParams("--values", valuesFile)
+		}
+
+		keys := maps.Keys(flags.Values)
+		slices.Sort(keys)
+		for _, key := range keys {
+			runArgs = runArgs.AppendParams("--set", fmt.Sprintf("%s=%s", key, flags.Values[key]))
+		}
+	}
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("helm upgrade --install: %w", withStderr(res, err))
+	}
+
+	return &res, nil
+}
+
+// withStderr adds the error output of helm to err. Exit errors of the command runner already include the output.
+func withStderr(res exec.RunResult, err error) error {
+	var exitErr *exec.ExitError
+	stderr := strings.TrimSpace(res.Stderr)
+	if errors.As(err, &exitErr) || stderr == "" {
+		return err
+	}
+
+	return fmt.Errorf("%w, stderr: %s", err, stderr)
+}
@@ -0,0 +1,63 @@
+package helm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Upgrade(t *testing.T) {
+	t.Run("Args", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm upgrade --install")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		cli := NewHelmCli(mockContext.CommandRunner)
+		_, err := cli.Upgrade(*mockContext.Context, "api", "./chart", &UpgradeFlags{
+			Namespace:   "test-namespace",
+			ValuesFiles: []string{"values.yaml", "values.dev.yaml"},
+			Values: map[string]string{
+				"image.tag":        "azd-deploy-0",
+				"image.repository": "REGISTRY.azurecr.io/test-app/api-test",
+			},
+			Cwd: "src/api",
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "helm", runArgs.Cmd)
+		require.Equal(t, "src/api", runArgs.Cwd)
+		require.Equal(t, []string{
+			"upgrade", "--install", "api", "./chart",
+			"--namespace", "test-namespace",
+			"--values", "values.yaml",
+			"--values", "values.dev.yaml",
+			"--set", "image.repository=REGISTRY.azurecr.io/test-app/api-test",
+			"--set", "image.tag=azd-deploy-0",
+		}, runArgs.Args)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "helm upgrade --install")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", "Error: path \"./chart\" not found\n"), errors.New("exit code: 1")
+		})
+
+		cli := NewHelmCli(mockContext.CommandRunner)
+		res, err := cli.Upgrade(*mockContext.Context, "api", "./chart", nil)
+		require.Nil(t, res)
+		require.ErrorContains(t, err, "exit code: 1, stderr: Error: path \"./chart\" not found")
+	})
+}
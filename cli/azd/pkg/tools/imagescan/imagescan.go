@@ -0,0 +1,224 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package imagescan wraps the scanners that find vulnerabilities in container images, Trivy and docker scout.
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// Severity is the severity of a vulnerability, as reported by the scanners.
+type Severity string
+
+const (
+	SeverityCritical Severity = "CRITICAL"
+	SeverityHigh     Severity = "HIGH"
+	SeverityMedium   Severity = "MEDIUM"
+	SeverityLow      Severity = "LOW"
+	// SeverityUnknown is the severity of vulnerabilities the scanner hasn't rated, or rated with a severity azd doesn't
+	// know.
+	SeverityUnknown Severity = "UNKNOWN"
+)
+
+// Severities lists the severities from the most to the least severe.
+var Severities = []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityUnknown}
+
+func parseSeverity(severity string) Severity {
+	s := Severity(strings.ToUpper(strings.TrimSpace(severity)))
+	switch s {
+	case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+		return s
+	default:
+		return SeverityUnknown
+	}
+}
+
+// Result is the result of the scan of an image.
+type Result struct {
+	// The name of the scanner that scanned the image
+	Scanner string `json:"scanner"`
+	// The image that was scanned
+	Image string `json:"image"`
+	// The number of vulnerabilities found, by severity
+	Vulnerabilities map[Severity]int `json:"vulnerabilities"`
+}
+
+func newResult(scanner string, image string, severities []string) *Result {
+	result := &Result{
+		Scanner:         scanner,
+		Image:           image,
+		Vulnerabilities: map[Severity]int{},
+	}
+
+	for _, severity := range Severities {
+		result.Vulnerabilities[severity] = 0
+	}
+
+	for _, severity := range severities {
+		result.Vulnerabilities[parseSeverity(severity)]++
+	}
+
+	return result
+}
+
+// Count returns the number of vulnerabilities with one of the severities.
+func (r *Result) Count(severities ...Severity) int {
+	count := 0
+	for _, severity := range severities {
+		count += r.Vulnerabilities[severity]
+	}
+
+	return count
+}
+
+// Scanner scans container images for vulnerabilities.
+type Scanner interface {
+	tools.ExternalTool
+	// Scan scans the image in the local image store, writing the progress of the scanner to [progress] when it is not
+	// nil.
+	Scan(ctx context.Context, image string, progress io.Writer) (*Result, error)
+}
+
+// ErrNoScanner is returned by Find when none of the scanners is installed.
+var ErrNoScanner = errors.New("no container image scanner is installed")
+
+// Find returns the first of the scanners that is installed, or ErrNoScanner.
+func Find(ctx context.Context, scanners ...Scanner) (Scanner, error) {
+	for _, scanner := range scanners {
+		err := scanner.CheckInstalled(ctx)
+		if err == nil {
+			return scanner, nil
+		}
+
+		log.Printf("image scanner %s is not available: %v", scanner.Name(), err)
+	}
+
+	return nil, ErrNoScanner
+}
+
+// NewTrivy creates a Scanner that scans images with Trivy.
+func NewTrivy(commandRunner exec.CommandRunner) Scanner {
+	return &trivy{
+		commandRunner: commandRunner,
+	}
+}
+
+type trivy struct {
+	commandRunner exec.CommandRunner
+}
+
+func (t *trivy) CheckInstalled(ctx context.Context) error {
+	return tools.ToolInPath("trivy")
+}
+
+func (t *trivy) InstallUrl() string {
+	return "https://aquasecurity.github.io/trivy/latest/getting-started/installation/"
+}
+
+func (t *trivy) Name() string {
+	return "Trivy"
+}
+
+// Scan runs trivy image, which writes the JSON report to stdout and its progress to stderr.
+func (t *trivy) Scan(ctx context.Context, image string, progress io.Writer) (*Result, error) {
+	runArgs := exec.NewRunArgs("trivy", "image", "--format", "json", "--scanners", "vuln", image)
+	if progress != nil {
+		runArgs = runArgs.WithStdErr(progress)
+	}
+
+	res, err := t.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("scanning image %s with trivy: %w", image, err)
+	}
+
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				Severity string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("parsing trivy report: %w", err)
+	}
+
+	severities := []string{}
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			severities = append(severities, vulnerability.Severity)
+		}
+	}
+
+	return newResult(t.Name(), image, severities), nil
+}
+
+// NewDockerScout creates a Scanner that scans images with the docker scout plugin.
+func NewDockerScout(commandRunner exec.CommandRunner) Scanner {
+	return &dockerScout{
+		commandRunner: commandRunner,
+	}
+}
+
+type dockerScout struct {
+	commandRunner exec.CommandRunner
+}
+
+func (d *dockerScout) CheckInstalled(ctx context.Context) error {
+	if err := tools.ToolInPath("docker"); err != nil {
+		return err
+	}
+
+	if _, err := tools.ExecuteCommand(ctx, d.commandRunner, "docker", "scout", "version"); err != nil {
+		return fmt.Errorf("checking docker scout version: %w", err)
+	}
+
+	return nil
+}
+
+func (d *dockerScout) InstallUrl() string {
+	return "https://docs.docker.com/scout/install/"
+}
+
+func (d *dockerScout) Name() string {
+	return "docker scout"
+}
+
+// Scan runs docker scout cves against the local image, which writes the report in the GitLab container scanning format to
+// stdout and its progress to stderr.
+func (d *dockerScout) Scan(ctx context.Context, image string, progress io.Writer) (*Result, error) {
+	runArgs := exec.NewRunArgs("docker", "scout", "cves", "--format", "gitlab", "local://"+image)
+	if progress != nil {
+		runArgs = runArgs.WithStdErr(progress)
+	}
+
+	res, err := d.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("scanning image %s with docker scout: %w", image, err)
+	}
+
+	var report struct {
+		Vulnerabilities []struct {
+			Severity string `json:"severity"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("parsing docker scout report: %w", err)
+	}
+
+	severities := make([]string, 0, len(report.Vulnerabilities))
+	for _, vulnerability := range report.Vulnerabilities {
+		severities = append(severities, vulnerability.Severity)
+	}
+
+	return newResult(d.Name(), image, severities), nil
+}
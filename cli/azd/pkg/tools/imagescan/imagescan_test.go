@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package imagescan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const trivyReport = `{
+	"Results": [
+		{
+			"Target": "api:latest (debian 12.5)",
+			"Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2024-0001", "Severity": "CRITICAL"},
+				{"VulnerabilityID": "CVE-2024-0002", "Severity": "HIGH"},
+				{"VulnerabilityID": "CVE-2024-0003", "Severity": "LOW"}
+			]
+		},
+		{
+			"Target": "app/package-lock.json",
+			"Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2024-0004", "Severity": "HIGH"}
+			]
+		},
+		{
+			"Target": "app/requirements.txt"
+		}
+	]
+}`
+
+const dockerScoutReport = `{
+	"version": "15.0.6",
+	"vulnerabilities": [
+		{"id": "CVE-2024-0001", "severity": "Critical"},
+		{"id": "CVE-2024-0002", "severity": "Medium"},
+		{"id": "CVE-2024-0003", "severity": "Unspecified"}
+	]
+}`
+
+func TestTrivyScan(t *testing.T) {
+	t.Run("Report", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		progress := &bytes.Buffer{}
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "trivy image")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Equal(t, []string{"image", "--format", "json", "--scanners", "vuln", "api:latest"}, args.Args)
+
+			_, err := io.WriteString(args.Stderr, "scanning api:latest")
+			require.NoError(t, err)
+
+			return exec.NewRunResult(0, trivyReport, ""), nil
+		})
+
+		result, err := NewTrivy(mockContext.CommandRunner).Scan(*mockContext.Context, "api:latest", progress)
+		require.NoError(t, err)
+		require.Equal(t, &Result{
+			Scanner: "Trivy",
+			Image:   "api:latest",
+			Vulnerabilities: map[Severity]int{
+				SeverityCritical: 1,
+				SeverityHigh:     2,
+				SeverityMedium:   0,
+				SeverityLow:      1,
+				SeverityUnknown:  0,
+			},
+		}, result)
+		require.Equal(t, 3, result.Count(SeverityCritical, SeverityHigh))
+		require.Equal(t, "scanning api:latest", progress.String())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "trivy image")
+		}).SetError(errors.New("image not found"))
+
+		_, err := NewTrivy(mockContext.CommandRunner).Scan(*mockContext.Context, "api:latest", nil)
+		require.ErrorContains(t, err, "image not found")
+	})
+}
+
+func TestDockerScoutScan(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker scout cves")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.Equal(t, []string{"scout", "cves", "--format", "gitlab", "local://api:latest"}, args.Args)
+		require.Nil(t, args.Stderr)
+
+		return exec.NewRunResult(0, dockerScoutReport, ""), nil
+	})
+
+	result, err := NewDockerScout(mockContext.CommandRunner).Scan(*mockContext.Context, "api:latest", nil)
+	require.NoError(t, err)
+	require.Equal(t, "docker scout", result.Scanner)
+	require.Equal(t, map[Severity]int{
+		SeverityCritical: 1,
+		SeverityHigh:     0,
+		SeverityMedium:   1,
+		SeverityLow:      0,
+		SeverityUnknown:  1,
+	}, result.Vulnerabilities)
+}
+
+func TestFind(t *testing.T) {
+	missing := &fakeScanner{name: "missing", installErr: errors.New("not found")}
+	installed := &fakeScanner{name: "installed"}
+
+	scanner, err := Find(context.Background(), missing, installed)
+	require.NoError(t, err)
+	require.Equal(t, installed, scanner)
+
+	_, err = Find(context.Background(), missing)
+	require.ErrorIs(t, err, ErrNoScanner)
+}
+
+type fakeScanner struct {
+	name       string
+	installErr error
+}
+
+func (f *fakeScanner) CheckInstalled(ctx context.Context) error {
+	return f.installErr
+}
+
+func (f *fakeScanner) InstallUrl() string {
+	return "https://example.com/" + f.name
+}
+
+func (f *fakeScanner) Name() string {
+	return f.name
+}
+
+func (f *fakeScanner) Scan(ctx context.Context, image string, progress io.Writer) (*Result, error) {
+	return newResult(f.name, image, nil), nil
+}
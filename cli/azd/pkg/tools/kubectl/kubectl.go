@@ -1,7 +1,9 @@
 package kubectl
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -12,6 +14,8 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // Executes commands against the Kubernetes CLI
@@ -27,6 +31,8 @@ type KubectlCli interface {
 	ApplyWithStdIn(ctx context.Context, input string, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Applies manifests from the specified file path
 	ApplyWithFile(ctx context.Context, filePath string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Applies the resources of the kustomization in the specified directory
+	ApplyWithKustomize(ctx context.Context, path string, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Views the current k8s configuration including available clusters, contexts & users
 	ConfigView(ctx context.Context, merge bool, flatten bool, flags *KubeCliFlags) (*exec.RunResult, error)
 	// Sets the k8s context to use for future CLI commands
@@ -37,6 +43,17 @@ type KubectlCli interface {
 	Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error)
 	// Gets the deployment rollout status
 	RolloutStatus(ctx context.Context, deploymentName string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Creates or updates a generic secret with the specified data
+	ApplySecret(ctx context.Context, name string, data map[string]string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Sets environment variables on the containers of a deployment. When secretName isn't empty, an environment variable
+	// is also set for each key of the secret, which references the secret with valueFrom.secretKeyRef.
+	SetDeploymentEnv(
+		ctx context.Context,
+		deploymentName string,
+		env map[string]string,
+		secretName string,
+		flags *KubeCliFlags,
+	) (*exec.RunResult, error)
 }
 
 type OutputType string
@@ -213,6 +230,20 @@ func (cli *kubectlCli) ApplyWithFile(ctx context.Context, filePath string, flags
 	return &res, nil
 }
 
+// Applies the resources of the kustomization in the specified directory
+func (cli *kubectlCli) ApplyWithKustomize(ctx context.Context, path string, flags *KubeCliFlags) (*exec.RunResult, error) {
+	runArgs := exec.
+		NewRunArgs("kubectl", "apply", "-k", path).
+		WithEnv(environ(cli.env))
+
+	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
+	if err != nil {
+		return nil, fmt.Errorf("kubectl apply -k: %w", err)
+	}
+
+	return &res, nil
+}
+
 // Applies manifests from the specified input
 func (cli *kubectlCli) Apply(ctx context.Context, path string, flags *KubeCliFlags) error {
 	if err := cli.applyTemplates(ctx, path, flags); err != nil {
@@ -248,6 +279,73 @@ func (cli *kubectlCli) RolloutStatus(
 	return &res, nil
 }
 
+// Creates or updates a generic secret with the specified data. The secret is written to stdin rather than passed as
+// arguments, and applied server side, so that its values aren't logged or stored in the last-applied-configuration
+// annotation of the secret.
+func (cli *kubectlCli) ApplySecret(
+	ctx context.Context,
+	name string,
+	data map[string]string,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	encoded := make(map[string]string, len(data))
+	for key, value := range data {
+		encoded[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	secret, err := json.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"metadata":   map[string]any{"name": name},
+		"data":       encoded,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runArgs := exec.
+		NewRunArgs("kubectl", "apply", "--server-side", "--force-conflicts", "--field-manager=azd", "-f", "-").
+		WithEnv(environ(cli.env)).
+		WithStdIn(bytes.NewReader(secret))
+
+	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed applying secret '%s': %w", name, err)
+	}
+
+	return &res, nil
+}
+
+// Sets environment variables on the containers of a deployment. When secretName isn't empty, an environment variable
+// is also set for each key of the secret, which references the secret with valueFrom.secretKeyRef.
+func (cli *kubectlCli) SetDeploymentEnv(
+	ctx context.Context,
+	deploymentName string,
+	env map[string]string,
+	secretName string,
+	flags *KubeCliFlags,
+) (*exec.RunResult, error) {
+	names := maps.Keys(env)
+	slices.Sort(names)
+
+	args := []string{"set", "env", fmt.Sprintf("deployment/%s", deploymentName)}
+	if secretName != "" {
+		args = append(args, fmt.Sprintf("--from=secret/%s", secretName))
+	}
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("%s=%s", name, env[name]))
+	}
+
+	runArgs := exec.NewRunArgs("kubectl", args...)
+	res, err := cli.executeCommandWithArgs(ctx, runArgs, flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed setting environment variables on deployment: %w", err)
+	}
+
+	return &res, nil
+}
+
 // Executes a k8s CLI command from the specified arguments and flags
 func (cli *kubectlCli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error) {
 	runArgs := exec.
@@ -80,6 +80,18 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"apply-with-kustomize": {
+			mockCommandPredicate: "kubectl apply -k",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"apply", "-k", "overlays/dev", "-n", "test-namespace"},
+			testFn: func() error {
+				_, err := cli.ApplyWithKustomize(*mockContext.Context, "overlays/dev", &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+
+				return err
+			},
+		},
 		"config-view": {
 			mockCommandPredicate: "kubectl config view",
 			expectedCmd:          "kubectl",
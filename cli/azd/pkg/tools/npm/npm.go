@@ -5,15 +5,25 @@ package npm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/blang/semver/v4"
 )
 
+// NpmCli runs package manager commands for Node.js projects. The package manager for each project (npm, yarn or pnpm) is
+// inferred from the lockfile in the project directory, see DetectPackageManager.
 type NpmCli interface {
 	tools.ExternalTool
+
+	// PackageManager returns the package manager of the project, which is detected the first time it's needed for the
+	// project and reused by the commands that run in the project afterwards.
+	PackageManager(projectPath string) (PackageManagerInfo, error)
 	Install(ctx context.Context, project string) error
 
 	// RunScript runs the given npm script (if it exists) in the project.
@@ -25,11 +35,16 @@ type NpmCli interface {
 
 type npmCli struct {
 	commandRunner exec.CommandRunner
+
+	// the package managers detected for each project path
+	packageManagers   map[string]PackageManagerInfo
+	packageManagersMu sync.Mutex
 }
 
 func NewNpmCli(commandRunner exec.CommandRunner) NpmCli {
 	return &npmCli{
-		commandRunner: commandRunner,
+		commandRunner:   commandRunner,
+		packageManagers: map[string]PackageManagerInfo{},
 	}
 }
 
@@ -74,12 +89,34 @@ func (cli *npmCli) Name() string {
 	return "npm CLI"
 }
 
+func (cli *npmCli) PackageManager(projectPath string) (PackageManagerInfo, error) {
+	cli.packageManagersMu.Lock()
+	defer cli.packageManagersMu.Unlock()
+
+	if info, has := cli.packageManagers[projectPath]; has {
+		return info, nil
+	}
+
+	info, err := DetectPackageManager(projectPath)
+	if err != nil {
+		return PackageManagerInfo{}, fmt.Errorf("detecting package manager for %s: %w", projectPath, err)
+	}
+
+	cli.packageManagers[projectPath] = info
+	return info, nil
+}
+
 func (cli *npmCli) Install(ctx context.Context, project string) error {
+	info, err := cli.PackageManager(project)
+	if err != nil {
+		return err
+	}
+
 	runArgs := exec.
-		NewRunArgs("npm", "install").
+		NewRunArgs(string(info.Kind), "install").
 		WithCwd(project)
 
-	_, err := cli.commandRunner.Run(ctx, runArgs)
+	_, err = cli.commandRunner.Run(ctx, runArgs)
 
 	if err != nil {
 		return fmt.Errorf("failed to install project %s: %w", project, err)
@@ -88,11 +125,30 @@ func (cli *npmCli) Install(ctx context.Context, project string) error {
 }
 
 func (cli *npmCli) RunScript(ctx context.Context, projectPath string, scriptName string) error {
-	runArgs := exec.
-		NewRunArgs("npm", "run", scriptName, "--if-present").
-		WithCwd(projectPath)
+	info, err := cli.PackageManager(projectPath)
+	if err != nil {
+		return err
+	}
+
+	var runArgs exec.RunArgs
+	switch info.Kind {
+	case PackageManagerYarn:
+		// yarn has no equivalent of --if-present, check for the script in package.json instead
+		has, err := hasScript(projectPath, scriptName)
+		if err != nil {
+			return fmt.Errorf("failed to run NPM script %s, %w", scriptName, err)
+		}
+
+		if !has {
+			return nil
+		}
+
+		runArgs = exec.NewRunArgs("yarn", "run", scriptName)
+	default:
+		runArgs = exec.NewRunArgs(string(info.Kind), "run", scriptName, "--if-present")
+	}
 
-	_, err := cli.commandRunner.Run(ctx, runArgs)
+	_, err = cli.commandRunner.Run(ctx, runArgs.WithCwd(projectPath))
 
 	if err != nil {
 		return fmt.Errorf("failed to run NPM script %s, %w", scriptName, err)
@@ -102,18 +158,53 @@ func (cli *npmCli) RunScript(ctx context.Context, projectPath string, scriptName
 }
 
 func (cli *npmCli) Prune(ctx context.Context, projectPath string, production bool) error {
-	runArgs := exec.
-		NewRunArgs("npm", "prune").
-		WithCwd(projectPath)
+	info, err := cli.PackageManager(projectPath)
+	if err != nil {
+		return err
+	}
 
-	if production {
-		runArgs = runArgs.AppendParams("--production")
+	var runArgs exec.RunArgs
+	switch info.Kind {
+	case PackageManagerYarn:
+		// yarn removes extraneous packages on every install
+		runArgs = exec.NewRunArgs("yarn", "install")
+		if production {
+			runArgs = runArgs.AppendParams("--production")
+		}
+	case PackageManagerPnpm:
+		runArgs = exec.NewRunArgs("pnpm", "prune")
+		if production {
+			runArgs = runArgs.AppendParams("--prod")
+		}
+	default:
+		runArgs = exec.NewRunArgs("npm", "prune")
+		if production {
+			runArgs = runArgs.AppendParams("--production")
+		}
 	}
 
-	_, err := cli.commandRunner.Run(ctx, runArgs)
+	_, err = cli.commandRunner.Run(ctx, runArgs.WithCwd(projectPath))
 	if err != nil {
 		return fmt.Errorf("failed pruning NPM packages, %w", err)
 	}
 
 	return nil
 }
+
+// hasScript returns true if the package.json of the project defines the given script.
+func hasScript(projectPath string, scriptName string) (bool, error) {
+	contents, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return false, err
+	}
+
+	var packageJson struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(contents, &packageJson); err != nil {
+		return false, fmt.Errorf("parsing package.json: %w", err)
+	}
+
+	_, has := packageJson.Scripts[scriptName]
+	return has, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package npm
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// PackageManagerKind is a package manager for Node.js projects.
+type PackageManagerKind string
+
+const (
+	PackageManagerNpm  PackageManagerKind = "npm"
+	PackageManagerYarn PackageManagerKind = "yarn"
+	PackageManagerPnpm PackageManagerKind = "pnpm"
+)
+
+// lockFiles are the lockfiles written by each package manager, sorted by name.
+var lockFiles = []struct {
+	name string
+	kind PackageManagerKind
+}{
+	{"package-lock.json", PackageManagerNpm},
+	{"pnpm-lock.yaml", PackageManagerPnpm},
+	{"yarn.lock", PackageManagerYarn},
+}
+
+// PackageManagerInfo describes the package manager inferred for a project.
+type PackageManagerInfo struct {
+	// The package manager to use for the project.
+	Kind PackageManagerKind
+
+	// The lockfile that the package manager was inferred from. Empty when no lockfile is present.
+	LockFile string
+
+	// All of the lockfiles present in the project, sorted by name. When there is more than one, the most recently
+	// modified lockfile determines the package manager.
+	LockFiles []string
+}
+
+// DetectPackageManager infers the package manager of the project in projectPath from the lockfiles present.
+// npm is used when no lockfile is present.
+func DetectPackageManager(projectPath string) (PackageManagerInfo, error) {
+	info := PackageManagerInfo{
+		Kind: PackageManagerNpm,
+	}
+
+	var newest fs.FileInfo
+	for _, lockFile := range lockFiles {
+		stat, err := os.Stat(filepath.Join(projectPath, lockFile.name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return PackageManagerInfo{}, err
+		}
+
+		info.LockFiles = append(info.LockFiles, lockFile.name)
+		if newest == nil || stat.ModTime().After(newest.ModTime()) {
+			newest = stat
+			info.Kind = lockFile.kind
+			info.LockFile = lockFile.name
+		}
+	}
+
+	return info, nil
+}
@@ -237,7 +237,14 @@ func wrapStatusCodeErr(err error, res exec.RunResult) error {
 		return err
 	}
 
-	matches := statusCodeFailureRegexp.FindStringSubmatch(res.Stderr)
+	// the output is only kept at the end of the error when it's streamed to a progress writer
+	output := res.Stderr
+	var exitErr *exec.ExitError
+	if output == "" && errors.As(err, &exitErr) {
+		output = exitErr.OutputTail
+	}
+
+	matches := statusCodeFailureRegexp.FindStringSubmatch(output)
 	if len(matches) == 2 {
 		code, parseErr := strconv.Atoi(matches[1])
 		if parseErr == nil {
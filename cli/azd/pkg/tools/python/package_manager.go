@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package python
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageManagerKind is a tool that installs the dependencies of Python projects.
+type PackageManagerKind string
+
+const (
+	PackageManagerPip    PackageManagerKind = "pip"
+	PackageManagerPoetry PackageManagerKind = "poetry"
+	PackageManagerPipenv PackageManagerKind = "pipenv"
+	PackageManagerUv     PackageManagerKind = "uv"
+)
+
+const (
+	RequirementsFile = "requirements.txt"
+	PyprojectFile    = "pyproject.toml"
+	PipfileFile      = "Pipfile"
+)
+
+// lockFiles are the lockfiles written by each package manager, in the order they are checked.
+var lockFiles = []struct {
+	name     string
+	manifest string
+	kind     PackageManagerKind
+}{
+	{"uv.lock", PyprojectFile, PackageManagerUv},
+	{"poetry.lock", PyprojectFile, PackageManagerPoetry},
+	{"Pipfile.lock", PipfileFile, PackageManagerPipenv},
+}
+
+// PackageManagerInfo describes the package manager inferred for a project.
+type PackageManagerInfo struct {
+	// The package manager to use for the project.
+	Kind PackageManagerKind
+
+	// The file that declares the dependencies of the project, for example 'pyproject.toml'.
+	ManifestFile string
+
+	// The lockfile that the package manager was inferred from. Empty when no lockfile is present.
+	LockFile string
+}
+
+// DetectPackageManager infers the package manager of the project in projectPath. The package manager is inferred from
+// the first lockfile present of uv.lock, poetry.lock and Pipfile.lock, otherwise from the presence of a Pipfile, or
+// from the [tool.poetry] or [tool.uv] tables of pyproject.toml. pip is used for anything else, installing the
+// requirements.txt file, or the pyproject.toml file when there is no requirements.txt file.
+func DetectPackageManager(projectPath string) (PackageManagerInfo, error) {
+	for _, lockFile := range lockFiles {
+		if has, err := fileExists(filepath.Join(projectPath, lockFile.name)); err != nil {
+			return PackageManagerInfo{}, err
+		} else if has {
+			return PackageManagerInfo{
+				Kind:         lockFile.kind,
+				ManifestFile: lockFile.manifest,
+				LockFile:     lockFile.name,
+			}, nil
+		}
+	}
+
+	if has, err := fileExists(filepath.Join(projectPath, PipfileFile)); err != nil {
+		return PackageManagerInfo{}, err
+	} else if has {
+		return PackageManagerInfo{Kind: PackageManagerPipenv, ManifestFile: PipfileFile}, nil
+	}
+
+	tables, err := pyprojectTables(filepath.Join(projectPath, PyprojectFile))
+	if err != nil {
+		return PackageManagerInfo{}, err
+	}
+
+	for _, table := range tables {
+		switch {
+		case table == "tool.poetry" || strings.HasPrefix(table, "tool.poetry."):
+			return PackageManagerInfo{Kind: PackageManagerPoetry, ManifestFile: PyprojectFile}, nil
+		case table == "tool.uv" || strings.HasPrefix(table, "tool.uv."):
+			return PackageManagerInfo{Kind: PackageManagerUv, ManifestFile: PyprojectFile}, nil
+		}
+	}
+
+	if has, err := fileExists(filepath.Join(projectPath, RequirementsFile)); err != nil {
+		return PackageManagerInfo{}, err
+	} else if !has && len(tables) > 0 {
+		return PackageManagerInfo{Kind: PackageManagerPip, ManifestFile: PyprojectFile}, nil
+	}
+
+	return PackageManagerInfo{Kind: PackageManagerPip, ManifestFile: RequirementsFile}, nil
+}
+
+// pyprojectTables returns the names of the tables of the pyproject.toml file at path, for example 'tool.poetry', after an
+// empty name for the keys before the first table. Nil is returned when the file does not exist.
+func pyprojectTables(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tables := []string{""}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			table := strings.Trim(line, "[] ")
+			tables = append(tables, strings.ReplaceAll(table, `"`, ""))
+		}
+	}
+
+	return tables, scanner.Err()
+}
+
+func fileExists(path string) (bool, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
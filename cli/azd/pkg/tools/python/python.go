@@ -5,11 +5,14 @@ package python
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	osexec "os/exec"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -68,6 +71,63 @@ func (cli *PythonCli) Name() string {
 }
 
 func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, environment, requirementFile string) error {
+	return cli.pipInstall(ctx, workingDir, environment, "-r", requirementFile)
+}
+
+// InstallDependencies installs the dependencies of the project in workingDir with the package manager inferred for it,
+// see DetectPackageManager. pip installs the dependencies into the virtual environment named environment, which must
+// already exist. The other package managers install the dependencies into the environments they manage.
+func (cli *PythonCli) InstallDependencies(
+	ctx context.Context,
+	workingDir string,
+	environment string,
+	packageManager PackageManagerInfo,
+) error {
+	switch packageManager.Kind {
+	case PackageManagerPoetry:
+		return cli.runPackageManager(
+			ctx, workingDir, packageManager.Kind, "https://python-poetry.org/docs/#installation",
+			"install", "--no-interaction", "--no-root")
+	case PackageManagerPipenv:
+		return cli.runPackageManager(
+			ctx, workingDir, packageManager.Kind, "https://pipenv.pypa.io/en/latest/installation.html",
+			"install")
+	case PackageManagerUv:
+		return cli.runPackageManager(
+			ctx, workingDir, packageManager.Kind, "https://docs.astral.sh/uv/getting-started/installation/",
+			"sync")
+	}
+
+	if packageManager.ManifestFile == PyprojectFile {
+		return cli.pipInstall(ctx, workingDir, environment, ".")
+	}
+
+	return cli.InstallRequirements(ctx, workingDir, environment, RequirementsFile)
+}
+
+// runPackageManager runs the package manager with args in workingDir, failing with the URL to install the package manager
+// when it isn't installed.
+func (cli *PythonCli) runPackageManager(
+	ctx context.Context,
+	workingDir string,
+	kind PackageManagerKind,
+	installUrl string,
+	args ...string,
+) error {
+	runArgs := exec.NewRunArgs(string(kind), args...).WithCwd(workingDir)
+	if _, err := cli.commandRunner.Run(ctx, runArgs); errors.Is(err, osexec.ErrNotFound) {
+		return fmt.Errorf(
+			"%s is required to install the dependencies of project '%s', see %s to install it: %w",
+			kind, workingDir, installUrl, err)
+	} else if err != nil {
+		return fmt.Errorf("failed to install dependencies for project '%s' with %s: %w", workingDir, kind, err)
+	}
+
+	return nil
+}
+
+// pipInstall runs 'pip install' with args in the virtual environment named environment.
+func (cli *PythonCli) pipInstall(ctx context.Context, workingDir, environment string, args ...string) error {
 	var err error
 
 	pyString, err := checkPath()
@@ -88,14 +148,14 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 		vEnvSetting := fmt.Sprintf("VIRTUAL_ENV=%s", path.Join(absWorkingDir, environment))
 
 		runArgs := exec.
-			NewRunArgs(pyString, "-m", "pip", "install", "-r", requirementFile).
+			NewRunArgs(pyString, append([]string{"-m", "pip", "install"}, args...)...).
 			WithCwd(workingDir).
 			WithEnv([]string{vEnvSetting})
 
 		_, err = cli.commandRunner.Run(ctx, runArgs)
 	} else {
 		envActivation := ". " + path.Join(environment, "bin", "activate")
-		installCmd := fmt.Sprintf("%s -m pip install -r %s", pyString, requirementFile)
+		installCmd := fmt.Sprintf("%s -m pip install %s", pyString, strings.Join(args, " "))
 		commands := []string{envActivation, installCmd}
 
 		runArgs := exec.NewRunArgs(pyString).WithCwd(workingDir)
@@ -106,6 +106,12 @@ func (cli *terraformCli) runInteractive(ctx context.Context, args ...string) (ex
 		WithEnv(cli.env).
 		WithInteractive(true)
 
+	// terraform may prompt for input, so these commands, which provision and destroy infrastructure, only time out
+	// when AZD_PROVISION_TIMEOUT is set
+	if timeout, has := exec.TimeoutFromEnv("AZD_PROVISION_TIMEOUT"); has {
+		runArgs = runArgs.WithTimeout(timeout)
+	}
+
 	return cli.commandRunner.Run(ctx, runArgs)
 }
 
@@ -3,6 +3,7 @@ package terraform
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -32,3 +33,32 @@ func Test_WithEnv(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ran)
 }
+
+func Test_ProvisionTimeout(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	var timeouts []*time.Duration
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		timeouts = append(timeouts, args.Timeout)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewTerraformCli(mockContext.CommandRunner)
+
+	_, err := cli.Apply(*mockContext.Context, "path/to/module")
+	require.NoError(t, err)
+
+	t.Setenv("AZD_PROVISION_TIMEOUT", "1h")
+	_, err = cli.Apply(*mockContext.Context, "path/to/module")
+	require.NoError(t, err)
+
+	// output isn't interactive, so it uses the default timeout
+	_, err = cli.Output(*mockContext.Context, "path/to/module")
+	require.NoError(t, err)
+
+	require.Len(t, timeouts, 3)
+	require.Nil(t, timeouts[0])
+	require.Equal(t, time.Hour, *timeouts[1])
+	require.Nil(t, timeouts[2])
+}
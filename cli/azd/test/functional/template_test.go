@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cli_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/test/azdcli"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CLI_TemplateCommandsWorkWhenLoggedOut(t *testing.T) {
+	ctx, cancel := newTestContext(t)
+	defer cancel()
+
+	dir := tempDirWithDiagnostics(t)
+
+	cli := azdcli.NewCLI(t)
+	cli.WorkingDirectory = dir
+	// set a private config dir, this will ensure we are logged out.
+	cli.Env = append(cli.Env, "AZD_CONFIG_DIR="+t.TempDir())
+
+	res, err := cli.RunCommand(ctx, "auth", "login", "--check-status", "--output", "json")
+	require.NoError(t, err)
+
+	var lr contracts.LoginResult
+	err = json.Unmarshal([]byte(res.Stdout), &lr)
+	require.NoError(t, err)
+	require.Equal(t, contracts.LoginStatusUnauthenticated, lr.Status)
+
+	// a file source lists its templates without the network
+	templatesPath := filepath.Join(dir, "templates.json")
+	err = os.WriteFile(templatesPath, []byte(`[
+		{"name": "Todo", "repositoryPath": "Azure-Samples/todo-python-mongo", "description": "A todo app"}
+	]`), 0600)
+	require.NoError(t, err)
+
+	_, err = cli.RunCommand(ctx, "template", "source", "add", "local", "--type", "file", "--location", templatesPath)
+	require.NoError(t, err)
+
+	res, err = cli.RunCommand(ctx, "template", "list", "--source", "local", "--output", "json")
+	require.NoError(t, err)
+
+	var list []*templates.Template
+	err = json.Unmarshal([]byte(res.Stdout), &list)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "Azure-Samples/todo-python-mongo", list[0].RepositoryPath)
+
+	_, err = cli.RunCommand(ctx, "config", "show")
+	require.NoError(t, err)
+}
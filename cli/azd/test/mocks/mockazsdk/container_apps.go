@@ -2,6 +2,7 @@ package mockazsdk
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -136,3 +137,65 @@ func MockContainerAppSecretsList(
 
 	return mockRequest
 }
+
+func MockContainerAppGetAuthToken(
+	mockContext *mocks.MockContext,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	token string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(
+			request.URL.Path,
+			fmt.Sprintf(
+				"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.App/containerApps/%s/getAuthtoken",
+				subscriptionId,
+				resourceGroup,
+				appName,
+			),
+		)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		response := armappcontainers.ContainerAppsClientGetAuthTokenResponse{
+			ContainerAppAuthToken: armappcontainers.ContainerAppAuthToken{
+				Properties: &armappcontainers.ContainerAppAuthTokenProperties{
+					Token: &token,
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	return mockRequest
+}
+
+// MockContainerAppEventStream mocks the event stream endpoint of a container app, which returns the system logs of the
+// container app as text
+func MockContainerAppEventStream(
+	mockContext *mocks.MockContext,
+	eventStreamEndpoint string,
+	logs string,
+) *http.Request {
+	mockRequest := &http.Request{}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasPrefix(request.URL.String(), eventStreamEndpoint)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		*mockRequest = *request
+
+		return &http.Response{
+			Request:    request,
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(logs)),
+		}, nil
+	})
+
+	return mockRequest
+}
@@ -36,11 +36,37 @@ func (m *MockEnvManager) Save(ctx context.Context, env *environment.Environment)
 	return args.Error(0)
 }
 
+func (m *MockEnvManager) SaveSecret(
+	ctx context.Context,
+	env *environment.Environment,
+	key string,
+	reference environment.KeyVaultReference,
+	value string,
+) error {
+	args := m.Called(ctx, env, key, reference, value)
+	return args.Error(0)
+}
+
 func (m *MockEnvManager) Reload(ctx context.Context, env *environment.Environment) error {
 	args := m.Called(ctx, env)
 	return args.Error(0)
 }
 
+func (m *MockEnvManager) Rename(ctx context.Context, oldName string, newName string) error {
+	args := m.Called(ctx, oldName, newName)
+	return args.Error(0)
+}
+
+func (m *MockEnvManager) Clone(
+	ctx context.Context,
+	source string,
+	dest string,
+	options environment.CloneOptions,
+) (*environment.Environment, error) {
+	args := m.Called(ctx, source, dest, options)
+	return args.Get(0).(*environment.Environment), args.Error(1)
+}
+
 func (m *MockEnvManager) EnvPath(env *environment.Environment) string {
 	args := m.Called(env)
 	return args.String(0)
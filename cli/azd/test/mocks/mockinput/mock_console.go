@@ -34,6 +34,7 @@ type MockConsole struct {
 	expressions []*MockConsoleExpression
 	log         []string
 	spinnerOps  []SpinnerOp
+	noPrompt    bool
 }
 
 func NewMockConsole() *MockConsole {
@@ -58,6 +59,14 @@ func (c *MockConsole) SetWriter(writer io.Writer) {
 
 }
 
+func (c *MockConsole) AddTranscript(writer io.Writer) {
+
+}
+
+func (c *MockConsole) Close() error {
+	return nil
+}
+
 func (c *MockConsole) Output() []string {
 	return c.log
 }
@@ -79,6 +88,10 @@ func (c *MockConsole) Message(ctx context.Context, message string) {
 	c.log = append(c.log, message)
 }
 
+func (c *MockConsole) SafeLog(msg string) {
+	c.Message(context.Background(), msg)
+}
+
 func (c *MockConsole) WarnForFeature(ctx context.Context, id alpha.FeatureId) {
 	c.Message(ctx, fmt.Sprintf("warning: alpha feature %s is enabled", id))
 }
@@ -109,6 +122,16 @@ func (c *MockConsole) ShowPreviewer(ctx context.Context, options *input.ShowPrev
 
 func (c *MockConsole) StopPreviewer(ctx context.Context, keepLogs bool) {}
 
+func (c *MockConsole) ShowProgress(ctx context.Context, title string) input.ProgressReporter {
+	return &mockProgressReporter{}
+}
+
+type mockProgressReporter struct{}
+
+func (r *mockProgressReporter) Report(fraction float64, msg string) {}
+
+func (r *mockProgressReporter) Stop() {}
+
 func (c *MockConsole) IsSpinnerRunning(ctx context.Context) bool {
 	if len(c.spinnerOps) > 0 && c.spinnerOps[len(c.spinnerOps)-1].Op == SpinnerOpShow {
 		return true
@@ -121,9 +144,23 @@ func (c *MockConsole) IsSpinnerInteractive() bool {
 	return false
 }
 
+func (c *MockConsole) IsNoPromptMode() bool {
+	return c.noPrompt
+}
+
+// SetNoPromptMode sets the value returned by IsNoPromptMode.
+func (c *MockConsole) SetNoPromptMode(noPrompt bool) {
+	c.noPrompt = noPrompt
+}
+
 // Prints a confirmation message to the console for the user to confirm
 func (c *MockConsole) Confirm(ctx context.Context, options input.ConsoleOptions) (bool, error) {
 	c.log = append(c.log, options.Message)
+	options, err := input.ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return false, err
+	}
+
 	value, err := c.respond("Confirm", options)
 	return value.(bool), err
 }
@@ -132,19 +169,48 @@ func (c *MockConsole) Confirm(ctx context.Context, options input.ConsoleOptions)
 func (c *MockConsole) WaitForEnter() {
 }
 
+// no-op for mock-console when calling WaitForEnterCtx()
+func (c *MockConsole) WaitForEnterCtx(ctx context.Context) {
+}
+
 func (c *MockConsole) EnsureBlankLine(context context.Context) {
 }
 
 // Writes a single answer prompt to the console for the user to complete
 func (c *MockConsole) Prompt(ctx context.Context, options input.ConsoleOptions) (string, error) {
 	c.log = append(c.log, options.Message)
+	options, err := input.ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return "", err
+	}
+
 	value, err := c.respond("Prompt", options)
 	return value.(string), err
 }
 
+// Writes a single integer answer prompt to the console for the user to complete
+func (c *MockConsole) PromptInt(ctx context.Context, options input.ConsoleOptions) (int, error) {
+	c.log = append(c.log, options.Message)
+	options, err := input.ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := c.respond("PromptInt", options)
+	if err != nil {
+		return 0, err
+	}
+	return value.(int), nil
+}
+
 // Writes a multiple choice selection to the console for the user to choose
 func (c *MockConsole) Select(ctx context.Context, options input.ConsoleOptions) (int, error) {
 	c.log = append(c.log, options.Message)
+	options, err := input.ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return -1, err
+	}
+
 	value, err := c.respond("Select", options)
 	return value.(int), err
 }
@@ -152,6 +218,11 @@ func (c *MockConsole) Select(ctx context.Context, options input.ConsoleOptions)
 // Writes a multiple choice selection to the console for the user to choose
 func (c *MockConsole) MultiSelect(ctx context.Context, options input.ConsoleOptions) ([]string, error) {
 	c.log = append(c.log, options.Message)
+	options, err := input.ResolveDefaultValue(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
 	value, err := c.respond("MultiSelect", options)
 	return value.([]string), err
 }
@@ -190,6 +261,18 @@ func (c *MockConsole) WhenPrompt(predicate WhenPredicate) *MockConsoleExpression
 	return &expr
 }
 
+// Registers an integer prompt expression for mocking in unit tests
+func (c *MockConsole) WhenPromptInt(predicate WhenPredicate) *MockConsoleExpression {
+	expr := MockConsoleExpression{
+		command:     "PromptInt",
+		console:     c,
+		predicateFn: predicate,
+	}
+
+	c.expressions = append(c.expressions, &expr)
+	return &expr
+}
+
 // Registers a confirmation expression for mocking in unit tests
 func (c *MockConsole) WhenConfirm(predicate WhenPredicate) *MockConsoleExpression {
 	expr := MockConsoleExpression{